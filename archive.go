@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/archivepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// ExportState writes the complete durable state of a node to dest, in form of a portable (gzip-compressed) archive.
+// The archive can be imported on another machine using ImportState,
+// after which the node can be restarted there using RestartNode, as if it had never moved.
+// This makes it possible to migrate a node to new hardware without having to transfer the state from other nodes.
+//
+// The archive contains the latest stable checkpoint found in the WAL (including the application snapshot),
+// all WAL entries that follow it, and the contents of the request store for all requests referenced by those entries.
+// ExportState must not be called while a Node is using the WAL and the request store.
+func ExportState(wal modules.WAL, reqStore modules.RequestStore, dest io.Writer) error {
+
+	// Load all WAL entries.
+	entries, err := loadWAL(wal)
+	if err != nil {
+		return err
+	}
+
+	// Only retain the entries starting from the latest stable checkpoint.
+	stableCheckpoint, entries := entriesFromStableCheckpoint(entries)
+	archive := &archivepb.StateArchive{
+		StableCheckpoint: stableCheckpoint,
+		WalEntries:       entries,
+		Requests:         make([]*requestpb.StoredRequest, 0),
+	}
+
+	// Add the stored data of each request referenced by the retained entries (each request only once).
+	exported := make(map[string]struct{})
+	for _, entry := range entries {
+		for _, ref := range persistedRequestRefs(entry.Event) {
+			key := fmt.Sprintf("%d.%d.%x", ref.ClientId, ref.ReqNo, ref.Digest)
+			if _, ok := exported[key]; ok {
+				continue
+			}
+			exported[key] = struct{}{}
+
+			data, err := reqStore.GetRequest(ref)
+			if err != nil {
+				return fmt.Errorf("could not read request (c%dr%d) from request store: %w",
+					ref.ClientId, ref.ReqNo, err)
+			}
+			authenticated, err := reqStore.IsAuthenticated(ref)
+			if err != nil {
+				return fmt.Errorf("could not read authentication status of request (c%dr%d): %w",
+					ref.ClientId, ref.ReqNo, err)
+			}
+			// The request store returns an error if no authenticator is stored,
+			// which is perfectly valid (e.g. for requests that are authenticated, but not signed).
+			authenticator, err := reqStore.GetAuthenticator(ref)
+			if err != nil {
+				authenticator = nil
+			}
+
+			archive.Requests = append(archive.Requests, &requestpb.StoredRequest{
+				RequestRef:    ref,
+				Data:          data,
+				Authenticated: authenticated,
+				Authenticator: authenticator,
+			})
+		}
+	}
+
+	// Serialize the archive and write it to the destination.
+	data, err := proto.Marshal(archive)
+	if err != nil {
+		return fmt.Errorf("could not marshal state archive: %w", err)
+	}
+	gzWriter := gzip.NewWriter(dest)
+	if _, err := gzWriter.Write(data); err != nil {
+		return fmt.Errorf("could not write state archive: %w", err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		return fmt.Errorf("could not write state archive: %w", err)
+	}
+
+	return nil
+}
+
+// ImportState reads an archive produced by ExportState from src and writes its contents
+// to the given WAL and request store, both of which are expected to be empty.
+// The request store is populated (and synced) before the WAL entries are appended,
+// such that the WAL never references requests missing from the request store.
+// After ImportState returns successfully, a Node can be started using RestartNode with the WAL and the request store.
+func ImportState(src io.Reader, wal modules.WAL, reqStore modules.RequestStore) error {
+
+	// Read and parse the archive.
+	gzReader, err := gzip.NewReader(src)
+	if err != nil {
+		return fmt.Errorf("could not read source as a gzip stream: %w", err)
+	}
+	data, err := ioutil.ReadAll(gzReader)
+	if err != nil {
+		return fmt.Errorf("could not read state archive: %w", err)
+	}
+	archive := &archivepb.StateArchive{}
+	if err := proto.Unmarshal(data, archive); err != nil {
+		return fmt.Errorf("could not unmarshal state archive: %w", err)
+	}
+
+	// Populate the request store.
+	if err := reqStore.StoreBatch(archive.Requests); err != nil {
+		return fmt.Errorf("could not store requests: %w", err)
+	}
+	if err := reqStore.Sync(); err != nil {
+		return fmt.Errorf("could not sync request store: %w", err)
+	}
+
+	// Populate the WAL.
+	for _, entry := range archive.WalEntries {
+		if err := wal.Append(entry.Event, t.WALRetIndex(entry.RetentionIndex)); err != nil {
+			return fmt.Errorf("could not append WAL entry: %w", err)
+		}
+	}
+	if err := wal.Sync(); err != nil {
+		return fmt.Errorf("could not sync WAL: %w", err)
+	}
+
+	return nil
+}
+
+// entriesFromStableCheckpoint returns the latest stable checkpoint persisted in the given WAL entries
+// and the sub-list of entries needed to recover from it, i.e., the entries following the stable checkpoint
+// and the entry persisting the checkpoint's application snapshot.
+// If the entries contain no stable checkpoint, all the entries are returned along with a nil checkpoint.
+func entriesFromStableCheckpoint(entries []*archivepb.WALEntry) (*isspb.StableCheckpoint, []*archivepb.WALEntry) {
+
+	// Find the latest stable checkpoint.
+	var stableCheckpoint *isspb.StableCheckpoint
+	stableIndex := -1
+	for i, entry := range entries {
+		if chkp := persistedStableCheckpoint(entry.Event); chkp != nil &&
+			(stableCheckpoint == nil || chkp.Sn > stableCheckpoint.Sn) {
+			stableCheckpoint = chkp
+			stableIndex = i
+		}
+	}
+	if stableCheckpoint == nil {
+		return nil, entries
+	}
+
+	// Retain the snapshot of the stable checkpoint and all entries following the stable checkpoint.
+	retained := make([]*archivepb.WALEntry, 0)
+	for i, entry := range entries {
+		if i >= stableIndex {
+			retained = append(retained, entry)
+		} else if sn, ok := persistedCheckpointSn(entry.Event); ok && sn == stableCheckpoint.Sn {
+			retained = append(retained, entry)
+		}
+	}
+	return stableCheckpoint, retained
+}
+
+// persistedStableCheckpoint returns the stable checkpoint persisted by the given WAL event, if any, and nil otherwise.
+func persistedStableCheckpoint(event *eventpb.Event) *isspb.StableCheckpoint {
+	if issEvent, ok := event.Type.(*eventpb.Event_Iss); ok {
+		if e, ok := issEvent.Iss.Type.(*isspb.ISSEvent_PersistStableCheckpoint); ok {
+			return e.PersistStableCheckpoint.StableCheckpoint
+		}
+	}
+	return nil
+}
+
+// persistedCheckpointSn returns the sequence number of the checkpoint persisted by the given WAL event, if any.
+func persistedCheckpointSn(event *eventpb.Event) (uint64, bool) {
+	if issEvent, ok := event.Type.(*eventpb.Event_Iss); ok {
+		if e, ok := issEvent.Iss.Type.(*isspb.ISSEvent_PersistCheckpoint); ok {
+			return e.PersistCheckpoint.Sn, true
+		}
+	}
+	return 0, false
+}
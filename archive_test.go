@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+)
+
+// The archive test exports the state of all nodes of a deployment, imports it in a fresh WAL and request store,
+// and restarts the nodes from the imported state.
+var _ = Describe("Archive test", func() {
+
+	It("recovers nodes migrated to a new location", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		deploytest.ExpectStopped(deployment.RunUntilThen(deployment.AllProcessed(testConfig.NumFakeRequests), func() {
+			deployment.AwaitStableCheckpoint()
+		}))
+
+		// Export the state of each replica, wipe it, and import it again in a new WAL and request store.
+		for _, replica := range deployment.TestReplicas {
+			walPath := filepath.Join(replica.Dir, "wal")
+			archive := &bytes.Buffer{}
+
+			wal, err := simplewal.Open(walPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mirbft.ExportState(wal, replica.ReqStore, archive)).To(Succeed())
+			Expect(wal.Close()).To(Succeed())
+			Expect(os.RemoveAll(walPath)).To(Succeed())
+
+			wal, err = simplewal.Open(walPath)
+			Expect(err).NotTo(HaveOccurred())
+			replica.ReqStore = reqstore.NewVolatileRequestStore()
+			Expect(mirbft.ImportState(archive, wal, replica.ReqStore)).To(Succeed())
+			Expect(wal.Close()).To(Succeed())
+
+			replica.App = &deploytest.FakeApp{}
+			replica.NumFakeRequests = 0
+			replica.Restart = true
+		}
+		deploytest.ExpectStopped(deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests)))
+	})
+})
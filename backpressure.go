@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// BackpressureError is returned by Node.SubmitRequest and Node.Step if NodeConfig.RejectOnBackpressure is set
+// and the number of events pending in the Node's internal buffers reached NodeConfig.MaxPendingEvents.
+// The caller is expected to retry later.
+type BackpressureError struct {
+
+	// Number of events pending in the Node's internal buffers when the input was rejected.
+	Pending int
+
+	// Maximal number of pending events (as configured in NodeConfig.MaxPendingEvents).
+	MaxPending int
+}
+
+// Error returns a description of the backpressure condition.
+func (e *BackpressureError) Error() string {
+	return fmt.Sprintf("node overloaded: %d pending events (max %d)", e.Pending, e.MaxPending)
+}
+
+// overloaded returns true if the number of pending events reached the configured maximum.
+func (n *Node) overloaded(pending int) bool {
+	maxPending := n.localParams().MaxPendingEvents
+	return maxPending > 0 && pending >= maxPending
+}
+
+// checkBackpressure returns a BackpressureError if the Node is configured to reject input when overloaded
+// and is currently overloaded, and nil otherwise.
+// It is called by the threads submitting input to the Node and only approximates the current number of pending events,
+// as updated by the process() goroutine after each processing step.
+func (n *Node) checkBackpressure() *BackpressureError {
+	pending := int(atomic.LoadInt64(&n.pendingEvents))
+	params := n.localParams()
+	if !params.RejectOnBackpressure || !n.overloaded(pending) {
+		return nil
+	}
+	return &BackpressureError{Pending: pending, MaxPending: params.MaxPendingEvents}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The backpressure test limits the number of events pending in the Node
+// and checks that the Node still delivers all requests, only throttling their submission.
+var _ = Describe("Backpressure test", func() {
+
+	It("delivers all requests with a minimal pending event limit", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.MaxPendingEvents = 1
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
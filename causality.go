@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/causality"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// causalTracer assigns causal IDs to the messages sent by a Node, propagates them through the resulting events,
+// and reports all steps of message flows to the causality.Tracer configured in the NodeConfig.
+// All methods can be called on a nil causalTracer, in which case tracing is disabled
+// and they do not modify the processed messages and events.
+type causalTracer struct {
+
+	// ID of the node this tracer assigns causal IDs for.
+	nodeID t.NodeID
+
+	// Tracer to report the causality records to.
+	tracer causality.Tracer
+
+	// Number of messages assigned a causal ID so far. Must only be accessed atomically.
+	numSent uint64
+}
+
+// newCausalTracer returns a new causalTracer for node nodeID reporting to tracer.
+// If tracer is nil, returns nil, disabling causality tracing.
+func newCausalTracer(nodeID t.NodeID, tracer causality.Tracer) *causalTracer {
+	if tracer == nil {
+		return nil
+	}
+	return &causalTracer{
+		nodeID: nodeID,
+		tracer: tracer,
+	}
+}
+
+// messageSent assigns a new causal ID to a message about to be sent (by a SendMessage event)
+// and returns the message to be sent instead of the original one.
+func (ct *causalTracer) messageSent(event *eventpb.Event, sendMessage *eventpb.SendMessage) *messagepb.Message {
+	if ct == nil {
+		return sendMessage.Msg
+	}
+
+	// Attach the causal ID to a copy of the message, as the original might still be referenced elsewhere.
+	msg := proto.Clone(sendMessage.Msg).(*messagepb.Message)
+	msg.CausalId = causality.ID(ct.nodeID, atomic.AddUint64(&ct.numSent, 1))
+
+	ct.tracer.Trace(&causality.Record{
+		Kind:    causality.Sent,
+		Node:    ct.nodeID,
+		ID:      msg.CausalId,
+		Cause:   event.CausalId,
+		Peers:   t.NodeIDSlice(sendMessage.Destinations),
+		Message: msg,
+	})
+
+	return msg
+}
+
+// messageReceived returns a new MessageReceived event for a message received from node source,
+// carrying the message's causal ID.
+func (ct *causalTracer) messageReceived(source t.NodeID, msg *messagepb.Message) *eventpb.Event {
+	event := events.MessageReceived(source, msg)
+	if ct == nil || msg.CausalId == 0 {
+		return event
+	}
+
+	event.CausalId = msg.CausalId
+	ct.tracer.Trace(&causality.Record{
+		Kind:    causality.Received,
+		Node:    ct.nodeID,
+		ID:      msg.CausalId,
+		Cause:   msg.CausalId,
+		Peers:   []t.NodeID{source},
+		Message: msg,
+	})
+
+	return event
+}
+
+// eventsProduced propagates the causal ID of an event processed by the protocol
+// to all the events the protocol produced while processing it (including their follow-up events).
+// Events that already have a causal ID are left unchanged.
+func (ct *causalTracer) eventsProduced(cause *eventpb.Event, produced *events.EventList) {
+	if ct == nil || cause.CausalId == 0 {
+		return
+	}
+
+	iter := produced.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		setCausalID(event, cause.CausalId)
+
+		// Report a copy of the event, as the original will be modified when processed.
+		ct.tracer.Trace(&causality.Record{
+			Kind:  causality.Produced,
+			Node:  ct.nodeID,
+			Cause: event.CausalId,
+			Event: proto.Clone(event).(*eventpb.Event),
+		})
+	}
+}
+
+// setCausalID sets the causal ID of an event and, recursively, of its follow-up events,
+// unless they already have one.
+func setCausalID(event *eventpb.Event, causalID uint64) {
+	if event.CausalId == 0 {
+		event.CausalId = causalID
+	}
+	for _, next := range event.Next {
+		setCausalID(next, causalID)
+	}
+}
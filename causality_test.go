@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/causality"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+)
+
+// The causality test runs a deployment with causality tracing enabled
+// and checks that the message flow of agreeing on a sequence number can be assembled from the traces.
+var _ = Describe("Causality tracing test", func() {
+
+	It("traces the message flow of a sequence number", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All replicas report to the same recorder, so that flows spanning multiple nodes can be assembled.
+		recorder := causality.NewRecorder()
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.Tracer = recorder
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		// Each received message must have been sent.
+		sent := make(map[uint64]struct{})
+		for _, record := range recorder.Records() {
+			if record.Kind == causality.Sent {
+				sent[record.ID] = struct{}{}
+			}
+		}
+		for _, record := range recorder.Records() {
+			if record.Kind == causality.Received {
+				Expect(sent).To(HaveKey(record.ID))
+			}
+		}
+
+		// The flow of sequence number 0 starts with sending its Preprepare message
+		// and must include the message's reception and processing by all replicas.
+		flow := recorder.Flow(func(record *causality.Record) bool {
+			return record.Kind == causality.Sent && isPreprepare(record.Message, 0)
+		})
+		receivers := make(map[uint64]struct{})
+		numProduced := 0
+		for _, record := range flow {
+			switch record.Kind {
+			case causality.Received:
+				receivers[record.Node.Pb()] = struct{}{}
+			case causality.Produced:
+				numProduced++
+			}
+		}
+		Expect(receivers).To(HaveLen(len(deployment.TestReplicas)))
+		Expect(numProduced).To(BeNumerically(">", 0))
+	})
+})
+
+// isPreprepare returns true if msg is a PBFT Preprepare message for sequence number sn.
+func isPreprepare(msg *messagepb.Message, sn uint64) bool {
+	preprepare := msg.GetIss().GetSb().GetMsg().GetPbftPreprepare()
+	return preprepare != nil && preprepare.Sn == sn
+}
@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/archivepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// walTailMarker returns a clean stop marker identifying the tail of the WAL consisting of the given entries.
+// The marker contains the number of entries, the retention index of the last entry
+// and a digest of the last entry's event.
+func walTailMarker(entries []*archivepb.WALEntry) (*archivepb.CleanStopMarker, error) {
+	if len(entries) == 0 {
+		return tailMarker(0, nil)
+	}
+	return tailMarker(uint64(len(entries)), entries[len(entries)-1])
+}
+
+// tailMarker returns a clean stop marker identifying the tail of a WAL with the given number of entries,
+// the last of which is tail (nil if the WAL is empty).
+func tailMarker(length uint64, tail *archivepb.WALEntry) (*archivepb.CleanStopMarker, error) {
+
+	// An empty WAL is identified by its length only.
+	if tail == nil {
+		return &archivepb.CleanStopMarker{}, nil
+	}
+
+	// Compute the digest of the last event in the WAL.
+	data, err := proto.Marshal(tail.Event)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal WAL tail event: %w", err)
+	}
+	digest := sha256.Sum256(data)
+
+	return &archivepb.CleanStopMarker{
+		WalLength:          length,
+		TailRetentionIndex: tail.RetentionIndex,
+		TailDigest:         digest[:],
+	}, nil
+}
+
+// validMarker returns true if marker is well-formed, i.e., if it could have been produced by walTailMarker.
+func validMarker(marker *archivepb.CleanStopMarker) bool {
+	if marker.WalLength == 0 {
+		return marker.TailRetentionIndex == 0 && len(marker.TailDigest) == 0
+	}
+	return len(marker.TailDigest) == sha256.Size
+}
+
+// walTailTracker is a WAL wrapper keeping track of the length and the last entry of the WAL,
+// such that a clean stop marker identifying the tail of the WAL can be written when the Node stops
+// without loading the whole WAL again.
+// The tracked state is initialized by reset with the entries loaded from the WAL when the Node starts.
+type walTailTracker struct {
+	modules.WAL
+
+	// Number of entries in the WAL per retention index, needed for updating length on truncation.
+	counts map[t.WALRetIndex]uint64
+
+	// Number of entries in the WAL.
+	length uint64
+
+	// The last entry of the WAL, nil if the WAL is empty.
+	tail *archivepb.WALEntry
+
+	// Set if the tail of the WAL is not known, e.g., before reset is called, after a failed WAL operation,
+	// or after the last entry has been truncated while entries appended before it were retained.
+	// No clean stop marker can be produced then.
+	unknown bool
+
+	lock sync.Mutex
+}
+
+// newWALTailTracker returns a new walTailTracker wrapping wal. Its tracked state is unknown until reset is called.
+func newWALTailTracker(wal modules.WAL) *walTailTracker {
+	return &walTailTracker{
+		WAL:     wal,
+		counts:  make(map[t.WALRetIndex]uint64),
+		unknown: true,
+	}
+}
+
+// reset initializes the tracked state with the given entries, as loaded from the wrapped WAL (see loadWAL).
+func (wt *walTailTracker) reset(entries []*archivepb.WALEntry) {
+	wt.lock.Lock()
+	defer wt.lock.Unlock()
+
+	wt.counts = make(map[t.WALRetIndex]uint64)
+	for _, entry := range entries {
+		wt.counts[t.WALRetIndex(entry.RetentionIndex)]++
+	}
+	wt.length = uint64(len(entries))
+	wt.tail = nil
+	if len(entries) > 0 {
+		wt.tail = entries[len(entries)-1]
+	}
+	wt.unknown = false
+}
+
+// Append appends the entry to the wrapped WAL and records it as the new tail.
+func (wt *walTailTracker) Append(event *eventpb.Event, retentionIndex t.WALRetIndex) error {
+	wt.lock.Lock()
+	defer wt.lock.Unlock()
+
+	if err := wt.WAL.Append(event, retentionIndex); err != nil {
+		wt.unknown = true
+		return err
+	}
+
+	wt.counts[retentionIndex]++
+	wt.length++
+	wt.tail = &archivepb.WALEntry{RetentionIndex: retentionIndex.Pb(), Event: event}
+	return nil
+}
+
+// Truncate truncates the wrapped WAL and removes the truncated entries from the tracked state.
+func (wt *walTailTracker) Truncate(retentionIndex t.WALRetIndex) error {
+	wt.lock.Lock()
+	defer wt.lock.Unlock()
+
+	if err := wt.WAL.Truncate(retentionIndex); err != nil {
+		wt.unknown = true
+		return err
+	}
+
+	for index, count := range wt.counts {
+		if index < retentionIndex {
+			wt.length -= count
+			delete(wt.counts, index)
+		}
+	}
+	if wt.tail != nil && t.WALRetIndex(wt.tail.RetentionIndex) < retentionIndex {
+		wt.tail = nil
+		if wt.length > 0 {
+			wt.unknown = true
+		}
+	}
+	return nil
+}
+
+// marker returns a clean stop marker identifying the current tail of the wrapped WAL,
+// or nil if the tail is not known.
+func (wt *walTailTracker) marker() (*archivepb.CleanStopMarker, error) {
+	wt.lock.Lock()
+	defer wt.lock.Unlock()
+
+	if wt.unknown {
+		return nil, nil
+	}
+	return tailMarker(wt.length, wt.tail)
+}
+
+// writeCleanStopMarker persists the given clean stop marker to the given file.
+// It must only be called after the node stopped gracefully and synced the WAL.
+// The marker is first written and synced to a temporary file that is then renamed,
+// such that a crash never leaves a partially written marker behind.
+// The directory containing the file is synced before and after the renaming, making the renaming durable.
+func writeCleanStopMarker(marker *archivepb.CleanStopMarker, fileName string) error {
+
+	// Serialize the marker.
+	data, err := proto.Marshal(marker)
+	if err != nil {
+		return fmt.Errorf("could not marshal clean stop marker: %w", err)
+	}
+
+	// Write the marker to a temporary file and sync it.
+	tmpFileName := fileName + ".tmp"
+	tmpFile, err := os.OpenFile(tmpFileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("could not create clean stop marker file: %w", err)
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("could not write clean stop marker: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("could not sync clean stop marker: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("could not close clean stop marker file: %w", err)
+	}
+
+	// Atomically replace the marker file by the temporary one.
+	if err := syncDir(filepath.Dir(fileName)); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFileName, fileName); err != nil {
+		return fmt.Errorf("could not rename clean stop marker file: %w", err)
+	}
+	return syncDir(filepath.Dir(fileName))
+}
+
+// removeCleanStopMarker deletes the clean stop marker file, if present, and syncs the directory containing it.
+func removeCleanStopMarker(fileName string) error {
+	if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not delete clean stop marker: %w", err)
+	}
+	return syncDir(filepath.Dir(fileName))
+}
+
+// syncDir syncs the directory with the given name, persisting the creation, renaming and deletion of its files.
+func syncDir(dirName string) error {
+	dir, err := os.Open(dirName)
+	if err != nil {
+		return fmt.Errorf("could not open directory %s: %w", dirName, err)
+	}
+	defer dir.Close()
+
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("could not sync directory %s: %w", dirName, err)
+	}
+	return nil
+}
+
+// consumeCleanStopMarker reads the clean stop marker from the given file and deletes the file,
+// such that the marker is never used by more than one restart.
+// If the file does not exist (e.g. because the node crashed instead of stopping gracefully),
+// consumeCleanStopMarker returns nil.
+// A marker that cannot be read, is malformed, or cannot be deleted is not used either:
+// consumeCleanStopMarker logs a warning and returns nil, such that the node falls back to the full consistency check.
+func consumeCleanStopMarker(fileName string, logger logging.Logger) *archivepb.CleanStopMarker {
+
+	// Read the marker file, if present.
+	data, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		logger.Log(logging.LevelWarn, "Could not read clean stop marker. Checking persisted state.",
+			"file", fileName, "err", err)
+		return nil
+	}
+
+	// Only use a well-formed marker.
+	marker := &archivepb.CleanStopMarker{}
+	if err := proto.Unmarshal(data, marker); err != nil {
+		logger.Log(logging.LevelWarn, "Could not unmarshal clean stop marker. Checking persisted state.",
+			"file", fileName, "err", err)
+		marker = nil
+	} else if !validMarker(marker) {
+		logger.Log(logging.LevelWarn, "Malformed clean stop marker. Checking persisted state.", "file", fileName)
+		marker = nil
+	}
+
+	// Delete the marker, as it becomes outdated as soon as the restarted node appends to the WAL.
+	if err := removeCleanStopMarker(fileName); err != nil {
+		logger.Log(logging.LevelWarn, "Could not delete clean stop marker. Checking persisted state.",
+			"file", fileName, "err", err)
+		return nil
+	}
+	return marker
+}
+
+// markerMatches returns true if the clean stop marker identifies the tail of the given WAL entries.
+func markerMatches(marker *archivepb.CleanStopMarker, entries []*archivepb.WALEntry) (bool, error) {
+	walMarker, err := walTailMarker(entries)
+	if err != nil {
+		return false, err
+	}
+	return marker.WalLength == walMarker.WalLength &&
+		marker.TailRetentionIndex == walMarker.TailRetentionIndex &&
+		bytes.Equal(marker.TailDigest, walMarker.TailDigest), nil
+}
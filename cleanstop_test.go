@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/archivepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The clean stop test gracefully stops all nodes of a deployment and restarts them using the clean stop marker
+// each node leaves behind.
+var _ = Describe("Clean stop test", func() {
+
+	It("restarts gracefully stopped nodes using a clean stop marker", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.CleanStopFile = filepath.Join(replica.Dir, "cleanstop")
+		}
+
+		// Each gracefully stopped replica must leave a clean stop marker behind, identifying the tail of its WAL
+		// (which has been truncated at the stable checkpoints meanwhile).
+		deploytest.ExpectStopped(deployment.RunUntilThen(deployment.AllProcessed(testConfig.NumFakeRequests), func() {
+			deployment.AwaitStableCheckpoint()
+		}))
+		for _, replica := range deployment.TestReplicas {
+			wal, err := simplewal.Open(filepath.Join(replica.Dir, "wal"))
+			Expect(err).NotTo(HaveOccurred())
+			expectMarker(replica.Config.CleanStopFile, walMarker(wal))
+			Expect(wal.Close()).To(Succeed())
+
+			replica.App = &deploytest.FakeApp{}
+			replica.NumFakeRequests = 0
+			replica.Restart = true
+		}
+
+		// The marker is consumed on restart and written again when the replica stops.
+		deploytest.ExpectStopped(deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests)))
+		for _, replica := range deployment.TestReplicas {
+			Expect(replica.Config.CleanStopFile).To(BeAnExistingFile())
+		}
+	})
+
+	Context("on restart", func() {
+
+		var (
+			// WAL referencing a request that is missing from the (empty) request store,
+			// such that the node only restarts if the consistency check is skipped.
+			wal *simplewal.VolatileWAL
+
+			// Directory containing the clean stop marker file.
+			dir        string
+			markerFile string
+		)
+
+		BeforeEach(func() {
+			wal = simplewal.NewVolatileWAL()
+			Expect(wal.Append(events.PersistDummyBatch(0, &requestpb.Batch{Requests: []*requestpb.RequestRef{
+				{ClientId: 0, ReqNo: 0, Digest: []byte{1}},
+			}}), 0)).To(Succeed())
+
+			var err error
+			dir, err = ioutil.TempDir("", "mirbft-cleanstop-")
+			Expect(err).NotTo(HaveOccurred())
+			markerFile = filepath.Join(dir, "cleanstop")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		restart := func() error {
+			protocol, err := iss.New(0, iss.DefaultConfig([]t.NodeID{0}), logging.NilLogger)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = mirbft.RestartNode(0, &mirbft.NodeConfig{Logger: logging.NilLogger, CleanStopFile: markerFile}, &modules.Modules{
+				Net:           discardingNet{},
+				Hasher:        crypto.SHA256,
+				App:           &deploytest.FakeApp{},
+				WAL:           wal,
+				ClientTracker: clients.SigningTracker(logging.NilLogger),
+				RequestStore:  reqstore.NewVolatileRequestStore(),
+				Protocol:      protocol,
+				Crypto:        &mirCrypto.DummyCrypto{DummySig: []byte{0}},
+			})
+			return err
+		}
+
+		It("checks the persisted state without a marker", func() {
+			Expect(restart()).To(MatchError(ContainSubstring("inconsistent persisted state")))
+		})
+
+		It("skips the consistency check if the marker matches the WAL tail and deletes the marker", func() {
+			writeMarker(markerFile, walMarker(wal))
+			Expect(restart()).To(Succeed())
+			Expect(markerFile).NotTo(BeAnExistingFile())
+		})
+
+		It("checks the persisted state if the marker does not match the WAL tail", func() {
+			marker := walMarker(wal)
+			marker.WalLength++
+			writeMarker(markerFile, marker)
+			Expect(restart()).To(MatchError(ContainSubstring("inconsistent persisted state")))
+			Expect(markerFile).NotTo(BeAnExistingFile())
+		})
+
+		It("falls back to checking the persisted state if the marker is corrupted", func() {
+			Expect(ioutil.WriteFile(markerFile, []byte("corrupted"), 0644)).To(Succeed())
+			Expect(restart()).To(MatchError(ContainSubstring("inconsistent persisted state")))
+			Expect(markerFile).NotTo(BeAnExistingFile())
+		})
+
+		It("falls back to checking the persisted state if the marker is malformed", func() {
+			marker := walMarker(wal)
+			marker.TailDigest = marker.TailDigest[:8]
+			writeMarker(markerFile, marker)
+			Expect(restart()).To(MatchError(ContainSubstring("inconsistent persisted state")))
+			Expect(markerFile).NotTo(BeAnExistingFile())
+		})
+	})
+})
+
+// walMarker returns the clean stop marker identifying the tail of wal:
+// its length, the retention index of its last entry, and the SHA-256 digest of the last entry's event.
+func walMarker(wal modules.WAL) *archivepb.CleanStopMarker {
+	marker := &archivepb.CleanStopMarker{}
+	var tail *eventpb.Event
+	Expect(wal.LoadAll(func(retentionIndex t.WALRetIndex, event *eventpb.Event) {
+		marker.WalLength++
+		marker.TailRetentionIndex = retentionIndex.Pb()
+		tail = event
+	})).To(Succeed())
+
+	if tail != nil {
+		data, err := proto.Marshal(tail)
+		Expect(err).NotTo(HaveOccurred())
+		digest := sha256.Sum256(data)
+		marker.TailDigest = digest[:]
+	}
+	return marker
+}
+
+// writeMarker writes marker to the given file.
+func writeMarker(fileName string, marker *archivepb.CleanStopMarker) {
+	data, err := proto.Marshal(marker)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(ioutil.WriteFile(fileName, data, 0644)).To(Succeed())
+}
+
+// expectMarker checks that the given file contains marker.
+func expectMarker(fileName string, marker *archivepb.CleanStopMarker) {
+	data, err := ioutil.ReadFile(fileName)
+	Expect(err).NotTo(HaveOccurred())
+	written := &archivepb.CleanStopMarker{}
+	Expect(proto.Unmarshal(data, written)).To(Succeed())
+	Expect(proto.Equal(written, marker)).To(BeTrue(), "written: %v, expected: %v", written, marker)
+}
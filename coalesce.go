@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// waitForMoreNetEvents implements the waiting for more messages to coalesce (see NodeConfig.CoalesceWindow).
+// For the duration of the coalescing window, it reads further lists of Net events from the corresponding work channel
+// and appends them to eventsIn, such that they are sent together.
+// It returns the number of additional lists read (which the caller must account for as finished work).
+// If coalescing is disabled, waitForMoreNetEvents returns immediately.
+// If exitC is closed, returns ErrStopped.
+func (n *Node) waitForMoreNetEvents(eventsIn *events.EventList, exitC <-chan struct{}) (int, error) {
+	window := n.localParams().CoalesceWindow
+	if window <= 0 {
+		return 0, nil
+	}
+
+	windowC := n.clock().After(window)
+	numLists := 0
+	for {
+		select {
+		case moreEvents := <-n.workChans.net:
+			eventsIn.PushBackList(moreEvents)
+			numLists++
+		case <-windowC:
+			return numLists, nil
+		case <-exitC:
+			return numLists, ErrStopped
+		}
+	}
+}
+
+// bundleMessages returns a single message to be sent instead of msgs.
+// If msgs only contains one message, it is returned as is. Otherwise, the messages are wrapped in a MessageBundle.
+func bundleMessages(msgs []*messagepb.Message) *messagepb.Message {
+	if len(msgs) == 1 {
+		return msgs[0]
+	}
+	return &messagepb.Message{Type: &messagepb.Message_Bundle{Bundle: &messagepb.MessageBundle{Msgs: msgs}}}
+}
+
+// messageReceivedEvents returns the MessageReceived events corresponding to a message received from node source.
+// For a MessageBundle, one event is returned for each contained message, in the order of the bundle.
+func (n *Node) messageReceivedEvents(source t.NodeID, msg *messagepb.Message) *events.EventList {
+	bundle, ok := msg.Type.(*messagepb.Message_Bundle)
+	if !ok {
+		return (&events.EventList{}).PushBack(n.causalTracer.messageReceived(source, msg))
+	}
+
+	eventsOut := &events.EventList{}
+	for _, m := range bundle.Bundle.Msgs {
+		eventsOut.PushBackList(n.messageReceivedEvents(source, m))
+	}
+	return eventsOut
+}
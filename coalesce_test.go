@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Message coalescing test", func() {
+
+	It("delivers all requests when coalescing messages", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Coalesce messages and count the sent bundles.
+		bundleNets := make([]*bundleCountingNet, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			bundleNets[i] = &bundleCountingNet{Net: replica.Net}
+			replica.Net = bundleNets[i]
+			replica.Config.CoalesceWindow = 5 * time.Millisecond
+		}
+
+		deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), func() bool {
+			for _, net := range bundleNets {
+				if atomic.LoadUint64(&net.bundles) == 0 {
+					return false
+				}
+			}
+			return true
+		}))
+
+		for i, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			Expect(atomic.LoadUint64(&bundleNets[i].bundles)).To(BeNumerically(">", 0))
+		}
+	})
+})
+
+// bundleCountingNet is a Net module wrapper that counts the sent message bundles.
+type bundleCountingNet struct {
+	modules.Net
+	bundles uint64
+}
+
+func (bn *bundleCountingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if _, ok := msg.Type.(*messagepb.Message_Bundle); ok {
+		atomic.AddUint64(&bn.bundles, 1)
+	}
+	return bn.Net.Send(dest, msg)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Committed returns a channel to which the Node writes the batches it commits, in the order of their sequence numbers,
+// starting from sequence number fromSn (batches with lower sequence numbers are omitted).
+// This allows consumers that only need the ordered log (e.g. auditors or indexers)
+// to follow it without being part of the application (see modules.App).
+// A batch is written to the channel as soon as its delivery to the application is scheduled.
+//
+// The Node never blocks on the channel. Instead, the batches the consumer did not read yet are queued in memory
+// (with no bound) and the consumer must keep up with reading them or cancel ctx,
+// after which the channel is closed and the batches are not queued any more.
+// The channel is also closed when the Node stops, after the consumer read all the queued batches.
+//
+// The log can be resumed after a restart of the Node (see RestartNode) from any sequence number
+// following the last stable checkpoint persisted in the WAL, as the Node commits the batches following
+// the checkpoint again when recovering. For this, Committed must be called before the Node is started.
+// Older batches are not available any more, as the WAL has been truncated.
+func (n *Node) Committed(ctx context.Context, fromSn t.SeqNr) <-chan *eventpb.Deliver {
+	feed := &committedFeed{
+		fromSn: fromSn,
+		outC:   make(chan *eventpb.Deliver),
+	}
+	feed.cond = sync.NewCond(&feed.lock)
+
+	n.committedFeeds.lock.Lock()
+	if n.committedFeeds.closed {
+		feed.closed = true
+	} else {
+		n.committedFeeds.feeds = append(n.committedFeeds.feeds, feed)
+	}
+	n.committedFeeds.lock.Unlock()
+
+	go feed.pump(ctx)
+	return feed.outC
+}
+
+// committedFeeds keeps track of the feeds created by Node.Committed().
+// The zero value is ready to use.
+type committedFeeds struct {
+
+	// All feeds (including those whose context has been canceled). Protected by lock.
+	feeds []*committedFeed
+
+	// Set when the Node stopped and all feeds have been closed. Protected by lock.
+	closed bool
+
+	lock sync.Mutex
+}
+
+// committedFeed passes the committed batches to a single consumer.
+type committedFeed struct {
+
+	// Batches with lower sequence numbers are not passed to the consumer.
+	fromSn t.SeqNr
+
+	// The channel returned to the consumer.
+	outC chan *eventpb.Deliver
+
+	// Committed batches not yet written to outC.
+	queue []*eventpb.Deliver
+
+	// Set when the Node stops. No more batches will be added to the queue.
+	closed bool
+
+	// Set when the consumer's context has been canceled. Batches are not queued any more.
+	canceled bool
+
+	// Protects the fields above and signals changes of them to the pump goroutine.
+	lock sync.Mutex
+	cond *sync.Cond
+}
+
+// add queues a committed batch, unless it precedes fromSn or the consumer is gone.
+func (f *committedFeed) add(deliver *eventpb.Deliver) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.canceled || t.SeqNr(deliver.Sn) < f.fromSn {
+		return
+	}
+	f.queue = append(f.queue, deliver)
+	f.cond.Signal()
+}
+
+// close makes the pump goroutine close outC after writing the queued batches.
+func (f *committedFeed) close() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.closed = true
+	f.cond.Signal()
+}
+
+// pump writes the queued batches to outC, until the feed is closed and the queue empty, or until ctx is canceled.
+func (f *committedFeed) pump(ctx context.Context) {
+	defer close(f.outC)
+
+	// Wake up the pump when the context is canceled.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			f.lock.Lock()
+			f.canceled = true
+			f.queue = nil
+			f.cond.Signal()
+			f.lock.Unlock()
+		case <-stop:
+		}
+	}()
+
+	for {
+		// Wait for a batch to be queued.
+		f.lock.Lock()
+		for len(f.queue) == 0 && !f.closed && !f.canceled {
+			f.cond.Wait()
+		}
+		if f.canceled || len(f.queue) == 0 {
+			f.lock.Unlock()
+			return
+		}
+		deliver := f.queue[0]
+		f.queue = f.queue[1:]
+		f.lock.Unlock()
+
+		// Pass it to the consumer.
+		select {
+		case f.outC <- deliver:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// publishCommitted adds the batches of the Deliver events among the given App events to all committed feeds.
+// The Deliver events are dispatched to the App in the order of their sequence numbers (see iss.deliverCommitted).
+// It must only be called from the process() goroutine.
+func (n *Node) publishCommitted(appEvents *events.EventList) {
+	n.committedFeeds.lock.Lock()
+	defer n.committedFeeds.lock.Unlock()
+
+	if len(n.committedFeeds.feeds) == 0 {
+		return
+	}
+
+	iter := appEvents.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if deliver, ok := event.Type.(*eventpb.Event_Deliver); ok {
+			for _, feed := range n.committedFeeds.feeds {
+				feed.add(deliver.Deliver)
+			}
+		}
+	}
+}
+
+// closeCommittedFeeds closes all committed feeds.
+// It must be called when the process() goroutine exits.
+func (n *Node) closeCommittedFeeds() {
+	n.committedFeeds.lock.Lock()
+	defer n.committedFeeds.lock.Unlock()
+
+	for _, feed := range n.committedFeeds.feeds {
+		feed.close()
+	}
+	n.committedFeeds.feeds = nil
+	n.committedFeeds.closed = true
+}
@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The committed log test follows the log of committed batches of all nodes, restarts the nodes,
+// and checks that the log resumes after the restart exactly where it stopped before.
+var _ = Describe("Committed log test", func() {
+
+	It("resumes the log of committed batches after a restart", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Runs the deployment, reading the committed log of each replica from the given sequence number,
+		// until condition holds, each replica committed (at least) the batch at that sequence number,
+		// and all committed batches are covered by a stable checkpoint.
+		runFrom := func(fromSns []t.SeqNr, condition func() bool) [][]*eventpb.Deliver {
+			logs := make([][]*eventpb.Deliver, len(deployment.TestReplicas))
+			var wg sync.WaitGroup
+			for i, replica := range deployment.TestReplicas {
+				i := i
+				replica.OnNode = func(node *mirbft.Node) {
+					committedC := node.Committed(context.Background(), fromSns[i])
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for deliver := range committedC {
+							logs[i] = append(logs[i], deliver)
+						}
+					}()
+				}
+			}
+
+			deployment.RunUntilThen(deploytest.AllOf(condition, func() bool {
+				for i, replica := range deployment.TestReplicas {
+					node := replica.Node()
+					if node == nil {
+						return false
+					}
+					if sn, ok := node.LastCommitted(); !ok || sn < fromSns[i] {
+						return false
+					}
+				}
+				return true
+			}), func() {
+				deployment.AwaitStableCheckpoint()
+			})
+			wg.Wait()
+			return logs
+		}
+
+		// Checks that the log contains consecutive sequence numbers starting at fromSn
+		// and returns the sequence number following the last one.
+		checkLog := func(log []*eventpb.Deliver, fromSn t.SeqNr) t.SeqNr {
+			Expect(log).NotTo(BeEmpty())
+			for i, deliver := range log {
+				Expect(t.SeqNr(deliver.Sn)).To(Equal(fromSn + t.SeqNr(i)))
+			}
+			return fromSn + t.SeqNr(len(log))
+		}
+
+		// Read the whole log during the first run, containing all requests.
+		fromSns := make([]t.SeqNr, len(deployment.TestReplicas))
+		logs := runFrom(fromSns, deployment.AllProcessed(testConfig.NumFakeRequests))
+		for i := range deployment.TestReplicas {
+			numRequests := 0
+			for _, deliver := range logs[i] {
+				numRequests += len(deliver.Batch.Requests)
+			}
+			Expect(numRequests).To(Equal(testConfig.NumFakeRequests))
+			fromSns[i] = checkLog(logs[i], 0)
+		}
+
+		// Restart all replicas and resume reading the log where it stopped.
+		for _, replica := range deployment.TestReplicas {
+			replica.App = &deploytest.FakeApp{}
+			replica.NumFakeRequests = 0
+			replica.Restart = true
+		}
+		logs = runFrom(fromSns, func() bool { return true })
+		for i := range deployment.TestReplicas {
+			checkLog(logs[i], fromSns[i])
+		}
+	})
+})
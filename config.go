@@ -8,15 +8,156 @@ Refactored: 1
 
 package mirbft
 
-import "github.com/hyperledger-labs/mirbft/pkg/logging"
+import (
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/causality"
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+)
+
+// LogModuleNode is the name of the logging module (see logging.ModuleLevels) the Node itself logs through,
+// e.g., when dropping invalid input, processing events or persisting data.
+const LogModuleNode = "node"
 
 // The NodeConfig struct represents configuration parameters of the node
 // that are independent of the protocol the Node is executing.
 // NodeConfig only contains protocol-independent parameters. Protocol-specific parameters
 // should be specified when instantiating the protocol implementation as one of the Node's modules.
+// The parameters that only affect the Node itself can be adjusted while the Node is running (see LocalParams).
 type NodeConfig struct {
 	// Logger provides the logging functions.
+	// If it is a logging.ModuleLevels, the Node logs through the LogModuleNode module,
+	// whose level can be adjusted independently of the other subsystems (e.g. the protocol, see iss.LogModuleISS).
 	Logger logging.Logger
+
+	// If not nil, enables causality tracing. Outgoing messages are assigned causal IDs
+	// and all steps of the resulting message flows are reported to Tracer (see package causality).
+	// Tracing is disabled by default.
+	Tracer causality.Tracer
+
+	// If not empty, the Node writes a clean stop marker to the file with this name when it is stopped gracefully
+	// (i.e., when Run returns ErrStopped). The marker identifies the tail of the WAL at the time of stopping.
+	// When restarting using RestartNode with the same CleanStopFile, if the marker matches the tail of the WAL,
+	// the (potentially expensive) consistency check of the WAL against the request store is skipped.
+	// The WAL is still loaded and replayed in full, as the marker does not contain any recovered state.
+	// The marker file is deleted on restart, such that it is used at most once.
+	// A marker that cannot be read or is malformed is ignored, and the persisted state is checked as without a marker.
+	CleanStopFile string
+
+	// Maximal size (in bytes) of a request payload. Larger requests are rejected, whether submitted locally
+	// (in which case SubmitRequest returns a RequestTooLargeError) or forwarded by another node.
+	// Each rejection is recorded as a RequestTooLarge event, visible to the event Interceptor (if any).
+	// This prevents a single client from exhausting the memory and bandwidth of the nodes.
+	// Zero means no limit.
+	MaxRequestSize int
+
+	// Maximal size (in bytes) of a (serialized) message received from another node.
+	// Larger messages (including whole message bundles) are dropped before being processed and each drop
+	// is recorded as a MessageTooLarge event, visible to the event Interceptor (if any).
+	// This prevents a faulty node from exhausting the memory of other nodes by sending huge messages.
+	// Note that the limit only applies after the Net module received (and deserialized) the message.
+	// The Net module must limit the size of the data it receives itself (as, e.g., gRPC does by default).
+	// Zero means no limit.
+	MaxMessageSize int
+
+	// Maximal number of events pending in the Node's internal buffers (see Node.Status) before the Node stops
+	// accepting new input through SubmitRequest and Step and stops reading messages from the Net module.
+	// This way, if the Node's modules fall behind (e.g. a slow WAL or application),
+	// the backpressure propagates to the callers instead of the Node buffering an unbounded amount of events.
+	// Events produced by the Node's modules are always accepted, so the limit can be exceeded temporarily.
+	// Zero means no limit.
+	MaxPendingEvents int
+
+	// If set to true, SubmitRequest and Step return a BackpressureError
+	// instead of blocking while the number of pending events is at or above MaxPendingEvents.
+	RejectOnBackpressure bool
+
+	// If set to true, when Run's exitC is closed, the Node does not stop immediately,
+	// but first drains the work already in flight.
+	// It stops accepting any new input (including messages, requests and ticks),
+	// but finishes processing all pending events, including the ones resulting from them,
+	// e.g. sending messages whose sending depends on WAL entries that have already been persisted.
+	// Only then Run returns ErrStopped.
+	// Otherwise, pending work is abandoned (though never in a way that would violate safety).
+	DrainOnStop bool
+
+	// Maximal duration of draining the in-flight work if DrainOnStop is set.
+	// If draining takes longer, the remaining work is abandoned. Zero means no limit.
+	DrainTimeout time.Duration
+
+	// If positive, the Node generates its logical time ticks itself, one every TickInterval,
+	// using a ticker that is started by Run and stopped when Run returns.
+	// In such a case, the tickC parameter of Run must be nil.
+	// Zero (the default) means that the calling code must write the ticks to the tickC parameter of Run.
+	TickInterval time.Duration
+
+	// Source of time for the time-based behavior of the Node: generating ticks (see TickInterval),
+	// timeouts (see DrainTimeout and CoalesceWindow), retry backoff (see SendRetryBackoff), streaming the status
+	// and timestamping oddities, the health indicators and dumps.
+	// Latency measurements (e.g. PersistLatencies and Metrics) always use the system clock, as they measure actual work.
+	// If nil (the default), the system clock is used. Tests can use a clock.Mock to advance time instantly.
+	Clock clock.Clock
+
+	// Number of goroutines verifying signatures concurrently (e.g. of client requests).
+	// Signature verification is CPU-heavy, so verifying multiple signatures in parallel
+	// prevents the verification from becoming a bottleneck when many requests arrive.
+	// If greater than 1, the Crypto module's verification methods must be safe for concurrent use.
+	// Values below 2 mean that signatures are verified sequentially.
+	NumVerifyWorkers int
+
+	// Number of times the Node retries sending a critical message (e.g. an ordering or checkpoint message)
+	// when the Net module fails to send it. Messages whose loss the protocol tolerates
+	// (e.g. forwarded requests) are never retried.
+	// If sending still fails after all retries, the destination node is considered unreachable
+	// and a PeerHealth event is reported to the protocol. Sending to an unreachable node is not retried
+	// until a message is sent to it successfully, at which point it is reported reachable again.
+	// As the Net module does not support concurrent sending, retries delay sending all subsequent messages.
+	SendRetries int
+
+	// Delay before the first retry of sending a message. The delay doubles with each subsequent retry.
+	SendRetryBackoff time.Duration
+
+	// If positive, messages to the same destination are coalesced into a single MessageBundle
+	// to reduce the per-message overhead of the network (framing, system calls).
+	// After obtaining messages to send, the Node waits for CoalesceWindow for more messages before sending them.
+	// The messages within a bundle are processed by the receiving Node in the order in which they were sent.
+	// Zero (the default) means that each message is sent individually without waiting.
+	CoalesceWindow time.Duration
+
+	// If positive, each destination node has an outbound queue holding at most SendQueueLength messages,
+	// from which the messages are sent by a goroutine dedicated to the destination.
+	// Thus, a slow or unreachable node only delays the messages sent to itself (including retries, see SendRetries)
+	// and the messages waiting to be sent to it never grow beyond SendQueueLength.
+	// When a queue is full, a message is dropped, preferring best-effort messages (e.g. forwarded requests),
+	// and the drop is recorded as a SendQueueOverflow event, visible to the event Interceptor (if any).
+	// Sent messages then no longer count as in-flight work when draining on stop (see DrainOnStop).
+	// The Net module must support concurrent sending to different destinations.
+	// Zero (the default) means that messages are sent synchronously, one by one.
+	SendQueueLength int
+
+	// If set, protocol-critical messages (e.g. ordering and checkpoint messages) are transmitted
+	// ahead of best-effort messages (e.g. forwarded requests, see messagePriority), such that bursts of bulk traffic
+	// do not delay the progress of the ordering protocol.
+	// Of each list of messages to send, the best-effort messages are only sent after all critical ones.
+	// When coalescing messages (see CoalesceWindow), critical and best-effort messages are bundled separately.
+	// With send queues (see SendQueueLength), queued critical messages are sent before the queued best-effort ones.
+	// The order of the critical messages among themselves (and of the best-effort ones) is preserved.
+	PrioritizeCriticalMessages bool
+
+	// If positive, each persistence operation (appending to or syncing the WAL, syncing the RequestStore)
+	// that takes longer than SlowPersistThreshold is logged as a warning and reported as a SlowPersist notification.
+	// Slow disks delay every message that depends on persisted state and thus slow down the whole protocol.
+	// The latencies are measured regardless of this setting (see Node.PersistLatencies).
+	// Zero (the default) disables the warnings.
+	SlowPersistThreshold time.Duration
+
+	// If not nil, the Node reports the duration of persisting, transmitting, hashing and committing through these hooks.
+	Metrics ProcessorMetrics
+
+	// Custom Processors driving the Node's modules, e.g. processing events of independent requests in parallel
+	// (see Processor and ParallelProcessor). Modules without a custom Processor are driven by a default one.
+	Processors Processors
 }
 
 // DefaultNodeConfig returns the default node configuration.
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// NodeDump is the machine-readable representation of the internal state of a Node, as written by Node.Dump.
+// It is only meant for (offline) debugging and its format, in particular the format of the protocol state,
+// may change at any time.
+type NodeDump struct {
+
+	// ID of the Node and the (local) time at which the dump has been taken.
+	NodeID t.NodeID
+	Time   time.Time
+
+	// Whether the Node is running, and the reason for halting if it halted.
+	Running bool
+	Halted  bool
+	Err     string
+
+	// Indicators of the Node's health (see Node.Healthy). The Err field of the report is contained in Err above.
+	Health *HealthReport
+
+	// The current epoch (see Node.EpochInfo), nil if the Node has not started its first epoch.
+	Epoch *EpochInfo
+
+	// The last committed sequence number (only meaningful if Committed is set)
+	// and the last stable checkpoint, if any (see Node.LastCommitted and Node.StableCheckpoint).
+	LastCommitted    t.SeqNr
+	Committed        bool
+	StableCheckpoint *eventpb.CheckpointStable
+
+	// The complete state of the Protocol module, if it implements modules.StateDumper, or nil.
+	// If obtaining the state failed, ProtocolErr describes the failure.
+	Protocol    interface{}
+	ProtocolErr string
+}
+
+// protocolDump is the result of a request for the state of the Protocol module (see Node.protocolDumps).
+type protocolDump struct {
+	state interface{}
+	err   error
+}
+
+// Dump writes a complete, machine-readable (JSON) representation of the internal state of the Node to w
+// (see NodeDump). Besides the Node-level information, the dump includes the whole state of the protocol
+// (if the Protocol module implements modules.StateDumper), e.g., with ISS, the client watermarks,
+// the messages buffered for future epochs per node, the orderers of the current epoch, and the checkpoint trackers.
+// This is far more detailed than Status and meant for offline debugging, e.g. of a cluster that does not make progress.
+// While the Node is running, the protocol state is obtained between two invocations of the Protocol module,
+// so Dump waits until the Protocol module finishes processing its current events.
+// Dump can also be called before the Node starts and after it stopped.
+// It must not be called concurrently with Run being invoked.
+func (n *Node) Dump(w io.Writer) error {
+	health := n.Healthy()
+	dump := &NodeDump{
+		NodeID:           n.ID,
+		Time:             n.clock().Now(),
+		Running:          atomic.LoadInt32(&n.running) != 0 && !health.Halted,
+		Halted:           health.Halted,
+		Health:           health,
+		Epoch:            n.EpochInfo(),
+		StableCheckpoint: n.StableCheckpoint(),
+	}
+	if health.Err != nil {
+		dump.Err = health.Err.Error()
+		health.Err = nil
+	}
+	dump.LastCommitted, dump.Committed = n.LastCommitted()
+
+	if _, ok := n.modules.Protocol.(modules.StateDumper); ok {
+		state := n.dumpProtocol()
+		dump.Protocol = state.state
+		if state.err != nil {
+			dump.ProtocolErr = state.err.Error()
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(dump); err != nil {
+		return fmt.Errorf("could not write node dump: %w", err)
+	}
+	return nil
+}
+
+// dumpProtocol obtains the state of the Protocol module, which must implement modules.StateDumper.
+// If the Node is running, the state is obtained by the goroutine processing the protocol events (see doProtocolWork).
+// Otherwise, the Protocol module is not being used and its state is obtained directly.
+func (n *Node) dumpProtocol() protocolDump {
+	dumper := n.modules.Protocol.(modules.StateDumper)
+
+	if atomic.LoadInt32(&n.running) != 0 {
+		resultC := make(chan protocolDump, 1)
+		select {
+		case n.protocolDumps <- resultC:
+			return <-resultC
+		case <-n.runDoneC:
+			// The Node stopped and the Protocol module is not used any more.
+		}
+	}
+
+	state, err := dumper.DumpState()
+	return protocolDump{state: state, err: err}
+}
+
+// serveProtocolDump obtains the state of the Protocol module and writes it to resultC.
+// It must only be called by the goroutine processing the protocol events.
+func (n *Node) serveProtocolDump(resultC chan protocolDump) {
+	state, err := n.modules.Protocol.(modules.StateDumper).DumpState()
+	resultC <- protocolDump{state: state, err: err}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The state dump test dumps the state of each node of a deployment before, while, and after running it
+// and checks that the dumps can be parsed and reflect the state of the nodes.
+var _ = Describe("State dump test", func() {
+
+	It("produces machine-readable dumps of the node state", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// parseDump parses a dump, with the protocol state as a generic JSON object.
+		parseDump := func(data []byte) (*mirbft.NodeDump, map[string]interface{}) {
+			var protocolState map[string]interface{}
+			dump := &mirbft.NodeDump{Protocol: &protocolState}
+			Expect(json.Unmarshal(data, dump)).To(Succeed())
+			return dump, protocolState
+		}
+
+		// Dump the state of each node before starting it and keep a reference to the node.
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		initialDumps := make([]bytes.Buffer, len(deployment.TestReplicas))
+		initialDumpErrs := make([]error, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+				initialDumpErrs[i] = node.Dump(&initialDumps[i])
+			}
+		}
+
+		// Dump the state of each node while running, after the requests have been committed.
+		runningDumps := make([]bytes.Buffer, len(deployment.TestReplicas))
+		runningDumpErrs := make([]error, len(deployment.TestReplicas))
+		conditions := deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllInEpoch(1))
+		deployment.RunUntilThen(conditions, func() {
+			for i, replica := range deployment.TestReplicas {
+				runningDumpErrs[i] = replica.Node().Dump(&runningDumps[i])
+			}
+		})
+
+		for i, node := range nodes {
+			Expect(initialDumpErrs[i]).NotTo(HaveOccurred())
+			dump, protocolState := parseDump(initialDumps[i].Bytes())
+			Expect(dump.NodeID).To(Equal(node.ID))
+			Expect(dump.Running).To(BeFalse())
+			Expect(dump.Committed).To(BeFalse())
+			Expect(protocolState["Epoch"]).To(BeNumerically("==", 0))
+
+			Expect(runningDumpErrs[i]).NotTo(HaveOccurred())
+			dump, protocolState = parseDump(runningDumps[i].Bytes())
+			Expect(dump.Running).To(BeTrue())
+			Expect(dump.Committed).To(BeTrue())
+			Expect(dump.Epoch).NotTo(BeNil())
+			Expect(protocolState["Epoch"]).To(BeNumerically("==", dump.Epoch.Epoch))
+			Expect(protocolState["Orderers"]).NotTo(BeEmpty())
+			Expect(protocolState["Buckets"]).To(HaveLen(len(deployment.TestReplicas)))
+			Expect(protocolState["MessageBuffers"]).To(HaveLen(len(deployment.TestReplicas) - 1))
+			Expect(protocolState["NextDeliveredSN"]).To(BeNumerically("==", dump.LastCommitted+1))
+
+			// After the node stopped, the dump is still available.
+			var finalDump bytes.Buffer
+			Expect(node.Dump(&finalDump)).To(Succeed())
+			dump, protocolState = parseDump(finalDump.Bytes())
+			Expect(dump.Running).To(BeFalse())
+			Expect(dump.Halted).To(BeTrue())
+			Expect(dump.Err).To(Equal(mirbft.ErrStopped.Error()))
+			Expect(protocolState["Epoch"]).To(BeNumerically(">", 0))
+		}
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// EpochInfo describes the epoch the Node is currently in (see Node.EpochInfo).
+// In each epoch, every request bucket is assigned to exactly one of the epoch's leaders,
+// which is the only node proposing the requests of that bucket in the epoch.
+// Client routing layers can thus submit requests to the leaders of their buckets to reduce latency.
+type EpochInfo struct {
+
+	// The epoch number.
+	Epoch t.EpochNr
+
+	// The leaders of the epoch, in the order returned by the leader selection policy.
+	Leaders []t.NodeID
+
+	// The leader each bucket is assigned to in the epoch, indexed by bucket ID.
+	BucketLeaders []t.NodeID
+
+	// The roles of the Node in the epoch: whether it is one of the leaders
+	// and, if so, the IDs of the buckets assigned to it (in increasing order).
+	Leader     bool
+	OwnBuckets []int
+}
+
+// epochInfo keeps track of the current epoch as announced by the EpochStarted notifications.
+// It is updated by the process() goroutine and read by any goroutine calling Node.EpochInfo.
+// The zero value is ready to use.
+type epochInfo struct {
+
+	// The last EpochStarted notification, nil if none has been produced yet. Protected by lock.
+	current *eventpb.EpochStarted
+
+	lock sync.Mutex
+}
+
+// EpochInfo returns information about the epoch the Node is currently in, including its leaders,
+// the assignment of request buckets to leaders, and the Node's own roles (see EpochInfo).
+// EpochInfo returns nil if the Node has not yet started its first epoch (e.g. if the Node has not been started yet).
+// After the Node stopped, EpochInfo returns information about the last epoch the Node has been in.
+// Changes of the epoch can be observed using Events.
+func (n *Node) EpochInfo() *EpochInfo {
+	n.epochInfo.lock.Lock()
+	current := n.epochInfo.current
+	n.epochInfo.lock.Unlock()
+
+	if current == nil {
+		return nil
+	}
+
+	info := &EpochInfo{
+		Epoch:         t.EpochNr(current.Epoch),
+		Leaders:       t.NodeIDSlice(current.Leaders),
+		BucketLeaders: t.NodeIDSlice(current.BucketLeaders),
+		OwnBuckets:    make([]int, 0),
+	}
+	for _, leader := range info.Leaders {
+		if leader == n.ID {
+			info.Leader = true
+		}
+	}
+	for bID, leader := range info.BucketLeaders {
+		if leader == n.ID {
+			info.OwnBuckets = append(info.OwnBuckets, bID)
+		}
+	}
+	return info
+}
+
+// updateEpochInfo records the last epoch announced by the given Notification events.
+// It must only be called from the process() goroutine.
+func (n *Node) updateEpochInfo(notifications *events.EventList) {
+	n.epochInfo.lock.Lock()
+	defer n.epochInfo.lock.Unlock()
+
+	iter := notifications.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		notification := event.Type.(*eventpb.Event_Notification).Notification
+		if epochStarted, ok := notification.Type.(*eventpb.Notification_EpochStarted); ok {
+			n.epochInfo.current = epochStarted.EpochStarted
+		}
+	}
+}
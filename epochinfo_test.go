@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The epoch introspection test runs a deployment and checks that the epoch information
+// reported by the nodes at the end is consistent with the nodes' view of the epoch.
+var _ = Describe("Epoch introspection test", func() {
+
+	It("reports the leaders and the bucket assignment of the current epoch", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Keep a reference to each node and its epoch information before being started.
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		initialInfos := make([]*mirbft.EpochInfo, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+				initialInfos[i] = node.EpochInfo()
+			}
+		}
+
+		deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllInEpoch(1)))
+
+		infos := make(map[t.EpochNr]*mirbft.EpochInfo)
+		for i, node := range nodes {
+			Expect(initialInfos[i]).To(BeNil())
+
+			info := node.EpochInfo()
+			Expect(info).NotTo(BeNil())
+			Expect(info.Epoch).To(BeNumerically(">", 0))
+			Expect(info.BucketLeaders).To(HaveLen(deployment.TestReplicas[i].ISSConfig.NumBuckets))
+
+			// Each bucket must be assigned to a leader and the node's own buckets to the node.
+			for _, leader := range info.BucketLeaders {
+				Expect(info.Leaders).To(ContainElement(leader))
+			}
+			for _, bID := range info.OwnBuckets {
+				Expect(info.BucketLeaders[bID]).To(Equal(node.ID))
+			}
+			if len(info.OwnBuckets) > 0 {
+				Expect(info.Leader).To(BeTrue())
+			}
+
+			// Nodes in the same epoch must agree on the epoch information (apart from their own roles).
+			if other, ok := infos[info.Epoch]; ok {
+				Expect(info.Leaders).To(Equal(other.Leaders))
+				Expect(info.BucketLeaders).To(Equal(other.BucketLeaders))
+			} else {
+				infos[info.Epoch] = info
+			}
+		}
+	})
+})
@@ -0,0 +1,179 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspbftpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The fuzz targets feed arbitrary input to a single node driven by a mirbft.Stepper.
+// Any panic in a module is converted to an error by the Stepper, and any error fails the target.
+// Run them using, e.g.:
+//
+//	go test -run '^$' -fuzz FuzzMessage
+
+// maxFuzzSteps bounds the number of steps processing the consequences of a fuzzed input.
+const maxFuzzSteps = 100
+
+// fuzzSig is the signature the dummy crypto module of the fuzzed node accepts.
+var fuzzSig = []byte{0}
+
+// FuzzMessage decodes its input as a message received from another node and,
+// if the node considers the message valid, makes the node process it.
+func FuzzMessage(f *testing.F) {
+	for _, msg := range fuzzSeedMessages() {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint64(1), data)
+	}
+
+	f.Fuzz(func(tt *testing.T, from uint64, data []byte) {
+		msg := &messagepb.Message{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return
+		}
+
+		stepper := startFuzzStepper(tt)
+		defer stepper.Stop()
+
+		// Invalid messages are dropped before reaching the modules. Rejecting them is the expected outcome.
+		eventsIn, err := stepper.ReceiveMessage(t.NodeID(from), msg)
+		if err != nil {
+			return
+		}
+		stepFuzzStepper(tt, stepper, eventsIn)
+	})
+}
+
+// FuzzClientRequest makes the node process a request submitted by a client.
+func FuzzClientRequest(f *testing.F) {
+	f.Add(uint64(0), uint64(0), []byte("request"), fuzzSig)
+	f.Add(uint64(7), uint64(1<<40), []byte{}, []byte("invalid signature"))
+
+	f.Fuzz(func(tt *testing.T, clientID uint64, reqNo uint64, data []byte, authenticator []byte) {
+		stepper := startFuzzStepper(tt)
+		defer stepper.Stop()
+
+		stepFuzzStepper(tt, stepper, events.ListOf(
+			events.ClientRequest(t.ClientID(clientID), t.ReqNo(reqNo), data, authenticator)))
+	})
+}
+
+// startFuzzStepper returns a started Stepper of node 0 of a 4-node system that has processed its initial events.
+func startFuzzStepper(tt *testing.T) *mirbft.Stepper {
+	membership := []t.NodeID{0, 1, 2, 3}
+	issConfig := iss.DefaultConfig(membership)
+	issConfig.CheckInvariants = true
+	protocol, err := iss.New(0, issConfig, logging.NilLogger)
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	stepper, err := mirbft.NewStepper(0, &mirbft.NodeConfig{Logger: logging.NilLogger}, &modules.Modules{
+		Net:           discardingNet{},
+		Hasher:        crypto.SHA256,
+		App:           &fuzzApp{},
+		WAL:           simplewal.NewVolatileWAL(),
+		ClientTracker: clients.SigningTracker(logging.NilLogger),
+		RequestStore:  reqstore.NewVolatileRequestStore(),
+		Protocol:      protocol,
+		Crypto:        &mirCrypto.DummyCrypto{DummySig: fuzzSig},
+	})
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	initial, err := stepper.Start()
+	if err != nil {
+		tt.Fatal(err)
+	}
+	stepFuzzStepper(tt, stepper, initial)
+	return stepper
+}
+
+// stepFuzzStepper makes stepper process eventsIn and all the events resulting from it (up to maxFuzzSteps steps),
+// failing the test if processing returns an error.
+func stepFuzzStepper(tt *testing.T, stepper *mirbft.Stepper, eventsIn *events.EventList) {
+	for i := 0; i < maxFuzzSteps && eventsIn.Len() > 0; i++ {
+		eventsOut, _, err := stepper.Step(eventsIn)
+		if err != nil {
+			stepper.Stop()
+			tt.Fatalf("error processing fuzzed input: %v", err)
+		}
+		eventsIn = eventsOut
+	}
+}
+
+// fuzzSeedMessages returns well-formed messages of the most common types, used as the seed corpus of FuzzMessage.
+func fuzzSeedMessages() []*messagepb.Message {
+	batch := &requestpb.Batch{Requests: []*requestpb.RequestRef{{
+		ClientId: 0,
+		ReqNo:    0,
+		Digest:   make([]byte, crypto.SHA256.Size()),
+	}}}
+
+	return []*messagepb.Message{
+		{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{Type: &isspb.ISSMessage_Sb{Sb: &isspb.SBMessage{
+			Epoch:    0,
+			Instance: 1,
+			Msg: &isspb.SBInstanceMessage{Type: &isspb.SBInstanceMessage_PbftPreprepare{
+				PbftPreprepare: &isspbftpb.Preprepare{Sn: 1, Batch: batch},
+			}},
+		}}}}},
+		{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{Type: &isspb.ISSMessage_Checkpoint{
+			Checkpoint: &isspb.Checkpoint{Epoch: 0, Sn: 64},
+		}}}},
+		{Type: &messagepb.Message_ForwardedRequest{ForwardedRequest: &requestpb.Request{
+			ClientId:      0,
+			ReqNo:         0,
+			Data:          []byte("request"),
+			Authenticator: fuzzSig,
+		}}},
+		{Type: &messagepb.Message_ReliableAck{ReliableAck: &messagepb.ReliableAck{
+			Session: 1,
+			Ranges:  []*messagepb.SnRange{{From: 0, To: 10}},
+		}}},
+	}
+}
+
+// fuzzApp is an App module that discards all batches and has an empty state.
+type fuzzApp struct{}
+
+func (fa *fuzzApp) Apply(batch *requestpb.Batch) error {
+	return nil
+}
+
+func (fa *fuzzApp) Snapshot() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (fa *fuzzApp) RestoreState(snapshot []byte) error {
+	return nil
+}
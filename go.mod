@@ -3,16 +3,19 @@ module github.com/hyperledger-labs/mirbft
 go 1.15
 
 require (
+	github.com/AlecAivazis/survey/v2 v2.3.7
 	github.com/alecthomas/template v0.0.0-20190718012654-fb15b899a751 // indirect
 	github.com/alecthomas/units v0.0.0-20190924025748-f65c72e2690d // indirect
 	github.com/dgraph-io/badger/v2 v2.2007.2
 	github.com/golang/protobuf v1.5.2
+	github.com/golang/snappy v0.0.1
 	github.com/onsi/ginkgo v1.8.0
 	github.com/onsi/gomega v1.5.0
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.6.1 // indirect
 	github.com/tidwall/wal v0.1.3
-	golang.org/x/sys v0.0.0-20200821140526-fda516888d29 // indirect
+	github.com/ttacon/chalk v0.0.0-20160626202418-22c06c80ed31
+	golang.org/x/sys v0.0.0-20220722155257-8c9f86f7a55f // indirect
 	google.golang.org/grpc v1.40.0
 	google.golang.org/protobuf v1.27.1
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// HealthReport is a snapshot of indicators of whether a Node is alive and making progress (see Node.Healthy).
+// The Node does not judge its own health. Instead, the application (e.g. a readiness or liveness probe)
+// compares the indicators to thresholds that make sense for its deployment.
+// For example, a Node whose TicksSinceCheckpoint keep growing while requests are being submitted
+// is most likely not able to make progress.
+type HealthReport struct {
+
+	// Set if the Node halted (see Node.Done), in which case Err holds the reason (see Node.Err).
+	Halted bool
+	Err    error
+
+	// Sequence number of the last batch dispatched to the application and the time it was dispatched at.
+	// LastCommit is the zero time if no batch has been dispatched since the Node started.
+	LastCommitSn t.SeqNr
+	LastCommit   time.Time
+
+	// Number of ticks since the low watermark of the log last moved, i.e., since the last stable checkpoint
+	// (or since the Node started, if there has not been a stable checkpoint yet).
+	TicksSinceCheckpoint uint64
+
+	// The current epoch and the number of ticks since it started.
+	// As an epoch ends when all its sequence numbers have been delivered,
+	// a large number of ticks in an epoch means that an epoch change is pending
+	// (e.g. because a leader is waiting to be suspected).
+	Epoch        t.EpochNr
+	TicksInEpoch uint64
+
+	// Number of events waiting in the Node's internal buffers to be processed by the modules
+	// and number of event lists being processed by the modules.
+	PendingEvents int
+	InFlight      int
+}
+
+// health keeps track of the indicators reported by Node.Healthy.
+// It is updated by the process() goroutine and read by any goroutine calling Node.Healthy.
+// The zero value is ready to use.
+type health struct {
+
+	// The indicators of the HealthReport maintained by the process() goroutine
+	// (apart from the last committed sequence number, see progress). Protected by lock.
+	lastCommit           time.Time
+	ticksSinceCheckpoint uint64
+	epoch                t.EpochNr
+	ticksInEpoch         uint64
+
+	lock sync.Mutex
+}
+
+// Healthy returns a structured report on the health of the Node (see HealthReport).
+// Unlike Status, Healthy never blocks (even if the Node is stuck) and it can be called at any time,
+// including before the Node is started and after it stopped, which makes it suitable for liveness probes.
+func (n *Node) Healthy() *HealthReport {
+	n.health.lock.Lock()
+	report := &HealthReport{
+		LastCommit:           n.health.lastCommit,
+		TicksSinceCheckpoint: n.health.ticksSinceCheckpoint,
+		Epoch:                n.health.epoch,
+		TicksInEpoch:         n.health.ticksInEpoch,
+	}
+	n.health.lock.Unlock()
+
+	report.LastCommitSn, _ = n.LastCommitted()
+	report.PendingEvents = int(atomic.LoadInt64(&n.pendingEvents))
+	report.InFlight = int(atomic.LoadInt64(&n.inFlight))
+
+	select {
+	case <-n.workErrNotifier.ExitC():
+		report.Halted = true
+		report.Err = n.workErrNotifier.Err()
+	default:
+	}
+
+	return report
+}
+
+// healthTick accounts for a tick in the health indicators.
+// It must only be called from the process() goroutine.
+func (n *Node) healthTick() {
+	n.health.lock.Lock()
+	defer n.health.lock.Unlock()
+
+	n.health.ticksSinceCheckpoint++
+	n.health.ticksInEpoch++
+}
+
+// healthCommitted records the time of delivering the batches (if any) in the given App events
+// in the health indicators.
+// It must only be called from the process() goroutine.
+func (n *Node) healthCommitted(appEvents *events.EventList) {
+	n.health.lock.Lock()
+	defer n.health.lock.Unlock()
+
+	iter := appEvents.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if _, ok := event.Type.(*eventpb.Event_Deliver); ok {
+			n.health.lastCommit = n.clock().Now()
+			return
+		}
+	}
+}
+
+// healthNotified updates the health indicators based on the given Notification events
+// (new epochs and stable checkpoints).
+// It must only be called from the process() goroutine.
+func (n *Node) healthNotified(notifications *events.EventList) {
+	n.health.lock.Lock()
+	defer n.health.lock.Unlock()
+
+	iter := notifications.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		switch notification := event.Type.(*eventpb.Event_Notification).Notification.Type.(type) {
+		case *eventpb.Notification_EpochStarted:
+			n.health.epoch = t.EpochNr(notification.EpochStarted.Epoch)
+			n.health.ticksInEpoch = 0
+		case *eventpb.Notification_CheckpointStable:
+			n.health.ticksSinceCheckpoint = 0
+		}
+	}
+}
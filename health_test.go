@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The health check test runs a deployment and checks that the health reports of the nodes
+// reflect the progress made by the nodes before they halted.
+var _ = Describe("Health check test", func() {
+
+	It("reports the progress and the halting of the nodes", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Keep a reference to each node and its health report before being started.
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		initialReports := make([]*mirbft.HealthReport, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+				initialReports[i] = node.Healthy()
+			}
+		}
+
+		deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deploytest.AllCommitted(deployment.TestReplicas, 1)))
+
+		for i, node := range nodes {
+			Expect(initialReports[i].Halted).To(BeFalse())
+			Expect(initialReports[i].LastCommit.IsZero()).To(BeTrue())
+
+			report := node.Healthy()
+			Expect(report.Halted).To(BeTrue())
+			Expect(report.Err).To(Equal(mirbft.ErrStopped))
+			Expect(report.LastCommit.IsZero()).To(BeFalse())
+			Expect(report.LastCommitSn).To(BeNumerically(">", 0))
+			Expect(report.TicksInEpoch).To(BeNumerically(">", 0))
+		}
+	})
+})
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+)
+
+// LocalParams are the tunable parameters of a Node that only affect the Node itself,
+// and not its agreement with the other nodes. Unlike the rest of the configuration,
+// they can be adjusted while the Node is running (see Node.Reconfigure),
+// without restarting the Node and without going through a configuration request ordered by the protocol.
+// The Node starts with the values of the NodeConfig fields of the same name, which describe their meaning.
+type LocalParams struct {
+	MaxRequestSize       int
+	MaxMessageSize       int
+	MaxPendingEvents     int
+	RejectOnBackpressure bool
+	NumVerifyWorkers     int
+	SendRetries          int
+	SendRetryBackoff     time.Duration
+
+	// Whether messages are coalesced into bundles is decided when the Node is created.
+	// Thus, CoalesceWindow can only be positive if the NodeConfig.CoalesceWindow was positive.
+	// Setting it to zero then makes the Node send the coalesced messages without waiting for more.
+	CoalesceWindow time.Duration
+
+	// Minimal level of the messages logged by the Node. The Node starts with logging.LevelDebug,
+	// i.e., it passes all messages to NodeConfig.Logger, unless NodeConfig.Logger is a logging.LevelFilter,
+	// in which case the Node starts with, and adjusts, the filter's level.
+	// Passing the same LevelFilter to the modules thus makes it possible to adjust their logging too.
+	// If NodeConfig.Logger is a logging.ModuleLevels, the Node logs through the LogModuleNode module
+	// and only the level of that module is adjusted.
+	LogLevel logging.LogLevel
+
+	// Maximal number of ticks between two proposals of this node as a leader, i.e., the timeout for cutting a batch
+	// that has not filled up yet (see, e.g., iss.Config.MaxProposeDelay). The protocol starts with its own configuration.
+	// A negative value (the initial one) leaves the protocol's parameter unchanged.
+	MaxProposeDelay int
+}
+
+// initLocalParams sets the initial local parameters of the Node from its configuration.
+func (n *Node) initLocalParams() {
+	switch logger := n.Config.Logger.(type) {
+	case *logging.ModuleLevels:
+		n.logger = logger.Module(LogModuleNode)
+	case *logging.LevelFilter:
+		n.logger = logger
+	default:
+		n.logger = logging.NewLevelFilter(logging.ForModule(logger, LogModuleNode), logging.LevelDebug)
+	}
+
+	n.params.Store(&LocalParams{
+		MaxRequestSize:       n.Config.MaxRequestSize,
+		MaxMessageSize:       n.Config.MaxMessageSize,
+		MaxPendingEvents:     n.Config.MaxPendingEvents,
+		RejectOnBackpressure: n.Config.RejectOnBackpressure,
+		NumVerifyWorkers:     n.Config.NumVerifyWorkers,
+		SendRetries:          n.Config.SendRetries,
+		SendRetryBackoff:     n.Config.SendRetryBackoff,
+		CoalesceWindow:       n.Config.CoalesceWindow,
+		LogLevel:             n.logger.Level(),
+		MaxProposeDelay:      -1,
+	})
+}
+
+// localParams returns the current local parameters of the Node. The returned value must not be modified.
+func (n *Node) localParams() *LocalParams {
+	return n.params.Load().(*LocalParams)
+}
+
+// LocalParams returns the local parameters the Node is currently using.
+// LocalParams is safe to be called concurrently.
+func (n *Node) LocalParams() LocalParams {
+	return *n.localParams()
+}
+
+// Reconfigure replaces all the local parameters of the Node (see LocalParams) by params.
+// To only adjust some parameters, obtain the current ones using Node.LocalParams first.
+// The Node-level parameters take effect immediately (for the batches of events processed from then on),
+// while the protocol parameters (if not negative) are passed to the protocol as a LocalParams event.
+// In the latter case, Reconfigure returns as soon as the event has been passed to the protocol.
+// Reconfigure returns an error if params are invalid (in which case none of them are applied),
+// or if ctx is canceled or the Node halts before the protocol parameters have been passed to the protocol.
+// Reconfigure is safe to be called concurrently.
+func (n *Node) Reconfigure(ctx context.Context, params LocalParams) error {
+	if err := n.checkLocalParams(&params); err != nil {
+		return fmt.Errorf("invalid local parameters: %w", err)
+	}
+
+	// Serialize reconfigurations, such that the Node-level and the protocol parameters stay consistent.
+	n.reconfigureLock.Lock()
+	defer n.reconfigureLock.Unlock()
+
+	n.params.Store(&params)
+	n.logger.SetLevel(params.LogLevel)
+	if params.MaxProposeDelay < 0 {
+		return nil
+	}
+
+	select {
+	case n.externalInput <- (&events.EventList{}).PushBack(events.LocalParams(params.MaxProposeDelay)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-n.workErrNotifier.ExitC():
+		return n.workErrNotifier.Err()
+	}
+}
+
+// checkLocalParams returns an error if the given local parameters cannot be used by the Node.
+func (n *Node) checkLocalParams(params *LocalParams) error {
+	switch {
+	case params.MaxRequestSize < 0:
+		return fmt.Errorf("negative MaxRequestSize: %d", params.MaxRequestSize)
+	case params.MaxMessageSize < 0:
+		return fmt.Errorf("negative MaxMessageSize: %d", params.MaxMessageSize)
+	case params.MaxPendingEvents < 0:
+		return fmt.Errorf("negative MaxPendingEvents: %d", params.MaxPendingEvents)
+	case params.SendRetries < 0:
+		return fmt.Errorf("negative SendRetries: %d", params.SendRetries)
+	case params.SendRetryBackoff < 0:
+		return fmt.Errorf("negative SendRetryBackoff: %v", params.SendRetryBackoff)
+	case params.CoalesceWindow < 0:
+		return fmt.Errorf("negative CoalesceWindow: %v", params.CoalesceWindow)
+	case params.CoalesceWindow > 0 && n.Config.CoalesceWindow <= 0:
+		return fmt.Errorf("cannot set CoalesceWindow, coalescing has not been enabled in the node configuration")
+	case params.LogLevel < logging.LevelDebug || params.LogLevel > logging.LevelError:
+		return fmt.Errorf("unknown LogLevel: %d", params.LogLevel)
+	}
+	return nil
+}
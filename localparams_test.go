@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+)
+
+// The local reconfiguration test adjusts the local parameters of running nodes
+// and checks that the nodes apply them without disrupting the ordering.
+var _ = Describe("Local reconfiguration test", func() {
+
+	It("adjusts local parameters at runtime", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// After the network started, limit the request size and shorten the batch cut timer of all nodes.
+		initialParams := make([]mirbft.LocalParams, len(nodes))
+		invalidErrs := make([]error, len(nodes))
+		reconfigureErrs := make([]error, len(nodes))
+		submitErrs := make([]error, len(nodes))
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			for i, replica := range deployment.TestReplicas {
+				node := replica.Node()
+				initialParams[i] = node.LocalParams()
+
+				invalid := node.LocalParams()
+				invalid.CoalesceWindow = time.Millisecond
+				invalidErrs[i] = node.Reconfigure(context.Background(), invalid)
+
+				params := node.LocalParams()
+				params.MaxRequestSize = 9
+				params.MaxProposeDelay = 1
+				params.LogLevel = logging.LevelWarn
+				reconfigureErrs[i] = node.Reconfigure(context.Background(), params)
+
+				submitErrs[i] = node.SubmitRequest(context.Background(), 0, 100, []byte("Oversized request"), nil)
+			}
+			Eventually(deployment.AllProcessed(testConfig.NumFakeRequests), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+
+			// The nodes started with the parameters of their configuration.
+			Expect(initialParams[i].MaxRequestSize).To(Equal(0))
+			Expect(initialParams[i].MaxProposeDelay).To(Equal(-1))
+
+			// Invalid parameters were rejected, valid ones applied.
+			Expect(invalidErrs[i]).To(HaveOccurred())
+			Expect(reconfigureErrs[i]).NotTo(HaveOccurred())
+			Expect(nodes[i].LocalParams().MaxRequestSize).To(Equal(9))
+			Expect(nodes[i].LocalParams().MaxProposeDelay).To(Equal(1))
+			Expect(nodes[i].LocalParams().LogLevel).To(Equal(logging.LevelWarn))
+
+			var tooLargeErr *mirbft.RequestTooLargeError
+			Expect(errors.As(submitErrs[i], &tooLargeErr)).To(BeTrue())
+			Expect(tooLargeErr.MaxSize).To(Equal(9))
+
+			// The nodes kept ordering the requests.
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
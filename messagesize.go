@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// MessageTooLargeError is returned by Node.Step if the message exceeds NodeConfig.MaxMessageSize.
+type MessageTooLargeError struct {
+	From t.NodeID
+
+	// Size of the rejected message in bytes.
+	Size int
+
+	// Maximal message size in bytes (as configured in NodeConfig.MaxMessageSize).
+	MaxSize int
+}
+
+// Error returns a description of the rejected message.
+func (e *MessageTooLargeError) Error() string {
+	return fmt.Sprintf("message from node %d too large: %d bytes (max %d bytes)", e.From, e.Size, e.MaxSize)
+}
+
+// checkMessageSize returns a MessageTooLargeError if the serialized size of msg, received from node from,
+// exceeds the maximal message size of the Node and nil otherwise.
+func (n *Node) checkMessageSize(from t.NodeID, msg *messagepb.Message) *MessageTooLargeError {
+	maxSize := n.localParams().MaxMessageSize
+	if maxSize == 0 {
+		return nil
+	}
+	if size := proto.Size(msg); size > maxSize {
+		return &MessageTooLargeError{
+			From:    from,
+			Size:    size,
+			MaxSize: maxSize,
+		}
+	}
+	return nil
+}
+
+// recordMessageTooLarge logs a dropped message, records the drop as a MessageTooLarge event
+// with the event Interceptor, and registers it as an oddity of the sender.
+// It must only be called from the process() goroutine.
+func (n *Node) recordMessageTooLarge(err *MessageTooLargeError) {
+	n.logger.Log(logging.LevelWarn, "Dropping received message.", "err", err)
+	n.interceptEvents((&events.EventList{}).PushBack(events.MessageTooLarge(err.From, err.Size, err.MaxSize)))
+	n.recordOddity(err.From, OddityMessageTooLarge, "%v", err)
+}
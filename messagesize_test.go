@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The maximum message size test makes one replica send, along with each message, an oversized message
+// that the receivers would not be able to process and checks that the receivers drop it and deliver all requests.
+var _ = Describe("Maximum message size test", func() {
+
+	It("drops oversized messages and delivers all requests", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.MaxMessageSize = 4096
+		}
+		deployment.TestReplicas[0].Net = &oversizeNet{Net: deployment.TestReplicas[0].Net, size: 8192}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// oversizeNet is a Net module wrapper that, along with each sent message,
+// sends a message whose payload is padded to the given size.
+// The payload is a message ISS does not accept, such that accepting the oversized message would crash the receiver.
+type oversizeNet struct {
+	modules.Net
+	size int
+}
+
+func (on *oversizeNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if err := on.Net.Send(dest, &messagepb.Message{Type: &messagepb.Message_DummyPreprepare{
+		DummyPreprepare: &messagepb.DummyPreprepare{Batch: &requestpb.Batch{Requests: []*requestpb.RequestRef{{
+			Digest: make([]byte, on.size),
+		}}}},
+	}}); err != nil {
+		return err
+	}
+	return on.Net.Send(dest, msg)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+)
+
+// ProcessorMetrics is an optional interface (see NodeConfig.Metrics) through which the Node reports
+// how long the I/O-heavy parts of processing events take, e.g. for exporting them to a monitoring system.
+// The hooks are invoked by the Node's default Processors and WAL stages
+// (custom Processors are expected to do their own instrumentation).
+// Each invocation covers the processing of one list of events and reports its duration d
+// and the number n of processed items.
+// Different hooks may be invoked concurrently, so implementations must be safe for concurrent use.
+// The hooks are invoked synchronously by the processing goroutines and thus must return quickly.
+type ProcessorMetrics interface {
+
+	// OnPersist is invoked after n entries have been appended to the WAL.
+	OnPersist(d time.Duration, n int)
+
+	// OnSync is invoked after the WAL has been synced. A single sync can cover the entries of multiple OnPersist calls.
+	OnSync(d time.Duration)
+
+	// OnTransmit is invoked after n message sending events have been processed by the Net module.
+	OnTransmit(d time.Duration, n int)
+
+	// OnHash is invoked after n digests have been computed.
+	OnHash(d time.Duration, n int)
+
+	// OnCommit is invoked after n batches have been applied to the App module.
+	OnCommit(d time.Duration, n int)
+}
+
+// nopProcessorMetrics is the ProcessorMetrics implementation used when the user does not provide one.
+// All its hooks do nothing.
+type nopProcessorMetrics struct{}
+
+func (nopProcessorMetrics) OnPersist(time.Duration, int)  {}
+func (nopProcessorMetrics) OnSync(time.Duration)          {}
+func (nopProcessorMetrics) OnTransmit(time.Duration, int) {}
+func (nopProcessorMetrics) OnHash(time.Duration, int)     {}
+func (nopProcessorMetrics) OnCommit(time.Duration, int)   {}
+
+// processorMetrics returns the ProcessorMetrics configured in config, or a no-op implementation if none is configured.
+func processorMetrics(config *NodeConfig) ProcessorMetrics {
+	if config.Metrics == nil {
+		return nopProcessorMetrics{}
+	}
+	return config.Metrics
+}
+
+// countBatches returns the number of batches to be applied to the App module contained in a list of events.
+func countBatches(eventList *events.EventList) int {
+	numBatches := 0
+	iter := eventList.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		switch event.Type.(type) {
+		case *eventpb.Event_Deliver, *eventpb.Event_AnnounceDummyBatch:
+			numBatches++
+		}
+	}
+	return numBatches
+}
@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+var _ = Describe("Processor metrics test", func() {
+
+	It("reports the processing of all requests", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		metrics := make([]*countingMetrics, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			metrics[i] = &countingMetrics{}
+			replica.Config.Metrics = metrics[i]
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for i, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			Expect(atomic.LoadInt64(&metrics[i].persisted)).To(BeNumerically(">", 0))
+			Expect(atomic.LoadInt64(&metrics[i].synced)).To(BeNumerically(">", 0))
+			Expect(atomic.LoadInt64(&metrics[i].transmitted)).To(BeNumerically(">", 0))
+			Expect(atomic.LoadInt64(&metrics[i].hashed)).To(BeNumerically(">=", testConfig.NumFakeRequests))
+			Expect(atomic.LoadInt64(&metrics[i].committed)).To(BeNumerically(">", 0))
+		}
+	})
+})
+
+// countingMetrics is a ProcessorMetrics implementation that counts the reported items.
+type countingMetrics struct {
+	persisted, synced, transmitted, hashed, committed int64
+}
+
+func (cm *countingMetrics) OnPersist(d time.Duration, n int) {
+	atomic.AddInt64(&cm.persisted, int64(n))
+}
+
+func (cm *countingMetrics) OnSync(d time.Duration) {
+	atomic.AddInt64(&cm.synced, 1)
+}
+
+func (cm *countingMetrics) OnTransmit(d time.Duration, n int) {
+	atomic.AddInt64(&cm.transmitted, int64(n))
+}
+
+func (cm *countingMetrics) OnHash(d time.Duration, n int) {
+	atomic.AddInt64(&cm.hashed, int64(n))
+}
+
+func (cm *countingMetrics) OnCommit(d time.Duration, n int) {
+	atomic.AddInt64(&cm.committed, int64(n))
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// mirbench runs a benchmark of an in-process cluster of Mir nodes (see package github.com/hyperledger-labs/mirbft/pkg/bench)
+// and prints its throughput and latency percentiles, e.g.:
+//
+//	mirbench --nodes 4 --clients 8 --requests 2000 --payload 1024 --batch 128
+//	mirbench --faults scenario.json --seed 7 --json >> results.jsonl
+//
+// The fault profile is a scenario file as accepted by faults.LoadScenario.
+// With --json, the result is printed as a single line of JSON, suitable for tracking the results across commits.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/hyperledger-labs/mirbft/pkg/bench"
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+)
+
+type arguments struct {
+	config *bench.Config
+	json   bool
+}
+
+// parseArgs parses the command line arguments. Unset parameters take the values of bench.DefaultConfig.
+func parseArgs(args []string) (*arguments, error) {
+	defaults := bench.DefaultConfig()
+	app := kingpin.New("mirbench", "Utility for benchmarking an in-process cluster of Mir nodes.")
+	nodes := app.Flag("nodes", "Number of nodes.").Default(fmt.Sprint(defaults.NumNodes)).Int()
+	clients := app.Flag("clients", "Number of clients.").Default(fmt.Sprint(defaults.NumClients)).Int()
+	requests := app.Flag("requests", "Number of requests per client.").Default(fmt.Sprint(defaults.RequestsPerClient)).Int()
+	payload := app.Flag("payload", "Request payload size in bytes.").Default(fmt.Sprint(defaults.PayloadSize)).Int()
+	window := app.Flag("window", "Maximal number of requests in flight per client.").Default(fmt.Sprint(defaults.Window)).Int()
+	batch := app.Flag("batch", "Maximal batch size.").Default(fmt.Sprint(defaults.MaxBatchSize)).Int()
+	proposeDelay := app.Flag("propose-delay", "Maximal number of ticks to wait for a batch to fill.").
+		Default(fmt.Sprint(defaults.MaxProposeDelay)).Int()
+	segment := app.Flag("segment", "Segment length.").Default(fmt.Sprint(defaults.SegmentLength)).Int()
+	tick := app.Flag("tick", "Tick interval.").Default(defaults.TickInterval.String()).Duration()
+	syncLatency := app.Flag("sync-latency", "Latency of syncing the WAL and request store.").Default("0s").Duration()
+	faultsFile := app.Flag("faults", "Scenario file describing the faults to inject.").ExistingFile()
+	seed := app.Flag("seed", "Seed of the random decisions of the fault injection.").Default("0").Int64()
+	timeout := app.Flag("timeout", "Maximal duration of the benchmark.").Default(defaults.Timeout.String()).Duration()
+	jsonOutput := app.Flag("json", "Print the result as JSON.").Bool()
+
+	if _, err := app.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := &bench.Config{
+		NumNodes:          *nodes,
+		NumClients:        *clients,
+		RequestsPerClient: *requests,
+		PayloadSize:       *payload,
+		Window:            *window,
+		MaxBatchSize:      *batch,
+		MaxProposeDelay:   *proposeDelay,
+		SegmentLength:     *segment,
+		TickInterval:      *tick,
+		SyncLatency:       *syncLatency,
+		Seed:              *seed,
+		Timeout:           *timeout,
+	}
+	if *faultsFile != "" {
+		scenario, err := faults.LoadScenario(*faultsFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Faults = scenario
+	}
+
+	return &arguments{config: config, json: *jsonOutput}, nil
+}
+
+func main() {
+	kingpin.Version("0.0.1")
+	args, err := parseArgs(os.Args[1:])
+	if err != nil {
+		kingpin.Fatalf("Cannot parse given argument: %v", err)
+	}
+
+	result, err := bench.Run(args.config)
+	if result != nil {
+		if args.json {
+			data, jsonErr := json.Marshal(result)
+			if jsonErr != nil {
+				kingpin.Fatalf("Cannot marshal result: %v", jsonErr)
+			}
+			fmt.Println(string(data))
+		} else {
+			fmt.Println(result)
+		}
+	}
+	if err != nil {
+		kingpin.Fatalf("Benchmark failed: %v", err)
+	}
+}
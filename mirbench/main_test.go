@@ -0,0 +1,45 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/bench"
+)
+
+var _ = Describe("parseArgs", func() {
+	It("uses the default configuration", func() {
+		args, err := parseArgs([]string{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.config).To(Equal(bench.DefaultConfig()))
+		Expect(args.json).To(BeFalse())
+	})
+
+	It("parses a populated command line", func() {
+		tmpDir, err := ioutil.TempDir("", "mirbench-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+		scenarioFile := filepath.Join(tmpDir, "scenario.json")
+		Expect(ioutil.WriteFile(scenarioFile, []byte(`{"faults": [{"type": "drop", "rate": 0.1}]}`), 0644)).To(Succeed())
+
+		args, err := parseArgs([]string{
+			"--nodes", "7", "--clients", "2", "--requests", "10", "--payload", "1024",
+			"--batch", "16", "--tick", "5ms", "--faults", scenarioFile, "--seed", "3", "--json",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.config.NumNodes).To(Equal(7))
+		Expect(args.config.NumClients).To(Equal(2))
+		Expect(args.config.RequestsPerClient).To(Equal(10))
+		Expect(args.config.PayloadSize).To(Equal(1024))
+		Expect(args.config.MaxBatchSize).To(Equal(16))
+		Expect(args.config.TickInterval).To(Equal(5 * time.Millisecond))
+		Expect(args.config.Faults).NotTo(BeNil())
+		Expect(args.config.Seed).To(Equal(int64(3)))
+		Expect(args.json).To(BeTrue())
+	})
+})
@@ -0,0 +1,13 @@
+package main_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMirbench(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Mirbench Suite")
+}
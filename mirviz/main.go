@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// mirviz renders the state of a Mir node and the message flow between Mir nodes as Graphviz graphs
+// (see package github.com/hyperledger-labs/mirbft/pkg/visualize), e.g.:
+//
+//	mirviz status --src status.json --svg --out status.svg
+//	mirviz flow --src node0.gz --src node1.gz --silence 5s --out flow.dot
+//
+// The status is read in the JSON representation produced by mirbft.MarshalStatusJSON,
+// and the message flow from event logs written by eventlog.Recorder.
+// Without --svg, the output is in the DOT language. Rendering SVG requires the dot tool of Graphviz.
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/visualize"
+)
+
+type arguments struct {
+	command string
+	srcs    []string
+	out     string
+	svg     bool
+	silence time.Duration
+}
+
+// parseArgs parses the command line arguments.
+func parseArgs(args []string) (*arguments, error) {
+	app := kingpin.New("mirviz", "Utility for rendering Mir node status and message flow as Graphviz graphs.")
+	out := app.Flag("out", "The file to write the graph to (defaults to stdout).").Default("-").String()
+	svg := app.Flag("svg", "Render the graph as SVG instead of DOT (requires Graphviz).").Bool()
+
+	status := app.Command("status", "Render the status of a node.")
+	statusSrc := status.Flag("src", "The JSON status file to read.").Required().ExistingFile()
+
+	flow := app.Command("flow", "Render the message flow from event logs.")
+	flowSrcs := flow.Flag("src", "An event log to read (repeatable, one per node).").Required().ExistingFiles()
+	silence := flow.Flag("silence", "Highlight nodes that did not send any message for this long "+
+		"before the end of the event logs.").Default("0s").Duration()
+
+	command, err := app.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &arguments{
+		command: command,
+		out:     *out,
+		svg:     *svg,
+		silence: *silence,
+	}
+	switch command {
+	case status.FullCommand():
+		parsed.srcs = []string{*statusSrc}
+	case flow.FullCommand():
+		parsed.srcs = *flowSrcs
+	}
+	return parsed, nil
+}
+
+// render returns the graph selected by args in the DOT language.
+func render(args *arguments) (string, error) {
+	switch args.command {
+	case "status":
+		data, err := ioutil.ReadFile(args.srcs[0])
+		if err != nil {
+			return "", err
+		}
+		status, err := mirbft.UnmarshalStatusJSON(data)
+		if err != nil {
+			return "", fmt.Errorf("could not parse status: %w", err)
+		}
+		return visualize.StatusDOT(status), nil
+	case "flow":
+		flow := visualize.NewMessageFlow(args.silence)
+		for _, src := range args.srcs {
+			if err := addEventLog(flow, src); err != nil {
+				return "", fmt.Errorf("could not read event log %s: %w", src, err)
+			}
+		}
+		return flow.DOT(), nil
+	default:
+		return "", fmt.Errorf("unknown command: %s", args.command)
+	}
+}
+
+// addEventLog adds all the entries of the event log in the file at path to flow.
+func addEventLog(flow *visualize.MessageFlow, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := eventlog.NewReader(file)
+	if err != nil {
+		return err
+	}
+	entry, err := reader.ReadEntry()
+	for ; err == nil; entry, err = reader.ReadEntry() {
+		flow.Add(entry)
+	}
+	if err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func main() {
+	kingpin.Version("0.0.1")
+	args, err := parseArgs(os.Args[1:])
+	if err != nil {
+		kingpin.Fatalf("Cannot parse given argument: %v", err)
+	}
+
+	dot, err := render(args)
+	if err != nil {
+		kingpin.Fatalf("Cannot render graph: %v", err)
+	}
+	output := []byte(dot)
+	if args.svg {
+		if output, err = visualize.SVG(dot); err != nil {
+			kingpin.Fatalf("Cannot render SVG: %v", err)
+		}
+	}
+
+	if args.out == "-" {
+		_, err = os.Stdout.Write(output)
+	} else {
+		err = ioutil.WriteFile(args.out, output, 0644)
+	}
+	if err != nil {
+		kingpin.Fatalf("Cannot write output: %v", err)
+	}
+}
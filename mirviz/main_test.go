@@ -0,0 +1,32 @@
+package main
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseArgs", func() {
+	It("parses the status command", func() {
+		args, err := parseArgs([]string{"--svg", "status", "--src", "main.go"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.command).To(Equal("status"))
+		Expect(args.srcs).To(Equal([]string{"main.go"}))
+		Expect(args.svg).To(BeTrue())
+		Expect(args.out).To(Equal("-"))
+	})
+
+	It("parses the flow command with multiple event logs", func() {
+		args, err := parseArgs([]string{"flow", "--src", "main.go", "--src", "main_test.go", "--silence", "5s"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.command).To(Equal("flow"))
+		Expect(args.srcs).To(Equal([]string{"main.go", "main_test.go"}))
+		Expect(args.silence).To(Equal(5 * time.Second))
+	})
+
+	It("requires a source", func() {
+		_, err := parseArgs([]string{"status"})
+		Expect(err).To(HaveOccurred())
+	})
+})
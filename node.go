@@ -9,20 +9,43 @@ package mirbft
 import (
 	"context"
 	"fmt"
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
 	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
 	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/archivepb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstoremetrics"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var ErrStopped = fmt.Errorf("stopped at caller request")
 
+// Number of requests rejected by SubmitRequest that can wait to be recorded by the Node.
+const rejectedRequestsBufferSize = 64
+
+// Number of messages dropped from full send queues that can wait to be recorded by the Node.
+const sendOverflowsBufferSize = 64
+
 // Node is the local instance of MirBFT and the application's interface to the mirbft library.
 type Node struct {
+	// Number of events pending in workItems, as last observed by the process() goroutine.
+	// Accessed atomically by other goroutines (and thus placed first in the struct to guarantee 64-bit alignment).
+	pendingEvents int64
+
+	// Number of event lists dispatched to the workers and not yet fully processed.
+	// Used for determining when all in-flight work has been drained on a graceful stop (see NodeConfig.DrainOnStop).
+	// Accessed atomically (and thus placed at the start of the struct to guarantee 64-bit alignment).
+	inFlight int64
+
+	// Set to 1 (atomically) when Run is called.
+	running int32
+
 	ID     t.NodeID    // Protocol-level node ID
 	Config *NodeConfig // Node-level (protocol-independent) configuration, like buffer sizes, logging, ...
 
@@ -49,6 +72,99 @@ type Node struct {
 	// to which the state machine status needs to be written once the status is obtained.
 	// TODO: Implement obtaining and writing the status (Currently no one reads from this channel).
 	statusC chan chan *statuspb.NodeStatus
+
+	// Channel for receiving requests for the state of the Protocol module (see Dump).
+	// Like a status request, a dump request is itself represented as a channel to which the result needs to be written.
+	protocolDumps chan chan protocolDump
+
+	// Assigns and propagates causal IDs of messages if causality tracing is enabled, nil otherwise.
+	causalTracer *causalTracer
+
+	// Wraps the RequestStore module and keeps track of request store metrics.
+	// It is also the RequestStore used by the node (modules.RequestStore).
+	reqStoreMetrics *reqstoremetrics.RequestStore
+
+	// Channel through which SubmitRequest and Step pass external input to the process() goroutine.
+	// Unlike workChans.workItemInput, which is always read, this channel is only read
+	// as long as the number of pending events is below NodeConfig.MaxPendingEvents.
+	externalInput chan *events.EventList
+
+	// Signaled (without blocking) by the workers whenever the number of in-flight event lists drops to zero.
+	idleC chan struct{}
+
+	// Requests rejected by SubmitRequest for being too large, to be recorded by the process() goroutine.
+	// The channel is buffered and SubmitRequest does not block on it. If the buffer is full, the record is omitted.
+	rejectedRequests chan *RequestTooLargeError
+
+	// Sends messages using the Net module, applying the send policy (see NodeConfig.SendRetries).
+	sender *sender
+
+	// Messages dropped from full send queues (see NodeConfig.SendQueueLength), to be recorded by the process() goroutine.
+	// The channel is buffered and the send queues do not block on it. If the buffer is full, the record is omitted.
+	sendOverflows chan *eventpb.Event
+
+	// Subscribers of the Node's events (see Events()).
+	subscribers subscribers
+
+	// Suspicious behavior of other nodes (see Oddities()).
+	oddities oddities
+
+	// Messages exchanged with other nodes (see PeerTraffic()).
+	peerTraffic peerTraffic
+
+	// Latencies of the WAL and RequestStore operations (see PersistLatencies()).
+	persistLatencies persistLatencies
+
+	// Consumers of the committed batches (see Committed()).
+	committedFeeds committedFeeds
+
+	// How far the Node got in committing batches and requests (see LastCommitted()).
+	progress progress
+
+	// Indicators of the Node's health (see Healthy()).
+	health health
+
+	// The epoch the Node is currently in (see EpochInfo()).
+	epochInfo epochInfo
+
+	// Callers of Snapshot() waiting for a stable checkpoint.
+	checkpointWaiters checkpointWaiters
+
+	// Linearizable reads being served (see Read()).
+	reads reads
+
+	// Hooks for reporting the duration of processing events (see NodeConfig.Metrics). Never nil.
+	metrics ProcessorMetrics
+
+	// Reusable hash.Hash objects created by the Hasher module.
+	hashers *hasherPool
+
+	// Processors driving the Node's modules (see NodeConfig.Processors).
+	processors Processors
+
+	// The current local parameters (a *LocalParams, see Reconfigure),
+	// replaced atomically and protected from concurrent replacement by reconfigureLock.
+	params          atomic.Value
+	reconfigureLock sync.Mutex
+
+	// Logger used by the Node itself, wrapping NodeConfig.Logger (see LocalParams.LogLevel).
+	logger *logging.LevelFilter
+
+	// Closed (once, using stopOnce) by Stop to make the process() goroutine drain the in-flight work and stop.
+	stopC    chan struct{}
+	stopOnce sync.Once
+
+	// Closed when Run returns, after setting runErr to the error Run returns.
+	runDoneC chan struct{}
+	runErr   error
+
+	// Entries of the WAL loaded by RestartNode, such that Run does not need to load them again.
+	// Nil if the node has been created using NewNode.
+	recoveredWAL []*archivepb.WALEntry
+
+	// Wrapper of the WAL module tracking the tail of the WAL for writing a clean stop marker
+	// (see NodeConfig.CleanStopFile). Nil if no marker is written.
+	walTail *walTailTracker
 }
 
 // NewNode creates a new node with numeric ID id.
@@ -66,8 +182,23 @@ func NewNode(
 		return nil, err
 	}
 
-	// Return a new Node.
-	return &Node{
+	// Keep track of request store metrics.
+	// If the application already wrapped the RequestStore (e.g. to also account for its own accesses), use its wrapper.
+	reqStoreMetrics, ok := modulesWithDefaults.RequestStore.(*reqstoremetrics.RequestStore)
+	if !ok {
+		reqStoreMetrics = reqstoremetrics.NewRequestStore(modulesWithDefaults.RequestStore)
+		modulesWithDefaults.RequestStore = reqStoreMetrics
+	}
+
+	// Keep track of the tail of the WAL if a clean stop marker is to be written when the Node stops.
+	var walTail *walTailTracker
+	if config.CleanStopFile != "" {
+		walTail = newWALTailTracker(modulesWithDefaults.WAL)
+		modulesWithDefaults.WAL = walTail
+	}
+
+	// Create a new Node.
+	n := &Node{
 		ID:     id,
 		Config: config,
 
@@ -77,14 +208,124 @@ func NewNode(
 		workItems:       newWorkItems(),
 		workErrNotifier: newWorkErrNotifier(),
 
-		statusC: make(chan chan *statuspb.NodeStatus),
-	}, nil
+		statusC:       make(chan chan *statuspb.NodeStatus),
+		protocolDumps: make(chan chan protocolDump),
+
+		causalTracer: newCausalTracer(id, config.Tracer),
+
+		reqStoreMetrics: reqStoreMetrics,
+		walTail:         walTail,
+
+		hashers: newHasherPool(modulesWithDefaults.Hasher),
+		metrics: processorMetrics(config),
+
+		externalInput:    make(chan *events.EventList),
+		idleC:            make(chan struct{}, 1),
+		rejectedRequests: make(chan *RequestTooLargeError, rejectedRequestsBufferSize),
+		sendOverflows:    make(chan *eventpb.Event, sendOverflowsBufferSize),
+		stopC:            make(chan struct{}),
+		runDoneC:         make(chan struct{}),
+	}
+
+	// Initialize the local parameters that can be adjusted at runtime and the sender using them.
+	n.initLocalParams()
+	n.sender = newSender(modulesWithDefaults.Net, n.localParams, &n.peerTraffic, n.clock())
+
+	// Decouple sending to each destination from the others if configured.
+	if config.SendQueueLength > 0 {
+		n.sender.queues = newSendQueues(
+			config.SendQueueLength,
+			config.PrioritizeCriticalMessages,
+			n.sender,
+			n.outputSendEvent,
+			n.recordSendOverflow,
+			n.workErrNotifier.ExitC(),
+		)
+	}
+
+	// Use the default Processors for modules for which the user did not specify one.
+	n.processors = n.defaultProcessors(config.Processors)
+
+	return n, nil
+}
+
+// RestartNode creates a new node with numeric ID id that recovers the state it persisted before stopping
+// (e.g. before a crash).
+// The parameters have the same meaning as with NewNode. However, RestartNode requires the WAL and the RequestStore
+// modules to be explicitly specified, as they must contain the data persisted by the node before it stopped.
+// Before returning, RestartNode checks the WAL and the RequestStore for consistency and returns an error if
+// they do not match (e.g., if the WAL references requests that are not present in the RequestStore).
+// This check is skipped if the node previously stopped gracefully and left behind a clean stop marker
+// matching the tail of the WAL (see NodeConfig.CleanStopFile). The WAL is loaded and replayed in full regardless.
+// RestartNode also returns an error if the WAL contains a stable checkpoint without the corresponding
+// application snapshot, regardless of the clean stop marker.
+// The recovered state is applied when the returned Node is started using Run.
+// Requests that have not been committed before the node stopped are not recovered
+// (only the proposals referencing them are) and need to be re-submitted by the clients.
+// See the architecture documentation (docs/README.md) for a description of the whole recovery process.
+func RestartNode(
+	id t.NodeID,
+	config *NodeConfig,
+	m *modules.Modules,
+) (*Node, error) {
+
+	// A restarted node cannot rely on the default (volatile) modules for its persistent state.
+	if m.WAL == nil {
+		return nil, fmt.Errorf("cannot restart node without a WAL")
+	}
+	if m.RequestStore == nil {
+		return nil, fmt.Errorf("cannot restart node without a request store")
+	}
+
+	// Read the clean stop marker, if the node is configured to use one and has left one behind.
+	var marker *archivepb.CleanStopMarker
+	if config.CleanStopFile != "" {
+		marker = consumeCleanStopMarker(config.CleanStopFile, logging.ForModule(config.Logger, LogModuleNode))
+	}
+
+	// Load the contents of the WAL.
+	walEntries, err := loadWAL(m.WAL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Regardless of how the node stopped, it cannot recover a stable checkpoint without its application snapshot.
+	if err := checkRecoveredCheckpoints(walEntries); err != nil {
+		return nil, fmt.Errorf("inconsistent persisted state: %w", err)
+	}
+
+	// If the node stopped gracefully and nothing has been appended to the WAL since,
+	// the persisted state is known to be consistent.
+	// Otherwise, check whether the persisted state can be recovered.
+	cleanStop := false
+	if marker != nil {
+		if cleanStop, err = markerMatches(marker, walEntries); err != nil {
+			return nil, err
+		}
+	}
+	if cleanStop {
+		logging.ForModule(config.Logger, LogModuleNode).Log(logging.LevelInfo, "Clean stop marker matches WAL tail. Skipping consistency check.",
+			"walLength", marker.WalLength)
+	} else {
+		if err := checkRecoveredState(walEntries, m.RequestStore); err != nil {
+			return nil, fmt.Errorf("inconsistent persisted state: %w", err)
+		}
+	}
+
+	// Apart from the checks above, the node is constructed the same way as a new one.
+	// All the state is recovered by Run, which processes the contents of the WAL before starting the node.
+	node, err := NewNode(id, config, m)
+	if err != nil {
+		return nil, err
+	}
+	node.recoveredWAL = walEntries
+	return node, nil
 }
 
 // Status returns a static snapshot in time of the internal state of the Node.
-// TODO: Currently a call to Status blocks until the node is stopped, as obtaining status is not yet implemented.
-//       Also change the return type to be a protobuf object that contains a field for each module
-//       with module-specific contents.
+// While the Node is running, the status only contains the numbers of events pending in the Node's internal buffers.
+// After the Node stopped, Status returns the final status of the protocol.
+// TODO: Also obtain the status of the protocol and the other modules while the Node is running.
 func (n *Node) Status(ctx context.Context) (*statuspb.NodeStatus, error) {
 
 	// Submit status request for processing by the process() function.
@@ -112,12 +353,57 @@ func (n *Node) Status(ctx context.Context) (*statuspb.NodeStatus, error) {
 	}
 }
 
+// RequestStoreMetrics returns a snapshot of the metrics of the Node's RequestStore
+// (the amount of stored and not yet committed request data and the latency of request store operations).
+// Only accesses to the RequestStore performed by the Node itself are accounted for,
+// unless the RequestStore module passed to the Node has already been wrapped using reqstoremetrics.NewRequestStore
+// and the application accesses the RequestStore through the same wrapper.
+// RequestStoreMetrics can be called concurrently with the operation of the Node.
+func (n *Node) RequestStoreMetrics() reqstoremetrics.Metrics {
+	return n.reqStoreMetrics.Metrics()
+}
+
+// Done returns a channel that is closed when the Node halts,
+// either because it has been stopped by the caller (see Run) or because it encountered an error
+// (e.g. a failure of the WAL or the RequestStore, or a panic in any of the modules).
+// Embedding applications can use it to detect a halted Node and shut down gracefully, inspecting Err.
+func (n *Node) Done() <-chan struct{} {
+	return n.workErrNotifier.ExitC()
+}
+
+// Err returns the error that made the Node halt (ErrStopped if it has been stopped by the caller)
+// or nil if the Node has not halted (yet).
+func (n *Node) Err() error {
+	return n.workErrNotifier.Err()
+}
+
 // Step inserts a new incoming message msg in the Node.
 // The source parameter specifies the numeric ID of the sender of the message.
 // The Node assumes the message to be authenticated and it is the caller's responsibility
 // to make sure that msg has indeed been sent by source,
 // for example by using an authenticated communication channel (e.g. TLS) with the source node.
+// If the Node is overloaded (see NodeConfig.MaxPendingEvents), Step blocks
+// or returns a BackpressureError (if NodeConfig.RejectOnBackpressure is set).
+// If msg exceeds NodeConfig.MaxMessageSize, it is rejected and Step returns a MessageTooLargeError.
+// If msg is invalid (e.g. sent by a node outside the protocol's membership or lacking required fields),
+// it is rejected and Step returns an InvalidMessageError, such that the caller can log and drop it.
 func (n *Node) Step(ctx context.Context, source t.NodeID, msg *messagepb.Message) error {
+	n.peerTraffic.messageReceived(source, msg)
+
+	// Reject the message if it is too large.
+	if err := n.checkMessageSize(source, msg); err != nil {
+		return err
+	}
+
+	// Reject the message if it is invalid.
+	if err := n.validateMessage(source, msg); err != nil {
+		return err
+	}
+
+	// Reject the message if the Node is overloaded and configured to reject input in such a case.
+	if err := n.checkBackpressure(); err != nil {
+		return err
+	}
 
 	// Pre-process the incoming message and return an error if pre-processing fails.
 	// TODO: Re-enable pre-processing.
@@ -126,12 +412,12 @@ func (n *Node) Step(ctx context.Context, source t.NodeID, msg *messagepb.Message
 	//	return errors.WithMessage(err, "pre-processing message failed")
 	//}
 
-	// Create a MessageReceived event
-	e := (&events.EventList{}).PushBack(events.MessageReceived(source, msg))
+	// Create a MessageReceived event (one for each message, if msg is a MessageBundle).
+	e := n.messageReceivedEvents(source, msg)
 
 	// Enqueue event in a work channel to be handled by the processing thread.
 	select {
-	case n.workChans.workItemInput <- e:
+	case n.externalInput <- e:
 		return nil
 	case <-n.workErrNotifier.ExitStatusC():
 		return n.workErrNotifier.Err()
@@ -143,6 +429,10 @@ func (n *Node) Step(ctx context.Context, source t.NodeID, msg *messagepb.Message
 // SubmitRequest submits a new client request to the Node.
 // clientID and reqNo uniquely identify the request.
 // data constitutes the (opaque) payload of the request.
+// If data exceeds NodeConfig.MaxRequestSize, the request is rejected and SubmitRequest returns a RequestTooLargeError.
+// If the Node is overloaded (see NodeConfig.MaxPendingEvents), SubmitRequest blocks
+// or returns a BackpressureError (if NodeConfig.RejectOnBackpressure is set).
+// After Stop has been called, SubmitRequest returns ErrStopping.
 // SubmitRequest is safe to be called concurrently by multiple threads.
 func (n *Node) SubmitRequest(
 	ctx context.Context,
@@ -151,12 +441,36 @@ func (n *Node) SubmitRequest(
 	data []byte,
 	authenticator []byte) error {
 
+	// Reject requests exceeding the maximal request size.
+	// Their rejection is recorded by the processing thread (unless too many rejections are already pending).
+	if err := n.checkRequestSize(clientID, reqNo, data); err != nil {
+		select {
+		case n.rejectedRequests <- err:
+		default:
+		}
+		return err
+	}
+
+	// Reject the request if the Node is overloaded and configured to reject input in such a case.
+	if err := n.checkBackpressure(); err != nil {
+		return err
+	}
+
+	// Reject the request if the Node is stopping (see Stop).
+	select {
+	case <-n.stopC:
+		return ErrStopping
+	default:
+	}
+
 	// Enqueue the generated events in a work channel to be handled by the processing thread.
 	select {
-	case n.workChans.workItemInput <- (&events.EventList{}).PushBack(
+	case n.externalInput <- (&events.EventList{}).PushBack(
 		events.ClientRequest(clientID, reqNo, data, authenticator),
 	):
 		return nil
+	case <-n.stopC:
+		return ErrStopping
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-n.workErrNotifier.ExitC():
@@ -170,10 +484,32 @@ func (n *Node) SubmitRequest(
 // Then it adds an Init event to the work items, giving the modules the possibility
 // to perform additional initialization based on the state recovered from the WAL.
 // Run then launches the processing of incoming messages, time ticks, and internal events.
-// The node stops when exitC is closed.
-// Logical time ticks need to be written to tickC by the calling code.
+// The node stops when exitC is closed or when it is stopped gracefully using Stop.
+// Logical time ticks need to be written to tickC by the calling code,
+// unless the Node generates them itself (see NodeConfig.TickInterval), in which case tickC must be nil.
 // The function call is blocking and only returns when the node stops.
-func (n *Node) Run(exitC <-chan struct{}, tickC <-chan time.Time) error {
+func (n *Node) Run(exitC <-chan struct{}, tickC <-chan time.Time) (err error) {
+
+	// Let Stop know that the Node is running and when it stopped.
+	atomic.StoreInt32(&n.running, 1)
+	defer func() {
+		n.runErr = err
+		close(n.runDoneC)
+	}()
+
+	// Generate the ticks internally if configured.
+	// The ticker is stopped when Run returns, after all the Node's goroutines exited.
+	if n.Config.TickInterval > 0 {
+		if tickC != nil {
+			err := fmt.Errorf("tick channel must be nil if NodeConfig.TickInterval is set (%v)", n.Config.TickInterval)
+			n.workErrNotifier.Fail(err)
+			n.workErrNotifier.SetExitStatus(nil, fmt.Errorf("node not started"))
+			return err
+		}
+		ticker := n.clock().NewTicker(n.Config.TickInterval)
+		defer ticker.Stop()
+		tickC = ticker.C()
+	}
 
 	// Load the contents of the WAL and enqueue it for processing.
 	if err := n.processWAL(); err != nil {
@@ -190,20 +526,68 @@ func (n *Node) Run(exitC <-chan struct{}, tickC <-chan time.Time) error {
 	}
 
 	// Start processing of events.
-	return n.process(exitC, tickC)
+	err = n.process(exitC, tickC)
+
+	// If the node has been stopped gracefully, make sure all its durable state is persisted.
+	if err == ErrStopped {
+		if syncErr := n.syncDurableState(); syncErr != nil {
+			return syncErr
+		}
+	}
+
+	// If the node has been stopped gracefully, leave behind a clean stop marker for a subsequent restart.
+	if err == ErrStopped && n.walTail != nil {
+		if markerErr := n.writeCleanStopMarker(); markerErr != nil {
+			return fmt.Errorf("could not write clean stop marker: %w", markerErr)
+		}
+	}
+
+	return err
+}
+
+// writeCleanStopMarker writes a clean stop marker identifying the tail of the WAL (see NodeConfig.CleanStopFile).
+// If the tail of the WAL is not known (see walTailTracker), it deletes any existing marker instead,
+// such that the next restart checks the persisted state.
+// It must only be called after the node stopped gracefully and synced its durable state.
+func (n *Node) writeCleanStopMarker() error {
+	marker, err := n.walTail.marker()
+	if err != nil {
+		return err
+	}
+	if marker == nil {
+		n.logger.Log(logging.LevelWarn, "WAL tail unknown. Not writing clean stop marker.")
+		return removeCleanStopMarker(n.Config.CleanStopFile)
+	}
+	return writeCleanStopMarker(marker, n.Config.CleanStopFile)
 }
 
 // Loads all events stored in the WAL and enqueues them in the node's processing queues.
+// If the WAL has already been loaded (and checked) by RestartNode, the loaded entries are used instead.
+// Returns an error if the WAL contains a stable checkpoint that cannot be recovered.
 func (n *Node) processWAL() error {
 
-	// Create empty EventList to hold all the WAL events.
-	walEvents := &events.EventList{}
+	// Load the WAL, unless already loaded on restart.
+	walEntries := n.recoveredWAL
+	n.recoveredWAL = nil
+	if walEntries == nil {
+		var err error
+		if walEntries, err = loadWAL(n.modules.WAL); err != nil {
+			return err
+		}
+		if err := checkRecoveredCheckpoints(walEntries); err != nil {
+			return fmt.Errorf("inconsistent persisted state: %w", err)
+		}
+	}
+
+	// Start tracking the tail of the WAL, if needed for writing a clean stop marker.
+	if n.walTail != nil {
+		n.walTail.reset(walEntries)
+	}
 
-	// Add all events from the WAL to the new EventList.
-	if err := n.modules.WAL.LoadAll(func(retIdx t.WALRetIndex, event *eventpb.Event) {
-		walEvents.PushBack(events.WALEntry(event, retIdx))
-	}); err != nil {
-		return fmt.Errorf("could not load WAL events: %w", err)
+	// Add all events from the WAL to a new EventList.
+	walEvents := &events.EventList{}
+	for _, entry := range walEntries {
+		walEvents.PushBack(events.WALEntry(entry.Event, t.WALRetIndex(entry.RetentionIndex)))
 	}
 
 	// Enqueue all events to the workItems buffers.
@@ -221,6 +605,18 @@ func (n *Node) processWAL() error {
 // Logical time ticks need to be written to tickC by the calling code.
 func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 
+	// Start the Processors and stop them only after all the worker functions have returned (the defer below
+	// is executed after the deferred wg.Wait()), such that Process is never invoked on a stopped Processor.
+	if err := n.processors.startAll(); err != nil {
+		n.workErrNotifier.Fail(err)
+		return err
+	}
+	defer n.processors.stopAll()
+
+	// Close the channels returned by Events() and Committed() when the Node stops.
+	defer n.closeSubscriptions()
+	defer n.closeCommittedFeeds()
+
 	var wg sync.WaitGroup // Synchronizes all the worker functions
 	defer wg.Wait()       // Watch out! If process() terminates unexpectedly (e.g. by panicking), this might get stuck!
 
@@ -233,6 +629,7 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 	//       (currently repeated inside each workFunc) outside of the workFunc.
 	for _, work := range []workFunc{
 		n.doWALWork,
+		n.doWALSyncWork,
 		n.doClientWork,
 		n.doHashWork, // TODO (Jason), spawn more of these
 		n.doSendingWork,
@@ -245,7 +642,7 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 		// The wg is waited on before n.process() returns.
 		wg.Add(1)
 		go func(work workFunc) {
-			wg.Done()
+			defer wg.Done()
 			n.doUntilErr(work)
 		}(work)
 	}
@@ -270,6 +667,34 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 		protocolEvents chan<- *events.EventList
 	)
 
+	// These variables hold the channels from which external input (messages received over the network
+	// and input submitted through SubmitRequest and Step) is read.
+	// When the number of pending events reaches NodeConfig.MaxPendingEvents, they are set to nil,
+	// such that the external input is not read (and its producers block) until the pending events are processed.
+	var (
+		netReceive    = n.modules.Net.ReceiveChan()
+		externalInput = n.externalInput
+	)
+
+	// Set when exitC is closed and the Node drains the in-flight work before stopping (see NodeConfig.DrainOnStop)
+	// or when the Node is stopped gracefully using Stop (stopC is closed).
+	// While draining, no new input (including ticks) is accepted.
+	// If NodeConfig.DrainTimeout is positive, drainTimeoutC fires when the draining takes too long.
+	var (
+		stopC         = n.stopC
+		draining      bool
+		drainTimeoutC <-chan time.Time
+	)
+	startDraining := func() {
+		draining = true
+		exitC = nil
+		stopC = nil
+		tickC = nil
+		if n.Config.DrainTimeout > 0 {
+			drainTimeoutC = n.clock().After(n.Config.DrainTimeout)
+		}
+	}
+
 	// This loop shovels events between the appropriate channels, until a stopping condition is satisfied.
 	for {
 
@@ -283,34 +708,69 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 
 		case protocolEvents <- n.workItems.Protocol():
 			n.interceptEvents(n.workItems.ClearProtocol())
+			atomic.AddInt64(&n.inFlight, 1)
 			protocolEvents = nil
 		case walEvents <- n.workItems.WAL():
 			n.interceptEvents(n.workItems.ClearWAL())
+			atomic.AddInt64(&n.inFlight, 1)
 			walEvents = nil
 		case clientEvents <- n.workItems.Client():
 			n.interceptEvents(n.workItems.ClearClient())
+			atomic.AddInt64(&n.inFlight, 1)
 			clientEvents = nil
 		case hashEvents <- n.workItems.Hash():
 			n.interceptEvents(n.workItems.ClearHash())
+			atomic.AddInt64(&n.inFlight, 1)
 			hashEvents = nil
 		case cryptoEvents <- n.workItems.Crypto():
 			n.interceptEvents(n.workItems.ClearCrypto())
+			atomic.AddInt64(&n.inFlight, 1)
 			cryptoEvents = nil
 		case netEvents <- n.workItems.Net():
 			n.interceptEvents(n.workItems.ClearNet())
+			atomic.AddInt64(&n.inFlight, 1)
 			netEvents = nil
 		case appEvents <- n.workItems.App():
+			n.publishCommitted(n.workItems.App())
+			n.recordCommitted(n.workItems.App())
+			n.healthCommitted(n.workItems.App())
 			n.interceptEvents(n.workItems.ClearApp())
+			n.releaseReads()
+			atomic.AddInt64(&n.inFlight, 1)
 			appEvents = nil
 		case reqStoreEvents <- n.workItems.ReqStore():
 			n.interceptEvents(n.workItems.ClearReqStore())
+			atomic.AddInt64(&n.inFlight, 1)
 			reqStoreEvents = nil
 
 		// Handle messages received over the network, as obtained by the Net module.
 
-		case receivedMessage := <-n.modules.Net.ReceiveChan():
-			if err := n.workItems.AddEvents((&events.EventList{}).
-				PushBack(events.MessageReceived(receivedMessage.Sender, receivedMessage.Msg))); err != nil {
+		case receivedMessage := <-netReceive:
+			n.peerTraffic.messageReceived(receivedMessage.Sender, receivedMessage.Msg)
+
+			// Messages exceeding the maximal message size are dropped as a whole.
+			if tooLarge := n.checkMessageSize(receivedMessage.Sender, receivedMessage.Msg); tooLarge != nil {
+				n.recordMessageTooLarge(tooLarge)
+				break
+			}
+
+			// Invalid messages are dropped as a whole.
+			if invalid := n.validateMessage(receivedMessage.Sender, receivedMessage.Msg); invalid != nil {
+				n.recordInvalidMessage(invalid)
+				break
+			}
+
+			// Forwarded requests exceeding the maximal request size are dropped.
+			if err := n.workItems.AddEvents(n.dropOversizeForwardedRequests(
+				n.messageReceivedEvents(receivedMessage.Sender, receivedMessage.Msg))); err != nil {
+				n.workErrNotifier.Fail(err)
+			}
+
+		// Handle input submitted through SubmitRequest and Step.
+
+		case input := <-externalInput:
+			// Forwarded requests exceeding the maximal request size are dropped.
+			if err := n.workItems.AddEvents(n.dropOversizeForwardedRequests(input)); err != nil {
 				n.workErrNotifier.Fail(err)
 			}
 
@@ -318,9 +778,25 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 
 		case clientOut := <-n.workChans.workItemInput:
 			if err := n.workItems.AddEvents(clientOut); err != nil {
+				// If the application failed to apply a batch, record the poisoned batch event before halting.
+				if _, ok := err.(*PoisonedBatchError); ok {
+					n.interceptEvents(clientOut)
+				}
 				n.workErrNotifier.Fail(err)
 			}
+		case rejected := <-n.rejectedRequests:
+			n.recordRequestTooLarge(rejected, nil)
+		case overflow := <-n.sendOverflows:
+			n.interceptEvents((&events.EventList{}).PushBack(overflow))
+		case statusC := <-n.statusC:
+			statusC <- &statuspb.NodeStatus{
+				WorkItems:        n.workItems.Status(),
+				Oddities:         n.oddities.status(),
+				PeerTraffic:      n.peerTraffic.status(),
+				PersistLatencies: n.persistLatencies.status(),
+			}
 		case <-tickC:
+			n.healthTick()
 			if err := n.workItems.AddEvents((&events.EventList{}).PushBack(events.Tick())); err != nil {
 				n.workErrNotifier.Fail(err)
 			}
@@ -330,7 +806,37 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 		case <-n.workErrNotifier.ExitC():
 			return n.workErrNotifier.Err()
 		case <-exitC:
+			if n.Config.DrainOnStop {
+				startDraining()
+			} else {
+				n.workErrNotifier.Fail(ErrStopped)
+			}
+		case <-stopC:
+			startDraining()
+		case <-drainTimeoutC:
+			n.logger.Log(logging.LevelWarn, "Timed out draining in-flight work. Abandoning it.",
+				"pending", n.workItems.Len(), "inFlight", atomic.LoadInt64(&n.inFlight))
 			n.workErrNotifier.Fail(ErrStopped)
+		case <-n.idleC:
+			// Nothing to do. Only wakes up the loop to check whether draining finished (below).
+		}
+
+		// Publish the notifications produced by the modules (not processed by any module).
+
+		if n.workItems.Notifications().Len() > 0 {
+			notifications := n.workItems.ClearNotifications()
+			n.recordStableCheckpoints(notifications)
+			n.healthNotified(notifications)
+			n.updateEpochInfo(notifications)
+			n.notifyCheckpointWaiters(notifications)
+			n.odditiesNotified(notifications)
+			n.publishNotifications(notifications)
+		}
+
+		// Serve the linearizable reads (not processed by any module).
+
+		if n.workItems.Reads().Len() > 0 {
+			n.handleReads(n.workItems.ClearReads())
 		}
 
 		// If any events have been added to the work items,
@@ -360,6 +866,24 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 		if reqStoreEvents == nil && n.workItems.ReqStore().Len() > 0 {
 			reqStoreEvents = n.workChans.reqStore
 		}
+
+		// Stop reading external input while the Node is overloaded and resume when it is not any more.
+
+		pending := n.workItems.Len()
+		atomic.StoreInt64(&n.pendingEvents, int64(pending))
+		if draining || n.overloaded(pending) {
+			netReceive = nil
+			externalInput = nil
+		} else {
+			netReceive = n.modules.Net.ReceiveChan()
+			externalInput = n.externalInput
+		}
+
+		// When draining, stop as soon as all the in-flight work has been processed.
+
+		if draining && pending == 0 && atomic.LoadInt64(&n.inFlight) == 0 {
+			n.workErrNotifier.Fail(ErrStopped)
+		}
 	}
 }
 
@@ -374,3 +898,8 @@ func (n *Node) interceptEvents(events *events.EventList) {
 		}
 	}
 }
+
+// clock returns the Clock configured in NodeConfig.Clock or, if none is configured, the system clock.
+func (n *Node) clock() clock.Clock {
+	return clock.OrSystem(n.Config.Clock)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The internal ticks test runs a deployment of nodes that generate their logical time ticks themselves
+// and checks that the nodes make progress (which requires ticks) and stop normally.
+var _ = Describe("Internal ticks test", func() {
+
+	It("drives the nodes using internally generated ticks", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.TickInterval = deploytest.TestTickInterval
+		}
+
+		finalStatuses := deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, status := range finalStatuses {
+			Expect(status.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+
+	It("generates the ticks using the configured clock", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		// With the system clock, the nodes would not see a single tick during the test.
+		mockClock := clock.NewMock(time.Unix(0, 0))
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.TickInterval = time.Minute
+			replica.Config.Clock = mockClock
+		}
+
+		// Make a virtual minute pass whenever the condition is polled.
+		finalStatuses := deployment.RunUntil(func() bool {
+			mockClock.Advance(time.Minute)
+			return deployment.AllProcessed(testConfig.NumFakeRequests)()
+		})
+
+		for _, status := range finalStatuses {
+			Expect(status.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+)
+
+// Number of notifications that can wait to be read by each subscriber of the Node's events.
+const notificationsBufferSize = 256
+
+// subscribers keeps track of the channels returned by Node.Events().
+// The zero value is ready to use.
+type subscribers struct {
+
+	// The channels of all subscribers. Protected by lock.
+	chans []chan *eventpb.Notification
+
+	// Set when the Node stopped and all channels have been closed. Protected by lock.
+	closed bool
+
+	lock sync.Mutex
+}
+
+// Events returns a channel to which the Node writes notifications about notable changes of the protocol state,
+// such as the start of an epoch, a new stable checkpoint, the advancement of a client's window,
+// or the suspicion of a node (see eventpb.Notification),
+// allowing the application or the operator to react to them without polling the Node's status.
+// Each call to Events returns a new channel receiving all notifications produced after the call, in order.
+// The Node never blocks on the channel: if the subscriber does not keep up with reading the notifications
+// and the channel's buffer is full, further notifications are dropped for this subscriber.
+// The channel is closed when the Node stops. If the Node already stopped, the returned channel is closed.
+// The notifications are also visible to the event Interceptor (if any).
+func (n *Node) Events() <-chan *eventpb.Notification {
+	n.subscribers.lock.Lock()
+	defer n.subscribers.lock.Unlock()
+
+	c := make(chan *eventpb.Notification, notificationsBufferSize)
+	if n.subscribers.closed {
+		close(c)
+	} else {
+		n.subscribers.chans = append(n.subscribers.chans, c)
+	}
+	return c
+}
+
+// publishNotifications records the notifications contained in the given Notification events
+// with the event Interceptor and writes them to the channels of all subscribers.
+// It must only be called from the process() goroutine.
+func (n *Node) publishNotifications(notifications *events.EventList) {
+	n.interceptEvents(notifications)
+
+	n.subscribers.lock.Lock()
+	defer n.subscribers.lock.Unlock()
+
+	iter := notifications.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		notification := event.Type.(*eventpb.Event_Notification).Notification
+		for _, c := range n.subscribers.chans {
+			select {
+			case c <- notification:
+			default:
+				n.logger.Log(logging.LevelDebug, "Subscriber not keeping up. Dropping notification.",
+					"type", notification.Type)
+			}
+		}
+	}
+}
+
+// closeSubscriptions closes the channels of all subscribers of the Node's events.
+// It must be called when the process() goroutine exits.
+func (n *Node) closeSubscriptions() {
+	n.subscribers.lock.Lock()
+	defer n.subscribers.lock.Unlock()
+
+	for _, c := range n.subscribers.chans {
+		close(c)
+	}
+	n.subscribers.chans = nil
+	n.subscribers.closed = true
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+)
+
+// The event subscription test subscribes to the events of all nodes
+// and checks that each node notifies about epochs, stable checkpoints, and moved client windows.
+var _ = Describe("Event subscription test", func() {
+
+	It("notifies about the progress of the protocol", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Collect the notifications of each node until the node stops (and closes the channel).
+		notifications := make([][]*eventpb.Notification, len(deployment.TestReplicas))
+		var wg sync.WaitGroup
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				notificationC := node.Events()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for notification := range notificationC {
+						notifications[i] = append(notifications[i], notification)
+					}
+				}()
+			}
+		}
+
+		deployment.RunUntil(deploytest.AllOf(
+			deployment.AllProcessed(testConfig.NumFakeRequests),
+			deployment.AllInEpoch(1),
+			deployment.AllCheckpointed(),
+			deployment.AllWatermarksMoved(),
+		))
+		wg.Wait()
+
+		for i, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+			maxEpoch := uint64(0)
+			checkpoints, windowsMoved := 0, 0
+			for _, notification := range notifications[i] {
+				switch n := notification.Type.(type) {
+				case *eventpb.Notification_EpochStarted:
+					Expect(n.EpochStarted.Epoch).To(BeNumerically(">=", maxEpoch))
+					maxEpoch = n.EpochStarted.Epoch
+				case *eventpb.Notification_CheckpointStable:
+					checkpoints++
+				case *eventpb.Notification_ClientWindowMoved:
+					windowsMoved++
+				}
+			}
+			Expect(maxEpoch).To(BeNumerically(">", 0))
+			Expect(checkpoints).To(BeNumerically(">", 0))
+			Expect(windowsMoved).To(BeNumerically(">", 0))
+		}
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Kinds of oddities, i.e., of suspicious behavior of other nodes the Node observes.
+// None of them necessarily means that the other node is malicious (it might, e.g., be running a different version),
+// but frequent oddities of a node indicate a misbehaving or buggy peer,
+// possibly before it causes an epoch change.
+const (
+	// The node sent a message that failed validation (see InvalidMessageError).
+	OddityInvalidMessage = "invalid_message"
+
+	// The node sent a message exceeding NodeConfig.MaxMessageSize.
+	OddityMessageTooLarge = "message_too_large"
+
+	// The node forwarded a request exceeding NodeConfig.MaxRequestSize.
+	OddityRequestTooLarge = "request_too_large"
+
+	// The protocol detected the node sending conflicting messages (see eventpb.Equivocation).
+	OddityEquivocation = "equivocation"
+)
+
+// Number of the most recent oddities retained for each node, to be reported as examples in the Node status.
+const oddityHistorySize = 10
+
+// oddities keeps track of the oddities observed of each node.
+// The zero value is ready to use.
+type oddities struct {
+
+	// For each node any oddity has been observed of, the number of oddities by kind and the most recent ones.
+	// Protected by lock.
+	nodes map[t.NodeID]*statuspb.NodeOddities
+
+	lock sync.Mutex
+}
+
+// record registers an oddity of the given kind observed of node nodeID at time now
+// and returns the corresponding Oddity event.
+func (o *oddities) record(nodeID t.NodeID, kind string, description string, now time.Time) *eventpb.Event {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.nodes == nil {
+		o.nodes = make(map[t.NodeID]*statuspb.NodeOddities)
+	}
+	nodeOddities, ok := o.nodes[nodeID]
+	if !ok {
+		nodeOddities = &statuspb.NodeOddities{
+			NodeId: nodeID.Pb(),
+			Counts: make(map[string]uint64),
+		}
+		o.nodes[nodeID] = nodeOddities
+	}
+
+	event := events.Oddity(nodeID, kind, description, now.UnixNano()/int64(time.Millisecond))
+	nodeOddities.Counts[kind]++
+	nodeOddities.Recent = append(nodeOddities.Recent, event.Type.(*eventpb.Event_Notification).Notification.GetOddity())
+	if len(nodeOddities.Recent) > oddityHistorySize {
+		nodeOddities.Recent = nodeOddities.Recent[len(nodeOddities.Recent)-oddityHistorySize:]
+	}
+
+	return event
+}
+
+// status returns a copy of the oddities observed of all nodes, ordered by node ID.
+func (o *oddities) status() []*statuspb.NodeOddities {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	status := make([]*statuspb.NodeOddities, 0, len(o.nodes))
+	for _, nodeOddities := range o.nodes {
+		status = append(status, proto.Clone(nodeOddities).(*statuspb.NodeOddities))
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].NodeId < status[j].NodeId })
+	return status
+}
+
+// Oddities returns the suspicious behavior of other nodes observed by the Node since its start:
+// for each node, the number of oddities by kind (see OddityInvalidMessage etc.) and the most recent ones.
+// The same information is part of the Node status, and each oddity is also announced as an eventpb.Oddity
+// notification to the subscribers of the Node's events (see Events).
+// Oddities is safe to be called concurrently.
+func (n *Node) Oddities() []*statuspb.NodeOddities {
+	return n.oddities.status()
+}
+
+// recordOddity registers an oddity of node nodeID and announces it to the subscribers of the Node's events.
+// It must only be called from the process() goroutine.
+func (n *Node) recordOddity(nodeID t.NodeID, kind string, format string, args ...interface{}) {
+	event := n.oddities.record(nodeID, kind, fmt.Sprintf(format, args...), n.clock().Now())
+	if err := n.workItems.AddEvents((&events.EventList{}).PushBack(event)); err != nil {
+		n.workErrNotifier.Fail(err)
+	}
+}
+
+// odditiesNotified registers the equivocations announced by the given Notification events as oddities.
+// It must only be called from the process() goroutine.
+func (n *Node) odditiesNotified(notifications *events.EventList) {
+	iter := notifications.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		notification := event.Type.(*eventpb.Event_Notification).Notification
+		if equivocation, ok := notification.Type.(*eventpb.Notification_Equivocation); ok {
+			e := equivocation.Equivocation
+			n.recordOddity(t.NodeID(e.NodeId), OddityEquivocation,
+				"conflicting %s messages for sequence number %d in epoch %d", e.MessageType, e.Sn, e.Epoch)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Oddities test", func() {
+
+	It("reports the suspicious behavior of a buggy node", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The last replica sends an additional malformed message with every 10th message.
+		buggy := deployment.TestReplicas[len(deployment.TestReplicas)-1]
+		buggy.Net = &garblingNet{Net: buggy.Net}
+
+		// Count the oddity notifications of each node.
+		notified := make([]int64, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				notifications := node.Events()
+				go func() {
+					for notification := range notifications {
+						if oddity := notification.GetOddity(); oddity != nil && t.NodeID(oddity.NodeId) == buggy.Id {
+							atomic.AddInt64(&notified[i], 1)
+						}
+					}
+				}()
+			}
+		}
+
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), func() bool {
+			for i, replica := range deployment.TestReplicas {
+				if replica != buggy && atomic.LoadInt64(&notified[i]) == 0 {
+					return false
+				}
+			}
+			return true
+		}))
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+			// Only the buggy node is reported, by all other nodes.
+			oddities := finalStatus.Status.Oddities
+			if deployment.TestReplicas[i] == buggy {
+				Expect(oddities).To(BeEmpty())
+				Expect(atomic.LoadInt64(&notified[i])).To(BeZero())
+				continue
+			}
+			Expect(oddities).To(HaveLen(1))
+			Expect(t.NodeID(oddities[0].NodeId)).To(Equal(buggy.Id))
+			count := oddities[0].Counts[mirbft.OddityInvalidMessage]
+			Expect(count).To(BeNumerically(">", 0))
+			Expect(len(oddities[0].Recent)).To(BeNumerically("<=", 10))
+			Expect(uint64(len(oddities[0].Recent))).To(BeNumerically("<=", count))
+			for _, oddity := range oddities[0].Recent {
+				Expect(oddity.Kind).To(Equal(mirbft.OddityInvalidMessage))
+				Expect(oddity.Description).To(ContainSubstring("message without content"))
+			}
+			Expect(atomic.LoadInt64(&notified[i])).To(BeNumerically(">", 0))
+		}
+	})
+})
+
+// garblingNet is a Net module wrapper that sends an additional empty (and thus malformed) message
+// with every 10th sent message.
+type garblingNet struct {
+	modules.Net
+	sent uint64
+}
+
+func (gn *garblingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if atomic.AddUint64(&gn.sent, 1)%10 == 0 {
+		if err := gn.Net.Send(dest, &messagepb.Message{}); err != nil {
+			return err
+		}
+	}
+	return gn.Net.Send(dest, msg)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// safeApplyBatchByClient applies a batch to a client-partitioned application,
+// applying the requests of each client in a separate goroutine (preserving the order of the client's requests).
+// It only returns when the requests of all clients have been applied.
+// Like safeApplyBatch, it recovers from panics of the application (in any of the goroutines)
+// and returns a PoisonedBatch event describing the first of them (in the order of the clients' first requests).
+// Otherwise, it returns nil and the first error returned by the application (in the same order), if any.
+func safeApplyBatchByClient(
+	app modules.ClientPartitionedApp,
+	sn t.SeqNr,
+	batch *requestpb.Batch,
+) (*eventpb.Event, error) {
+
+	// Partition the requests by client, keeping the clients in the order of their first request in the batch,
+	// such that the returned error does not depend on the scheduling of the goroutines.
+	clientIDs := make([]t.ClientID, 0)
+	partitions := make(map[t.ClientID][]*requestpb.RequestRef)
+	for _, reqRef := range batch.Requests {
+		clientID := t.ClientID(reqRef.ClientId)
+		if _, ok := partitions[clientID]; !ok {
+			clientIDs = append(clientIDs, clientID)
+		}
+		partitions[clientID] = append(partitions[clientID], reqRef)
+	}
+
+	// Apply the requests of each client concurrently, recording the outcome for each client.
+	errs := make([]error, len(clientIDs))
+	panics := make([]string, len(clientIDs))
+	var wg sync.WaitGroup
+	wg.Add(len(clientIDs))
+	for i, clientID := range clientIDs {
+		go func(i int, clientID t.ClientID) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics[i] = fmt.Sprintf("panic in application (client %d): %v\nStack trace:\n%s",
+						clientID, r, string(debug.Stack()))
+				}
+			}()
+			errs[i] = app.ApplyClientRequests(clientID, partitions[clientID])
+		}(i, clientID)
+	}
+	wg.Wait()
+
+	// Report the first panic, if any, as a poisoned batch.
+	for _, reason := range panics {
+		if reason != "" {
+			return events.PoisonedBatch(sn, batch, reason), nil
+		}
+	}
+
+	// Otherwise, return the first error, if any.
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("could not apply requests of client %d: %w", clientIDs[i], err)
+		}
+	}
+
+	return nil, nil
+}
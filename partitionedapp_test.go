@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The partitioned application test has the replicas apply the requests of different clients concurrently.
+var _ = Describe("Partitioned application test", func() {
+
+	It("applies the requests of multiple clients concurrently", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			NumClients:      1,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			NumNetRequests:  10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.ApplyByClient = true
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests + testConfig.NumNetRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests + testConfig.NumNetRequests))
+		}
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// peerTraffic counts the messages (and their sizes) sent to and received from each other node, by message type.
+// It helps diagnosing asymmetric load and chattering peers.
+// The zero value is ready to use. peerTraffic is safe for concurrent use,
+// as messages are sent by multiple goroutines (with send queues) and received through Node.Step.
+type peerTraffic struct {
+
+	// The traffic exchanged with each node. Protected by lock.
+	peers map[t.NodeID]*statuspb.PeerTraffic
+
+	lock sync.Mutex
+}
+
+// messageSent accounts for message msg having been sent to node dest.
+func (pt *peerTraffic) messageSent(dest t.NodeID, msg *messagepb.Message) {
+	pt.add(dest, msg, func(peer *statuspb.PeerTraffic) map[string]*statuspb.MessageCount { return peer.Sent })
+}
+
+// messageReceived accounts for message msg having been received from node source.
+func (pt *peerTraffic) messageReceived(source t.NodeID, msg *messagepb.Message) {
+	pt.add(source, msg, func(peer *statuspb.PeerTraffic) map[string]*statuspb.MessageCount { return peer.Received })
+}
+
+// add adds msg to the counts of node nodeID selected by direction.
+func (pt *peerTraffic) add(
+	nodeID t.NodeID,
+	msg *messagepb.Message,
+	direction func(*statuspb.PeerTraffic) map[string]*statuspb.MessageCount,
+) {
+	// Compute the size and type of the message outside the critical section.
+	size := uint64(proto.Size(msg))
+	msgType := msg.TypeName()
+
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+
+	if pt.peers == nil {
+		pt.peers = make(map[t.NodeID]*statuspb.PeerTraffic)
+	}
+	peer, ok := pt.peers[nodeID]
+	if !ok {
+		peer = &statuspb.PeerTraffic{
+			NodeId:   nodeID.Pb(),
+			Sent:     make(map[string]*statuspb.MessageCount),
+			Received: make(map[string]*statuspb.MessageCount),
+		}
+		pt.peers[nodeID] = peer
+	}
+
+	counts := direction(peer)
+	count, ok := counts[msgType]
+	if !ok {
+		count = &statuspb.MessageCount{}
+		counts[msgType] = count
+	}
+	count.Messages++
+	count.Bytes += size
+}
+
+// status returns a copy of the traffic exchanged with all nodes, ordered by node ID.
+func (pt *peerTraffic) status() []*statuspb.PeerTraffic {
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+
+	status := make([]*statuspb.PeerTraffic, 0, len(pt.peers))
+	for _, peer := range pt.peers {
+		status = append(status, proto.Clone(peer).(*statuspb.PeerTraffic))
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].NodeId < status[j].NodeId })
+	return status
+}
+
+// PeerTraffic returns the numbers of messages (and their sizes in bytes) the Node exchanged
+// with each other node since its start, by message type. The same information is part of the Node status.
+// Received messages are counted before being validated, i.e., including the ones the Node dropped.
+// Sent messages are counted once the Net module accepted them.
+// PeerTraffic is safe to be called concurrently.
+func (n *Node) PeerTraffic() []*statuspb.PeerTraffic {
+	return n.peerTraffic.status()
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Peer traffic test", func() {
+
+	It("counts the messages exchanged with each peer", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllInEpoch(2)))
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+			// Each node exchanged proposals and checkpoints with all other nodes.
+			peerTraffic := finalStatus.Status.PeerTraffic
+			Expect(peerTraffic).To(HaveLen(testConfig.NumReplicas - 1))
+			for _, peer := range peerTraffic {
+				Expect(t.NodeID(peer.NodeId)).NotTo(Equal(deployment.TestReplicas[i].Id))
+				for _, counts := range []map[string]*statuspb.MessageCount{peer.Sent, peer.Received} {
+					Expect(counts).To(HaveKey("pbft_preprepare"))
+					Expect(counts).To(HaveKey("checkpoint"))
+					for _, count := range counts {
+						Expect(count.Messages).To(BeNumerically(">", 0))
+						Expect(count.Bytes).To(BeNumerically(">=", count.Messages))
+					}
+				}
+			}
+		}
+	})
+})
@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+// Persistence operations whose latencies the Node measures (see Node.PersistLatencies).
+// Slow syncs are the most common hidden cause of consensus stalls,
+// as no message depending on persisted state can be sent before the state is synced.
+const (
+	// Appending a list of entries to the WAL.
+	PersistWALAppend = "wal_append"
+
+	// Syncing the WAL. A single sync can cover the entries of multiple appends.
+	PersistWALSync = "wal_sync"
+
+	// Syncing the RequestStore after storing (or pruning) a list of requests.
+	PersistReqStoreSync = "reqstore_sync"
+)
+
+// Number of the most recent invocations of each persistence operation the latency percentiles are computed over.
+const persistLatencyWindow = 1024
+
+// persistLatencies keeps track of the latencies of the persistence operations.
+// The zero value is ready to use. persistLatencies is safe for concurrent use,
+// as the WAL and the RequestStore are driven by different goroutines.
+type persistLatencies struct {
+
+	// The latencies of each operation. Protected by lock.
+	operations map[string]*latencyWindow
+
+	lock sync.Mutex
+}
+
+// latencyWindow holds the most recent latencies of an operation in a ring buffer.
+type latencyWindow struct {
+
+	// Total number of invocations of the operation.
+	count uint64
+
+	// The most recent (up to persistLatencyWindow) latencies, overwritten in a round-robin fashion.
+	samples []time.Duration
+}
+
+// observe records an invocation of operation that took d.
+func (pl *persistLatencies) observe(operation string, d time.Duration) {
+	pl.lock.Lock()
+	defer pl.lock.Unlock()
+
+	if pl.operations == nil {
+		pl.operations = make(map[string]*latencyWindow)
+	}
+	window, ok := pl.operations[operation]
+	if !ok {
+		window = &latencyWindow{samples: make([]time.Duration, 0, persistLatencyWindow)}
+		pl.operations[operation] = window
+	}
+
+	if len(window.samples) < persistLatencyWindow {
+		window.samples = append(window.samples, d)
+	} else {
+		window.samples[window.count%persistLatencyWindow] = d
+	}
+	window.count++
+}
+
+// status returns the latencies of all operations invoked so far, ordered by operation name.
+func (pl *persistLatencies) status() []*statuspb.PersistLatency {
+	pl.lock.Lock()
+	defer pl.lock.Unlock()
+
+	status := make([]*statuspb.PersistLatency, 0, len(pl.operations))
+	for operation, window := range pl.operations {
+		sorted := append([]time.Duration{}, window.samples...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		percentile := func(p int) uint64 {
+			return uint64(sorted[(len(sorted)-1)*p/100].Microseconds())
+		}
+		status = append(status, &statuspb.PersistLatency{
+			Operation: operation,
+			Count:     window.count,
+			P50Us:     percentile(50),
+			P90Us:     percentile(90),
+			P99Us:     percentile(99),
+			MaxUs:     percentile(100),
+		})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Operation < status[j].Operation })
+	return status
+}
+
+// PersistLatencies returns the latencies of the persistence operations (see PersistWALSync etc.)
+// performed by the Node since its start. The same information is part of the Node status.
+// Only the operations performed by the Node's default Processors and WAL stages are measured.
+// PersistLatencies is safe to be called concurrently.
+func (n *Node) PersistLatencies() []*statuspb.PersistLatency {
+	return n.persistLatencies.status()
+}
+
+// persisted records an invocation of the persistence operation operation that started at start.
+// If the operation took longer than NodeConfig.SlowPersistThreshold, persisted logs a warning
+// and adds a SlowPersist notification to eventsOut.
+func (n *Node) persisted(operation string, start time.Time, eventsOut *events.EventList) {
+	d := time.Since(start)
+	n.persistLatencies.observe(operation, d)
+
+	if threshold := n.Config.SlowPersistThreshold; threshold > 0 && d > threshold {
+		n.logger.Log(logging.LevelWarn, "Slow persistence operation.",
+			"operation", operation, "duration", d, "threshold", threshold)
+		eventsOut.PushBack(events.SlowPersist(operation, d, threshold))
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+var _ = Describe("Persistence latency test", func() {
+
+	It("measures the persistence latencies and warns about slow operations", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// With a threshold this small, practically every persistence operation is considered slow.
+		slowSyncs := make([]int64, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.Config.SlowPersistThreshold = time.Nanosecond
+			replica.OnNode = func(node *mirbft.Node) {
+				notifications := node.Events()
+				go func() {
+					for notification := range notifications {
+						slow := notification.GetSlowPersist()
+						if slow != nil && slow.Operation == mirbft.PersistWALSync && slow.DurationUs >= slow.ThresholdUs {
+							atomic.AddInt64(&slowSyncs[i], 1)
+						}
+					}
+				}()
+			}
+		}
+
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), func() bool {
+			for i := range deployment.TestReplicas {
+				if atomic.LoadInt64(&slowSyncs[i]) == 0 {
+					return false
+				}
+			}
+			return true
+		}))
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			Expect(atomic.LoadInt64(&slowSyncs[i])).To(BeNumerically(">", 0))
+
+			// All persistence operations have been measured, in the order of their names.
+			latencies := finalStatus.Status.PersistLatencies
+			Expect(latencies).To(HaveLen(3))
+			Expect(latencies[0].Operation).To(Equal(mirbft.PersistReqStoreSync))
+			Expect(latencies[1].Operation).To(Equal(mirbft.PersistWALAppend))
+			Expect(latencies[2].Operation).To(Equal(mirbft.PersistWALSync))
+			for _, latency := range latencies {
+				Expect(latency.Count).To(BeNumerically(">", 0))
+				Expect(latency.P50Us).To(BeNumerically("<=", latency.P90Us))
+				Expect(latency.P90Us).To(BeNumerically("<=", latency.P99Us))
+				Expect(latency.P99Us).To(BeNumerically("<=", latency.MaxUs))
+			}
+		}
+	})
+})
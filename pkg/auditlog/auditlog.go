@@ -0,0 +1,324 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package auditlog maintains an append-only log of the notable protocol events observed by a Node,
+// meant for compliance and for post-incident forensics.
+//
+// Unlike the WAL, which is truncated as checkpoints become stable and only serves the recovery of the Node,
+// the audit log is kept in a separate file that is never truncated by the Node.
+// It records the epoch transitions, the stable checkpoints, the detected equivocations, and the configuration changes,
+// each with the context in which the Node observed it (the current epoch and the number of delivered batches).
+// The records (auditpb.Record) are written either as size-prefixed protobuf messages or as JSON lines
+// (using the canonical JSON mapping of protobuf), the latter being easy to process with standard log tooling.
+//
+// A Log is attached to a Node as its event Interceptor (see modules.Interceptors for combining it with others).
+// Each record is synced to stable storage before the Node continues processing,
+// and an error writing the audit log makes the Node halt, such that no audited event goes unrecorded.
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/auditpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Format is the encoding of the records in an audit log.
+type Format int
+
+const (
+	// FormatProto encodes each record as a protobuf message, prefixed by its size (as a varint).
+	FormatProto Format = iota
+
+	// FormatJSON encodes each record as one line of JSON.
+	FormatJSON
+)
+
+// jsonMarshaler is used for writing records in FormatJSON.
+// It uses the field names as defined in the .proto files, such that the fields can be looked up there.
+var jsonMarshaler = jsonpb.Marshaler{
+	OrigName: true,
+}
+
+// jsonUnmarshaler is used for reading records in FormatJSON.
+// Unknown fields are ignored, such that a log produced by a newer version of the library can still be read.
+var jsonUnmarshaler = jsonpb.Unmarshaler{
+	AllowUnknownFields: true,
+}
+
+// Log is an audit log backed by a file (see package documentation).
+// It implements the modules.EventInterceptor interface.
+type Log struct {
+	nodeID t.NodeID
+	format Format
+	file   *os.File
+	writer *bufio.Writer
+
+	// Index to be assigned to the next record.
+	nextIndex uint64
+
+	// The context in which events are recorded, updated from the intercepted events.
+	epoch       t.EpochNr
+	deliveredSN t.SeqNr
+
+	// Source of the record timestamps (Unix time in milliseconds).
+	timeSource func() int64
+
+	// Protects all the fields of the Log.
+	lock sync.Mutex
+}
+
+// Open opens the audit log of node nodeID stored in the file at path, creating the file if it does not exist.
+// If the file already contains records (e.g., written before the node restarted), they must be in the given format.
+// The new records are appended, continuing the numbering and the context of the existing ones.
+// Open fails if the existing records cannot be read, in order not to append to a corrupted log.
+func Open(path string, nodeID t.NodeID, format Format) (*Log, error) {
+	l := &Log{
+		nodeID:     nodeID,
+		format:     format,
+		nextIndex:  1,
+		timeSource: func() int64 { return time.Now().UnixNano() / int64(time.Millisecond) },
+	}
+
+	// Continue where the existing records end.
+	records, err := ReadFile(path, format)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("could not read existing audit log: %w", err)
+	}
+	if len(records) > 0 {
+		last := records[len(records)-1]
+		l.nextIndex = last.Index + 1
+		l.epoch = t.EpochNr(last.Epoch)
+		l.deliveredSN = t.SeqNr(last.DeliveredSn)
+	}
+
+	if l.file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600); err != nil {
+		return nil, fmt.Errorf("could not open audit log file: %w", err)
+	}
+	l.writer = bufio.NewWriter(l.file)
+
+	return l, nil
+}
+
+// Intercept records the audited events among the given events.
+// It implements the modules.EventInterceptor interface.
+// Intercept returns after the records have been synced to stable storage, or with an error if writing them failed.
+func (l *Log) Intercept(eventList *events.EventList) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log closed")
+	}
+
+	written := false
+	iter := eventList.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		switch e := event.Type.(type) {
+		case *eventpb.Event_Deliver:
+			if sn := t.SeqNr(e.Deliver.Sn); sn+1 > l.deliveredSN {
+				l.deliveredSN = sn + 1
+			}
+		case *eventpb.Event_Notification:
+			if !audited(e.Notification) {
+				continue
+			}
+			if epochStarted, ok := e.Notification.Type.(*eventpb.Notification_EpochStarted); ok {
+				l.epoch = t.EpochNr(epochStarted.EpochStarted.Epoch)
+			}
+			if err := l.write(e.Notification); err != nil {
+				return err
+			}
+			written = true
+		}
+	}
+
+	if !written {
+		return nil
+	}
+	if err := l.writer.Flush(); err != nil {
+		return fmt.Errorf("could not write audit log: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("could not sync audit log: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the audit log file. It must only be called after the Node has stopped.
+func (l *Log) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	flushErr := l.writer.Flush()
+	closeErr := l.file.Close()
+	l.file = nil
+	if flushErr != nil {
+		return fmt.Errorf("could not write audit log: %w", flushErr)
+	}
+	return closeErr
+}
+
+// audited returns true if the notification is to be recorded in the audit log.
+func audited(notification *eventpb.Notification) bool {
+	switch notification.Type.(type) {
+	case *eventpb.Notification_EpochStarted,
+		*eventpb.Notification_CheckpointStable,
+		*eventpb.Notification_Equivocation,
+		*eventpb.Notification_ConfigChanged:
+		return true
+	default:
+		return false
+	}
+}
+
+// write appends a record of the given notification to the buffered writer of the log.
+func (l *Log) write(notification *eventpb.Notification) error {
+
+	// Omit the application snapshot from recorded checkpoints.
+	// It can be large, may contain sensitive application data, and is not necessary for auditing.
+	if checkpoint, ok := notification.Type.(*eventpb.Notification_CheckpointStable); ok {
+		notification = &eventpb.Notification{Type: &eventpb.Notification_CheckpointStable{
+			CheckpointStable: &eventpb.CheckpointStable{
+				Epoch: checkpoint.CheckpointStable.Epoch,
+				Sn:    checkpoint.CheckpointStable.Sn,
+			},
+		}}
+	}
+
+	record := &auditpb.Record{
+		Index:       l.nextIndex,
+		NodeId:      l.nodeID.Pb(),
+		Timestamp:   l.timeSource(),
+		Epoch:       l.epoch.Pb(),
+		DeliveredSn: l.deliveredSN.Pb(),
+		Event:       notification,
+	}
+	if err := writeRecord(l.writer, record, l.format); err != nil {
+		return fmt.Errorf("could not write audit record %d: %w", record.Index, err)
+	}
+	l.nextIndex++
+	return nil
+}
+
+// writeRecord writes a single record to dest in the given format.
+func writeRecord(dest io.Writer, record *auditpb.Record, format Format) error {
+	switch format {
+	case FormatProto:
+		data, err := proto.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("could not marshal record: %w", err)
+		}
+		lenBuf := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(lenBuf, uint64(len(data)))
+		if _, err := dest.Write(lenBuf[:n]); err != nil {
+			return err
+		}
+		_, err = dest.Write(data)
+		return err
+	case FormatJSON:
+		data, err := jsonMarshaler.MarshalToString(record)
+		if err != nil {
+			return fmt.Errorf("could not marshal record to JSON: %w", err)
+		}
+		_, err = io.WriteString(dest, data+"\n")
+		return err
+	default:
+		return fmt.Errorf("unknown audit log format: %d", format)
+	}
+}
+
+// Reader reads the records of an audit log.
+type Reader struct {
+	source *bufio.Reader
+	format Format
+}
+
+// NewReader returns a new Reader reading the records in the given format from source.
+func NewReader(source io.Reader, format Format) *Reader {
+	return &Reader{
+		source: bufio.NewReader(source),
+		format: format,
+	}
+}
+
+// ReadRecord returns the next record of the audit log, or io.EOF if there are no more records.
+// A record truncated by the end of the log results in io.ErrUnexpectedEOF.
+func (r *Reader) ReadRecord() (*auditpb.Record, error) {
+	record := &auditpb.Record{}
+
+	switch r.format {
+	case FormatProto:
+		size, err := binary.ReadUvarint(r.source)
+		if err == io.EOF {
+			return nil, io.EOF
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read record size: %w", err)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r.source, data); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("could not read record: %w", err)
+		}
+		if err := proto.Unmarshal(data, record); err != nil {
+			return nil, fmt.Errorf("could not unmarshal record: %w", err)
+		}
+	case FormatJSON:
+		line, err := r.source.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			return nil, io.EOF
+		} else if err == io.EOF {
+			return nil, fmt.Errorf("could not read record: %w", io.ErrUnexpectedEOF)
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read record: %w", err)
+		}
+		if err := jsonUnmarshaler.Unmarshal(bytes.NewReader(line), record); err != nil {
+			return nil, fmt.Errorf("could not unmarshal record from JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown audit log format: %d", r.format)
+	}
+
+	return record, nil
+}
+
+// ReadFile returns all the records of the audit log stored in the file at path in the given format.
+func ReadFile(path string, format Format) ([]*auditpb.Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records := make([]*auditpb.Record, 0)
+	reader := NewReader(file, format)
+	for {
+		record, err := reader.ReadRecord()
+		if err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read record %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
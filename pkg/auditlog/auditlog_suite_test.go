@@ -0,0 +1,13 @@
+package auditlog_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAuditlog(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auditlog Suite")
+}
@@ -0,0 +1,233 @@
+package auditlog_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/auditlog"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// eventList returns a list of the given events.
+func eventList(evts ...*eventpb.Event) *events.EventList {
+	list := &events.EventList{}
+	for _, event := range evts {
+		list.PushBack(event)
+	}
+	return list
+}
+
+var _ = Describe("Log", func() {
+	var (
+		tmpDir string
+		path   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "auditlog-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(tmpDir, "audit.log")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	for name, format := range map[string]auditlog.Format{"proto": auditlog.FormatProto, "JSON": auditlog.FormatJSON} {
+		format := format
+
+		Context("in the "+name+" format", func() {
+
+			It("records the audited events with their context", func() {
+				log, err := auditlog.Open(path, 2, format)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(log.Intercept(eventList(
+					events.EpochStarted(1, nil, nil),
+					events.Deliver(4, &requestpb.Batch{}),
+					events.ClientWindowMoved(0, 1),
+					events.CheckpointStable(1, 5, []byte("snapshot")),
+					events.Tick(),
+					events.Equivocation(3, 1, 4, "pbft_preprepare"),
+				))).To(Succeed())
+				Expect(log.Close()).To(Succeed())
+
+				records, err := auditlog.ReadFile(path, format)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records).To(HaveLen(3))
+				for i, record := range records {
+					Expect(record.Index).To(Equal(uint64(i + 1)))
+					Expect(record.NodeId).To(Equal(uint64(2)))
+					Expect(record.Epoch).To(Equal(uint64(1)))
+					Expect(record.Timestamp).To(BeNumerically(">", 0))
+				}
+				Expect(records[0].Event.GetEpochStarted().GetEpoch()).To(Equal(uint64(1)))
+				Expect(records[0].DeliveredSn).To(BeZero())
+				Expect(records[1].DeliveredSn).To(Equal(uint64(5)))
+				Expect(records[2].Event.GetEquivocation().GetNodeId()).To(Equal(uint64(3)))
+
+				// The application snapshot is not recorded.
+				Expect(records[1].Event.GetCheckpointStable().GetSn()).To(Equal(uint64(5)))
+				Expect(records[1].Event.GetCheckpointStable().GetAppSnapshot()).To(BeEmpty())
+			})
+
+			It("continues the existing records when reopened", func() {
+				log, err := auditlog.Open(path, 0, format)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(log.Intercept(eventList(events.EpochStarted(3, nil, nil), events.Deliver(9, &requestpb.Batch{})))).
+					To(Succeed())
+				Expect(log.Close()).To(Succeed())
+
+				log, err = auditlog.Open(path, 0, format)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(log.Intercept(eventList(events.CheckpointStable(3, 10, nil)))).To(Succeed())
+				Expect(log.Close()).To(Succeed())
+
+				records, err := auditlog.ReadFile(path, format)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records).To(HaveLen(2))
+				Expect(records[1].Index).To(Equal(uint64(2)))
+				Expect(records[1].Epoch).To(Equal(uint64(3)))
+				Expect(records[1].DeliveredSn).To(BeZero())
+			})
+
+			It("refuses to append to a truncated log", func() {
+				log, err := auditlog.Open(path, 0, format)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(log.Intercept(eventList(events.EpochStarted(1, nil, nil)))).To(Succeed())
+				Expect(log.Close()).To(Succeed())
+
+				data, err := ioutil.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(path, data[:len(data)-2], 0600)).To(Succeed())
+
+				_, err = auditlog.ReadFile(path, format)
+				Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue())
+				_, err = auditlog.Open(path, 0, format)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	}
+
+	It("fails recording events after it is closed", func() {
+		log, err := auditlog.Open(path, 0, auditlog.FormatProto)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log.Close()).To(Succeed())
+		Expect(log.Intercept(eventList(events.EpochStarted(1, nil, nil)))).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Audit log test", func() {
+
+	It("records epoch transitions, checkpoints, and configuration changes", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All nodes accept requests from the configuration client
+		// and keep an audit log, half of them in JSON and half of them in protobuf format.
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		formats := make([]auditlog.Format, len(deployment.TestReplicas))
+		paths := make([]string, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+
+			formats[i] = auditlog.Format(i % 2)
+			paths[i] = filepath.Join(testConfig.Directory, fmt.Sprintf("audit-%d.log", i))
+			auditLog, err := auditlog.Open(paths[i], replica.Id, formats[i])
+			Expect(err).NotTo(HaveOccurred())
+			defer func() { Expect(auditLog.Close()).To(Succeed()) }()
+			replica.Interceptor = auditLog
+		}
+
+		// Change the number of buckets after the network started.
+		request := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			NumBuckets: 8,
+		})
+		var submitErrs []error
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			submitErrs = deploytest.SubmitToAll(deployment.TestReplicas, request)
+			Eventually(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests+1), deployment.AllCheckpointed(), func() bool {
+				for _, replica := range deployment.TestReplicas {
+					if len(replica.Node().EpochInfo().BucketLeaders) != 8 {
+						return false
+					}
+				}
+				return true
+			}), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, finalStatus := range finalStatuses {
+			Expect(submitErrs[i]).NotTo(HaveOccurred())
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+
+			records, err := auditlog.ReadFile(paths[i], formats[i])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).NotTo(BeEmpty())
+
+			// The records are numbered consecutively and their context only advances.
+			var epochsStarted, checkpoints, configChanges int
+			for j, record := range records {
+				Expect(record.Index).To(Equal(uint64(j + 1)))
+				Expect(record.NodeId).To(Equal(uint64(i)))
+				if j > 0 {
+					Expect(record.Epoch).To(BeNumerically(">=", records[j-1].Epoch))
+					Expect(record.DeliveredSn).To(BeNumerically(">=", records[j-1].DeliveredSn))
+				}
+
+				switch e := record.Event.Type.(type) {
+				case *eventpb.Notification_EpochStarted:
+					epochsStarted++
+					Expect(record.Epoch).To(Equal(e.EpochStarted.Epoch))
+				case *eventpb.Notification_CheckpointStable:
+					checkpoints++
+					Expect(e.CheckpointStable.AppSnapshot).To(BeEmpty())
+				case *eventpb.Notification_ConfigChanged:
+					configChanges++
+					Expect(e.ConfigChanged.Membership).To(Equal([]uint64{0, 1, 2, 3}))
+				}
+			}
+			Expect(epochsStarted).To(BeNumerically(">", 1))
+			Expect(checkpoints).To(BeNumerically(">", 0))
+			Expect(configChanges).To(Equal(1))
+
+			// A reopened audit log continues the numbering of the existing records.
+			auditLog, err := auditlog.Open(paths[i], t.NodeID(i), formats[i])
+			Expect(err).NotTo(HaveOccurred())
+			last := records[len(records)-1]
+			Expect(auditLog.Intercept((&events.EventList{}).PushBack(
+				events.Equivocation(1, t.EpochNr(last.Epoch), 0, "pbft_preprepare"),
+			))).To(Succeed())
+			Expect(auditLog.Close()).To(Succeed())
+
+			records, err = auditlog.ReadFile(paths[i], formats[i])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records[len(records)-1].Index).To(Equal(last.Index + 1))
+			Expect(records[len(records)-1].Epoch).To(Equal(last.Epoch))
+			Expect(records[len(records)-1].Event.GetEquivocation().GetNodeId()).To(Equal(uint64(1)))
+		}
+	})
+})
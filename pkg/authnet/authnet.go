@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package authnet provides an authentication layer on top of a Net module.
+// Without it, the Node accepts each received message as sent by the node the Net module reports as its sender,
+// e.g., based on the sender ID contained in the message itself.
+// An attacker controlling the network could thus forge protocol messages (e.g. votes) of other nodes.
+// The authentication layer attaches to each sent message a message authentication code (MAC)
+// computed using a secret key shared by the sender and the destination,
+// and only delivers received messages with a valid MAC.
+// Both the sender and the receiver must use the authentication layer.
+// Alternatively (see NewSigned), the identity of a node can be its public key rather than its bare numeric ID:
+// each sent message is signed with the sender's private key and a received message is only delivered
+// if it is signed by the key registered (with the Crypto module) for the node the message claims to be sent by.
+// Note that the authentication layer does not prevent replaying messages previously sent by the same node.
+package authnet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Net is a modules.Net that wraps another modules.Net, authenticating all messages sent and received over it
+// using HMAC-SHA256 with pairwise shared keys or, if created using NewSigned, using node signatures.
+// Received messages that are not authenticated or whose MAC (or signature) is invalid are dropped.
+// Net must be started using the Start method before use and stopped using the Stop method.
+type Net struct {
+
+	// The wrapped Net module.
+	net modules.Net
+
+	// ID of the node using this Net.
+	ownID t.NodeID
+
+	// The keys shared with the other nodes, indexed by node ID. Only used if crypto is nil.
+	keys map[t.NodeID][]byte
+
+	// The Crypto module used for signing sent messages and verifying the signatures of received ones.
+	// If nil, messages are authenticated using the shared keys.
+	crypto modules.Crypto
+
+	// Logger used for all logging events of this Net.
+	logger logging.Logger
+
+	// Channel to which all authenticated received messages are written (see ReceiveChan).
+	incoming chan modules.ReceivedMessage
+
+	// Closed when the Net is stopped, making its goroutine exit.
+	stopC chan struct{}
+
+	// Used to wait for the goroutine to exit on Stop.
+	wg sync.WaitGroup
+}
+
+// New returns a new Net authenticating the messages sent over the given Net module by node ownID.
+// keys must contain, for each other node, the secret key shared by ownID and that node.
+// The same key must be used at the other node for ownID.
+// Messages to or from nodes without a key are dropped.
+// The returned Net is not yet running. This needs to be done explicitly by calling the Start() method.
+func New(net modules.Net, ownID t.NodeID, keys map[t.NodeID][]byte, logger logging.Logger) *Net {
+
+	// If no logger was given, only write errors to the console.
+	if logger == nil {
+		logger = logging.ConsoleErrorLogger
+	}
+
+	return &Net{
+		net:      net,
+		ownID:    ownID,
+		keys:     keys,
+		logger:   logger,
+		incoming: make(chan modules.ReceivedMessage),
+	}
+}
+
+// NewSigned returns a new Net authenticating the messages sent over the given Net module by node ownID
+// using signatures produced and verified by the given Crypto module.
+// The Crypto module must sign with the private key of ownID, and the public keys of the other nodes must be registered
+// with it (see modules.Crypto), making them the identities of the nodes.
+// Messages from nodes without a registered key are dropped.
+// The Crypto module can be shared with the Node (e.g., such that node key rotations apply to the Net as well),
+// in which case it must be safe for concurrent use (as is crypto.Crypto).
+// The returned Net is not yet running. This needs to be done explicitly by calling the Start() method.
+func NewSigned(net modules.Net, ownID t.NodeID, crypto modules.Crypto, logger logging.Logger) *Net {
+	n := New(net, ownID, nil, logger)
+	n.crypto = crypto
+	return n
+}
+
+// Start launches the goroutine receiving (and verifying) messages from the wrapped Net module.
+// The wrapped Net module must be started separately (if it needs starting).
+func (n *Net) Start() {
+	n.stopC = make(chan struct{})
+	n.wg.Add(1)
+	go n.receive()
+}
+
+// Stop stops the goroutine launched by Start and waits for it to exit.
+func (n *Net) Stop() {
+	close(n.stopC)
+	n.wg.Wait()
+}
+
+// Send authenticates msg using the key shared with node dest (or the own signature)
+// and sends it to dest using the wrapped Net module.
+// Send is safe for concurrent use if the wrapped Net module's Send is.
+func (n *Net) Send(dest t.NodeID, msg *messagepb.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("could not serialize message: %w", err)
+	}
+	authenticated := &messagepb.AuthenticatedMessage{Msg: data}
+
+	if n.crypto != nil {
+		if authenticated.Signature, err = n.crypto.Sign(signedData(n.ownID, dest, data)); err != nil {
+			return fmt.Errorf("could not sign message: %w", err)
+		}
+	} else {
+		key, ok := n.keys[dest]
+		if !ok {
+			return fmt.Errorf("no authentication key for node %d", dest)
+		}
+		authenticated.Mac = computeMAC(key, n.ownID, dest, data)
+	}
+
+	return n.net.Send(dest, &messagepb.Message{Type: &messagepb.Message_Authenticated{
+		Authenticated: authenticated,
+	}})
+}
+
+// ReceiveChan returns a channel to which the Net writes all authenticated received messages and sender IDs.
+func (n *Net) ReceiveChan() <-chan modules.ReceivedMessage {
+	return n.incoming
+}
+
+// receive reads messages from the wrapped Net module and delivers those with a valid MAC until the Net is stopped.
+func (n *Net) receive() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case received := <-n.net.ReceiveChan():
+			msg, err := n.verify(received.Sender, received.Msg)
+			if err != nil {
+				n.logger.Log(logging.LevelWarn, "Dropping unauthenticated message.",
+					"source", received.Sender, "err", err)
+				continue
+			}
+
+			select {
+			case n.incoming <- modules.ReceivedMessage{Sender: received.Sender, Msg: msg}:
+			case <-n.stopC:
+				return
+			}
+		case <-n.stopC:
+			return
+		}
+	}
+}
+
+// verify checks the MAC (or the signature) of a message received from node source and returns the contained message.
+func (n *Net) verify(source t.NodeID, msg *messagepb.Message) (*messagepb.Message, error) {
+	authenticated, ok := msg.Type.(*messagepb.Message_Authenticated)
+	if !ok {
+		return nil, fmt.Errorf("message not authenticated: %T", msg.Type)
+	}
+	data := authenticated.Authenticated.Msg
+
+	if n.crypto != nil {
+		signature := authenticated.Authenticated.Signature
+		if err := n.crypto.VerifyNodeSig(signedData(source, n.ownID, data), signature, source); err != nil {
+			return nil, fmt.Errorf("invalid signature: %w", err)
+		}
+	} else {
+		key, ok := n.keys[source]
+		if !ok {
+			return nil, fmt.Errorf("no authentication key for node %d", source)
+		}
+		if !hmac.Equal(authenticated.Authenticated.Mac, computeMAC(key, source, n.ownID, data)) {
+			return nil, fmt.Errorf("invalid MAC")
+		}
+	}
+
+	contained := &messagepb.Message{}
+	if err := proto.Unmarshal(data, contained); err != nil {
+		return nil, fmt.Errorf("could not deserialize message: %w", err)
+	}
+	return contained, nil
+}
+
+// computeMAC returns the MAC of a serialized message sent from node source to node dest.
+// The node IDs are included, such that a message cannot be presented as sent in the opposite direction.
+func computeMAC(key []byte, source t.NodeID, dest t.NodeID, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	ids := nodeIDBytes(source, dest)
+	mac.Write(ids[:])
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// signedData returns the data signed for a serialized message sent from node source to node dest.
+// As with the MAC, the node IDs are included, such that the message cannot be presented as sent to another node.
+func signedData(source t.NodeID, dest t.NodeID, data []byte) [][]byte {
+	ids := nodeIDBytes(source, dest)
+	return [][]byte{ids[:], data}
+}
+
+// nodeIDBytes returns the binary representation of a pair of node IDs.
+func nodeIDBytes(source t.NodeID, dest t.NodeID) [16]byte {
+	var ids [16]byte
+	binary.BigEndian.PutUint64(ids[:8], source.Pb())
+	binary.BigEndian.PutUint64(ids[8:], dest.Pb())
+	return ids
+}
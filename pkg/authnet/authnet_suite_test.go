@@ -0,0 +1,13 @@
+package authnet_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAuthnet(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Authnet Suite")
+}
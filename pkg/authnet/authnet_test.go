@@ -0,0 +1,326 @@
+package authnet_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/authnet"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// recordingNet is a Net module wrapper that records the last message sent over it.
+type recordingNet struct {
+	modules.Net
+	lock sync.Mutex
+	last *messagepb.Message
+}
+
+func (rn *recordingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	rn.lock.Lock()
+	rn.last = msg
+	rn.lock.Unlock()
+	return rn.Net.Send(dest, msg)
+}
+
+func (rn *recordingNet) lastSent() *messagepb.Message {
+	rn.lock.Lock()
+	defer rn.lock.Unlock()
+	return rn.last
+}
+
+// testMessage returns a distinguishable message.
+func testMessage(sn uint64) *messagepb.Message {
+	return &messagepb.Message{Type: &messagepb.Message_DummyPreprepare{
+		DummyPreprepare: &messagepb.DummyPreprepare{Sn: sn},
+	}}
+}
+
+// pairwiseKey returns the (test-only) authentication key shared by nodes a and b.
+func pairwiseKey(a, b t.NodeID) []byte {
+	if a > b {
+		a, b = b, a
+	}
+	key := sha256.Sum256([]byte(fmt.Sprintf("key-%d-%d", a, b)))
+	return key[:]
+}
+
+// forge returns a copy of the authenticated message msg with its payload replaced by the one of replacement.
+func forge(msg *messagepb.Message, replacement *messagepb.Message) *messagepb.Message {
+	data, err := proto.Marshal(replacement)
+	Expect(err).NotTo(HaveOccurred())
+	authenticated := msg.Type.(*messagepb.Message_Authenticated).Authenticated
+	return &messagepb.Message{Type: &messagepb.Message_Authenticated{Authenticated: &messagepb.AuthenticatedMessage{
+		Msg:       data,
+		Mac:       authenticated.Mac,
+		Signature: authenticated.Signature,
+	}}}
+}
+
+var _ = Describe("Net", func() {
+	var (
+		membership []t.NodeID
+		transport  *deploytest.FakeTransport
+		links      []*recordingNet
+		nets       []*authnet.Net
+	)
+
+	// start wraps the links of all nodes in the authenticating Nets created by newNet and starts them.
+	start := func(newNet func(net modules.Net, id t.NodeID) *authnet.Net) {
+		for _, id := range membership {
+			links = append(links, &recordingNet{Net: transport.Link(id)})
+			nets = append(nets, newNet(links[id], id))
+			nets[id].Start()
+		}
+	}
+
+	// sharedKeys returns the keys node id shares with all nodes.
+	sharedKeys := func(id t.NodeID) map[t.NodeID][]byte {
+		keys := make(map[t.NodeID][]byte)
+		for _, other := range membership {
+			keys[other] = pairwiseKey(id, other)
+		}
+		return keys
+	}
+
+	// expectReceived expects node dest to receive msg from source next.
+	expectReceived := func(dest t.NodeID, source t.NodeID, msg *messagepb.Message) {
+		var received modules.ReceivedMessage
+		Eventually(nets[dest].ReceiveChan()).Should(Receive(&received))
+		Expect(received.Sender).To(Equal(source))
+		Expect(proto.Equal(received.Msg, msg)).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		membership = []t.NodeID{0, 1, 2}
+		transport = deploytest.NewFakeTransport(len(membership))
+		links = nil
+		nets = nil
+		transport.Start()
+	})
+
+	AfterEach(func() {
+		for _, net := range nets {
+			net.Stop()
+		}
+		transport.Stop()
+	})
+
+	Context("with shared keys", func() {
+		BeforeEach(func() {
+			start(func(net modules.Net, id t.NodeID) *authnet.Net {
+				return authnet.New(net, id, sharedKeys(id), logging.NilLogger)
+			})
+		})
+
+		It("delivers authenticated messages", func() {
+			Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+			expectReceived(1, 0, testMessage(1))
+			Expect(links[0].lastSent().Type).To(BeAssignableToTypeOf(&messagepb.Message_Authenticated{}))
+		})
+
+		It("drops unauthenticated messages", func() {
+			Expect(links[0].Send(1, testMessage(1))).To(Succeed())
+			Expect(nets[0].Send(1, testMessage(2))).To(Succeed())
+			expectReceived(1, 0, testMessage(2))
+		})
+
+		It("drops messages with a forged payload", func() {
+			Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+			expectReceived(1, 0, testMessage(1))
+
+			Expect(links[0].Send(1, forge(links[0].lastSent(), testMessage(100)))).To(Succeed())
+			Expect(nets[0].Send(1, testMessage(2))).To(Succeed())
+			expectReceived(1, 0, testMessage(2))
+		})
+
+		It("drops messages presented as sent in the opposite direction", func() {
+			Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+			expectReceived(1, 0, testMessage(1))
+
+			// Node 1 reflects the message back to node 0 (the same key is shared by both).
+			Expect(links[1].Send(0, links[0].lastSent())).To(Succeed())
+			Expect(nets[1].Send(0, testMessage(2))).To(Succeed())
+			expectReceived(0, 1, testMessage(2))
+		})
+
+		It("drops messages authenticated for another node", func() {
+			Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+			expectReceived(1, 0, testMessage(1))
+
+			// Node 0 replays the message sent to node 1 to node 2.
+			Expect(links[0].Send(2, links[0].lastSent())).To(Succeed())
+			Expect(nets[0].Send(2, testMessage(2))).To(Succeed())
+			expectReceived(2, 0, testMessage(2))
+		})
+	})
+
+	Context("with a missing key", func() {
+		BeforeEach(func() {
+			start(func(net modules.Net, id t.NodeID) *authnet.Net {
+				keys := sharedKeys(id)
+				if id == 0 {
+					delete(keys, 1)
+				}
+				if id == 1 {
+					delete(keys, 2)
+				}
+				return authnet.New(net, id, keys, logging.NilLogger)
+			})
+		})
+
+		It("refuses to send to the node", func() {
+			Expect(nets[0].Send(1, testMessage(1))).NotTo(Succeed())
+		})
+
+		It("drops messages from the node", func() {
+			Expect(nets[2].Send(1, testMessage(1))).To(Succeed())
+			Consistently(nets[1].ReceiveChan(), "100ms").ShouldNot(Receive())
+		})
+	})
+
+	Context("with signatures", func() {
+		BeforeEach(func() {
+			start(func(net modules.Net, id t.NodeID) *authnet.Net {
+				cryptoModule, err := mirCrypto.NodePseudo(membership, nil, id, mirCrypto.DefaultPseudoSeed)
+				Expect(err).NotTo(HaveOccurred())
+				return authnet.NewSigned(net, id, cryptoModule, logging.NilLogger)
+			})
+		})
+
+		It("delivers signed messages", func() {
+			Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+			expectReceived(1, 0, testMessage(1))
+			Expect(links[0].lastSent().GetAuthenticated().Signature).NotTo(BeEmpty())
+		})
+
+		It("drops messages with a forged payload", func() {
+			Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+			expectReceived(1, 0, testMessage(1))
+
+			Expect(links[0].Send(1, forge(links[0].lastSent(), testMessage(100)))).To(Succeed())
+			Expect(nets[0].Send(1, testMessage(2))).To(Succeed())
+			expectReceived(1, 0, testMessage(2))
+		})
+
+		It("drops messages signed by another node", func() {
+			Expect(nets[2].Send(1, testMessage(1))).To(Succeed())
+			expectReceived(1, 2, testMessage(1))
+
+			// Node 0 presents the message signed by node 2 as its own.
+			Expect(links[0].Send(1, links[2].lastSent())).To(Succeed())
+			Expect(nets[0].Send(1, testMessage(2))).To(Succeed())
+			expectReceived(1, 0, testMessage(2))
+		})
+	})
+})
+
+var _ = Describe("Message authentication test", func() {
+
+	It("delivers all requests with authenticated messages and drops forged ones", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Authenticate all messages using pairwise keys derived from the node IDs.
+		// The first replica additionally sends a forged (unauthenticated) message with each authenticated one,
+		// which the receivers must drop.
+		for _, replica := range deployment.TestReplicas {
+			keys := make(map[t.NodeID][]byte)
+			for _, other := range replica.Membership {
+				keys[other] = pairwiseKey(replica.Id, other)
+			}
+			net := replica.Net
+			if replica.Id == 0 {
+				net = &forgingNet{Net: net}
+			}
+			replica.Net = authnet.New(net, replica.Id, keys, replica.Config.Logger)
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// The signed authentication test makes all nodes sign their messages, using public keys as node identities.
+// As in the authentication test, the first replica additionally sends forged messages, which the receivers must drop.
+var _ = Describe("Signed authentication test", func() {
+
+	It("delivers all requests with signed messages and drops forged ones", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Sign all messages using the Crypto module of the replica, which knows the public keys of all nodes.
+		for _, replica := range deployment.TestReplicas {
+			cryptoModule, err := mirCrypto.NodePseudo(
+				replica.Membership,
+				replica.ClientIDs,
+				replica.Id,
+				mirCrypto.DefaultPseudoSeed,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			replica.Crypto = cryptoModule
+
+			net := replica.Net
+			if replica.Id == 0 {
+				net = &forgingNet{Net: net}
+			}
+			replica.Net = authnet.NewSigned(net, replica.Id, cryptoModule, replica.Config.Logger)
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// forgingNet is a Net module wrapper that, along with each sent authenticated message,
+// sends a forged message with the same MAC (or signature), but a different payload.
+// The payload is a message ISS does not accept, such that accepting the forged message would crash the receiver.
+type forgingNet struct {
+	modules.Net
+}
+
+func (fn *forgingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if authenticated, ok := msg.Type.(*messagepb.Message_Authenticated); ok {
+		forged, err := proto.Marshal(&messagepb.Message{Type: &messagepb.Message_DummyPreprepare{
+			DummyPreprepare: &messagepb.DummyPreprepare{},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		if err := fn.Net.Send(dest, &messagepb.Message{Type: &messagepb.Message_Authenticated{
+			Authenticated: &messagepb.AuthenticatedMessage{
+				Msg:       forged,
+				Mac:       authenticated.Authenticated.Mac,
+				Signature: authenticated.Authenticated.Signature,
+			},
+		}}); err != nil {
+			return err
+		}
+	}
+	return fn.Net.Send(dest, msg)
+}
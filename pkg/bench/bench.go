@@ -0,0 +1,582 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bench measures the performance of a cluster of Nodes running the ISS protocol in a single process.
+// The Nodes are connected by an in-memory network, persist their state in memory,
+// and sign and verify messages and requests as in a real deployment (see crypto.NodePseudo).
+// Clients submit requests of a configurable size to all Nodes, keeping a bounded number of requests in flight,
+// and a request counts as committed once f+1 Nodes (at least one of them correct) delivered it,
+// as a client waiting for f+1 matching responses would observe it.
+// Network faults and node crashes can be injected using a faults.Scenario.
+//
+// As all Nodes share the same machine, the absolute numbers mostly reflect the CPU cost of the protocol
+// rather than the performance of a distributed deployment. They are meant for comparing commits
+// (see the mirbench command), not for capacity planning.
+package bench
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/serializing"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	"github.com/hyperledger-labs/mirbft/pkg/testkit"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Config describes a benchmark. Zero values are replaced by the defaults (see DefaultConfig).
+type Config struct {
+
+	// Number of Nodes in the cluster.
+	NumNodes int
+
+	// Number of clients submitting requests concurrently.
+	NumClients int
+
+	// Number of requests each client submits.
+	RequestsPerClient int
+
+	// Size of the payload of each request in bytes.
+	PayloadSize int
+
+	// Maximal number of requests of a client that have been submitted but not yet committed.
+	// A client only submits a new request when one of its requests has been committed.
+	Window int
+
+	// Maximal number of requests in a batch (see iss.Config.MaxBatchSize).
+	MaxBatchSize int
+
+	// Maximal number of ticks a leader waits for a batch to fill before proposing it (see iss.Config.MaxProposeDelay).
+	MaxProposeDelay int
+
+	// Number of sequence numbers in each segment (see iss.Config.SegmentLength).
+	SegmentLength int
+
+	// Interval between the ticks of the logical clock of the Nodes.
+	TickInterval time.Duration
+
+	// Latency injected into each Sync of the Nodes' WAL and request store, simulating a (slow) disk.
+	SyncLatency time.Duration
+
+	// If not nil, the network faults and node crashes to inject, with time measured from the start of the benchmark.
+	// Crashed nodes are not restarted, i.e., the Crash.RestartAt field must be zero.
+	// Note that the segments led by a crashed node stall until the end of the epoch, which itself waits for them,
+	// so crashing a leader typically prevents the remaining requests from being committed.
+	Faults *faults.Scenario
+
+	// Seed of all random decisions of the fault injection.
+	Seed int64
+
+	// Maximal duration of the benchmark. If not all requests are committed by then, Run returns an error.
+	Timeout time.Duration
+
+	// Logger of the Nodes. If nil, nothing is logged.
+	Logger logging.Logger
+}
+
+// DefaultConfig returns the default configuration of a benchmark: 4 Nodes and 4 clients,
+// each submitting 1000 requests of 256 bytes with at most 100 requests in flight.
+// The ISS parameters not present in Config are those of iss.DefaultConfig.
+func DefaultConfig() *Config {
+	return &Config{
+		NumNodes:          4,
+		NumClients:        4,
+		RequestsPerClient: 1000,
+		PayloadSize:       256,
+		Window:            100,
+		MaxBatchSize:      64,
+		MaxProposeDelay:   2,
+		SegmentLength:     16,
+		TickInterval:      10 * time.Millisecond,
+		Timeout:           time.Minute,
+	}
+}
+
+// withDefaults returns a copy of c with all zero values replaced by the defaults.
+func (c *Config) withDefaults() *Config {
+	defaults := DefaultConfig()
+	config := *c
+	if config.NumNodes == 0 {
+		config.NumNodes = defaults.NumNodes
+	}
+	if config.NumClients == 0 {
+		config.NumClients = defaults.NumClients
+	}
+	if config.RequestsPerClient == 0 {
+		config.RequestsPerClient = defaults.RequestsPerClient
+	}
+	if config.Window == 0 {
+		config.Window = defaults.Window
+	}
+	if config.MaxBatchSize == 0 {
+		config.MaxBatchSize = defaults.MaxBatchSize
+	}
+	if config.MaxProposeDelay == 0 {
+		config.MaxProposeDelay = defaults.MaxProposeDelay
+	}
+	if config.SegmentLength == 0 {
+		config.SegmentLength = defaults.SegmentLength
+	}
+	if config.TickInterval == 0 {
+		config.TickInterval = defaults.TickInterval
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.Logger == nil {
+		config.Logger = logging.NilLogger
+	}
+	return &config
+}
+
+// Result summarizes the performance observed by a benchmark.
+// The JSON representation of a Result (with durations in nanoseconds) is meant for tracking the results over time.
+type Result struct {
+
+	// Number of requests committed.
+	Committed int `json:"committed"`
+
+	// Time from the submission of the first request to the commit of the last one.
+	Duration time.Duration `json:"duration"`
+
+	// Number of requests committed per second.
+	Throughput float64 `json:"throughput"`
+
+	// Percentiles of the latencies of the committed requests, from their submission to their commit.
+	LatencyP50 time.Duration `json:"latencyP50"`
+	LatencyP90 time.Duration `json:"latencyP90"`
+	LatencyP99 time.Duration `json:"latencyP99"`
+	LatencyMax time.Duration `json:"latencyMax"`
+}
+
+// String returns a single-line summary of the result.
+func (r *Result) String() string {
+	return fmt.Sprintf("%d requests in %v: %.1f req/s, latency p50 %v, p90 %v, p99 %v, max %v",
+		r.Committed, r.Duration.Round(time.Millisecond), r.Throughput,
+		r.LatencyP50.Round(time.Microsecond), r.LatencyP90.Round(time.Microsecond),
+		r.LatencyP99.Round(time.Microsecond), r.LatencyMax.Round(time.Microsecond))
+}
+
+// Run executes the benchmark described by config and returns its result.
+// If not all requests have been committed within config.Timeout, or if a Node fails,
+// Run returns the result for the requests committed so far together with an error.
+func Run(config *Config) (*Result, error) {
+	config = config.withDefaults()
+	if config.Faults != nil {
+		for _, crash := range config.Faults.Crashes {
+			if crash.RestartAt != 0 {
+				return nil, fmt.Errorf("node %d: restarting crashed nodes is not supported", crash.Node)
+			}
+		}
+	}
+
+	c, err := newCluster(config)
+	if err != nil {
+		return nil, err
+	}
+	return c.run()
+}
+
+// cluster is a running benchmark.
+type cluster struct {
+	config *Config
+
+	membership []t.NodeID
+	clientIDs  []t.ClientID
+
+	nodes []*mirbft.Node
+	nets  []*faults.Net
+	net   *testkit.Loopback
+
+	// Closed to stop (or crash) each node, exactly once (see stopNode).
+	nodeExitCs   []chan struct{}
+	nodeStopOnce []sync.Once
+
+	// The errors the nodes' Run methods returned. Written to by the goroutines running the nodes.
+	nodeErrs []error
+
+	// Tracks the deliveries of the requests. Written to by the nodes' Apps.
+	tracker *tracker
+}
+
+// newCluster creates the nodes of a benchmark.
+func newCluster(config *Config) (*cluster, error) {
+	membership := make([]t.NodeID, config.NumNodes)
+	for i := range membership {
+		membership[i] = t.NodeID(i)
+	}
+	clientIDs := make([]t.ClientID, config.NumClients)
+	for i := range clientIDs {
+		clientIDs[i] = t.ClientID(i)
+	}
+
+	c := &cluster{
+		config:       config,
+		membership:   membership,
+		clientIDs:    clientIDs,
+		net:          testkit.NewLoopback(config.NumNodes),
+		nodeExitCs:   make([]chan struct{}, config.NumNodes),
+		nodeStopOnce: make([]sync.Once, config.NumNodes),
+		nodeErrs:     make([]error, config.NumNodes),
+		tracker:      newTracker(config.NumClients, config.NumClients*config.RequestsPerClient, (config.NumNodes-1)/3+1),
+	}
+
+	var injector faults.Injector = faults.Chain()
+	if config.Faults != nil && config.Faults.Injector != nil {
+		injector = config.Faults.Injector
+	}
+
+	for _, nodeID := range membership {
+		issConfig := iss.DefaultConfig(membership)
+		issConfig.MaxBatchSize = t.NumRequests(config.MaxBatchSize)
+		issConfig.MaxProposeDelay = config.MaxProposeDelay
+		issConfig.SegmentLength = config.SegmentLength
+		protocol, err := iss.New(nodeID, issConfig, logging.Decorate(config.Logger, "ISS: "))
+		if err != nil {
+			return nil, fmt.Errorf("could not create protocol of node %d: %w", nodeID, err)
+		}
+
+		cryptoModule, err := mirCrypto.NodePseudo(membership, clientIDs, nodeID, mirCrypto.DefaultPseudoSeed)
+		if err != nil {
+			return nil, fmt.Errorf("could not create crypto module of node %d: %w", nodeID, err)
+		}
+
+		wal := simplewal.NewVolatileWAL()
+		wal.SyncLatency = config.SyncLatency
+		reqStore := reqstore.NewVolatileRequestStore()
+		reqStore.SyncLatency = config.SyncLatency
+
+		net := faults.NewNet(c.net.Link(nodeID), nodeID, injector, config.Seed+int64(nodeID), config.Logger)
+
+		node, err := mirbft.NewNode(
+			nodeID,
+			&mirbft.NodeConfig{
+				Logger:       logging.Decorate(config.Logger, fmt.Sprintf("Node %d: ", nodeID)),
+				TickInterval: config.TickInterval,
+			},
+			&modules.Modules{
+				Net:           net,
+				App:           &app{nodeID: nodeID, tracker: c.tracker},
+				WAL:           wal,
+				RequestStore:  reqStore,
+				ClientTracker: clients.SigningTracker(logging.Decorate(config.Logger, "CT: ")),
+				Protocol:      protocol,
+				Crypto:        cryptoModule,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not create node %d: %w", nodeID, err)
+		}
+
+		c.nodes = append(c.nodes, node)
+		c.nets = append(c.nets, net)
+		c.nodeExitCs[nodeID] = make(chan struct{})
+	}
+
+	return c, nil
+}
+
+// run runs the benchmark until all requests are committed or the timeout expires.
+func (c *cluster) run() (*Result, error) {
+	var nodesWg sync.WaitGroup
+	var failedOnce sync.Once
+	failedC := make(chan struct{})
+
+	c.net.Start()
+	for i, node := range c.nodes {
+		nodesWg.Add(1)
+		go func(i int, node *mirbft.Node) {
+			defer nodesWg.Done()
+			c.nodeErrs[i] = node.Run(c.nodeExitCs[i], nil)
+			if !errors.Is(c.nodeErrs[i], mirbft.ErrStopped) {
+				failedOnce.Do(func() { close(failedC) })
+			}
+		}(i, node)
+	}
+
+	// Crash the nodes as scheduled by the fault scenario.
+	var crashTimers []*time.Timer
+	if c.config.Faults != nil {
+		for _, crash := range c.config.Faults.Crashes {
+			nodeID := crash.Node
+			crashTimers = append(crashTimers, time.AfterFunc(crash.At, func() { c.stopNode(nodeID) }))
+		}
+	}
+
+	// Start the clients and wait until all requests are committed.
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	var clientsWg sync.WaitGroup
+	clientErrs := make([]error, len(c.clientIDs))
+	for i, clientID := range c.clientIDs {
+		clientsWg.Add(1)
+		go func(i int, clientID t.ClientID) {
+			defer clientsWg.Done()
+			clientErrs[i] = c.runClient(ctx, clientID)
+		}(i, clientID)
+	}
+
+	var runErr error
+	select {
+	case <-c.tracker.doneC:
+	case <-ctx.Done():
+		runErr = fmt.Errorf("only %d of %d requests committed within %v",
+			c.tracker.numCommitted(), c.config.NumClients*c.config.RequestsPerClient, c.config.Timeout)
+	case <-failedC:
+		runErr = errors.New("a node failed")
+	}
+	cancel()
+	clientsWg.Wait()
+
+	// Stop the cluster.
+	for _, timer := range crashTimers {
+		timer.Stop()
+	}
+	for _, net := range c.nets {
+		net.Stop()
+	}
+	for _, nodeID := range c.membership {
+		c.stopNode(nodeID)
+	}
+	nodesWg.Wait()
+	c.net.Stop()
+
+	for i, err := range c.nodeErrs {
+		if !errors.Is(err, mirbft.ErrStopped) {
+			runErr = fmt.Errorf("node %d failed: %w", i, err)
+		}
+	}
+	if runErr == nil {
+		for i, err := range clientErrs {
+			if err != nil {
+				runErr = fmt.Errorf("client %d failed: %w", i, err)
+			}
+		}
+	}
+
+	return c.tracker.result(), runErr
+}
+
+// stopNode makes a node stop immediately, abandoning its in-flight work. It can be called multiple times.
+func (c *cluster) stopNode(nodeID t.NodeID) {
+	c.nodeStopOnce[nodeID].Do(func() {
+		close(c.nodeExitCs[nodeID])
+	})
+}
+
+// runClient submits the requests of a client to all nodes, keeping at most config.Window requests in flight.
+func (c *cluster) runClient(ctx context.Context, clientID t.ClientID) error {
+	cryptoModule, err := mirCrypto.ClientPseudo(c.membership, c.clientIDs, clientID, mirCrypto.DefaultPseudoSeed)
+	if err != nil {
+		return err
+	}
+	hasher := crypto.SHA256
+
+	committedC := c.tracker.committedCs[clientID]
+	for reqNo := 0; reqNo < c.config.RequestsPerClient; reqNo++ {
+
+		// Wait for a free slot in the window.
+		if reqNo >= c.config.Window {
+			select {
+			case <-committedC:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		req := &requestpb.Request{
+			ClientId: clientID.Pb(),
+			ReqNo:    t.ReqNo(reqNo).Pb(),
+			Data:     make([]byte, c.config.PayloadSize),
+		}
+		h := hasher.New()
+		if err := serializing.WriteRequestForHash(h, req); err != nil {
+			return err
+		}
+		if req.Authenticator, err = cryptoModule.Sign([][]byte{h.Sum(nil)}); err != nil {
+			return err
+		}
+
+		c.tracker.submitted(clientID, t.ReqNo(reqNo))
+		for _, node := range c.nodes {
+			// Crashed (i.e., stopped) nodes reject the requests, which the client tolerates.
+			err := node.SubmitRequest(ctx, clientID, t.ReqNo(reqNo), req.Data, req.Authenticator)
+			if ctx.Err() != nil {
+				return nil
+			} else if err != nil && !errors.Is(err, mirbft.ErrStopping) && !errors.Is(err, mirbft.ErrStopped) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// app is the App module of a benchmarked node. It reports the delivered requests to the tracker
+// and its state only consists of the number of delivered requests.
+type app struct {
+	nodeID    t.NodeID
+	tracker   *tracker
+	delivered uint64
+}
+
+func (a *app) Apply(batch *requestpb.Batch) error {
+	for _, reqRef := range batch.Requests {
+		a.tracker.delivered(t.ClientID(reqRef.ClientId), t.ReqNo(reqRef.ReqNo))
+		a.delivered++
+	}
+	return nil
+}
+
+func (a *app) Snapshot() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d", a.delivered)), nil
+}
+
+func (a *app) RestoreState(snapshot []byte) error {
+	_, err := fmt.Sscanf(string(snapshot), "%d", &a.delivered)
+	return err
+}
+
+// tracker measures the latencies of the requests, from their submission until f+1 nodes delivered them.
+// All its methods are safe for concurrent use.
+type tracker struct {
+	lock sync.Mutex
+
+	// Number of deliveries after which a request counts as committed.
+	quorum int
+
+	// Submission times of the requests that have not been committed yet.
+	submissions map[requestKey]time.Time
+
+	// Number of nodes that delivered each request that has not been committed yet.
+	deliveries map[requestKey]int
+
+	// Latencies of the committed requests, in the order of their commits.
+	latencies []time.Duration
+
+	// Times of the first submission and the last commit.
+	start time.Time
+	end   time.Time
+
+	// One channel per client, to which a value is written each time one of its requests has been committed.
+	committedCs []chan struct{}
+
+	// Total number of requests the clients submit.
+	total int
+
+	// Closed when all requests have been committed.
+	doneC chan struct{}
+}
+
+// requestKey identifies a request.
+type requestKey struct {
+	clientID t.ClientID
+	reqNo    t.ReqNo
+}
+
+// newTracker returns a new tracker for numClients clients submitting total requests,
+// considering a request committed after quorum deliveries.
+func newTracker(numClients int, total int, quorum int) *tracker {
+	committedCs := make([]chan struct{}, numClients)
+	for i := range committedCs {
+		// Buffered, such that committing never blocks on a slow client.
+		committedCs[i] = make(chan struct{}, 1<<20)
+	}
+	return &tracker{
+		quorum:      quorum,
+		submissions: make(map[requestKey]time.Time),
+		deliveries:  make(map[requestKey]int),
+		committedCs: committedCs,
+		total:       total,
+		doneC:       make(chan struct{}),
+	}
+}
+
+// submitted records the submission of a request.
+func (tr *tracker) submitted(clientID t.ClientID, reqNo t.ReqNo) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	now := time.Now()
+	if tr.start.IsZero() {
+		tr.start = now
+	}
+	tr.submissions[requestKey{clientID: clientID, reqNo: reqNo}] = now
+}
+
+// delivered records the delivery of a request by a node.
+func (tr *tracker) delivered(clientID t.ClientID, reqNo t.ReqNo) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	key := requestKey{clientID: clientID, reqNo: reqNo}
+	submitted, ok := tr.submissions[key]
+	if !ok {
+		// Already committed.
+		return
+	}
+
+	tr.deliveries[key]++
+	if tr.deliveries[key] < tr.quorum {
+		return
+	}
+
+	tr.end = time.Now()
+	tr.latencies = append(tr.latencies, tr.end.Sub(submitted))
+	delete(tr.submissions, key)
+	delete(tr.deliveries, key)
+	tr.committedCs[clientID] <- struct{}{}
+	if len(tr.latencies) == tr.total {
+		close(tr.doneC)
+	}
+}
+
+// numCommitted returns the number of requests committed so far.
+func (tr *tracker) numCommitted() int {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	return len(tr.latencies)
+}
+
+// result returns the result of the benchmark so far.
+func (tr *tracker) result() *Result {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	result := &Result{Committed: len(tr.latencies)}
+	if len(tr.latencies) == 0 {
+		return result
+	}
+
+	result.Duration = tr.end.Sub(tr.start)
+	if result.Duration > 0 {
+		result.Throughput = float64(result.Committed) / result.Duration.Seconds()
+	}
+
+	sorted := append([]time.Duration{}, tr.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+	result.LatencyP50 = percentile(50)
+	result.LatencyP90 = percentile(90)
+	result.LatencyP99 = percentile(99)
+	result.LatencyMax = percentile(100)
+	return result
+}
@@ -0,0 +1,83 @@
+package bench_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/bench"
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+)
+
+var _ = Describe("Run", func() {
+	It("commits all requests and reports their latencies", func() {
+		result, err := bench.Run(&bench.Config{
+			NumClients:        2,
+			RequestsPerClient: 100,
+			Window:            10,
+			Timeout:           30 * time.Second,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Committed).To(Equal(200))
+		Expect(result.Throughput).To(BeNumerically(">", 0))
+		Expect(result.LatencyP50).To(BeNumerically(">", 0))
+		Expect(result.LatencyP50).To(BeNumerically("<=", result.LatencyP90))
+		Expect(result.LatencyP90).To(BeNumerically("<=", result.LatencyP99))
+		Expect(result.LatencyP99).To(BeNumerically("<=", result.LatencyMax))
+		Expect(result.LatencyMax).To(BeNumerically("<=", result.Duration))
+	})
+
+	It("commits all requests despite delayed and duplicated messages", func() {
+		scenario, err := faults.ParseScenario([]byte(`{"faults": [
+			{"type": "delay", "min": "1ms", "max": "5ms"},
+			{"type": "duplicate", "rate": 0.1, "maxDelay": "5ms"}
+		]}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := bench.Run(&bench.Config{
+			NumClients:        2,
+			RequestsPerClient: 50,
+			Faults:            scenario,
+			Seed:              42,
+			Timeout:           30 * time.Second,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Committed).To(Equal(100))
+	})
+
+	It("reports the requests committed before the timeout", func() {
+		// With all messages dropped, no request can be committed.
+		result, err := bench.Run(&bench.Config{
+			NumClients:        1,
+			RequestsPerClient: 10,
+			Faults:            &faults.Scenario{Injector: &faults.Drop{Rate: 1}},
+			Timeout:           time.Second,
+		})
+		Expect(err).To(MatchError("only 0 of 10 requests committed within 1s"))
+		Expect(result.Committed).To(Equal(0))
+	})
+
+	It("rejects restarting crashed nodes", func() {
+		_, err := bench.Run(&bench.Config{Faults: &faults.Scenario{
+			Crashes: []faults.Crash{{Node: 1, At: time.Second, RestartAt: 2 * time.Second}},
+		}})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// BenchmarkRun runs the default benchmark b.N times, reporting the throughput and latencies as custom metrics, e.g.:
+//
+//	go test ./pkg/bench -run '^$' -bench Run -benchtime 3x
+func BenchmarkRun(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		result, err := bench.Run(bench.DefaultConfig())
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(result.Throughput, "req/s")
+		b.ReportMetric(float64(result.LatencyP50.Microseconds()), "p50-us")
+		b.ReportMetric(float64(result.LatencyP99.Microseconds()), "p99-us")
+	}
+}
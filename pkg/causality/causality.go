@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package causality provides (opt-in) tracing of message causality across nodes.
+// When tracing is enabled at a Node, each outgoing message is assigned a lightweight causal ID
+// that is carried with the message to its receivers. Each event the protocol produces when processing
+// a received message (and, transitively, each event resulting from those) inherits the message's causal ID,
+// such that messages sent as a consequence can be linked back to the message that caused them.
+// All sending, reception, and resulting events are reported to a Tracer as Records.
+// Debugging tools can then assemble the records into message flows,
+// e.g., all messages and events related to the agreement on a given sequence number or to an epoch change.
+package causality
+
+import (
+	"fmt"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Kind is the kind of a causality Record.
+type Kind int
+
+const (
+	// Sent is the kind of a record of sending a message.
+	Sent Kind = iota
+
+	// Received is the kind of a record of receiving a message.
+	Received
+
+	// Produced is the kind of a record of the protocol producing an event
+	// as a (direct or indirect) result of processing a received message.
+	Produced
+)
+
+// String returns a human-readable representation of the record kind.
+func (k Kind) String() string {
+	switch k {
+	case Sent:
+		return "sent"
+	case Received:
+		return "received"
+	case Produced:
+		return "produced"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(k))
+	}
+}
+
+// Record describes a single step of a causal message flow.
+type Record struct {
+
+	// The kind of the record.
+	Kind Kind
+
+	// The node at which the record was created.
+	Node t.NodeID
+
+	// For Sent and Received records, the causal ID of the message.
+	// Always zero for Produced records.
+	ID uint64
+
+	// The causal ID of the received message the recorded step results from.
+	// For Received records, this is equal to ID. For Sent records, it is zero if the message
+	// was not sent as a result of a traced received message.
+	Cause uint64
+
+	// For Sent records, the destinations of the message. For Received records, the sender of the message.
+	// Empty for Produced records.
+	Peers []t.NodeID
+
+	// For Sent and Received records, the message itself.
+	Message *messagepb.Message
+
+	// For Produced records, the produced event (including its follow-up events).
+	Event *eventpb.Event
+}
+
+// Tracer is notified about all causality records of a Node.
+// The Trace method may be called concurrently by multiple goroutines and must not modify the record.
+type Tracer interface {
+	Trace(record *Record)
+}
+
+// ID returns the globally unique causal ID of the n-th message sent by node nodeID.
+// The node ID occupies the 16 most significant bits of the causal ID.
+// Note that n must be greater than 0, as causal ID 0 denotes an untraced message.
+func ID(nodeID t.NodeID, n uint64) uint64 {
+	return uint64(nodeID)<<48 | n&(1<<48-1)
+}
@@ -0,0 +1,13 @@
+package causality_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCausality(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Causality Suite")
+}
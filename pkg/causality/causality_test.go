@@ -0,0 +1,105 @@
+package causality_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/causality"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("ID", func() {
+
+	It("is unique across nodes", func() {
+		Expect(causality.ID(0, 1)).NotTo(Equal(causality.ID(1, 1)))
+		Expect(causality.ID(1, 1)).NotTo(Equal(causality.ID(1, 2)))
+		Expect(causality.ID(0, 1)).NotTo(BeZero())
+	})
+
+	It("keeps the node ID in the most significant bits", func() {
+		Expect(causality.ID(3, 7) >> 48).To(Equal(uint64(3)))
+		Expect(causality.ID(3, 1<<48+7)).To(Equal(causality.ID(3, 7)))
+	})
+})
+
+var _ = Describe("Recorder", func() {
+	var recorder *causality.Recorder
+
+	// A flow spanning two nodes: node 0 sends message a to node 1, which, as a result, produces an event
+	// and sends message b back to node 0. Unrelated message c is sent by node 1 independently.
+	var (
+		a = causality.ID(0, 1)
+		b = causality.ID(1, 1)
+		c = causality.ID(1, 2)
+
+		sentA     = &causality.Record{Kind: causality.Sent, Node: 0, ID: a, Peers: []t.NodeID{1}}
+		receivedA = &causality.Record{Kind: causality.Received, Node: 1, ID: a, Cause: a, Peers: []t.NodeID{0}}
+		producedA = &causality.Record{Kind: causality.Produced, Node: 1, Cause: a}
+		sentB     = &causality.Record{Kind: causality.Sent, Node: 1, ID: b, Cause: a, Peers: []t.NodeID{0}}
+		receivedB = &causality.Record{Kind: causality.Received, Node: 0, ID: b, Cause: b, Peers: []t.NodeID{1}}
+		sentC     = &causality.Record{Kind: causality.Sent, Node: 1, ID: c, Peers: []t.NodeID{0}}
+		receivedC = &causality.Record{Kind: causality.Received, Node: 0, ID: c, Cause: c, Peers: []t.NodeID{1}}
+	)
+
+	BeforeEach(func() {
+		recorder = causality.NewRecorder()
+	})
+
+	It("returns the records in the order in which they were saved", func() {
+		recorder.Trace(sentA)
+		recorder.Trace(sentC)
+		Expect(recorder.Records()).To(Equal([]*causality.Record{sentA, sentC}))
+	})
+
+	It("assembles flows across nodes", func() {
+		for _, record := range []*causality.Record{sentA, sentC, receivedA, producedA, sentB, receivedC, receivedB} {
+			recorder.Trace(record)
+		}
+
+		Expect(recorder.Flow(func(record *causality.Record) bool { return record == sentA })).
+			To(Equal([]*causality.Record{sentA, receivedA, producedA, sentB, receivedB}))
+		Expect(recorder.Flow(func(record *causality.Record) bool { return record == sentC })).
+			To(Equal([]*causality.Record{sentC, receivedC}))
+	})
+
+	It("assembles flows from records saved out of causal order", func() {
+		for _, record := range []*causality.Record{receivedB, sentB, producedA, receivedA, sentA} {
+			recorder.Trace(record)
+		}
+
+		Expect(recorder.Flow(func(record *causality.Record) bool { return record == sentA })).
+			To(Equal([]*causality.Record{receivedB, sentB, producedA, receivedA, sentA}))
+	})
+
+	It("returns an empty flow if no record matches", func() {
+		recorder.Trace(sentA)
+		Expect(recorder.Flow(func(*causality.Record) bool { return false })).To(BeEmpty())
+	})
+
+	It("can be shared by concurrently tracing nodes", func() {
+		var wg sync.WaitGroup
+		for node := t.NodeID(0); node < 4; node++ {
+			wg.Add(1)
+			go func(node t.NodeID) {
+				defer wg.Done()
+				for n := uint64(1); n <= 100; n++ {
+					recorder.Trace(&causality.Record{Kind: causality.Sent, Node: node, ID: causality.ID(node, n)})
+				}
+			}(node)
+		}
+		wg.Wait()
+		Expect(recorder.Records()).To(HaveLen(400))
+	})
+})
+
+var _ = Describe("Kind", func() {
+
+	It("has a readable name", func() {
+		Expect(causality.Sent.String()).To(Equal("sent"))
+		Expect(causality.Received.String()).To(Equal("received"))
+		Expect(causality.Produced.String()).To(Equal("produced"))
+		Expect(causality.Kind(7).String()).To(Equal("unknown(7)"))
+	})
+})
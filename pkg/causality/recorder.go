@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package causality
+
+import (
+	"sync"
+)
+
+// Recorder is a Tracer that keeps all the records in memory.
+// The same Recorder can be shared by multiple nodes, in which case it can assemble message flows spanning nodes.
+type Recorder struct {
+	mutex   sync.Mutex
+	records []*Record
+}
+
+// NewRecorder returns a new empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		records: make([]*Record, 0),
+	}
+}
+
+// Trace saves the record.
+func (r *Recorder) Trace(record *Record) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.records = append(r.records, record)
+}
+
+// Records returns all the records saved so far, in the order in which they have been saved.
+func (r *Recorder) Records() []*Record {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	records := make([]*Record, len(r.records))
+	copy(records, r.records)
+	return records
+}
+
+// Flow returns the message flow originating at the records matched by the given function.
+// The flow consists of the matched records and all records that are causally dependent on them:
+// the reception of each sent message in the flow, the events produced as a result of each received message,
+// and the messages sent as a result of those events. Records are returned in the order in which they have been saved.
+// For example, to obtain the flow of the agreement on a sequence number,
+// match the records of sending the protocol messages that carry that sequence number.
+func (r *Recorder) Flow(match func(record *Record) bool) []*Record {
+	records := r.Records()
+
+	// Find the roots of the flow.
+	inFlow := make([]bool, len(records))
+	causes := make(map[uint64]struct{})
+	for i, record := range records {
+		if match(record) {
+			inFlow[i] = true
+			if record.ID != 0 {
+				causes[record.ID] = struct{}{}
+			}
+		}
+	}
+
+	// Add causally dependent records until no more are found.
+	// Records are not necessarily saved in causal order (e.g. when multiple nodes share the Recorder),
+	// so all records are scanned repeatedly.
+	for changed := true; changed; {
+		changed = false
+		for i, record := range records {
+			if inFlow[i] {
+				continue
+			}
+
+			_, idInFlow := causes[record.ID]
+			_, causeInFlow := causes[record.Cause]
+			if (record.ID != 0 && idInFlow) || (record.Cause != 0 && causeInFlow) {
+				inFlow[i] = true
+				changed = true
+				if record.ID != 0 {
+					causes[record.ID] = struct{}{}
+				}
+			}
+		}
+	}
+
+	flow := make([]*Record, 0)
+	for i, record := range records {
+		if inFlow[i] {
+			flow = append(flow, record)
+		}
+	}
+	return flow
+}
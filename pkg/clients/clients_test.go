@@ -0,0 +1,230 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clients_test
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("GossipingSigningTracker", func() {
+
+	// gossipTargets feeds numRequests valid requests from a client to a tracker gossiping with the given seed
+	// and returns the destinations each request is forwarded to.
+	gossipTargets := func(seed int64, numRequests int) [][]uint64 {
+		ct := clients.GossipingSigningTracker(&clients.GossipConfig{
+			OwnID:      0,
+			Membership: []t.NodeID{0, 1, 2, 3, 4, 5, 6, 7},
+			Fanout:     2,
+			Seed:       seed,
+		}, logging.NilLogger)
+
+		targets := make([][]uint64, 0, numRequests)
+		for reqNo := 0; reqNo < numRequests; reqNo++ {
+			req := &requestpb.Request{ClientId: 0, ReqNo: uint64(reqNo), Data: []byte(fmt.Sprintf("request %d", reqNo))}
+			digest := []byte(fmt.Sprintf("digest %d", reqNo))
+			ct.ApplyEvent(events.HashResult(digest, &eventpb.HashOrigin{
+				Type: &eventpb.HashOrigin_Request{Request: req},
+			}))
+
+			reqRef := &requestpb.RequestRef{ClientId: req.ClientId, ReqNo: req.ReqNo, Digest: digest}
+			for _, event := range ct.ApplyEvent(events.RequestSigVerified(reqRef, true, "")).Slice() {
+				if send, ok := event.Type.(*eventpb.Event_SendMessage); ok {
+					targets = append(targets, send.SendMessage.Destinations)
+				}
+			}
+		}
+		return targets
+	}
+
+	It("chooses the same gossip targets given the same seed", func() {
+		targets := gossipTargets(42, 20)
+		Expect(targets).To(HaveLen(20))
+		for _, destinations := range targets {
+			Expect(destinations).To(HaveLen(2))
+			Expect(destinations).NotTo(ContainElement(uint64(0)))
+		}
+		Expect(gossipTargets(42, 20)).To(Equal(targets))
+		Expect(gossipTargets(43, 20)).NotTo(Equal(targets))
+	})
+})
+
+// The client reconfiguration test adds a client using a configuration request and restarts all nodes.
+// After the restart, the client (whose key is only known from the configuration request)
+// must be able to submit requests.
+var _ = Describe("Client reconfiguration test", func() {
+
+	It("keeps clients added by configuration requests across restarts", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All nodes accept requests from the configuration client.
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// Create a new client, unknown to the nodes, and the configuration request adding it.
+		newClient := t.ClientID(7)
+		privKey, pubKey, err := mirCrypto.GenerateKeyPair(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		clientCrypto, err := mirCrypto.New(privKey)
+		Expect(err).NotTo(HaveOccurred())
+		addRequest := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			AddClients: []*isspb.ClientKey{{ClientId: newClient.Pb(), PubKey: pubKey}},
+		})
+
+		// committed returns a condition that holds when all nodes committed the first request of client.
+		committed := func(client t.ClientID) func() bool {
+			return func() bool {
+				for _, replica := range deployment.TestReplicas {
+					if _, ok := replica.Node().ClientCommitted(client); !ok {
+						return false
+					}
+				}
+				return true
+			}
+		}
+
+		// Add the client and run until the change is included in a stable checkpoint.
+		deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			for _, err := range deploytest.SubmitToAll(deployment.TestReplicas, addRequest) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Eventually(committed(t.ConfigClientID), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+			deployment.AwaitStableCheckpoint()
+		})
+
+		for _, node := range nodes {
+			reqNo, ok := node.ClientCommitted(t.ConfigClientID)
+			Expect(ok).To(BeTrue())
+			Expect(reqNo).To(Equal(t.ReqNo(0)))
+		}
+
+		// Restart all nodes and have the new client submit a request.
+		// Its public key is only known to the nodes from the recovered checkpoint.
+		for _, replica := range deployment.TestReplicas {
+			replica.App = &deploytest.FakeApp{}
+			replica.NumFakeRequests = 0
+			replica.Restart = true
+		}
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			for _, err := range deploytest.SubmitToAll(deployment.TestReplicas, deploytest.SignedRequest(clientCrypto, newClient, 0, []byte("new client request"))) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Eventually(committed(newClient), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, node := range nodes {
+			Expect(finalStatuses[i].ExitErr).To(Equal(mirbft.ErrStopped))
+			reqNo, ok := node.ClientCommitted(newClient)
+			Expect(ok).To(BeTrue())
+			Expect(reqNo).To(Equal(t.ReqNo(0)))
+		}
+	})
+
+	It("rejects requests of clients removed by configuration requests after the epoch switch", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All nodes accept requests from the configuration client.
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		for _, replica := range deployment.TestReplicas {
+			replica.ClientIDs = clientIDs
+		}
+
+		// Create a new client, unknown to the nodes, and the configuration requests adding and removing it.
+		newClient := t.ClientID(7)
+		privKey, pubKey, err := mirCrypto.GenerateKeyPair(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		clientCrypto, err := mirCrypto.New(privKey)
+		Expect(err).NotTo(HaveOccurred())
+		addRequest := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			AddClients: []*isspb.ClientKey{{ClientId: newClient.Pb(), PubKey: pubKey}},
+		})
+		removeRequest := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 1, &isspb.ConfigChange{
+			RemoveClients: []uint64{newClient.Pb()},
+		})
+
+		// committed returns a condition that holds when all nodes committed request reqNo of client.
+		committed := func(client t.ClientID, reqNo t.ReqNo) func() bool {
+			return func() bool {
+				for _, replica := range deployment.TestReplicas {
+					if committedReqNo, ok := replica.Node().ClientCommitted(client); !ok || committedReqNo < reqNo {
+						return false
+					}
+				}
+				return true
+			}
+		}
+
+		// submit submits a request to all nodes and waits until it has been committed
+		// and the epoch it has been committed in has ended.
+		submit := func(request *requestpb.Request) {
+			for _, err := range deploytest.SubmitToAll(deployment.TestReplicas, request) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Eventually(committed(t.ClientID(request.ClientId), t.ReqNo(request.ReqNo)), deploytest.TestTimeout, deploytest.PollInterval).
+				Should(BeTrue())
+			deployment.AwaitStableCheckpoint()
+		}
+
+		deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+
+			// Once added, the client can submit requests.
+			submit(addRequest)
+			submit(deploytest.SignedRequest(clientCrypto, newClient, 0, []byte("request of added client")))
+
+			// Once removed, the requests of the client are rejected.
+			// Waiting for an epoch to end after the request has been submitted gives the nodes ample time
+			// to commit the request, if they accepted it.
+			submit(removeRequest)
+			for _, err := range deploytest.SubmitToAll(deployment.TestReplicas, deploytest.SignedRequest(clientCrypto, newClient, 1, []byte("request of removed client"))) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			deployment.AwaitStableCheckpoint()
+			Expect(committed(newClient, 1)()).To(BeFalse())
+		})
+
+		for _, replica := range deployment.TestReplicas {
+			reqNo, ok := replica.Node().ClientCommitted(newClient)
+			Expect(ok).To(BeTrue())
+			Expect(reqNo).To(Equal(t.ReqNo(0)))
+		}
+	})
+})
@@ -32,6 +32,10 @@ func (ct *DummyClientTracker) ApplyEvent(event *eventpb.Event) *events.EventList
 			&eventpb.HashOrigin{Type: &eventpb.HashOrigin_Request{Request: req}},
 		))
 
+	case *eventpb.Event_ForwardedRequest:
+		// Request forwarded by another node. Treat it the same way as a request received from a client.
+		return ct.ApplyEvent(&eventpb.Event{Type: &eventpb.Event_Request{Request: e.ForwardedRequest.Request}})
+
 	case *eventpb.Event_HashResult:
 		// Digest for a client request.
 		// Persist request and announce it to the protocol.
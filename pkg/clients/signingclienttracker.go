@@ -8,27 +8,89 @@ package clients
 
 import (
 	"fmt"
+	"math/rand"
+
 	"github.com/hyperledger-labs/mirbft/pkg/events"
 	"github.com/hyperledger-labs/mirbft/pkg/logging"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
 	"github.com/hyperledger-labs/mirbft/pkg/serializing"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
 )
 
 type SigningClientTracker struct {
 	logger logging.Logger
 
 	unverifiedRequests map[string]*requestpb.Request
+
+	// For each request (indexed by reqStrKey), the set of other nodes known to have the request,
+	// i.e., the nodes that forwarded it to this node and the nodes this node forwarded it to.
+	// When gossiping, requests are only forwarded to nodes not known to have them.
+	holders map[string]map[t.NodeID]struct{}
+
+	// Gossip configuration. If nil, requests are not gossiped.
+	gossip *GossipConfig
+
+	// Source of randomness for choosing the nodes to gossip requests to, seeded by GossipConfig.Seed.
+	rand *rand.Rand
+
+	// Set of requests (indexed by reqStrKey) that have already been received
+	// and the order in which they have been received (for forgetting the oldest ones, including their holders).
+	seen      map[string]struct{}
+	seenOrder []string
+}
+
+// GossipConfig configures gossip-based dissemination of requests (see GossipingSigningTracker).
+type GossipConfig struct {
+
+	// ID of the node using the client tracker.
+	OwnID t.NodeID
+
+	// IDs of all nodes in the system.
+	Membership []t.NodeID
+
+	// Number of randomly chosen nodes each request is gossiped to.
+	Fanout int
+
+	// Number of most recently received requests remembered for deduplication.
+	// A duplicate of a forgotten request is processed (and gossiped) again,
+	// which is harmless, but wastes resources.
+	MaxSeen int
+
+	// Seed of the source of randomness used for choosing the nodes to gossip requests to.
+	// Given the same seed, the client tracker chooses the same nodes for the same sequence of received requests.
+	Seed int64
 }
 
 func SigningTracker(logger logging.Logger) *SigningClientTracker {
 	return &SigningClientTracker{
 		logger:             logger,
 		unverifiedRequests: make(map[string]*requestpb.Request),
+		holders:            make(map[string]map[t.NodeID]struct{}),
 	}
 }
 
+// GossipingSigningTracker returns a SigningClientTracker that disseminates the received requests using gossip.
+// Instead of clients sending each request to all nodes (or each node forwarding it to all other nodes),
+// a client can send a request to a single node, from which the request propagates to the other nodes:
+// Each node forwards each valid request it receives for the first time to config.Fanout randomly chosen nodes.
+// Requests are identified by their digests, and duplicates are dropped before verifying their signatures.
+// A request is only forwarded to nodes not known to have it already
+// (i.e., not to the nodes the request has been received from).
+// With a fanout logarithmic in the number of nodes, all nodes receive each request with high probability,
+// while the total payload traffic stays linear in the number of nodes (times the fanout).
+// Gossip does not guarantee that all nodes receive a request. If a request is not committed in time,
+// the client must re-submit it.
+func GossipingSigningTracker(config *GossipConfig, logger logging.Logger) *SigningClientTracker {
+	ct := SigningTracker(logger)
+	ct.gossip = config
+	ct.rand = rand.New(rand.NewSource(config.Seed))
+	ct.seen = make(map[string]struct{})
+	return ct
+}
+
 // ApplyEvent processes an event incoming to the SigningClientTracker module
 // and produces a (potentially empty) list of new events to be processed by the node.
 func (ct *SigningClientTracker) ApplyEvent(event *eventpb.Event) *events.EventList {
@@ -43,13 +105,31 @@ func (ct *SigningClientTracker) ApplyEvent(event *eventpb.Event) *events.EventLi
 			&eventpb.HashOrigin{Type: &eventpb.HashOrigin_Request{Request: req}},
 		))
 
+	case *eventpb.Event_ForwardedRequest:
+		// Request forwarded by another node. Have the digest computed, remembering where the request came from.
+
+		return (&events.EventList{}).PushBack(events.HashRequest(
+			serializing.RequestForHash(e.ForwardedRequest.Request),
+			&eventpb.HashOrigin{Type: &eventpb.HashOrigin_ForwardedRequest{ForwardedRequest: e.ForwardedRequest}},
+		))
+
 	case *eventpb.Event_HashResult:
 		// Digest for a client request.
 		// Verify request signature.
 		// TODO: Implement request number watermarks.
 
+		// Obtain the request and, if it has been forwarded, the node it has been forwarded by.
+		var req *requestpb.Request
+		var forwarded *eventpb.ForwardedRequest
+		switch origin := e.HashResult.Origin.Type.(type) {
+		case *eventpb.HashOrigin_Request:
+			req = origin.Request
+		case *eventpb.HashOrigin_ForwardedRequest:
+			forwarded = origin.ForwardedRequest
+			req = forwarded.Request
+		}
+
 		// Create a reference to the received request, including the computed hash.
-		req := e.HashResult.Origin.Type.(*eventpb.HashOrigin_Request).Request
 		digest := e.HashResult.Digest
 		reqRef := &requestpb.RequestRef{
 			ClientId: req.ClientId,
@@ -57,6 +137,18 @@ func (ct *SigningClientTracker) ApplyEvent(event *eventpb.Event) *events.EventLi
 			Digest:   digest,
 		}
 
+		if ct.gossip != nil {
+			// The forwarding node evidently has the request.
+			if forwarded != nil {
+				ct.addHolders(reqRef, t.NodeID(forwarded.From))
+			}
+
+			// Drop requests that have already been received (without verifying their signature again).
+			if _, ok := ct.seen[reqStrKey(reqRef)]; ok {
+				return &events.EventList{}
+			}
+		}
+
 		// Store a reference to the request until the signature verification result is available.
 		// An alternative would be to store the payload in the request store directly
 		// and delete it if authentication fails.
@@ -79,15 +171,37 @@ func (ct *SigningClientTracker) ApplyEvent(event *eventpb.Event) *events.EventLi
 		delete(ct.unverifiedRequests, reqStrKey(reqRef))
 
 		if e.RequestSigVerified.Valid {
+			// When gossiping, ignore valid requests that have already been received.
+			// (Multiple copies of a request might have been verified concurrently.)
+			// Only requests with a valid signature are marked as received, such that an invalid copy of a request
+			// does not prevent the valid request from being processed.
+			if ct.gossip != nil && !ct.markSeen(reqRef) {
+				return &events.EventList{}
+			}
+
 			// If signature is valid,
 			// store the verified request in the request store and, submit a reference to it to the protocol.
 			// It is important to first persist the request and only then submit it to the protocol,
 			// in case the node crashes in between.
 			storeEvent := events.StoreVerifiedRequest(reqRef, req.Data, req.Authenticator)
-			storeEvent.Next = []*eventpb.Event{events.RequestReady(reqRef)}
-			return (&events.EventList{}).PushBack(storeEvent)
+			storeEvent.Next = []*eventpb.Event{events.RequestReady(reqRef, req.Data)}
+			eventsOut := (&events.EventList{}).PushBack(storeEvent)
+
+			// When gossiping, forward the valid request to other nodes that do not have it yet.
+			if ct.gossip != nil {
+				if targets := ct.gossipTargets(reqRef); len(targets) > 0 {
+					ct.addHolders(reqRef, targets...)
+					eventsOut.PushBack(events.SendMessage(&messagepb.Message{
+						Type: &messagepb.Message_ForwardedRequest{ForwardedRequest: req},
+					}, targets))
+				}
+			}
+			return eventsOut
 		} else {
-			// If signature is not valid, ignore request
+			// If signature is not valid, ignore request (forgetting its holders, unless a valid copy has been seen).
+			if _, ok := ct.seen[reqStrKey(reqRef)]; !ok {
+				delete(ct.holders, reqStrKey(reqRef))
+			}
 			ct.logger.Log(logging.LevelWarn, "Ignoring invalid request",
 				"clID", reqRef.ClientId, "reqNo", reqRef.ReqNo, "err", e.RequestSigVerified.Error)
 			return &events.EventList{}
@@ -102,6 +216,55 @@ func (ct *SigningClientTracker) Status() (s *statuspb.ClientTrackerStatus, err e
 	return nil, nil
 }
 
+// markSeen records the reception of a valid request.
+// It returns true if the request has not been seen before (or has already been forgotten) and false otherwise.
+func (ct *SigningClientTracker) markSeen(reqRef *requestpb.RequestRef) bool {
+	key := reqStrKey(reqRef)
+	if _, ok := ct.seen[key]; ok {
+		return false
+	}
+
+	// Forget the oldest request if necessary.
+	if ct.gossip.MaxSeen > 0 && len(ct.seenOrder) >= ct.gossip.MaxSeen {
+		delete(ct.seen, ct.seenOrder[0])
+		delete(ct.holders, ct.seenOrder[0])
+		ct.seenOrder = ct.seenOrder[1:]
+	}
+
+	ct.seen[key] = struct{}{}
+	ct.seenOrder = append(ct.seenOrder, key)
+	return true
+}
+
+// addHolders records that the given nodes have the referenced request.
+func (ct *SigningClientTracker) addHolders(reqRef *requestpb.RequestRef, nodeIDs ...t.NodeID) {
+	key := reqStrKey(reqRef)
+	if _, ok := ct.holders[key]; !ok {
+		ct.holders[key] = make(map[t.NodeID]struct{})
+	}
+	for _, id := range nodeIDs {
+		ct.holders[key][id] = struct{}{}
+	}
+}
+
+// gossipTargets returns the IDs of Fanout randomly chosen nodes (other than the own node)
+// not known to have the referenced request. If there are not enough such nodes, all of them are returned.
+func (ct *SigningClientTracker) gossipTargets(reqRef *requestpb.RequestRef) []t.NodeID {
+	holders := ct.holders[reqStrKey(reqRef)]
+	others := make([]t.NodeID, 0, len(ct.gossip.Membership))
+	for _, id := range ct.gossip.Membership {
+		if _, ok := holders[id]; !ok && id != ct.gossip.OwnID {
+			others = append(others, id)
+		}
+	}
+
+	ct.rand.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+	if len(others) > ct.gossip.Fanout {
+		others = others[:ct.gossip.Fanout]
+	}
+	return others
+}
+
 // reqStrKey takes a request reference and transforms it to a string for using as a map key.
 func reqStrKey(reqRef *requestpb.RequestRef) string {
 	return fmt.Sprintf("%d-%d.%v", reqRef.ClientId, reqRef.ReqNo, reqRef.Digest)
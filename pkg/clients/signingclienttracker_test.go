@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clients_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+var _ = Describe("Request gossip test", func() {
+
+	It("disseminates requests submitted to a single node", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Only submit the requests to the first replica and let them propagate using gossip.
+		// As requests are not gossiped back to the nodes they have been received from,
+		// with a fanout of 2, the replicas reached by the first one reach all the remaining ones,
+		// such that the outcome does not depend on the random choice of gossip targets.
+		for i, replica := range deployment.TestReplicas {
+			replica.GossipFanout = 2
+			if i > 0 {
+				replica.NumFakeRequests = 0
+			}
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package clock abstracts the passage of time, such that all time-based behavior
+// (generating ticks, timeouts, retry backoff, timestamps) can be driven by a virtual clock in tests.
+// Components take a Clock (typically as an optional configuration parameter defaulting to System)
+// and never use the functions of package time that depend on the current time directly.
+// A Mock clock only advances when told to, which lets tests skip waiting periods instantly and deterministically
+// instead of sleeping.
+package clock
+
+import (
+	"time"
+)
+
+// A Clock provides the current time and the means of waiting for time to pass.
+// The methods have the same semantics as the corresponding functions of package time.
+// A Clock is safe for concurrent use.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+	NewTicker(d time.Duration) Ticker
+}
+
+// A Ticker delivers ticks at regular intervals on the channel returned by C, like a time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// System is the Clock of the operating system, implemented by package time.
+var System Clock = systemClock{}
+
+// OrSystem returns c, unless it is nil, in which case it returns System.
+// It is meant for resolving optional Clock configuration parameters.
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System
+	}
+	return c
+}
+
+// systemClock is the type of System.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// systemTicker is a Ticker wrapping a time.Ticker.
+type systemTicker struct {
+	ticker *time.Ticker
+}
+
+func (st systemTicker) C() <-chan time.Time {
+	return st.ticker.C
+}
+
+func (st systemTicker) Stop() {
+	st.ticker.Stop()
+}
@@ -0,0 +1,73 @@
+package clock_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+)
+
+var _ = Describe("Mock", func() {
+	var (
+		start     time.Time
+		mockClock *clock.Mock
+	)
+
+	BeforeEach(func() {
+		start = time.Unix(1000, 0)
+		mockClock = clock.NewMock(start)
+	})
+
+	It("only advances when told to", func() {
+		Expect(mockClock.Now()).To(Equal(start))
+		mockClock.Advance(time.Second)
+		Expect(mockClock.Now()).To(Equal(start.Add(time.Second)))
+		Expect(mockClock.Since(start)).To(Equal(time.Second))
+	})
+
+	It("fires timers in the order of their due time", func() {
+		late := mockClock.After(2 * time.Second)
+		early := mockClock.After(time.Second)
+		Expect(mockClock.Waiters()).To(Equal(2))
+
+		mockClock.Advance(time.Second - 1)
+		Consistently(early).ShouldNot(Receive())
+
+		mockClock.Advance(5 * time.Second)
+		Expect(early).To(Receive(Equal(start.Add(time.Second))))
+		Expect(late).To(Receive(Equal(start.Add(2 * time.Second))))
+		Expect(mockClock.Waiters()).To(Equal(0))
+		Expect(mockClock.Now()).To(Equal(start.Add(6*time.Second - 1)))
+	})
+
+	It("drops the ticks of a ticker that are not read", func() {
+		ticker := mockClock.NewTicker(time.Second)
+
+		mockClock.Advance(3 * time.Second)
+		Expect(ticker.C()).To(Receive(Equal(start.Add(time.Second))))
+		Expect(ticker.C()).NotTo(Receive())
+
+		mockClock.Advance(time.Second)
+		Expect(ticker.C()).To(Receive(Equal(start.Add(4 * time.Second))))
+
+		ticker.Stop()
+		mockClock.Advance(time.Second)
+		Expect(ticker.C()).NotTo(Receive())
+		Expect(mockClock.Waiters()).To(Equal(0))
+	})
+
+	It("wakes up sleepers", func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			mockClock.Sleep(time.Hour)
+		}()
+
+		mockClock.BlockUntil(1)
+		Consistently(done).ShouldNot(BeClosed())
+		mockClock.Advance(time.Hour)
+		Eventually(done).Should(BeClosed())
+	})
+})
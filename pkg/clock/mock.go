@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Mock is a virtual Clock for tests. Its time only changes when Advance is called.
+// Advancing the time fires all timers (created by After and Sleep) and tickers that become due,
+// in the order of their due time (and in the order of their creation for equal due times).
+// As with time.Ticker, a ticker whose tick has not been read yet drops the ticks that fall due in the meantime.
+type Mock struct {
+
+	// Protects all the fields.
+	lock sync.Mutex
+
+	// The current virtual time.
+	now time.Time
+
+	// The pending timers and tickers.
+	waiters []*waiter
+
+	// Sequence number of the next waiter, determining the order of waiters with equal due times.
+	nextSeq uint64
+
+	// Closed (and replaced by a new channel) whenever a waiter is added, for BlockUntil.
+	added chan struct{}
+}
+
+// waiter is a pending timer or ticker of a Mock.
+type waiter struct {
+	at     time.Time
+	seq    uint64
+	c      chan time.Time
+	period time.Duration // Zero for timers.
+}
+
+// NewMock returns a new Mock whose current time is start.
+func NewMock(start time.Time) *Mock {
+	return &Mock{
+		now:   start,
+		added: make(chan struct{}),
+	}
+}
+
+// Now returns the current virtual time.
+func (m *Mock) Now() time.Time {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.now
+}
+
+// Since returns the virtual time elapsed since t.
+func (m *Mock) Since(t time.Time) time.Duration {
+	return m.Now().Sub(t)
+}
+
+// After returns a channel to which the virtual time is written once the clock has been advanced by at least d.
+// If d is not positive, the time is written immediately.
+func (m *Mock) After(d time.Duration) <-chan time.Time {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- m.now
+		return c
+	}
+	m.addWaiter(&waiter{at: m.now.Add(d), c: c})
+	return c
+}
+
+// Sleep blocks until the clock has been advanced by at least d.
+func (m *Mock) Sleep(d time.Duration) {
+	<-m.After(d)
+}
+
+// NewTicker returns a new Ticker that ticks each time the clock has been advanced by another d.
+// Like time.NewTicker, NewTicker panics if d is not positive.
+func (m *Mock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for Mock.NewTicker")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	w := &waiter{at: m.now.Add(d), c: make(chan time.Time, 1), period: d}
+	m.addWaiter(w)
+	return &mockTicker{mock: m, waiter: w}
+}
+
+// Advance moves the virtual time forward by d, firing all timers and tickers that fall due.
+func (m *Mock) Advance(d time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	target := m.now.Add(d)
+	for len(m.waiters) > 0 && !m.waiters[0].at.After(target) {
+		w := m.waiters[0]
+		m.now = w.at
+
+		// Deliver the tick, unless the previous one has not been read yet.
+		select {
+		case w.c <- m.now:
+		default:
+		}
+
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+			m.sortWaiters()
+		} else {
+			m.waiters = m.waiters[1:]
+		}
+	}
+	m.now = target
+}
+
+// Waiters returns the number of pending timers and tickers, including goroutines blocked in Sleep.
+func (m *Mock) Waiters() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return len(m.waiters)
+}
+
+// BlockUntil blocks until at least n timers and tickers are pending.
+// It lets a test wait until the tested code is waiting for the clock before advancing it.
+func (m *Mock) BlockUntil(n int) {
+	for {
+		m.lock.Lock()
+		pending, added := len(m.waiters), m.added
+		m.lock.Unlock()
+
+		if pending >= n {
+			return
+		}
+		<-added
+	}
+}
+
+// addWaiter registers w. Must be called with the lock held.
+func (m *Mock) addWaiter(w *waiter) {
+	w.seq = m.nextSeq
+	m.nextSeq++
+	m.waiters = append(m.waiters, w)
+	m.sortWaiters()
+
+	close(m.added)
+	m.added = make(chan struct{})
+}
+
+// removeWaiter unregisters w, if it is pending. Must be called with the lock held.
+func (m *Mock) removeWaiter(w *waiter) {
+	for i, pending := range m.waiters {
+		if pending == w {
+			m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// sortWaiters orders the waiters by their due time and sequence number. Must be called with the lock held.
+func (m *Mock) sortWaiters() {
+	sort.Slice(m.waiters, func(i, j int) bool {
+		if !m.waiters[i].at.Equal(m.waiters[j].at) {
+			return m.waiters[i].at.Before(m.waiters[j].at)
+		}
+		return m.waiters[i].seq < m.waiters[j].seq
+	})
+}
+
+// mockTicker is a Ticker of a Mock.
+type mockTicker struct {
+	mock   *Mock
+	waiter *waiter
+}
+
+func (mt *mockTicker) C() <-chan time.Time {
+	return mt.waiter.c
+}
+
+func (mt *mockTicker) Stop() {
+	mt.mock.lock.Lock()
+	defer mt.mock.lock.Unlock()
+
+	mt.mock.removeWaiter(mt.waiter)
+}
@@ -0,0 +1,303 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package compressnet provides a compression layer on top of a Net module.
+// Large messages (e.g. forwarded requests with big payloads or bundles of many messages)
+// are compressed before being sent, reducing the bandwidth consumed, especially when the network is the bottleneck.
+// The compression algorithm is negotiated per peer: each node announces the algorithms it supports
+// and a message is only compressed using an algorithm that the destination announced.
+// Until the negotiation with a peer is complete, messages to that peer are sent uncompressed.
+// Thus, nodes using the compression layer can communicate with nodes that do not use it
+// (messages between them are never compressed).
+package compressnet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Config holds the parameters of the compression layer.
+type Config struct {
+
+	// Compression algorithms supported by this node, in the order of preference.
+	// Messages to a peer are compressed using the first of these algorithms that the peer supports as well.
+	// If empty, no message is compressed, but compressed messages are still accepted.
+	Algorithms []messagepb.CompressionAlgorithm
+
+	// Minimal size (in bytes) of a serialized message to be compressed.
+	// Compressing small messages hardly saves any bandwidth, but costs CPU time.
+	Threshold int
+}
+
+// DefaultConfig returns the default configuration of the compression layer.
+func DefaultConfig() *Config {
+	return &Config{
+		Algorithms: []messagepb.CompressionAlgorithm{messagepb.CompressionAlgorithm_SNAPPY},
+		Threshold:  1024,
+	}
+}
+
+// Net is a modules.Net that wraps another modules.Net, compressing large messages sent over it.
+// The first time a message is sent to a peer, a CompressionHello message announcing the supported algorithms
+// is sent to the peer before it. A peer receiving a CompressionHello responds with its own one.
+// Net must be started using the Start method before use and stopped using the Stop method.
+type Net struct {
+
+	// The wrapped Net module.
+	net modules.Net
+
+	// Configuration parameters.
+	config *Config
+
+	// Logger used for all logging events of this Net.
+	logger logging.Logger
+
+	// Serializes the calls to the wrapped Net module's Send method and protects the state below.
+	lock sync.Mutex
+
+	// The peers to which a CompressionHello has already been sent.
+	helloSent map[t.NodeID]struct{}
+
+	// The algorithm negotiated with each peer from which a CompressionHello has been received.
+	// NONE if the peer does not support any of the algorithms of this node.
+	negotiated map[t.NodeID]messagepb.CompressionAlgorithm
+
+	// Channel to which all received (and decompressed) messages are written (see ReceiveChan).
+	incoming chan modules.ReceivedMessage
+
+	// Closed when the Net is stopped, making its goroutine exit.
+	stopC chan struct{}
+
+	// Used to wait for the goroutine to exit on Stop.
+	wg sync.WaitGroup
+}
+
+// New returns a new Net compressing the messages sent over the given Net module.
+// If config is nil, the default configuration is used.
+// The returned Net is not yet running. This needs to be done explicitly by calling the Start() method.
+func New(net modules.Net, config *Config, logger logging.Logger) *Net {
+
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	// If no logger was given, only write errors to the console.
+	if logger == nil {
+		logger = logging.ConsoleErrorLogger
+	}
+
+	return &Net{
+		net:        net,
+		config:     config,
+		logger:     logger,
+		helloSent:  make(map[t.NodeID]struct{}),
+		negotiated: make(map[t.NodeID]messagepb.CompressionAlgorithm),
+		incoming:   make(chan modules.ReceivedMessage),
+	}
+}
+
+// Start launches the goroutine receiving (and decompressing) messages from the wrapped Net module.
+// The wrapped Net module must be started separately (if it needs starting).
+func (n *Net) Start() {
+	n.stopC = make(chan struct{})
+	n.wg.Add(1)
+	go n.receive()
+}
+
+// Stop stops the goroutine launched by Start and waits for it to exit.
+func (n *Net) Stop() {
+	close(n.stopC)
+	n.wg.Wait()
+}
+
+// Send sends msg to node dest using the wrapped Net module,
+// compressing it if it is large enough and an algorithm has been negotiated with dest.
+// Send is safe for concurrent use.
+func (n *Net) Send(dest t.NodeID, msg *messagepb.Message) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// Announce the supported algorithms to the destination if this has not been done yet.
+	if _, ok := n.helloSent[dest]; !ok {
+		if err := n.sendHello(dest, false); err != nil {
+			return err
+		}
+	}
+
+	// Compress the message if possible and worth it.
+	// If compression fails, the message is sent uncompressed.
+	if algorithm := n.negotiated[dest]; algorithm != messagepb.CompressionAlgorithm_NONE {
+		if compressed, err := n.compress(msg, algorithm); err != nil {
+			n.logger.Log(logging.LevelWarn, "Failed compressing message.", "dest", dest, "err", err)
+		} else if compressed != nil {
+			msg = compressed
+		}
+	}
+
+	return n.net.Send(dest, msg)
+}
+
+// ReceiveChan returns a channel to which the Net writes all received messages (decompressed) and sender IDs.
+func (n *Net) ReceiveChan() <-chan modules.ReceivedMessage {
+	return n.incoming
+}
+
+// sendHello sends a CompressionHello message to node dest. Must be called with the lock held.
+func (n *Net) sendHello(dest t.NodeID, reply bool) error {
+	hello := &messagepb.Message{Type: &messagepb.Message_CompressionHello{CompressionHello: &messagepb.CompressionHello{
+		Algorithms: n.config.Algorithms,
+		Reply:      reply,
+	}}}
+	if err := n.net.Send(dest, hello); err != nil {
+		return fmt.Errorf("could not send compression hello: %w", err)
+	}
+	n.helloSent[dest] = struct{}{}
+	return nil
+}
+
+// compress returns msg compressed using the given algorithm, wrapped in a CompressedMessage.
+// If msg is smaller than the threshold or compression does not reduce its size, compress returns nil.
+func (n *Net) compress(msg *messagepb.Message, algorithm messagepb.CompressionAlgorithm) (*messagepb.Message, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < n.config.Threshold {
+		return nil, nil
+	}
+
+	var compressed []byte
+	switch algorithm {
+	case messagepb.CompressionAlgorithm_SNAPPY:
+		compressed = snappy.Encode(nil, data)
+	case messagepb.CompressionAlgorithm_GZIP:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		compressed = buf.Bytes()
+	default:
+		return nil, fmt.Errorf("unsupported compression algorithm: %v", algorithm)
+	}
+
+	if len(compressed) >= len(data) {
+		return nil, nil
+	}
+	return &messagepb.Message{Type: &messagepb.Message_Compressed{Compressed: &messagepb.CompressedMessage{
+		Algorithm: algorithm,
+		Data:      compressed,
+	}}}, nil
+}
+
+// decompress returns the message wrapped in a CompressedMessage.
+func decompress(compressed *messagepb.CompressedMessage) (*messagepb.Message, error) {
+	var data []byte
+	var err error
+	switch compressed.Algorithm {
+	case messagepb.CompressionAlgorithm_SNAPPY:
+		data, err = snappy.Decode(nil, compressed.Data)
+	case messagepb.CompressionAlgorithm_GZIP:
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(bytes.NewReader(compressed.Data)); err == nil {
+			data, err = ioutil.ReadAll(r)
+		}
+	default:
+		err = fmt.Errorf("unsupported compression algorithm: %v", compressed.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &messagepb.Message{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// receive reads messages from the wrapped Net module, processes the CompressionHello messages,
+// and delivers the other messages (decompressed if necessary) until the Net is stopped.
+func (n *Net) receive() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case received := <-n.net.ReceiveChan():
+			// Process the received message, obtaining the message to deliver (if any).
+			var deliver *messagepb.Message
+			switch msg := received.Msg.Type.(type) {
+			case *messagepb.Message_CompressionHello:
+				n.handleHello(received.Sender, msg.CompressionHello)
+			case *messagepb.Message_Compressed:
+				var err error
+				if deliver, err = decompress(msg.Compressed); err != nil {
+					n.logger.Log(logging.LevelWarn, "Dropping message that failed to decompress.",
+						"source", received.Sender, "err", err)
+				}
+			default:
+				deliver = received.Msg
+			}
+
+			// Deliver the message.
+			if deliver != nil {
+				select {
+				case n.incoming <- modules.ReceivedMessage{Sender: received.Sender, Msg: deliver}:
+				case <-n.stopC:
+					return
+				}
+			}
+		case <-n.stopC:
+			return
+		}
+	}
+}
+
+// handleHello negotiates the compression algorithm with node source based on the algorithms the node announced.
+// Unless the announcement is itself a reply, handleHello replies with the algorithms supported by this node.
+// This way, a peer that restarted (and thus forgot the algorithms announced by this node) learns them again.
+func (n *Net) handleHello(source t.NodeID, hello *messagepb.CompressionHello) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.negotiated[source] = messagepb.CompressionAlgorithm_NONE
+	for _, own := range n.config.Algorithms {
+		if supports(hello.Algorithms, own) {
+			n.negotiated[source] = own
+			break
+		}
+	}
+	n.logger.Log(logging.LevelDebug, "Negotiated compression.", "peer", source, "algorithm", n.negotiated[source])
+
+	if !hello.Reply {
+		if err := n.sendHello(source, true); err != nil {
+			n.logger.Log(logging.LevelWarn, "Failed replying to compression hello.", "peer", source, "err", err)
+		}
+	}
+}
+
+// supports returns true if algorithms contains algorithm.
+func supports(algorithms []messagepb.CompressionAlgorithm, algorithm messagepb.CompressionAlgorithm) bool {
+	for _, a := range algorithms {
+		if a == algorithm {
+			return true
+		}
+	}
+	return false
+}
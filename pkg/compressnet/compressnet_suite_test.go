@@ -0,0 +1,13 @@
+package compressnet_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCompressnet(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Compressnet Suite")
+}
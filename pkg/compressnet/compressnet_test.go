@@ -0,0 +1,283 @@
+package compressnet_test
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/compressnet"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// recordingNet is a Net module wrapper that records the messages sent over it.
+type recordingNet struct {
+	modules.Net
+	lock sync.Mutex
+	sent []*messagepb.Message
+}
+
+func (rn *recordingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	rn.lock.Lock()
+	rn.sent = append(rn.sent, msg)
+	rn.lock.Unlock()
+	return rn.Net.Send(dest, msg)
+}
+
+func (rn *recordingNet) lastSent() *messagepb.Message {
+	rn.lock.Lock()
+	defer rn.lock.Unlock()
+	return rn.sent[len(rn.sent)-1]
+}
+
+// testMessage returns a distinguishable message containing a batch of numRequests (well compressible) requests.
+func testMessage(sn uint64, numRequests int) *messagepb.Message {
+	batch := &requestpb.Batch{}
+	for i := 0; i < numRequests; i++ {
+		batch.Requests = append(batch.Requests, &requestpb.RequestRef{
+			ClientId: 1,
+			ReqNo:    uint64(i),
+			Digest:   bytes.Repeat([]byte{byte(i)}, 32),
+		})
+	}
+	return &messagepb.Message{Type: &messagepb.Message_DummyPreprepare{
+		DummyPreprepare: &messagepb.DummyPreprepare{Sn: sn, Batch: batch},
+	}}
+}
+
+var _ = Describe("Net", func() {
+	var (
+		transport *deploytest.FakeTransport
+		links     []*recordingNet
+		nets      []modules.Net
+		started   []*compressnet.Net
+	)
+
+	// start wraps the link of each node in a compressing Net using the given configuration.
+	// A nil configuration makes the node use the bare link.
+	start := func(configs ...*compressnet.Config) {
+		for i, config := range configs {
+			links = append(links, &recordingNet{Net: transport.Link(t.NodeID(i))})
+			if config == nil {
+				nets = append(nets, links[i])
+				continue
+			}
+			net := compressnet.New(links[i], config, logging.NilLogger)
+			net.Start()
+			started = append(started, net)
+			nets = append(nets, net)
+		}
+	}
+
+	// exchange sends msg from source to dest and expects dest to receive it.
+	exchange := func(source, dest t.NodeID, msg *messagepb.Message) {
+		Expect(nets[source].Send(dest, msg)).To(Succeed())
+		var received modules.ReceivedMessage
+		Eventually(nets[dest].ReceiveChan()).Should(Receive(&received))
+		Expect(received.Sender).To(Equal(source))
+		Expect(proto.Equal(received.Msg, msg)).To(BeTrue())
+	}
+
+	// negotiate makes nodes 0 and 1 exchange messages until both learned the algorithms supported by the other.
+	// Messages are delivered in the order in which they were sent between the same pair of nodes,
+	// so a node has processed the peer's hello once it received a message the peer sent after it.
+	negotiate := func() {
+		exchange(0, 1, testMessage(0, 0))
+		exchange(1, 0, testMessage(0, 0))
+	}
+
+	BeforeEach(func() {
+		transport = deploytest.NewFakeTransport(2)
+		links = nil
+		nets = nil
+		started = nil
+		transport.Start()
+	})
+
+	AfterEach(func() {
+		for _, net := range started {
+			net.Stop()
+		}
+		transport.Stop()
+	})
+
+	It("compresses large messages once the algorithm is negotiated", func() {
+		start(compressnet.DefaultConfig(), compressnet.DefaultConfig())
+		negotiate()
+
+		exchange(0, 1, testMessage(1, 100))
+		compressed := links[0].lastSent().GetCompressed()
+		Expect(compressed).NotTo(BeNil())
+		Expect(compressed.Algorithm).To(Equal(messagepb.CompressionAlgorithm_SNAPPY))
+		Expect(proto.Size(links[0].lastSent())).To(BeNumerically("<", proto.Size(testMessage(1, 100))))
+	})
+
+	It("sends small messages uncompressed", func() {
+		start(compressnet.DefaultConfig(), compressnet.DefaultConfig())
+		negotiate()
+
+		exchange(0, 1, testMessage(1, 1))
+		Expect(links[0].lastSent().GetCompressed()).To(BeNil())
+	})
+
+	It("sends messages uncompressed before the negotiation", func() {
+		start(compressnet.DefaultConfig(), compressnet.DefaultConfig())
+
+		exchange(0, 1, testMessage(1, 100))
+		Expect(links[0].lastSent().GetCompressed()).To(BeNil())
+	})
+
+	It("uses the most preferred algorithm supported by both nodes", func() {
+		start(
+			&compressnet.Config{
+				Algorithms: []messagepb.CompressionAlgorithm{
+					messagepb.CompressionAlgorithm_GZIP,
+					messagepb.CompressionAlgorithm_SNAPPY,
+				},
+				Threshold: 0,
+			},
+			&compressnet.Config{
+				Algorithms: []messagepb.CompressionAlgorithm{messagepb.CompressionAlgorithm_SNAPPY},
+				Threshold:  0,
+			},
+		)
+		negotiate()
+
+		exchange(0, 1, testMessage(1, 100))
+		Expect(links[0].lastSent().GetCompressed().GetAlgorithm()).To(Equal(messagepb.CompressionAlgorithm_SNAPPY))
+	})
+
+	It("delivers messages compressed with gzip", func() {
+		config := &compressnet.Config{
+			Algorithms: []messagepb.CompressionAlgorithm{messagepb.CompressionAlgorithm_GZIP},
+			Threshold:  0,
+		}
+		start(config, config)
+		negotiate()
+
+		exchange(0, 1, testMessage(1, 100))
+		Expect(links[0].lastSent().GetCompressed().GetAlgorithm()).To(Equal(messagepb.CompressionAlgorithm_GZIP))
+	})
+
+	It("never compresses messages to nodes without common algorithms", func() {
+		start(
+			&compressnet.Config{
+				Algorithms: []messagepb.CompressionAlgorithm{messagepb.CompressionAlgorithm_GZIP},
+				Threshold:  0,
+			},
+			&compressnet.Config{
+				Algorithms: []messagepb.CompressionAlgorithm{messagepb.CompressionAlgorithm_SNAPPY},
+				Threshold:  0,
+			},
+		)
+		negotiate()
+
+		exchange(0, 1, testMessage(1, 100))
+		Expect(links[0].lastSent().GetCompressed()).To(BeNil())
+		exchange(1, 0, testMessage(2, 100))
+		Expect(links[1].lastSent().GetCompressed()).To(BeNil())
+	})
+
+	It("communicates with nodes not using compression", func() {
+		start(compressnet.DefaultConfig(), nil)
+
+		// The plain node receives the hello, but never replies.
+		Expect(nets[0].Send(1, testMessage(1, 100))).To(Succeed())
+		var received modules.ReceivedMessage
+		Eventually(nets[1].ReceiveChan()).Should(Receive(&received))
+		Expect(received.Msg.GetCompressionHello()).NotTo(BeNil())
+		Eventually(nets[1].ReceiveChan()).Should(Receive(&received))
+		Expect(proto.Equal(received.Msg, testMessage(1, 100))).To(BeTrue())
+
+		exchange(1, 0, testMessage(2, 100))
+		exchange(0, 1, testMessage(3, 100))
+		Expect(links[0].lastSent().GetCompressed()).To(BeNil())
+	})
+
+	It("drops messages that fail to decompress", func() {
+		start(compressnet.DefaultConfig(), compressnet.DefaultConfig())
+		negotiate()
+
+		Expect(links[0].Send(1, &messagepb.Message{Type: &messagepb.Message_Compressed{
+			Compressed: &messagepb.CompressedMessage{
+				Algorithm: messagepb.CompressionAlgorithm_SNAPPY,
+				Data:      []byte("not snappy"),
+			},
+		}})).To(Succeed())
+		exchange(0, 1, testMessage(1, 1))
+	})
+})
+
+var _ = Describe("Compression test", func() {
+
+	It("delivers all requests when compressing messages", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 100,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Compress messages (the first replica only supports a different algorithm than preferred by the others)
+		// and count the sent compressed messages.
+		// The requests are made large (and compressible) and gossiped, such that they are sent in messages.
+		compressedNets := make([]*compressedCountingNet, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			config := &compressnet.Config{
+				Algorithms: []messagepb.CompressionAlgorithm{
+					messagepb.CompressionAlgorithm_SNAPPY,
+					messagepb.CompressionAlgorithm_GZIP,
+				},
+				Threshold: 512,
+			}
+			if i == 0 {
+				config.Algorithms = []messagepb.CompressionAlgorithm{messagepb.CompressionAlgorithm_GZIP}
+			}
+			replica.FakeRequestPadding = 1024
+			replica.GossipFanout = 1
+			compressedNets[i] = &compressedCountingNet{Net: replica.Net}
+			replica.Net = compressnet.New(compressedNets[i], config, replica.Config.Logger)
+		}
+
+		deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), func() bool {
+			for _, net := range compressedNets {
+				if atomic.LoadUint64(&net.compressed) > 0 {
+					return true
+				}
+			}
+			return false
+		}))
+
+		// Whether a particular replica sends any large message after negotiating compression depends on timing.
+		// Thus, only the total number of compressed messages is checked.
+		compressed := uint64(0)
+		for i, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			compressed += atomic.LoadUint64(&compressedNets[i].compressed)
+		}
+		Expect(compressed).To(BeNumerically(">", 0))
+	})
+})
+
+// compressedCountingNet is a Net module wrapper that counts the sent compressed messages.
+type compressedCountingNet struct {
+	modules.Net
+	compressed uint64
+}
+
+func (cn *compressedCountingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if _, ok := msg.Type.(*messagepb.Message_Compressed); ok {
+		atomic.AddUint64(&cn.compressed, 1)
+	}
+	return cn.Net.Send(dest, msg)
+}
@@ -21,18 +21,27 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"sync"
 )
 
 // Crypto represents an instance of the Crypto module that can be used at Node instantiation
-// (when calling mirbft.NewNode)
+// (when calling mirbft.NewNode).
+// Crypto is safe for concurrent use, such that it can be shared by the Node and other components
+// (e.g., a Net module authenticating messages using node signatures, see authnet.NewSigned).
 type Crypto struct {
 
 	// Private key used for signing.
 	privKey interface{}
 
+	// Protects the public keys below.
+	keysLock sync.RWMutex
+
 	// Node public keys used for verifying signatures.
 	nodeKeys map[t.NodeID]interface{}
 
+	// Previous public keys of the nodes whose keys have been rotated (see RotateNodeKey), until they are retired.
+	previousNodeKeys map[t.NodeID]interface{}
+
 	// Client public keys used for verifying signatures.
 	clientKeys map[t.ClientID]interface{}
 }
@@ -46,9 +55,10 @@ func New(privKey []byte) (*Crypto, error) {
 	if key, err := privKeyFromBytes(privKey); err == nil {
 		// If deserialization succeeds, return the pointer to a new initialized instance of Crypto.
 		return &Crypto{
-			privKey:    key,
-			nodeKeys:   make(map[t.NodeID]interface{}),
-			clientKeys: make(map[t.ClientID]interface{}),
+			privKey:          key,
+			nodeKeys:         make(map[t.NodeID]interface{}),
+			previousNodeKeys: make(map[t.NodeID]interface{}),
+			clientKeys:       make(map[t.ClientID]interface{}),
 		}, nil
 	} else {
 		// Report error if deserialization of the private key fails.
@@ -80,7 +90,9 @@ func (c *Crypto) RegisterNodeKey(pubKey []byte, nodeID t.NodeID) error {
 	// Deserialize passed public key
 	if key, err := pubKeyFromBytes(pubKey); err == nil {
 		// If deserialization succeeds, save public key under the given node ID.
+		c.keysLock.Lock()
 		c.nodeKeys[nodeID] = key
+		c.keysLock.Unlock()
 		return nil
 	} else {
 		// If deserialization fails, report error.
@@ -88,6 +100,35 @@ func (c *Crypto) RegisterNodeKey(pubKey []byte, nodeID t.NodeID) error {
 	}
 }
 
+// RotateNodeKey associates a new public key with a numeric node ID (see modules.NodeKeyRotator).
+// pubKey must be the output of SerializePubKey.
+// Until RetireNodeKey is called for the node, VerifyNodeSig also accepts signatures verifiable with the previous key.
+// Returns nil on success, a non-nil error on failure, in which case the node's keys remain unchanged.
+func (c *Crypto) RotateNodeKey(pubKey []byte, nodeID t.NodeID) error {
+
+	// Deserialize passed public key
+	key, err := pubKeyFromBytes(pubKey)
+	if err != nil {
+		return fmt.Errorf("error parsing node public key: %w", err)
+	}
+
+	// Keep the current key (if any) as the previous one and save the new key under the given node ID.
+	c.keysLock.Lock()
+	defer c.keysLock.Unlock()
+	if previous, ok := c.nodeKeys[nodeID]; ok {
+		c.previousNodeKeys[nodeID] = previous
+	}
+	c.nodeKeys[nodeID] = key
+	return nil
+}
+
+// RetireNodeKey stops accepting the previous key of a node whose key has been rotated using RotateNodeKey.
+func (c *Crypto) RetireNodeKey(nodeID t.NodeID) {
+	c.keysLock.Lock()
+	defer c.keysLock.Unlock()
+	delete(c.previousNodeKeys, nodeID)
+}
+
 // RegisterClientKey associates a public key with a numeric client ID.
 // pubKey must be the output of SerializePubKey.
 // Calls to VerifyClientSig will fail until RegisterClientKey is successfully called with the corresponding client ID.
@@ -97,7 +138,9 @@ func (c *Crypto) RegisterClientKey(pubKey []byte, clientID t.ClientID) error {
 	// Deserialize passed public key
 	if key, err := pubKeyFromBytes(pubKey); err == nil {
 		// If deserialization succeeds, save public key under the given client ID.
+		c.keysLock.Lock()
 		c.clientKeys[clientID] = key
+		c.keysLock.Unlock()
 		return nil
 	} else {
 		// If deserialization fails, report error.
@@ -108,29 +151,42 @@ func (c *Crypto) RegisterClientKey(pubKey []byte, clientID t.ClientID) error {
 // DeleteNodeKey removes the public key associated with nodeID from the internal state.
 // Any subsequent call to VerifyNodeSig(..., nodeID) will fail.
 func (c *Crypto) DeleteNodeKey(nodeID t.NodeID) {
+	c.keysLock.Lock()
+	defer c.keysLock.Unlock()
 	delete(c.nodeKeys, nodeID)
+	delete(c.previousNodeKeys, nodeID)
 }
 
 // DeleteClientKey removes the public key associated with clientID from the state.
 // Any subsequent call to VerifyClientSig(..., clientID) will fail.
 func (c *Crypto) DeleteClientKey(clientID t.ClientID) {
+	c.keysLock.Lock()
+	defer c.keysLock.Unlock()
 	delete(c.clientKeys, clientID)
 }
 
 // VerifyNodeSig verifies a signature produced by the node with numeric ID nodeID over data.
 // First, VerifyNodeSig computes a SHA256 hash of the concatenation of all the byte slices in data.
 // Then it verifies the signature over this hash using the public key registered under nodeID.
+// If the node's key has been rotated and the previous key not yet retired, the previous key is accepted as well.
 // Returns nil on success (i.e., if the given signature is valid) and a non-nil error otherwise.
 // Note that RegisterNodeKey must be used to register the node's public key before calling VerifyNodeSig,
 // otherwise VerifyNodeSig will fail.
 func (c *Crypto) VerifyNodeSig(data [][]byte, signature []byte, nodeID t.NodeID) error {
 
+	c.keysLock.RLock()
 	pubKey, ok := c.nodeKeys[nodeID]
+	previous, rotated := c.previousNodeKeys[nodeID]
+	c.keysLock.RUnlock()
 	if !ok {
 		return fmt.Errorf("no public key for node with ID %d", nodeID)
 	}
 
-	return c.verifySig(data, signature, pubKey)
+	err := c.verifySig(data, signature, pubKey)
+	if rotated && err != nil {
+		return c.verifySig(data, signature, previous)
+	}
+	return err
 }
 
 // VerifyClientSig verifies a signature produced by the client with numeric ID clientID over data.
@@ -141,7 +197,9 @@ func (c *Crypto) VerifyNodeSig(data [][]byte, signature []byte, nodeID t.NodeID)
 // otherwise VerifyClientSig will fail.
 func (c *Crypto) VerifyClientSig(data [][]byte, signature []byte, clientID t.ClientID) error {
 
+	c.keysLock.RLock()
 	pubKey, ok := c.clientKeys[clientID]
+	c.keysLock.RUnlock()
 	if !ok {
 		return fmt.Errorf("no public key for client with ID %d", clientID)
 	}
@@ -0,0 +1,102 @@
+package crypto_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/crypto"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Key generation", func() {
+
+	data := [][]byte{[]byte("some"), []byte("data")}
+
+	It("derives the same keys from the same seed", func() {
+		priv1, pub1, err := crypto.GenerateKeyPair(rand.New(rand.NewSource(1)))
+		Expect(err).NotTo(HaveOccurred())
+		priv2, pub2, err := crypto.GenerateKeyPair(rand.New(rand.NewSource(1)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(priv2).To(Equal(priv1))
+		Expect(pub2).To(Equal(pub1))
+
+		priv3, _, err := crypto.GenerateKeyPair(rand.New(rand.NewSource(2)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(priv3).NotTo(Equal(priv1))
+	})
+
+	It("derives valid keys that interoperate with the standard library", func() {
+		randomness := rand.New(rand.NewSource(42))
+		for i := 0; i < 20; i++ {
+			priv, pub, err := crypto.GenerateKeyPair(randomness)
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed, err := x509.ParsePKCS8PrivateKey(priv)
+			Expect(err).NotTo(HaveOccurred())
+			privKey := parsed.(*ecdsa.PrivateKey)
+			Expect(privKey.D.Sign()).To(Equal(1))
+			Expect(privKey.D.Cmp(elliptic.P256().Params().N)).To(Equal(-1))
+			Expect(elliptic.P256().IsOnCurve(privKey.X, privKey.Y)).To(BeTrue())
+
+			// The public key is the point the standard library computes from the scalar.
+			x, y := elliptic.P256().ScalarBaseMult(privKey.D.Bytes())
+			Expect(elliptic.Marshal(elliptic.P256(), privKey.X, privKey.Y)).To(Equal(elliptic.Marshal(elliptic.P256(), x, y)))
+
+			// Signatures made by the standard library with the derived key verify with the serialized public key.
+			digest := sha256.Sum256([]byte("message"))
+			sig, err := ecdsa.SignASN1(crand.Reader, privKey, digest[:])
+			Expect(err).NotTo(HaveOccurred())
+			parsedPub, err := x509.ParsePKIXPublicKey(pub)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ecdsa.VerifyASN1(parsedPub.(*ecdsa.PublicKey), digest[:], sig)).To(BeTrue())
+		}
+	})
+
+	It("verifies signatures made with derived and standard library keys alike", func() {
+		derivedPriv, derivedPub, err := crypto.GenerateKeyPair(rand.New(rand.NewSource(1)))
+		Expect(err).NotTo(HaveOccurred())
+
+		stdKey, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		stdPriv, err := crypto.SerializePrivKey(stdKey)
+		Expect(err).NotTo(HaveOccurred())
+		stdPub, err := crypto.SerializePubKey(&stdKey.PublicKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		verifier, err := crypto.New(derivedPriv)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verifier.RegisterNodeKey(derivedPub, 0)).To(Succeed())
+		Expect(verifier.RegisterNodeKey(stdPub, 1)).To(Succeed())
+
+		for nodeID, priv := range [][]byte{derivedPriv, stdPriv} {
+			signer, err := crypto.New(priv)
+			Expect(err).NotTo(HaveOccurred())
+			sig, err := signer.Sign(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verifier.VerifyNodeSig(data, sig, t.NodeID(nodeID))).To(Succeed())
+			Expect(verifier.VerifyNodeSig(data, sig, t.NodeID(1-nodeID))).NotTo(Succeed())
+		}
+	})
+
+	It("generates the same keys for all nodes from the same seed", func() {
+		nodes := []t.NodeID{0, 1, 2, 3}
+		clients := []t.ClientID{0}
+		signer, err := crypto.NodePseudo(nodes, clients, 2, crypto.DefaultPseudoSeed)
+		Expect(err).NotTo(HaveOccurred())
+		sig, err := signer.Sign(data)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, id := range nodes {
+			verifier, err := crypto.NodePseudo(nodes, clients, id, crypto.DefaultPseudoSeed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verifier.VerifyNodeSig(data, sig, 2)).To(Succeed())
+		}
+	})
+})
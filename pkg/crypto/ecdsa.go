@@ -73,18 +73,58 @@ func verifyEcdsaSignature(pk *ecdsa.PublicKey, hash []byte, signature []byte) er
 	return nil
 }
 
+// generateEcdsaKeyPair generates a pair of ECDSA keys, using crypto/rand.Reader if randomness is nil.
+// Otherwise, the keys are derived deterministically from randomness (see deriveEcdsaPrivKey).
 func generateEcdsaKeyPair(randomness io.Reader) (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
 
+	// TODO: No clue which curve to use, picked P256 because it was in the documentation example.
+	//       Check whether this is OK.
+	curve := elliptic.P256()
+
 	if randomness == nil {
-		randomness = rand.Reader
+		privKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		return privKey, &privKey.PublicKey, nil
 	}
 
-	// TODO: No clue which curve to use, picked P256 because it was in the documentation example.
-	//       Check whether this is OK.
-	privKey, err := ecdsa.GenerateKey(elliptic.P256(), randomness)
+	privKey, err := deriveEcdsaPrivKey(curve, randomness)
 	if err != nil {
 		return nil, nil, err
 	}
-
 	return privKey, &privKey.PublicKey, nil
 }
+
+// deriveEcdsaPrivKey derives an ECDSA private key deterministically from the given source of randomness.
+// This is necessary for generating the same (pseudo-random) keys from the same seed at all nodes
+// (see NodePseudo and ClientPseudo), since ecdsa.GenerateKey does not derive the key deterministically
+// from its source of randomness (it may read an additional random byte or ignore the source altogether).
+// The private scalar is chosen uniformly from [1, N-1] by testing candidates (FIPS 186-4, Appendix B.4.2):
+// a candidate read from randomness is rejected (and a new one is read) if it is not smaller than N-1,
+// which, unlike reducing the candidate modulo N-1, does not bias the resulting scalar.
+func deriveEcdsaPrivKey(curve elliptic.Curve, randomness io.Reader) (*ecdsa.PrivateKey, error) {
+	params := curve.Params()
+	one := big.NewInt(1)
+	nMinusOne := new(big.Int).Sub(params.N, one)
+	candidate := make([]byte, (params.BitSize+7)/8)
+
+	for {
+		if _, err := io.ReadFull(randomness, candidate); err != nil {
+			return nil, err
+		}
+
+		// Discard the excess bits, such that candidates are rejected with a probability of at most 1/2.
+		if excess := len(candidate)*8 - params.BitSize; excess > 0 {
+			candidate[0] >>= excess
+		}
+
+		d := new(big.Int).SetBytes(candidate)
+		if d.Cmp(nMinusOne) < 0 {
+			d.Add(d, one)
+			privKey := &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: curve}, D: d}
+			privKey.PublicKey.X, privKey.PublicKey.Y = curve.ScalarBaseMult(d.Bytes())
+			return privKey, nil
+		}
+	}
+}
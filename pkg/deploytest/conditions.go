@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploytest
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// AllOf returns a condition that holds when all of the given conditions hold.
+func AllOf(conditions ...func() bool) func() bool {
+	return func() bool {
+		for _, condition := range conditions {
+			if !condition() {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AllProcessed returns a condition that holds when the applications of all replicas of the deployment
+// processed (at least) n requests.
+func (d *Deployment) AllProcessed(n int) func() bool {
+	return Processed(d.TestReplicas, n)
+}
+
+// Processed returns a condition that holds when the applications of all the given replicas
+// processed (at least) n requests.
+func Processed(replicas []*TestReplica, n int) func() bool {
+	return func() bool {
+		for _, replica := range replicas {
+			if replica.App.Processed() < uint64(n) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AllCommitted returns a condition that holds when all the given replicas
+// committed a batch with a sequence number of (at least) sn.
+func AllCommitted(replicas []*TestReplica, sn t.SeqNr) func() bool {
+	return func() bool {
+		for _, replica := range replicas {
+			node := replica.RunningNode()
+			if node == nil {
+				return false
+			}
+			if lastSn, ok := node.LastCommitted(); !ok || lastSn < sn {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AllCheckpointed returns a condition that holds when a checkpoint became stable at all replicas of the deployment.
+func (d *Deployment) AllCheckpointed() func() bool {
+	return func() bool {
+		for _, replica := range d.TestReplicas {
+			if node := replica.RunningNode(); node == nil || node.StableCheckpoint() == nil {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AwaitStableCheckpoint waits until, at all replicas of the deployment,
+// a checkpoint covering all the batches committed so far became stable.
+// As the protocol does not retransmit requests, the replicas can only be restarted after that without losing requests.
+func (d *Deployment) AwaitStableCheckpoint() {
+	lastCommitted := make([]t.SeqNr, len(d.TestReplicas))
+	for i, replica := range d.TestReplicas {
+		lastCommitted[i], _ = replica.Node().LastCommitted()
+	}
+	Eventually(func() bool {
+		for i, replica := range d.TestReplicas {
+			if checkpoint := replica.Node().StableCheckpoint(); checkpoint == nil || t.SeqNr(checkpoint.Sn) <= lastCommitted[i] {
+				return false
+			}
+		}
+		return true
+	}, TestTimeout, PollInterval).Should(BeTrue())
+}
+
+// AllInEpoch returns a condition that holds when all replicas of the deployment reached (at least) epoch e.
+func (d *Deployment) AllInEpoch(e t.EpochNr) func() bool {
+	return func() bool {
+		for _, replica := range d.TestReplicas {
+			node := replica.RunningNode()
+			if node == nil {
+				return false
+			}
+			if info := node.EpochInfo(); info == nil || info.Epoch < e {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AllWatermarksMoved returns a condition that holds when all replicas of the deployment
+// advanced the low watermark of some client, i.e., when requests were committed and garbage-collected.
+func (d *Deployment) AllWatermarksMoved() func() bool {
+	return func() bool {
+		for _, replica := range d.TestReplicas {
+			node := replica.RunningNode()
+			if node == nil {
+				return false
+			}
+			var protocolState struct{ ClientWatermarks []struct{ Low t.ReqNo } }
+			DumpProtocol(node, &protocolState)
+			moved := false
+			for _, watermark := range protocolState.ClientWatermarks {
+				moved = moved || watermark.Low > 0
+			}
+			if !moved {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AllHalted returns a condition that holds when all replicas of the deployment halted.
+func (d *Deployment) AllHalted() func() bool {
+	return func() bool {
+		for _, replica := range d.TestReplicas {
+			if node := replica.Node(); node == nil || !node.Healthy().Halted {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyHalted returns a condition that holds when (at least) one replica of the deployment halted on an error.
+func (d *Deployment) AnyHalted() func() bool {
+	return func() bool {
+		for _, replica := range d.TestReplicas {
+			if node := replica.Node(); node != nil && node.Healthy().Halted {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RunningNode returns the node of the replica if it is running and nil otherwise,
+// e.g., if the node of a restarted replica has not been created yet and the node of its previous run stopped.
+func (tr *TestReplica) RunningNode() *mirbft.Node {
+	if node := tr.Node(); node != nil && !node.Healthy().Halted {
+		return node
+	}
+	return nil
+}
+
+// DumpProtocol unmarshals the protocol state contained in a dump of the state of node (see Node.Dump) into protocolState.
+func DumpProtocol(node *mirbft.Node, protocolState interface{}) {
+	var buf bytes.Buffer
+	Expect(node.Dump(&buf)).To(Succeed())
+	Expect(json.Unmarshal(buf.Bytes(), &mirbft.NodeDump{Protocol: protocolState})).To(Succeed())
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/hyperledger-labs/mirbft/pkg/grpctransport"
 	"github.com/hyperledger-labs/mirbft/pkg/logging"
 	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
 	"path/filepath"
 	"sync"
@@ -44,7 +46,7 @@ type TestConfig struct {
 	NumClients int
 
 	// Type of networking to use.
-	// Current possible values: "fake", "grpc"
+	// Current possible values: "fake", "grpc", "grpc-tls" (gRPC with mutual TLS between the replicas)
 	Transport string
 
 	// The number of requests each client submits during the execution of the deployment.
@@ -59,6 +61,19 @@ type TestConfig struct {
 
 	// Duration after which the test deployment will be asked to shut down.
 	Duration time.Duration
+
+	// If set to true, the replicas use an in-memory WAL instead of one stored on disk.
+	VolatileWAL bool
+
+	// Latency injected into each Sync of the replicas' in-memory storage (WAL and request store),
+	// simulating the latency of fsync. The WAL is only affected if VolatileWAL is set.
+	SyncLatency time.Duration
+
+	// If set to true, the replicas' request stores deduplicate identical request payloads.
+	DedupRequests bool
+
+	// Logger used by all replicas and clients. If nil, warnings and errors are logged to the console.
+	Logger logging.Logger
 }
 
 // The Deployment represents a list of replicas interconnected by a simulated network transport.
@@ -81,7 +96,11 @@ type Deployment struct {
 func NewDeployment(testConfig *TestConfig) (*Deployment, error) {
 
 	// Use a common logger for all clients and replicas.
-	logger := logging.Synchronize(logging.ConsoleDebugLogger)
+	logger := testConfig.Logger
+	if logger == nil {
+		logger = logging.ConsoleWarnLogger
+	}
+	logger = logging.Synchronize(logger)
 
 	// Create a simulated network transport to route messages between replicas.
 	fakeTransport := NewFakeTransport(testConfig.NumReplicas)
@@ -92,6 +111,15 @@ func NewDeployment(testConfig *TestConfig) (*Deployment, error) {
 		membership[i] = t.NodeID(i)
 	}
 
+	// Generate the TLS configurations of the replicas if needed.
+	var tlsConfigs map[t.NodeID]*grpctransport.TLSConfig
+	if testConfig.Transport == "grpc-tls" {
+		var err error
+		if tlsConfigs, err = LocalTLSConfigs(membership); err != nil {
+			return nil, err
+		}
+	}
+
 	// Compute a list of all client IDs.
 	// It consists of all dummy client IDs, plus the "fake" client associated with replicas submitting requests directly
 	clientIDs := []t.ClientID{0} // "Fake" client has always ID 0, others start from 1.
@@ -115,6 +143,24 @@ func NewDeployment(testConfig *TestConfig) (*Deployment, error) {
 			transport = fakeTransport.Link(t.NodeID(i))
 		case "grpc":
 			transport = localGrpcTransport(membership, t.NodeID(i))
+		case "grpc-tls":
+			var err error
+			if transport, err = localTLSGrpcTransport(membership, t.NodeID(i), tlsConfigs[t.NodeID(i)]); err != nil {
+				return nil, err
+			}
+		}
+
+		// Create in-memory storage for the replica, simulating the configured latency.
+		reqStore := reqstore.NewVolatileRequestStore()
+		if testConfig.DedupRequests {
+			reqStore = reqstore.NewDedupVolatileRequestStore()
+		}
+		reqStore.SyncLatency = testConfig.SyncLatency
+		var wal modules.WAL
+		if testConfig.VolatileWAL {
+			volatileWAL := simplewal.NewVolatileWAL()
+			volatileWAL.SyncLatency = testConfig.SyncLatency
+			wal = volatileWAL
 		}
 
 		// Create instance of test replica.
@@ -126,6 +172,8 @@ func NewDeployment(testConfig *TestConfig) (*Deployment, error) {
 			Dir:             filepath.Join(testConfig.Directory, fmt.Sprintf("node%d", i)),
 			App:             &FakeApp{},
 			Net:             transport,
+			ReqStore:        reqStore,
+			WAL:             wal,
 			NumFakeRequests: testConfig.NumFakeRequests,
 		}
 	}
@@ -221,15 +269,27 @@ func (d *Deployment) Run(tickInterval time.Duration, stopC <-chan struct{}) []No
 // Creates an instance of GrpcTransport based on the numeric IDs of test replicas.
 // The network address of each test replica is the loopback 127.0.0.1
 func localGrpcTransport(nodeIds []t.NodeID, ownId t.NodeID) *grpctransport.GrpcTransport {
+	return grpctransport.NewGrpcTransport(localMembership(nodeIds), ownId, nil)
+}
 
-	// Compute network addresses and ports for all test replicas.
-	// Each test replica is on the local machine - 127.0.0.1
+// Creates an instance of GrpcTransport using mutual TLS based on the numeric IDs of test replicas.
+// The network address of each test replica is the loopback 127.0.0.1
+func localTLSGrpcTransport(
+	nodeIds []t.NodeID,
+	ownId t.NodeID,
+	tlsConfig *grpctransport.TLSConfig,
+) (*grpctransport.GrpcTransport, error) {
+	return grpctransport.NewTLSGrpcTransport(localMembership(nodeIds), ownId, tlsConfig, nil)
+}
+
+// Computes network addresses and ports for all test replicas.
+// Each test replica is on the local machine - 127.0.0.1
+func localMembership(nodeIds []t.NodeID) map[t.NodeID]string {
 	membership := make(map[t.NodeID]string, len(nodeIds))
 	for _, id := range nodeIds {
 		membership[id] = fmt.Sprintf("127.0.0.1:%d", BaseListenPort+id)
 	}
-
-	return grpctransport.NewGrpcTransport(membership, ownId, nil)
+	return membership
 }
 
 func (d *Deployment) localRequestReceiverAddrs() map[t.NodeID]string {
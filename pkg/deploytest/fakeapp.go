@@ -9,31 +9,72 @@ package deploytest
 import (
 	"encoding/binary"
 	"fmt"
+	"sync/atomic"
+
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
 )
 
 // FakeApp represents a dummy stub application used for testing only.
 type FakeApp struct {
 
 	// The state of the FakeApp only consists of a counter of processed requests.
+	// It is updated atomically, such that it can be read (see Processed) while the FakeApp is in use.
 	RequestsProcessed uint64
+
+	// If greater than zero, the FakeApp panics when applying the PanicAt-th batch.
+	// Used for simulating application bugs.
+	PanicAt uint64
+
+	// Number of batches applied so far. Not part of the application state.
+	batchesApplied uint64
 }
 
 // Apply
 func (fa *FakeApp) Apply(batch *requestpb.Batch) error {
+	fa.batchesApplied++
+	if fa.batchesApplied == fa.PanicAt {
+		panic(fmt.Sprintf("simulated application bug at batch %d", fa.PanicAt))
+	}
+
 	for range batch.Requests {
-		fa.RequestsProcessed++
-		fmt.Printf("Processed requests: %d\n", fa.RequestsProcessed)
+		fmt.Printf("Processed requests: %d\n", atomic.AddUint64(&fa.RequestsProcessed, 1))
 	}
 	return nil
 }
 
+// Processed returns the number of requests processed so far. It is safe to call while the FakeApp is in use.
+func (fa *FakeApp) Processed() uint64 {
+	return atomic.LoadUint64(&fa.RequestsProcessed)
+}
+
+// PartitionedFakeApp wraps a FakeApp, declaring the requests of different clients commutative
+// (which they are, as the FakeApp only counts them), such that the Node applies them concurrently.
+type PartitionedFakeApp struct {
+	*FakeApp
+}
+
+// ApplyClientRequests counts the requests of a single client. It is safe for concurrent use.
+func (pfa *PartitionedFakeApp) ApplyClientRequests(clientID t.ClientID, reqRefs []*requestpb.RequestRef) error {
+	atomic.AddUint64(&pfa.RequestsProcessed, uint64(len(reqRefs)))
+	return nil
+}
+
 func (fa *FakeApp) Snapshot() ([]byte, error) {
-	return uint64ToBytes(fa.RequestsProcessed), nil
+	return uint64ToBytes(atomic.LoadUint64(&fa.RequestsProcessed)), nil
+}
+
+// Query returns the number of requests processed so far (the state of the FakeApp), regardless of the query.
+func (fa *FakeApp) Query(query []byte) ([]byte, error) {
+	return uint64ToBytes(atomic.LoadUint64(&fa.RequestsProcessed)), nil
 }
 
 func (fa *FakeApp) RestoreState(snapshot []byte) error {
-	return fmt.Errorf("we don't support state transfer in this test (yet)")
+	if len(snapshot) != 8 {
+		return fmt.Errorf("invalid snapshot length: %d", len(snapshot))
+	}
+	atomic.StoreUint64(&fa.RequestsProcessed, binary.LittleEndian.Uint64(snapshot))
+	return nil
 }
 
 func uint64ToBytes(value uint64) []byte {
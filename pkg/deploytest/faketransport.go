@@ -79,7 +79,11 @@ func (ft *FakeTransport) RecvC(dest t.NodeID) <-chan modules.ReceivedMessage {
 	return ft.NodeSinks[int(dest)]
 }
 
+// Start launches the goroutines delivering the sent messages.
+// A FakeTransport that has been stopped can be started again.
 func (ft *FakeTransport) Start() {
+	ft.DoneC = make(chan struct{})
+
 	for i, sourceBuffers := range ft.Buffers {
 		for j, buffer := range sourceBuffers {
 			if i == j {
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploytest
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/serializing"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// ConfigRequest returns a request of the configuration client (see types.ConfigClientID) containing the given change,
+// signed like the requests of the fake client of TestReplicas with the given membership and client IDs.
+func ConfigRequest(
+	membership []t.NodeID,
+	clientIDs []t.ClientID,
+	reqNo t.ReqNo,
+	change *isspb.ConfigChange,
+) *requestpb.Request {
+	cryptoModule, err := mirCrypto.ClientPseudo(membership, clientIDs, t.ConfigClientID, mirCrypto.DefaultPseudoSeed)
+	Expect(err).NotTo(HaveOccurred())
+
+	data, err := proto.Marshal(change)
+	Expect(err).NotTo(HaveOccurred())
+
+	return SignedRequest(cryptoModule, t.ConfigClientID, reqNo, data)
+}
+
+// SignedRequest returns a request of the given client, signed (like the requests of the fake client of TestReplicas)
+// using the given Crypto module.
+func SignedRequest(cryptoModule *mirCrypto.Crypto, clientID t.ClientID, reqNo t.ReqNo, data []byte) *requestpb.Request {
+	request := &requestpb.Request{ClientId: clientID.Pb(), ReqNo: reqNo.Pb(), Data: data}
+
+	h := FakeClientHasher.New()
+	Expect(serializing.WriteRequestForHash(h, request)).To(Succeed())
+	var err error
+	request.Authenticator, err = cryptoModule.Sign([][]byte{h.Sum(nil)})
+	Expect(err).NotTo(HaveOccurred())
+
+	return request
+}
+
+// SubmitToAll submits request to the nodes of all the given replicas and returns the resulting errors.
+func SubmitToAll(replicas []*TestReplica, request *requestpb.Request) []error {
+	errs := make([]error, len(replicas))
+	for i, replica := range replicas {
+		errs[i] = replica.Node().SubmitRequest(
+			context.Background(),
+			t.ClientID(request.ClientId),
+			t.ReqNo(request.ReqNo),
+			request.Data,
+			request.Authenticator,
+		)
+	}
+	return errs
+}
@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploytest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+)
+
+// Parameters of running a Deployment in a test (see RunUntil).
+var (
+	// Interval between two ticks of the replicas.
+	TestTickInterval = 50 * time.Millisecond
+
+	// Maximal time to wait for a condition on the state of the replicas to hold.
+	TestTimeout = 30 * time.Second
+
+	// Interval between two evaluations of a condition on the state of the replicas.
+	PollInterval = 10 * time.Millisecond
+)
+
+// Directories created by NewTestDeployment for the current test. They are removed after the test,
+// unless the test failed, in which case they are moved to failed-test-data for inspection.
+var testDirs []string
+
+// The directories are cleaned up in every test suite using NewTestDeployment.
+var _ = AfterEach(func() {
+	for _, dir := range testDirs {
+		if CurrentGinkgoTestDescription().Failed {
+			retainedDir := filepath.Join("failed-test-data", filepath.Base(dir))
+			os.MkdirAll("failed-test-data", 0777)
+			if err := os.Rename(dir, retainedDir); err == nil {
+				fmt.Printf("Test failed. Moved deployment data to: %s\n", retainedDir)
+				continue
+			}
+		}
+		os.RemoveAll(dir)
+	}
+	testDirs = nil
+})
+
+// NewTestDeployment creates a deployment according to testConfig, failing the current test on an error.
+// If testConfig.Directory is empty, the deployment-generated files are stored in a new temporary directory,
+// to which testConfig.Directory is set. The directory is removed after the test.
+// A directory explicitly named in testConfig is created if necessary and left to the caller to remove,
+// such that later tests can reuse it (e.g., to load the WAL written by a previous test).
+func NewTestDeployment(testConfig *TestConfig) *Deployment {
+	if testConfig.Directory == "" {
+		tmpDir, err := ioutil.TempDir("", "mirbft-deploytest-")
+		Expect(err).NotTo(HaveOccurred())
+		testConfig.Directory = tmpDir
+		testDirs = append(testDirs, tmpDir)
+	} else {
+		Expect(os.MkdirAll(testConfig.Directory, 0777)).To(Succeed())
+	}
+
+	deployment, err := NewDeployment(testConfig)
+	Expect(err).NotTo(HaveOccurred())
+	return deployment
+}
+
+// RunUntil runs the deployment until condition holds (polling it for at most TestTimeout),
+// stops the deployment, and returns the final statuses of its replicas.
+// The deployment is stopped even if condition never holds, in which case the test fails.
+func (d *Deployment) RunUntil(condition func() bool) []NodeStatus {
+	return d.RunUntilThen(condition, func() {})
+}
+
+// RunUntilThen runs the deployment until condition holds (polling it for at most TestTimeout),
+// then invokes action while the deployment is still running, stops the deployment,
+// and returns the final statuses of its replicas.
+func (d *Deployment) RunUntilThen(condition func() bool, action func()) (finalStatuses []NodeStatus) {
+	stopC := make(chan struct{})
+	doneC := make(chan struct{})
+	go func() {
+		defer GinkgoRecover()
+		defer close(doneC)
+		finalStatuses = d.Run(TestTickInterval, stopC)
+	}()
+	defer func() {
+		close(stopC)
+		<-doneC
+	}()
+
+	Eventually(condition, TestTimeout, PollInterval).Should(BeTrue())
+	action()
+	return
+}
+
+// ExpectStopped checks that all replicas exited without an error and that their final statuses are available.
+func ExpectStopped(finalStatuses []NodeStatus) {
+	Expect(finalStatuses).NotTo(BeNil())
+	for _, status := range finalStatuses {
+		if status.ExitErr != mirbft.ErrStopped {
+			Expect(status.ExitErr).NotTo(HaveOccurred())
+		}
+		Expect(status.StatusErr).NotTo(HaveOccurred())
+	}
+}
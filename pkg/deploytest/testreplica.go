@@ -1,12 +1,13 @@
 package deploytest
 
 import (
-	"bytes"
 	"context"
 	"crypto"
 	"fmt"
 	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/authnet"
 	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	"github.com/hyperledger-labs/mirbft/pkg/compressnet"
 	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
 	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
 	"github.com/hyperledger-labs/mirbft/pkg/grpctransport"
@@ -15,6 +16,7 @@ import (
 	"github.com/hyperledger-labs/mirbft/pkg/modules"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	"github.com/hyperledger-labs/mirbft/pkg/reliablenet"
 	"github.com/hyperledger-labs/mirbft/pkg/requestreceiver"
 	"github.com/hyperledger-labs/mirbft/pkg/serializing"
 	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
@@ -56,11 +58,68 @@ type TestReplica struct {
 	// Network transport subsystem.
 	Net modules.Net
 
+	// Crypto module of the replica, e.g., shared with a Net module authenticating messages using node signatures.
+	// If nil, Run creates one using the pseudo-random keys of the membership and the clients (see crypto.NodePseudo).
+	Crypto modules.Crypto
+
 	// Number of simulated requests inserted in the test replica by a hypothetical client.
 	NumFakeRequests int
 
+	// Number of zero bytes appended to the payload of each simulated request, e.g., to make the requests larger.
+	FakeRequestPadding int
+
 	// Configuration of the ISS protocol, if used. If set to nil, the default ISS configuration is assumed.
 	ISSConfig *iss.Config
+
+	// Request store of the replica.
+	// It is kept across multiple invocations of Run, simulating a persistent request store.
+	ReqStore modules.RequestStore
+
+	// Write-ahead log of the replica.
+	// If nil, Run opens a simplewal.WAL in the replica's directory.
+	// Otherwise, the given WAL (e.g. an in-memory simplewal.VolatileWAL) is used
+	// and it is kept across multiple invocations of Run.
+	WAL modules.WAL
+
+	// If set to true, Run creates the replica's node using mirbft.RestartNode,
+	// recovering the state persisted by a previous run of the replica.
+	Restart bool
+
+	// If set to true, the replica's App is wrapped in a PartitionedFakeApp,
+	// such that the requests of different clients are applied concurrently.
+	ApplyByClient bool
+
+	// If positive, the replica disseminates the received requests using gossip with this fanout
+	// (see clients.GossipingSigningTracker).
+	GossipFanout int
+
+	// If not nil, Run calls OnNode with the replica's node right after creating it (before the node is started),
+	// e.g., for subscribing to the node's events.
+	OnNode func(node *mirbft.Node)
+
+	// If not nil, Run calls OnProtocol with the replica's ISS protocol right after creating it,
+	// e.g., for accessing the statistics the protocol maintains.
+	OnProtocol func(protocol *iss.ISS)
+
+	// If not nil, the events of the replica's node are passed to Interceptor,
+	// in addition to being recorded in the replica's event log (e.g., for collecting metrics).
+	Interceptor modules.EventInterceptor
+
+	// If set to true, the replica's ISS protocol is recorded step by step (see eventlog.ProtocolRecorder)
+	// in the file returned by ProtocolLogFile.
+	RecordProtocol bool
+
+	// The node created by the latest invocation of Run (see Node).
+	node     *mirbft.Node
+	nodeLock sync.Mutex
+}
+
+// Node returns the node created by the latest invocation of Run, or nil if Run has not created it yet.
+// It is safe to call while the replica is running, e.g., for waiting until the node reaches some state.
+func (tr *TestReplica) Node() *mirbft.Node {
+	tr.nodeLock.Lock()
+	defer tr.nodeLock.Unlock()
+	return tr.node
 }
 
 // EventLogFile returns the name of the file where the replica's event log is stored.
@@ -68,6 +127,11 @@ func (tr *TestReplica) EventLogFile() string {
 	return filepath.Join(tr.Dir, "eventlog.gz")
 }
 
+// ProtocolLogFile returns the name of the file where the replica's protocol recording is stored (see RecordProtocol).
+func (tr *TestReplica) ProtocolLogFile() string {
+	return filepath.Join(tr.Dir, "protocollog.gz")
+}
+
 // Run initializes all the required modules and starts the test replica.
 // The function blocks until the replica stops.
 // The replica stops when stopC is closed.
@@ -76,11 +140,18 @@ func (tr *TestReplica) EventLogFile() string {
 //   - The error that made the node terminate
 //   - The error that occurred while obtaining the final node status
 func (tr *TestReplica) Run(tickInterval time.Duration, stopC <-chan struct{}) NodeStatus {
+	tr.nodeLock.Lock()
+	tr.node = nil
+	tr.nodeLock.Unlock()
 
-	// Create logical time for the test replica.
+	// Create logical time for the test replica, unless the node generates it itself (see NodeConfig.TickInterval).
 	// (Note that this is not just for testing - production deployment also only uses this form of time.)
-	ticker := time.NewTicker(tickInterval)
-	defer ticker.Stop()
+	var tickC <-chan time.Time
+	if tr.Config.TickInterval <= 0 {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		tickC = ticker.C
+	}
 
 	//// Initialize the request store.
 	//reqStorePath := filepath.Join(tr.TmpDir, "reqstore")
@@ -90,15 +161,21 @@ func (tr *TestReplica) Run(tickInterval time.Duration, stopC <-chan struct{}) No
 	//Expect(err).NotTo(HaveOccurred())
 	//defer reqStore.Close()
 
-	// Initialize the write-ahead log.
-	walPath := filepath.Join(tr.Dir, "wal")
-	err := os.MkdirAll(walPath, 0700)
-	Expect(err).NotTo(HaveOccurred())
-	wal, err := simplewal.Open(walPath)
-	Expect(err).NotTo(HaveOccurred())
-	defer wal.Close()
+	// Initialize the write-ahead log, unless one has been provided.
+	wal := tr.WAL
+	if wal == nil {
+		walPath := filepath.Join(tr.Dir, "wal")
+		err := os.MkdirAll(walPath, 0700)
+		Expect(err).NotTo(HaveOccurred())
+		diskWAL, err := simplewal.Open(walPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer diskWAL.Close()
+		wal = diskWAL
+	}
 
 	// Initialize recording of events.
+	err := os.MkdirAll(tr.Dir, 0700)
+	Expect(err).NotTo(HaveOccurred())
 	file, err := os.Create(tr.EventLogFile())
 	Expect(err).NotTo(HaveOccurred())
 	defer file.Close()
@@ -113,24 +190,77 @@ func (tr *TestReplica) Run(tickInterval time.Duration, stopC <-chan struct{}) No
 		tr.ISSConfig = iss.DefaultConfig(tr.Membership)
 	}
 
-	issProtocol, err := iss.New(tr.Id, tr.ISSConfig, logging.Decorate(tr.Config.Logger, "ISS: "))
-	Expect(err).NotTo(HaveOccurred())
+	// If configured, record the protocol, making it observe the recorder's wall clock.
+	issConfig := tr.ISSConfig
+	var protocolRecorder *eventlog.ProtocolRecorder
+	if tr.RecordProtocol {
+		protocolFile, err := os.Create(tr.ProtocolLogFile())
+		Expect(err).NotTo(HaveOccurred())
+		defer protocolFile.Close()
+		protocolRecorder, err = eventlog.NewProtocolRecorder(tr.Id, protocolFile)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			Expect(protocolRecorder.Stop()).To(Succeed())
+		}()
 
-	cryptoModule, err := mirCrypto.NodePseudo(tr.Membership, tr.ClientIDs, tr.Id, mirCrypto.DefaultPseudoSeed)
+		configCopy := *tr.ISSConfig
+		configCopy.Now = protocolRecorder.Now
+		issConfig = &configCopy
+	}
+
+	issProtocol, err := iss.New(tr.Id, issConfig, logging.Decorate(tr.Config.Logger, "ISS: "))
 	Expect(err).NotTo(HaveOccurred())
+	if tr.OnProtocol != nil {
+		tr.OnProtocol(issProtocol)
+	}
+	var protocol modules.Protocol = issProtocol
+	if protocolRecorder != nil {
+		protocol = protocolRecorder.Wrap(issProtocol)
+	}
+
+	// Use the configured Crypto module or create one.
+	cryptoModule := tr.Crypto
+	if cryptoModule == nil {
+		cryptoModule, err = mirCrypto.NodePseudo(tr.Membership, tr.ClientIDs, tr.Id, mirCrypto.DefaultPseudoSeed)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// Use the App directly or, if configured, let the Node apply the requests of different clients concurrently.
+	var app modules.App = tr.App
+	if tr.ApplyByClient {
+		app = &PartitionedFakeApp{FakeApp: tr.App}
+	}
+
+	// Create the client tracker, gossiping requests if configured.
+	clientTracker := clients.SigningTracker(logging.Decorate(tr.Config.Logger, "CT: "))
+	if tr.GossipFanout > 0 {
+		clientTracker = clients.GossipingSigningTracker(&clients.GossipConfig{
+			OwnID:      tr.Id,
+			Membership: tr.Membership,
+			Fanout:     tr.GossipFanout,
+			MaxSeen:    10000,
+			Seed:       int64(tr.Id),
+		}, logging.Decorate(tr.Config.Logger, "CT: "))
+	}
 
 	// Create the mirbft node for this replica.
-	node, err := mirbft.NewNode(
+	// If the replica is restarting, the node recovers the state it persisted in the previous run.
+	newNode := mirbft.NewNode
+	if tr.Restart {
+		newNode = mirbft.RestartNode
+	}
+	node, err := newNode(
 		tr.Id,
 		tr.Config,
 		&modules.Modules{
 			Net:           tr.Net,
-			App:           tr.App,
+			App:           app,
 			WAL:           wal,
-			ClientTracker: clients.SigningTracker(logging.Decorate(tr.Config.Logger, "CT: ")),
+			RequestStore:  tr.ReqStore,
+			ClientTracker: clientTracker,
 			//Protocol:    ordering.NewDummyProtocol(tr.Config.Logger, tr.Membership, tr.Id),
-			Protocol:    issProtocol,
-			Interceptor: interceptor,
+			Protocol:    protocol,
+			Interceptor: modules.Interceptors(interceptor, tr.Interceptor),
 			//// Use dummy crypto module that only produces signatures
 			//// consisting of a single zero byte and treats those signatures as valid.
 			//Crypto: &mirCrypto.DummyCrypto{DummySig: []byte{0}},
@@ -138,6 +268,12 @@ func (tr *TestReplica) Run(tickInterval time.Duration, stopC <-chan struct{}) No
 		},
 	)
 	Expect(err).NotTo(HaveOccurred())
+	tr.nodeLock.Lock()
+	tr.node = node
+	tr.nodeLock.Unlock()
+	if tr.OnNode != nil {
+		tr.OnNode(node)
+	}
 
 	// Create a RequestReceiver for request coming over the network.
 	requestReceiver := requestreceiver.NewRequestReceiver(node, logging.Decorate(tr.Config.Logger, "ReqRec: "))
@@ -153,16 +289,22 @@ func (tr *TestReplica) Run(tickInterval time.Duration, stopC <-chan struct{}) No
 	go tr.submitFakeRequests(node, stopC, &wg)
 
 	// ATTENTION! This is hacky!
-	// If the test replica used the GRPC transport, initialize the Net module.
+	// If the test replica used the GRPC transport or a Net module wrapper (e.g. the retransmission layer), initialize the Net module.
 	switch transport := tr.Net.(type) {
 	case *grpctransport.GrpcTransport:
 		err := transport.Start()
 		Expect(err).NotTo(HaveOccurred())
 		transport.Connect()
+	case *reliablenet.Net:
+		transport.Start()
+	case *compressnet.Net:
+		transport.Start()
+	case *authnet.Net:
+		transport.Start()
 	}
 
 	// Run the node until it stops and obtain the node's final status.
-	exitErr := node.Run(stopC, ticker.C)
+	exitErr := node.Run(stopC, tickC)
 	fmt.Println("Run returned!")
 
 	finalStatus, statusErr := node.Status(context.Background())
@@ -175,10 +317,16 @@ func (tr *TestReplica) Run(tickInterval time.Duration, stopC <-chan struct{}) No
 	wg.Wait()
 
 	// ATTENTION! This is hacky!
-	// If the test replica used the GRPC transport, stop the Net module.
+	// If the test replica used the GRPC transport or a Net module wrapper (e.g. the retransmission layer), stop the Net module.
 	switch transport := tr.Net.(type) {
 	case *grpctransport.GrpcTransport:
 		transport.Stop()
+	case *reliablenet.Net:
+		transport.Stop()
+	case *compressnet.Net:
+		transport.Stop()
+	case *authnet.Net:
+		transport.Stop()
 	}
 
 	// Return the final node status.
@@ -227,12 +375,12 @@ func (tr *TestReplica) submitFakeRequests(node *mirbft.Node, stopC <-chan struct
 			reqMsg := &requestpb.Request{
 				ClientId: 0,
 				ReqNo:    t.ReqNo(i).Pb(),
-				Data:     []byte(fmt.Sprintf("Request %d", i)),
+				Data:     append([]byte(fmt.Sprintf("Request %d", i)), make([]byte, tr.FakeRequestPadding)...),
 			}
 
 			// Sign (the hash of) the request, adding the signature to the request message.
 			h := FakeClientHasher.New()
-			h.Write(bytes.Join(serializing.RequestForHash(reqMsg), nil))
+			Expect(serializing.WriteRequestForHash(h, reqMsg)).To(Succeed())
 			reqMsg.Authenticator, err = cryptoModule.Sign([][]byte{h.Sum(nil)})
 			Expect(err).NotTo(HaveOccurred())
 
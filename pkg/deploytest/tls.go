@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploytest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/grpctransport"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// LocalTLSConfigs generates a self-signed certificate for each of the given nodes
+// and returns the TLS configurations of the nodes' transports, indexed by node ID.
+func LocalTLSConfigs(nodeIds []t.NodeID) (map[t.NodeID]*grpctransport.TLSConfig, error) {
+
+	// Generate a key pair and a certificate for each node.
+	ownCerts := make(map[t.NodeID]tls.Certificate, len(nodeIds))
+	nodeCerts := make(map[t.NodeID]*x509.Certificate, len(nodeIds))
+	for _, id := range nodeIds {
+		cert, err := selfSignedCertificate(id)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate certificate for node %d: %w", id, err)
+		}
+		ownCerts[id] = cert
+		nodeCerts[id] = cert.Leaf
+	}
+
+	// Each node uses its own certificate and knows the certificates of all nodes.
+	configs := make(map[t.NodeID]*grpctransport.TLSConfig, len(nodeIds))
+	for _, id := range nodeIds {
+		configs[id] = &grpctransport.TLSConfig{
+			Certificate:      ownCerts[id],
+			NodeCertificates: nodeCerts,
+		}
+	}
+	return configs, nil
+}
+
+// selfSignedCertificate generates a new ECDSA key pair and a self-signed certificate for node id,
+// valid for the local loopback address.
+func selfSignedCertificate(id t.NodeID) (tls.Certificate, error) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(id) + 1),
+		Subject:      pkix.Name{CommonName: fmt.Sprintf("node%d", id)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privKey.PublicKey, privKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: privKey, Leaf: leaf}, nil
+}
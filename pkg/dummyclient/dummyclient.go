@@ -111,8 +111,8 @@ func (dc *DummyClient) SubmitRequest(data []byte) error {
 
 	// Compute request hash (for signing).
 	h := dc.hasher.New()
-	for _, data := range serializing.RequestForHash(reqMsg) {
-		h.Write(data)
+	if err := serializing.WriteRequestForHash(h, reqMsg); err != nil {
+		return err
 	}
 
 	// Sign (the hash of) the request, adding the signature to the request object itself.
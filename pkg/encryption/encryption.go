@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package encryption provides authenticated encryption of data persisted by mirbft modules,
+// for deployments that require the persisted state (WAL entries, request data, ...) to be encrypted at rest.
+// The AEAD type implements the encryption itself (using AES-GCM),
+// while the keys are obtained from a pluggable KeyProvider.
+// Each ciphertext records the ID of the key it has been encrypted with,
+// such that the KeyProvider can rotate the key used for new data while old data remains readable.
+//
+// The AEAD is used by the persistent modules (see simplewal.OpenEncrypted and NewRequestStore)
+// and is transparent to the rest of the library.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+)
+
+// KeyProvider provides the symmetric keys used for encrypting and decrypting persisted data.
+// Keys are identified by numeric IDs and must be 16, 24, or 32 bytes long (selecting AES-128, AES-192, or AES-256).
+// A KeyProvider might, for example, obtain the keys from a hardware security module or a key management service.
+type KeyProvider interface {
+
+	// CurrentKey returns the key (and its ID) to be used for encrypting new data.
+	CurrentKey() (keyID uint32, key []byte, err error)
+
+	// Key returns the key with the given ID. It is used for decrypting data that has been encrypted with that key.
+	// A KeyProvider must be able to return all keys that have been used to encrypt data that is still persisted.
+	Key(keyID uint32) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider that always uses a single key (with ID 0).
+type StaticKeyProvider struct {
+	key []byte
+}
+
+// NewStaticKeyProvider returns a new StaticKeyProvider using the given key.
+func NewStaticKeyProvider(key []byte) *StaticKeyProvider {
+	return &StaticKeyProvider{key: key}
+}
+
+// CurrentKey always returns the key of the StaticKeyProvider with key ID 0.
+func (skp *StaticKeyProvider) CurrentKey() (uint32, []byte, error) {
+	return 0, skp.key, nil
+}
+
+// Key returns the key of the StaticKeyProvider if keyID is 0 and an error otherwise.
+func (skp *StaticKeyProvider) Key(keyID uint32) ([]byte, error) {
+	if keyID != 0 {
+		return nil, fmt.Errorf("unknown key ID: %d", keyID)
+	}
+	return skp.key, nil
+}
+
+// Length of the key ID prefix of a ciphertext.
+const keyIDLength = 4
+
+// AEAD encrypts and authenticates data using AES-GCM with keys obtained from a KeyProvider.
+// The output of Seal has the following format:
+// key ID (4 bytes, big endian) | nonce (12 bytes) | encrypted data with authentication tag.
+// The key ID and the nonce are not encrypted, but are authenticated along with the data.
+type AEAD struct {
+	keys KeyProvider
+}
+
+// NewAEAD returns a new AEAD obtaining its keys from the given KeyProvider.
+func NewAEAD(keys KeyProvider) *AEAD {
+	return &AEAD{keys: keys}
+}
+
+// Seal encrypts and authenticates plaintext using the current key of the KeyProvider.
+// The additionalData is authenticated, but neither encrypted nor included in the output.
+// It is used to bind the ciphertext to its context (e.g. its position in the WAL),
+// preventing encrypted data from being moved around in the storage.
+// The same additionalData must be passed to Open when decrypting.
+func (a *AEAD) Seal(plaintext []byte, additionalData []byte) ([]byte, error) {
+
+	// Obtain the current key.
+	keyID, key, err := a.keys.CurrentKey()
+	if err != nil {
+		return nil, fmt.Errorf("could not obtain encryption key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the key ID and a fresh random nonce at the start of the output.
+	output := make([]byte, keyIDLength+gcm.NonceSize(), keyIDLength+gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	binary.BigEndian.PutUint32(output, keyID)
+	nonce := output[keyIDLength:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	// Encrypt the data, authenticating the header (key ID and nonce) along with the additional data.
+	return gcm.Seal(output, nonce, plaintext, authData(output, additionalData)), nil
+}
+
+// Open authenticates and decrypts ciphertext produced by Seal, using the key the ciphertext has been encrypted with.
+// The additionalData must be the same as the one passed to Seal.
+// Open returns an error if the ciphertext has been tampered with.
+func (a *AEAD) Open(ciphertext []byte, additionalData []byte) ([]byte, error) {
+
+	// Parse the key ID and obtain the corresponding key.
+	if len(ciphertext) < keyIDLength {
+		return nil, fmt.Errorf("ciphertext too short: %d bytes", len(ciphertext))
+	}
+	keyID := binary.BigEndian.Uint32(ciphertext)
+	key, err := a.keys.Key(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("could not obtain decryption key %d: %w", keyID, err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split the rest of the ciphertext in nonce and encrypted data.
+	headerLength := keyIDLength + gcm.NonceSize()
+	if len(ciphertext) < headerLength+gcm.Overhead() {
+		return nil, fmt.Errorf("ciphertext too short: %d bytes", len(ciphertext))
+	}
+	header := ciphertext[:headerLength]
+	nonce := ciphertext[keyIDLength:headerLength]
+
+	// Decrypt and authenticate the data.
+	plaintext, err := gcm.Open(nil, nonce, ciphertext[headerLength:], authData(header, additionalData))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt data: %w", err)
+	}
+	return plaintext, nil
+}
+
+// newGCM returns an AES-GCM cipher using the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create block cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("could not create AES-GCM cipher: %w", err)
+	}
+	return gcm, nil
+}
+
+// authData returns the data authenticated (but not encrypted) along with the plaintext,
+// consisting of the ciphertext header and the user-provided additional data.
+func authData(header []byte, additionalData []byte) []byte {
+	data := make([]byte, 0, len(header)+len(additionalData))
+	data = append(data, header...)
+	return append(data, additionalData...)
+}
@@ -0,0 +1,181 @@
+package encryption_test
+
+import (
+	"bytes"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/encryption"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+)
+
+// rotatingKeyProvider is a KeyProvider encrypting with the last of its keys.
+type rotatingKeyProvider struct {
+	keys [][]byte
+}
+
+func (rkp *rotatingKeyProvider) CurrentKey() (uint32, []byte, error) {
+	return uint32(len(rkp.keys) - 1), rkp.keys[len(rkp.keys)-1], nil
+}
+
+func (rkp *rotatingKeyProvider) Key(keyID uint32) ([]byte, error) {
+	if int(keyID) >= len(rkp.keys) {
+		return nil, fmt.Errorf("unknown key ID: %d", keyID)
+	}
+	return rkp.keys[keyID], nil
+}
+
+// testKey returns a 32-byte key filled with b.
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+var _ = Describe("AEAD", func() {
+	var aead *encryption.AEAD
+
+	BeforeEach(func() {
+		aead = encryption.NewAEAD(encryption.NewStaticKeyProvider(testKey(1)))
+	})
+
+	It("decrypts what it encrypted", func() {
+		ciphertext, err := aead.Seal([]byte("plaintext"), []byte("context"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ciphertext).NotTo(ContainSubstring("plaintext"))
+
+		plaintext, err := aead.Open(ciphertext, []byte("context"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plaintext).To(Equal([]byte("plaintext")))
+	})
+
+	It("uses a fresh nonce for each encryption", func() {
+		first, err := aead.Seal([]byte("plaintext"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := aead.Seal([]byte("plaintext"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first).NotTo(Equal(second))
+	})
+
+	It("rejects ciphertexts opened in another context", func() {
+		ciphertext, err := aead.Seal([]byte("plaintext"), []byte("context"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = aead.Open(ciphertext, []byte("other context"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects tampered ciphertexts", func() {
+		ciphertext, err := aead.Seal([]byte("plaintext"), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, i := range []int{0, 4, len(ciphertext) - 1} {
+			tampered := append([]byte{}, ciphertext...)
+			tampered[i] ^= 1
+			_, err = aead.Open(tampered, nil)
+			Expect(err).To(HaveOccurred(), "byte %d", i)
+		}
+	})
+
+	It("rejects truncated ciphertexts", func() {
+		ciphertext, err := aead.Seal([]byte("plaintext"), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, length := range []int{0, 3, 16, len(ciphertext) - 1} {
+			_, err = aead.Open(ciphertext[:length], nil)
+			Expect(err).To(HaveOccurred(), "length %d", length)
+		}
+	})
+
+	It("decrypts data encrypted with previous keys after a key rotation", func() {
+		keys := &rotatingKeyProvider{keys: [][]byte{testKey(1)}}
+		aead = encryption.NewAEAD(keys)
+		old, err := aead.Seal([]byte("old"), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		keys.keys = append(keys.keys, testKey(2))
+		current, err := aead.Seal([]byte("current"), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(aead.Open(old, nil)).To(Equal([]byte("old")))
+		Expect(aead.Open(current, nil)).To(Equal([]byte("current")))
+
+		// Data encrypted with the new key cannot be read with the old key only.
+		_, err = encryption.NewAEAD(encryption.NewStaticKeyProvider(testKey(1))).Open(current, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails with keys of an invalid length", func() {
+		aead = encryption.NewAEAD(encryption.NewStaticKeyProvider([]byte("short")))
+		_, err := aead.Seal([]byte("plaintext"), nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("RequestStore", func() {
+	var (
+		backend  *reqstore.VolatileRequestStore
+		reqStore *encryption.RequestStore
+
+		ref1 = &requestpb.RequestRef{ClientId: 1, ReqNo: 1, Digest: []byte("digest1")}
+		ref2 = &requestpb.RequestRef{ClientId: 1, ReqNo: 2, Digest: []byte("digest2")}
+	)
+
+	BeforeEach(func() {
+		backend = reqstore.NewVolatileRequestStore()
+		reqStore = encryption.NewRequestStore(backend, encryption.NewAEAD(encryption.NewStaticKeyProvider(testKey(1))))
+	})
+
+	It("stores the request data encrypted", func() {
+		Expect(reqStore.PutRequest(ref1, []byte("data1"))).To(Succeed())
+		Expect(reqStore.GetRequest(ref1)).To(Equal([]byte("data1")))
+
+		stored, err := backend.GetRequest(ref1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).NotTo(ContainSubstring("data1"))
+	})
+
+	It("stores the authenticators encrypted", func() {
+		Expect(reqStore.PutAuthenticator(ref1, []byte("auth1"))).To(Succeed())
+		Expect(reqStore.GetAuthenticator(ref1)).To(Equal([]byte("auth1")))
+
+		stored, err := backend.GetAuthenticator(ref1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).NotTo(ContainSubstring("auth1"))
+	})
+
+	It("stores and retrieves batches", func() {
+		Expect(reqStore.StoreBatch([]*requestpb.StoredRequest{
+			{RequestRef: ref1, Data: []byte("data1"), Authenticated: true, Authenticator: []byte("auth1")},
+			{RequestRef: ref2, Data: []byte("data2")},
+		})).To(Succeed())
+
+		Expect(reqStore.GetBatch([]*requestpb.RequestRef{ref2, ref1})).To(Equal([][]byte{
+			[]byte("data2"),
+			[]byte("data1"),
+		}))
+		Expect(reqStore.GetAuthenticator(ref1)).To(Equal([]byte("auth1")))
+		Expect(reqStore.IsAuthenticated(ref1)).To(BeTrue())
+	})
+
+	It("rejects data moved to another request", func() {
+		Expect(reqStore.PutRequest(ref1, []byte("data1"))).To(Succeed())
+		stored, err := backend.GetRequest(ref1)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(backend.PutRequest(ref2, stored)).To(Succeed())
+		_, err = reqStore.GetRequest(ref2)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects request data passed off as an authenticator", func() {
+		Expect(reqStore.PutRequest(ref1, []byte("data1"))).To(Succeed())
+		stored, err := backend.GetRequest(ref1)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(backend.PutAuthenticator(ref1, stored)).To(Succeed())
+		_, err = reqStore.GetAuthenticator(ref1)
+		Expect(err).To(HaveOccurred())
+	})
+})
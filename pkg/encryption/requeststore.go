@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+)
+
+// Tags distinguishing the type of encrypted data, making it impossible to interchange request data and authenticators.
+const (
+	requestDataTag   = byte(0)
+	authenticatorTag = byte(1)
+)
+
+// RequestStore wraps a modules.RequestStore and encrypts all request data and authenticators
+// before passing them to the wrapped RequestStore (and decrypts them when retrieving).
+// All other information (request references, authentication flags) is stored unencrypted.
+// Each ciphertext is bound to the reference of the request it belongs to,
+// such that encrypted data stored under one request reference cannot be passed off as data of another request.
+type RequestStore struct {
+	modules.RequestStore
+
+	// Used for encrypting and decrypting the data.
+	aead *AEAD
+}
+
+// NewRequestStore returns a new RequestStore wrapping the given RequestStore.
+func NewRequestStore(reqStore modules.RequestStore, aead *AEAD) *RequestStore {
+	return &RequestStore{
+		RequestStore: reqStore,
+		aead:         aead,
+	}
+}
+
+// PutRequest encrypts the request data and stores it in the wrapped RequestStore.
+func (rs *RequestStore) PutRequest(reqRef *requestpb.RequestRef, data []byte) error {
+	ciphertext, err := rs.aead.Seal(data, encryptionContext(requestDataTag, reqRef))
+	if err != nil {
+		return fmt.Errorf("could not encrypt request data: %w", err)
+	}
+	return rs.RequestStore.PutRequest(reqRef, ciphertext)
+}
+
+// GetRequest retrieves the request data from the wrapped RequestStore and decrypts it.
+func (rs *RequestStore) GetRequest(reqRef *requestpb.RequestRef) ([]byte, error) {
+	ciphertext, err := rs.RequestStore.GetRequest(reqRef)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rs.aead.Open(ciphertext, encryptionContext(requestDataTag, reqRef))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt request data: %w", err)
+	}
+	return data, nil
+}
+
+// PutAuthenticator encrypts the authenticator and stores it in the wrapped RequestStore.
+func (rs *RequestStore) PutAuthenticator(reqRef *requestpb.RequestRef, auth []byte) error {
+	ciphertext, err := rs.aead.Seal(auth, encryptionContext(authenticatorTag, reqRef))
+	if err != nil {
+		return fmt.Errorf("could not encrypt authenticator: %w", err)
+	}
+	return rs.RequestStore.PutAuthenticator(reqRef, ciphertext)
+}
+
+// GetAuthenticator retrieves the authenticator from the wrapped RequestStore and decrypts it.
+func (rs *RequestStore) GetAuthenticator(reqRef *requestpb.RequestRef) ([]byte, error) {
+	ciphertext, err := rs.RequestStore.GetAuthenticator(reqRef)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := rs.aead.Open(ciphertext, encryptionContext(authenticatorTag, reqRef))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt authenticator: %w", err)
+	}
+	return auth, nil
+}
+
+// StoreBatch encrypts the data and authenticators of all the requests
+// and stores them in the wrapped RequestStore at once.
+func (rs *RequestStore) StoreBatch(requests []*requestpb.StoredRequest) error {
+	encrypted := make([]*requestpb.StoredRequest, len(requests))
+	for i, req := range requests {
+		data, err := rs.aead.Seal(req.Data, encryptionContext(requestDataTag, req.RequestRef))
+		if err != nil {
+			return fmt.Errorf("could not encrypt request data: %w", err)
+		}
+
+		var auth []byte
+		if req.Authenticator != nil {
+			if auth, err = rs.aead.Seal(req.Authenticator, encryptionContext(authenticatorTag, req.RequestRef)); err != nil {
+				return fmt.Errorf("could not encrypt authenticator: %w", err)
+			}
+		}
+
+		encrypted[i] = &requestpb.StoredRequest{
+			RequestRef:    req.RequestRef,
+			Data:          data,
+			Authenticated: req.Authenticated,
+			Authenticator: auth,
+		}
+	}
+	return rs.RequestStore.StoreBatch(encrypted)
+}
+
+// GetBatch retrieves the data of all the requests from the wrapped RequestStore at once and decrypts it.
+func (rs *RequestStore) GetBatch(reqRefs []*requestpb.RequestRef) ([][]byte, error) {
+	ciphertexts, err := rs.RequestStore.GetBatch(reqRefs)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([][]byte, len(ciphertexts))
+	for i, ciphertext := range ciphertexts {
+		if data[i], err = rs.aead.Open(ciphertext, encryptionContext(requestDataTag, reqRefs[i])); err != nil {
+			return nil, fmt.Errorf("could not decrypt request data: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// encryptionContext returns the additional authenticated data used when encrypting data associated with a request.
+// It consists of the type tag, the client ID, the request number, and the request digest.
+func encryptionContext(tag byte, reqRef *requestpb.RequestRef) []byte {
+	context := make([]byte, 17, 17+len(reqRef.Digest))
+	context[0] = tag
+	binary.BigEndian.PutUint64(context[1:], reqRef.ClientId)
+	binary.BigEndian.PutUint64(context[9:], reqRef.ReqNo)
+	return append(context, reqRef.Digest...)
+}
@@ -16,6 +16,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/recordingpb"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
 	"io"
@@ -93,6 +94,10 @@ type Recorder struct {
 	doneC             chan struct{}
 	exitC             chan struct{}
 
+	// Sequence number to be assigned to the next intercepted list of events.
+	// Only accessed by Intercept, which the Node never calls concurrently.
+	nextSeqNo uint64
+
 	exitErr      error
 	exitErrMutex sync.Mutex
 }
@@ -106,6 +111,7 @@ func NewRecorder(nodeID t.NodeID, dest io.Writer, opts ...RecorderOpt) *Recorder
 			return time.Since(startTime).Milliseconds()
 		},
 		compressionLevel: DefaultCompressionLevel,
+		nextSeqNo:        1,
 		eventC:           make(chan eventTime, DefaultBufferSize),
 		doneC:            make(chan struct{}),
 		exitC:            make(chan struct{}),
@@ -132,17 +138,33 @@ func NewRecorder(nodeID t.NodeID, dest io.Writer, opts ...RecorderOpt) *Recorder
 type eventTime struct {
 	events *events.EventList
 	time   int64
+	seqNo  uint64
 }
 
 // Intercept takes an event and enqueues it into the event buffer.
 // If there is no room in the buffer, it blocks.  If draining the buffer
 // to the output stream has completed (successfully or otherwise), Intercept
 // returns an error.
-func (i *Recorder) Intercept(events *events.EventList) error {
+// Each intercepted list of events is tagged with a sequence number,
+// such that the order of the recorded entries can be verified on replay.
+// The events are copied before being enqueued, as the node keeps modifying them
+// (e.g., stripping their follow-up events) while they are being written out.
+// Intercept must not be called concurrently.
+func (i *Recorder) Intercept(eventList *events.EventList) error {
+	seqNo := i.nextSeqNo
+	i.nextSeqNo++
+
+	eventsCopy := &events.EventList{}
+	iter := eventList.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		eventsCopy.PushBack(proto.Clone(event).(*eventpb.Event))
+	}
+
 	select {
 	case i.eventC <- eventTime{
-		events: events,
+		events: eventsCopy,
 		time:   i.timeSource(),
+		seqNo:  seqNo,
 	}:
 		return nil
 	case <-i.exitC:
@@ -187,6 +209,7 @@ func (i *Recorder) run(dest io.Writer) (exitErr error) {
 			NodeId: i.nodeID.Pb(),
 			Time:   eventTime.time,
 			Events: eventTime.events.Slice(),
+			SeqNo:  eventTime.seqNo,
 		})
 	}
 
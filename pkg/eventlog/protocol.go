@@ -0,0 +1,299 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventlog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recordingpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+	"github.com/pkg/errors"
+)
+
+type wallClockOpt func() time.Time
+
+// WallClockOpt overrides the wall clock of a ProtocolRecorder (time.Now by default),
+// e.g., to record the virtual time of a simulation.
+func WallClockOpt(now func() time.Time) RecorderOpt {
+	return wallClockOpt(now)
+}
+
+// ProtocolRecorder records the run of a protocol state machine step by step:
+// each input event applied to the protocol (including received messages and ticks of the logical clock)
+// together with the output events the protocol produced in response.
+// Unlike the Recorder, which records the events the Node dispatches to all its modules,
+// the ProtocolRecorder captures the protocol in isolation, such that a ProtocolReplayer can feed the recorded
+// inputs to a fresh instance of the protocol and check that it produces exactly the recorded outputs.
+//
+// The only input of the protocol that is not an event is the wall clock.
+// To make it reproducible, the protocol must read the wall clock using the recorder's Now method
+// (for ISS, by setting iss.Config.Now), which returns the same time for the whole duration of a step.
+type ProtocolRecorder struct {
+	nodeID t.NodeID
+	now    func() time.Time
+
+	// Serializes the steps with the calls to Now and Stop.
+	lock sync.Mutex
+
+	gzWriter *gzip.Writer
+
+	// Wall clock time of the step being applied, zero outside a step.
+	stepTime time.Time
+
+	// Number of steps and Tick events recorded so far.
+	steps uint64
+	ticks uint64
+
+	// The first error encountered while writing the recording. No steps are recorded after an error.
+	err error
+}
+
+// NewProtocolRecorder returns a new ProtocolRecorder of the protocol of node nodeID,
+// writing the gzip-compressed recording to dest. Of the RecorderOpts, only CompressionLevelOpt and WallClockOpt apply.
+// The recorder starts recording when the protocol is wrapped using Wrap, and must be stopped using Stop.
+func NewProtocolRecorder(nodeID t.NodeID, dest io.Writer, opts ...RecorderOpt) (*ProtocolRecorder, error) {
+	pr := &ProtocolRecorder{
+		nodeID: nodeID,
+		now:    time.Now,
+	}
+
+	compressionLevel := DefaultCompressionLevel
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case compressionLevelOpt:
+			compressionLevel = int(v)
+		case wallClockOpt:
+			pr.now = v
+		}
+	}
+
+	gzWriter, err := gzip.NewWriterLevel(dest, compressionLevel)
+	if err != nil {
+		return nil, err
+	}
+	pr.gzWriter = gzWriter
+
+	return pr, nil
+}
+
+// Wrap returns a Protocol module that applies all events to protocol and records them.
+// The returned module implements modules.MessageValidator and modules.StateDumper,
+// forwarding the calls to protocol if it implements them.
+func (pr *ProtocolRecorder) Wrap(protocol modules.Protocol) modules.Protocol {
+	return &recordedProtocol{protocol: protocol, recorder: pr}
+}
+
+// Now returns the wall clock time to be observed by the protocol:
+// during a step, the time at which the step started, and the current time otherwise.
+func (pr *ProtocolRecorder) Now() time.Time {
+	pr.lock.Lock()
+	defer pr.lock.Unlock()
+
+	if pr.stepTime.IsZero() {
+		return pr.now()
+	}
+	return pr.stepTime
+}
+
+// Stop flushes the recording. It must be called after the protocol has processed its last event.
+// Stop returns the first error encountered while writing the recording, if any.
+func (pr *ProtocolRecorder) Stop() error {
+	pr.lock.Lock()
+	defer pr.lock.Unlock()
+
+	if err := pr.gzWriter.Close(); err != nil && pr.err == nil {
+		pr.err = errors.WithMessage(err, "could not flush protocol recording")
+	}
+	return pr.err
+}
+
+// applyEvent applies event to protocol and records the step.
+// It never fails, as the protocol must advance regardless of the recording.
+// A write error is reported by Stop.
+func (pr *ProtocolRecorder) applyEvent(protocol modules.Protocol, event *eventpb.Event) *events.EventList {
+	pr.lock.Lock()
+	pr.stepTime = pr.now()
+	pr.lock.Unlock()
+
+	// Record the input as it was before the protocol had the chance to modify it.
+	input := proto.Clone(event).(*eventpb.Event)
+	output := protocol.ApplyEvent(event)
+
+	pr.lock.Lock()
+	defer pr.lock.Unlock()
+
+	if _, ok := input.Type.(*eventpb.Event_Tick); ok {
+		pr.ticks++
+	}
+	pr.steps++
+
+	if pr.err == nil {
+		// The step is serialized right away, as the events are modified by further processing.
+		if err := writeSizePrefixedProto(pr.gzWriter, &recordingpb.ProtocolStep{
+			NodeId: pr.nodeID.Pb(),
+			StepNo: pr.steps,
+			Tick:   pr.ticks,
+			Time:   pr.stepTime.UnixNano(),
+			Input:  input,
+			Output: output.Slice(),
+		}); err != nil {
+			pr.err = errors.WithMessagef(err, "could not record protocol step %d", pr.steps)
+		}
+	}
+
+	pr.stepTime = time.Time{}
+	return output
+}
+
+// recordedProtocol is the Protocol module returned by ProtocolRecorder.Wrap.
+type recordedProtocol struct {
+	protocol modules.Protocol
+	recorder *ProtocolRecorder
+}
+
+// ApplyEvent applies event to the wrapped protocol and records the step.
+func (rp *recordedProtocol) ApplyEvent(event *eventpb.Event) *events.EventList {
+	return rp.recorder.applyEvent(rp.protocol, event)
+}
+
+// Status returns the status of the wrapped protocol.
+func (rp *recordedProtocol) Status() (*statuspb.ProtocolStatus, error) {
+	return rp.protocol.Status()
+}
+
+// ValidateMessage validates msg using the wrapped protocol, if it implements modules.MessageValidator,
+// and accepts all messages otherwise.
+func (rp *recordedProtocol) ValidateMessage(from t.NodeID, msg *messagepb.Message) error {
+	if validator, ok := rp.protocol.(modules.MessageValidator); ok {
+		return validator.ValidateMessage(from, msg)
+	}
+	return nil
+}
+
+// DumpState returns the state of the wrapped protocol, if it implements modules.StateDumper, and nil otherwise.
+func (rp *recordedProtocol) DumpState() (interface{}, error) {
+	if dumper, ok := rp.protocol.(modules.StateDumper); ok {
+		return dumper.DumpState()
+	}
+	return nil, nil
+}
+
+// ReadProtocolStep reads the next step of a recording written by a ProtocolRecorder.
+// At the end of the recording, ReadProtocolStep returns io.EOF.
+func (r *Reader) ReadProtocolStep() (*recordingpb.ProtocolStep, error) {
+	step := &recordingpb.ProtocolStep{}
+	err := readSizePrefixedProto(r.source, step, r.buffer)
+	if err == io.EOF {
+		r.gzReader.Close()
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "error reading protocol step")
+	}
+	r.buffer.Reset()
+
+	return step, nil
+}
+
+// DivergenceError is returned by ProtocolReplayer.Replay if the replayed protocol
+// produced different output than the recorded one.
+type DivergenceError struct {
+	Step *recordingpb.ProtocolStep
+
+	// The output produced by the replayed protocol for the input of Step.
+	Output []*eventpb.Event
+}
+
+func (de *DivergenceError) Error() string {
+	return fmt.Sprintf("protocol diverged from the recording at step %d (tick %d): applying %s produced %v, recorded %v",
+		de.Step.StepNo, de.Step.Tick, de.Step.Input, de.Output, de.Step.Output)
+}
+
+// ProtocolReplayer feeds the inputs recorded by a ProtocolRecorder to a fresh instance of the protocol
+// and checks that the protocol produces exactly the recorded outputs.
+// For the outputs to be reproducible, the protocol must read the wall clock using the replayer's Now method
+// (for ISS, by setting iss.Config.Now), which returns the time recorded for the step being replayed.
+type ProtocolReplayer struct {
+	reader *Reader
+
+	// Protects step, as Now may be called concurrently with Replay.
+	lock sync.Mutex
+
+	// The step being replayed or, outside a step, the last replayed step. Nil before the first step.
+	step *recordingpb.ProtocolStep
+}
+
+// NewProtocolReplayer returns a new ProtocolReplayer replaying the steps read from reader.
+func NewProtocolReplayer(reader *Reader) *ProtocolReplayer {
+	return &ProtocolReplayer{reader: reader}
+}
+
+// Now returns the wall clock time recorded for the step being replayed.
+// Before the first step, Now returns the zero time.
+func (pr *ProtocolReplayer) Now() time.Time {
+	pr.lock.Lock()
+	defer pr.lock.Unlock()
+
+	if pr.step == nil {
+		return time.Time{}
+	}
+	return time.Unix(0, pr.step.Time)
+}
+
+// Replay applies all recorded input events to protocol, in the recorded order.
+// Replay returns nil if protocol produced the recorded output at each step
+// and a *DivergenceError describing the first step at which it did not.
+// Replay fails with a different error if the recording cannot be read or is incomplete.
+func (pr *ProtocolReplayer) Replay(protocol modules.Protocol) error {
+	var expectedStepNo uint64 = 1
+	for {
+		step, err := pr.reader.ReadProtocolStep()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.WithMessage(err, "could not read recorded step")
+		}
+
+		if step.StepNo != expectedStepNo {
+			return fmt.Errorf("expected step %d, read step %d (missing steps in the recording)",
+				expectedStepNo, step.StepNo)
+		}
+		expectedStepNo++
+
+		pr.lock.Lock()
+		pr.step = step
+		pr.lock.Unlock()
+
+		output := protocol.ApplyEvent(step.Input).Slice()
+		if !equalEvents(output, step.Output) {
+			return &DivergenceError{Step: step, Output: output}
+		}
+	}
+}
+
+// equalEvents returns true if the two event lists are equal, including the follow-up events.
+func equalEvents(events1, events2 []*eventpb.Event) bool {
+	if len(events1) != len(events2) {
+		return false
+	}
+	for i := range events1 {
+		if !proto.Equal(events1[i], events2[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -19,6 +19,26 @@ type EventList struct {
 	list *list.List
 }
 
+// ListOf returns a new EventList containing the given events, in the given order.
+func ListOf(events ...*eventpb.Event) *EventList {
+	el := &EventList{}
+	for _, event := range events {
+		el.PushBack(event)
+	}
+	return el
+}
+
+// FromPb returns a new EventList containing the events of the given protobuf representation of an event list.
+func FromPb(pb *eventpb.EventList) *EventList {
+	return ListOf(pb.Events...)
+}
+
+// Pb returns the protobuf representation of the list, e.g. for serializing the list.
+// As with Slice, no deep copying of the events is performed.
+func (el *EventList) Pb() *eventpb.EventList {
+	return &eventpb.EventList{Events: el.Slice()}
+}
+
 // Iterator returns a pointer to an EventListIterator object used to iterate over the events in this list,
 // starting from the beginning of the list.
 func (el *EventList) Iterator() *EventListIterator {
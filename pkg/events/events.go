@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package events
 
 import (
+	"time"
+
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
@@ -100,10 +103,14 @@ func HashResult(digest []byte, origin *eventpb.HashOrigin) *eventpb.Event {
 
 // RequestReady returns an event signifying that a new request is ready to be inserted into the protocol state machine.
 // This normally occurs when the request has been received, persisted, authenticated, and an authenticator is available.
-func RequestReady(requestRef *requestpb.RequestRef) *eventpb.Event {
-	return &eventpb.Event{Type: &eventpb.Event_RequestReady{RequestReady: &eventpb.RequestReady{
-		RequestRef: requestRef,
-	}}}
+// The request data is only included in the event for configuration requests (see types.ConfigClientID),
+// as the protocol only interprets the payload of those.
+func RequestReady(requestRef *requestpb.RequestRef, data []byte) *eventpb.Event {
+	requestReady := &eventpb.RequestReady{RequestRef: requestRef}
+	if t.ClientID(requestRef.ClientId) == t.ConfigClientID {
+		requestReady.Data = data
+	}
+	return &eventpb.Event{Type: &eventpb.Event_RequestReady{RequestReady: requestReady}}
 }
 
 // WALAppend returns an event of appending a new entry to the WAL.
@@ -115,6 +122,49 @@ func WALAppend(event *eventpb.Event, retentionIndex t.WALRetIndex) *eventpb.Even
 	}}}
 }
 
+// UpdateClientKeys returns an event making the Crypto module register the given client public keys
+// (or delete the keys of the clients for which the key is empty).
+func UpdateClientKeys(clientKeys []*isspb.ClientKey) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_UpdateClientKeys{UpdateClientKeys: &eventpb.UpdateClientKeys{
+		ClientKeys: clientKeys,
+	}}}
+}
+
+// UpdateNodeKeys returns an event making the Crypto module associate the given new public keys with the nodes,
+// while still accepting the nodes' previous keys until a RetireNodeKeys event for the nodes.
+func UpdateNodeKeys(nodeKeys []*isspb.NodeKey) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_UpdateNodeKeys{UpdateNodeKeys: &eventpb.UpdateNodeKeys{
+		NodeKeys: nodeKeys,
+	}}}
+}
+
+// RetireNodeKeys returns an event making the Crypto module stop accepting the previous keys of the given nodes.
+func RetireNodeKeys(nodeIDs []t.NodeID) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_RetireNodeKeys{RetireNodeKeys: &eventpb.RetireNodeKeys{
+		NodeIds: t.NodeIDSlicePb(nodeIDs),
+	}}}
+}
+
+// StepDown returns an event making the protocol hand off the segments this node leads (see Node.StepDown).
+func StepDown() *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_StepDown{StepDown: &eventpb.StepDown{}}}
+}
+
+// LocalParams returns an event adjusting the local parameters of the protocol (see Node.Reconfigure).
+func LocalParams(maxProposeDelay int) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_LocalParams{LocalParams: &eventpb.LocalParams{
+		MaxProposeDelay: uint64(maxProposeDelay),
+	}}}
+}
+
+// WALTruncate returns an event of truncating the WAL,
+// i.e., removing all entries appended with a retention index smaller than retentionIndex.
+func WALTruncate(retentionIndex t.WALRetIndex) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_WalTruncate{WalTruncate: &eventpb.WALTruncate{
+		RetentionIndex: retentionIndex.Pb(),
+	}}}
+}
+
 // WALEntry returns an event of reading an entry from the WAL.
 // Those events are used at system initialization.
 func WALEntry(persistedEvent *eventpb.Event, retentionIndex t.WALRetIndex) *eventpb.Event {
@@ -160,6 +210,25 @@ func StoreVerifiedRequest(reqRef *requestpb.RequestRef, data []byte, authenticat
 	}}}
 }
 
+// PruneRequests returns an event representing the protocol module asking the request store
+// to delete all data stored for requests of client clientID with request numbers lower than belowReqNo.
+// The protocol emits this event once those requests have been committed and are not needed any more.
+func PruneRequests(clientID t.ClientID, belowReqNo t.ReqNo) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_PruneRequests{PruneRequests: &eventpb.PruneRequests{
+		ClientId:   clientID.Pb(),
+		BelowReqNo: belowReqNo.Pb(),
+	}}}
+}
+
+// ForwardRequests returns an event instructing the request store module to send the stored requests
+// (including their authenticators) referenced by requestRefs to the given destination nodes.
+func ForwardRequests(requestRefs []*requestpb.RequestRef, destinations []t.NodeID) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_ForwardRequests{ForwardRequests: &eventpb.ForwardRequests{
+		RequestRefs:  requestRefs,
+		Destinations: t.NodeIDSlicePb(destinations),
+	}}}
+}
+
 // AppSnapshotRequest returns an event representing the protocol module asking the application for a state snapshot.
 // sn is the number of batches delivered to the application when taking the snapshot
 // (i.e. the sequence number of the first unprocessed batch).
@@ -182,6 +251,229 @@ func AppSnapshot(sn t.SeqNr, data []byte) *eventpb.Event {
 	}}}
 }
 
+// AppRestoreState returns an event representing the protocol module asking the application
+// for restoring its state from the snapshot data.
+// This event is used, for example, when a node restarts and the protocol module recovers
+// a stable checkpoint from the WAL, in which case the application needs to be brought to the checkpoint's state.
+func AppRestoreState(data []byte) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_AppRestoreState{AppRestoreState: &eventpb.AppRestoreState{
+		Data: data,
+	}}}
+}
+
+// AppQuery returns an event instructing the application to execute the read-only query of read readID
+// against its current state. As the event is processed in order with the delivered batches,
+// the query observes the effects of exactly the batches delivered before it.
+func AppQuery(readID uint64, query []byte) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_AppQuery{AppQuery: &eventpb.AppQuery{
+		ReadId: readID,
+		Query:  query,
+	}}}
+}
+
+// AppQueryResult returns an event representing the result of the read-only query of read readID.
+// If the query failed, err describes the failure and result is ignored.
+func AppQueryResult(readID uint64, result []byte, err error) *eventpb.Event {
+	queryResult := &eventpb.AppQueryResult{
+		ReadId: readID,
+		Result: result,
+	}
+	if err != nil {
+		queryResult.Error = err.Error()
+	}
+	return &eventpb.Event{Type: &eventpb.Event_AppQueryResult{AppQueryResult: queryResult}}
+}
+
+// PoisonedBatch returns an event representing the failure of the application to apply a batch.
+// It is produced by the Node when the application panics while applying the batch with sequence number sn,
+// reason containing a description of the panic. The Node halts as soon as it encounters this event.
+func PoisonedBatch(sn t.SeqNr, batch *requestpb.Batch, reason string) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_PoisonedBatch{PoisonedBatch: &eventpb.PoisonedBatch{
+		Sn:     sn.Pb(),
+		Batch:  batch,
+		Reason: reason,
+	}}}
+}
+
+// RequestTooLarge returns an event recording the rejection of request reqNo of client clientID,
+// whose payload of size bytes exceeds the maximal request size maxSize.
+// If the request has been forwarded by another node (rather than submitted locally), forwardedBy is not nil.
+func RequestTooLarge(clientID t.ClientID, reqNo t.ReqNo, size int, maxSize int, forwardedBy *t.NodeID) *eventpb.Event {
+	rtl := &eventpb.RequestTooLarge{
+		ClientId: clientID.Pb(),
+		ReqNo:    reqNo.Pb(),
+		Size:     uint64(size),
+		MaxSize:  uint64(maxSize),
+	}
+	if forwardedBy != nil {
+		rtl.Forwarded = true
+		rtl.ForwardedBy = forwardedBy.Pb()
+	}
+	return &eventpb.Event{Type: &eventpb.Event_RequestTooLarge{RequestTooLarge: rtl}}
+}
+
+// MessageTooLarge returns an event recording that a message of the given size received from node from
+// has been dropped, as it exceeds the maximal message size maxSize.
+func MessageTooLarge(from t.NodeID, size int, maxSize int) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_MessageTooLarge{MessageTooLarge: &eventpb.MessageTooLarge{
+		From:    from.Pb(),
+		Size:    uint64(size),
+		MaxSize: uint64(maxSize),
+	}}}
+}
+
+// ForwardedRequest returns an event representing the reception of a request forwarded by node from.
+func ForwardedRequest(from t.NodeID, request *requestpb.Request) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_ForwardedRequest{ForwardedRequest: &eventpb.ForwardedRequest{
+		From:    from.Pb(),
+		Request: request,
+	}}}
+}
+
+// PeerHealth returns an event reporting that node nodeID became reachable or unreachable.
+// If the node is unreachable, err is the error that occurred when last sending a message to it.
+func PeerHealth(nodeID t.NodeID, reachable bool, err error) *eventpb.Event {
+	ph := &eventpb.PeerHealth{
+		NodeId:    nodeID.Pb(),
+		Reachable: reachable,
+	}
+	if err != nil {
+		ph.Error = err.Error()
+	}
+	return &eventpb.Event{Type: &eventpb.Event_PeerHealth{PeerHealth: ph}}
+}
+
+// SendQueueOverflow returns an event recording that a message to node dest has been dropped
+// because the outbound queue of dest was full. critical indicates whether the dropped message was critical.
+func SendQueueOverflow(dest t.NodeID, critical bool) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_SendQueueOverflow{SendQueueOverflow: &eventpb.SendQueueOverflow{
+		Destination: dest.Pb(),
+		Critical:    critical,
+	}}}
+}
+
+// EpochStarted returns a notification about the start of epoch epoch with the given leaders.
+// bucketLeaders contains the leader each bucket is assigned to in the epoch, indexed by bucket ID.
+func EpochStarted(epoch t.EpochNr, leaders []t.NodeID, bucketLeaders []t.NodeID) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_EpochStarted{
+		EpochStarted: &eventpb.EpochStarted{
+			Epoch:         epoch.Pb(),
+			Leaders:       t.NodeIDSlicePb(leaders),
+			BucketLeaders: t.NodeIDSlicePb(bucketLeaders),
+		},
+	}})
+}
+
+// CheckpointStable returns a notification about a new stable checkpoint of epoch epoch
+// covering the first sn sequence numbers, with the associated snapshot of the application state.
+func CheckpointStable(epoch t.EpochNr, sn t.SeqNr, appSnapshot []byte) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_CheckpointStable{
+		CheckpointStable: &eventpb.CheckpointStable{
+			Epoch:       epoch.Pb(),
+			Sn:          sn.Pb(),
+			AppSnapshot: appSnapshot,
+		},
+	}})
+}
+
+// ClientWindowMoved returns a notification about the low watermark of client clientID advancing to lowWatermark.
+func ClientWindowMoved(clientID t.ClientID, lowWatermark t.ReqNo) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_ClientWindowMoved{
+		ClientWindowMoved: &eventpb.ClientWindowMoved{
+			ClientId:     clientID.Pb(),
+			LowWatermark: lowWatermark.Pb(),
+		},
+	}})
+}
+
+// NodeSuspected returns a notification about node nodeID being suspected in epoch epoch.
+func NodeSuspected(nodeID t.NodeID, epoch t.EpochNr) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_NodeSuspected{
+		NodeSuspected: &eventpb.NodeSuspected{
+			NodeId: nodeID.Pb(),
+			Epoch:  epoch.Pb(),
+		},
+	}})
+}
+
+// Equivocation returns a notification about node nodeID having sent conflicting messages of type messageType
+// concerning sequence number sn in epoch epoch.
+func Equivocation(nodeID t.NodeID, epoch t.EpochNr, sn t.SeqNr, messageType string) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_Equivocation{
+		Equivocation: &eventpb.Equivocation{
+			NodeId:      nodeID.Pb(),
+			Epoch:       epoch.Pb(),
+			Sn:          sn.Pb(),
+			MessageType: messageType,
+		},
+	}})
+}
+
+// NodeSlow returns a notification about node nodeID lagging behind the stable checkpoint at sequence number stableSN
+// in epoch epoch, the latest checkpoint the node confirmed being at sequence number checkpointSN.
+// If caughtUp is set, the notification announces that the node, previously reported as slow, caught up.
+func NodeSlow(nodeID t.NodeID, epoch t.EpochNr, stableSN t.SeqNr, checkpointSN t.SeqNr, caughtUp bool) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_NodeSlow{
+		NodeSlow: &eventpb.NodeSlow{
+			NodeId:       nodeID.Pb(),
+			Epoch:        epoch.Pb(),
+			StableSn:     stableSN.Pb(),
+			CheckpointSn: checkpointSN.Pb(),
+			CaughtUp:     caughtUp,
+		},
+	}})
+}
+
+// SlowPersist returns a notification about the persistence operation operation (e.g. syncing the WAL)
+// having taken duration d, exceeding threshold.
+func SlowPersist(operation string, d time.Duration, threshold time.Duration) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_SlowPersist{
+		SlowPersist: &eventpb.SlowPersist{
+			Operation:   operation,
+			DurationUs:  uint64(d.Microseconds()),
+			ThresholdUs: uint64(threshold.Microseconds()),
+		},
+	}})
+}
+
+// Oddity returns a notification about suspicious behavior of the given kind observed of node nodeID at the given time
+// (Unix time in milliseconds), with the given description.
+func Oddity(nodeID t.NodeID, kind string, description string, timestamp int64) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_Oddity{
+		Oddity: &eventpb.Oddity{
+			NodeId:      nodeID.Pb(),
+			Kind:        kind,
+			Description: description,
+			Timestamp:   timestamp,
+		},
+	}})
+}
+
+// ConfigChanged returns a notification about configuration changes taking effect with epoch epoch,
+// resulting in the given membership and learners, and rotating the keys of the given nodes and clients.
+func ConfigChanged(
+	epoch t.EpochNr,
+	membership []t.NodeID,
+	learners []t.NodeID,
+	rotatedNodes []t.NodeID,
+	updatedClients []t.ClientID,
+) *eventpb.Event {
+	return notification(&eventpb.Notification{Type: &eventpb.Notification_ConfigChanged{
+		ConfigChanged: &eventpb.ConfigChanged{
+			Epoch:          epoch.Pb(),
+			Membership:     t.NodeIDSlicePb(membership),
+			Learners:       t.NodeIDSlicePb(learners),
+			RotatedNodes:   t.NodeIDSlicePb(rotatedNodes),
+			UpdatedClients: t.ClientIDSlicePb(updatedClients),
+		},
+	}})
+}
+
+// notification returns an event containing the given notification.
+func notification(n *eventpb.Notification) *eventpb.Event {
+	return &eventpb.Event{Type: &eventpb.Event_Notification{Notification: n}}
+}
+
 // ============================================================
 // DUMMY EVENTS FOR TESTING PURPOSES ONLY.
 // ============================================================
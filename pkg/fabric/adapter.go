@@ -0,0 +1,272 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fabric contains an adapter that makes it possible to use mirbft as a Hyperledger Fabric ordering service.
+// The Adapter is an App (to be passed to a mirbft.Node in the modules.Modules structure)
+// that interprets the data of each request as a Fabric envelope
+// and groups the delivered (totally ordered) envelopes in Fabric-style blocks.
+// A block is formed by the envelopes of one or more request batches delivered by mirbft,
+// subject to the BatchSize limits, analogous to the block cutter of the Fabric ordering service.
+// Config transactions (as recognized by a user-provided EnvelopeClassifier) are always placed in a block of their own.
+//
+// As opposed to the Fabric ordering service, the Adapter does not use a timeout to cut blocks,
+// since all nodes must cut exactly the same blocks. Instead, pending envelopes are cut into a block
+// after a configurable number of delivered batches (see Config.MaxBatchesPerBlock).
+package fabric
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/fabricpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+)
+
+// EnvelopeClassifier decides whether an envelope is a Fabric config transaction.
+// It must be deterministic, as all nodes must classify the same envelope the same way.
+// If the classifier returns an error, the application of the whole batch fails.
+type EnvelopeClassifier func(envelope []byte) (isConfig bool, err error)
+
+// BlockConsumer receives the blocks produced by the Adapter, in order of increasing block numbers.
+type BlockConsumer interface {
+
+	// DeliverBlock is called by the Adapter each time a new block is cut.
+	// If DeliverBlock returns an error, the application of the current batch fails.
+	DeliverBlock(block *fabricpb.Block) error
+}
+
+// Config holds the parameters of the Adapter.
+type Config struct {
+
+	// Limits on the size of blocks.
+	BatchSize BatchSize
+
+	// Number of batches delivered by mirbft after which the pending envelopes are cut into a block,
+	// even if the block is not full. With value 1, all pending envelopes are cut at the end of each batch.
+	// This parameter replaces the BatchTimeout of the Fabric ordering service.
+	MaxBatchesPerBlock int
+}
+
+// DefaultConfig returns a default Adapter configuration,
+// with the BatchSize limits set to the defaults of the Fabric ordering service.
+func DefaultConfig() *Config {
+	return &Config{
+		BatchSize: BatchSize{
+			MaxMessageCount:   500,
+			PreferredMaxBytes: 2 * 1024 * 1024,
+		},
+		MaxBatchesPerBlock: 1,
+	}
+}
+
+// CheckConfig checks whether the given configuration satisfies all necessary constraints.
+func CheckConfig(c *Config) error {
+	if c.BatchSize.MaxMessageCount <= 0 {
+		return fmt.Errorf("non-positive MaxMessageCount: %d", c.BatchSize.MaxMessageCount)
+	}
+	if c.BatchSize.PreferredMaxBytes <= 0 {
+		return fmt.Errorf("non-positive PreferredMaxBytes: %d", c.BatchSize.PreferredMaxBytes)
+	}
+	if c.MaxBatchesPerBlock <= 0 {
+		return fmt.Errorf("non-positive MaxBatchesPerBlock: %d", c.MaxBatchesPerBlock)
+	}
+	return nil
+}
+
+// Adapter implements the modules.App interface and produces Fabric-style blocks from the delivered request batches.
+type Adapter struct {
+
+	// Adapter configuration.
+	config *Config
+
+	// Request store from which the envelopes (i.e., the request data) are retrieved.
+	reqStore modules.RequestStore
+
+	// Hasher used to compute the block data hashes and the hash chain.
+	hasher modules.Hasher
+
+	// Function recognizing config transactions.
+	classify EnvelopeClassifier
+
+	// Recipient of the produced blocks.
+	consumer BlockConsumer
+
+	// Groups the envelopes in blocks.
+	cutter *blockCutter
+
+	// Number of batches delivered since the oldest pending envelope has been delivered.
+	pendingBatches int
+
+	// Number of the next block to be cut.
+	nextBlockNumber uint64
+
+	// Hash of the header of the last cut block (nil before cutting the first block).
+	previousHash []byte
+}
+
+// NewAdapter returns a new Adapter that is ready to be used as the App module of a mirbft.Node.
+// The request store must be the same as the one used by the Node.
+// The produced blocks are delivered to consumer.
+func NewAdapter(
+	config *Config,
+	reqStore modules.RequestStore,
+	hasher modules.Hasher,
+	classify EnvelopeClassifier,
+	consumer BlockConsumer,
+) (*Adapter, error) {
+
+	// Check whether the passed configuration is valid.
+	if err := CheckConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid Fabric adapter configuration: %w", err)
+	}
+
+	return &Adapter{
+		config:          config,
+		reqStore:        reqStore,
+		hasher:          hasher,
+		classify:        classify,
+		consumer:        consumer,
+		cutter:          newBlockCutter(config.BatchSize),
+		pendingBatches:  0,
+		nextBlockNumber: 0,
+		previousHash:    nil,
+	}, nil
+}
+
+// Apply feeds the envelopes of all requests in the batch to the block cutter and delivers the resulting blocks.
+// After processing the batch, if pending envelopes have been waiting for MaxBatchesPerBlock batches,
+// they are cut into a block as well.
+func (a *Adapter) Apply(batch *requestpb.Batch) error {
+
+	// Retrieve all the envelopes of the batch from the request store at once.
+	batchEnvelopes, err := a.reqStore.GetBatch(batch.Requests)
+	if err != nil {
+		return fmt.Errorf("could not retrieve envelopes of batch: %w", err)
+	}
+
+	for i, reqRef := range batch.Requests {
+		envelope := batchEnvelopes[i]
+
+		// Check whether the envelope is a config transaction.
+		isConfig, err := a.classify(envelope)
+		if err != nil {
+			return fmt.Errorf("could not classify envelope (client %d, reqNo %d): %w",
+				reqRef.ClientId, reqRef.ReqNo, err)
+		}
+
+		if isConfig {
+			// A config transaction first cuts all pending envelopes and then forms a block on its own.
+			if err := a.cutBlock(a.cutter.Cut(), false); err != nil {
+				return err
+			}
+			if err := a.cutBlock([][]byte{envelope}, true); err != nil {
+				return err
+			}
+		} else {
+			// Other envelopes are handled by the block cutter.
+			for _, envelopes := range a.cutter.Ordered(envelope) {
+				if err := a.cutBlock(envelopes, false); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// If envelopes are still pending, count this batch and cut them if they have waited long enough.
+	if a.cutter.Pending() > 0 {
+		a.pendingBatches++
+		if a.pendingBatches >= a.config.MaxBatchesPerBlock {
+			if err := a.cutBlock(a.cutter.Cut(), false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Snapshot returns a binary representation of the Adapter state,
+// including the envelopes that have been delivered, but not yet included in a block.
+func (a *Adapter) Snapshot() ([]byte, error) {
+	return proto.Marshal(&fabricpb.AdapterState{
+		NextBlockNumber: a.nextBlockNumber,
+		PreviousHash:    a.previousHash,
+		Pending:         a.cutter.pending,
+		PendingBatches:  uint64(a.pendingBatches),
+	})
+}
+
+// RestoreState restores the Adapter state from the output of Snapshot.
+func (a *Adapter) RestoreState(snapshot []byte) error {
+	state := &fabricpb.AdapterState{}
+	if err := proto.Unmarshal(snapshot, state); err != nil {
+		return fmt.Errorf("could not unmarshal Fabric adapter state: %w", err)
+	}
+
+	a.nextBlockNumber = state.NextBlockNumber
+	a.previousHash = state.PreviousHash
+	a.pendingBatches = int(state.PendingBatches)
+	a.cutter = newBlockCutter(a.config.BatchSize)
+	for _, envelope := range state.Pending {
+		a.cutter.pending = append(a.cutter.pending, envelope)
+		a.cutter.pendingBytes += len(envelope)
+	}
+
+	return nil
+}
+
+// cutBlock creates a new block containing the given envelopes, links it to the hash chain,
+// and delivers it to the consumer. If the list of envelopes is empty, cutBlock does nothing.
+func (a *Adapter) cutBlock(envelopes [][]byte, config bool) error {
+	if len(envelopes) == 0 {
+		return nil
+	}
+
+	// Create block.
+	block := &fabricpb.Block{
+		Number:       a.nextBlockNumber,
+		PreviousHash: a.previousHash,
+		DataHash:     a.dataHash(envelopes),
+		Envelopes:    envelopes,
+		Config:       config,
+	}
+
+	// Update the hash chain.
+	a.nextBlockNumber++
+	a.previousHash = a.headerHash(block)
+
+	// All envelopes have been cut, reset the batch counter.
+	a.pendingBatches = 0
+
+	// Deliver the block.
+	if err := a.consumer.DeliverBlock(block); err != nil {
+		return fmt.Errorf("could not deliver block %d: %w", block.Number, err)
+	}
+	return nil
+}
+
+// dataHash computes the hash of the concatenation of the given envelopes.
+func (a *Adapter) dataHash(envelopes [][]byte) []byte {
+	h := a.hasher.New()
+	for _, envelope := range envelopes {
+		h.Write(envelope)
+	}
+	return h.Sum(nil)
+}
+
+// headerHash computes the hash of the block header, i.e., of the block number, previous hash, and data hash.
+func (a *Adapter) headerHash(block *fabricpb.Block) []byte {
+	numberBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(numberBytes, block.Number)
+
+	h := a.hasher.New()
+	h.Write(numberBytes)
+	h.Write(block.PreviousHash)
+	h.Write(block.DataHash)
+	return h.Sum(nil)
+}
@@ -0,0 +1,114 @@
+package fabric_test
+
+import (
+	"bytes"
+	"crypto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/fabric"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/fabricpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+type blockCollector struct {
+	blocks []*fabricpb.Block
+}
+
+func (bc *blockCollector) DeliverBlock(block *fabricpb.Block) error {
+	bc.blocks = append(bc.blocks, block)
+	return nil
+}
+
+// Envelopes starting with "config" are considered config transactions.
+func isConfig(envelope []byte) (bool, error) {
+	return bytes.HasPrefix(envelope, []byte("config")), nil
+}
+
+var _ = Describe("Adapter", func() {
+	var (
+		reqStore  *reqstore.VolatileRequestStore
+		collector *blockCollector
+		adapter   *fabric.Adapter
+		nextReqNo t.ReqNo
+	)
+
+	// batch stores the given envelopes as requests in the request store and returns a batch referencing them.
+	batch := func(envelopes ...string) *requestpb.Batch {
+		b := &requestpb.Batch{}
+		for _, envelope := range envelopes {
+			ref := &requestpb.RequestRef{ClientId: 0, ReqNo: nextReqNo.Pb(), Digest: []byte(envelope)}
+			nextReqNo++
+			Expect(reqStore.PutRequest(ref, []byte(envelope))).To(Succeed())
+			b.Requests = append(b.Requests, ref)
+		}
+		return b
+	}
+
+	BeforeEach(func() {
+		reqStore = reqstore.NewVolatileRequestStore()
+		collector = &blockCollector{}
+		nextReqNo = 0
+
+		var err error
+		adapter, err = fabric.NewAdapter(
+			&fabric.Config{
+				BatchSize:          fabric.BatchSize{MaxMessageCount: 3, PreferredMaxBytes: 10},
+				MaxBatchesPerBlock: 2,
+			},
+			reqStore,
+			crypto.SHA256,
+			isConfig,
+			collector,
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("cuts blocks by message count and isolates config transactions", func() {
+		Expect(adapter.Apply(batch("a", "b", "c", "d", "config1", "e"))).To(Succeed())
+
+		Expect(collector.blocks).To(HaveLen(3))
+		Expect(collector.blocks[0].Envelopes).To(Equal([][]byte{[]byte("a"), []byte("b"), []byte("c")}))
+		Expect(collector.blocks[1].Envelopes).To(Equal([][]byte{[]byte("d")}))
+		Expect(collector.blocks[2].Envelopes).To(Equal([][]byte{[]byte("config1")}))
+		Expect(collector.blocks[2].Config).To(BeTrue())
+
+		// "e" is pending and is only cut after the second batch.
+		Expect(adapter.Apply(batch())).To(Succeed())
+		Expect(collector.blocks).To(HaveLen(4))
+		Expect(collector.blocks[3].Envelopes).To(Equal([][]byte{[]byte("e")}))
+
+		for i, block := range collector.blocks {
+			Expect(block.Number).To(Equal(uint64(i)))
+		}
+		Expect(collector.blocks[0].PreviousHash).To(BeNil())
+		Expect(collector.blocks[1].PreviousHash).NotTo(BeNil())
+	})
+
+	It("respects the preferred block size", func() {
+		Expect(adapter.Apply(batch("aaaaaa", "bbbbbb", "ccccccccccccc"))).To(Succeed())
+
+		Expect(collector.blocks).To(HaveLen(3))
+		Expect(collector.blocks[0].Envelopes).To(Equal([][]byte{[]byte("aaaaaa")}))
+		Expect(collector.blocks[1].Envelopes).To(Equal([][]byte{[]byte("bbbbbb")}))
+		Expect(collector.blocks[2].Envelopes).To(Equal([][]byte{[]byte("ccccccccccccc")}))
+	})
+
+	It("restores pending envelopes and the hash chain from a snapshot", func() {
+		Expect(adapter.Apply(batch("a", "b", "c", "d"))).To(Succeed())
+		snapshot, err := adapter.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(adapter.Apply(batch("e"))).To(Succeed())
+		expected := collector.blocks[1]
+
+		collector.blocks = nil
+		Expect(adapter.RestoreState(snapshot)).To(Succeed())
+		Expect(adapter.Apply(batch("e"))).To(Succeed())
+		Expect(collector.blocks).To(HaveLen(1))
+		Expect(collector.blocks[0]).To(Equal(expected))
+	})
+})
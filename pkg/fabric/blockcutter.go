@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabric
+
+// BatchSize holds the parameters governing the size of the cut blocks.
+// The semantics of the fields is the same as with the BatchSize configuration of the Fabric ordering service.
+type BatchSize struct {
+
+	// Maximal number of envelopes in a block.
+	MaxMessageCount int
+
+	// Preferred maximal size of a block in bytes (i.e., of the sum of the sizes of the contained envelopes).
+	// A block is only larger than PreferredMaxBytes if it consists of a single envelope
+	// that itself is larger than PreferredMaxBytes.
+	PreferredMaxBytes int
+}
+
+// blockCutter accumulates envelopes and splits them in blocks according to a BatchSize.
+// Its logic mirrors the block cutter of the Fabric ordering service, except that it has no notion of time.
+// All decisions are based exclusively on the sequence of envelopes, so that all nodes cut the same blocks.
+type blockCutter struct {
+
+	// Parameters determining when a block is cut.
+	batchSize BatchSize
+
+	// Envelopes not yet included in any block.
+	pending [][]byte
+
+	// Total size of the pending envelopes in bytes.
+	pendingBytes int
+}
+
+// newBlockCutter returns a new blockCutter with no pending envelopes.
+func newBlockCutter(batchSize BatchSize) *blockCutter {
+	return &blockCutter{
+		batchSize: batchSize,
+		pending:   make([][]byte, 0),
+	}
+}
+
+// Ordered adds a new envelope to the pending envelopes and returns the (potentially empty) list of blocks
+// (each represented as a list of envelopes) that have been cut as a result.
+// At most two blocks can be cut: the pending envelopes (if the new envelope does not fit in the same block)
+// and the new envelope itself (if it exceeds the preferred block size on its own).
+func (bc *blockCutter) Ordered(envelope []byte) [][][]byte {
+	blocks := make([][][]byte, 0)
+
+	// An envelope larger than the preferred block size is isolated in its own block.
+	if len(envelope) > bc.batchSize.PreferredMaxBytes {
+		if len(bc.pending) > 0 {
+			blocks = append(blocks, bc.Cut())
+		}
+		return append(blocks, [][]byte{envelope})
+	}
+
+	// If the envelope would make the pending block exceed the preferred size, cut the pending block first.
+	if bc.pendingBytes+len(envelope) > bc.batchSize.PreferredMaxBytes {
+		blocks = append(blocks, bc.Cut())
+	}
+
+	// Add the envelope to the pending ones.
+	bc.pending = append(bc.pending, envelope)
+	bc.pendingBytes += len(envelope)
+
+	// Cut a block if the maximal number of envelopes has been reached.
+	if len(bc.pending) >= bc.batchSize.MaxMessageCount {
+		blocks = append(blocks, bc.Cut())
+	}
+
+	return blocks
+}
+
+// Cut returns all pending envelopes (as a single block) and clears them from the blockCutter.
+// The returned block might be empty.
+func (bc *blockCutter) Cut() [][]byte {
+	block := bc.pending
+	bc.pending = make([][]byte, 0)
+	bc.pendingBytes = 0
+	return block
+}
+
+// Pending returns the number of pending envelopes.
+func (bc *blockCutter) Pending() int {
+	return len(bc.pending)
+}
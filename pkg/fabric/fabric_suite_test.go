@@ -0,0 +1,13 @@
+package fabric_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFabric(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fabric Suite")
+}
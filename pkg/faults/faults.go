@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package faults provides composable injectors of network faults (message loss, delays, duplication,
+// reordering and partitions) as well as descriptions of node crashes, for testing how the protocol copes with them.
+// The same injectors can be applied to the simulated network of package testengine (see testengine.Spec)
+// and, using Net, to a real Net module. Fault timelines can be described in scenario files (see LoadScenario).
+// Crashes and slow disks are only supported by the simulation, as a real process cannot crash itself
+// (or slow down its disk) in a controlled way.
+package faults
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// An Injector decides the fate of the messages sent over a network.
+type Injector interface {
+
+	// Inject is invoked for each message msg sent from node from to node to at time now
+	// (measured from the start of the test) and returns the delays after which the copies of the message
+	// are to be handed to the network, one delay per copy.
+	// An empty result drops the message, a single zero delay leaves the message untouched.
+	// All random decisions must be taken using rnd, such that the faults can be reproduced from its seed.
+	Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration
+}
+
+// Chain returns an Injector applying all the given injectors, in the given order.
+// Each copy of a message produced by an injector is subject to the following ones,
+// and the delays imposed on a copy by the different injectors add up.
+func Chain(injectors ...Injector) Injector {
+	return chain(injectors)
+}
+
+// chain is the Injector returned by Chain.
+type chain []Injector
+
+// Inject applies all the injectors of the chain.
+func (c chain) Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration {
+	delays := []time.Duration{0}
+	for _, injector := range c {
+		next := make([]time.Duration, 0, len(delays))
+		for _, delay := range delays {
+			for _, extra := range injector.Inject(now, from, to, msg, rnd) {
+				next = append(next, delay+extra)
+			}
+		}
+		delays = next
+	}
+	return delays
+}
+
+// During applies an Injector only to the messages sent during the period [Start, End).
+// A zero End makes the period unbounded.
+type During struct {
+	Start    time.Duration
+	End      time.Duration
+	Injector Injector
+}
+
+// Inject applies the wrapped Injector if now is within the period and leaves the message untouched otherwise.
+func (d *During) Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration {
+	if now < d.Start || (d.End != 0 && now >= d.End) {
+		return []time.Duration{0}
+	}
+	return d.Injector.Inject(now, from, to, msg, rnd)
+}
+
+// Drop drops each message with probability Rate.
+type Drop struct {
+	Rate float64
+}
+
+// Inject drops the message with probability Rate.
+func (d *Drop) Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration {
+	if rnd.Float64() < d.Rate {
+		return nil
+	}
+	return []time.Duration{0}
+}
+
+// Delay delays each message by a duration drawn uniformly at random from [Min, Max].
+type Delay struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Inject delays the message by a random duration between Min and Max.
+func (d *Delay) Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration {
+	return []time.Duration{randomDuration(d.Min, d.Max, rnd)}
+}
+
+// Duplicate sends a second copy of each message with probability Rate.
+// The second copy is delayed by a duration drawn uniformly at random from [0, MaxDelay].
+type Duplicate struct {
+	Rate     float64
+	MaxDelay time.Duration
+}
+
+// Inject duplicates the message with probability Rate.
+func (d *Duplicate) Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration {
+	if rnd.Float64() < d.Rate {
+		return []time.Duration{0, randomDuration(0, d.MaxDelay, rnd)}
+	}
+	return []time.Duration{0}
+}
+
+// Reorder holds back each message with probability Rate by a duration drawn uniformly at random from (0, MaxDelay],
+// such that it is overtaken by the messages sent after it.
+type Reorder struct {
+	Rate     float64
+	MaxDelay time.Duration
+}
+
+// Inject holds back the message with probability Rate.
+func (r *Reorder) Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration {
+	if r.MaxDelay > 0 && rnd.Float64() < r.Rate {
+		return []time.Duration{randomDuration(1, r.MaxDelay, rnd)}
+	}
+	return []time.Duration{0}
+}
+
+// Partition splits the nodes in groups and drops all messages between nodes of different groups.
+// Nodes not contained in any group are isolated from all other nodes.
+// Combined with During, a Partition describes a partition that heals after some time.
+// If HealAt is not zero, the messages between different groups are not dropped, but held back until time HealAt,
+// as by a network retransmitting them until they get through.
+type Partition struct {
+	Groups [][]t.NodeID
+	HealAt time.Duration
+}
+
+// Inject drops (or holds back) the message if its sender and its destination are not in the same group.
+func (p *Partition) Inject(now time.Duration, from, to t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []time.Duration {
+	for _, group := range p.Groups {
+		if containsNode(group, from) && containsNode(group, to) {
+			return []time.Duration{0}
+		}
+	}
+	if p.HealAt > now {
+		return []time.Duration{p.HealAt - now}
+	}
+	return nil
+}
+
+// Crash describes a crash of node Node at time At. If RestartAt is not zero, the node restarts at time RestartAt,
+// recovering the state it persisted before crashing.
+type Crash struct {
+	Node      t.NodeID
+	At        time.Duration
+	RestartAt time.Duration
+}
+
+// LeaderCrash describes a crash of the leader proposing sequence number Sn, at the moment it sends its proposal,
+// such that only some of the other nodes might receive it.
+// If Downtime is not zero, the node restarts Downtime after crashing, recovering the state it persisted before.
+// As the leader of a sequence number is only known once it proposes, the crash is skipped
+// if it would leave more than f nodes crashed at the same time.
+type LeaderCrash struct {
+	Sn       t.SeqNr
+	Downtime time.Duration
+}
+
+// SlowDisk describes a period [Start, End) during which the persistent storage of node Node is slow:
+// each operation persisting data (appending to the WAL or storing requests) delays the events that result from it
+// by Latency.
+type SlowDisk struct {
+	Node    t.NodeID
+	Start   time.Duration
+	End     time.Duration
+	Latency time.Duration
+}
+
+// randomDuration returns a duration drawn uniformly at random from [min, max].
+func randomDuration(min, max time.Duration, rnd *rand.Rand) time.Duration {
+	if max <= min {
+		return min
+	}
+	return min + time.Duration(rnd.Int63n(int64(max-min)+1))
+}
+
+// containsNode returns true if nodeID is in nodeIDs.
+func containsNode(nodeIDs []t.NodeID, nodeID t.NodeID) bool {
+	for _, n := range nodeIDs {
+		if n == nodeID {
+			return true
+		}
+	}
+	return false
+}
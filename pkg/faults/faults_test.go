@@ -0,0 +1,133 @@
+package faults_test
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// countingNet is a Net module counting the messages sent to each destination.
+type countingNet struct {
+	lock sync.Mutex
+	sent map[t.NodeID]int
+}
+
+func (cn *countingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	cn.lock.Lock()
+	defer cn.lock.Unlock()
+	cn.sent[dest]++
+	return nil
+}
+
+func (cn *countingNet) ReceiveChan() <-chan modules.ReceivedMessage {
+	return nil
+}
+
+func (cn *countingNet) count(dest t.NodeID) int {
+	cn.lock.Lock()
+	defer cn.lock.Unlock()
+	return cn.sent[dest]
+}
+
+var _ = Describe("Net", func() {
+	var inner *countingNet
+
+	BeforeEach(func() {
+		inner = &countingNet{sent: make(map[t.NodeID]int)}
+	})
+
+	It("drops, duplicates and partitions messages", func() {
+		net := faults.NewNet(inner, 0, faults.Chain(
+			&faults.Duplicate{Rate: 1},
+			&faults.Partition{Groups: [][]t.NodeID{{0, 1}, {2}}},
+		), 1, logging.NilLogger)
+		defer net.Stop()
+
+		for i := 0; i < 10; i++ {
+			Expect(net.Send(1, &messagepb.Message{})).To(Succeed())
+			Expect(net.Send(2, &messagepb.Message{})).To(Succeed())
+		}
+		Expect(inner.count(1)).To(Equal(20))
+		Expect(inner.count(2)).To(Equal(0))
+	})
+
+	It("sends delayed messages later and discards them when stopped", func() {
+		net := faults.NewNet(inner, 0, &faults.Delay{Min: 50 * time.Millisecond, Max: 50 * time.Millisecond},
+			1, logging.NilLogger)
+
+		Expect(net.Send(1, &messagepb.Message{})).To(Succeed())
+		Expect(inner.count(1)).To(Equal(0))
+		Eventually(func() int { return inner.count(1) }).Should(Equal(1))
+
+		Expect(net.Send(1, &messagepb.Message{})).To(Succeed())
+		net.Stop()
+		Consistently(func() int { return inner.count(1) }, 100*time.Millisecond).Should(Equal(1))
+	})
+
+	It("measures time and delays messages using its clock", func() {
+		mockClock := clock.NewMock(time.Unix(0, 0))
+		net := faults.NewNet(inner, 0, &faults.Partition{Groups: [][]t.NodeID{{0}, {1}}, HealAt: time.Minute},
+			1, logging.NilLogger)
+		net.SetClock(mockClock)
+		defer net.Stop()
+
+		// Held back until the partition heals, a minute of virtual time after the Net has been created.
+		Expect(net.Send(1, &messagepb.Message{})).To(Succeed())
+		mockClock.Advance(30 * time.Second)
+		Consistently(func() int { return inner.count(1) }, 100*time.Millisecond).Should(Equal(0))
+		mockClock.Advance(30 * time.Second)
+		Eventually(func() int { return inner.count(1) }).Should(Equal(1))
+	})
+})
+
+var _ = Describe("Scenario", func() {
+
+	It("parses faults and crashes", func() {
+		scenario, err := faults.ParseScenario([]byte(`{"faults": [
+			{"type": "drop", "rate": 1, "start": "1s", "end": "2s"},
+			{"type": "crash", "node": 2, "start": "3s", "end": "4s"}
+		]}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scenario.Crashes).To(Equal([]faults.Crash{{Node: 2, At: 3 * time.Second, RestartAt: 4 * time.Second}}))
+
+		// Messages are only dropped during the specified period.
+		rnd := rand.New(rand.NewSource(1))
+		Expect(scenario.Injector.Inject(0, 0, 1, &messagepb.Message{}, rnd)).To(HaveLen(1))
+		Expect(scenario.Injector.Inject(1500*time.Millisecond, 0, 1, &messagepb.Message{}, rnd)).To(BeEmpty())
+		Expect(scenario.Injector.Inject(2*time.Second, 0, 1, &messagepb.Message{}, rnd)).To(HaveLen(1))
+	})
+
+	It("rejects invalid faults", func() {
+		for _, fault := range []string{
+			`{"type": "flood"}`,
+			`{"type": "drop", "rate": 2}`,
+			`{"type": "delay", "min": "2s", "max": "1s"}`,
+			`{"type": "partition", "start": "2s", "end": "1s"}`,
+			`{"type": "drop", "start": "soon"}`,
+		} {
+			_, err := faults.ParseScenario([]byte(`{"faults": [` + fault + `]}`))
+			Expect(err).To(HaveOccurred(), fault)
+		}
+	})
+})
+
+var _ = Describe("Partition", func() {
+
+	It("holds back the messages between groups until it heals", func() {
+		partition := &faults.Partition{Groups: [][]t.NodeID{{0, 1}, {2}}, HealAt: 3 * time.Second}
+		rnd := rand.New(rand.NewSource(1))
+		Expect(partition.Inject(time.Second, 0, 1, &messagepb.Message{}, rnd)).To(Equal([]time.Duration{0}))
+		Expect(partition.Inject(time.Second, 0, 2, &messagepb.Message{}, rnd)).To(Equal([]time.Duration{2 * time.Second}))
+		Expect(partition.Inject(3*time.Second, 0, 2, &messagepb.Message{}, rnd)).To(BeEmpty())
+	})
+})
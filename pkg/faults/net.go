@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faults
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Net is a Net module wrapper injecting faults into the messages sent through the wrapped Net module.
+// Time is measured from the creation of the Net (or from setting its clock). Delayed copies of a message are sent
+// by separate goroutines, and errors sending them can only be logged. Received messages are passed through unchanged
+// (faults on the receiving side are injected by the Net of the sender).
+type Net struct {
+	net      modules.Net
+	ownID    t.NodeID
+	injector Injector
+	logger   logging.Logger
+
+	// Source of time for the injector and for delaying messages.
+	clock clock.Clock
+
+	// Time the Net has been created (or its clock set).
+	start time.Time
+
+	// Closed when the Net is stopped, discarding the delayed messages that have not been sent yet.
+	stopC chan struct{}
+
+	// Protects rand and stopped, and serializes the calls to the wrapped Net's Send.
+	lock sync.Mutex
+
+	rand    *rand.Rand
+	stopped bool
+}
+
+// NewNet returns a new Net of node ownID wrapping net, deciding the fate of each sent message using injector,
+// with all random decisions determined by seed.
+func NewNet(net modules.Net, ownID t.NodeID, injector Injector, seed int64, logger logging.Logger) *Net {
+	return &Net{
+		net:      net,
+		ownID:    ownID,
+		injector: injector,
+		logger:   logger,
+		clock:    clock.System,
+		start:    clock.System.Now(),
+		stopC:    make(chan struct{}),
+		rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// SetClock makes the Net measure time and delay messages using c instead of the system clock,
+// restarting the measurement of time at the current time of c. It must be called before the Net is used.
+func (n *Net) SetClock(c clock.Clock) {
+	n.clock = c
+	n.start = c.Now()
+}
+
+// Send passes msg to the wrapped Net module as decided by the injector:
+// not at all, immediately, or after a delay, possibly multiple times.
+// Only errors sending the immediate copies are returned.
+func (n *Net) Send(dest t.NodeID, msg *messagepb.Message) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.stopped {
+		return nil
+	}
+
+	var err error
+	for _, delay := range n.injector.Inject(n.clock.Since(n.start), n.ownID, dest, msg, n.rand) {
+		if delay <= 0 {
+			if sendErr := n.net.Send(dest, msg); sendErr != nil {
+				err = sendErr
+			}
+			continue
+		}
+
+		go n.sendDelayed(n.clock.After(delay), dest, msg)
+	}
+	return err
+}
+
+// sendDelayed passes msg to the wrapped Net module when timeout fires, unless the Net is stopped before.
+func (n *Net) sendDelayed(timeout <-chan time.Time, dest t.NodeID, msg *messagepb.Message) {
+	select {
+	case <-timeout:
+	case <-n.stopC:
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.stopped {
+		return
+	}
+	if err := n.net.Send(dest, msg); err != nil {
+		n.logger.Log(logging.LevelWarn, "Failed sending delayed message.", "dest", dest, "err", err)
+	}
+}
+
+// ReceiveChan returns the channel of messages received by the wrapped Net module.
+func (n *Net) ReceiveChan() <-chan modules.ReceivedMessage {
+	return n.net.ReceiveChan()
+}
+
+// Stop discards all delayed messages that have not been sent yet. Messages sent after Stop is called are dropped.
+func (n *Net) Stop() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.stopped {
+		n.stopped = true
+		close(n.stopC)
+	}
+}
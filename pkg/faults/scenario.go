@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faults
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Scenario is a timeline of faults, typically loaded from a scenario file (see LoadScenario).
+type Scenario struct {
+
+	// The network faults of the scenario, applied to all messages in the order they appear in the scenario file.
+	Injector Injector
+
+	// The node crashes of the scenario, in the order they appear in the scenario file.
+	Crashes []Crash
+}
+
+// A scenario file is a JSON document containing a list of faults, e.g.:
+//
+//	{"faults": [
+//	  {"type": "drop", "rate": 0.05},
+//	  {"type": "delay", "min": "1ms", "max": "20ms", "start": "1s", "end": "2s"},
+//	  {"type": "partition", "groups": [[0, 1, 2], [3]], "start": "2s", "end": "4s"},
+//	  {"type": "crash", "node": 1, "start": "5s", "end": "6s"}
+//	]}
+//
+// Each fault has a type and, optionally, the period [start, end) during which it applies (unbounded by default).
+// For crashes, start is the time of the crash and end the time of the restart (no restart if omitted).
+// Durations are written as accepted by time.ParseDuration.
+// The parameters of the other types correspond to the fields of Drop (rate), Delay (min, max),
+// Duplicate (rate, maxDelay), Reorder (rate, maxDelay), and Partition (groups).
+type scenarioFile struct {
+	Faults []faultSpec `json:"faults"`
+}
+
+// faultSpec is a single fault of a scenario file.
+type faultSpec struct {
+	Type     string       `json:"type"`
+	Start    duration     `json:"start"`
+	End      duration     `json:"end"`
+	Rate     float64      `json:"rate"`
+	Min      duration     `json:"min"`
+	Max      duration     `json:"max"`
+	MaxDelay duration     `json:"maxDelay"`
+	Groups   [][]t.NodeID `json:"groups"`
+	Node     t.NodeID     `json:"node"`
+}
+
+// duration is a time.Duration represented in JSON as a string accepted by time.ParseDuration.
+type duration time.Duration
+
+// UnmarshalJSON parses a duration string.
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+// LoadScenario reads and parses the scenario file at path.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read scenario file: %w", err)
+	}
+	scenario, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario file %s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+// ParseScenario parses the contents of a scenario file.
+func ParseScenario(data []byte) (*Scenario, error) {
+	var file scenarioFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	injectors := make([]Injector, 0, len(file.Faults))
+	crashes := make([]Crash, 0)
+	for i, fault := range file.Faults {
+		start, end := time.Duration(fault.Start), time.Duration(fault.End)
+		if start < 0 || (end != 0 && end <= start) {
+			return nil, fmt.Errorf("fault %d: invalid period [%v, %v)", i, start, end)
+		}
+		if fault.Rate < 0 || fault.Rate > 1 {
+			return nil, fmt.Errorf("fault %d: rate %v not between 0 and 1", i, fault.Rate)
+		}
+
+		var injector Injector
+		switch fault.Type {
+		case "drop":
+			injector = &Drop{Rate: fault.Rate}
+		case "delay":
+			if fault.Max < fault.Min {
+				return nil, fmt.Errorf("fault %d: maximal delay smaller than minimal delay", i)
+			}
+			injector = &Delay{Min: time.Duration(fault.Min), Max: time.Duration(fault.Max)}
+		case "duplicate":
+			injector = &Duplicate{Rate: fault.Rate, MaxDelay: time.Duration(fault.MaxDelay)}
+		case "reorder":
+			injector = &Reorder{Rate: fault.Rate, MaxDelay: time.Duration(fault.MaxDelay)}
+		case "partition":
+			injector = &Partition{Groups: fault.Groups}
+		case "crash":
+			crashes = append(crashes, Crash{Node: fault.Node, At: start, RestartAt: end})
+			continue
+		default:
+			return nil, fmt.Errorf("fault %d: unknown fault type: %q", i, fault.Type)
+		}
+
+		if start != 0 || end != 0 {
+			injector = &During{Start: start, End: end, Injector: injector}
+		}
+		injectors = append(injectors, injector)
+	}
+
+	return &Scenario{Injector: Chain(injectors...), Crashes: crashes}, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package golden maintains golden traces: canonical recordings of the ISS protocol in representative scenarios,
+// each simulated by the testengine from a fixed seed. Replaying a golden trace feeds the recorded inputs
+// to the current implementation of the protocol and fails with an *eventlog.DivergenceError
+// as soon as the protocol produces different output than recorded, catching unintended changes of its behavior.
+// After an intended change, the traces are recorded anew (see Scenario.Record), and the difference
+// reviewed like any other change. The golden traces of the repository are recorded by
+//
+//	go test ./pkg/golden -update
+package golden
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// A Scenario is a simulated run of the protocol whose recording serves as golden trace.
+// The recording of each node incarnation (see testengine.Spec.RecordProtocol) is stored in a separate file.
+type Scenario struct {
+
+	// Name of the scenario, also naming the directory of its recordings.
+	Name string
+
+	// The simulated system and load. All nodes are members.
+	NumNodes              int
+	NumClients            int
+	RequestsPerClient     int
+	ClientRequestInterval time.Duration
+	Crashes               []faults.Crash
+
+	// The seed of the simulation and the virtual time it runs for.
+	Seed     int64
+	Duration time.Duration
+
+	// If not nil, Configure adjusts the default configuration of node id.
+	Configure func(id t.NodeID, config *iss.Config)
+}
+
+// Scenarios returns the scenarios of the golden traces:
+//   - normal: four correct nodes order the requests of two clients over several epochs.
+//   - leader-failure: like normal, but the leader of one of the segments crashes and later restarts.
+//   - state-transfer: the fourth node, which is not a leader, joins the running network,
+//     obtaining the state at a stable checkpoint from the other nodes (see iss.Config.Join).
+func Scenarios() []*Scenario {
+	shortSegments := func(id t.NodeID, config *iss.Config) {
+		config.SegmentLength = 4
+	}
+
+	return []*Scenario{{
+		Name:                  "normal",
+		NumNodes:              4,
+		NumClients:            2,
+		RequestsPerClient:     20,
+		ClientRequestInterval: 10 * time.Millisecond,
+		Seed:                  1,
+		Duration:              time.Second,
+		Configure:             shortSegments,
+	}, {
+		Name:                  "leader-failure",
+		NumNodes:              4,
+		NumClients:            2,
+		RequestsPerClient:     20,
+		ClientRequestInterval: 10 * time.Millisecond,
+		Crashes:               []faults.Crash{{Node: 1, At: 100 * time.Millisecond, RestartAt: 400 * time.Millisecond}},
+		Seed:                  2,
+		Duration:              2 * time.Second,
+		Configure:             shortSegments,
+	}, {
+		Name:                  "state-transfer",
+		NumNodes:              4,
+		NumClients:            2,
+		RequestsPerClient:     20,
+		ClientRequestInterval: 10 * time.Millisecond,
+		Seed:                  3,
+		Duration:              2 * time.Second,
+		Configure: func(id t.NodeID, config *iss.Config) {
+			shortSegments(id, config)
+			config.LeaderPolicy = &iss.SimpleLeaderPolicy{Membership: []t.NodeID{0, 1, 2}}
+			config.Join = id == 3
+		},
+	}}
+}
+
+// Config returns the protocol configuration of node id in the scenario.
+func (s *Scenario) Config(id t.NodeID) *iss.Config {
+	config := iss.DefaultConfig(s.membership())
+	config.CheckInvariants = true
+	if s.Configure != nil {
+		s.Configure(id, config)
+	}
+	return config
+}
+
+// Record simulates the scenario and writes its recordings to the subdirectory of dir named after the scenario,
+// replacing any previous recordings.
+func (s *Scenario) Record(dir string) (err error) {
+	scenarioDir := filepath.Join(dir, s.Name)
+	if err := os.RemoveAll(scenarioDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(scenarioDir, 0755); err != nil {
+		return err
+	}
+
+	var files []*os.File
+	defer func() {
+		for _, file := range files {
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}()
+
+	// Files cannot be created in RecordProtocol, which cannot fail. The first error is reported after the run.
+	var createErr error
+	engine, err := testengine.New(&testengine.Spec{
+		NumNodes:              s.NumNodes,
+		NumClients:            s.NumClients,
+		RequestsPerClient:     s.RequestsPerClient,
+		ClientRequestInterval: s.ClientRequestInterval,
+		Crashes:               s.Crashes,
+		ISSConfig:             s.Configure,
+		RecordProtocol: func(node t.NodeID, incarnation int) io.Writer {
+			file, err := os.Create(filepath.Join(scenarioDir, recordingName(node, incarnation)))
+			if err != nil {
+				if createErr == nil {
+					createErr = err
+				}
+				return ioutil.Discard
+			}
+			files = append(files, file)
+			return file
+		},
+	}, s.Seed)
+	if err != nil {
+		return err
+	}
+
+	runErr := engine.RunFor(s.Duration)
+	if err := engine.Stop(); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return runErr
+	}
+	return createErr
+}
+
+// Replay replays all the recordings of the scenario stored in the subdirectory of dir named after the scenario,
+// each against a fresh instance of the protocol configured by Config.
+// Replay returns an error wrapping an *eventlog.DivergenceError for the first recording the protocol diverges from.
+func (s *Scenario) Replay(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, s.Name, "node*.gz"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no recordings of scenario %s in %s", s.Name, dir)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := s.replayFile(path); err != nil {
+			return fmt.Errorf("could not replay recording %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// replayFile replays the recording in the file at path, converting a panic of the protocol to an error.
+func (s *Scenario) replayFile(path string) (err error) {
+	var node t.NodeID
+	var incarnation int
+	if _, err := fmt.Sscanf(filepath.Base(path), "node%d.%d.gz", &node, &incarnation); err != nil {
+		return fmt.Errorf("unexpected file name: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := eventlog.NewReader(file)
+	if err != nil {
+		return err
+	}
+	replayer := eventlog.NewProtocolReplayer(reader)
+
+	config := s.Config(node)
+	config.Now = replayer.Now
+	protocol, err := iss.New(node, config, logging.NilLogger)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if rErr, ok := r.(error); ok {
+				err = fmt.Errorf("panic in protocol: %w", rErr)
+			} else {
+				err = fmt.Errorf("panic in protocol: %v", r)
+			}
+		}
+	}()
+	return replayer.Replay(protocol)
+}
+
+// membership returns the IDs of all nodes of the scenario.
+func (s *Scenario) membership() []t.NodeID {
+	membership := make([]t.NodeID, s.NumNodes)
+	for i := range membership {
+		membership[i] = t.NodeID(i)
+	}
+	return membership
+}
+
+// recordingName returns the name of the file holding the recording of an incarnation of a node.
+func recordingName(node t.NodeID, incarnation int) string {
+	return fmt.Sprintf("node%d.%d.gz", node, incarnation)
+}
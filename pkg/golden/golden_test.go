@@ -0,0 +1,66 @@
+package golden_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/golden"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var update = flag.Bool("update", false, "record the golden traces in testdata anew")
+
+var _ = Describe("Golden traces", func() {
+
+	for _, scenario := range golden.Scenarios() {
+		scenario := scenario
+		It("are reproduced by the protocol in scenario "+scenario.Name, func() {
+			if *update {
+				Expect(scenario.Record("testdata")).To(Succeed())
+			}
+			Expect(scenario.Replay("testdata")).To(Succeed())
+		})
+	}
+
+	It("detect a change of the protocol's behavior", func() {
+		changed := *golden.Scenarios()[0]
+		changed.Configure = func(id t.NodeID, config *iss.Config) {
+			golden.Scenarios()[0].Configure(id, config)
+			config.MaxBatchSize = 1
+		}
+
+		err := changed.Replay("testdata")
+		var divergence *eventlog.DivergenceError
+		Expect(errors.As(err, &divergence)).To(BeTrue())
+	})
+
+	It("cover the restart of the failed leader", func() {
+		Expect(filepath.Join("testdata", "leader-failure", "node1.1.gz")).To(BeARegularFile())
+	})
+
+	It("cover the transfer of the state to the joining node", func() {
+		file, err := os.Open(filepath.Join("testdata", "state-transfer", "node3.0.gz"))
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+		reader, err := eventlog.NewReader(file)
+		Expect(err).NotTo(HaveOccurred())
+
+		stateTransfers := 0
+		step, err := reader.ReadProtocolStep()
+		for ; err == nil; step, err = reader.ReadProtocolStep() {
+			if step.Input.GetMessageReceived().GetMsg().GetIss().GetStateTransfer() != nil {
+				stateTransfers++
+			}
+		}
+		Expect(err).To(Equal(io.EOF))
+		Expect(stateTransfers).To(BeNumerically(">", 0))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpctransport
+
+import (
+	"fmt"
+	"sync"
+
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// AddressBook maps the numeric ID of each node to a string representation of its network address
+// with the format "IPAddress:port".
+// Unlike a static membership, the AddressBook can be updated at runtime, e.g. when a node changes its IP address.
+// A GrpcTransport using the AddressBook picks up the changes automatically:
+// the next message sent to a node whose address changed is sent over a new connection to the new address.
+// The address book of each node is updated independently.
+// To have all nodes apply the same updates in the same order, the updates can be ordered through consensus,
+// i.e., submitted as requests and applied to the AddressBook by the application when the requests are delivered.
+// Changing the own address of a node only takes effect when its GrpcTransport is (re-)started.
+// AddressBook is safe for concurrent use.
+type AddressBook struct {
+
+	// The addresses of the nodes, indexed by node ID. Protected by lock.
+	addrs map[t.NodeID]string
+	lock  sync.RWMutex
+}
+
+// NewAddressBook returns a new AddressBook initialized with the given addresses.
+// The map is copied, such that later modifications of it do not affect the AddressBook.
+func NewAddressBook(addrs map[t.NodeID]string) *AddressBook {
+	ab := &AddressBook{addrs: make(map[t.NodeID]string, len(addrs))}
+	for id, addr := range addrs {
+		ab.addrs[id] = addr
+	}
+	return ab
+}
+
+// Lookup returns the current address of node id and true, or false if the AddressBook contains no address for id.
+func (ab *AddressBook) Lookup(id t.NodeID) (string, bool) {
+	ab.lock.RLock()
+	defer ab.lock.RUnlock()
+
+	addr, ok := ab.addrs[id]
+	return addr, ok
+}
+
+// Update sets the address of node id to addr, adding the node to the AddressBook if it is not contained yet.
+// The address must have the format "IPAddress:port", otherwise Update returns an error.
+func (ab *AddressBook) Update(id t.NodeID, addr string) error {
+	if _, _, err := splitAddrPort(addr); err != nil {
+		return fmt.Errorf("invalid address of node %d (%s): %w", id, addr, err)
+	}
+
+	ab.lock.Lock()
+	defer ab.lock.Unlock()
+
+	ab.addrs[id] = addr
+	return nil
+}
+
+// Remove removes the address of node id from the AddressBook.
+// Sending messages to a node without an address fails.
+func (ab *AddressBook) Remove(id t.NodeID) {
+	ab.lock.Lock()
+	defer ab.lock.Unlock()
+
+	delete(ab.addrs, id)
+}
+
+// Snapshot returns a copy of the current contents of the AddressBook.
+func (ab *AddressBook) Snapshot() map[t.NodeID]string {
+	ab.lock.RLock()
+	defer ab.lock.RUnlock()
+
+	addrs := make(map[t.NodeID]string, len(ab.addrs))
+	for id, addr := range ab.addrs {
+		addrs[id] = addr
+	}
+	return addrs
+}
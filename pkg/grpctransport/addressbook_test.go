@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpctransport_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/grpctransport"
+)
+
+// The address book test starts a gRPC deployment in which the other nodes do not know the address of the last node,
+// provides the address at runtime, and checks that the last node (that can only deliver requests
+// after receiving messages from the others) delivers all requests.
+var _ = Describe("Address book test", func() {
+
+	It("delivers all requests after a node's address is updated", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "grpc",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Remove the address of the last node from the address books of the others
+		// and retry sending to it long enough for the address to be updated.
+		last := deployment.TestReplicas[len(deployment.TestReplicas)-1]
+		addr, ok := last.Net.(*grpctransport.GrpcTransport).AddressBook().Lookup(last.Id)
+		Expect(ok).To(BeTrue())
+		var addressBooks []*grpctransport.AddressBook
+		for _, replica := range deployment.TestReplicas[:len(deployment.TestReplicas)-1] {
+			addressBook := replica.Net.(*grpctransport.GrpcTransport).AddressBook()
+			addressBook.Remove(last.Id)
+			addressBooks = append(addressBooks, addressBook)
+			replica.Config.SendRetries = 10
+			replica.Config.SendRetryBackoff = 10 * time.Millisecond
+		}
+
+		// Update the address books as soon as the last node is running.
+		updated := false
+		deployment.RunUntil(func() bool {
+			if !updated {
+				if last.Node() == nil {
+					return false
+				}
+				for _, addressBook := range addressBooks {
+					Expect(addressBook.Update(last.Id, addr)).To(Succeed())
+				}
+				updated = true
+			}
+			return deployment.AllProcessed(testConfig.NumFakeRequests)()
+		})
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
@@ -9,6 +9,9 @@ package grpctransport
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
 	"github.com/hyperledger-labs/mirbft/pkg/logging"
 	"github.com/hyperledger-labs/mirbft/pkg/modules"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
@@ -24,6 +27,19 @@ import (
 const (
 	// Maximum size of a gRPC message
 	maxMessageSize = 1073741824
+
+	// Maximal time to wait for a connection to another node to be established when calling Connect().
+	connectTimeout = 10 * time.Second
+
+	// Maximal time to wait for a connection to be re-established when sending a message to a node.
+	// Since sending blocks until the connection is re-established, this timeout is kept short.
+	reconnectTimeout = time.Second
+
+	// Minimal and maximal time between two attempts to re-establish a connection to a node.
+	// After each failed attempt, the time to the next one doubles (up to maxReconnectBackoff).
+	// Messages sent to the node before the next attempt fail immediately.
+	minReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff = 10 * time.Second
 )
 
 // GrpcTransport represents a networking module that is based on gRPC.
@@ -31,22 +47,35 @@ const (
 // The type of gRPC connection is multi-request-single-response, where each module contains
 // one instance of a gRPC client per node.
 // A message to a node is sent as request to that node's gRPC server.
+// For WAN deployments, package quictransport offers a QUIC-based variant with the same AddressBook and TLSConfig.
 type GrpcTransport struct {
 
 	// The numeric ID of the node that uses this networking module.
 	ownId t.NodeID
 
-	// Complete static membership of the system.
 	// Maps the numeric node ID of each node in the system to a string representation of its network address.
 	// The address format "IPAddress:port"
-	membership map[t.NodeID]string // nodeId -> "IPAddress:port"
+	// Initialized with the membership of the system, but can be updated at runtime (see AddressBook).
+	addressBook *AddressBook
 
 	// Channel to which all incoming messages are written.
 	// This channel is also returned by the ReceiveChan() method.
 	incomingMessages chan modules.ReceivedMessage
 
-	// For each node ID, stores a gRPC message sink, calling the Send() method of which sends a message to that node.
-	connections map[t.NodeID]GrpcTransport_ListenClient
+	// For each node ID, stores the connection to that node.
+	// Each connection contains a gRPC message sink, calling the Send() method of which sends a message to that node.
+	// If there is no entry for a node, no connection to the node is established.
+	// Sending a message to such a node attempts to (re-)establish the connection first.
+	connections map[t.NodeID]*connection
+
+	// For each node ID that is not connected, the reconnection backoff state.
+	reconnects map[t.NodeID]*reconnectState
+
+	// Source of time for the reconnection backoff (see SetClock).
+	clock clock.Clock
+
+	// Mutual TLS configuration. If nil, connections are neither encrypted nor authenticated.
+	tlsConfig *TLSConfig
 
 	// The gRPC server used by this networking module.
 	grpcServer *grpc.Server
@@ -59,9 +88,10 @@ type GrpcTransport struct {
 }
 
 // NewGrpcTransport returns a pointer to a new initialized GrpcTransport networking module.
-// The membership parameter must represent the complete static membership of the system.
+// The membership parameter must represent the complete membership of the system.
 // It maps the numeric node ID of each node in the system to
 // a string representation of its network address with the format "IPAddress:port".
+// The addresses are used to initialize the GrpcTransport's AddressBook, through which they can be updated later.
 // The ownId parameter is the numeric ID of the node that will use the returned networking module.
 // The returned GrpcTransport is not yet running (able to receive messages),
 // nor is it connected to any nodes (able to send messages).
@@ -76,16 +106,173 @@ func NewGrpcTransport(membership map[t.NodeID]string, ownId t.NodeID, l logging.
 	return &GrpcTransport{
 		ownId:            ownId,
 		incomingMessages: make(chan modules.ReceivedMessage),
-		membership:       membership,
-		connections:      make(map[t.NodeID]GrpcTransport_ListenClient),
+		addressBook:      NewAddressBook(membership),
+		connections:      make(map[t.NodeID]*connection),
+		reconnects:       make(map[t.NodeID]*reconnectState),
+		clock:            clock.System,
 		logger:           l,
 	}
 }
 
+// NewTLSGrpcTransport returns a pointer to a new initialized GrpcTransport networking module
+// that authenticates the other nodes and encrypts all communication using mutual TLS.
+// The parameters have the same meaning as with NewGrpcTransport.
+// The tlsConfig must contain exactly one certificate for each node in the membership (including the own node),
+// otherwise NewTLSGrpcTransport returns an error.
+func NewTLSGrpcTransport(
+	membership map[t.NodeID]string,
+	ownId t.NodeID,
+	tlsConfig *TLSConfig,
+	l logging.Logger,
+) (*GrpcTransport, error) {
+
+	// Check that the certificates correspond to the membership.
+	for id := range membership {
+		if _, ok := tlsConfig.NodeCertificates[id]; !ok {
+			return nil, fmt.Errorf("no certificate for node %d", id)
+		}
+	}
+	for id := range tlsConfig.NodeCertificates {
+		if _, ok := membership[id]; !ok {
+			return nil, fmt.Errorf("certificate for node %d not in membership", id)
+		}
+	}
+
+	gt := NewGrpcTransport(membership, ownId, l)
+	gt.tlsConfig = tlsConfig
+	return gt, nil
+}
+
+// A connection to another node.
+type connection struct {
+
+	// The underlying gRPC connection.
+	conn *grpc.ClientConn
+
+	// The address of the node to which the connection was established.
+	addr string
+
+	// The message sink to which messages to the node are written.
+	msgSink GrpcTransport_ListenClient
+}
+
+// close closes the message sink and the underlying gRPC connection.
+func (c *connection) close() error {
+	_, sinkErr := c.msgSink.CloseAndRecv()
+	if err := c.conn.Close(); err != nil {
+		return err
+	}
+	return sinkErr
+}
+
+// Backoff state for re-establishing a connection to a node.
+type reconnectState struct {
+
+	// Time after which the next attempt to reconnect can be made.
+	next time.Time
+
+	// Time to wait after the next failed attempt.
+	backoff time.Duration
+
+	// The address of the node to which the last attempt was made.
+	// If the address of the node changes, the backoff is reset.
+	addr string
+}
+
+// SetClock makes the GrpcTransport measure the backoff between reconnection attempts using c
+// instead of the system clock. It must be called before the GrpcTransport is used.
+func (gt *GrpcTransport) SetClock(c clock.Clock) {
+	gt.clock = c
+}
+
+// AddressBook returns the AddressBook used by the GrpcTransport.
+// Updating it makes the GrpcTransport connect to the new addresses.
+func (gt *GrpcTransport) AddressBook() *AddressBook {
+	return gt.addressBook
+}
+
 // Send sends msg to the node with ID dest.
+// If the GrpcTransport is not connected to dest (e.g. because the connection broke or could not be established),
+// Send first tries to (re-)establish the connection, unless a previous attempt failed only recently
+// (see minReconnectBackoff and maxReconnectBackoff). If no connection can be established, Send returns an error.
+// If sending over a connection fails, the connection is closed, Send returns the error,
+// and the connection is re-established when sending the next message.
+// If the address of dest changed in the AddressBook, Send closes the connection to the old address
+// and connects to the new one.
 // Concurrent calls to Send are not (yet? TODO) supported.
 func (gt *GrpcTransport) Send(dest t.NodeID, msg *messagepb.Message) error {
-	return gt.connections[dest].Send(&GrpcMessage{Sender: gt.ownId.Pb(), Msg: msg})
+
+	// Drop the connection to the destination node if the node's address changed.
+	conn, ok := gt.connections[dest]
+	if addr, known := gt.addressBook.Lookup(dest); ok && (!known || addr != conn.addr) {
+		gt.logger.Log(logging.LevelInfo,
+			fmt.Sprintf("Address of node %d changed (%s -> %s). Dropping connection.", dest, conn.addr, addr))
+		delete(gt.connections, dest)
+		if cerr := conn.close(); cerr != nil {
+			gt.logger.Log(logging.LevelDebug, fmt.Sprintf("Failed to close connection to node %d: %v", dest, cerr))
+		}
+		ok = false
+	}
+
+	// (Re-)connect to the destination node if necessary.
+	if !ok {
+		var err error
+		if conn, err = gt.reconnect(dest); err != nil {
+			return err
+		}
+	}
+
+	// Send the message, dropping the connection on failure.
+	if err := conn.msgSink.Send(&GrpcMessage{Sender: gt.ownId.Pb(), Msg: msg}); err != nil {
+		gt.logger.Log(logging.LevelWarn, fmt.Sprintf("Failed sending to node %d: %v. Dropping connection.", dest, err))
+		delete(gt.connections, dest)
+		if cerr := conn.close(); cerr != nil {
+			gt.logger.Log(logging.LevelDebug, fmt.Sprintf("Failed to close connection to node %d: %v", dest, cerr))
+		}
+		return fmt.Errorf("failed sending to node %d: %w", dest, err)
+	}
+
+	return nil
+}
+
+// reconnect tries to establish a connection to node dest, unless the backoff after the last failed attempt
+// has not yet elapsed. On success, the connection is stored and returned.
+func (gt *GrpcTransport) reconnect(dest t.NodeID) (*connection, error) {
+
+	// Look up the current address of the node.
+	addr, ok := gt.addressBook.Lookup(dest)
+	if !ok {
+		return nil, fmt.Errorf("node %d not in address book", dest)
+	}
+
+	// Look up the backoff state, creating a new one if this is the first attempt
+	// or if the last attempt was made to a different address.
+	state, ok := gt.reconnects[dest]
+	if !ok || state.addr != addr {
+		state = &reconnectState{backoff: minReconnectBackoff, addr: addr}
+		gt.reconnects[dest] = state
+	}
+
+	// Fail immediately if the last attempt was too recent.
+	if gt.clock.Now().Before(state.next) {
+		return nil, fmt.Errorf("not connected to node %d", dest)
+	}
+
+	// Try to connect.
+	conn, err := gt.connectToNode(dest, addr, reconnectTimeout)
+	if err != nil {
+		// Schedule the next attempt with exponential backoff.
+		state.next = gt.clock.Now().Add(state.backoff)
+		if state.backoff *= 2; state.backoff > maxReconnectBackoff {
+			state.backoff = maxReconnectBackoff
+		}
+		return nil, fmt.Errorf("could not connect to node %d (%s): %w", dest, addr, err)
+	}
+
+	gt.logger.Log(logging.LevelInfo, fmt.Sprintf("Node %d (%s) reconnected.", dest, addr))
+	delete(gt.reconnects, dest)
+	gt.connections[dest] = conn
+	return conn, nil
 }
 
 // ReceiveChan returns a channel to which the Net module writes all received messages and sender IDs
@@ -99,6 +286,7 @@ func (gt *GrpcTransport) ReceiveChan() <-chan modules.ReceivedMessage {
 // and writes them to a channel that the user can access through ReceiveChan().
 // This function is called by the gRPC system on every new connection
 // from another node's Net module's gRPC client.
+// If TLS is used, messages whose claimed sender does not match the authenticated identity of the peer are dropped.
 func (gt *GrpcTransport) Listen(srv GrpcTransport_ListenServer) error {
 
 	// Print address of incoming connection.
@@ -109,12 +297,30 @@ func (gt *GrpcTransport) Listen(srv GrpcTransport_ListenServer) error {
 		return fmt.Errorf("failed to get grpc peer info from context")
 	}
 
+	// If TLS is used, determine the ID of the connected node.
+	// This cannot fail for connections accepted by the gRPC server, but it is checked nevertheless.
+	var peerId t.NodeID
+	if gt.tlsConfig != nil {
+		var err error
+		if peerId, err = gt.tlsConfig.peerNodeID(p); err != nil {
+			gt.logger.Log(logging.LevelWarn, fmt.Sprintf("Rejecting connection from %s: %v", p.Addr.String(), err))
+			return err
+		}
+	}
+
 	// Declare loop variables outside, since err is used also after the loop finishes.
 	var err error
 	var grpcMsg *GrpcMessage
 
 	// For each message received
 	for grpcMsg, err = srv.Recv(); err == nil; grpcMsg, err = srv.Recv() {
+		// Drop messages not sent by the authenticated node.
+		if gt.tlsConfig != nil && t.NodeID(grpcMsg.Sender) != peerId {
+			gt.logger.Log(logging.LevelWarn, fmt.Sprintf("Dropping message from node %d claiming to be sent by node %d",
+				peerId, grpcMsg.Sender))
+			continue
+		}
+
 		// Write the message to the channel. This channel will be read by the user of the module.
 		gt.incomingMessages <- modules.ReceivedMessage{Sender: t.NodeID(grpcMsg.Sender), Msg: grpcMsg.Msg}
 	}
@@ -127,17 +333,22 @@ func (gt *GrpcTransport) Listen(srv GrpcTransport_ListenServer) error {
 }
 
 // Start starts the networking module by initializing and starting the internal gRPC server,
-// listening on the port determined by the membership and own ID.
+// listening on the port determined by the own address in the AddressBook.
 // Before ths method is called, no other GrpcTransports can connect to this one.
 func (gt *GrpcTransport) Start() error {
 
-	// Obtain own port number from membership.
-	_, ownPort, err := splitAddrPort(gt.membership[gt.ownId])
+	// Obtain own port number from the address book.
+	ownAddr, _ := gt.addressBook.Lookup(gt.ownId)
+	_, ownPort, err := splitAddrPort(ownAddr)
 
 	gt.logger.Log(logging.LevelInfo, fmt.Sprintf("Listening for connections on port %d", ownPort))
 
 	// Create a gRPC server and assign it the logic of this module.
-	gt.grpcServer = grpc.NewServer()
+	var serverOpts []grpc.ServerOption
+	if gt.tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(gt.tlsConfig.serverCredentials()))
+	}
+	gt.grpcServer = grpc.NewServer(serverOpts...)
 	RegisterGrpcTransportServer(gt.grpcServer, gt)
 
 	// Start listening on the network
@@ -164,7 +375,7 @@ func (gt *GrpcTransport) Stop() {
 
 	// Close connections to other nodes.
 	for id, connection := range gt.connections {
-		if _, err := connection.CloseAndRecv(); err != nil {
+		if err := connection.close(); err != nil {
 			gt.logger.Log(logging.LevelWarn, fmt.Sprintf("Could not close connection to node %d: %v", id, err))
 		}
 	}
@@ -181,31 +392,36 @@ func (gt *GrpcTransport) ServerError() error {
 	return gt.grpcServerError
 }
 
-// Connect establishes (in parallel) network connections to all nodes in the system.
-// The other nodes' GrpcTransport modules must be running.
+// Connect establishes (in parallel) network connections to all nodes in the AddressBook.
+// The other nodes' GrpcTransport modules should be running.
+// If a connection cannot be established within connectTimeout, it is re-attempted when sending a message to the node.
 // Only after Connect() returns, sending messages over this GrpcTransport is possible.
-// TODO: Deal with errors, e.g. when the connection times out (make sure the RPC call in connectToNode() has a timeout).
 func (gt *GrpcTransport) Connect() {
 
+	// Obtain the current addresses of all nodes.
+	addrs := gt.addressBook.Snapshot()
+
 	// Initialize wait group used by the connecting goroutines
 	wg := sync.WaitGroup{}
-	wg.Add(len(gt.membership))
+	wg.Add(len(addrs))
 
 	// Synchronizes concurrent access to connections.
 	lock := sync.Mutex{}
 
-	// For each node in the membership
-	for nodeId, nodeAddr := range gt.membership {
+	// For each node in the address book
+	for nodeId, nodeAddr := range addrs {
 
 		// Launch a goroutine that connects to the node.
 		go func(id t.NodeID, addr string) {
 			defer wg.Done()
 
 			// Create and store connection
-			connection, err := gt.connectToNode(addr) // May take long time, execute before acquiring the lock.
-			lock.Lock()
-			gt.connections[id] = connection
-			lock.Unlock()
+			connection, err := gt.connectToNode(id, addr, connectTimeout) // May take long, execute before acquiring the lock.
+			if err == nil {
+				lock.Lock()
+				gt.connections[id] = connection
+				lock.Unlock()
+			}
 
 			// Print debug info.
 			if err != nil {
@@ -222,8 +438,9 @@ func (gt *GrpcTransport) Connect() {
 	wg.Wait()
 }
 
-// Establishes a connection to a single node at address addrString.
-func (gt *GrpcTransport) connectToNode(addrString string) (GrpcTransport_ListenClient, error) {
+// Establishes a connection to a single node with ID id at address addrString.
+// Fails if the connection cannot be established within the given timeout.
+func (gt *GrpcTransport) connectToNode(id t.NodeID, addrString string, timeout time.Duration) (*connection, error) {
 
 	gt.logger.Log(logging.LevelDebug, fmt.Sprintf("Connecting to node: %s", addrString))
 
@@ -231,11 +448,19 @@ func (gt *GrpcTransport) connectToNode(addrString string) (GrpcTransport_ListenC
 	dialOpts := []grpc.DialOption{
 		grpc.WithBlock(),
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize), grpc.MaxCallSendMsgSize(maxMessageSize)),
-		grpc.WithInsecure(),
+	}
+
+	// Authenticate the node and encrypt the connection if TLS is configured.
+	if gt.tlsConfig != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(gt.tlsConfig.clientCredentials(id)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
 	}
 
 	// Set up a gRPC connection.
-	conn, err := grpc.Dial(addrString, dialOpts...)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addrString, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -253,8 +478,8 @@ func (gt *GrpcTransport) connectToNode(addrString string) (GrpcTransport_ListenC
 		return nil, err
 	}
 
-	// Return the message sink connected to the node.
-	return msgSink, nil
+	// Return the connection to the node.
+	return &connection{conn: conn, addr: addrString, msgSink: msgSink}, nil
 }
 
 // Parses an address string with the format "IPAddress:port" into a string address and an integer port number.
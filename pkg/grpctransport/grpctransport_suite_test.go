@@ -0,0 +1,13 @@
+package grpctransport_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGrpctransport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Grpctransport Suite")
+}
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpctransport_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The TLS test runs a deployment whose nodes and clients communicate over gRPC secured by mutual TLS.
+var _ = Describe("TLS test", func() {
+
+	It("delivers all requests over mutual TLS", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			NumClients:      1,
+			Transport:       "grpc-tls",
+			NumFakeRequests: 10,
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		deploytest.ExpectStopped(deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests)))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
@@ -8,9 +8,9 @@ package iss
 
 import (
 	"container/list"
-	"fmt"
 	"github.com/hyperledger-labs/mirbft/pkg/logging"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
 )
 
 // requestBucket represents a subset of received requests (called a Bucket in ISS)
@@ -48,6 +48,10 @@ type requestBucket struct {
 	//       instead of the whole request reference.
 	reqMap map[string]*list.Element
 
+	// References to all requests in the reqMap, indexed the same way.
+	// They are necessary for moving the requests to another bucket group (see bucketGroup.Regroup).
+	reqRefs map[string]*requestpb.RequestRef
+
 	// TODO: Make sure the system works well even if a malicious client tries to submit conflicting requests.
 	//       If any conflicting requests end up in a bucket, make sure to garbage-collect them.
 
@@ -60,6 +64,7 @@ func newRequestBucket(id int, logger logging.Logger) *requestBucket {
 	return &requestBucket{
 		ID:      id,
 		reqMap:  make(map[string]*list.Element),
+		reqRefs: make(map[string]*requestpb.RequestRef),
 		reqList: list.List{},
 		logger:  logger,
 	}
@@ -93,6 +98,7 @@ func (b *requestBucket) Add(reqRef *requestpb.RequestRef) bool {
 		// Add request to the bucket.
 		e := b.reqList.PushBack(reqRef)
 		b.reqMap[key] = e
+		b.reqRefs[key] = reqRef
 		return true
 	}
 }
@@ -129,6 +135,13 @@ func (b *requestBucket) Contains(reqRef *requestpb.RequestRef) bool {
 	return b.reqMap[reqStrKey(reqRef)] != nil
 }
 
+// CountClientRequests increments the count of each client by the number of its requests present in the bucket.
+func (b *requestBucket) CountClientRequests(counts map[t.ClientID]int) {
+	for e := b.reqList.Front(); e != nil; e = e.Next() {
+		counts[t.ClientID(e.Value.(*requestpb.RequestRef).ClientId)]++
+	}
+}
+
 // RemoveFirst removes the first up to n requests from the bucket and appends them to the accumulator acc.
 // Returns the resulting slice obtained by appending the Requests to acc.
 func (b *requestBucket) RemoveFirst(n int, acc []*requestpb.RequestRef) []*requestpb.RequestRef {
@@ -153,7 +166,8 @@ func (b *requestBucket) Resurrect(reqRef *requestpb.RequestRef) {
 
 	// If request is not in the reqMap or request already is in the bucket, panic. This must never happen.
 	if element, ok := b.reqMap[key]; !ok || element == nil {
-		panic(fmt.Errorf("resurrecting request that has been garbage-collected or never added"))
+		panic(violation(InvariantBucket,
+			"resurrecting request %s that has been garbage-collected or never added to bucket %d", key, b.ID))
 	}
 
 	// Add request to the front of the list.
@@ -163,3 +177,33 @@ func (b *requestBucket) Resurrect(reqRef *requestpb.RequestRef) {
 	e := b.reqList.PushFront(reqRef)
 	b.reqMap[key] = e
 }
+
+// moveTo moves all requests from this bucket to the buckets of the given group they map to.
+// The requests present in this bucket are added to the back of the target buckets, preserving their order.
+// The requests that have been removed from this bucket are marked as removed in the target buckets,
+// such that they cannot be added again there either.
+func (b *requestBucket) moveTo(buckets bucketGroup) {
+
+	// Move the requests present in the bucket.
+	present := make(map[string]struct{}, b.Len())
+	for e := b.reqList.Front(); e != nil; e = e.Next() {
+		reqRef := e.Value.(*requestpb.RequestRef)
+		present[reqStrKey(reqRef)] = struct{}{}
+		buckets.RequestBucket(reqRef).Add(reqRef)
+	}
+
+	// Mark the removed requests as such.
+	// Note that the map entries of requests removed by RemoveFirst still point to their (unlinked) list elements.
+	for key, reqRef := range b.reqRefs {
+		if _, ok := present[key]; ok {
+			continue
+		}
+		target := buckets.RequestBucket(reqRef)
+		target.reqMap[key] = nil
+		target.reqRefs[key] = reqRef
+	}
+
+	b.reqList.Init()
+	b.reqMap = make(map[string]*list.Element)
+	b.reqRefs = make(map[string]*requestpb.RequestRef)
+}
@@ -27,6 +27,17 @@ func newBuckets(numBuckets int, logger logging.Logger) *bucketGroup {
 	return (*bucketGroup)(&buckets)
 }
 
+// Regroup returns a new group of numBuckets initialized buckets and moves all requests from this group to it,
+// each request to the bucket it maps to in the new group (see RequestBucket).
+// This group is left empty. The logger will be used to output bucket-related debugging messages.
+func (buckets bucketGroup) Regroup(numBuckets int, logger logging.Logger) *bucketGroup {
+	newGroup := newBuckets(numBuckets, logger)
+	for _, b := range buckets {
+		b.moveTo(*newGroup)
+	}
+	return newGroup
+}
+
 // Get returns the bucket with id bID.
 func (buckets bucketGroup) Get(bID int) *requestBucket {
 	return buckets[bID]
@@ -59,7 +70,7 @@ func (buckets bucketGroup) Select(bucketIDs []int) bucketGroup {
 // Thus, the same request may map to some bucket in one group and to a different bucket in a different group,
 // even if the former bucket is part of the latter group.
 func (buckets bucketGroup) RequestBucket(reqRef *requestpb.RequestRef) *requestBucket {
-	bucketID := int(reqRef.ClientId+reqRef.ReqNo) % len(buckets) // If types change, this might need to be updated.
+	bucketID := int((reqRef.ClientId + reqRef.ReqNo) % uint64(len(buckets))) // If types change, this might need to be updated.
 	return buckets.Get(bucketID)
 }
 
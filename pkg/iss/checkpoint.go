@@ -32,22 +32,56 @@ type checkpointTracker struct {
 	// i.e., "between" seqNr-1 and seqNr.
 	seqNr t.SeqNr
 
-	// The IDs of nodes to execute this instance of the checkpoint protocol.
+	// The ID of this node.
+	ownID t.NodeID
+
+	// The IDs of nodes to execute this instance of the checkpoint protocol
+	// and the quorums derived from them, which stay the same even if the membership of ISS changes meanwhile.
 	membership []t.NodeID
+	quorums    quorums
+
+	// The learners (see Config.Learners) of the epoch executing the checkpoint protocol.
+	// They receive the Checkpoint messages of the members, but their own are neither sent nor counted.
+	learners []t.NodeID
+
+	// The configuration of the epoch starting at this checkpoint and the ID of the epoch's first orderer.
+	// The membership of the epoch differs from the one executing the checkpoint protocol
+	// if a configuration change takes effect at this checkpoint.
+	// The membership, the learners, the number of buckets, and the segment length, as well as the orderer ID,
+	// are part of the persisted checkpoint, as they are necessary for starting from it
+	// (when recovering from the WAL or when joining a running network).
+	epochConfig   *Config
+	firstInstance t.SBInstanceID
+
+	// Version of the epoch's configuration (see ISS.configEpoch).
+	// Only Checkpoint messages carrying the same version count as confirmations of this checkpoint.
+	configEpoch t.EpochNr
+
+	// The client changes and node key rotations made by configuration requests
+	// up to the epoch starting at this checkpoint.
+	clientKeys []*isspb.ClientKey
+	nodeKeys   []*isspb.NodeKey
 
 	// Application snapshot data associated with this checkpoint.
 	appSnapshot []byte
 
-	// Set of nodes from which any Checkpoint message has been received.
+	// Client low watermarks after applying all batches below seqNr, persisted along with the snapshot.
+	clientWatermarks []*isspb.ClientWatermark
+
+	// Set of nodes from which any Checkpoint message has been received,
+	// along with the configuration version each of these messages carried.
 	// This is necessary for ignoring all but the first message a node sends, regardless of the snapshot hash.
-	confirmations map[t.NodeID]struct{}
+	// The versions are only checked when counting the confirmations,
+	// since messages might be received before the checkpoint's own configuration version is known.
+	confirmations map[t.NodeID]t.EpochNr
 }
 
 // newCheckpointTracker allocates and returns a new instance of a checkpointTracker associated with sequence number sn.
-func newCheckpointTracker(sn t.SeqNr) *checkpointTracker {
+func newCheckpointTracker(ownID t.NodeID, sn t.SeqNr) *checkpointTracker {
 	return &checkpointTracker{
+		ownID:         ownID,
 		seqNr:         sn,
-		confirmations: make(map[t.NodeID]struct{}),
+		confirmations: make(map[t.NodeID]t.EpochNr),
 		// the epoch and membership fields will be set later by iss.startCheckpoint
 		// the appSnapshot field will be set by ProcessAppSnapshot
 	}
@@ -60,7 +94,7 @@ func (iss *ISS) getCheckpointTracker(sn t.SeqNr) *checkpointTracker {
 
 	// If no checkpoint tracker with sequence number sn exists, create a new one.
 	if _, ok := iss.checkpoints[sn]; !ok {
-		iss.checkpoints[sn] = newCheckpointTracker(sn)
+		iss.checkpoints[sn] = newCheckpointTracker(iss.ownID, sn)
 	}
 
 	// Look up and return checkpoint tracker.
@@ -70,37 +104,70 @@ func (iss *ISS) getCheckpointTracker(sn t.SeqNr) *checkpointTracker {
 // Start initiates the checkpoint protocol among nodes in membership.
 // The checkpoint to be produced encompasses all currently delivered sequence numbers.
 // If Start is called during epoch transition,
-// it must be called with the new epoch number, but the old epoch's membership.
-func (ct *checkpointTracker) Start(epoch t.EpochNr, membership []t.NodeID) *events.EventList {
-
-	// Set the checkpoint's epoch.
+// it must be called with the new epoch number, but the old epoch's membership (and learners).
+// The configuration of the new epoch (epochConfig), the ID of its first orderer, the configuration's version,
+// the client changes (clientKeys) and the node key rotations (nodeKeys) made by configuration requests
+// are recorded in the checkpoint.
+func (ct *checkpointTracker) Start(
+	epoch t.EpochNr,
+	membership []t.NodeID,
+	learners []t.NodeID,
+	epochConfig *Config,
+	firstInstance t.SBInstanceID,
+	configEpoch t.EpochNr,
+	clientKeys []*isspb.ClientKey,
+	nodeKeys []*isspb.NodeKey,
+) *events.EventList {
+
+	// Set the checkpoint's epoch and the configuration of the epoch.
 	ct.epoch = epoch
+	ct.epochConfig = epochConfig
+	ct.firstInstance = firstInstance
+	ct.configEpoch = configEpoch
+	ct.clientKeys = clientKeys
+	ct.nodeKeys = nodeKeys
 
 	// Save the membership this instance of the checkpoint protocol will use.
 	// This is required in case where the membership changes before the checkpoint sub-protocol finishes.
 	// That is also why the content of the Membership slice needs to be copied.
 	ct.membership = make([]t.NodeID, len(membership), len(membership))
 	copy(ct.membership, membership)
+	ct.quorums = newQuorums(ct.membership)
+	ct.learners = append([]t.NodeID{}, learners...)
 
 	// Request a snapshot of the application state.
 	// TODO: also get a snapshot of the shared state
 	return (&events.EventList{}).PushBack(events.AppSnapshotRequest(ct.seqNr))
 }
 
-func (ct *checkpointTracker) ProcessAppSnapshot(snapshot []byte) *events.EventList {
+// ProcessAppSnapshot saves the application snapshot, persists the checkpoint (along with the client watermarks
+// corresponding to the snapshot), and sends a Checkpoint message carrying the given timestamp
+// (the local wall clock time, used by other nodes for clock skew detection).
+func (ct *checkpointTracker) ProcessAppSnapshot(
+	snapshot []byte,
+	clientWatermarks []*isspb.ClientWatermark,
+	timestamp int64,
+) *events.EventList {
 
 	// Save received snapshot
 	// TODO: Compute and save the hash of the snapshot as well.
 	ct.appSnapshot = snapshot
+	ct.clientWatermarks = clientWatermarks
 
 	// Write Checkpoint to WAL
-	walEvent := events.WALAppend(PersistCheckpointEvent(ct.seqNr, ct.appSnapshot), t.WALRetIndex(ct.epoch))
+	walEvent := events.WALAppend(PersistCheckpointEvent(ct.persistCheckpoint()), t.WALRetIndex(ct.epoch))
 
 	// Send a checkpoint message to all nodes after persisting checkpoint to the WAL.
+	// Learners (not being part of the membership) only persist the checkpoint.
 	// TODO: Add hash of the snapshot
 	// TODO: Add signature.
 	// TODO: Implement checkpoint message retransmission.
-	walEvent.FollowUp(events.SendMessage(CheckpointMessage(ct.epoch, ct.seqNr), ct.membership))
+	if _, ok := membershipSet(ct.membership)[ct.ownID]; ok {
+		walEvent.FollowUp(events.SendMessage(
+			CheckpointMessage(ct.epoch, ct.seqNr, ct.configEpoch, timestamp),
+			ct.recipients(),
+		))
+	}
 
 	// If the app snapshot was the last thing missing for the checkpoint to become stable,
 	// also produce the necessary events.
@@ -112,6 +179,18 @@ func (ct *checkpointTracker) ProcessAppSnapshot(snapshot []byte) *events.EventLi
 	return (&events.EventList{}).PushBack(walEvent)
 }
 
+// recipients returns the nodes the Checkpoint message is sent to: the membership executing the checkpoint protocol,
+// as well as the learners of both the epoch executing it and the epoch starting at the checkpoint.
+func (ct *checkpointTracker) recipients() []t.NodeID {
+	recipients := append([]t.NodeID{}, ct.membership...)
+	for _, nodeID := range append(append([]t.NodeID{}, ct.learners...), ct.epochConfig.Learners...) {
+		if _, ok := membershipSet(recipients)[nodeID]; !ok {
+			recipients = append(recipients, nodeID)
+		}
+	}
+	return recipients
+}
+
 func (ct *checkpointTracker) applyMessage(chkpMsg *isspb.Checkpoint, source t.NodeID) *events.EventList {
 
 	// If checkpoint is already stable, ignore message.
@@ -125,7 +204,11 @@ func (ct *checkpointTracker) applyMessage(chkpMsg *isspb.Checkpoint, source t.No
 	//       separately keeping the set of nodes from which a Checkpoint message has been received.
 
 	// Ignore duplicate messages (regardless of snapshot hash).
-	if _, ok := ct.confirmations[source]; ok {
+	// A node confirming the checkpoint with two different configuration versions, however, is reported as equivocating.
+	if configEpoch, ok := ct.confirmations[source]; ok {
+		if configEpoch != t.EpochNr(chkpMsg.ConfigEpoch) {
+			return (&events.EventList{}).PushBack(events.Equivocation(source, ct.epoch, ct.seqNr, "checkpoint"))
+		}
 		return &events.EventList{}
 	}
 
@@ -134,7 +217,7 @@ func (ct *checkpointTracker) applyMessage(chkpMsg *isspb.Checkpoint, source t.No
 
 	// Note the reception of a Checkpoint message from node `source`.
 	// TODO: take the snapshot hash into account. Separate data structures will be needed for that.
-	ct.confirmations[source] = struct{}{}
+	ct.confirmations[source] = t.EpochNr(chkpMsg.ConfigEpoch)
 
 	// If, after having applied this message, the checkpoint became stable, produce the necessary events.
 	if ct.stable() {
@@ -144,8 +227,40 @@ func (ct *checkpointTracker) applyMessage(chkpMsg *isspb.Checkpoint, source t.No
 	}
 }
 
+// persistCheckpoint returns the protobuf representation of the checkpoint, as persisted in the WAL.
+// It must only be called after the application snapshot has been obtained.
+func (ct *checkpointTracker) persistCheckpoint() *isspb.PersistCheckpoint {
+	return &isspb.PersistCheckpoint{
+		Sn:               ct.seqNr.Pb(),
+		AppSnapshot:      ct.appSnapshot,
+		ClientWatermarks: ct.clientWatermarks,
+		Membership:       t.NodeIDSlicePb(ct.epochConfig.Membership),
+		FirstInstance:    ct.firstInstance.Pb(),
+		NumBuckets:       uint64(ct.epochConfig.NumBuckets),
+		SegmentLength:    uint64(ct.epochConfig.SegmentLength),
+		ClientKeys:       ct.clientKeys,
+		ConfigEpoch:      ct.configEpoch.Pb(),
+		NodeKeys:         ct.nodeKeys,
+		Learners:         t.NodeIDSlicePb(ct.epochConfig.Learners),
+	}
+}
+
 func (ct *checkpointTracker) stable() bool {
-	return ct.appSnapshot != nil && len(ct.confirmations) >= strongQuorum(len(ct.membership))
+	// The configuration version is only known (and the confirmations only need to be counted)
+	// once the snapshot has been obtained, as the checkpoint tracker is started before requesting the snapshot.
+	if ct.appSnapshot == nil {
+		return false
+	}
+
+	// Only count the confirmations of the members, generated under the checkpoint's configuration.
+	members := membershipSet(ct.membership)
+	numConfirmations := 0
+	for nodeID, configEpoch := range ct.confirmations {
+		if _, ok := members[nodeID]; ok && configEpoch == ct.configEpoch {
+			numConfirmations++
+		}
+	}
+	return numConfirmations >= ct.quorums.intersection
 }
 
 func (ct *checkpointTracker) announceStable() *events.EventList {
@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"sort"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// clientWatermarks tracks, for each client, the low watermark of its requests,
+// i.e., the lowest request number such that all requests of the client with lower request numbers
+// have been committed and are covered by a stable checkpoint.
+// The data of requests below the low watermark is never needed again and can be pruned from the request store.
+// Only requests committed at sequence numbers being garbage-collected are passed to the tracker,
+// so that the watermarks never move past requests that might still be needed for recovery.
+// The low watermarks (but not the committed requests above them) are part of the persisted checkpoints
+// and are restored when a node recovers a stable checkpoint from the WAL.
+type clientWatermarks struct {
+
+	// Low watermark of each client that has any request committed.
+	low map[t.ClientID]t.ReqNo
+
+	// For each client, the request numbers above the low watermark that have already been committed.
+	// They wait for the requests below them to be committed before the low watermark can advance past them.
+	committed map[t.ClientID]map[t.ReqNo]struct{}
+
+	// Clients whose low watermark advanced since the last call to advanced().
+	moved map[t.ClientID]struct{}
+}
+
+// newClientWatermarks returns a new clientWatermarks with the low watermarks of all clients at zero.
+func newClientWatermarks() *clientWatermarks {
+	return &clientWatermarks{
+		low:       make(map[t.ClientID]t.ReqNo),
+		committed: make(map[t.ClientID]map[t.ReqNo]struct{}),
+		moved:     make(map[t.ClientID]struct{}),
+	}
+}
+
+// restoreClientWatermarks returns a new clientWatermarks with the low watermarks
+// restored from their protobuf representation (as stored in a persisted checkpoint).
+// All restored clients are reported by the next call to advanced(),
+// such that requests persisted below the restored watermarks can be pruned.
+func restoreClientWatermarks(watermarks []*isspb.ClientWatermark) *clientWatermarks {
+	cw := newClientWatermarks()
+	for _, wm := range watermarks {
+		cw.low[t.ClientID(wm.ClientId)] = t.ReqNo(wm.ReqNo)
+		cw.moved[t.ClientID(wm.ClientId)] = struct{}{}
+	}
+	return cw
+}
+
+// clone returns a deep copy of the clientWatermarks.
+func (cw *clientWatermarks) clone() *clientWatermarks {
+	c := newClientWatermarks()
+	for clientID, low := range cw.low {
+		c.low[clientID] = low
+	}
+	for clientID, committed := range cw.committed {
+		c.committed[clientID] = make(map[t.ReqNo]struct{}, len(committed))
+		for reqNo := range committed {
+			c.committed[clientID][reqNo] = struct{}{}
+		}
+	}
+	for clientID := range cw.moved {
+		c.moved[clientID] = struct{}{}
+	}
+	return c
+}
+
+// commit registers a committed request, advancing the low watermark of its client if possible.
+func (cw *clientWatermarks) commit(reqRef *requestpb.RequestRef) {
+	clientID := t.ClientID(reqRef.ClientId)
+	reqNo := t.ReqNo(reqRef.ReqNo)
+
+	// Ignore requests below the low watermark (e.g. the same request committed twice).
+	low := cw.low[clientID]
+	if reqNo < low {
+		return
+	}
+
+	// Register the committed request.
+	committed, ok := cw.committed[clientID]
+	if !ok {
+		committed = make(map[t.ReqNo]struct{})
+		cw.committed[clientID] = committed
+	}
+	committed[reqNo] = struct{}{}
+
+	// Advance the low watermark past all contiguously committed requests.
+	for _, ok := committed[low]; ok; _, ok = committed[low] {
+		delete(committed, low)
+		low++
+	}
+
+	if low != cw.low[clientID] {
+		cw.low[clientID] = low
+		cw.moved[clientID] = struct{}{}
+	}
+}
+
+// advanced returns the IDs of clients whose low watermarks advanced since the last invocation of advanced,
+// in increasing order. Clients whose watermarks have not moved are omitted.
+func (cw *clientWatermarks) advanced() []t.ClientID {
+	clientIDs := make([]t.ClientID, 0, len(cw.moved))
+	for clientID := range cw.moved {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Slice(clientIDs, func(i, j int) bool { return clientIDs[i] < clientIDs[j] })
+
+	cw.moved = make(map[t.ClientID]struct{})
+	return clientIDs
+}
+
+// watermark returns the low watermark of the given client.
+func (cw *clientWatermarks) watermark(clientID t.ClientID) t.ReqNo {
+	return cw.low[clientID]
+}
+
+// pb returns the protobuf representation of the low watermarks, in increasing order of client IDs.
+// Clients with a zero low watermark are omitted.
+func (cw *clientWatermarks) pb() []*isspb.ClientWatermark {
+	watermarks := make([]*isspb.ClientWatermark, 0, len(cw.low))
+	for clientID, low := range cw.low {
+		if low > 0 {
+			watermarks = append(watermarks, &isspb.ClientWatermark{ClientId: clientID.Pb(), ReqNo: low.Pb()})
+		}
+	}
+	sort.Slice(watermarks, func(i, j int) bool { return watermarks[i].ClientId < watermarks[j].ClientId })
+	return watermarks
+}
+
+// status returns the status of the clients that have any request committed or ready,
+// given the number of ready requests of each client, in increasing order of client IDs.
+func (cw *clientWatermarks) status(ready map[t.ClientID]int) []*isspb.ClientStatus {
+	clients := make(map[t.ClientID]struct{})
+	for clientID := range ready {
+		clients[clientID] = struct{}{}
+	}
+	for clientID := range cw.low {
+		clients[clientID] = struct{}{}
+	}
+	for clientID := range cw.committed {
+		clients[clientID] = struct{}{}
+	}
+
+	statuses := make([]*isspb.ClientStatus, 0, len(clients))
+	for clientID := range clients {
+		statuses = append(statuses, &isspb.ClientStatus{
+			ClientId:     clientID.Pb(),
+			Ready:        uint64(ready[clientID]),
+			LowWatermark: cw.low[clientID].Pb(),
+			Committed:    uint64(len(cw.committed[clientID])),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ClientId < statuses[j].ClientId })
+	return statuses
+}
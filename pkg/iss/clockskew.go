@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// clockSkewDetector estimates the skew of other nodes' wall clocks relative to the local one.
+// Although ISS itself is driven exclusively by logical time (ticks), gross skew of the wall clocks across nodes
+// breaks the correlation of logs of different nodes and the tuning of client-side timeouts.
+// Thus, each node piggybacks a coarse timestamp on its Checkpoint messages
+// and the receiver compares it with its own clock.
+// The estimate includes the network delay of the Checkpoint message and thus is only meaningful for detecting
+// skew considerably larger than the network delay.
+// The estimates never influence the protocol. They are only logged and exposed in the protocol Status.
+type clockSkewDetector struct {
+
+	// Source of the local wall clock time.
+	now func() time.Time
+
+	// Skew larger than maxSkew (in absolute value) makes the detector log a warning.
+	// If set to zero, no warnings are logged.
+	maxSkew time.Duration
+
+	// Latest skew estimate for each node from which a timestamped Checkpoint message has been received.
+	skews map[t.NodeID]time.Duration
+
+	// Logger for outputting the warnings.
+	logger logging.Logger
+}
+
+// newClockSkewDetector returns a new clockSkewDetector with no skew estimates.
+func newClockSkewDetector(now func() time.Time, maxSkew time.Duration, logger logging.Logger) *clockSkewDetector {
+	return &clockSkewDetector{
+		now:     now,
+		maxSkew: maxSkew,
+		skews:   make(map[t.NodeID]time.Duration),
+		logger:  logger,
+	}
+}
+
+// Timestamp returns the current local wall clock time to be included in an outgoing Checkpoint message.
+func (csd *clockSkewDetector) Timestamp() int64 {
+	return csd.now().UnixNano() / int64(time.Millisecond)
+}
+
+// Observe updates the skew estimate for node source based on the timestamp of a received Checkpoint message.
+// Messages without a timestamp (zero value) are ignored.
+func (csd *clockSkewDetector) Observe(source t.NodeID, timestamp int64) {
+	if timestamp == 0 {
+		return
+	}
+
+	skew := time.Duration(timestamp-csd.Timestamp()) * time.Millisecond
+	csd.skews[source] = skew
+
+	if csd.maxSkew != 0 && (skew > csd.maxSkew || skew < -csd.maxSkew) {
+		csd.logger.Log(logging.LevelWarn, "Detected clock skew. Check the clock synchronization of the nodes.",
+			"node", source, "skew", skew.String(), "maxSkew", csd.maxSkew.String())
+	}
+}
+
+// Status returns the current skew estimates in the order given by membership.
+func (csd *clockSkewDetector) Status(membership []t.NodeID) []*isspb.ClockSkew {
+	skews := make([]*isspb.ClockSkew, 0, len(csd.skews))
+	for _, nodeID := range membership {
+		if skew, ok := csd.skews[nodeID]; ok {
+			skews = append(skews, &isspb.ClockSkew{
+				NodeId: nodeID.Pb(),
+				SkewMs: int64(skew / time.Millisecond),
+			})
+		}
+	}
+	return skews
+}
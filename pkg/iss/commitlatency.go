@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Inclusive upper bounds (in ticks) of the bins of the commit latency histograms.
+// Latencies above the last bound are counted in an extra bin.
+var commitLatencyBounds = []uint64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512}
+
+// LatencyHistogram is a histogram of the commit latencies of requests,
+// i.e., of the times (in ticks of the logical clock) from the requests becoming ready at this node
+// (i.e., available and authenticated, see LeaderStats.TotalRequestAge) to the requests being committed.
+// Comparing the histograms of different buckets or leaders makes it easy to spot a slow leader
+// that only degrades the latency of the requests in the buckets it is assigned.
+type LatencyHistogram struct {
+
+	// Inclusive upper bounds of the histogram bins, in ticks.
+	UpperBounds []uint64
+
+	// Number of requests in each bin. Counts has one more element than UpperBounds,
+	// the last one counting the requests with a latency above the last bound.
+	Counts []uint64
+
+	// Sum of the latencies of all requests, in ticks.
+	Sum uint64
+
+	// Number of requests in the histogram.
+	Count uint64
+}
+
+// newLatencyHistogram returns a new, empty histogram with the bins defined by commitLatencyBounds.
+func newLatencyHistogram() *LatencyHistogram {
+	return &LatencyHistogram{
+		UpperBounds: commitLatencyBounds,
+		Counts:      make([]uint64, len(commitLatencyBounds)+1),
+	}
+}
+
+// Mean returns the average latency (in ticks) of the requests in the histogram.
+func (h *LatencyHistogram) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return float64(h.Sum) / float64(h.Count)
+}
+
+// observe adds a request with the given latency to the histogram.
+func (h *LatencyHistogram) observe(latency uint64) {
+	bin := sort.Search(len(h.UpperBounds), func(i int) bool { return latency <= h.UpperBounds[i] })
+	h.Counts[bin]++
+	h.Sum += latency
+	h.Count++
+}
+
+// copy returns a deep copy of the histogram.
+func (h *LatencyHistogram) copy() LatencyHistogram {
+	c := *h
+	c.Counts = append([]uint64{}, h.Counts...)
+	return c
+}
+
+// pb returns the protobuf representation of the histogram, identified by id.
+func (h *LatencyHistogram) pb(id uint64) *isspb.CommitLatency {
+	return &isspb.CommitLatency{
+		Id:          id,
+		UpperBounds: append([]uint64{}, h.UpperBounds...),
+		Counts:      append([]uint64{}, h.Counts...),
+		Sum:         h.Sum,
+		Count:       h.Count,
+	}
+}
+
+// commitLatencyTracker collects the commit latency histograms of all buckets and leaders,
+// accumulated since the start of the node.
+// All its methods are safe to be called concurrently, as the histograms are read by the CommitLatencies method of ISS.
+type commitLatencyTracker struct {
+	byBucket map[int]*LatencyHistogram
+	byLeader map[t.NodeID]*LatencyHistogram
+	lock     sync.Mutex
+}
+
+// newCommitLatencyTracker returns a new commitLatencyTracker with empty histograms.
+func newCommitLatencyTracker() *commitLatencyTracker {
+	return &commitLatencyTracker{
+		byBucket: make(map[int]*LatencyHistogram),
+		byLeader: make(map[t.NodeID]*LatencyHistogram),
+	}
+}
+
+// batchCommitted records the latencies (ages) of the requests of a batch committed in a segment of leader.
+// Each request is attributed to the bucket it belongs to in buckets.
+func (clt *commitLatencyTracker) batchCommitted(leader t.NodeID, reqAges []requestAge, buckets *bucketGroup) {
+	if len(reqAges) == 0 {
+		return
+	}
+
+	clt.lock.Lock()
+	defer clt.lock.Unlock()
+
+	if _, ok := clt.byLeader[leader]; !ok {
+		clt.byLeader[leader] = newLatencyHistogram()
+	}
+	for _, reqAge := range reqAges {
+		bucketID := buckets.RequestBucket(reqAge.reqRef).ID
+		if _, ok := clt.byBucket[bucketID]; !ok {
+			clt.byBucket[bucketID] = newLatencyHistogram()
+		}
+		clt.byBucket[bucketID].observe(reqAge.age)
+		clt.byLeader[leader].observe(reqAge.age)
+	}
+}
+
+// snapshot returns copies of the histograms of all buckets and leaders.
+func (clt *commitLatencyTracker) snapshot() (map[int]LatencyHistogram, map[t.NodeID]LatencyHistogram) {
+	clt.lock.Lock()
+	defer clt.lock.Unlock()
+
+	byBucket := make(map[int]LatencyHistogram, len(clt.byBucket))
+	for bucketID, h := range clt.byBucket {
+		byBucket[bucketID] = h.copy()
+	}
+	byLeader := make(map[t.NodeID]LatencyHistogram, len(clt.byLeader))
+	for leader, h := range clt.byLeader {
+		byLeader[leader] = h.copy()
+	}
+	return byBucket, byLeader
+}
+
+// status returns the protobuf representation of the histograms of all buckets and of all leaders,
+// each ordered by ID.
+func (clt *commitLatencyTracker) status() ([]*isspb.CommitLatency, []*isspb.CommitLatency) {
+	clt.lock.Lock()
+	defer clt.lock.Unlock()
+
+	byBucket := make([]*isspb.CommitLatency, 0, len(clt.byBucket))
+	for bucketID, h := range clt.byBucket {
+		byBucket = append(byBucket, h.pb(uint64(bucketID)))
+	}
+	sort.Slice(byBucket, func(i, j int) bool { return byBucket[i].Id < byBucket[j].Id })
+
+	byLeader := make([]*isspb.CommitLatency, 0, len(clt.byLeader))
+	for leader, h := range clt.byLeader {
+		byLeader = append(byLeader, h.pb(leader.Pb()))
+	}
+	sort.Slice(byLeader, func(i, j int) bool { return byLeader[i].Id < byLeader[j].Id })
+
+	return byBucket, byLeader
+}
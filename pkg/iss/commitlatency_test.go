@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/metrics"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+)
+
+var _ = Describe("Commit latency test", func() {
+
+	It("attributes the commit latencies of requests to buckets and leaders", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Export the commit latencies maintained by the protocol of each replica as metrics.
+		collectors := make([]*metrics.Collector, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			collectors[i] = metrics.NewCollector()
+			collector := collectors[i]
+			replica.OnProtocol = func(protocol *iss.ISS) {
+				collector.RegisterCommitLatencies(protocol)
+			}
+		}
+
+		finalStatuses := deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(finalStatus.StatusErr).NotTo(HaveOccurred())
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+			// Each request that became ready locally is counted once per bucket and once per leader.
+			status := finalStatus.Status.Protocol.GetIss()
+			Expect(status).NotTo(BeNil())
+			countRequests := func(histograms []*isspb.CommitLatency) uint64 {
+				total := uint64(0)
+				for _, h := range histograms {
+					Expect(h.Counts).To(HaveLen(len(h.UpperBounds) + 1))
+					sum := uint64(0)
+					for _, count := range h.Counts {
+						sum += count
+					}
+					Expect(sum).To(Equal(h.Count))
+					total += h.Count
+				}
+				return total
+			}
+			Expect(countRequests(status.BucketCommitLatencies)).To(Equal(uint64(testConfig.NumFakeRequests)))
+			Expect(countRequests(status.LeaderCommitLatencies)).To(Equal(uint64(testConfig.NumFakeRequests)))
+			for _, h := range status.LeaderCommitLatencies {
+				Expect(h.Id).To(BeNumerically("<", testConfig.NumReplicas))
+			}
+
+			// The same histograms are exported as metrics.
+			recorder := httptest.NewRecorder()
+			collectors[i].ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+			scraped := recorder.Body.String()
+			Expect(scraped).To(ContainSubstring("# TYPE mirbft_bucket_commit_latency_ticks histogram\n"))
+			Expect(scraped).To(MatchRegexp(`\nmirbft_bucket_commit_latency_ticks_bucket\{bucket="\d+",le="1"\} \d+\n`))
+			Expect(status.LeaderCommitLatencies).NotTo(BeEmpty())
+			for _, h := range status.LeaderCommitLatencies {
+				Expect(scraped).To(ContainSubstring(fmt.Sprintf(
+					"\nmirbft_leader_commit_latency_ticks_count{leader=\"%d\"} %d\n", h.Id, h.Count)))
+			}
+		}
+	})
+})
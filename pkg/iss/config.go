@@ -8,6 +8,8 @@ package iss
 
 import (
 	"fmt"
+	"time"
+
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
 )
 
@@ -17,9 +19,20 @@ import (
 type Config struct {
 
 	// The IDs of all nodes that execute the protocol.
+	// The membership can change at runtime through configuration requests (see isspb.ConfigChange).
 	// Must not be empty.
 	Membership []t.NodeID
 
+	// The IDs of the learners, i.e., non-voting nodes that receive the proposals and the Checkpoint messages
+	// of the members and apply the log like the members, but do not count toward any quorum,
+	// are never selected as leaders, and do not send any protocol messages themselves.
+	// Learners make it possible to warm up a new node before adding it to the membership
+	// (a configuration change adding a learner to the membership promotes it),
+	// or to follow the log (e.g. for analytics) without affecting the fault tolerance of the system.
+	// A node running as a learner has its own ID in Learners. The learners can change at runtime
+	// through configuration requests (see isspb.ConfigChange). Learners must not be members at the same time.
+	Learners []t.NodeID
+
 	// The length of an ISS segment, in sequence numbers.
 	// This is the number of commitLog entries each orderer needs to output in an epoch.
 	// Depending on the number of leaders (and thus orderers), this will result in epoch of different lengths.
@@ -73,6 +86,16 @@ type Config struct {
 	// Must be positive.
 	RequestNAckTimeout int
 
+	// If set to true, ISS orders requests based on their references (digests) only,
+	// without waiting for the payloads of all proposed requests to be received first.
+	// After a batch is committed, the payloads of the requests this node is missing
+	// are fetched from the leader that proposed the batch, and the batch is only delivered to the application
+	// once all its payloads have been obtained (the fetch is repeated every RequestNAckTimeout ticks).
+	// This decouples ordering latency from payload dissemination, which is beneficial for large requests.
+	// Note that, with HashOnlyOrdering, a batch proposed by a faulty leader not disclosing the payloads
+	// can stall delivery at nodes that do not receive the payloads otherwise (e.g. directly from the client).
+	HashOnlyOrdering bool
+
 	// Maximal number of bytes used for message backlogging buffers
 	// (only message payloads are counted towards MsgBufCapacity).
 	// On reception of a message that the node is not yet ready to process
@@ -84,6 +107,92 @@ type Config struct {
 	// If the capacity is set to 0, all messages that cannot yet be processed are dropped on reception.
 	// Must not be negative.
 	MsgBufCapacity int
+
+	// Thresholds on the statistics of the batches committed in each leader's segment.
+	// At the end of each epoch, leaders whose statistics exceed the thresholds
+	// are reported to the LeaderPolicy as suspected.
+	// If nil, the statistics are still collected (see ISS.LeaderStats), but no leader is ever suspected based on them.
+	LeaderStatsThresholds *LeaderStatsThresholds
+
+	// Maximal tolerated skew of other nodes' wall clocks relative to the local one.
+	// Unlike the other parameters, this one is expressed in real time, as it relates to the wall clock.
+	// The skew is estimated using timestamps piggybacked on Checkpoint messages
+	// and exceeding MaxClockSkew only produces a warning (the protocol itself does not depend on wall clocks).
+	// If set to 0, no warnings are produced. The estimates are still available in the protocol Status.
+	// Must not be negative.
+	MaxClockSkew time.Duration
+
+	// Source of the local wall clock time used for the timestamps piggybacked on Checkpoint messages
+	// (see MaxClockSkew). This is the only wall clock time ISS uses.
+	// Replacing it (e.g. by the virtual time of a simulation, see package testengine)
+	// makes the protocol's output fully reproducible. If nil, time.Now is used.
+	Now func() time.Time
+
+	// Number of consecutive stable checkpoints another member can fail to confirm by the time they become stable
+	// before being flagged as slow. A slow node is announced by an eventpb.NodeSlow notification
+	// (and again when it catches up with the stable checkpoint) and marked as such in the protocol Status.
+	// Being flagged as slow has no effect on the protocol, it only helps operators tell a replica
+	// that cannot keep up from a faulty one.
+	// If set to 0, no node is ever flagged as slow. The lags of the nodes are still available in the protocol Status.
+	// Must not be negative.
+	MaxCheckpointLag int
+
+	// Number of logical time ticks between two rounds of Heartbeat messages this node sends to all other nodes.
+	// Any message received from a node, including a Heartbeat, signals that the node is alive.
+	// If nothing is received from a leader of the current epoch for SuspectTimeout ticks,
+	// the leader is reported to the LeaderPolicy as suspected,
+	// without waiting for the leader's segment to stall or for the end of the epoch.
+	// ATTENTION: Unlike the suspicions based on LeaderStatsThresholds, these suspicions are based on local observations
+	// and thus need not be the same at all nodes. They are only safe to use with a LeaderPolicy that does not
+	// require all nodes to report the same suspicions (e.g. one that ignores them, like the SimpleLeaderPolicy).
+	// If set to 0, no Heartbeat messages are sent and no leader is ever suspected for being unresponsive.
+	// Must not be negative.
+	HeartbeatPeriod int
+
+	// Number of logical time ticks without receiving any message from a leader of the current epoch,
+	// after which the leader is suspected (see HeartbeatPeriod).
+	// Only used if HeartbeatPeriod is non-zero, in which case SuspectTimeout must be greater than HeartbeatPeriod.
+	SuspectTimeout int
+
+	// If set to true, the node joins a network that is already running, instead of starting from the initial state.
+	// The node must have been added to the network's membership by a configuration request (see isspb.ConfigChange)
+	// and Membership must be the membership of the network including the node.
+	// Before participating in the protocol, the joining node obtains the state of the system
+	// at a stable checkpoint at which it already is a member, requesting it from the other nodes
+	// every RequestNAckTimeout ticks until f+1 of them (see quorums.someCorrect) send a matching state.
+	// The remaining parameters must match those of the network (e.g., NumBuckets).
+	// Join is ignored if the node recovers a stable checkpoint from its WAL.
+	Join bool
+
+	// Number of epochs during which, after a node's key has been rotated by a configuration request
+	// (see isspb.ConfigChange), the node's previous key is still accepted along with the new one.
+	// The previous key is retired at the start of the KeyRotationOverlap-th epoch after the one the new key
+	// took effect in. This gives the node time to switch to signing with the new key (e.g., by restarting with it)
+	// without being unable to communicate in the meantime.
+	// If set to 0, the previous key is retired as soon as the new key takes effect.
+	// Must not be negative.
+	KeyRotationOverlap int
+
+	// Maximal number of clients added by configuration requests (see isspb.ConfigChange) at the same time.
+	// The quota is enforced when a configuration request is committed (and thus at the same point at all nodes):
+	// a change that would register more clients is rejected as a whole.
+	// The clients the Node has been created with do not count towards the quota.
+	// If set to 0, the number of clients is not limited. Must not be negative.
+	MaxClients int
+
+	// Maximal sum of the windows (see isspb.ClientKey) of the clients added by configuration requests.
+	// This bounds the number of pending requests, and thus the per-client state, the added clients can make
+	// the nodes maintain. Like MaxClients, the quota is enforced when a configuration request is committed.
+	// If set to a positive value, each added client must have a (non-zero) window.
+	// If set to 0, the aggregate window is not limited. Must not be negative.
+	MaxClientWindows int
+
+	// If set to true, ISS checks the invariants of its internal state (e.g., the ordering of the watermarks,
+	// the monotonicity of quorum confirmations, or the consistency of the request buckets) after each applied event,
+	// failing with an *InvariantViolation as soon as one is violated (see InvariantViolation).
+	// The checks are expensive and intended for tests, CI and soak runs.
+	// Building with the mirbft_invariants tag enables them regardless of this setting.
+	CheckInvariants bool
 }
 
 // CheckConfig checks whether the given configuration satisfies all necessary constraints.
@@ -94,6 +203,18 @@ func CheckConfig(c *Config) error {
 		return fmt.Errorf("empty membership")
 	}
 
+	// Learners must not be members (or listed multiple times).
+	learners := make(map[t.NodeID]struct{}, len(c.Learners))
+	for _, nodeID := range c.Learners {
+		if _, ok := membershipSet(c.Membership)[nodeID]; ok {
+			return fmt.Errorf("learner %d is also a member", nodeID)
+		}
+		if _, ok := learners[nodeID]; ok {
+			return fmt.Errorf("duplicate learner: %d", nodeID)
+		}
+		learners[nodeID] = struct{}{}
+	}
+
 	// Segment length must not be negative.
 	if c.SegmentLength < 0 {
 		return fmt.Errorf("negative SegmentLength: %d", c.SegmentLength)
@@ -141,6 +262,51 @@ func CheckConfig(c *Config) error {
 		return fmt.Errorf("negative MsgBufCapacity: %d", c.MsgBufCapacity)
 	}
 
+	// The leader statistics thresholds, if present, must be within their respective ranges.
+	if c.LeaderStatsThresholds != nil {
+		if c.LeaderStatsThresholds.MaxEmptyBatchRate < 0 || c.LeaderStatsThresholds.MaxEmptyBatchRate > 1 {
+			return fmt.Errorf("MaxEmptyBatchRate out of range [0, 1]: %f", c.LeaderStatsThresholds.MaxEmptyBatchRate)
+		}
+		if c.LeaderStatsThresholds.MaxInclusionSkew < 0 || c.LeaderStatsThresholds.MaxInclusionSkew > 1 {
+			return fmt.Errorf("MaxInclusionSkew out of range [0, 1]: %f", c.LeaderStatsThresholds.MaxInclusionSkew)
+		}
+		if c.LeaderStatsThresholds.MaxMeanRequestAge < 0 {
+			return fmt.Errorf("negative MaxMeanRequestAge: %f", c.LeaderStatsThresholds.MaxMeanRequestAge)
+		}
+	}
+
+	// MaxClockSkew must not be negative.
+	if c.MaxClockSkew < 0 {
+		return fmt.Errorf("negative MaxClockSkew: %v", c.MaxClockSkew)
+	}
+
+	// MaxCheckpointLag must not be negative.
+	if c.MaxCheckpointLag < 0 {
+		return fmt.Errorf("negative MaxCheckpointLag: %d", c.MaxCheckpointLag)
+	}
+
+	// HeartbeatPeriod must not be negative and, if heartbeats are enabled,
+	// SuspectTimeout must be long enough for a Heartbeat to arrive.
+	if c.HeartbeatPeriod < 0 {
+		return fmt.Errorf("negative HeartbeatPeriod: %d", c.HeartbeatPeriod)
+	}
+	if c.HeartbeatPeriod > 0 && c.SuspectTimeout <= c.HeartbeatPeriod {
+		return fmt.Errorf("SuspectTimeout (%d) not greater than HeartbeatPeriod (%d)", c.SuspectTimeout, c.HeartbeatPeriod)
+	}
+
+	// KeyRotationOverlap must not be negative.
+	if c.KeyRotationOverlap < 0 {
+		return fmt.Errorf("negative KeyRotationOverlap: %d", c.KeyRotationOverlap)
+	}
+
+	// The client quotas must not be negative.
+	if c.MaxClients < 0 {
+		return fmt.Errorf("negative MaxClients: %d", c.MaxClients)
+	}
+	if c.MaxClientWindows < 0 {
+		return fmt.Errorf("negative MaxClientWindows: %d", c.MaxClientWindows)
+	}
+
 	// If all checks passed, return nil error.
 	return nil
 }
@@ -160,5 +326,8 @@ func DefaultConfig(membership []t.NodeID) *Config {
 		LeaderPolicy:       &SimpleLeaderPolicy{Membership: membership},
 		RequestNAckTimeout: 16,
 		MsgBufCapacity:     32 * 1024 * 1024, // 32 MiB
+		MaxClockSkew:       5 * time.Second,
+		MaxCheckpointLag:   2,
+		KeyRotationOverlap: 2,
 	}
 }
@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The configuration change test runs a deployment and, while it is running, changes the number of buckets
+// and the segment length (and thus the checkpoint interval) using a configuration request.
+// All nodes must switch to the new configuration in the same epoch and keep processing requests.
+var _ = Describe("Configuration change test", func() {
+
+	It("changes the number of buckets and the segment length", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All nodes accept requests from the configuration client.
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// Create the configuration request.
+		numBuckets := 8
+		segmentLength := 5
+		request := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			NumBuckets:    uint64(numBuckets),
+			SegmentLength: uint64(segmentLength),
+		})
+
+		// Submit the configuration request to all nodes after the network started
+		// and wait until all nodes applied it and switched to the new configuration.
+		var submitErrs []error
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			submitErrs = deploytest.SubmitToAll(deployment.TestReplicas, request)
+			Eventually(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests+1), func() bool {
+				for _, replica := range deployment.TestReplicas {
+					if len(replica.Node().EpochInfo().BucketLeaders) != numBuckets {
+						return false
+					}
+				}
+				return true
+			}), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, finalStatus := range finalStatuses {
+			Expect(submitErrs[i]).NotTo(HaveOccurred())
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		for i, node := range nodes {
+
+			// The buckets of the current epoch have been distributed according to the new configuration.
+			Expect(node.EpochInfo().BucketLeaders).To(HaveLen(numBuckets))
+
+			// The segments of the current epoch have the new length.
+			// The orderer with the highest ID is always one of the current epoch.
+			var buf bytes.Buffer
+			Expect(node.Dump(&buf)).To(Succeed())
+			var dump struct {
+				Protocol struct {
+					Orderers []struct {
+						ID    t.SBInstanceID
+						State struct{ SeqNrs []t.SeqNr }
+					}
+				}
+			}
+			Expect(json.Unmarshal(buf.Bytes(), &dump)).To(Succeed())
+			orderers := dump.Protocol.Orderers
+			Expect(orderers).NotTo(BeEmpty())
+			Expect(orderers[len(orderers)-1].State.SeqNrs).To(HaveLen(segmentLength))
+
+			// All requests, including the configuration request, have been applied.
+			Expect(deployment.TestReplicas[i].App.RequestsProcessed).To(Equal(uint64(testConfig.NumFakeRequests + 1)))
+		}
+	})
+})
+
+// The configuration version test changes the configuration of a running deployment.
+// All nodes must agree on the new configuration version (the epoch in which the change took effect)
+// and keep establishing stable checkpoints using messages that carry it.
+var _ = Describe("Configuration version test", func() {
+
+	It("agrees on the configuration version after a configuration change", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All nodes accept requests from the configuration client.
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// Submit a configuration request changing the segment length to all nodes after the network started.
+		request := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			SegmentLength: 5,
+		})
+		// Wait until all nodes established a checkpoint under the new configuration version.
+		var submitErrs []error
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			submitErrs = deploytest.SubmitToAll(deployment.TestReplicas, request)
+			Eventually(func() bool {
+				for _, replica := range deployment.TestReplicas {
+					var protocolState struct{ ConfigEpoch t.EpochNr }
+					deploytest.DumpProtocol(replica.Node(), &protocolState)
+					checkpoint := replica.Node().StableCheckpoint()
+					if protocolState.ConfigEpoch == 0 || checkpoint == nil || t.EpochNr(checkpoint.Epoch) <= protocolState.ConfigEpoch {
+						return false
+					}
+				}
+				return true
+			}, deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, finalStatus := range finalStatuses {
+			Expect(submitErrs[i]).NotTo(HaveOccurred())
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		var configEpoch t.EpochNr
+		for i, node := range nodes {
+			var buf bytes.Buffer
+			Expect(node.Dump(&buf)).To(Succeed())
+			var dump struct {
+				Protocol struct{ ConfigEpoch t.EpochNr }
+			}
+			Expect(json.Unmarshal(buf.Bytes(), &dump)).To(Succeed())
+
+			// All nodes use the same configuration version, set when the change took effect.
+			Expect(dump.Protocol.ConfigEpoch).NotTo(BeZero())
+			if i == 0 {
+				configEpoch = dump.Protocol.ConfigEpoch
+			}
+			Expect(dump.Protocol.ConfigEpoch).To(Equal(configEpoch))
+
+			// Checkpoints are still established under the new configuration version.
+			Expect(t.EpochNr(node.StableCheckpoint().Epoch)).To(BeNumerically(">", configEpoch))
+		}
+	})
+})
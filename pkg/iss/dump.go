@@ -0,0 +1,270 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/messagebuffer"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The types in this file represent the internal state of ISS in a form that can be serialized using encoding/json
+// (see ISS.DumpState). They are only meant for debugging and their format may change at any time.
+
+// issDump is the representation of the whole state of ISS.
+type issDump struct {
+	OwnID                t.NodeID
+	Membership           []t.NodeID
+	PendingMembership    []t.NodeID
+	ConfigEpoch          t.EpochNr
+	NodeKeys             []nodeKeyDump
+	Joining              bool
+	Epoch                t.EpochNr
+	EpochLeaders         []t.NodeID
+	UnresponsiveLeaders  []t.NodeID
+	HandoffLeaders       []t.NodeID
+	SteppingDown         bool
+	NextDeliveredSN      t.SeqNr
+	GcSN                 t.SeqNr
+	LastStableCheckpoint checkpointDump
+	Orderers             []ordererDump
+	Buckets              []bucketDump
+	UndeliveredLog       []commitLogEntryDump
+	MissingRequests      []missingRequestsDump
+	MissingPayloads      []missingRequestsDump
+	Checkpoints          []checkpointTrackerDump
+	ClientWatermarks     []clientWatermarkDump
+	MessageBuffers       []messageBufferDump
+}
+
+// ordererDump represents an orderer (SB instance) of the current epoch.
+type ordererDump struct {
+	ID    t.SBInstanceID
+	State interface{}
+}
+
+// bucketDump represents the requests waiting in a bucket to be proposed.
+type bucketDump struct {
+	ID       int
+	Leader   t.NodeID
+	Requests int
+}
+
+// commitLogEntryDump represents a committed entry of the log that has not yet been delivered to the application.
+type commitLogEntryDump struct {
+	Sn       t.SeqNr
+	Requests int
+	Aborted  bool
+}
+
+// missingRequestsDump represents the requests a proposal (or a committed batch) is waiting for.
+type missingRequestsDump struct {
+	Sn       t.SeqNr
+	Requests []requestRefDump
+}
+
+// requestRefDump represents a reference to a request.
+type requestRefDump struct {
+	ClientID t.ClientID
+	ReqNo    t.ReqNo
+}
+
+// checkpointDump represents a stable checkpoint.
+type checkpointDump struct {
+	Epoch t.EpochNr
+	Sn    t.SeqNr
+}
+
+// checkpointTrackerDump represents an instance of the checkpoint sub-protocol.
+type checkpointTrackerDump struct {
+	Epoch         t.EpochNr
+	Sn            t.SeqNr
+	HaveSnapshot  bool
+	Confirmations []t.NodeID
+}
+
+// clientWatermarkDump represents the low watermark of a client
+// and the requests above the watermark that have already been committed.
+type clientWatermarkDump struct {
+	ClientID  t.ClientID
+	Low       t.ReqNo
+	Committed []t.ReqNo
+}
+
+// nodeKeyDump represents a node key rotated by a configuration request
+// and the epoch at which the node's previous key is retired (0 if it already has been retired).
+type nodeKeyDump struct {
+	NodeID      t.NodeID
+	RetireEpoch t.EpochNr
+}
+
+// messageBufferDump represents the messages buffered for future epochs from a single node.
+type messageBufferDump struct {
+	NodeID   t.NodeID
+	Capacity int
+	Size     int
+	Messages []string
+}
+
+// DumpState returns a representation of the complete internal state of ISS,
+// including the membership, the orderers of the current epoch, the request buckets, the undelivered part of the commit log,
+// the requests ISS is waiting for, the checkpoint trackers, the client watermarks,
+// and the messages buffered for future epochs (see modules.StateDumper).
+// The representation can be serialized using encoding/json and is only meant for debugging.
+func (iss *ISS) DumpState() (interface{}, error) {
+	dump := &issDump{
+		OwnID:           iss.ownID,
+		Membership:      append([]t.NodeID{}, iss.config.Membership...),
+		ConfigEpoch:     iss.configEpoch,
+		Joining:         iss.joining,
+		Epoch:           iss.epoch,
+		EpochLeaders:    append([]t.NodeID{}, iss.epochLeaders...),
+		NextDeliveredSN: iss.nextDeliveredSN,
+		GcSN:            iss.gcSN,
+		LastStableCheckpoint: checkpointDump{
+			Epoch: t.EpochNr(iss.lastStableCheckpoint.Epoch),
+			Sn:    t.SeqNr(iss.lastStableCheckpoint.Sn),
+		},
+	}
+
+	if iss.pendingConfig != nil {
+		dump.PendingMembership = append([]t.NodeID{}, iss.pendingConfig.Membership...)
+	}
+
+	for _, nodeKey := range nodeKeysPb(iss.nodeKeys) {
+		dump.NodeKeys = append(dump.NodeKeys, nodeKeyDump{
+			NodeID:      t.NodeID(nodeKey.NodeId),
+			RetireEpoch: t.EpochNr(nodeKey.RetireEpoch),
+		})
+	}
+
+	for nodeID := range iss.unresponsiveLeaders {
+		dump.UnresponsiveLeaders = append(dump.UnresponsiveLeaders, nodeID)
+	}
+	sortNodeIDs(dump.UnresponsiveLeaders)
+
+	for nodeID := range iss.handoffLeaders {
+		dump.HandoffLeaders = append(dump.HandoffLeaders, nodeID)
+	}
+	sortNodeIDs(dump.HandoffLeaders)
+	dump.SteppingDown = iss.steppingDown
+
+	// Orderers and buckets of the current epoch.
+	for id, orderer := range iss.orderers {
+		dump.Orderers = append(dump.Orderers, ordererDump{ID: id, State: orderer.DumpState()})
+	}
+	sort.Slice(dump.Orderers, func(i, j int) bool { return dump.Orderers[i].ID < dump.Orderers[j].ID })
+	for bID := 0; bID < iss.config.NumBuckets; bID++ {
+		bucket := bucketDump{ID: bID, Requests: iss.buckets.Get(bID).Len()}
+		if orderer, ok := iss.bucketOrderers[bID]; ok {
+			bucket.Leader = orderer.Segment().Leader
+		}
+		dump.Buckets = append(dump.Buckets, bucket)
+	}
+
+	// Committed, but not yet delivered entries of the log.
+	for sn, entry := range iss.commitLog {
+		if sn < iss.nextDeliveredSN {
+			continue
+		}
+		logEntry := commitLogEntryDump{Sn: sn, Aborted: entry.Aborted}
+		if entry.Batch != nil {
+			logEntry.Requests = len(entry.Batch.Requests)
+		}
+		dump.UndeliveredLog = append(dump.UndeliveredLog, logEntry)
+	}
+	sort.Slice(dump.UndeliveredLog, func(i, j int) bool { return dump.UndeliveredLog[i].Sn < dump.UndeliveredLog[j].Sn })
+
+	dump.MissingRequests = dumpMissingRequests(iss.missingRequests)
+	dump.MissingPayloads = dumpMissingRequests(iss.missingPayloads)
+
+	// Instances of the checkpoint sub-protocol.
+	for sn, checkpoint := range iss.checkpoints {
+		tracker := checkpointTrackerDump{
+			Epoch:        checkpoint.epoch,
+			Sn:           sn,
+			HaveSnapshot: checkpoint.appSnapshot != nil,
+		}
+		for nodeID := range checkpoint.confirmations {
+			tracker.Confirmations = append(tracker.Confirmations, nodeID)
+		}
+		sortNodeIDs(tracker.Confirmations)
+		dump.Checkpoints = append(dump.Checkpoints, tracker)
+	}
+	sort.Slice(dump.Checkpoints, func(i, j int) bool { return dump.Checkpoints[i].Sn < dump.Checkpoints[j].Sn })
+
+	// Client watermarks.
+	clients := make(map[t.ClientID]struct{})
+	for clientID := range iss.clientWatermarks.low {
+		clients[clientID] = struct{}{}
+	}
+	for clientID := range iss.clientWatermarks.committed {
+		clients[clientID] = struct{}{}
+	}
+	for clientID := range clients {
+		watermark := clientWatermarkDump{ClientID: clientID, Low: iss.clientWatermarks.watermark(clientID)}
+		for reqNo := range iss.clientWatermarks.committed[clientID] {
+			watermark.Committed = append(watermark.Committed, reqNo)
+		}
+		sort.Slice(watermark.Committed, func(i, j int) bool { return watermark.Committed[i] < watermark.Committed[j] })
+		dump.ClientWatermarks = append(dump.ClientWatermarks, watermark)
+	}
+	sort.Slice(dump.ClientWatermarks, func(i, j int) bool {
+		return dump.ClientWatermarks[i].ClientID < dump.ClientWatermarks[j].ClientID
+	})
+
+	// Messages buffered for future epochs, listed without removing them from the buffers.
+	for nodeID, buffer := range iss.messageBuffers {
+		bufferDump := messageBufferDump{NodeID: nodeID, Capacity: buffer.Capacity(), Size: buffer.Size()}
+		buffer.Iterate(func(source t.NodeID, msg proto.Message) messagebuffer.Applicable {
+			bufferDump.Messages = append(bufferDump.Messages, fmt.Sprintf("%T %v", msg, msg))
+			return messagebuffer.Future
+		}, nil)
+		dump.MessageBuffers = append(dump.MessageBuffers, bufferDump)
+	}
+	sort.Slice(dump.MessageBuffers, func(i, j int) bool {
+		return dump.MessageBuffers[i].NodeID < dump.MessageBuffers[j].NodeID
+	})
+
+	return dump, nil
+}
+
+// dumpMissingRequests returns the representation of the requests missing for each sequence number,
+// in increasing order of sequence numbers.
+func dumpMissingRequests(missing map[t.SeqNr]*missingRequestInfo) []missingRequestsDump {
+	dumps := make([]missingRequestsDump, 0, len(missing))
+	for sn, info := range missing {
+		dumps = append(dumps, missingRequestsDump{Sn: sn, Requests: dumpRequestRefs(info.Requests)})
+	}
+	sort.Slice(dumps, func(i, j int) bool { return dumps[i].Sn < dumps[j].Sn })
+	return dumps
+}
+
+// dumpRequestRefs returns the representation of the given request references,
+// ordered by client ID and request number.
+func dumpRequestRefs(reqRefs map[string]*requestpb.RequestRef) []requestRefDump {
+	dumps := make([]requestRefDump, 0, len(reqRefs))
+	for _, reqRef := range reqRefs {
+		dumps = append(dumps, requestRefDump{ClientID: t.ClientID(reqRef.ClientId), ReqNo: t.ReqNo(reqRef.ReqNo)})
+	}
+	sort.Slice(dumps, func(i, j int) bool {
+		if dumps[i].ClientID != dumps[j].ClientID {
+			return dumps[i].ClientID < dumps[j].ClientID
+		}
+		return dumps[i].ReqNo < dumps[j].ReqNo
+	})
+	return dumps
+}
+
+// sortNodeIDs sorts a slice of node IDs in increasing order.
+func sortNodeIDs(nodeIDs []t.NodeID) {
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+}
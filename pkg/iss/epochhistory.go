@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Number of the most recent epoch changes retained in the epoch history.
+const epochHistorySize = 16
+
+// epochHistory records why the recent epochs started with the leaders (and membership) they did,
+// such that a post-mortem analysis does not have to reconstruct it from the logs.
+// During an epoch, the causes influencing the next epoch (suspected leaders, handoffs, configuration changes)
+// are accumulated. When the next epoch starts, they are recorded together with the new epoch's leaders.
+// The history is only exposed in the protocol Status and never influences the protocol.
+type epochHistory struct {
+
+	// The most recent epoch changes, oldest first. At most epochHistorySize entries are retained.
+	changes []*isspb.EpochChange
+
+	// The causes observed during the current epoch, to be recorded when the next epoch starts.
+	causes []*isspb.EpochChangeCause
+}
+
+// newEpochHistory returns a new, empty epochHistory.
+func newEpochHistory() *epochHistory {
+	return &epochHistory{
+		changes: make([]*isspb.EpochChange, 0, epochHistorySize),
+		causes:  make([]*isspb.EpochChangeCause, 0),
+	}
+}
+
+// AddCause records a cause of the next epoch change, implicating node nodeID
+// (ignored for causes not related to a particular leader).
+func (eh *epochHistory) AddCause(reason isspb.EpochChangeReason, nodeID t.NodeID) {
+	cause := &isspb.EpochChangeCause{Reason: reason}
+	if reason != isspb.EpochChangeReason_CONFIG_CHANGE && reason != isspb.EpochChangeReason_RESTORED {
+		cause.NodeId = nodeID.Pb()
+	}
+	eh.causes = append(eh.causes, cause)
+}
+
+// EpochStarted records the start of epoch epoch at sequence number firstSN with the given leaders,
+// along with all causes added since the previous epoch started. The oldest entry is dropped if the history is full.
+func (eh *epochHistory) EpochStarted(epoch t.EpochNr, firstSN t.SeqNr, leaders []t.NodeID) {
+	if len(eh.changes) == epochHistorySize {
+		eh.changes = append(eh.changes[:0], eh.changes[1:]...)
+	}
+	eh.changes = append(eh.changes, &isspb.EpochChange{
+		Epoch:   epoch.Pb(),
+		FirstSn: firstSN.Pb(),
+		Leaders: t.NodeIDSlicePb(leaders),
+		Causes:  eh.causes,
+	})
+	eh.causes = make([]*isspb.EpochChangeCause, 0)
+}
+
+// Status returns a copy of the recorded epoch changes, oldest first.
+func (eh *epochHistory) Status() []*isspb.EpochChange {
+	changes := make([]*isspb.EpochChange, len(eh.changes))
+	for i, change := range eh.changes {
+		changes[i] = proto.Clone(change).(*isspb.EpochChange)
+	}
+	return changes
+}
@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The graceful handoff test makes a leader step down, as before a planned shutdown.
+// The leader must hand off its segments instead of stalling them,
+// and all nodes must report it to their leader selection policies.
+var _ = Describe("Graceful handoff test", func() {
+
+	It("hands off the segments of a leader stepping down", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Record the suspicions reported to the leader selection policies.
+		policies := make([]*suspectRecordingPolicy, len(deployment.TestReplicas))
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			policies[i] = &suspectRecordingPolicy{
+				SimpleLeaderPolicy: iss.SimpleLeaderPolicy{Membership: replica.Membership},
+				suspects:           make(map[t.NodeID]struct{}),
+			}
+			replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+			replica.ISSConfig.LeaderPolicy = policies[i]
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// The last replica steps down after the network started.
+		steppingDown := len(deployment.TestReplicas) - 1
+		// Then wait until all nodes observed the handoff in a new epoch and committed all requests.
+		var stepDownErr error
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			node := deployment.TestReplicas[steppingDown].Node()
+			epoch := node.EpochInfo().Epoch
+			stepDownErr = node.StepDown(context.Background())
+			Eventually(deploytest.AllOf(deployment.AllInEpoch(epoch+1), func() bool {
+				for i, replica := range deployment.TestReplicas {
+					if _, ok := policies[i].Suspects()[deployment.TestReplicas[steppingDown].Id]; !ok {
+						return false
+					}
+					for _, clientID := range deployment.TestReplicas[0].ClientIDs {
+						if reqNo, ok := replica.Node().ClientCommitted(clientID); !ok || reqNo < t.ReqNo(testConfig.NumFakeRequests-1) {
+							return false
+						}
+					}
+				}
+				return true
+			}), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		Expect(stepDownErr).NotTo(HaveOccurred())
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+
+			// All nodes observed the handoff and suspect the stepping down node, and only that node.
+			Expect(policies[i].Suspects()).To(Equal(map[t.NodeID]struct{}{
+				deployment.TestReplicas[steppingDown].Id: {},
+			}))
+
+			// The handoffs (one in each epoch the node is still a leader in) are recorded
+			// as the causes of the following epoch changes, and are the only causes.
+			handoffs := 0
+			for _, change := range finalStatus.Status.Protocol.GetIss().EpochHistory {
+				for _, cause := range change.Causes {
+					Expect(cause.Reason).To(Equal(isspb.EpochChangeReason_LEADER_HANDOFF))
+					Expect(t.NodeID(cause.NodeId)).To(Equal(deployment.TestReplicas[steppingDown].Id))
+					handoffs++
+				}
+			}
+			Expect(handoffs).To(BeNumerically(">", 0))
+		}
+
+		// The other leaders kept committing the requests, including those of the stepping down node's buckets.
+		for _, node := range nodes {
+			for _, clientID := range deployment.TestReplicas[0].ClientIDs {
+				reqNo, ok := node.ClientCommitted(clientID)
+				Expect(ok).To(BeTrue())
+				Expect(reqNo).To(Equal(t.ReqNo(testConfig.NumFakeRequests - 1)))
+			}
+		}
+	})
+})
@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+)
+
+// The hash-only ordering test has one replica not receive any requests from the client,
+// such that it has to fetch the payloads of all committed requests from the other replicas.
+var _ = Describe("Hash-only ordering test", func() {
+
+	It("delivers batches whose payloads are fetched after commit", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+			replica.ISSConfig.HashOnlyOrdering = true
+		}
+		deployment.TestReplicas[3].NumFakeRequests = 0
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		// All replicas, including the one that never received the requests directly, must deliver all requests.
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
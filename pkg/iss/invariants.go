@@ -0,0 +1,251 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Names of the invariants checked by ISS (see InvariantViolation).
+const (
+	// The sequence numbers below which the state has been garbage-collected, of the last stable checkpoint,
+	// and of the next batch to deliver are ordered in this way. No commit log entry remains below the first one.
+	InvariantWatermarkOrder = "watermark-order"
+
+	// The epoch, the sequence numbers of the watermarks, and the clients' low watermarks never decrease,
+	// and the requests a client has committed above its low watermark are above it.
+	InvariantMonotonicity = "monotonicity"
+
+	// The quorum sizes match the membership, and the number of confirmations of a checkpoint never decreases.
+	InvariantQuorum = "quorum"
+
+	// The list of requests of each bucket is consistent with the bucket's index of requests,
+	// and each request is in the bucket it maps to.
+	InvariantBucket = "bucket"
+
+	// Each request registered as missing belongs to a proposal that is waiting for it.
+	InvariantMissingRequests = "missing-requests"
+)
+
+// InvariantViolation is a violated invariant of the internal state of ISS, i.e., a bug.
+// When an invariant violation is detected, ISS panics with the *InvariantViolation as value.
+// The Node converts the panic into the error returned by Node.Run (and the Stepper into the error returned by Step),
+// wrapping the *InvariantViolation, such that it can be extracted using errors.As.
+// Most invariants are only checked if Config.CheckInvariants is set.
+type InvariantViolation struct {
+
+	// Name of the violated invariant, one of the Invariant* constants.
+	Invariant string
+
+	// The node and the epoch in which the violation has been detected.
+	NodeID t.NodeID
+	Epoch  t.EpochNr
+
+	// Description of the violation.
+	Details string
+}
+
+func (iv *InvariantViolation) Error() string {
+	return fmt.Sprintf("invariant %s violated at node %d in epoch %d: %s", iv.Invariant, iv.NodeID, iv.Epoch, iv.Details)
+}
+
+// violation returns a new InvariantViolation of the given invariant.
+// The node and the epoch are filled in by ISS.ApplyEvent when the violation is propagated as a panic.
+func violation(invariant string, format string, args ...interface{}) *InvariantViolation {
+	return &InvariantViolation{
+		Invariant: invariant,
+		Details:   fmt.Sprintf(format, args...),
+	}
+}
+
+// invariantChecker checks the invariants of the state of ISS after each applied event (see Config.CheckInvariants).
+// It remembers the values observed by the previous check, for checking that they never decrease.
+type invariantChecker struct {
+	epoch           t.EpochNr
+	gcSN            t.SeqNr
+	stableSN        t.SeqNr
+	nextDeliveredSN t.SeqNr
+	clientLow       map[t.ClientID]t.ReqNo
+
+	// Number of confirmations of each checkpoint tracker.
+	confirmations map[*checkpointTracker]int
+}
+
+// newInvariantChecker returns a new invariantChecker for an ISS instance in its initial state.
+func newInvariantChecker() *invariantChecker {
+	return &invariantChecker{
+		clientLow:     make(map[t.ClientID]t.ReqNo),
+		confirmations: make(map[*checkpointTracker]int),
+	}
+}
+
+// check checks all invariants of the state of iss and returns the first violation found, or nil if there is none.
+func (ic *invariantChecker) check(iss *ISS) *InvariantViolation {
+	for _, checkFunc := range []func(*ISS) *InvariantViolation{
+		ic.checkWatermarks,
+		ic.checkMonotonicity,
+		ic.checkQuorums,
+		checkBuckets,
+		checkMissingRequests,
+	} {
+		if v := checkFunc(iss); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// checkWatermarks checks InvariantWatermarkOrder.
+func (ic *invariantChecker) checkWatermarks(iss *ISS) *InvariantViolation {
+	stableSN := t.SeqNr(iss.lastStableCheckpoint.Sn)
+	if iss.gcSN > stableSN {
+		return violation(InvariantWatermarkOrder,
+			"garbage-collected up to SN %d, beyond the last stable checkpoint at SN %d", iss.gcSN, stableSN)
+	}
+	if stableSN > iss.nextDeliveredSN {
+		return violation(InvariantWatermarkOrder,
+			"last stable checkpoint at SN %d, beyond the next SN to deliver %d", stableSN, iss.nextDeliveredSN)
+	}
+	for sn := range iss.commitLog {
+		if sn < iss.gcSN {
+			return violation(InvariantWatermarkOrder,
+				"commit log entry at SN %d survived garbage collection up to SN %d", sn, iss.gcSN)
+		}
+	}
+	return nil
+}
+
+// checkMonotonicity checks InvariantMonotonicity.
+func (ic *invariantChecker) checkMonotonicity(iss *ISS) *InvariantViolation {
+	stableSN := t.SeqNr(iss.lastStableCheckpoint.Sn)
+	switch {
+	case iss.epoch < ic.epoch:
+		return violation(InvariantMonotonicity, "epoch decreased from %d", ic.epoch)
+	case iss.gcSN < ic.gcSN:
+		return violation(InvariantMonotonicity, "garbage collection SN decreased from %d to %d", ic.gcSN, iss.gcSN)
+	case stableSN < ic.stableSN:
+		return violation(InvariantMonotonicity, "stable checkpoint SN decreased from %d to %d", ic.stableSN, stableSN)
+	case iss.nextDeliveredSN < ic.nextDeliveredSN:
+		return violation(InvariantMonotonicity,
+			"next SN to deliver decreased from %d to %d", ic.nextDeliveredSN, iss.nextDeliveredSN)
+	}
+	ic.epoch, ic.gcSN, ic.stableSN, ic.nextDeliveredSN = iss.epoch, iss.gcSN, stableSN, iss.nextDeliveredSN
+
+	for _, clientID := range sortedClientIDs(iss.clientWatermarks.low) {
+		low := iss.clientWatermarks.low[clientID]
+		if low < ic.clientLow[clientID] {
+			return violation(InvariantMonotonicity,
+				"low watermark of client %d decreased from %d to %d", clientID, ic.clientLow[clientID], low)
+		}
+		ic.clientLow[clientID] = low
+		for reqNo := range iss.clientWatermarks.committed[clientID] {
+			if reqNo <= low {
+				return violation(InvariantMonotonicity,
+					"request %d of client %d registered as committed at or below the low watermark %d", reqNo, clientID, low)
+			}
+		}
+	}
+	return nil
+}
+
+// checkQuorums checks InvariantQuorum.
+func (ic *invariantChecker) checkQuorums(iss *ISS) *InvariantViolation {
+	if expected := newQuorums(iss.config.Membership); iss.quorums != expected {
+		return violation(InvariantQuorum,
+			"quorums %+v do not match the membership of %d nodes", iss.quorums, len(iss.config.Membership))
+	}
+
+	// Forget the garbage-collected checkpoint trackers.
+	for tracker := range ic.confirmations {
+		if iss.checkpoints[tracker.seqNr] != tracker {
+			delete(ic.confirmations, tracker)
+		}
+	}
+
+	for _, sn := range sortedCheckpointSNs(iss.checkpoints) {
+		tracker := iss.checkpoints[sn]
+		confirmations := len(tracker.confirmations)
+		if confirmations < ic.confirmations[tracker] {
+			return violation(InvariantQuorum, "confirmations of checkpoint at SN %d decreased from %d to %d",
+				sn, ic.confirmations[tracker], confirmations)
+		}
+		ic.confirmations[tracker] = confirmations
+	}
+	return nil
+}
+
+// checkBuckets checks InvariantBucket.
+func checkBuckets(iss *ISS) *InvariantViolation {
+	for _, bucket := range *iss.buckets {
+		listed := make(map[string]struct{}, bucket.Len())
+		for e := bucket.reqList.Front(); e != nil; e = e.Next() {
+			reqRef := e.Value.(*requestpb.RequestRef)
+			key := reqStrKey(reqRef)
+
+			if _, ok := listed[key]; ok {
+				return violation(InvariantBucket, "request %s listed twice in bucket %d", key, bucket.ID)
+			}
+			listed[key] = struct{}{}
+
+			if bucket.reqMap[key] != e {
+				return violation(InvariantBucket,
+					"request %s listed in bucket %d, but not indexed as such", key, bucket.ID)
+			}
+			if iss.buckets.RequestBucket(reqRef) != bucket {
+				return violation(InvariantBucket, "request %s listed in bucket %d, but maps to bucket %d",
+					key, bucket.ID, iss.buckets.RequestBucket(reqRef).ID)
+			}
+		}
+
+		for key := range bucket.reqMap {
+			if _, ok := bucket.reqRefs[key]; !ok {
+				return violation(InvariantBucket,
+					"request %s indexed in bucket %d without its reference", key, bucket.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// checkMissingRequests checks InvariantMissingRequests.
+func checkMissingRequests(iss *ISS) *InvariantViolation {
+	for key, info := range iss.missingRequestIndex {
+		if iss.missingRequests[info.Sn] != info {
+			return violation(InvariantMissingRequests,
+				"request %s missing for SN %d, which is not waiting for requests", key, info.Sn)
+		}
+		if _, ok := info.Requests[key]; !ok {
+			return violation(InvariantMissingRequests,
+				"request %s indexed as missing for SN %d, but not registered as such", key, info.Sn)
+		}
+	}
+	return nil
+}
+
+// sortedClientIDs returns the keys of the given map in increasing order.
+func sortedClientIDs(m map[t.ClientID]t.ReqNo) []t.ClientID {
+	clientIDs := make([]t.ClientID, 0, len(m))
+	for clientID := range m {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Slice(clientIDs, func(i, j int) bool { return clientIDs[i] < clientIDs[j] })
+	return clientIDs
+}
+
+// sortedCheckpointSNs returns the keys of the given map in increasing order.
+func sortedCheckpointSNs(m map[t.SeqNr]*checkpointTracker) []t.SeqNr {
+	sns := make([]t.SeqNr, 0, len(m))
+	for sn := range m {
+		sns = append(sns, sn)
+	}
+	sort.Slice(sns, func(i, j int) bool { return sns[i] < sns[j] })
+	return sns
+}
@@ -0,0 +1,11 @@
+// Copyright IBM Corp. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !mirbft_invariants
+// +build !mirbft_invariants
+
+package iss
+
+// Without the mirbft_invariants tag, the invariants are only checked if Config.CheckInvariants is set.
+const forceInvariants = false
@@ -0,0 +1,12 @@
+// Copyright IBM Corp. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build mirbft_invariants
+// +build mirbft_invariants
+
+package iss
+
+// Building with the mirbft_invariants tag (e.g., go test -tags mirbft_invariants ./...) enables the checking
+// of the invariants of every ISS instance, regardless of Config.CheckInvariants.
+const forceInvariants = true
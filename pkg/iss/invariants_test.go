@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"crypto"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Invariant checking test", func() {
+
+	It("finds no violations in a normal run", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 100,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+			replica.ISSConfig.CheckInvariants = true
+		}
+
+		finalStatuses := deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+
+	It("reports a violated invariant as a structured error", func() {
+		membership := []t.NodeID{0, 1, 2, 3}
+		protocol, err := iss.New(0, iss.DefaultConfig(membership), logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+
+		stepper, err := mirbft.NewStepper(0, &mirbft.NodeConfig{Logger: logging.NilLogger}, &modules.Modules{
+			Net:           discardingNet{},
+			Hasher:        crypto.SHA256,
+			App:           &deploytest.FakeApp{},
+			WAL:           simplewal.NewVolatileWAL(),
+			ClientTracker: clients.SigningTracker(logging.NilLogger),
+			RequestStore:  reqstore.NewVolatileRequestStore(),
+			Protocol:      protocol,
+			Crypto:        &mirCrypto.DummyCrypto{DummySig: []byte{0}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		defer stepper.Stop()
+		_, err = stepper.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		// A stable checkpoint (without a recorded configuration) in the middle of an epoch cannot be recovered.
+		_, _, err = stepper.Step(events.ListOf(
+			&eventpb.Event{Type: &eventpb.Event_Iss{Iss: &isspb.ISSEvent{Type: &isspb.ISSEvent_PersistCheckpoint{
+				PersistCheckpoint: &isspb.PersistCheckpoint{Sn: 5},
+			}}}},
+			&eventpb.Event{Type: &eventpb.Event_Iss{Iss: &isspb.ISSEvent{Type: &isspb.ISSEvent_PersistStableCheckpoint{
+				PersistStableCheckpoint: &isspb.PersistStableCheckpoint{
+					StableCheckpoint: &isspb.StableCheckpoint{Epoch: 1, Sn: 5},
+				},
+			}}}},
+		))
+		var violation *iss.InvariantViolation
+		Expect(errors.As(err, &violation)).To(BeTrue())
+		Expect(violation.Invariant).To(Equal(iss.InvariantWatermarkOrder))
+		Expect(violation.NodeID).To(Equal(t.NodeID(0)))
+		Expect(violation.Epoch).To(Equal(t.EpochNr(1)))
+	})
+})
+
+// discardingNet is a Net module that drops all sent messages and never receives any.
+type discardingNet struct{}
+
+func (dn discardingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	return nil
+}
+
+func (dn discardingNet) ReceiveChan() <-chan modules.ReceivedMessage {
+	return nil
+}
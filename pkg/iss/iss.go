@@ -19,12 +19,35 @@ import (
 	"github.com/hyperledger-labs/mirbft/pkg/events"
 	"github.com/hyperledger-labs/mirbft/pkg/logging"
 	"github.com/hyperledger-labs/mirbft/pkg/messagebuffer"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Names of the logging modules of ISS. If the logger passed to New is a logging.ModuleLevels,
+// the verbosity of each of the subsystems can be adjusted independently, also while the protocol is running.
+const (
+	// Everything not attributed to any of the subsystems below, e.g., delivering batches and message buffering.
+	LogModuleISS = "iss"
+
+	// Epoch changes: leader selection, suspecting and handing off leaders, and membership changes.
+	LogModuleEpoch = "iss.epoch"
+
+	// Client requests: buckets, client watermarks and fetching missing requests.
+	LogModuleClients = "iss.clients"
+
+	// Checkpointing: stable checkpoints, state transfer, garbage collection and lagging nodes.
+	LogModuleCheckpoint = "iss.checkpoint"
+
+	// The orderers (SB instances) ordering the individual segments.
+	LogModuleOrderer = "iss.orderer"
 )
 
 // ============================================================
@@ -62,6 +85,9 @@ type segment struct {
 	// List of all nodes executing the orderer implementation.
 	Membership []t.NodeID
 
+	// List of the learners (see Config.Learners), which only receive the proposals.
+	Learners []t.NodeID
+
 	// List of sequence numbers for which the orderer is responsible.
 	// This is the actual "segment" of the commit log.
 	SeqNrs []t.SeqNr
@@ -122,18 +148,53 @@ type ISS struct {
 	// This is mostly for debugging - not to be confused with the commit log.
 	logger logging.Logger
 
+	// Loggers of the ISS subsystems (see LogModuleEpoch etc.).
+	// They only differ from logger if per-module log levels are used (see logging.ModuleLevels).
+	epochLogger      logging.Logger
+	clientLogger     logging.Logger
+	checkpointLogger logging.Logger
+	ordererLogger    logging.Logger
+
+	// The set of nodes (of type map[t.NodeID]struct{}) messages are accepted from, used by ValidateMessage
+	// (which cannot access the config concurrently with the protocol).
+	// It consists of the current membership and the nodes added by configuration changes that are yet to take effect.
+	// The set is replaced (never modified) when the membership changes and is read atomically.
+	members atomic.Value
+
+	// The set of learners (of type map[t.NodeID]struct{}, see Config.Learners), maintained like members.
+	// The only messages accepted from learners are requests for state and for request payloads (see learnerMessage).
+	learners atomic.Value
+
+	// Total size (in bytes) of the messages held in the messageBuffers, updated on each tick
+	// and read atomically by BufferedMessageBytes (e.g., for exporting it as a metric).
+	bufferedMessageBytes int64
+
+	// The quorum sizes derived from the current membership.
+	// They are replaced together with the configuration when a configuration change takes effect (see setConfig).
+	quorums quorums
+
+	// The current membership and its intersection quorum (of type membershipQuorum), exported by Membership.
+	// It is replaced (never modified) together with quorums and read atomically.
+	membershipQuorum atomic.Value
+
+	// Checker of the invariants of the state of ISS, nil if the invariants are not checked (see Config.CheckInvariants).
+	invariants *invariantChecker
+
 	// --------------------------------------------------------------------------------
 	// These fields might change from epoch to epoch. Modified only by initEpoch()
 	// --------------------------------------------------------------------------------
 
 	// The ISS configuration parameters (e.g. number of buckets, batch size, etc...)
 	// passed to New() when creating an ISS protocol instance.
-	// TODO: Make it possible to change this dynamically.
+	// When the configuration changes at runtime, it is replaced by a new copy (see setConfig).
 	config *Config
 
 	// The current epoch number.
 	epoch t.EpochNr
 
+	// A copy of the current epoch number (as uint64), read atomically by ValidateMessage.
+	validationEpoch uint64
+
 	// The next ID to assign to a newly created orderer.
 	// The orderers have monotonically increasing IDs that do *not* reset on epoch transitions.
 	nextOrdererID t.SBInstanceID
@@ -145,6 +206,9 @@ type ISS struct {
 	// For each bucket ID, this map stores the orderer to which the bucket is assigned in the current epoch.
 	bucketOrderers map[int]sbInstance
 
+	// The (ordered) list of leaders of the current epoch, as returned by the leader selection policy.
+	epochLeaders []t.NodeID
+
 	// --------------------------------------------------------------------------------
 	// These fields are modified throughout an epoch
 	// --------------------------------------------------------------------------------
@@ -181,6 +245,16 @@ type ISS struct {
 	// As soon as a request has been received the corresponding entry is deleted from this map.
 	missingRequestIndex map[string]*missingRequestInfo
 
+	// For each sequence number of a committed batch, this field holds information about the requests of the batch
+	// whose payloads the node is still missing (with Config.HashOnlyOrdering, or for configuration requests).
+	// Delivery of batches to the application stops at the first sequence number with missing payloads,
+	// until all the payloads have been received. Unlike missingRequests, this field is not reset on epoch change,
+	// as an epoch cannot end before all of its batches have been delivered.
+	missingPayloads map[t.SeqNr]*missingRequestInfo
+
+	// For each request whose payload is missing, a pointer to the corresponding entry in missingPayloads.
+	missingPayloadIndex map[string]*missingRequestInfo
+
 	// Represents the state of all the instances of the checkpoint sub-protocol.
 	// Each instance is associated with a unique sequence number (the first one the checkpoint does *not* include).
 	// The entries in this map are garbage-collected when some checkpoint becomes stable,
@@ -191,6 +265,105 @@ type ISS struct {
 	// If no stable checkpoint has been observed yet, lastStableCheckpoint is initialized to a stable checkpoint value
 	// corresponding to the initial state and associated with sequence number 0.
 	lastStableCheckpoint *isspb.StableCheckpoint
+
+	// All state associated with sequence numbers lower than gcSN has been garbage-collected.
+	// Garbage collection is not performed immediately when a checkpoint becomes stable, but on the next tick.
+	// This way, if multiple checkpoints become stable in quick succession (e.g. after a stall clears),
+	// only a single garbage collection pass is performed, up to the most recent stable checkpoint.
+	gcSN t.SeqNr
+
+	// Low watermarks of the clients' requests, advanced on garbage collection.
+	// When they advance, the data of the requests below them is pruned from the request store.
+	clientWatermarks *clientWatermarks
+
+	// Checkpoints (application snapshots and client watermarks) loaded from the WAL at startup,
+	// indexed by the sequence number of the checkpoint.
+	// When a stable checkpoint is recovered from the WAL, the corresponding snapshot is used
+	// to restore the application state at initialization.
+	// This map is only populated while the WAL is being loaded and is discarded when the Init event is applied.
+	recoveredCheckpoints map[t.SeqNr]*isspb.PersistCheckpoint
+
+	// Statistics about the content of the batches committed in the segments of each leader.
+	// At the end of each epoch, they are evaluated against the configured thresholds (if any)
+	// and leaders exceeding them are reported to the leader selection policy as suspected.
+	leaderStats *leaderStatsTracker
+
+	// Histograms of the commit latencies of the requests, per bucket and per leader.
+	commitLatency *commitLatencyTracker
+
+	// Estimates the skew of other nodes' wall clocks based on the timestamps of their Checkpoint messages.
+	clockSkew *clockSkewDetector
+
+	// Detects nodes persistently lagging behind the stable checkpoints (see Config.MaxCheckpointLag).
+	lag *lagTracker
+
+	// Records why the recent epochs started with the leaders they did.
+	epochHistory *epochHistory
+
+	// Detects unresponsive nodes based on the messages received from them (see Config.HeartbeatPeriod).
+	// Set to nil if Config.HeartbeatPeriod is zero.
+	liveness *livenessTracker
+
+	// Leaders of the current epoch that have already been suspected for being unresponsive.
+	// Each leader is reported to the leader selection policy at most once per epoch. Reset by initEpoch().
+	unresponsiveLeaders map[t.NodeID]struct{}
+
+	// Set when this node has been asked to step down as a leader (see eventpb.StepDown), e.g., before a planned shutdown.
+	// From then on, the node hands off each segment it leads (see SBStepDown) as soon as the segment's orderer starts.
+	steppingDown bool
+
+	// Leaders of the current epoch that handed off their segment, as observed in the committed batches.
+	// They are reported to the leader selection policy at the end of the epoch,
+	// so that a policy excluding suspected nodes stops selecting them as leaders. Reset by initEpoch().
+	handoffLeaders map[t.NodeID]struct{}
+
+	// Content of the configuration requests (see t.ConfigClientID) that became ready but have not yet been committed,
+	// indexed by the string representation of their request references.
+	// The content is also persisted in the WAL, such that it is known when the requests are committed after recovery.
+	configRequests map[string]*isspb.PersistConfigRequest
+
+	// The configuration resulting from the configuration changes committed in the current epoch,
+	// taking effect at the start of the next epoch. Nil if no configuration change has been committed in this epoch.
+	pendingConfig *Config
+
+	// Version of the configuration in effect: the epoch in which the last configuration change
+	// (including changes of the clients) took effect, 0 if the configuration has not changed since the start.
+	// All messages of the protocol that depend on the configuration (SB and Checkpoint messages) carry it,
+	// and messages generated under a different configuration version are rejected.
+	// This prevents messages sent by a node that (e.g., due to a bug) applied a different configuration change
+	// from interfering with the protocol during reconfiguration.
+	// The version is recorded in checkpoints and restored when starting from a checkpoint.
+	configEpoch t.EpochNr
+
+	// Public keys and windows of the clients added by the configuration changes in effect, indexed by client ID.
+	// An empty key represents a removed client. The keys are recorded in checkpoints and,
+	// when starting from a checkpoint, registered with the Crypto module again (see events.UpdateClientKeys).
+	clientKeys map[t.ClientID]*isspb.ClientKey
+
+	// The changes of the client keys committed in the current epoch, taking effect at the start of the next epoch,
+	// in the same representation as clientKeys. Nil if no client has been added or removed in this epoch.
+	pendingClientKeys map[t.ClientID]*isspb.ClientKey
+
+	// The keys of the nodes rotated by the configuration changes in effect, indexed by node ID,
+	// along with the epoch at which each node's previous key is retired (see Config.KeyRotationOverlap).
+	// The retire epoch is set to 0 once the previous key has been retired.
+	// Like the client keys, the node keys are recorded in checkpoints and,
+	// when starting from a checkpoint, registered with the Crypto module again (see events.UpdateNodeKeys).
+	nodeKeys map[t.NodeID]*isspb.NodeKey
+
+	// The node keys rotated by the configuration changes committed in the current epoch,
+	// taking effect at the start of the next epoch. Nil if no node key has been rotated in this epoch.
+	pendingNodeKeys map[t.NodeID][]byte
+
+	// Set while the node is joining a running network (see Config.Join), i.e., until it obtains the state to start from.
+	joining bool
+
+	// Only used while joining. The state received from each node in response to the StateRequest messages,
+	// the requests that became ready in the meantime (only added to the buckets once the state has been obtained),
+	// and the ticks remaining until the StateRequest messages are sent again.
+	stateTransfers         map[t.NodeID]*isspb.StateTransfer
+	joinRequests           []*eventpb.RequestReady
+	ticksUntilStateRequest int
 }
 
 // New returns a new initialized instance of the ISS protocol module to be used when instantiating a mirbft.Node.
@@ -199,6 +372,7 @@ type ISS struct {
 // - config: ISS protocol-specific configuration (e.g. number of buckets, batch size, etc...).
 //           see the documentation of the Config type for details.
 // - logger: Logger the ISS implementation uses to output log messages.
+//           If it is a logging.ModuleLevels, the subsystems log through separate modules (see LogModuleISS etc.).
 func New(ownID t.NodeID, config *Config, logger logging.Logger) (*ISS, error) {
 
 	// Check whether the passed configuration is valid.
@@ -209,9 +383,13 @@ func New(ownID t.NodeID, config *Config, logger logging.Logger) (*ISS, error) {
 	// Initialize a new ISS object.
 	iss := &ISS{
 		// Static fields
-		ownID:   ownID,
-		buckets: newBuckets(config.NumBuckets, logger),
-		logger:  logger,
+		ownID:            ownID,
+		buckets:          newBuckets(config.NumBuckets, logging.ForModule(logger, LogModuleClients)),
+		logger:           logging.ForModule(logger, LogModuleISS),
+		epochLogger:      logging.ForModule(logger, LogModuleEpoch),
+		clientLogger:     logging.ForModule(logger, LogModuleClients),
+		checkpointLogger: logging.ForModule(logger, LogModuleCheckpoint),
+		ordererLogger:    logging.ForModule(logger, LogModuleOrderer),
 
 		// Fields modified only by initEpoch
 		config:         config,
@@ -219,16 +397,19 @@ func New(ownID t.NodeID, config *Config, logger logging.Logger) (*ISS, error) {
 		nextOrdererID:  0,
 		orderers:       make(map[t.SBInstanceID]sbInstance),
 		bucketOrderers: nil,
+		epochLeaders:   nil,
 
 		// Fields modified throughout an epoch
 		commitLog:           make(map[t.SeqNr]*commitLogEntry),
 		nextDeliveredSN:     0,
 		missingRequests:     nil, // allocated in initEpoch()
 		missingRequestIndex: nil, // allocated in initEpoch()
+		missingPayloads:     make(map[t.SeqNr]*missingRequestInfo),
+		missingPayloadIndex: make(map[string]*missingRequestInfo),
 		messageBuffers: messagebuffer.NewBuffers(
 			removeNodeID(config.Membership, ownID), // Create a message buffer for everyone except for myself.
 			config.MsgBufCapacity,
-			logging.Decorate(logger, "Msgbuf: "),
+			logging.Decorate(logging.ForModule(logger, LogModuleISS), "Msgbuf: "),
 		),
 		checkpoints: make(map[t.SeqNr]*checkpointTracker),
 		lastStableCheckpoint: &isspb.StableCheckpoint{
@@ -238,6 +419,33 @@ func New(ownID t.NodeID, config *Config, logger logging.Logger) (*ISS, error) {
 			//       will have to be set here. E.g., an empty byte slice could be defined as "initial state" and
 			//       the application required to interpret it as such.
 		},
+		gcSN:                 0,
+		clientWatermarks:     newClientWatermarks(),
+		recoveredCheckpoints: make(map[t.SeqNr]*isspb.PersistCheckpoint),
+		leaderStats:          newLeaderStatsTracker(),
+		commitLatency:        newCommitLatencyTracker(),
+		clockSkew:            newClockSkewDetector(wallClock(config), config.MaxClockSkew, logging.ForModule(logger, LogModuleISS)),
+		lag:                  newLagTracker(ownID, config.MaxCheckpointLag, logging.ForModule(logger, LogModuleCheckpoint)),
+		epochHistory:         newEpochHistory(),
+		configRequests:       make(map[string]*isspb.PersistConfigRequest),
+		clientKeys:           make(map[t.ClientID]*isspb.ClientKey),
+		nodeKeys:             make(map[t.NodeID]*isspb.NodeKey),
+	}
+	iss.members.Store(membershipSet(config.Membership))
+	iss.learners.Store(membershipSet(config.Learners))
+	iss.setQuorums(config.Membership)
+	if config.CheckInvariants || forceInvariants {
+		iss.invariants = newInvariantChecker()
+	}
+
+	// Track the liveness of the other nodes if heartbeats are enabled.
+	if config.HeartbeatPeriod > 0 {
+		iss.liveness = newLivenessTracker(
+			removeNodeID(config.Membership, ownID),
+			config.HeartbeatPeriod,
+			config.SuspectTimeout,
+			logging.ForModule(logger, LogModuleEpoch),
+		)
 	}
 
 	// Initialize the first epoch (epoch 0).
@@ -255,7 +463,31 @@ func New(ownID t.NodeID, config *Config, logger logging.Logger) (*ISS, error) {
 
 // ApplyEvent receives one event and applies it to the ISS protocol state machine, potentially altering its state
 // and producing a (potentially empty) list of more events to be applied to other modules.
+// If the invariants are checked (see Config.CheckInvariants), ApplyEvent panics with an *InvariantViolation
+// if the resulting state violates any of them.
 func (iss *ISS) ApplyEvent(event *eventpb.Event) *events.EventList {
+
+	// Attribute any invariant violation to this node and its current epoch.
+	defer func() {
+		if r := recover(); r != nil {
+			if v, ok := r.(*InvariantViolation); ok {
+				v.NodeID, v.Epoch = iss.ownID, iss.epoch
+			}
+			panic(r)
+		}
+	}()
+
+	eventsOut := iss.applyEvent(event)
+	if iss.invariants != nil {
+		if violation := iss.invariants.check(iss); violation != nil {
+			panic(violation)
+		}
+	}
+	return eventsOut
+}
+
+// applyEvent dispatches event to the handler of its type.
+func (iss *ISS) applyEvent(event *eventpb.Event) *events.EventList {
 	switch e := event.Type.(type) {
 	case *eventpb.Event_Init:
 		return iss.applyInit(e.Init)
@@ -271,22 +503,204 @@ func (iss *ISS) ApplyEvent(event *eventpb.Event) *events.EventList {
 			return iss.applySBEvent(issEvent.Sb)
 		case *isspb.ISSEvent_StableCheckpoint:
 			return iss.applyStableCheckpoint(issEvent.StableCheckpoint)
+		case *isspb.ISSEvent_PersistCheckpoint:
+			return iss.applyPersistCheckpoint(issEvent.PersistCheckpoint)
+		case *isspb.ISSEvent_PersistStableCheckpoint:
+			return iss.applyPersistStableCheckpoint(issEvent.PersistStableCheckpoint.StableCheckpoint)
+		case *isspb.ISSEvent_PersistConfigRequest:
+			return iss.applyPersistConfigRequest(issEvent.PersistConfigRequest)
 		default:
 			panic(fmt.Sprintf("unknown ISS event type: %T", issEvent))
 		}
 	case *eventpb.Event_MessageReceived:
 		return iss.applyMessageReceived(e.MessageReceived)
+	case *eventpb.Event_PeerHealth:
+		return iss.applyPeerHealth(e.PeerHealth)
+	case *eventpb.Event_StepDown:
+		return iss.applyStepDown()
+	case *eventpb.Event_LocalParams:
+		return iss.applyLocalParams(e.LocalParams)
 	default:
 		panic(fmt.Sprintf("unknown protocol (ISS) event type: %T", event.Type))
 	}
 }
 
-// Status returns a protobuf representation of the current protocol state that can be later printed (TODO: Say how).
+// LeaderStats returns the statistics about the batches committed in the segments of each leader,
+// accumulated since the start of the node.
+// Unlike the other methods of ISS, LeaderStats can be called concurrently with the processing of events.
+func (iss *ISS) LeaderStats() map[t.NodeID]LeaderStats {
+	return iss.leaderStats.snapshot()
+}
+
+// CommitLatencies returns the histograms of the commit latencies of the requests (see LatencyHistogram)
+// committed since the start of the node, per bucket (indexed by bucket ID) and per leader.
+// Unlike the other methods of ISS, CommitLatencies can be called concurrently with the processing of events.
+func (iss *ISS) CommitLatencies() (map[int]LatencyHistogram, map[t.NodeID]LatencyHistogram) {
+	return iss.commitLatency.snapshot()
+}
+
+// Status returns a protobuf representation of the current protocol state
+// that can be later printed (e.g. using mirbft.PrettyStatus).
 // This functionality is meant mostly for debugging and is *not* meant to provide an interface for
 // serializing and deserializing the whole protocol state.
 func (iss *ISS) Status() (s *statuspb.ProtocolStatus, err error) {
-	// TODO: Implement this.
-	return nil, nil
+
+	// Collect the status of the current epoch's orderers, ordered by their IDs,
+	// along with the information about the transition to the next epoch they are driving.
+	nextEpoch := &isspb.EpochTransitionStatus{
+		Epoch:               (iss.epoch + 1).Pb(),
+		FirstSn:             iss.nextDeliveredSN.Pb(),
+		Leaders:             t.NodeIDSlicePb(iss.epochLeaders),
+		UnresponsiveLeaders: t.NodeIDSlicePb(sortedNodeIDs(iss.unresponsiveLeaders)),
+		HandoffLeaders:      t.NodeIDSlicePb(sortedNodeIDs(iss.handoffLeaders)),
+	}
+	if iss.pendingConfig != nil {
+		nextEpoch.PendingMembership = t.NodeIDSlicePb(iss.pendingConfig.Membership)
+	}
+	orderers := make([]*isspb.SBStatus, 0, len(iss.orderers))
+	for id, orderer := range iss.orderers {
+		ordererStatus := orderer.Status()
+		ordererStatus.Instance = id.Pb()
+		for _, sn := range orderer.Segment().SeqNrs {
+			if iss.commitLog[sn] != nil {
+				ordererStatus.Committed = append(ordererStatus.Committed, sn.Pb())
+			} else {
+				nextEpoch.Uncommitted++
+			}
+			if sn >= t.SeqNr(nextEpoch.FirstSn) {
+				nextEpoch.FirstSn = (sn + 1).Pb()
+			}
+		}
+		orderers = append(orderers, ordererStatus)
+	}
+	sort.Slice(orderers, func(i, j int) bool { return orderers[i].Instance < orderers[j].Instance })
+
+	// Collect the status of the buckets, counting the ready requests of each client.
+	buckets := make([]*isspb.BucketStatus, 0, iss.config.NumBuckets)
+	readyRequests := make(map[t.ClientID]int)
+	for bID := 0; bID < iss.config.NumBuckets; bID++ {
+		bucket := iss.buckets.Get(bID)
+		bucketStatus := &isspb.BucketStatus{Id: uint64(bID), Requests: uint64(bucket.Len())}
+		for id, orderer := range iss.orderers {
+			if orderer == iss.bucketOrderers[bID] {
+				bucketStatus.Leader = orderer.Segment().Leader.Pb()
+				bucketStatus.Instance = id.Pb()
+			}
+		}
+		bucket.CountClientRequests(readyRequests)
+		buckets = append(buckets, bucketStatus)
+	}
+
+	bucketLatencies, leaderLatencies := iss.commitLatency.status()
+
+	return &statuspb.ProtocolStatus{Type: &statuspb.ProtocolStatus_Iss{Iss: &isspb.Status{
+		Epoch:                 iss.epoch.Pb(),
+		Orderers:              orderers,
+		ClockSkews:            iss.clockSkew.Status(iss.config.Membership),
+		BucketCommitLatencies: bucketLatencies,
+		LeaderCommitLatencies: leaderLatencies,
+		NodeLags:              iss.lag.Status(iss.config.Membership),
+		NextDeliveredSn:       iss.nextDeliveredSN.Pb(),
+		LastStableSn:          iss.lastStableCheckpoint.Sn,
+		Buckets:               buckets,
+		Clients:               iss.clientWatermarks.status(readyRequests),
+		MessageBuffers:        iss.messageBufferStatus(),
+		NextEpoch:             nextEpoch,
+		EpochHistory:          iss.epochHistory.Status(),
+	}}}, nil
+}
+
+// messageBufferStatus returns the status of the buffers of messages for future epochs, ordered by node ID.
+func (iss *ISS) messageBufferStatus() []*isspb.MessageBufferStatus {
+	statuses := make([]*isspb.MessageBufferStatus, 0, len(iss.messageBuffers))
+	for nodeID, buffer := range iss.messageBuffers {
+		statuses = append(statuses, &isspb.MessageBufferStatus{
+			NodeId:   nodeID.Pb(),
+			Messages: uint64(buffer.Len()),
+			Size:     uint64(buffer.Size()),
+			Capacity: uint64(buffer.Capacity()),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].NodeId < statuses[j].NodeId })
+	return statuses
+}
+
+// Membership returns the current membership of ISS and the size of its intersection quorum (see quorums),
+// implementing modules.MembershipProvider. Both change when a configuration change takes effect.
+// Membership is safe to be called concurrently with ApplyEvent.
+func (iss *ISS) Membership() ([]t.NodeID, int) {
+	mq := iss.membershipQuorum.Load().(membershipQuorum)
+	return mq.members, mq.quorum
+}
+
+// IsMember returns true if nodeID is a member of ISS, including the nodes added by configuration changes
+// that are yet to take effect (see members). It implements modules.MembershipProvider
+// and is safe to be called concurrently with ApplyEvent.
+func (iss *ISS) IsMember(nodeID t.NodeID) bool {
+	_, ok := iss.members.Load().(map[t.NodeID]struct{})[nodeID]
+	return ok
+}
+
+// IsLearner returns true if nodeID is a learner (see Config.Learners), maintained like the members (see IsMember).
+// It implements modules.MembershipProvider and is safe to be called concurrently with ApplyEvent.
+func (iss *ISS) IsLearner(nodeID t.NodeID) bool {
+	_, ok := iss.learners.Load().(map[t.NodeID]struct{})[nodeID]
+	return ok
+}
+
+// ValidateMessage checks a message received from node from before it is applied (see modules.MessageValidator).
+// It rejects messages from nodes outside the membership, messages that are not ISS messages
+// or lack fields ISS relies on, and SB messages from epochs that already ended.
+// ValidateMessage only accesses the static state of ISS and the atomically updated epoch number
+// and is thus safe to be called concurrently with ApplyEvent.
+func (iss *ISS) ValidateMessage(from t.NodeID, msg *messagepb.Message) error {
+
+	// The sender must be part of the membership. Learners are only allowed to request state and request payloads.
+	if !iss.IsMember(from) && !(iss.IsLearner(from) && learnerMessage(msg)) {
+		return fmt.Errorf("%w: %d", modules.ErrUnknownNode, from)
+	}
+
+	issMsg, ok := msg.Type.(*messagepb.Message_Iss)
+	if !ok || issMsg.Iss == nil {
+		return fmt.Errorf("%w: not an ISS message: %T", modules.ErrMalformedMessage, msg.Type)
+	}
+
+	switch m := issMsg.Iss.Type.(type) {
+	case *isspb.ISSMessage_Sb:
+		if m.Sb == nil || m.Sb.Msg == nil {
+			return fmt.Errorf("%w: SB message without content", modules.ErrMalformedMessage)
+		}
+		if err := validatePbftMessage(m.Sb.Msg); err != nil {
+			return fmt.Errorf("%w: %v", modules.ErrMalformedMessage, err)
+		}
+		// Messages of past epochs are never applied. (Messages of future epochs are buffered.)
+		if epoch := atomic.LoadUint64(&iss.validationEpoch); m.Sb.Epoch < epoch {
+			return fmt.Errorf("%w: SB message of epoch %d (current epoch is %d)",
+				modules.ErrEpochOutOfRange, m.Sb.Epoch, epoch)
+		}
+	case *isspb.ISSMessage_Checkpoint:
+		if m.Checkpoint == nil {
+			return fmt.Errorf("%w: empty Checkpoint message", modules.ErrMalformedMessage)
+		}
+	case *isspb.ISSMessage_RetransmitRequests:
+		if m.RetransmitRequests == nil || !validRequestRefs(m.RetransmitRequests.Requests) {
+			return fmt.Errorf("%w: invalid request references in RetransmitRequests", modules.ErrMalformedMessage)
+		}
+	case *isspb.ISSMessage_FetchRequests:
+		if m.FetchRequests == nil || !validRequestRefs(m.FetchRequests.Requests) {
+			return fmt.Errorf("%w: invalid request references in FetchRequests", modules.ErrMalformedMessage)
+		}
+	case *isspb.ISSMessage_Heartbeat, *isspb.ISSMessage_StateRequest:
+		// Heartbeat and StateRequest messages have no fields.
+	case *isspb.ISSMessage_StateTransfer:
+		if m.StateTransfer == nil || m.StateTransfer.Checkpoint == nil || len(m.StateTransfer.Checkpoint.Membership) == 0 {
+			return fmt.Errorf("%w: StateTransfer without checkpoint membership", modules.ErrMalformedMessage)
+		}
+	default:
+		return fmt.Errorf("%w: unknown ISS message type: %T", modules.ErrMalformedMessage, m)
+	}
+
+	return nil
 }
 
 // ============================================================
@@ -297,24 +711,142 @@ func (iss *ISS) Status() (s *statuspb.ProtocolStatus, err error) {
 // This event is only expected to be applied once at startup,
 // after all the events stored in the WAL have been applied and before any other event has been applied.
 func (iss *ISS) applyInit(init *eventpb.Init) *events.EventList {
+	eventsOut := &events.EventList{}
+
+	// A node joining a running network first needs to obtain the state to start from,
+	// unless it already recovered a stable checkpoint from the WAL.
+	if iss.config.Join && iss.lastStableCheckpoint.Sn == 0 {
+		iss.recoveredCheckpoints = nil
+		return iss.startJoining()
+	}
+
+	// If a stable checkpoint has been recovered from the WAL,
+	// have the application restore its state from the checkpoint's snapshot
+	// before any further batches are delivered to it.
+	// The client and node keys changed by configuration changes up to the checkpoint
+	// are registered with the Crypto module again.
+	if iss.lastStableCheckpoint.Sn > 0 {
+		eventsOut.PushBack(events.AppRestoreState(iss.checkpoints[t.SeqNr(iss.lastStableCheckpoint.Sn)].appSnapshot))
+		eventsOut.PushBackList(iss.restoreKeys())
+	}
+
+	// The checkpoints loaded from the WAL are not needed any more.
+	iss.recoveredCheckpoints = nil
+
+	// Notify about the start of the initial (or recovered) epoch.
+	eventsOut.PushBack(events.EpochStarted(iss.epoch, iss.epochLeaders, iss.bucketLeaders()))
 
 	// Trigger an Init event at all orderers.
-	return iss.initOrderers()
+	return eventsOut.PushBackList(iss.initOrderers())
+}
+
+// applyPeerHealth applies a change of reachability of another node, as observed by the Node sending messages to it.
+// Currently, ISS only logs the change.
+// TODO: Use the information, e.g., for retransmitting checkpoint messages when a node becomes reachable again.
+func (iss *ISS) applyPeerHealth(peerHealth *eventpb.PeerHealth) *events.EventList {
+	if peerHealth.Reachable {
+		iss.logger.Log(logging.LevelInfo, "Node reachable again.", "nodeID", peerHealth.NodeId)
+	} else {
+		iss.logger.Log(logging.LevelWarn, "Node unreachable.", "nodeID", peerHealth.NodeId, "error", peerHealth.Error)
+	}
+	return &events.EventList{}
+}
+
+// applyStepDown makes this node stop leading, in preparation of being shut down.
+// Rather than leaving the other nodes waiting for the proposals of its unfinished segments,
+// the node hands off the segments it leads in the current epoch, as well as in all the following ones,
+// by immediately proposing empty batches for all their remaining sequence numbers (see SBStepDown).
+// The requests in the node's buckets are thus left to the leaders of the next epoch.
+// The other nodes learn about the handoff from the committed batches and report the node to the leader selection
+// policy at the end of the epoch, which can then stop selecting the node as a leader.
+func (iss *ISS) applyStepDown() *events.EventList {
+	if iss.steppingDown {
+		return &events.EventList{}
+	}
+	iss.epochLogger.Log(logging.LevelInfo, "Stepping down as leader.", "epoch", iss.epoch)
+	iss.steppingDown = true
+	return iss.stepDownOrderers()
+}
+
+// stepDownOrderers makes the orderers led by this node hand off the rest of their segments.
+// Orderers that already made all their proposals ignore the event.
+func (iss *ISS) stepDownOrderers() *events.EventList {
+	eventsOut := &events.EventList{}
+	for _, orderer := range iss.sortedOrderers() {
+		if orderer.Segment().Leader == iss.ownID {
+			eventsOut.PushBackList(orderer.ApplyEvent(SBStepDownEvent()))
+		}
+	}
+	return eventsOut
+}
+
+// applyLocalParams adjusts the parameters of ISS that only affect this node (see eventpb.LocalParams).
+// The configuration is replaced by an adjusted copy (as is the pending one, if any), such that the adjustment
+// persists across configuration changes. The orderers of the current epoch are adjusted right away.
+func (iss *ISS) applyLocalParams(params *eventpb.LocalParams) *events.EventList {
+	iss.logger.Log(logging.LevelInfo, "Adjusting local parameters.", "maxProposeDelay", params.MaxProposeDelay)
+
+	config := *iss.config
+	config.MaxProposeDelay = int(params.MaxProposeDelay)
+	iss.config = &config
+	if iss.pendingConfig != nil {
+		pendingConfig := *iss.pendingConfig
+		pendingConfig.MaxProposeDelay = int(params.MaxProposeDelay)
+		iss.pendingConfig = &pendingConfig
+	}
+
+	eventsOut := &events.EventList{}
+	for _, orderer := range iss.sortedOrderers() {
+		eventsOut.PushBackList(orderer.ApplyEvent(SBLocalParamsEvent(params.MaxProposeDelay)))
+	}
+	return eventsOut
+}
+
+// BufferedMessageBytes returns the total size (in bytes) of the messages destined to future epochs
+// that the protocol currently buffers, as of the last tick.
+// Unlike the other methods of ISS, BufferedMessageBytes is safe to be called concurrently with the protocol.
+func (iss *ISS) BufferedMessageBytes() int {
+	return int(atomic.LoadInt64(&iss.bufferedMessageBytes))
 }
 
 // applyTick applies a single tick of the logical clock to the protocol state machine.
 func (iss *ISS) applyTick(tick *eventpb.Tick) *events.EventList {
 	eventsOut := &events.EventList{}
 
+	// While joining, the node only periodically requests the state to start from.
+	if iss.joining {
+		return iss.joinTick()
+	}
+
+	// Advance the clock used for measuring the age of requests.
+	iss.leaderStats.tick()
+
+	// Publish the amount of buffered messages.
+	bufferedBytes := 0
+	for _, buffer := range iss.messageBuffers {
+		bufferedBytes += buffer.Size()
+	}
+	atomic.StoreInt64(&iss.bufferedMessageBytes, int64(bufferedBytes))
+
+	// Send heartbeats and suspect the current epoch's leaders that stopped responding.
+	if iss.liveness != nil {
+		eventsOut.PushBackList(iss.checkLiveness())
+	}
+
+	// If any checkpoint became stable since the last tick, garbage-collect the state it encompasses.
+	if t.SeqNr(iss.lastStableCheckpoint.Sn) > iss.gcSN {
+		eventsOut.PushBackList(iss.garbageCollect())
+	}
+
 	// Relay tick to each orderer.
 	sbTick := SBTickEvent()
-	for _, orderer := range iss.orderers {
+	for _, orderer := range iss.sortedOrderers() {
 		eventsOut.PushBackList(orderer.ApplyEvent(sbTick))
 	}
 
 	// Demand retransmission of requests if retransmission timer expired.
-	// TODO: iterate in a deterministic order!
-	for _, missingRequests := range iss.missingRequests {
+	for _, sn := range sortedSeqNrs(iss.missingRequests) {
+		missingRequests := iss.missingRequests[sn]
 		// For all sequence numbers for which requests are missing,
 
 		// Decrement the timer/counter.
@@ -331,6 +863,16 @@ func (iss *ISS) applyTick(tick *eventpb.Tick) *events.EventList {
 		}
 	}
 
+	// Fetch the payloads of committed requests again if the fetching timer expired.
+	for _, sn := range sortedSeqNrs(iss.missingPayloads) {
+		missingPayloads := iss.missingPayloads[sn]
+		missingPayloads.TicksUntilNAck--
+		if missingPayloads.TicksUntilNAck == 0 {
+			eventsOut.PushBackList(iss.fetchPayloads(missingPayloads))
+			missingPayloads.TicksUntilNAck = iss.config.RequestNAckTimeout
+		}
+	}
+
 	return eventsOut
 }
 
@@ -342,6 +884,50 @@ func (iss *ISS) applyRequestReady(requestReady *eventpb.RequestReady) *events.Ev
 	// Get request reference.
 	ref := requestReady.RequestRef
 
+	// Remember the content of configuration requests, to be interpreted when they are committed.
+	if t.ClientID(ref.ClientId) == t.ConfigClientID {
+		eventsOut.PushBackList(iss.registerConfigRequest(ref, requestReady.Data))
+	}
+
+	// While joining, the requests are only added to the buckets after the state to start from has been obtained,
+	// as the requests that already have been committed are not known until then.
+	if iss.joining {
+		iss.joinRequests = append(iss.joinRequests, requestReady)
+		return eventsOut
+	}
+
+	// If the payload of an already committed request has been missing (with Config.HashOnlyOrdering),
+	// the request must not be added to a bucket (it would be proposed again).
+	// Instead, the delivery of the corresponding batch might be able to continue.
+	if _, ok := iss.missingPayloadIndex[reqStrKey(ref)]; ok {
+		return iss.payloadReceived(ref)
+	}
+
+	// Ignore requests below the low watermark of their client.
+	// Those have already been committed (e.g., before the node restarted and the client re-submitted them).
+	if t.ReqNo(ref.ReqNo) < iss.clientWatermarks.watermark(t.ClientID(ref.ClientId)) {
+		iss.clientLogger.Log(logging.LevelDebug, "Ignoring request below client watermark.",
+			"clId", ref.ClientId, "reqNo", ref.ReqNo)
+		return eventsOut
+	}
+
+	// Ignore requests of clients removed by a configuration request (see isspb.ConfigChange).
+	// Such a request might have been authenticated before the removal took effect.
+	if clientKey, ok := iss.clientKeys[t.ClientID(ref.ClientId)]; ok && len(clientKey.PubKey) == 0 {
+		iss.clientLogger.Log(logging.LevelDebug, "Ignoring request of removed client.",
+			"clId", ref.ClientId, "reqNo", ref.ReqNo)
+		return eventsOut
+	}
+
+	// Ignore requests beyond the window of their client, if the client has been added with one.
+	// The client needs to re-submit them once its low watermark advanced.
+	if clientKey, ok := iss.clientKeys[t.ClientID(ref.ClientId)]; ok && clientKey.Window != 0 &&
+		t.ReqNo(ref.ReqNo) >= iss.clientWatermarks.watermark(t.ClientID(ref.ClientId))+t.ReqNo(clientKey.Window) {
+		iss.clientLogger.Log(logging.LevelDebug, "Ignoring request beyond client window.",
+			"clId", ref.ClientId, "reqNo", ref.ReqNo, "window", clientKey.Window)
+		return eventsOut
+	}
+
 	// Get bucket to which the new request maps.
 	bucket := iss.buckets.RequestBucket(ref)
 
@@ -353,6 +939,9 @@ func (iss *ISS) applyRequestReady(requestReady *eventpb.RequestReady) *events.Ev
 		return &events.EventList{}
 	}
 
+	// Remember when the request became ready, in order to measure its age when it is committed.
+	iss.leaderStats.requestReady(ref)
+
 	// If necessary, notify the orderer responsible for this request to continue processing it.
 	// (This is necessary in case the request has been "missing", i.e., proposed but not yet received.)
 	eventsOut.PushBackList(iss.notifyOrderer(ref))
@@ -376,9 +965,29 @@ func (iss *ISS) applyRequestReady(requestReady *eventpb.RequestReady) *events.Ev
 }
 
 // applyAppSnapshot applies the event of the application creating a state snapshot.
-// It passes the snapshot to the appropriate CheckpointTracker (identified by the event's associated sequence number).
+// It passes the snapshot to the appropriate CheckpointTracker (identified by the event's associated sequence number),
+// along with the client watermarks corresponding to the snapshot, to be persisted together with it.
 func (iss *ISS) applyAppSnapshot(snapshot *eventpb.AppSnapshot) *events.EventList {
-	return iss.getCheckpointTracker(t.SeqNr(snapshot.Sn)).ProcessAppSnapshot(snapshot.Data)
+	return iss.getCheckpointTracker(t.SeqNr(snapshot.Sn)).ProcessAppSnapshot(
+		snapshot.Data,
+		iss.checkpointWatermarks(t.SeqNr(snapshot.Sn)).pb(),
+		iss.clockSkew.Timestamp(),
+	)
+}
+
+// checkpointWatermarks returns the client watermarks as they will be after all batches below sn are garbage-collected.
+// It must only be called for a checkpoint whose sequence numbers have all been delivered,
+// but not yet garbage-collected, i.e., with all the corresponding entries still present in the commit log.
+func (iss *ISS) checkpointWatermarks(sn t.SeqNr) *clientWatermarks {
+	watermarks := iss.clientWatermarks.clone()
+	for s := iss.gcSN; s < sn; s++ {
+		if entry, ok := iss.commitLog[s]; ok && !entry.Aborted {
+			for _, reqRef := range entry.Batch.Requests {
+				watermarks.commit(reqRef)
+			}
+		}
+	}
+	return watermarks
 }
 
 // applySBEvent applies an event triggered by or addressed to an orderer (i.e., instance of Sequenced Broadcast),
@@ -387,6 +996,14 @@ func (iss *ISS) applyAppSnapshot(snapshot *eventpb.AppSnapshot) *events.EventLis
 func (iss *ISS) applySBEvent(event *isspb.SBEvent) *events.EventList {
 
 	switch epoch := t.EpochNr(event.Epoch); {
+	case epoch > iss.epoch && loadedFromWAL(event.Event):
+		// Events loaded from the WAL at startup can belong to an epoch the node cannot recover,
+		// e.g. if the node crashed before the checkpoint at the start of that epoch became stable.
+		// Such events are ignored, as the corresponding state will have to be obtained from other nodes.
+		iss.logger.Log(logging.LevelWarn, "Ignoring WAL event from future epoch.",
+			"type", fmt.Sprintf("%T", event.Event.Type), "epoch", epoch, "instance", event.Instance)
+		return &events.EventList{}
+
 	case epoch > iss.epoch:
 		// Events coming from future epochs should never occur (as, unlike messages, events are all generated locally.)
 		panic(fmt.Sprintf("trying to handle ISS event (type %T, instance %d) from future epoch: %d",
@@ -410,19 +1027,93 @@ func (iss *ISS) applyStableCheckpoint(stableCheckpoint *isspb.StableCheckpoint)
 
 	if stableCheckpoint.Sn > iss.lastStableCheckpoint.Sn {
 		// If this is the most recent checkpoint observed, save it.
-		iss.logger.Log(logging.LevelInfo, "New stable checkpoint.",
+		iss.checkpointLogger.Log(logging.LevelInfo, "New stable checkpoint.",
 			"epoch", stableCheckpoint.Epoch,
 			"sn", stableCheckpoint.Sn,
 			"replacingEpoch", iss.lastStableCheckpoint.Epoch,
 			"replacingSn", iss.lastStableCheckpoint.Sn)
 		iss.lastStableCheckpoint = stableCheckpoint
 
-		// TODO: Perform WAL truncation (and other cleanup).
+		// Note that the state encompassed by the checkpoint is only garbage-collected on the next tick
+		// (see garbageCollect).
+
+		// A checkpoint only becomes stable after the local application snapshot has been obtained.
+		checkpoint := iss.checkpoints[t.SeqNr(stableCheckpoint.Sn)]
+		eventsOut := (&events.EventList{}).PushBack(events.CheckpointStable(
+			t.EpochNr(stableCheckpoint.Epoch),
+			t.SeqNr(stableCheckpoint.Sn),
+			checkpoint.appSnapshot,
+		))
+
+		// Announce the members that persistently failed to keep up with the stable checkpoints.
+		for _, nodeID := range iss.lag.Stable(t.SeqNr(stableCheckpoint.Sn), checkpoint.membership) {
+			eventsOut.PushBack(events.NodeSlow(
+				nodeID, iss.epoch, t.SeqNr(stableCheckpoint.Sn), iss.lag.CheckpointSN(nodeID), false,
+			))
+		}
+
+		return eventsOut
+	}
+
+	iss.checkpointLogger.Log(logging.LevelInfo, "Ignoring outdated stable checkpoint.", "sn", stableCheckpoint.Sn)
+	return &events.EventList{}
+}
+
+// applyPersistCheckpoint applies a checkpoint loaded from the WAL at startup.
+// It only remembers the associated application snapshot and client watermarks,
+// which are used for restoring the application state and the watermarks
+// in case the checkpoint turns out to be stable (i.e., if a corresponding PersistStableCheckpoint event is applied).
+func (iss *ISS) applyPersistCheckpoint(persistCheckpoint *isspb.PersistCheckpoint) *events.EventList {
+	iss.recoveredCheckpoints[t.SeqNr(persistCheckpoint.Sn)] = persistCheckpoint
+	return &events.EventList{}
+}
+
+// applyPersistStableCheckpoint applies a stable checkpoint loaded from the WAL at startup.
+// If the checkpoint is more recent than the last stable checkpoint, the state of ISS advances
+// to the epoch of the recovered checkpoint, skipping all sequence numbers the checkpoint encompasses.
+func (iss *ISS) applyPersistStableCheckpoint(stableCheckpoint *isspb.StableCheckpoint) *events.EventList {
+
+	// Ignore checkpoints that are not more recent than the current one.
+	if stableCheckpoint.Sn <= iss.lastStableCheckpoint.Sn {
+		iss.checkpointLogger.Log(logging.LevelDebug, "Ignoring outdated stable checkpoint from WAL.", "sn", stableCheckpoint.Sn)
+		return &events.EventList{}
+	}
+
+	// A stable checkpoint must always have been preceded by the corresponding checkpoint in the WAL.
+	// The Node refuses to load a WAL in which this is not the case (returning an error before starting),
+	// so this can only happen if the events are fed to ISS directly. The stable checkpoint is then not recovered.
+	checkpoint, ok := iss.recoveredCheckpoints[t.SeqNr(stableCheckpoint.Sn)]
+	if !ok {
+		iss.checkpointLogger.Log(logging.LevelError, "No application snapshot recovered for stable checkpoint. Ignoring.",
+			"epoch", stableCheckpoint.Epoch, "sn", stableCheckpoint.Sn)
+		return &events.EventList{}
+	}
 
+	iss.checkpointLogger.Log(logging.LevelInfo, "Recovering stable checkpoint from WAL.",
+		"epoch", stableCheckpoint.Epoch, "sn", stableCheckpoint.Sn)
+
+	if len(checkpoint.Membership) > 0 {
+		// Start directly from the epoch of the checkpoint, with the configuration recorded in the checkpoint.
+		iss.restoreCheckpoint(t.EpochNr(stableCheckpoint.Epoch), checkpoint)
 	} else {
-		iss.logger.Log(logging.LevelInfo, "Ignoring outdated stable checkpoint.", "sn", stableCheckpoint.Sn)
+		// Checkpoints persisted before the configuration has been recorded in them can only be recovered
+		// by skipping all the epochs (and their sequence numbers) encompassed by the checkpoint.
+		iss.skipToEpoch(t.EpochNr(stableCheckpoint.Epoch))
+		if iss.nextDeliveredSN != t.SeqNr(stableCheckpoint.Sn) {
+			panic(violation(InvariantWatermarkOrder,
+				"recovered stable checkpoint (epoch %d, sn %d) does not match first sn of its epoch (%d)",
+				stableCheckpoint.Epoch, stableCheckpoint.Sn, iss.nextDeliveredSN))
+		}
+		iss.restoreCheckpointTracker(t.EpochNr(stableCheckpoint.Epoch), checkpoint)
 	}
 
+	// Record the start from the checkpoint in the epoch history.
+	iss.epochHistory.AddCause(isspb.EpochChangeReason_RESTORED, 0)
+	iss.epochHistory.EpochStarted(iss.epoch, iss.nextDeliveredSN, iss.epochLeaders)
+
+	// Save the checkpoint as the most recent stable one.
+	iss.lastStableCheckpoint = stableCheckpoint
+
 	return &events.EventList{}
 }
 
@@ -435,6 +1126,16 @@ func (iss *ISS) applyMessageReceived(messageReceived *eventpb.MessageReceived) *
 	message := messageReceived.Msg
 	from := t.NodeID(messageReceived.From)
 
+	// Any message received from a node shows that the node is alive.
+	if iss.liveness != nil {
+		iss.liveness.Observe(from)
+	}
+
+	// While joining, only the state to start from is processed.
+	if iss.joining {
+		return iss.applyMessageWhileJoining(message.Type.(*messagepb.Message_Iss).Iss, from)
+	}
+
 	// ISS only accepts ISS messages. If another message is applied, the next line panics.
 	switch msg := message.Type.(*messagepb.Message_Iss).Iss.Type.(type) {
 	case *isspb.ISSMessage_Checkpoint:
@@ -443,14 +1144,68 @@ func (iss *ISS) applyMessageReceived(messageReceived *eventpb.MessageReceived) *
 		return iss.applySBMessage(msg.Sb, from)
 	case *isspb.ISSMessage_RetransmitRequests:
 		return iss.applyRetransmitRequestsMessage(msg.RetransmitRequests, from)
+	case *isspb.ISSMessage_FetchRequests:
+		return iss.applyFetchRequestsMessage(msg.FetchRequests, from)
+	case *isspb.ISSMessage_Heartbeat:
+		// A Heartbeat carries no information besides the liveness of the sender, which has already been recorded.
+		return &events.EventList{}
+	case *isspb.ISSMessage_StateRequest:
+		return iss.applyStateRequestMessage(from)
+	case *isspb.ISSMessage_StateTransfer:
+		// The state is only needed while joining.
+		iss.checkpointLogger.Log(logging.LevelDebug, "Ignoring StateTransfer message.", "from", from)
+		return &events.EventList{}
 	default:
 		panic(fmt.Errorf("unknown ISS message type: %T", msg))
 	}
 }
 
+// checkLiveness advances the clock of the liveness tracker, sending a round of Heartbeat messages if one is due,
+// and reports the leaders of the current epoch that became unresponsive to the leader selection policy.
+func (iss *ISS) checkLiveness() *events.EventList {
+	eventsOut := &events.EventList{}
+
+	// Learners do not send heartbeats, as the members do not accept messages from them.
+	if iss.liveness.Tick() && !iss.learner() {
+		eventsOut.PushBack(events.SendMessage(HeartbeatMessage(), removeNodeID(iss.config.Membership, iss.ownID)))
+	}
+
+	// Iterate over the leaders in their order (and not over a map) for the suspicions to be reported deterministically.
+	for _, leader := range iss.epochLeaders {
+		if _, ok := iss.unresponsiveLeaders[leader]; ok || !iss.liveness.Unresponsive(leader) {
+			continue
+		}
+		iss.epochLogger.Log(logging.LevelWarn, "Suspecting unresponsive leader.", "leader", leader, "epoch", iss.epoch)
+		iss.unresponsiveLeaders[leader] = struct{}{}
+		iss.config.LeaderPolicy.Suspect(iss.epoch, leader)
+		iss.epochHistory.AddCause(isspb.EpochChangeReason_LEADER_UNRESPONSIVE, leader)
+		eventsOut.PushBack(events.NodeSuspected(leader, iss.epoch))
+	}
+
+	return eventsOut
+}
+
 // applyCheckpointMessage relays a Checkpoint message received over the network to the appropriate CheckpointTracker.
 func (iss *ISS) applyCheckpointMessage(chkpMsg *isspb.Checkpoint, source t.NodeID) *events.EventList {
-	return iss.getCheckpointTracker(t.SeqNr(chkpMsg.Sn)).applyMessage(chkpMsg, source)
+
+	// Use the timestamp piggybacked on the message to estimate the clock skew of the sender.
+	iss.clockSkew.Observe(source, chkpMsg.Timestamp)
+
+	// Update the lag of the sender, announcing it if the sender stopped being slow.
+	eventsOut := &events.EventList{}
+	if iss.lag.Observe(source, t.SeqNr(chkpMsg.Sn)) {
+		eventsOut.PushBack(events.NodeSlow(
+			source, iss.epoch, t.SeqNr(iss.lastStableCheckpoint.Sn), t.SeqNr(chkpMsg.Sn), true,
+		))
+	}
+
+	// Ignore messages of checkpoints older than the last stable one.
+	// Their checkpoint trackers might have been garbage-collected and must not be created again.
+	if chkpMsg.Sn < iss.lastStableCheckpoint.Sn {
+		return eventsOut
+	}
+
+	return eventsOut.PushBackList(iss.getCheckpointTracker(t.SeqNr(chkpMsg.Sn)).applyMessage(chkpMsg, source))
 }
 
 // applySBMessage applies a message destined for an orderer (i.e. a Sequenced Broadcast implementation).
@@ -497,6 +1252,12 @@ func (iss *ISS) applyRetransmitRequestsMessage(req *isspb.RetransmitRequests, fr
 	return &events.EventList{}
 }
 
+// applyFetchRequestsMessage applies a message of a node missing the payloads of committed requests
+// (see Config.HashOnlyOrdering). It has the requested payloads forwarded to the node from the request store.
+func (iss *ISS) applyFetchRequestsMessage(fetch *isspb.FetchRequests, from t.NodeID) *events.EventList {
+	return (&events.EventList{}).PushBack(events.ForwardRequests(fetch.Requests, []t.NodeID{from}))
+}
+
 // ============================================================
 // Additional protocol logic
 // ============================================================
@@ -508,6 +1269,9 @@ func (iss *ISS) initEpoch(newEpoch t.EpochNr) {
 	// Note that leader policy is stateful, choosing leaders deterministically based on the state of the system.
 	// Its state must be consistent across all nodes when calling Leaders() on it.
 	leaders := iss.config.LeaderPolicy.Leaders(newEpoch)
+	iss.epochLeaders = leaders
+	iss.unresponsiveLeaders = make(map[t.NodeID]struct{})
+	iss.handoffLeaders = make(map[t.NodeID]struct{})
 
 	// Compute the assignment of buckets to orderers (each leader will correspond to one orderer).
 	leaderBuckets := iss.buckets.Distribute(leaders, newEpoch)
@@ -530,6 +1294,7 @@ func (iss *ISS) initEpoch(newEpoch t.EpochNr) {
 		seg := &segment{
 			Leader:     leader,
 			Membership: iss.config.Membership,
+			Learners:   iss.config.Learners,
 			SeqNrs: sequenceNumbers(
 				iss.nextDeliveredSN+t.SeqNr(i),
 				t.SeqNr(len(leaders)),
@@ -544,8 +1309,8 @@ func (iss *ISS) initEpoch(newEpoch t.EpochNr) {
 			seg,
 			iss.buckets.Select(seg.BucketIDs).TotalRequests(),
 			iss.config,
-			&sbEventService{epoch: newEpoch, instanceID: iss.nextOrdererID},
-			logging.Decorate(iss.logger, "PBFT: ", "epoch", newEpoch, "instance", iss.nextOrdererID))
+			&sbEventService{epoch: newEpoch, instanceID: iss.nextOrdererID, configEpoch: iss.configEpoch},
+			logging.Decorate(iss.ordererLogger, "PBFT: ", "epoch", newEpoch, "instance", iss.nextOrdererID))
 		iss.orderers[iss.nextOrdererID] = sbInst
 
 		// Increment the ID to give to the next orderer.
@@ -559,16 +1324,51 @@ func (iss *ISS) initEpoch(newEpoch t.EpochNr) {
 
 	// Set the new epoch number as the current epoch.
 	iss.epoch = newEpoch
+	atomic.StoreUint64(&iss.validationEpoch, newEpoch.Pb())
+}
+
+// bucketLeaders returns the leaders the buckets are assigned to in the current epoch, indexed by bucket ID.
+func (iss *ISS) bucketLeaders() []t.NodeID {
+	leaders := make([]t.NodeID, iss.config.NumBuckets)
+	for bID := range leaders {
+		leaders[bID] = iss.bucketOrderers[bID].Segment().Leader
+	}
+	return leaders
+}
+
+// skipToEpoch advances the state of ISS to the beginning of epoch targetEpoch,
+// as if all sequence numbers of the preceding epochs have been delivered.
+// It is used when recovering a stable checkpoint from the WAL, since the deliveries leading up to the checkpoint
+// are not persisted themselves. The epochs are initialized one by one, so that the orderer IDs
+// (as well as the state of the leader selection policy) evolve exactly the same way as in normal operation.
+func (iss *ISS) skipToEpoch(targetEpoch t.EpochNr) {
+	for iss.epoch < targetEpoch {
+
+		// Skip all sequence numbers of the current epoch.
+		for _, orderer := range iss.orderers {
+			iss.nextDeliveredSN += t.SeqNr(len(orderer.Segment().SeqNrs))
+		}
+
+		// Discard the orderers of the skipped epoch, as they will never deliver anything.
+		iss.orderers = make(map[t.SBInstanceID]sbInstance)
+
+		iss.initEpoch(iss.epoch + 1)
+	}
 }
 
 func (iss *ISS) initOrderers() *events.EventList {
 	eventsOut := &events.EventList{}
 
 	sbInit := SBInitEvent()
-	for _, orderer := range iss.orderers {
+	for _, orderer := range iss.sortedOrderers() {
 		eventsOut.PushBackList(orderer.ApplyEvent(sbInit))
 	}
 
+	// A node stepping down hands off the segments of the new orderers right away.
+	if iss.steppingDown {
+		eventsOut.PushBackList(iss.stepDownOrderers())
+	}
+
 	return eventsOut
 }
 
@@ -609,6 +1409,12 @@ func (iss *ISS) validateSBMessage(message *isspb.SBMessage, from t.NodeID) error
 		return fmt.Errorf("sender of SB message not in the membership: %d", from)
 	}
 
+	// Message must have been generated under the same configuration.
+	if t.EpochNr(message.ConfigEpoch) != iss.configEpoch {
+		return fmt.Errorf("incompatible configuration version: %d (current version is %d)",
+			message.ConfigEpoch, iss.configEpoch)
+	}
+
 	return nil
 }
 
@@ -653,17 +1459,9 @@ func (iss *ISS) notifyOrderer(reqRef *requestpb.RequestRef) *events.EventList {
 // TODO: Implement at least one of these options.
 func (iss *ISS) demandRequestRetransmission(reqInfo *missingRequestInfo) *events.EventList {
 
-	// Create a slice of requests for which to demand retransmission.
-	// This is only necessary because they are stored in a map.
-	requests := make([]*requestpb.RequestRef, 0, len(reqInfo.Requests))
-	// TODO: iterate in a deterministic order!
-	for _, reqRef := range reqInfo.Requests {
-		requests = append(requests, reqRef)
-	}
-
 	// Send a message to the leader that made the proposal for which requests are still missing.
 	return (&events.EventList{}).PushBack(events.SendMessage(
-		RetransmitRequestsMessage(requests), []t.NodeID{reqInfo.Orderer.Segment().Leader},
+		RetransmitRequestsMessage(sortedRequests(reqInfo.Requests)), []t.NodeID{reqInfo.Orderer.Segment().Leader},
 	))
 }
 
@@ -673,53 +1471,67 @@ func (iss *ISS) demandRequestRetransmission(reqInfo *missingRequestInfo) *events
 func (iss *ISS) deliverCommitted() *events.EventList {
 	eventsOut := &events.EventList{}
 
-	// In case more than one Deliver events are produced, they need to be chained (using the Next field)
-	// so they are guaranteed to be processed in the same order as they have been created.
-	// firstDeliverEvent is the one ultimately returned by deliverCommitted.
-	// lastDeliverEvent is the one every new event is appended to (before becoming the lastDeliverEvent itself)
-	var firstDeliverEvent *eventpb.Event = nil
-	var lastDeliverEvent *eventpb.Event = nil
-
 	// The iss.nextDeliveredSN variable always contains the lowest sequence number
 	// for which no batch has been delivered yet.
 	// As long as there is an entry in the commitLog with that sequence number,
 	// deliver the corresponding batch and advance to the next sequence number.
-	for iss.commitLog[iss.nextDeliveredSN] != nil {
-
-		// TODO: Once system configuration requests are introduced, apply them here.
-
-		// Create a new Deliver event.
-		deliverEvent := events.Deliver(iss.nextDeliveredSN, iss.commitLog[iss.nextDeliveredSN].Batch)
+	// Delivery also stops at the first batch with missing payloads (see Config.HashOnlyOrdering).
+	// The Deliver events are all output directly (rather than chained using the Next field),
+	// so they are guaranteed to be processed in the order they have been created and before any event output after them
+	// (in particular, before the AppSnapshotRequest of a checkpoint including the delivered batches).
+	// Chained follow-up events would only be processed after the events output after them.
+	for iss.commitLog[iss.nextDeliveredSN] != nil && iss.missingPayloads[iss.nextDeliveredSN] == nil {
 
 		// Output debugging information.
 		iss.logger.Log(logging.LevelDebug, "Delivering entry.",
 			"sn", iss.nextDeliveredSN, "nReq", len(iss.commitLog[iss.nextDeliveredSN].Batch.Requests))
 
-		if firstDeliverEvent == nil {
-			// If this is the first event produced, it is the first and last one at the same time
-			firstDeliverEvent = deliverEvent
-			lastDeliverEvent = deliverEvent
-		} else {
-			// If an event already has been produced,
-			// append the new event to the end of the event chain and make it the last event.
-			lastDeliverEvent.Next = append(lastDeliverEvent.Next, deliverEvent)
-			lastDeliverEvent = deliverEvent
-		}
+		// Create a new Deliver event.
+		eventsOut.PushBack(events.Deliver(iss.nextDeliveredSN, iss.commitLog[iss.nextDeliveredSN].Batch))
 
-		iss.nextDeliveredSN++
-	}
+		// Interpret the configuration requests contained in the batch, if any.
+		iss.applyConfigRequests(iss.commitLog[iss.nextDeliveredSN].Batch)
 
-	// If at least one deliver event occurred,
-	// output the first produced Deliver event (with potential others chained to it using its Next field).
-	if firstDeliverEvent != nil {
-		eventsOut.PushBack(firstDeliverEvent)
+		iss.nextDeliveredSN++
 	}
 
 	// If the epoch is finished, transition to the next epoch.
 	if iss.epochFinished() {
 
+		// Evaluate the statistics of the finished epoch's leaders
+		// and announce the leaders exceeding the configured thresholds to the leader selection policy.
+		// This must happen before initializing the new epoch, since the new leaders depend on the suspicions.
+		for _, suspect := range iss.leaderStats.endEpoch(iss.epochLeaders, iss.config.LeaderStatsThresholds, iss.epochLogger) {
+			iss.config.LeaderPolicy.Suspect(iss.epoch, suspect)
+			iss.epochHistory.AddCause(isspb.EpochChangeReason_LEADER_STATS, suspect)
+			eventsOut.PushBack(events.NodeSuspected(suspect, iss.epoch))
+		}
+
+		// Likewise, announce the leaders that handed off their segments, as they are about to shut down.
+		// The handoffs are part of the committed batches, and thus observed by all nodes.
+		for _, leader := range iss.epochLeaders {
+			if _, ok := iss.handoffLeaders[leader]; ok {
+				iss.epochLogger.Log(logging.LevelInfo, "Leader handed off its segment.", "leader", leader, "epoch", iss.epoch)
+				iss.config.LeaderPolicy.Suspect(iss.epoch, leader)
+				iss.epochHistory.AddCause(isspb.EpochChangeReason_LEADER_HANDOFF, leader)
+				eventsOut.PushBack(events.NodeSuspected(leader, iss.epoch))
+			}
+		}
+
+		// Activate the configuration changes committed in the finished epoch, if any.
+		// They take effect at the checkpoint the new epoch starts with, i.e., at the same point at all nodes.
+		// The checkpoint itself is still established by the finished epoch's membership.
+		checkpointMembership := iss.config.Membership
+		checkpointLearners := iss.config.Learners
+		if iss.pendingConfig != nil {
+			iss.epochHistory.AddCause(isspb.EpochChangeReason_CONFIG_CHANGE, 0)
+		}
+		eventsOut.PushBackList(iss.activateConfig(iss.epoch + 1))
+
 		// Initialize the internal data structures for the new epoch.
 		iss.initEpoch(iss.epoch + 1)
+		iss.epochHistory.EpochStarted(iss.epoch, iss.nextDeliveredSN, iss.epochLeaders)
+		eventsOut.PushBack(events.EpochStarted(iss.epoch, iss.epochLeaders, iss.bucketLeaders()))
 
 		// Look up a (or create a new) checkpoint tracker and start the checkpointing protocol.
 		// This must happen after initialization of the new epoch,
@@ -728,7 +1540,16 @@ func (iss *ISS) deliverCommitted() *events.EventList {
 		// The checkpoint tracker might already exist if a corresponding message has been already received.
 		// iss.nextDeliveredSN is the first sequence number *not* included in the checkpoint,
 		// i.e., as sequence numbers start at 0, the checkpoint includes the first iss.nextDeliveredSN sequence numbers.
-		eventsOut.PushBackList(iss.getCheckpointTracker(iss.nextDeliveredSN).Start(iss.epoch, iss.config.Membership))
+		eventsOut.PushBackList(iss.getCheckpointTracker(iss.nextDeliveredSN).Start(
+			iss.epoch,
+			checkpointMembership,
+			checkpointLearners,
+			iss.config,
+			iss.firstInstanceOfEpoch(),
+			iss.configEpoch,
+			clientKeysPb(iss.clientKeys),
+			nodeKeysPb(iss.nodeKeys),
+		))
 
 		// Give the init signals to the newly instantiated orderers.
 		// TODO: Currently this probably sends the Init event to old orderers as well.
@@ -742,15 +1563,96 @@ func (iss *ISS) deliverCommitted() *events.EventList {
 	return eventsOut
 }
 
+// registerMissingPayloads checks, for a batch committed at sequence number sn by the given orderer,
+// whether the node has received the payloads of all the batch's requests that it needs for delivering the batch.
+// With Config.HashOnlyOrdering, these are all the requests, the payload of which counts as received
+// if the request is in its bucket. Otherwise, these are only the configuration requests,
+// the content of which must be known when the batch is delivered (see applyConfigRequests),
+// as the epoch transition following the batch depends on it.
+// The leader of the orderer is the one who proposed the batch (having removed its requests from its buckets)
+// and thus never misses any payloads.
+// If some payloads are missing, registerMissingPayloads registers them, such that the batch is not delivered
+// (and thus the epoch does not end) until they are received, and immediately fetches them from the leader.
+// registerMissingPayloads must be called before the requests of the batch are removed from their buckets.
+func (iss *ISS) registerMissingPayloads(sn t.SeqNr, batch *requestpb.Batch, orderer sbInstance) *events.EventList {
+
+	// The leader has all the payloads.
+	if orderer.Segment().Leader == iss.ownID {
+		return &events.EventList{}
+	}
+
+	// Collect the requests that are not present in the local buckets.
+	missingPayloads := &missingRequestInfo{
+		Sn:             sn,
+		Requests:       make(map[string]*requestpb.RequestRef),
+		Orderer:        orderer,
+		TicksUntilNAck: iss.config.RequestNAckTimeout,
+	}
+	for _, reqRef := range batch.Requests {
+		reqKey := reqStrKey(reqRef)
+		_, configKnown := iss.configRequests[reqKey]
+		if (iss.config.HashOnlyOrdering && !iss.buckets.RequestBucket(reqRef).Contains(reqRef)) ||
+			(t.ClientID(reqRef.ClientId) == t.ConfigClientID && !configKnown) {
+			missingPayloads.Requests[reqKey] = reqRef
+			iss.missingPayloadIndex[reqKey] = missingPayloads
+		}
+	}
+
+	// If all payloads are present, nothing needs to be done.
+	if len(missingPayloads.Requests) == 0 {
+		return &events.EventList{}
+	}
+
+	// Otherwise, register the missing payloads and fetch them.
+	iss.clientLogger.Log(logging.LevelDebug, "Fetching missing payloads of committed batch.",
+		"sn", sn, "numMissing", len(missingPayloads.Requests), "leader", orderer.Segment().Leader)
+	iss.missingPayloads[sn] = missingPayloads
+	return iss.fetchPayloads(missingPayloads)
+}
+
+// fetchPayloads asks the leader who proposed a committed batch for the payloads of the batch's missing requests.
+// The leader responds by forwarding the requests, which will result in a RequestReady event for each of them.
+func (iss *ISS) fetchPayloads(missingPayloads *missingRequestInfo) *events.EventList {
+	return (&events.EventList{}).PushBack(events.SendMessage(
+		FetchRequestsMessage(sortedRequests(missingPayloads.Requests)),
+		[]t.NodeID{missingPayloads.Orderer.Segment().Leader},
+	))
+}
+
+// payloadReceived marks the payload of a committed request as received.
+// If this was the last payload missing from the first undelivered batch,
+// the delivery of batches to the application continues.
+func (iss *ISS) payloadReceived(reqRef *requestpb.RequestRef) *events.EventList {
+	reqKey := reqStrKey(reqRef)
+	missingPayloads := iss.missingPayloadIndex[reqKey]
+
+	// Remove the request from the missing payloads.
+	delete(iss.missingPayloadIndex, reqKey)
+	delete(missingPayloads.Requests, reqKey)
+
+	// If no more payloads are missing from the batch, remove its entry and continue delivery.
+	if len(missingPayloads.Requests) == 0 {
+		delete(iss.missingPayloads, missingPayloads.Sn)
+		return iss.deliverCommitted()
+	}
+	return &events.EventList{}
+}
+
 // applyBufferedMessages applies all SB messages destined to the current epoch
 // that have been buffered during past epochs.
 // This function is always called directly after initializing a new epoch, except for epoch 0.
 func (iss *ISS) applyBufferedMessages() *events.EventList {
 	eventsOut := &events.EventList{}
 
-	// Iterate over the all messages in all buffers, selecting those that can be applied.
-	for _, buffer := range iss.messageBuffers {
-		buffer.Iterate(iss.bufferedMessageFilter, func(source t.NodeID, msg proto.Message) {
+	// Iterate over the all messages in all buffers (in the order of the source node IDs),
+	// selecting those that can be applied.
+	sources := make([]t.NodeID, 0, len(iss.messageBuffers))
+	for nodeID := range iss.messageBuffers {
+		sources = append(sources, nodeID)
+	}
+	sortNodeIDs(sources)
+	for _, nodeID := range sources {
+		iss.messageBuffers[nodeID].Iterate(iss.bufferedMessageFilter, func(source t.NodeID, msg proto.Message) {
 
 			// Apply all messages selected by the filter.
 			switch m := msg.(type) {
@@ -766,6 +1668,83 @@ func (iss *ISS) applyBufferedMessages() *events.EventList {
 	return eventsOut
 }
 
+// garbageCollect deletes all the state associated with sequence numbers below the last stable checkpoint
+// and truncates the WAL accordingly. It performs a single pass up to the last stable checkpoint,
+// regardless of how many checkpoints became stable since the previous pass.
+// Each data structure is only traversed to the extent that it contains state below the stable checkpoint.
+func (iss *ISS) garbageCollect() *events.EventList {
+	gcSN := t.SeqNr(iss.lastStableCheckpoint.Sn)
+
+	iss.checkpointLogger.Log(logging.LevelDebug, "Garbage-collecting.", "fromSn", iss.gcSN, "toSn", gcSN)
+
+	// Delete the commit log entries. All of them have been delivered,
+	// since a checkpoint can only become stable after the local application created a snapshot of it.
+	// Only the sequence numbers since the previous pass are visited.
+	// The requests contained in the deleted entries advance the low watermarks of their clients.
+	for sn := iss.gcSN; sn < gcSN; sn++ {
+		if entry, ok := iss.commitLog[sn]; ok && !entry.Aborted {
+			for _, reqRef := range entry.Batch.Requests {
+				iss.clientWatermarks.commit(reqRef)
+			}
+		}
+		delete(iss.commitLog, sn)
+	}
+
+	// Delete the checkpoint trackers of older checkpoints.
+	// The tracker of the stable checkpoint itself is retained.
+	for sn := range iss.checkpoints {
+		if sn < gcSN {
+			delete(iss.checkpoints, sn)
+		}
+	}
+
+	// Delete the orderers whose whole segment precedes the stable checkpoint.
+	// Orderers of the current epoch are skipped without inspecting their segments.
+	currentOrderers := iss.orderersOfEpoch()
+	for id, orderer := range iss.orderers {
+		if _, current := currentOrderers[id]; current {
+			continue
+		}
+		if segmentBelow(orderer.Segment(), gcSN) {
+			delete(iss.orderers, id)
+		}
+	}
+
+	iss.gcSN = gcSN
+
+	// Truncate the WAL, removing all entries of epochs preceding the one of the stable checkpoint.
+	// The entries of the checkpoint's epoch itself (including the checkpoint) are retained.
+	walEvent := events.WALTruncate(t.WALRetIndex(iss.lastStableCheckpoint.Epoch))
+
+	// Only after the WAL has been truncated, prune the requests below the clients' advanced low watermarks.
+	// Otherwise, in case of a crash, the WAL could still reference requests that have already been pruned.
+	// Clients whose low watermarks did not advance are skipped.
+	// The subscribers of the Node's events are notified about each advanced low watermark.
+	eventsOut := (&events.EventList{}).PushBack(walEvent)
+	for _, clientID := range iss.clientWatermarks.advanced() {
+		walEvent.FollowUp(events.PruneRequests(clientID, iss.clientWatermarks.watermark(clientID)))
+		eventsOut.PushBack(events.ClientWindowMoved(clientID, iss.clientWatermarks.watermark(clientID)))
+	}
+
+	return eventsOut
+}
+
+// firstInstanceOfEpoch returns the ID of the first orderer of the current epoch.
+// As orderer IDs are assigned in increasing order, the orderers of the epoch are the last len(iss.epochLeaders) ones.
+func (iss *ISS) firstInstanceOfEpoch() t.SBInstanceID {
+	return iss.nextOrdererID - t.SBInstanceID(len(iss.epochLeaders))
+}
+
+// orderersOfEpoch returns the set of IDs of the orderers of the current epoch.
+// As orderer IDs are assigned in increasing order, those are the last len(iss.epochLeaders) assigned IDs.
+func (iss *ISS) orderersOfEpoch() map[t.SBInstanceID]struct{} {
+	ids := make(map[t.SBInstanceID]struct{}, len(iss.epochLeaders))
+	for i := 1; i <= len(iss.epochLeaders); i++ {
+		ids[iss.nextOrdererID-t.SBInstanceID(i)] = struct{}{}
+	}
+	return ids
+}
+
 // removeFromBuckets removes the given requests from their corresponding buckets.
 // This happens when a batch is committed.
 // TODO: Implement marking requests as "in flight"/proposed, so we don't accept proposals with duplicates.
@@ -827,14 +1806,57 @@ func sequenceNumbers(start t.SeqNr, step t.SeqNr, length int) []t.SeqNr {
 	return seqNrs
 }
 
+// loadedFromWAL returns true if the given SB instance event can only originate from the WAL, i.e.,
+// if it is one of the events orderers persist in the WAL (in contrast to the events generated during normal operation).
+func loadedFromWAL(event *isspb.SBInstanceEvent) bool {
+	switch event.Type.(type) {
+	case *isspb.SBInstanceEvent_PbftPersistPreprepare:
+		return true
+	default:
+		return false
+	}
+}
+
 // reqStrKey takes a request reference and transforms it to a string for using as a map key.
 func reqStrKey(reqRef *requestpb.RequestRef) string {
 	return fmt.Sprintf("%d-%d.%v", reqRef.ClientId, reqRef.ReqNo, reqRef.Digest)
 }
 
+// segmentBelow returns true if all sequence numbers of the segment are lower than sn.
+func segmentBelow(seg *segment, sn t.SeqNr) bool {
+	for _, segSN := range seg.SeqNrs {
+		if segSN >= sn {
+			return false
+		}
+	}
+	return true
+}
+
 // membershipSet takes a list of node IDs and returns a map of empty structs with an entry for each node ID in the list.
 // The returned map is effectively a set representation of the given list,
 // useful for testing whether any given node ID is in the set.
+// learnerMessage returns true if msg is of a type accepted from learners,
+// i.e., a request for the state to start from (see Config.Join)
+// or for the payloads of committed requests (see Config.HashOnlyOrdering).
+func learnerMessage(msg *messagepb.Message) bool {
+	issMsg, ok := msg.Type.(*messagepb.Message_Iss)
+	if !ok || issMsg.Iss == nil {
+		return false
+	}
+	switch issMsg.Iss.Type.(type) {
+	case *isspb.ISSMessage_StateRequest, *isspb.ISSMessage_FetchRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// learner returns true if this node is a learner (see Config.Learners) in the current configuration.
+func (iss *ISS) learner() bool {
+	_, ok := membershipSet(iss.config.Learners)[iss.ownID]
+	return ok
+}
+
 func membershipSet(membership []t.NodeID) map[t.NodeID]struct{} {
 
 	// Allocate a new map representing a set of node IDs
@@ -849,6 +1871,68 @@ func membershipSet(membership []t.NodeID) map[t.NodeID]struct{} {
 	return set
 }
 
+// sortedNodeIDs returns the node IDs in a set in increasing order.
+func sortedNodeIDs(set map[t.NodeID]struct{}) []t.NodeID {
+	nodeIDs := make([]t.NodeID, 0, len(set))
+	for nodeID := range set {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sortNodeIDs(nodeIDs)
+	return nodeIDs
+}
+
+// wallClock returns the source of wall clock time configured in config (see Config.Now), defaulting to time.Now.
+func wallClock(config *Config) func() time.Time {
+	if config.Now != nil {
+		return config.Now
+	}
+	return time.Now
+}
+
+// sortedOrderers returns the orderers in the order of their instance IDs.
+// Iterating over the orderers in this order (rather than over the map) when producing events
+// makes the protocol's output independent of the map iteration order, such that it is reproducible
+// (e.g. in a simulation, see package testengine).
+func (iss *ISS) sortedOrderers() []sbInstance {
+	ids := make([]t.SBInstanceID, 0, len(iss.orderers))
+	for id := range iss.orderers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	orderers := make([]sbInstance, len(ids))
+	for i, id := range ids {
+		orderers[i] = iss.orderers[id]
+	}
+	return orderers
+}
+
+// sortedSeqNrs returns the sequence numbers indexing the missing requests (or payloads) in increasing order.
+func sortedSeqNrs(missing map[t.SeqNr]*missingRequestInfo) []t.SeqNr {
+	sns := make([]t.SeqNr, 0, len(missing))
+	for sn := range missing {
+		sns = append(sns, sn)
+	}
+	sort.Slice(sns, func(i, j int) bool { return sns[i] < sns[j] })
+	return sns
+}
+
+// sortedRequests returns the requests of the given map (indexed by reqStrKey) ordered by their keys.
+// Requests sent in messages are listed in this order (and not in the order of map iteration),
+// such that the messages only depend on the state of the protocol.
+func sortedRequests(requests map[string]*requestpb.RequestRef) []*requestpb.RequestRef {
+	keys := make([]string, 0, len(requests))
+	for key := range requests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]*requestpb.RequestRef, 0, len(requests))
+	for _, key := range keys {
+		sorted = append(sorted, requests[key])
+	}
+	return sorted
+}
+
 // removeNodeID emoves a node ID from a list of node IDs.
 // Takes a membership list and a Node ID and returns a new list of nodeIDs containing all IDs from the membership list,
 // except for (if present) the specified nID.
@@ -869,14 +1953,13 @@ func removeNodeID(membership []t.NodeID, nID t.NodeID) []t.NodeID {
 	return others
 }
 
-func strongQuorum(n int) int {
-	// assuming n = 3f + 1:
-	//     2 *  f      + 1
-	return 2*(n-1)/3 + 1
+// validRequestRefs returns true if none of the given request references is nil.
+func validRequestRefs(reqRefs []*requestpb.RequestRef) bool {
+	for _, reqRef := range reqRefs {
+		if reqRef == nil {
+			return false
+		}
+	}
+	return true
 }
 
-func weakQuorum(n int) int {
-	// assuming n = 3f + 1:
-	//      f      + 1
-	return (n-1)/3 + 1
-}
@@ -0,0 +1,13 @@
+package iss_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestIss(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Iss Suite")
+}
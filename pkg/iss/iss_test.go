@@ -0,0 +1,168 @@
+package iss_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var membership = []t.NodeID{0, 1, 2, 3}
+
+var _ = Describe("Config", func() {
+
+	It("accepts the default configuration", func() {
+		Expect(iss.CheckConfig(iss.DefaultConfig(membership))).To(Succeed())
+	})
+
+	DescribeTable("rejects invalid configurations",
+		func(modify func(config *iss.Config)) {
+			config := iss.DefaultConfig(membership)
+			modify(config)
+			Expect(iss.CheckConfig(config)).NotTo(Succeed())
+
+			_, err := iss.New(0, config, logging.NilLogger)
+			Expect(err).To(MatchError(ContainSubstring("invalid ISS configuration")))
+		},
+		Entry("empty membership", func(c *iss.Config) { c.Membership = nil }),
+		Entry("learner that is a member", func(c *iss.Config) { c.Learners = []t.NodeID{2} }),
+		Entry("duplicate learner", func(c *iss.Config) { c.Learners = []t.NodeID{5, 5} }),
+		Entry("both segment and epoch length", func(c *iss.Config) { c.EpochLength = 40 }),
+		Entry("neither segment nor epoch length", func(c *iss.Config) { c.SegmentLength = 0 }),
+		Entry("no buckets", func(c *iss.Config) { c.NumBuckets = 0 }),
+		Entry("no leader policy", func(c *iss.Config) { c.LeaderPolicy = nil }),
+		Entry("no request NAck timeout", func(c *iss.Config) { c.RequestNAckTimeout = 0 }),
+		Entry("empty batch rate out of range", func(c *iss.Config) {
+			c.LeaderStatsThresholds = &iss.LeaderStatsThresholds{MaxEmptyBatchRate: 1.5}
+		}),
+		Entry("negative clock skew", func(c *iss.Config) { c.MaxClockSkew = -time.Second }),
+		Entry("negative client quota", func(c *iss.Config) { c.MaxClients = -1 }),
+		Entry("negative client window quota", func(c *iss.Config) { c.MaxClientWindows = -1 }),
+		Entry("suspect timeout shorter than heartbeat period", func(c *iss.Config) {
+			c.HeartbeatPeriod = 10
+			c.SuspectTimeout = 10
+		}),
+	)
+})
+
+var _ = Describe("SimpleLeaderPolicy", func() {
+
+	It("selects all members as leaders in every epoch, even suspected ones", func() {
+		policy := &iss.SimpleLeaderPolicy{Membership: membership}
+		policy.Suspect(0, 1)
+		Expect(policy.Leaders(0)).To(Equal(membership))
+		Expect(policy.Leaders(7)).To(Equal(membership))
+	})
+
+	It("selects the new members after reconfiguration", func() {
+		policy := &iss.SimpleLeaderPolicy{Membership: membership}
+		policy.Reconfigure(3, []t.NodeID{0, 1, 2, 3, 4})
+		Expect(policy.Leaders(3)).To(Equal([]t.NodeID{0, 1, 2, 3, 4}))
+	})
+})
+
+var _ = Describe("ValidateMessage", func() {
+
+	var protocol *iss.ISS
+
+	BeforeEach(func() {
+		config := iss.DefaultConfig(membership)
+		config.Learners = []t.NodeID{7}
+		var err error
+		protocol, err = iss.New(0, config, logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	requestRefs := []*requestpb.RequestRef{{ClientId: 0, ReqNo: 0, Digest: []byte{1}}}
+
+	It("accepts ISS messages from members", func() {
+		Expect(protocol.ValidateMessage(1, iss.CheckpointMessage(0, 10, 0, 0))).To(Succeed())
+		Expect(protocol.ValidateMessage(2, iss.HeartbeatMessage())).To(Succeed())
+		Expect(protocol.ValidateMessage(3, iss.FetchRequestsMessage(requestRefs))).To(Succeed())
+	})
+
+	It("rejects messages from unknown nodes", func() {
+		Expect(errors.Is(protocol.ValidateMessage(4, iss.HeartbeatMessage()), modules.ErrUnknownNode)).To(BeTrue())
+	})
+
+	It("accepts only state and payload requests from learners", func() {
+		Expect(protocol.ValidateMessage(7, iss.StateRequestMessage())).To(Succeed())
+		Expect(protocol.ValidateMessage(7, iss.FetchRequestsMessage(requestRefs))).To(Succeed())
+		Expect(errors.Is(protocol.ValidateMessage(7, iss.CheckpointMessage(0, 10, 0, 0)), modules.ErrUnknownNode)).To(BeTrue())
+	})
+
+	It("rejects messages that are not ISS messages", func() {
+		msg := &messagepb.Message{Type: &messagepb.Message_ForwardedRequest{}}
+		Expect(errors.Is(protocol.ValidateMessage(1, msg), modules.ErrMalformedMessage)).To(BeTrue())
+	})
+
+	It("rejects ISS messages with missing content", func() {
+		for _, msg := range []*messagepb.Message{
+			{Type: &messagepb.Message_Iss{}},
+			{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{Type: &isspb.ISSMessage_Checkpoint{}}}},
+			{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{Type: &isspb.ISSMessage_Sb{
+				Sb: &isspb.SBMessage{},
+			}}}},
+			iss.FetchRequestsMessage([]*requestpb.RequestRef{nil}),
+			iss.StateTransferMessage(1, &isspb.PersistCheckpoint{}),
+		} {
+			Expect(errors.Is(protocol.ValidateMessage(1, msg), modules.ErrMalformedMessage)).To(BeTrue())
+		}
+	})
+})
+
+var _ = Describe("Ordering", func() {
+
+	// run simulates a system with the given number of nodes, each configured by configure,
+	// until all requests are delivered, and returns the requests delivered by each node.
+	run := func(numNodes int, configure func(config *iss.Config)) [][]*requestpb.RequestRef {
+		engine, err := testengine.New(&testengine.Spec{
+			NumNodes:          numNodes,
+			NumClients:        2,
+			RequestsPerClient: 30,
+			ISSConfig:         func(_ t.NodeID, config *iss.Config) { configure(config) },
+		}, 1)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		delivered, err := engine.RunUntil(engine.AllDelivered, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delivered).To(BeTrue())
+		Expect(engine.CheckConsistency()).To(Succeed())
+
+		requests := make([][]*requestpb.RequestRef, numNodes)
+		for i, node := range engine.Nodes {
+			Expect(node.Err).NotTo(HaveOccurred())
+			requests[i] = node.App.Requests
+		}
+		return requests
+	}
+
+	DescribeTable("delivers all requests in the same order at all nodes",
+		func(numNodes int, configure func(config *iss.Config)) {
+			requests := run(numNodes, configure)
+			Expect(requests[0]).To(HaveLen(60))
+			for _, r := range requests[1:] {
+				Expect(r).To(Equal(requests[0]))
+			}
+		},
+		Entry("with a single node", 1, func(config *iss.Config) {}),
+		Entry("with the default configuration", 4, func(config *iss.Config) {}),
+		Entry("with short epochs", 4, func(config *iss.Config) { config.SegmentLength = 2 }),
+		Entry("with single-request batches", 4, func(config *iss.Config) { config.MaxBatchSize = 1 }),
+		Entry("with a single bucket", 4, func(config *iss.Config) { config.NumBuckets = 1 }),
+		Entry("with more buckets than nodes", 4, func(config *iss.Config) { config.NumBuckets = 16 }),
+		Entry("with seven nodes", 7, func(config *iss.Config) {}),
+	)
+})
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The node key rotation test rotates the key of a node of a running deployment using a configuration request.
+// All nodes must register the new key in the same epoch, retire the previous one after the overlap period,
+// and keep processing requests in the meantime.
+var _ = Describe("Node key rotation test", func() {
+
+	It("rotates the key of a node without interrupting the network", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All nodes accept requests from the configuration client.
+		// The overlap period is kept short for the previous key to be retired while the deployment is running.
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+			replica.ISSConfig.KeyRotationOverlap = 1
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// Create the configuration request rotating the key of a node.
+		rotatedNode := deployment.TestReplicas[1].Id
+		_, pubKey, err := mirCrypto.GenerateKeyPair(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		request := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			RotateNodes: []*isspb.NodeKey{{NodeId: rotatedNode.Pb(), PubKey: pubKey}},
+		})
+
+		// Submit the configuration request to all nodes after the network started
+		// and wait until all nodes applied it and moved past the epoch in which it took effect.
+		var submitErrs []error
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			submitErrs = deploytest.SubmitToAll(deployment.TestReplicas, request)
+			Eventually(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests+1), func() bool {
+				for _, replica := range deployment.TestReplicas {
+					var protocolState struct{ Epoch, ConfigEpoch t.EpochNr }
+					deploytest.DumpProtocol(replica.Node(), &protocolState)
+					if protocolState.ConfigEpoch == 0 || protocolState.Epoch <= protocolState.ConfigEpoch {
+						return false
+					}
+				}
+				return true
+			}), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, finalStatus := range finalStatuses {
+			Expect(submitErrs[i]).NotTo(HaveOccurred())
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		for i, node := range nodes {
+			var buf bytes.Buffer
+			Expect(node.Dump(&buf)).To(Succeed())
+			var dump struct {
+				Protocol struct {
+					Epoch       t.EpochNr
+					ConfigEpoch t.EpochNr
+					NodeKeys    []struct {
+						NodeID      t.NodeID
+						RetireEpoch t.EpochNr
+					}
+				}
+			}
+			Expect(json.Unmarshal(buf.Bytes(), &dump)).To(Succeed())
+
+			// The rotated key took effect and, once the overlap period is over, the previous key has been retired.
+			Expect(dump.Protocol.NodeKeys).To(HaveLen(1))
+			Expect(dump.Protocol.NodeKeys[0].NodeID).To(Equal(rotatedNode))
+			Expect(dump.Protocol.ConfigEpoch).NotTo(BeZero())
+			Expect(dump.Protocol.Epoch).To(BeNumerically(">", dump.Protocol.ConfigEpoch))
+			Expect(dump.Protocol.NodeKeys[0].RetireEpoch).To(BeZero())
+
+			// All requests, including the configuration request, have been applied.
+			Expect(deployment.TestReplicas[i].App.RequestsProcessed).To(Equal(uint64(testConfig.NumFakeRequests + 1)))
+		}
+	})
+})
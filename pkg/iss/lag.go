@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// lagTracker detects slow nodes, i.e., members that persistently lag behind the stable checkpoints of this node.
+// For each other member, the tracker remembers the latest checkpoint the member confirmed by a Checkpoint message.
+// Each time a checkpoint becomes stable locally, the members that have not (yet) confirmed it are considered behind.
+// As a checkpoint becomes stable as soon as a quorum confirms it, a correct node is often briefly behind,
+// but it confirms the checkpoint shortly afterwards.
+// Only a member that has been behind at more than the configured number of consecutive stable checkpoints
+// (see Config.MaxCheckpointLag) is flagged as slow, until it confirms the last stable checkpoint.
+// A slow node, unlike a faulty one, keeps confirming checkpoints (only late) and keeps responding to heartbeats
+// (see livenessTracker). A crashed node, however, is flagged as slow as well.
+// The lags never influence the protocol. They are only logged, announced as NodeSlow notifications,
+// and exposed in the protocol Status.
+type lagTracker struct {
+
+	// ID of this node, which is never tracked.
+	ownID t.NodeID
+
+	// Number of consecutive stable checkpoints a node can be behind without being flagged as slow.
+	// If set to zero, no node is ever flagged as slow.
+	maxLag int
+
+	// Sequence number of the last stable checkpoint.
+	stableSN t.SeqNr
+
+	// The lag of each other member of the configuration of the last stable checkpoint
+	// and of each node a Checkpoint message has been received from since then.
+	nodes map[t.NodeID]*nodeLag
+
+	// Logger for outputting the warnings about slow nodes.
+	logger logging.Logger
+}
+
+// nodeLag represents the lag of a single node.
+type nodeLag struct {
+
+	// Sequence number of the latest checkpoint the node confirmed.
+	checkpointSN t.SeqNr
+
+	// Number of consecutive stable checkpoints the node has not confirmed by the time they became stable.
+	// Reset when the node confirms the last stable checkpoint.
+	behind int
+
+	// Set if the node has been flagged as slow.
+	slow bool
+}
+
+// newLagTracker returns a new lagTracker with no nodes being behind.
+func newLagTracker(ownID t.NodeID, maxLag int, logger logging.Logger) *lagTracker {
+	return &lagTracker{
+		ownID:  ownID,
+		maxLag: maxLag,
+		nodes:  make(map[t.NodeID]*nodeLag),
+		logger: logger,
+	}
+}
+
+// node returns the lag of node nodeID, starting to track the node if it is not yet tracked.
+func (lt *lagTracker) node(nodeID t.NodeID) *nodeLag {
+	if _, ok := lt.nodes[nodeID]; !ok {
+		lt.nodes[nodeID] = &nodeLag{}
+	}
+	return lt.nodes[nodeID]
+}
+
+// Observe records node source confirming the checkpoint at sequence number sn.
+// It returns true if the node had been flagged as slow and caught up with the last stable checkpoint.
+func (lt *lagTracker) Observe(source t.NodeID, sn t.SeqNr) bool {
+	if source == lt.ownID {
+		return false
+	}
+
+	nl := lt.node(source)
+	if sn > nl.checkpointSN {
+		nl.checkpointSN = sn
+	}
+
+	// Only confirming the last stable checkpoint (or a later one) counts as having caught up.
+	if nl.checkpointSN < lt.stableSN {
+		return false
+	}
+	nl.behind = 0
+	if nl.slow {
+		nl.slow = false
+		lt.logger.Log(logging.LevelInfo, "Slow node caught up.", "nodeID", source, "sn", nl.checkpointSN)
+		return true
+	}
+	return false
+}
+
+// Stable records the checkpoint at sequence number sn becoming stable, having been executed by membership.
+// Nodes not in membership stop being tracked.
+// Stable returns the members that have newly been flagged as slow, in the order given by membership.
+func (lt *lagTracker) Stable(sn t.SeqNr, membership []t.NodeID) []t.NodeID {
+	lt.stableSN = sn
+
+	// Stop tracking nodes that are not members anymore.
+	members := membershipSet(membership)
+	for nodeID := range lt.nodes {
+		if _, ok := members[nodeID]; !ok {
+			delete(lt.nodes, nodeID)
+		}
+	}
+
+	// Iterate over the members in their order (and not over a map) for the slow nodes to be reported deterministically.
+	slow := make([]t.NodeID, 0)
+	for _, nodeID := range membership {
+		if nodeID == lt.ownID {
+			continue
+		}
+
+		nl := lt.node(nodeID)
+		if nl.checkpointSN >= sn {
+			nl.behind = 0
+			continue
+		}
+
+		nl.behind++
+		if lt.maxLag > 0 && nl.behind > lt.maxLag && !nl.slow {
+			nl.slow = true
+			lt.logger.Log(logging.LevelWarn, "Node lagging behind stable checkpoints.",
+				"nodeID", nodeID, "stableSN", sn, "checkpointSN", nl.checkpointSN, "behind", nl.behind)
+			slow = append(slow, nodeID)
+		}
+	}
+	return slow
+}
+
+// CheckpointSN returns the sequence number of the latest checkpoint node nodeID confirmed.
+func (lt *lagTracker) CheckpointSN(nodeID t.NodeID) t.SeqNr {
+	return lt.node(nodeID).checkpointSN
+}
+
+// Status returns the lags of the other nodes in the order given by membership.
+func (lt *lagTracker) Status(membership []t.NodeID) []*isspb.NodeLag {
+	lags := make([]*isspb.NodeLag, 0, len(lt.nodes))
+	for _, nodeID := range membership {
+		nl, ok := lt.nodes[nodeID]
+		if !ok {
+			continue
+		}
+		var lag t.SeqNr
+		if nl.checkpointSN < lt.stableSN {
+			lag = lt.stableSN - nl.checkpointSN
+		}
+		lags = append(lags, &isspb.NodeLag{
+			NodeId:            nodeID.Pb(),
+			CheckpointSn:      nl.checkpointSN.Pb(),
+			Lag:               lag.Pb(),
+			BehindCheckpoints: uint64(nl.behind),
+			Slow:              nl.slow,
+		})
+	}
+	return lags
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Slow node test", func() {
+
+	It("flags a node that never confirms the stable checkpoints", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The last replica participates in the protocol, but never sends its Checkpoint messages.
+		slow := deployment.TestReplicas[len(deployment.TestReplicas)-1]
+		slow.Net = &checkpointDroppingNet{Net: slow.Net}
+
+		// Count the notifications about the slow node.
+		// Flag nodes as slow early, as only a few checkpoints become stable during the test.
+		notified := make([]int64, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+			replica.ISSConfig.MaxCheckpointLag = 1
+			replica.OnNode = func(node *mirbft.Node) {
+				notifications := node.Events()
+				go func() {
+					for notification := range notifications {
+						if nodeSlow := notification.GetNodeSlow(); nodeSlow != nil && t.NodeID(nodeSlow.NodeId) == slow.Id {
+							atomic.AddInt64(&notified[i], 1)
+						}
+					}
+				}()
+			}
+		}
+
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), func() bool {
+			for i, replica := range deployment.TestReplicas {
+				if replica != slow && atomic.LoadInt64(&notified[i]) == 0 {
+					return false
+				}
+			}
+			return true
+		}))
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+			// All other nodes flag the slow node and only the slow node.
+			status := finalStatus.Status.Protocol.GetIss()
+			Expect(status).NotTo(BeNil())
+			Expect(status.NodeLags).To(HaveLen(testConfig.NumReplicas - 1))
+			for _, nodeLag := range status.NodeLags {
+				Expect(t.NodeID(nodeLag.NodeId)).NotTo(Equal(deployment.TestReplicas[i].Id))
+				if deployment.TestReplicas[i] == slow || t.NodeID(nodeLag.NodeId) != slow.Id {
+					Expect(nodeLag.Slow).To(BeFalse())
+					continue
+				}
+				Expect(nodeLag.Slow).To(BeTrue())
+				Expect(nodeLag.CheckpointSn).To(BeZero())
+				Expect(nodeLag.Lag).To(BeNumerically(">", 0))
+			}
+			if deployment.TestReplicas[i] == slow {
+				Expect(atomic.LoadInt64(&notified[i])).To(BeZero())
+			} else {
+				Expect(atomic.LoadInt64(&notified[i])).To(Equal(int64(1)))
+			}
+		}
+	})
+})
+
+// checkpointDroppingNet is a Net module wrapper that drops all sent Checkpoint messages.
+type checkpointDroppingNet struct {
+	modules.Net
+}
+
+func (cn *checkpointDroppingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if msg.GetIss().GetCheckpoint() != nil {
+		return nil
+	}
+	return cn.Net.Send(dest, msg)
+}
@@ -23,6 +23,17 @@ type LeaderSelectionPolicy interface {
 	Suspect(e t.EpochNr, node t.NodeID)
 }
 
+// A ReconfigurableLeaderPolicy is a LeaderSelectionPolicy that can adapt to changes of the membership.
+// When the membership changes (see isspb.ConfigChange), ISS announces the new membership to the policy
+// using Reconfigure, before querying the leaders of the first epoch with the new membership.
+// A LeaderSelectionPolicy not implementing this interface keeps selecting leaders from the initial membership.
+type ReconfigurableLeaderPolicy interface {
+	LeaderSelectionPolicy
+
+	// Reconfigure announces that, starting from epoch e, the membership consists of the given nodes.
+	Reconfigure(e t.EpochNr, membership []t.NodeID)
+}
+
 // The SimpleLeaderPolicy is a trivial leader selection policy.
 // It must be initialized with a set of node IDs and always returns that full set as leaders,
 // regardless of which nodes have been suspected. In other words, each node is leader each epoch with this policy.
@@ -40,3 +51,8 @@ func (simple *SimpleLeaderPolicy) Leaders(e t.EpochNr) []t.NodeID {
 func (simple *SimpleLeaderPolicy) Suspect(e t.EpochNr, node t.NodeID) {
 	// Do nothing.
 }
+
+// Reconfigure replaces the membership of the SimpleLeaderPolicy, all nodes of which are leaders from epoch e on.
+func (simple *SimpleLeaderPolicy) Reconfigure(e t.EpochNr, membership []t.NodeID) {
+	simple.Membership = membership
+}
@@ -0,0 +1,295 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// LeaderStats holds statistics about the content of the batches committed in the segments led by one leader.
+// These statistics help detecting subtle misbehavior of a leader that never triggers a timeout,
+// e.g., a leader that proposes empty batches while requests are pending,
+// or a leader that censors (or delays) the requests of some clients.
+type LeaderStats struct {
+
+	// Number of committed batches proposed by the leader.
+	Batches uint64
+
+	// Number of committed empty batches proposed by the leader.
+	EmptyBatches uint64
+
+	// Number of requests in the committed batches proposed by the leader.
+	Requests uint64
+
+	// Sum of the ages (in ticks of the logical clock) of the requests at the time they were committed.
+	// The age of a request is measured locally, from the moment the request became ready at this node.
+	// Only requests that became ready at this node before being committed are counted (see AgedRequests).
+	TotalRequestAge uint64
+
+	// Number of requests included in TotalRequestAge.
+	AgedRequests uint64
+
+	// Number of requests included by the leader, indexed by the ID of the client that submitted the request.
+	ClientRequests map[t.ClientID]uint64
+}
+
+// newLeaderStats returns new, empty leader statistics.
+func newLeaderStats() *LeaderStats {
+	return &LeaderStats{
+		ClientRequests: make(map[t.ClientID]uint64),
+	}
+}
+
+// EmptyBatchRate returns the fraction of the leader's committed batches that were empty.
+func (ls *LeaderStats) EmptyBatchRate() float64 {
+	if ls.Batches == 0 {
+		return 0
+	}
+	return float64(ls.EmptyBatches) / float64(ls.Batches)
+}
+
+// MeanRequestAge returns the average age (in ticks) of the leader's requests at the time they were committed.
+func (ls *LeaderStats) MeanRequestAge() float64 {
+	if ls.AgedRequests == 0 {
+		return 0
+	}
+	return float64(ls.TotalRequestAge) / float64(ls.AgedRequests)
+}
+
+// InclusionSkew compares the share of each client's requests among the requests included by the leader
+// to the share of the same client's requests among all the requests in reference.
+// It returns the largest amount by which any client is under-represented in the leader's batches
+// (a value between 0 and 1). A high skew indicates that the leader might be censoring some client.
+// If reference contains no requests, InclusionSkew returns 0.
+func (ls *LeaderStats) InclusionSkew(reference *LeaderStats) float64 {
+	if reference.Requests == 0 {
+		return 0
+	}
+
+	skew := 0.0
+	for clientID, refCount := range reference.ClientRequests {
+		refShare := float64(refCount) / float64(reference.Requests)
+		ownShare := 0.0
+		if ls.Requests > 0 {
+			ownShare = float64(ls.ClientRequests[clientID]) / float64(ls.Requests)
+		}
+		if refShare-ownShare > skew {
+			skew = refShare - ownShare
+		}
+	}
+	return skew
+}
+
+// add updates the statistics with a committed batch. ages contains the known ages of the batch's requests.
+func (ls *LeaderStats) add(batch *requestpb.Batch, ages []uint64) {
+	ls.Batches++
+	if len(batch.Requests) == 0 {
+		ls.EmptyBatches++
+	}
+	for _, reqRef := range batch.Requests {
+		ls.Requests++
+		ls.ClientRequests[t.ClientID(reqRef.ClientId)]++
+	}
+	for _, age := range ages {
+		ls.TotalRequestAge += age
+		ls.AgedRequests++
+	}
+}
+
+// copy returns a deep copy of the statistics.
+func (ls *LeaderStats) copy() LeaderStats {
+	c := *ls
+	c.ClientRequests = make(map[t.ClientID]uint64, len(ls.ClientRequests))
+	for clientID, count := range ls.ClientRequests {
+		c.ClientRequests[clientID] = count
+	}
+	return c
+}
+
+// LeaderStatsThresholds defines when a leader is suspected based on the statistics of its committed batches.
+// The statistics are evaluated at the end of each epoch, only considering the batches committed in that epoch.
+// A threshold set to zero is disabled.
+type LeaderStatsThresholds struct {
+
+	// Minimal number of batches a leader must have committed in an epoch for the thresholds to be applied.
+	// This prevents suspecting leaders based on too small samples.
+	MinBatches uint64
+
+	// A leader whose fraction of empty batches exceeds MaxEmptyBatchRate is suspected,
+	// unless all leaders of the epoch exceed the threshold (which indicates low load rather than misbehavior).
+	// Must be between 0 and 1.
+	MaxEmptyBatchRate float64
+
+	// A leader whose InclusionSkew (computed with respect to all requests committed in the epoch)
+	// exceeds MaxInclusionSkew is suspected.
+	// Must be between 0 and 1.
+	MaxInclusionSkew float64
+
+	// If the MeanRequestAge (in ticks) of a leader's requests exceeds MaxMeanRequestAge, a warning is logged.
+	// As request ages are observed locally and thus differ across nodes,
+	// exceeding this threshold does not lead to suspecting the leader
+	// (the LeaderSelectionPolicy must be updated consistently at all nodes).
+	MaxMeanRequestAge float64
+}
+
+// leaderStatsTracker collects the LeaderStats of all leaders.
+type leaderStatsTracker struct {
+
+	// Current value of the local logical clock, incremented on each tick.
+	now uint64
+
+	// For each request that is ready but not yet committed, the logical time at which the request became ready,
+	// indexed by the string representation of the request reference.
+	readySince map[string]uint64
+
+	// Statistics of the batches committed in the current epoch.
+	epochStats map[t.NodeID]*LeaderStats
+
+	// Protects totalStats, which are accessed concurrently by the LeaderStats method of ISS.
+	totalStatsLock sync.Mutex
+
+	// Statistics of all batches committed since the start of the node.
+	totalStats map[t.NodeID]*LeaderStats
+}
+
+// newLeaderStatsTracker returns a new leaderStatsTracker with empty statistics.
+func newLeaderStatsTracker() *leaderStatsTracker {
+	return &leaderStatsTracker{
+		now:        0,
+		readySince: make(map[string]uint64),
+		epochStats: make(map[t.NodeID]*LeaderStats),
+		totalStats: make(map[t.NodeID]*LeaderStats),
+	}
+}
+
+// tick advances the logical clock used to measure request ages.
+func (lst *leaderStatsTracker) tick() {
+	lst.now++
+}
+
+// requestReady records the time at which a request became ready.
+func (lst *leaderStatsTracker) requestReady(reqRef *requestpb.RequestRef) {
+	key := reqStrKey(reqRef)
+	if _, ok := lst.readySince[key]; !ok {
+		lst.readySince[key] = lst.now
+	}
+}
+
+// requestAge is the age (in ticks) of a request at the time it was committed.
+type requestAge struct {
+	reqRef *requestpb.RequestRef
+	age    uint64
+}
+
+// batchCommitted updates the statistics of leader with a newly committed batch.
+// It returns the ages of the batch's requests that became ready locally.
+func (lst *leaderStatsTracker) batchCommitted(leader t.NodeID, batch *requestpb.Batch) []requestAge {
+
+	// Compute the ages of all the requests that became ready locally.
+	reqAges := make([]requestAge, 0, len(batch.Requests))
+	ages := make([]uint64, 0, len(batch.Requests))
+	for _, reqRef := range batch.Requests {
+		key := reqStrKey(reqRef)
+		if readySince, ok := lst.readySince[key]; ok {
+			reqAges = append(reqAges, requestAge{reqRef: reqRef, age: lst.now - readySince})
+			ages = append(ages, lst.now-readySince)
+			delete(lst.readySince, key)
+		}
+	}
+
+	// Update both the epoch and the total statistics.
+	if _, ok := lst.epochStats[leader]; !ok {
+		lst.epochStats[leader] = newLeaderStats()
+	}
+	lst.epochStats[leader].add(batch, ages)
+
+	lst.totalStatsLock.Lock()
+	defer lst.totalStatsLock.Unlock()
+	if _, ok := lst.totalStats[leader]; !ok {
+		lst.totalStats[leader] = newLeaderStats()
+	}
+	lst.totalStats[leader].add(batch, ages)
+
+	return reqAges
+}
+
+// endEpoch evaluates the statistics of the epoch's leaders against the thresholds,
+// resets the epoch statistics, and returns the list of leaders to be suspected.
+// The leaders are processed in the given order, which must be the same at all nodes.
+// Only the statistics that are derived from the committed batches (and thus are the same at all nodes)
+// can lead to suspecting a leader. If thresholds is nil, endEpoch only resets the epoch statistics.
+func (lst *leaderStatsTracker) endEpoch(
+	leaders []t.NodeID,
+	thresholds *LeaderStatsThresholds,
+	logger logging.Logger,
+) []t.NodeID {
+	suspects := make([]t.NodeID, 0)
+	epochStats := lst.epochStats
+	lst.epochStats = make(map[t.NodeID]*LeaderStats)
+
+	if thresholds == nil {
+		return suspects
+	}
+
+	// Aggregate the statistics of all leaders to serve as a reference for the per-client inclusion skew
+	// and check whether all leaders exceed the empty batch rate threshold.
+	all := newLeaderStats()
+	allEmpty := true
+	for _, leader := range leaders {
+		if stats, ok := epochStats[leader]; ok {
+			all.Batches += stats.Batches
+			all.EmptyBatches += stats.EmptyBatches
+			all.Requests += stats.Requests
+			for clientID, count := range stats.ClientRequests {
+				all.ClientRequests[clientID] += count
+			}
+			if stats.EmptyBatchRate() <= thresholds.MaxEmptyBatchRate {
+				allEmpty = false
+			}
+		}
+	}
+
+	for _, leader := range leaders {
+		stats, ok := epochStats[leader]
+		if !ok || stats.Batches < thresholds.MinBatches {
+			continue
+		}
+
+		if thresholds.MaxMeanRequestAge > 0 && stats.MeanRequestAge() > thresholds.MaxMeanRequestAge {
+			logger.Log(logging.LevelWarn, "High request age in batches of leader.",
+				"leader", leader, "meanAge", stats.MeanRequestAge())
+		}
+
+		if thresholds.MaxEmptyBatchRate > 0 && !allEmpty && stats.EmptyBatchRate() > thresholds.MaxEmptyBatchRate {
+			logger.Log(logging.LevelWarn, "Suspecting leader due to high empty batch rate.",
+				"leader", leader, "emptyBatchRate", stats.EmptyBatchRate())
+			suspects = append(suspects, leader)
+		} else if thresholds.MaxInclusionSkew > 0 && stats.InclusionSkew(all) > thresholds.MaxInclusionSkew {
+			logger.Log(logging.LevelWarn, "Suspecting leader due to high client inclusion skew.",
+				"leader", leader, "inclusionSkew", stats.InclusionSkew(all))
+			suspects = append(suspects, leader)
+		}
+	}
+
+	return suspects
+}
+
+// snapshot returns a copy of the total statistics of all leaders.
+func (lst *leaderStatsTracker) snapshot() map[t.NodeID]LeaderStats {
+	lst.totalStatsLock.Lock()
+	defer lst.totalStatsLock.Unlock()
+
+	stats := make(map[t.NodeID]LeaderStats, len(lst.totalStats))
+	for leader, leaderStats := range lst.totalStats {
+		stats[leader] = leaderStats.copy()
+	}
+	return stats
+}
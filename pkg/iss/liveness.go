@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// livenessTracker detects other nodes that stopped responding, based on the messages received from them.
+// Each node periodically sends a Heartbeat message to all other nodes (see Config.HeartbeatPeriod),
+// such that even a node with nothing else to send is heard from regularly.
+// Any message received from a node (not only a Heartbeat) counts as a sign of life.
+// The tracker counts, for each other node, the ticks elapsed since the last message from that node was received.
+// A node from which nothing has been received for at least the configured timeout is considered unresponsive.
+type livenessTracker struct {
+
+	// Number of ticks between two consecutive rounds of Heartbeat messages sent by this node.
+	heartbeatPeriod int
+
+	// Number of ticks without any message received from a node, after which the node is considered unresponsive.
+	timeout int
+
+	// Ticks remaining until the next round of Heartbeat messages is sent.
+	ticksUntilHeartbeat int
+
+	// For each other node, the number of ticks elapsed since the last message from that node was received.
+	silence map[t.NodeID]int
+
+	// Logger for outputting the warnings about unresponsive nodes.
+	logger logging.Logger
+}
+
+// newLivenessTracker returns a new livenessTracker for the given (other) nodes.
+// Initially, the nodes are considered to have been heard from just now.
+func newLivenessTracker(nodes []t.NodeID, heartbeatPeriod int, timeout int, logger logging.Logger) *livenessTracker {
+	lt := &livenessTracker{
+		heartbeatPeriod:     heartbeatPeriod,
+		timeout:             timeout,
+		ticksUntilHeartbeat: heartbeatPeriod,
+		silence:             make(map[t.NodeID]int, len(nodes)),
+		logger:              logger,
+	}
+	for _, nodeID := range nodes {
+		lt.silence[nodeID] = 0
+	}
+	return lt
+}
+
+// Add starts tracking the liveness of a node (e.g. one that has been added to the membership),
+// considering it to have been heard from just now. Add has no effect if the node is already being tracked.
+func (lt *livenessTracker) Add(nodeID t.NodeID) {
+	if _, ok := lt.silence[nodeID]; !ok {
+		lt.silence[nodeID] = 0
+	}
+}
+
+// Remove stops tracking the liveness of a node (e.g. one that has been removed from the membership).
+func (lt *livenessTracker) Remove(nodeID t.NodeID) {
+	delete(lt.silence, nodeID)
+}
+
+// Tick advances the tracker's clock by one tick.
+// It returns true if a round of Heartbeat messages is due to be sent.
+func (lt *livenessTracker) Tick() bool {
+	// Iterate over the nodes in their order (and not over the map) for the warnings to be logged deterministically.
+	nodeIDs := make([]t.NodeID, 0, len(lt.silence))
+	for nodeID := range lt.silence {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sortNodeIDs(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		lt.silence[nodeID]++
+		if lt.silence[nodeID] == lt.timeout {
+			lt.logger.Log(logging.LevelWarn, "Node unresponsive.", "nodeID", nodeID, "ticks", lt.timeout)
+		}
+	}
+
+	lt.ticksUntilHeartbeat--
+	if lt.ticksUntilHeartbeat <= 0 {
+		lt.ticksUntilHeartbeat = lt.heartbeatPeriod
+		return true
+	}
+	return false
+}
+
+// Observe records that a message has been received from node source.
+func (lt *livenessTracker) Observe(source t.NodeID) {
+	if silence, ok := lt.silence[source]; ok {
+		if silence >= lt.timeout {
+			lt.logger.Log(logging.LevelInfo, "Node responsive again.", "nodeID", source)
+		}
+		lt.silence[source] = 0
+	}
+}
+
+// Unresponsive returns true if nothing has been received from node nodeID for at least the configured timeout.
+func (lt *livenessTracker) Unresponsive(nodeID t.NodeID) bool {
+	silence, ok := lt.silence[nodeID]
+	return ok && silence >= lt.timeout
+}
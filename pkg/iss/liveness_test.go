@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Heartbeat test", func() {
+
+	It("suspects a leader that stopped sending messages", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Enable heartbeats at all replicas and record the suspicions reported to their leader selection policies.
+		policies := make([]*suspectRecordingPolicy, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			policies[i] = &suspectRecordingPolicy{
+				SimpleLeaderPolicy: iss.SimpleLeaderPolicy{Membership: replica.Membership},
+				suspects:           make(map[t.NodeID]struct{}),
+			}
+			replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+			replica.ISSConfig.LeaderPolicy = policies[i]
+			replica.ISSConfig.HeartbeatPeriod = 2
+			replica.ISSConfig.SuspectTimeout = 10
+		}
+
+		// The last replica (a leader, as all replicas are leaders with the SimpleLeaderPolicy) never sends anything.
+		muted := deployment.TestReplicas[len(deployment.TestReplicas)-1]
+		muted.Net = &mutedNet{Net: muted.Net}
+
+		deployment.RunUntil(func() bool {
+			for i, replica := range deployment.TestReplicas {
+				if _, ok := policies[i].Suspects()[muted.Id]; replica != muted && !ok {
+					return false
+				}
+			}
+			return true
+		})
+
+		// All other replicas suspect the muted replica and only the muted one.
+		for i, replica := range deployment.TestReplicas {
+			if replica != muted {
+				Expect(policies[i].Suspects()).To(Equal(map[t.NodeID]struct{}{muted.Id: {}}))
+			}
+		}
+	})
+})
+
+// mutedNet is a Net module wrapper that drops all sent messages, but still receives messages.
+type mutedNet struct {
+	modules.Net
+}
+
+func (mn *mutedNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	return nil
+}
+
+// suspectRecordingPolicy is a SimpleLeaderPolicy that records the suspected nodes.
+type suspectRecordingPolicy struct {
+	iss.SimpleLeaderPolicy
+	lock     sync.Mutex
+	suspects map[t.NodeID]struct{}
+}
+
+func (sp *suspectRecordingPolicy) Suspect(e t.EpochNr, node t.NodeID) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+	sp.suspects[node] = struct{}{}
+}
+
+func (sp *suspectRecordingPolicy) Suspects() map[t.NodeID]struct{} {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+	suspects := make(map[t.NodeID]struct{}, len(sp.suspects))
+	for id := range sp.suspects {
+		suspects[id] = struct{}{}
+	}
+	return suspects
+}
@@ -0,0 +1,254 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The node addition test starts a deployment with four nodes and a fifth node joining the running network.
+// A configuration request adding the fifth node is submitted to the four nodes.
+// Once the configuration change takes effect, the fifth node must obtain the state from the others
+// and participate in the protocol, leading one of the epoch's segments and applying all requests.
+var _ = Describe("Node addition test", func() {
+
+	It("adds a node to a running network", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     5,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The network initially consists of the first four nodes and the fifth one joins it.
+		// All nodes accept requests from the configuration client.
+		initialMembership := []t.NodeID{0, 1, 2, 3}
+		joiningNode := t.NodeID(4)
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			if replica.Id == joiningNode {
+				replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+				replica.ISSConfig.NumBuckets = len(initialMembership)
+				replica.ISSConfig.Join = true
+			} else {
+				replica.ISSConfig = iss.DefaultConfig(initialMembership)
+			}
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// Create the configuration request adding the fifth node.
+		configRequest := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			AddNodes: []uint64{joiningNode.Pb()},
+		})
+
+		// Submit the configuration request to the initial members after the network started
+		// and wait until the joining node participates and all nodes applied all requests.
+		submitErrs := make([]error, len(initialMembership))
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas[:len(initialMembership)], 0), func() {
+			for i := range initialMembership {
+				submitErrs[i] = deployment.TestReplicas[i].Node().SubmitRequest(
+					context.Background(),
+					t.ConfigClientID,
+					0,
+					configRequest.Data,
+					configRequest.Authenticator,
+				)
+			}
+			Eventually(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests+1), func() bool {
+				info := deployment.TestReplicas[0].Node().EpochInfo()
+				for _, leader := range info.Leaders {
+					if leader == joiningNode {
+						return true
+					}
+				}
+				return false
+			}), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i := range initialMembership {
+			Expect(submitErrs[i]).NotTo(HaveOccurred())
+		}
+		for _, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		// The joining node started from a checkpoint and became one of the leaders.
+		Expect(nodes[joiningNode].EpochInfo()).NotTo(BeNil())
+		Expect(nodes[joiningNode].EpochInfo().Epoch).To(BeNumerically(">", 0))
+		Expect(nodes[0].EpochInfo().Leaders).To(ContainElement(joiningNode))
+
+		// All nodes applied the fake requests and the configuration request.
+		for _, replica := range deployment.TestReplicas {
+			Expect(replica.App.RequestsProcessed).To(Equal(uint64(testConfig.NumFakeRequests + 1)))
+		}
+	})
+})
+
+// The node removal test starts a deployment with five nodes and removes one of them using a configuration request.
+// A second configuration request, which would remove another node and thus reduce the number of tolerated faults,
+// must be rejected. The remaining nodes must continue without the removed node.
+var _ = Describe("Node removal test", func() {
+
+	It("removes a node from a running network", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     5,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// All nodes accept requests from the configuration client.
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// Create the configuration requests removing the fifth node and, subsequently, the fourth one.
+		membership := deployment.TestReplicas[0].Membership
+		removedNode := t.NodeID(4)
+		requests := []*requestpb.Request{
+			deploytest.ConfigRequest(membership, clientIDs, 0, &isspb.ConfigChange{RemoveNodes: []uint64{removedNode.Pb()}}),
+			deploytest.ConfigRequest(membership, clientIDs, 1, &isspb.ConfigChange{RemoveNodes: []uint64{3}}),
+		}
+
+		// Submit the configuration requests to all nodes after the network started
+		// and wait until the remaining nodes applied all requests.
+		submitErrs := make([]error, 0)
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			for _, request := range requests {
+				for _, replica := range deployment.TestReplicas {
+					submitErrs = append(submitErrs, replica.Node().SubmitRequest(
+						context.Background(),
+						t.ConfigClientID,
+						t.ReqNo(request.ReqNo),
+						request.Data,
+						request.Authenticator,
+					))
+				}
+			}
+			remaining := deployment.TestReplicas[:removedNode]
+			Eventually(deploytest.AllOf(deploytest.Processed(remaining, testConfig.NumFakeRequests+len(requests)), func() bool {
+				for _, replica := range remaining {
+					if len(replica.Node().EpochInfo().Leaders) != len(remaining) {
+						return false
+					}
+				}
+				return true
+			}), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for _, err := range submitErrs {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		for _, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		// The remaining nodes continued with all of them, but without the removed node, being leaders.
+		for _, node := range nodes[:removedNode] {
+			Expect(node.EpochInfo().Leaders).To(ConsistOf(t.NodeID(0), t.NodeID(1), t.NodeID(2), t.NodeID(3)))
+		}
+
+		// The remaining nodes applied the fake requests and both configuration requests.
+		for _, replica := range deployment.TestReplicas[:removedNode] {
+			Expect(replica.App.RequestsProcessed).To(Equal(uint64(testConfig.NumFakeRequests + len(requests))))
+		}
+	})
+})
+
+// The learner test runs a deployment in which one node is a learner.
+// The learner must apply the same log as the members without being one of them,
+// and become a leader after being promoted to the membership by a configuration request.
+var _ = Describe("Learner test", func() {
+
+	It("lets a learner follow the log and promotes it to the membership", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     5,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The first four nodes are members, the fifth one is a learner.
+		// All nodes accept requests from the configuration client.
+		members := []t.NodeID{0, 1, 2, 3}
+		learner := t.NodeID(4)
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.ClientIDs = clientIDs
+			replica.ISSConfig = iss.DefaultConfig(members)
+			replica.ISSConfig.NumBuckets = len(members)
+			replica.ISSConfig.Learners = []t.NodeID{learner}
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+			}
+		}
+
+		// After the network started, check that the learner is not a leader and promote it.
+		request := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			AddNodes: []uint64{learner.Pb()},
+		})
+		// Then wait until all nodes applied all requests and the promoted learner leads segments.
+		var learnerLeaders []t.NodeID
+		var submitErrs []error
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			learnerLeaders = deployment.TestReplicas[learner].Node().EpochInfo().Leaders
+			submitErrs = deploytest.SubmitToAll(deployment.TestReplicas, request)
+			Eventually(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests+1), func() bool {
+				for _, replica := range deployment.TestReplicas {
+					if len(replica.Node().EpochInfo().Leaders) != len(members)+1 {
+						return false
+					}
+				}
+				return true
+			}), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, finalStatus := range finalStatuses {
+			Expect(submitErrs[i]).NotTo(HaveOccurred())
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		// While being a learner, the node followed the epochs of the members without leading any segment.
+		Expect(learnerLeaders).To(Equal(members))
+
+		// The learner applied the same requests as the members and, once promoted, became one of the leaders.
+		for _, replica := range deployment.TestReplicas {
+			Expect(replica.App.RequestsProcessed).To(Equal(uint64(testConfig.NumFakeRequests + 1)))
+		}
+		Expect(nodes[0].EpochInfo().Leaders).To(ContainElement(learner))
+		Expect(nodes[learner].EpochInfo().Leaders).To(Equal(nodes[0].EpochInfo().Leaders))
+	})
+})
@@ -8,6 +8,7 @@ package iss
 
 import (
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger-labs/mirbft/pkg/events"
 	"github.com/hyperledger-labs/mirbft/pkg/logging"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
@@ -47,6 +48,10 @@ type pbftProposalState struct {
 	// Counts the logical clock ticks since last proposal.
 	// Used to detect when config.MaxProposeDelay has elapsed.
 	ticksSinceProposal int
+
+	// Flag indicating that the leader is handing off the rest of the segment (see SBStepDown).
+	// No more batches are requested from ISS and all remaining sequence numbers are filled with empty batches.
+	handingOff bool
 }
 
 // pbftSlot tracks the state of the agreement protocol for one sequence number,
@@ -161,6 +166,10 @@ func (pbft *pbftInstance) ApplyEvent(event *isspb.SBInstanceEvent) *events.Event
 		return pbft.applyPbftPersistPreprepare(e.PbftPersistPreprepare)
 	case *isspb.SBInstanceEvent_MessageReceived:
 		return pbft.applyMessageReceived(e.MessageReceived.Msg, t.NodeID(e.MessageReceived.From))
+	case *isspb.SBInstanceEvent_StepDown:
+		return pbft.applyStepDown()
+	case *isspb.SBInstanceEvent_LocalParams:
+		return pbft.applyLocalParams(e.LocalParams)
 	default:
 		// Panic if message type is not known.
 		panic(fmt.Sprintf("unknown PBFT SB instance event type: %T", event.Type))
@@ -176,8 +185,55 @@ func (pbft *pbftInstance) Segment() *segment {
 // This functionality is meant mostly for debugging and is *not* meant to provide an interface for
 // serializing and deserializing the whole protocol state.
 func (pbft *pbftInstance) Status() *isspb.SBStatus {
-	// TODO: Return actual status here, not just a stub.
-	return &isspb.SBStatus{Leader: pbft.segment.Leader.Pb()}
+	s := &isspb.SBStatus{Leader: pbft.segment.Leader.Pb()}
+	for _, bID := range pbft.segment.BucketIDs {
+		s.BucketIds = append(s.BucketIds, uint64(bID))
+	}
+	for _, sn := range pbft.segment.SeqNrs {
+		s.SeqNrs = append(s.SeqNrs, sn.Pb())
+		if pbft.slots[sn].Preprepare != nil {
+			s.Proposed = append(s.Proposed, sn.Pb())
+		}
+	}
+	return s
+}
+
+// pbftDump is the representation of the state of a PBFT orderer, as returned by DumpState.
+type pbftDump struct {
+	Leader             t.NodeID
+	SeqNrs             []t.SeqNr
+	BucketIDs          []int
+	ProposalsMade      int
+	NumPendingRequests t.NumRequests
+	BatchRequested     bool
+	TicksSinceProposal int
+	HandingOff         bool
+
+	// Sequence numbers of the segment for which a preprepare message has been received,
+	// with the number of requests in the proposed batch.
+	Preprepared map[t.SeqNr]int
+}
+
+// DumpState returns a representation of the complete state of the orderer that can be serialized
+// using encoding/json. It is only meant for debugging.
+func (pbft *pbftInstance) DumpState() interface{} {
+	dump := &pbftDump{
+		Leader:             pbft.segment.Leader,
+		SeqNrs:             append([]t.SeqNr{}, pbft.segment.SeqNrs...),
+		BucketIDs:          append([]int{}, pbft.segment.BucketIDs...),
+		ProposalsMade:      pbft.proposal.proposalsMade,
+		NumPendingRequests: pbft.proposal.numPendingRequests,
+		BatchRequested:     pbft.proposal.batchRequested,
+		TicksSinceProposal: pbft.proposal.ticksSinceProposal,
+		HandingOff:         pbft.proposal.handingOff,
+		Preprepared:        make(map[t.SeqNr]int),
+	}
+	for sn, slot := range pbft.slots {
+		if slot.Preprepare != nil {
+			dump.Preprepared[sn] = len(slot.Preprepare.Batch.Requests)
+		}
+	}
+	return dump
 }
 
 // ============================================================
@@ -188,14 +244,29 @@ func (pbft *pbftInstance) Status() *isspb.SBStatus {
 // The Init event is expected to be the first event applied to the orderer,
 // except for events read from the WAL at startup, which are expected to be applied even before the Init event.
 func (pbft *pbftInstance) applyInit() *events.EventList {
+	eventsOut := &events.EventList{}
+
+	// Re-send the own proposals recovered from the WAL.
+	// A proposal is persisted before the corresponding preprepare message is sent,
+	// so the node might have crashed before sending it.
+	// Note that the own preprepare message is not sent to this node, as it is already preprepared.
+	if pbft.ownID == pbft.segment.Leader {
+		for _, sn := range pbft.segment.SeqNrs {
+			if slot := pbft.slots[sn]; slot.Preprepare != nil {
+				eventsOut.PushBack(pbft.eventService.SendMessage(
+					PbftPreprepareMessage(sn, slot.Preprepare.Batch, slot.Preprepare.Handoff),
+					removeNodeID(pbft.recipients(), pbft.ownID),
+				))
+			}
+		}
+	}
 
 	// Make a proposal if one can be made right away.
 	if pbft.canPropose() {
-		return pbft.requestNewBatch()
-	} else {
-		return &events.EventList{}
+		eventsOut.PushBackList(pbft.requestNewBatch())
 	}
 
+	return eventsOut
 }
 
 // applyTick applies a single tick of the logical clock to the protocol state machine.
@@ -234,7 +305,35 @@ func (pbft *pbftInstance) applyBatchReady(batch *isspb.SBBatchReady) *events.Eve
 	pbft.proposal.batchRequested = false
 
 	// Propose the received batch and update the number of pending requests that remain after the batch was created.
-	return pbft.propose(batch.Batch).PushBackList(pbft.applyPendingRequests(t.NumRequests(batch.PendingRequestsLeft)))
+	eventsOut := pbft.propose(batch.Batch, false).PushBackList(
+		pbft.applyPendingRequests(t.NumRequests(batch.PendingRequestsLeft)),
+	)
+
+	// If the leader started handing off the segment while waiting for the batch, finish the handoff now.
+	if pbft.proposal.handingOff {
+		eventsOut.PushBackList(pbft.handOff())
+	}
+	return eventsOut
+}
+
+// applyLocalParams adjusts the parameters of the orderer that only affect this node (see SBLocalParams).
+// As the configuration is shared with ISS, the orderer replaces it by an adjusted copy.
+// The adjusted maximal propose delay is applied starting with the next tick.
+func (pbft *pbftInstance) applyLocalParams(params *isspb.SBLocalParams) *events.EventList {
+	config := *pbft.config
+	config.MaxProposeDelay = int(params.MaxProposeDelay)
+	pbft.config = &config
+	return &events.EventList{}
+}
+
+// applyStepDown makes the leader hand off the rest of its segment (see handOff).
+// If this node is not the leader of the segment, applyStepDown has no effect.
+func (pbft *pbftInstance) applyStepDown() *events.EventList {
+	if pbft.ownID != pbft.segment.Leader {
+		return &events.EventList{}
+	}
+	pbft.proposal.handingOff = true
+	return pbft.handOff()
 }
 
 // applyRequestsReady processes the notification from ISS
@@ -244,15 +343,50 @@ func (pbft *pbftInstance) applyRequestsReady(requestsReady *isspb.SBRequestsRead
 	return (&events.EventList{}).PushBack(pbft.eventService.SBEvent(SBDeliverEvent(
 		t.SeqNr(requestsReady.Sn),
 		slot.Preprepare.Batch,
+		slot.Preprepare.Handoff,
 	)))
 }
 
 // applyPbftPersistPreprepare processes a preprepare message loaded from the WAL.
+// Since only the leader persists preprepare messages (when proposing), the recovered preprepare
+// is always an own proposal. It is restored in the corresponding slot and the proposal is accounted for,
+// such that the same sequence number is never proposed again (possibly with a different batch).
 func (pbft *pbftInstance) applyPbftPersistPreprepare(pp *isspbftpb.PersistPreprepare) *events.EventList {
 
-	// TODO: Implement this.
-	pbft.logger.Log(logging.LevelDebug, "Loading WAL event: Preprepare (unimplemented)")
-	return &events.EventList{}
+	// Convenience variable
+	sn := t.SeqNr(pp.Preprepare.Sn)
+
+	pbft.logger.Log(logging.LevelDebug, "Loading WAL event: Preprepare", "sn", sn)
+
+	// Look up the slot concerned by the recovered preprepare.
+	slot, ok := pbft.slots[sn]
+	if !ok {
+		pbft.logger.Log(logging.LevelWarn, "Ignoring recovered Preprepare with invalid sequence number.", "sn", sn)
+		return &events.EventList{}
+	}
+
+	// Ignore duplicates, the same proposal might have been persisted more than once.
+	if slot.Preprepare != nil {
+		return &events.EventList{}
+	}
+
+	// Restore the preprepare message.
+	slot.Preprepare = pp.Preprepare
+
+	// Account for the recovered proposal.
+	// As proposals are made in the order of the segment's sequence numbers,
+	// all sequence numbers up to the recovered one are considered proposed.
+	for i, segSn := range pbft.segment.SeqNrs {
+		if segSn == sn && i >= pbft.proposal.proposalsMade {
+			pbft.proposal.proposalsMade = i + 1
+		}
+	}
+
+	// Wait for all the requests to be available locally, as with a received preprepare message.
+	return (&events.EventList{}).PushBack(pbft.eventService.SBEvent(SBWaitForRequestsEvent(
+		sn,
+		pp.Preprepare.Batch.Requests,
+	)))
 }
 
 // applyMessageReceived handles a received PBFT protocol message.
@@ -267,6 +401,27 @@ func (pbft *pbftInstance) applyMessageReceived(message *isspb.SBInstanceMessage,
 	}
 }
 
+// validatePbftMessage returns an error if a received PBFT protocol message is of an unknown type
+// or lacks any of the fields the PBFT implementation relies on, and nil otherwise.
+// It is used for validating messages before they are applied (see ISS.ValidateMessage).
+func validatePbftMessage(message *isspb.SBInstanceMessage) error {
+	switch msg := message.Type.(type) {
+	case *isspb.SBInstanceMessage_PbftPreprepare:
+		if msg.PbftPreprepare == nil || msg.PbftPreprepare.Batch == nil {
+			return fmt.Errorf("preprepare without batch")
+		}
+		if !validRequestRefs(msg.PbftPreprepare.Batch.Requests) {
+			return fmt.Errorf("preprepare with invalid request references")
+		}
+		if msg.PbftPreprepare.Handoff && len(msg.PbftPreprepare.Batch.Requests) > 0 {
+			return fmt.Errorf("handoff preprepare with non-empty batch")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown ISS PBFT message type: %T", message.Type)
+	}
+}
+
 // applyMsgPreprepare applies a received preprepare message.
 // It performs the necessary checks and, if successful,
 // requests a confirmation from ISS that all contained requests have been received and authenticated.
@@ -287,9 +442,24 @@ func (pbft *pbftInstance) applyMsgPreprepare(preprepare *isspbftpb.Preprepare, f
 		return &events.EventList{}
 	}
 
+	// Ignore Preprepare message with a batch larger than allowed.
+	// This prevents a faulty leader from making the other nodes wait for an unbounded number of requests.
+	// The value 0 for config.MaxBatchSize means no limit on batch size.
+	if pbft.config.MaxBatchSize != 0 && t.NumRequests(len(preprepare.Batch.Requests)) > pbft.config.MaxBatchSize {
+		pbft.logger.Log(logging.LevelWarn, "Ignoring Preprepare message with oversized batch.",
+			"sn", sn, "from", from, "batchSize", len(preprepare.Batch.Requests), "max", pbft.config.MaxBatchSize)
+		return &events.EventList{}
+	}
+
 	// Ignore the received message
 	// if a valid preprepare message with the same sequence number already has been received.
+	// If the leader sent a different preprepare for the same sequence number, report the leader's equivocation.
 	if slot.Preprepare != nil {
+		if from == pbft.segment.Leader && !proto.Equal(slot.Preprepare, preprepare) {
+			pbft.logger.Log(logging.LevelWarn, "Leader equivocated. Ignoring conflicting Preprepare message.",
+				"sn", sn, "from", from)
+			return (&events.EventList{}).PushBack(pbft.eventService.Equivocation(from, sn, "pbft_preprepare"))
+		}
 		pbft.logger.Log(logging.LevelWarn, "Ignoring Preprepare message. Already preprepared.",
 			"sn", sn, "from", from)
 		return &events.EventList{}
@@ -313,6 +483,9 @@ func (pbft *pbftInstance) applyMsgPreprepare(preprepare *isspbftpb.Preprepare, f
 func (pbft *pbftInstance) canPropose() bool {
 	return pbft.ownID == pbft.segment.Leader && // Only the leader can propose
 
+		// A leader handing off the segment does not propose any more batches of requests.
+		!pbft.proposal.handingOff &&
+
 		// A new batch must not have been requested (if it has, we are already in the process of proposing).
 		!pbft.proposal.batchRequested &&
 
@@ -339,10 +512,36 @@ func (pbft *pbftInstance) requestNewBatch() *events.EventList {
 	return (&events.EventList{}).PushBack(pbft.eventService.SBEvent(SBCutBatchEvent(pbft.config.MaxBatchSize)))
 }
 
+// handOff proposes an empty batch for each remaining sequence number of the segment,
+// marking the proposals as a handoff, so that the segment finishes without waiting for the leader any more.
+// If a batch has already been requested from ISS, the handoff is postponed until the batch is proposed,
+// as the batch's sequence number is already reserved for it.
+func (pbft *pbftInstance) handOff() *events.EventList {
+	eventsOut := &events.EventList{}
+	if pbft.proposal.batchRequested {
+		return eventsOut
+	}
+
+	if pbft.proposal.proposalsMade < len(pbft.segment.SeqNrs) {
+		pbft.logger.Log(logging.LevelInfo, "Handing off segment.",
+			"remaining", len(pbft.segment.SeqNrs)-pbft.proposal.proposalsMade)
+	}
+	for pbft.proposal.proposalsMade < len(pbft.segment.SeqNrs) {
+		eventsOut.PushBackList(pbft.propose(&requestpb.Batch{}, true))
+	}
+	return eventsOut
+}
+
+// recipients returns the nodes the proposals are sent to, i.e., the segment's membership and learners.
+func (pbft *pbftInstance) recipients() []t.NodeID {
+	return append(append([]t.NodeID{}, pbft.segment.Membership...), pbft.segment.Learners...)
+}
+
 // propose proposes a new request batch by sending a preprepare message.
+// The handoff flag marks (empty) batches proposed when handing off the rest of the segment.
 // propose assumes that the state of the PBFT orderer allows sending a new proposal
 // and does not perform any checks in this regard.
-func (pbft *pbftInstance) propose(batch *requestpb.Batch) *events.EventList {
+func (pbft *pbftInstance) propose(batch *requestpb.Batch, handoff bool) *events.EventList {
 
 	// Update proposal counter and reset proposal timer.
 	sn := pbft.segment.SeqNrs[pbft.proposal.proposalsMade]
@@ -354,10 +553,10 @@ func (pbft *pbftInstance) propose(batch *requestpb.Batch) *events.EventList {
 		"sn", sn, "batchSize", len(batch.Requests))
 
 	// Create a preprepare message and an event for sending it.
-	msgSendEvent := pbft.eventService.SendMessage(PbftPreprepareMessage(sn, batch), pbft.segment.Membership)
+	msgSendEvent := pbft.eventService.SendMessage(PbftPreprepareMessage(sn, batch, handoff), pbft.recipients())
 
 	// Create a WAL entry and an event to persist it.
-	persistEvent := pbft.eventService.WALAppend(PbftPersistPreprepare(sn, batch))
+	persistEvent := pbft.eventService.WALAppend(PbftPersistPreprepare(sn, batch, handoff))
 
 	// First the preprepare needs to be persisted to the WAL, and only then it can be sent to the network.
 	persistEvent.Next = []*eventpb.Event{msgSendEvent}
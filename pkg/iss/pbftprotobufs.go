@@ -27,12 +27,13 @@ import (
 // Events
 // ============================================================
 
-func PbftPersistPreprepare(sn t.SeqNr, batch *requestpb.Batch) *isspb.SBInstanceEvent {
+func PbftPersistPreprepare(sn t.SeqNr, batch *requestpb.Batch, handoff bool) *isspb.SBInstanceEvent {
 	return &isspb.SBInstanceEvent{Type: &isspb.SBInstanceEvent_PbftPersistPreprepare{
 		PbftPersistPreprepare: &isspbftpb.PersistPreprepare{
 			Preprepare: &isspbftpb.Preprepare{
-				Sn:    sn.Pb(),
-				Batch: batch,
+				Sn:      sn.Pb(),
+				Batch:   batch,
+				Handoff: handoff,
 			},
 		},
 	}}
@@ -42,11 +43,12 @@ func PbftPersistPreprepare(sn t.SeqNr, batch *requestpb.Batch) *isspb.SBInstance
 // Messages
 // ============================================================
 
-func PbftPreprepareMessage(sn t.SeqNr, batch *requestpb.Batch) *isspb.SBInstanceMessage {
+func PbftPreprepareMessage(sn t.SeqNr, batch *requestpb.Batch, handoff bool) *isspb.SBInstanceMessage {
 	return &isspb.SBInstanceMessage{Type: &isspb.SBInstanceMessage_PbftPreprepare{
 		PbftPreprepare: &isspbftpb.Preprepare{
-			Sn:    sn.Pb(),
-			Batch: batch,
+			Sn:      sn.Pb(),
+			Batch:   batch,
+			Handoff: handoff,
 		},
 	}}
 }
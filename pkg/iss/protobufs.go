@@ -34,11 +34,8 @@ func Event(event *isspb.ISSEvent) *eventpb.Event {
 	return &eventpb.Event{Type: &eventpb.Event_Iss{Iss: event}}
 }
 
-func PersistCheckpointEvent(sn t.SeqNr, appSnapshot []byte) *eventpb.Event {
-	return Event(&isspb.ISSEvent{Type: &isspb.ISSEvent_PersistCheckpoint{PersistCheckpoint: &isspb.PersistCheckpoint{
-		Sn:          sn.Pb(),
-		AppSnapshot: appSnapshot,
-	}}})
+func PersistCheckpointEvent(checkpoint *isspb.PersistCheckpoint) *eventpb.Event {
+	return Event(&isspb.ISSEvent{Type: &isspb.ISSEvent_PersistCheckpoint{PersistCheckpoint: checkpoint}})
 }
 
 func PersistStableCheckpointEvent(stableCheckpoint *isspb.StableCheckpoint) *eventpb.Event {
@@ -49,6 +46,10 @@ func PersistStableCheckpointEvent(stableCheckpoint *isspb.StableCheckpoint) *eve
 	}})
 }
 
+func PersistConfigRequestEvent(configRequest *isspb.PersistConfigRequest) *eventpb.Event {
+	return Event(&isspb.ISSEvent{Type: &isspb.ISSEvent_PersistConfigRequest{PersistConfigRequest: configRequest}})
+}
+
 func StableCheckpointEvent(stableCheckpoint *isspb.StableCheckpoint) *eventpb.Event {
 	return Event(&isspb.ISSEvent{Type: &isspb.ISSEvent_StableCheckpoint{
 		StableCheckpoint: stableCheckpoint,
@@ -74,15 +75,26 @@ func SBTickEvent() *isspb.SBInstanceEvent {
 	return &isspb.SBInstanceEvent{Type: &isspb.SBInstanceEvent_Tick{Tick: &isspb.SBTick{}}}
 }
 
-func SBDeliverEvent(sn t.SeqNr, batch *requestpb.Batch) *isspb.SBInstanceEvent {
+func SBDeliverEvent(sn t.SeqNr, batch *requestpb.Batch, handoff bool) *isspb.SBInstanceEvent {
 	return &isspb.SBInstanceEvent{Type: &isspb.SBInstanceEvent_Deliver{
 		Deliver: &isspb.SBDeliver{
-			Sn:    sn.Pb(),
-			Batch: batch,
+			Sn:      sn.Pb(),
+			Batch:   batch,
+			Handoff: handoff,
 		},
 	}}
 }
 
+func SBStepDownEvent() *isspb.SBInstanceEvent {
+	return &isspb.SBInstanceEvent{Type: &isspb.SBInstanceEvent_StepDown{StepDown: &isspb.SBStepDown{}}}
+}
+
+func SBLocalParamsEvent(maxProposeDelay uint64) *isspb.SBInstanceEvent {
+	return &isspb.SBInstanceEvent{Type: &isspb.SBInstanceEvent_LocalParams{LocalParams: &isspb.SBLocalParams{
+		MaxProposeDelay: maxProposeDelay,
+	}}}
+}
+
 func SBMessageReceivedEvent(message *isspb.SBInstanceMessage, from t.NodeID) *isspb.SBInstanceEvent {
 	return &isspb.SBInstanceEvent{Type: &isspb.SBInstanceEvent_MessageReceived{
 		MessageReceived: &isspb.SBMessageReceived{
@@ -136,21 +148,41 @@ func Message(msg *isspb.ISSMessage) *messagepb.Message {
 	return &messagepb.Message{Type: &messagepb.Message_Iss{Iss: msg}}
 }
 
-func SBMessage(epoch t.EpochNr, instance t.SBInstanceID, msg *isspb.SBInstanceMessage) *messagepb.Message {
+func SBMessage(
+	epoch t.EpochNr,
+	instance t.SBInstanceID,
+	configEpoch t.EpochNr,
+	msg *isspb.SBInstanceMessage,
+) *messagepb.Message {
 	return Message(&isspb.ISSMessage{Type: &isspb.ISSMessage_Sb{Sb: &isspb.SBMessage{
-		Epoch:    epoch.Pb(),
-		Instance: instance.Pb(),
-		Msg:      msg,
+		Epoch:       epoch.Pb(),
+		Instance:    instance.Pb(),
+		Msg:         msg,
+		ConfigEpoch: configEpoch.Pb(),
 	}}})
 }
 
-func CheckpointMessage(epoch t.EpochNr, sn t.SeqNr) *messagepb.Message {
+func CheckpointMessage(epoch t.EpochNr, sn t.SeqNr, configEpoch t.EpochNr, timestamp int64) *messagepb.Message {
 	return Message(&isspb.ISSMessage{Type: &isspb.ISSMessage_Checkpoint{Checkpoint: &isspb.Checkpoint{
-		Epoch: epoch.Pb(),
-		Sn:    sn.Pb(),
+		Epoch:       epoch.Pb(),
+		Sn:          sn.Pb(),
+		Timestamp:   timestamp,
+		ConfigEpoch: configEpoch.Pb(),
 	}}})
 }
 
+func HeartbeatMessage() *messagepb.Message {
+	return Message(&isspb.ISSMessage{Type: &isspb.ISSMessage_Heartbeat{Heartbeat: &isspb.Heartbeat{}}})
+}
+
+func FetchRequestsMessage(requests []*requestpb.RequestRef) *messagepb.Message {
+	return Message(&isspb.ISSMessage{Type: &isspb.ISSMessage_FetchRequests{
+		FetchRequests: &isspb.FetchRequests{
+			Requests: requests,
+		},
+	}})
+}
+
 func RetransmitRequestsMessage(requests []*requestpb.RequestRef) *messagepb.Message {
 	return Message(&isspb.ISSMessage{Type: &isspb.ISSMessage_RetransmitRequests{
 		RetransmitRequests: &isspb.RetransmitRequests{
@@ -158,3 +190,16 @@ func RetransmitRequestsMessage(requests []*requestpb.RequestRef) *messagepb.Mess
 		},
 	}})
 }
+
+func StateRequestMessage() *messagepb.Message {
+	return Message(&isspb.ISSMessage{Type: &isspb.ISSMessage_StateRequest{StateRequest: &isspb.StateRequest{}}})
+}
+
+func StateTransferMessage(epoch t.EpochNr, checkpoint *isspb.PersistCheckpoint) *messagepb.Message {
+	return Message(&isspb.ISSMessage{Type: &isspb.ISSMessage_StateTransfer{
+		StateTransfer: &isspb.StateTransfer{
+			Epoch:      epoch.Pb(),
+			Checkpoint: checkpoint,
+		},
+	}})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// quorums holds the fault-tolerance parameters derived from the size of a membership.
+// All quorum sizes used by ISS are derived here, and nowhere else, from the number of nodes.
+// ISS keeps the quorums of its current membership (see ISS.quorums) and replaces them, together with the configuration,
+// when a configuration change takes effect (see setConfig).
+// Sub-protocols that may outlive a membership change (like a checkpoint) keep their own copy,
+// derived from the membership executing them.
+// A quorums value is never modified after its creation.
+type quorums struct {
+
+	// Number of nodes in the membership.
+	n int
+
+	// Maximal number of faulty nodes the membership tolerates.
+	// Assuming n = 3f + 1, it is f.
+	f int
+
+	// Minimal number of nodes any two sets of which intersect in at least f + 1 nodes,
+	// and thus in at least one correct node ((n + f) / 2 + 1, which is 2f + 1 if n = 3f + 1).
+	// Used when a decision must be confirmed by the system, e.g., for a checkpoint to become stable.
+	intersection int
+
+	// Minimal number of nodes among which there is at least one correct node (f + 1).
+	// Used when a value must be vouched for by a correct node, e.g., a state transferred to a joining node.
+	someCorrect int
+}
+
+// newQuorums returns the quorums of the given membership.
+func newQuorums(membership []t.NodeID) quorums {
+	n := len(membership)
+	f := (n - 1) / 3
+	return quorums{
+		n:            n,
+		f:            f,
+		intersection: (n+f)/2 + 1,
+		someCorrect:  f + 1,
+	}
+}
+
+// membershipQuorum is a membership together with its intersection quorum, as exported by ISS.Membership.
+type membershipQuorum struct {
+	members []t.NodeID
+	quorum  int
+}
+
+// setQuorums replaces the quorums of ISS by the ones of the given membership
+// and atomically publishes the membership and its intersection quorum for ISS.Membership.
+func (iss *ISS) setQuorums(membership []t.NodeID) {
+	iss.quorums = newQuorums(membership)
+	iss.membershipQuorum.Store(membershipQuorum{
+		members: append([]t.NodeID{}, membership...),
+		quorum:  iss.quorums.intersection,
+	})
+}
@@ -0,0 +1,66 @@
+package iss
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Quorums", func() {
+
+	entries := make([]TableEntry, 0, 10)
+	for n := 1; n <= 10; n++ {
+		entries = append(entries, Entry(fmt.Sprintf("n = %d", n), n))
+	}
+
+	DescribeTable("of a membership of n nodes",
+		func(n int) {
+			membership := make([]t.NodeID, n)
+			for i := range membership {
+				membership[i] = t.NodeID(i)
+			}
+			q := newQuorums(membership)
+
+			Expect(q.n).To(Equal(n))
+			Expect(3*q.f + 1).To(BeNumerically("<=", n))
+
+			// Any two quorums intersect in at least f + 1 nodes, and thus in at least one correct node.
+			Expect(2*q.intersection - n).To(BeNumerically(">=", q.f+1))
+
+			// A quorum can be formed by the correct nodes alone.
+			Expect(q.intersection).To(BeNumerically("<=", n-q.f))
+
+			Expect(q.someCorrect).To(Equal(q.f + 1))
+		},
+		entries...,
+	)
+})
+
+var _ = Describe("Membership", func() {
+
+	It("exports the intersection quorum of the current membership", func() {
+		iss, err := New(0, DefaultConfig(nodeIDs(4)), logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+
+		members, quorum := iss.Membership()
+		Expect(members).To(Equal(nodeIDs(4)))
+		Expect(quorum).To(Equal(3))
+
+		// Adding a node only changes the exported membership and quorum once the change takes effect.
+		iss.applyConfigChange(&isspb.ConfigChange{AddNodes: []uint64{4}})
+		_, quorum = iss.Membership()
+		Expect(quorum).To(Equal(3))
+
+		iss.activateConfig(1)
+		members, quorum = iss.Membership()
+		Expect(members).To(Equal(nodeIDs(5)))
+		Expect(quorum).To(Equal(newQuorums(nodeIDs(5)).intersection))
+		Expect(quorum).To(Equal(4))
+	})
+})
@@ -0,0 +1,779 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package iss
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/messagebuffer"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// This file implements the changes of the ISS configuration at runtime (reconfiguration)
+// and the joining of new nodes to a running network.
+//
+// A configuration change is submitted as a request of the reserved configuration client (t.ConfigClientID),
+// the payload of which is a serialized isspb.ConfigChange. The request is ordered like any other request.
+// When it is committed, the change is registered as pending and takes effect at the start of the next epoch,
+// which is also the point in the total order of the next checkpoint. Thus, all nodes switch to the new configuration
+// at the same point and the checkpoint the new epoch starts with is the first one that includes the new configuration.
+// Besides the membership, a change can set the number of buckets and the segment length
+// (which, together with the number of leaders, determines the length of an epoch and thus the checkpoint interval).
+// The quorum sizes used by the orderers and by the checkpoint sub-protocol are derived from the membership
+// of their respective epochs and thus change along with it.
+//
+// A node added to the membership starts with Config.Join set. Instead of starting from the initial state,
+// it requests the state of the system at the latest stable checkpoint from the other nodes,
+// which respond once the node is part of the membership of the epoch starting at their latest stable checkpoint.
+// As soon as the node receives someCorrect (f+1) matching responses, it starts from the received checkpoint.
+//
+// A change can also add and remove learners (see Config.Learners), which follow the log without being members.
+// A learner can join a running network in the same way. Adding a learner to the membership promotes it:
+// having followed the log, it participates in the protocol right from the epoch in which the change takes effect.
+
+// registerConfigRequest remembers the content of a configuration request that became ready,
+// to be interpreted when the request is committed, and persists it in the WAL.
+func (iss *ISS) registerConfigRequest(reqRef *requestpb.RequestRef, data []byte) *events.EventList {
+	reqKey := reqStrKey(reqRef)
+	if _, ok := iss.configRequests[reqKey]; ok {
+		return &events.EventList{}
+	}
+
+	configRequest := &isspb.PersistConfigRequest{RequestRef: reqRef, Data: data}
+	iss.configRequests[reqKey] = configRequest
+	return (&events.EventList{}).PushBack(events.WALAppend(
+		PersistConfigRequestEvent(configRequest),
+		t.WALRetIndex(iss.epoch),
+	))
+}
+
+// applyPersistConfigRequest applies the content of a configuration request loaded from the WAL at startup.
+func (iss *ISS) applyPersistConfigRequest(configRequest *isspb.PersistConfigRequest) *events.EventList {
+	iss.configRequests[reqStrKey(configRequest.RequestRef)] = configRequest
+	return &events.EventList{}
+}
+
+// persistConfigRequests appends the contents of the configuration requests that have not yet been committed
+// to the WAL again, with the retention index of epoch e. This is necessary, as the WAL entries
+// of the preceding epochs are eventually truncated, while the requests might only be committed later.
+// Configuration requests that have already been committed (e.g. ones loaded from the WAL
+// that are encompassed by the recovered stable checkpoint) are discarded.
+func (iss *ISS) persistConfigRequests(e t.EpochNr) *events.EventList {
+	eventsOut := &events.EventList{}
+
+	// Iterate over the requests in a deterministic order.
+	reqKeys := make([]string, 0, len(iss.configRequests))
+	for reqKey := range iss.configRequests {
+		reqKeys = append(reqKeys, reqKey)
+	}
+	sort.Strings(reqKeys)
+
+	for _, reqKey := range reqKeys {
+		configRequest := iss.configRequests[reqKey]
+		reqNo := t.ReqNo(configRequest.RequestRef.ReqNo)
+		if _, committed := iss.clientWatermarks.committed[t.ConfigClientID][reqNo]; committed ||
+			reqNo < iss.clientWatermarks.watermark(t.ConfigClientID) {
+			delete(iss.configRequests, reqKey)
+			continue
+		}
+		eventsOut.PushBack(events.WALAppend(PersistConfigRequestEvent(configRequest), t.WALRetIndex(e)))
+	}
+
+	return eventsOut
+}
+
+// applyConfigRequests interprets the configuration requests contained in a batch that is being delivered.
+// The content of all of them must be known, as a batch is only delivered
+// after the missing contents have been fetched (see registerMissingPayloads).
+// Skipping a configuration request would make this node's configuration diverge from that of the other nodes.
+func (iss *ISS) applyConfigRequests(batch *requestpb.Batch) {
+	for _, reqRef := range batch.Requests {
+		if t.ClientID(reqRef.ClientId) != t.ConfigClientID {
+			continue
+		}
+
+		reqKey := reqStrKey(reqRef)
+		configRequest, ok := iss.configRequests[reqKey]
+		delete(iss.configRequests, reqKey)
+		if !ok {
+			panic(fmt.Sprintf("delivering configuration request %d with unknown content", reqRef.ReqNo))
+		}
+
+		change := &isspb.ConfigChange{}
+		if err := proto.Unmarshal(configRequest.Data, change); err != nil {
+			iss.epochLogger.Log(logging.LevelWarn, "Ignoring malformed configuration request.",
+				"reqNo", reqRef.ReqNo, "error", err)
+			continue
+		}
+		iss.applyConfigChange(change)
+	}
+}
+
+// applyConfigChange registers a committed configuration change to take effect at the start of the next epoch.
+// If the configuration resulting from the change is not safe to use (see checkConfigChange),
+// the whole change is rejected. As all nodes apply the same changes in the same order, they all reject the same ones.
+func (iss *ISS) applyConfigChange(change *isspb.ConfigChange) {
+
+	// Start from the configuration resulting from the changes already committed in this epoch, if any.
+	// The new configuration is computed on a copy, since the change might be rejected.
+	config := *iss.config
+	if iss.pendingConfig != nil {
+		config = *iss.pendingConfig
+	}
+	membership := append([]t.NodeID{}, config.Membership...)
+
+	// Add the new nodes to the membership.
+	added := make([]t.NodeID, 0, len(change.AddNodes))
+	for _, nodeID := range t.NodeIDSlice(change.AddNodes) {
+		if _, ok := membershipSet(membership)[nodeID]; ok {
+			iss.epochLogger.Log(logging.LevelWarn, "Ignoring addition of node already in the membership.", "nodeID", nodeID)
+			continue
+		}
+		membership = append(membership, nodeID)
+		added = append(added, nodeID)
+	}
+
+	// Remove nodes from the membership.
+	removed := make([]t.NodeID, 0, len(change.RemoveNodes))
+	for _, nodeID := range t.NodeIDSlice(change.RemoveNodes) {
+		if _, ok := membershipSet(membership)[nodeID]; !ok {
+			iss.epochLogger.Log(logging.LevelWarn, "Ignoring removal of node not in the membership.", "nodeID", nodeID)
+			continue
+		}
+		membership = removeNodeID(membership, nodeID)
+		removed = append(removed, nodeID)
+	}
+	config.Membership = membership
+
+	// Add and remove learners. The learners added to the membership are promoted, i.e., stop being learners.
+	learners := make([]t.NodeID, 0, len(config.Learners)+len(change.AddLearners))
+	for _, nodeID := range append(append([]t.NodeID{}, config.Learners...), t.NodeIDSlice(change.AddLearners)...) {
+		if _, ok := membershipSet(learners)[nodeID]; ok {
+			continue
+		}
+		if _, ok := membershipSet(membership)[nodeID]; ok {
+			continue
+		}
+		learners = append(learners, nodeID)
+	}
+	for _, nodeID := range t.NodeIDSlice(change.RemoveLearners) {
+		learners = removeNodeID(learners, nodeID)
+	}
+	config.Learners = learners
+
+	// Change the other parameters.
+	if change.NumBuckets != 0 {
+		config.NumBuckets = int(change.NumBuckets)
+	}
+	if change.SegmentLength != 0 {
+		config.SegmentLength = int(change.SegmentLength)
+	}
+
+	// Add and remove clients, starting from the client changes already committed in this epoch, if any.
+	clientKeys := make(map[t.ClientID]*isspb.ClientKey, len(iss.pendingClientKeys))
+	for clientID, clientKey := range iss.pendingClientKeys {
+		clientKeys[clientID] = clientKey
+	}
+	for _, clientKey := range change.AddClients {
+		clientKeys[t.ClientID(clientKey.ClientId)] = clientKey
+	}
+	for _, clientID := range change.RemoveClients {
+		clientKeys[t.ClientID(clientID)] = &isspb.ClientKey{ClientId: clientID}
+	}
+
+	// Rotate node keys, starting from the rotations already committed in this epoch, if any.
+	nodeKeys := make(map[t.NodeID][]byte, len(iss.pendingNodeKeys))
+	for nodeID, pubKey := range iss.pendingNodeKeys {
+		nodeKeys[nodeID] = pubKey
+	}
+	for _, nodeKey := range change.RotateNodes {
+		nodeKeys[t.NodeID(nodeKey.NodeId)] = nodeKey.PubKey
+	}
+
+	err := checkClientChange(change)
+	if err == nil {
+		err = checkClientQuotas(&config, iss.clientKeys, clientKeys)
+	}
+	if err == nil {
+		err = checkNodeKeyChange(change, membership)
+	}
+	if err == nil {
+		err = checkConfigChange(iss.config, &config)
+	}
+	if err != nil {
+		iss.epochLogger.Log(logging.LevelWarn, "Rejecting configuration change.", "error", err)
+		return
+	}
+
+	for _, nodeID := range added {
+		iss.epochLogger.Log(logging.LevelInfo, "Adding node to the membership.", "nodeID", nodeID, "epoch", iss.epoch+1)
+	}
+	for _, nodeID := range removed {
+		iss.epochLogger.Log(logging.LevelInfo, "Removing node from the membership.", "nodeID", nodeID, "epoch", iss.epoch+1)
+	}
+	for _, clientKey := range change.AddClients {
+		iss.epochLogger.Log(logging.LevelInfo, "Adding client.", "clientID", clientKey.ClientId, "epoch", iss.epoch+1)
+	}
+	for _, clientID := range change.RemoveClients {
+		iss.epochLogger.Log(logging.LevelInfo, "Removing client.", "clientID", clientID, "epoch", iss.epoch+1)
+	}
+	for _, nodeKey := range change.RotateNodes {
+		iss.epochLogger.Log(logging.LevelInfo, "Rotating node key.", "nodeID", nodeKey.NodeId, "epoch", iss.epoch+1)
+	}
+	for _, nodeID := range change.AddLearners {
+		iss.epochLogger.Log(logging.LevelInfo, "Adding learner.", "nodeID", nodeID, "epoch", iss.epoch+1)
+	}
+	for _, nodeID := range change.RemoveLearners {
+		iss.epochLogger.Log(logging.LevelInfo, "Removing learner.", "nodeID", nodeID, "epoch", iss.epoch+1)
+	}
+	iss.pendingConfig = &config
+	if len(change.AddClients) > 0 || len(change.RemoveClients) > 0 {
+		iss.pendingClientKeys = clientKeys
+	}
+	if len(change.RotateNodes) > 0 {
+		iss.pendingNodeKeys = nodeKeys
+	}
+
+	// Accept messages from the added nodes right away, as the new configuration might become active
+	// at other nodes (and the added nodes might start participating) before it becomes active at this node.
+	// The removed nodes still participate until the new configuration becomes active.
+	iss.members.Store(membershipSet(append(append([]t.NodeID{}, iss.config.Membership...), membership...)))
+	iss.learners.Store(membershipSet(append(append([]t.NodeID{}, iss.config.Learners...), learners...)))
+	iss.trackNodes(membership)
+}
+
+// checkClientChange returns an error if the client changes of a configuration change are invalid,
+// i.e., if a client is added without a public key or if the configuration client is removed.
+func checkClientChange(change *isspb.ConfigChange) error {
+	for _, clientKey := range change.AddClients {
+		if len(clientKey.PubKey) == 0 {
+			return fmt.Errorf("no public key for added client %d", clientKey.ClientId)
+		}
+	}
+	for _, clientID := range change.RemoveClients {
+		if t.ClientID(clientID) == t.ConfigClientID {
+			return fmt.Errorf("configuration client cannot be removed")
+		}
+	}
+	return nil
+}
+
+// checkClientQuotas returns an error if the clients registered after applying the client changes
+// to the clients registered so far (both indexed by client ID, in the representation of ISS.clientKeys)
+// would exceed the quotas of the configuration (see Config.MaxClients and Config.MaxClientWindows).
+func checkClientQuotas(config *Config, registered map[t.ClientID]*isspb.ClientKey, changes map[t.ClientID]*isspb.ClientKey) error {
+	numClients, windows := 0, uint64(0)
+	count := func(clientKey *isspb.ClientKey) error {
+		if len(clientKey.PubKey) == 0 {
+			return nil
+		}
+		if config.MaxClientWindows > 0 && clientKey.Window == 0 {
+			return fmt.Errorf("no window for client %d", clientKey.ClientId)
+		}
+		numClients++
+		windows += clientKey.Window
+		return nil
+	}
+
+	for clientID, clientKey := range registered {
+		if _, ok := changes[clientID]; !ok {
+			if err := count(clientKey); err != nil {
+				return err
+			}
+		}
+	}
+	for _, clientKey := range changes {
+		if err := count(clientKey); err != nil {
+			return err
+		}
+	}
+
+	if config.MaxClients > 0 && numClients > config.MaxClients {
+		return fmt.Errorf("%d clients would exceed the quota of %d", numClients, config.MaxClients)
+	}
+	if config.MaxClientWindows > 0 && windows > uint64(config.MaxClientWindows) {
+		return fmt.Errorf("aggregate client window of %d would exceed the quota of %d", windows, config.MaxClientWindows)
+	}
+	return nil
+}
+
+// checkNodeKeyChange returns an error if the node key rotations of a configuration change are invalid,
+// i.e., if a key is rotated without a new public key or if the node is not in the resulting membership.
+func checkNodeKeyChange(change *isspb.ConfigChange, membership []t.NodeID) error {
+	for _, nodeKey := range change.RotateNodes {
+		if len(nodeKey.PubKey) == 0 {
+			return fmt.Errorf("no public key for rotated key of node %d", nodeKey.NodeId)
+		}
+		if _, ok := membershipSet(membership)[t.NodeID(nodeKey.NodeId)]; !ok {
+			return fmt.Errorf("rotated key of node %d not in the membership", nodeKey.NodeId)
+		}
+	}
+	return nil
+}
+
+// checkConfigChange returns an error if the configuration resulting from a configuration change is not safe to use,
+// i.e., if it is not valid (see CheckConfig) or if its membership of n nodes cannot tolerate
+// as many faulty nodes f as the current one, which requires n >= 3f + 1.
+func checkConfigChange(current *Config, changed *Config) error {
+	if err := CheckConfig(changed); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if n, f := len(changed.Membership), newQuorums(current.Membership).f; n < 3*f+1 {
+		return fmt.Errorf("membership of %d nodes cannot tolerate %d faulty nodes (at least %d nodes needed)",
+			n, f, 3*f+1)
+	}
+	return nil
+}
+
+// activateConfig makes the configuration changes committed in the finished epoch take effect
+// with the epoch e, initialized next, which also becomes the version of the configuration (see ISS.configEpoch).
+// The changes of client keys and node keys are announced to the Crypto module,
+// as well as the retirement of the previous node keys whose overlap period ends with the start of epoch e.
+// activateConfig also persists the contents of the configuration requests that have not yet been committed again
+// (see persistConfigRequests).
+func (iss *ISS) activateConfig(e t.EpochNr) *events.EventList {
+	eventsOut := &events.EventList{}
+
+	// Announce the changes taking effect (if any) to the subscribers of the Node's events.
+	if iss.pendingConfig != nil || iss.pendingClientKeys != nil || iss.pendingNodeKeys != nil {
+		eventsOut.PushBack(iss.configChangedNotification(e))
+	}
+
+	if iss.pendingConfig != nil {
+		iss.setConfig(e, iss.pendingConfig)
+		iss.pendingConfig = nil
+		iss.configEpoch = e
+	}
+
+	if iss.pendingClientKeys != nil {
+		for clientID, clientKey := range iss.pendingClientKeys {
+			iss.clientKeys[clientID] = clientKey
+		}
+		eventsOut.PushBack(events.UpdateClientKeys(clientKeysPb(iss.pendingClientKeys)))
+		iss.pendingClientKeys = nil
+		iss.configEpoch = e
+	}
+
+	if iss.pendingNodeKeys != nil {
+		rotated := make([]*isspb.NodeKey, 0, len(iss.pendingNodeKeys))
+		for nodeID, pubKey := range iss.pendingNodeKeys {
+			nodeKey := &isspb.NodeKey{
+				NodeId:      nodeID.Pb(),
+				PubKey:      pubKey,
+				RetireEpoch: (e + t.EpochNr(iss.config.KeyRotationOverlap)).Pb(),
+			}
+			iss.nodeKeys[nodeID] = nodeKey
+			rotated = append(rotated, nodeKey)
+		}
+		sort.Slice(rotated, func(i, j int) bool { return rotated[i].NodeId < rotated[j].NodeId })
+		eventsOut.PushBack(events.UpdateNodeKeys(rotated))
+		iss.pendingNodeKeys = nil
+		iss.configEpoch = e
+	}
+	eventsOut.PushBackList(iss.retireNodeKeys(e))
+
+	return eventsOut.PushBackList(iss.persistConfigRequests(e))
+}
+
+// configChangedNotification returns a notification about the pending configuration changes taking effect with epoch e.
+// It must be called before the changes are applied.
+func (iss *ISS) configChangedNotification(e t.EpochNr) *eventpb.Event {
+	config := iss.config
+	if iss.pendingConfig != nil {
+		config = iss.pendingConfig
+	}
+
+	rotatedNodes := make([]t.NodeID, 0, len(iss.pendingNodeKeys))
+	for nodeID := range iss.pendingNodeKeys {
+		rotatedNodes = append(rotatedNodes, nodeID)
+	}
+	sort.Slice(rotatedNodes, func(i, j int) bool { return rotatedNodes[i] < rotatedNodes[j] })
+
+	updatedClients := make([]t.ClientID, 0, len(iss.pendingClientKeys))
+	for clientID := range iss.pendingClientKeys {
+		updatedClients = append(updatedClients, clientID)
+	}
+	sort.Slice(updatedClients, func(i, j int) bool { return updatedClients[i] < updatedClients[j] })
+
+	return events.ConfigChanged(e, config.Membership, config.Learners, rotatedNodes, updatedClients)
+}
+
+// setConfig replaces the configuration of ISS, taking effect with the epoch e, initialized next.
+// It announces the new membership to the leader selection policy (if it supports membership changes),
+// which thus stops selecting removed nodes as leaders.
+// If the number of buckets changes, the requests waiting to be proposed are moved to a new group of buckets.
+func (iss *ISS) setConfig(e t.EpochNr, config *Config) {
+	previous := iss.config
+	iss.config = config
+	iss.setQuorums(config.Membership)
+
+	// From now on, only accept messages from the new members.
+	// The quorums of the new epoch's orderers and checkpoint are derived from the new membership as well.
+	members := membershipSet(config.Membership)
+	iss.members.Store(members)
+	iss.learners.Store(membershipSet(config.Learners))
+	if _, ok := members[iss.ownID]; !ok && !iss.learner() {
+		iss.epochLogger.Log(logging.LevelInfo, "Removed from the membership, not participating any more.", "epoch", e)
+	}
+
+	iss.untrackNodes(members)
+	iss.trackNodes(config.Membership)
+
+	if policy, ok := iss.config.LeaderPolicy.(ReconfigurableLeaderPolicy); ok {
+		policy.Reconfigure(e, config.Membership)
+	} else {
+		iss.epochLogger.Log(logging.LevelWarn, "Leader selection policy does not support membership changes.", "epoch", e)
+	}
+
+	if config.NumBuckets != previous.NumBuckets {
+		iss.epochLogger.Log(logging.LevelInfo, "Changing number of buckets.", "numBuckets", config.NumBuckets, "epoch", e)
+		iss.buckets = iss.buckets.Regroup(config.NumBuckets, iss.clientLogger)
+	}
+	if config.SegmentLength != previous.SegmentLength {
+		iss.epochLogger.Log(logging.LevelInfo, "Changing segment length.", "segmentLength", config.SegmentLength, "epoch", e)
+	}
+}
+
+// trackNodes creates the per-node state for the nodes in membership that do not have it yet,
+// i.e., the buffers for messages from future epochs and the liveness tracking.
+// The capacity of the message buffers is then evenly split among all the other nodes again.
+func (iss *ISS) trackNodes(membership []t.NodeID) {
+	others := removeNodeID(membership, iss.ownID)
+	if len(others) == 0 {
+		return
+	}
+
+	for _, nodeID := range others {
+		if _, ok := iss.messageBuffers[nodeID]; !ok {
+			iss.messageBuffers[nodeID] = messagebuffer.New(
+				nodeID,
+				0, // Resized below.
+				logging.Decorate(iss.logger, "Msgbuf: ", "source", nodeID),
+			)
+		}
+		if iss.liveness != nil {
+			iss.liveness.Add(nodeID)
+		}
+	}
+
+	for _, buffer := range iss.messageBuffers {
+		buffer.Resize(iss.config.MsgBufCapacity / len(others))
+	}
+}
+
+// untrackNodes discards the per-node state (see trackNodes) of all nodes that are not in members,
+// i.e., the messages buffered from them and the tracking of their liveness.
+func (iss *ISS) untrackNodes(members map[t.NodeID]struct{}) {
+	for nodeID := range iss.messageBuffers {
+		if _, ok := members[nodeID]; ok {
+			continue
+		}
+		delete(iss.messageBuffers, nodeID)
+		if iss.liveness != nil {
+			iss.liveness.Remove(nodeID)
+		}
+	}
+}
+
+// restoreCheckpoint makes ISS start from a stable checkpoint, recovered from the WAL or obtained from other nodes.
+// The state of ISS advances directly to the beginning of the checkpoint's epoch,
+// with the configuration recorded in the checkpoint (the membership, number of buckets, and segment length),
+// skipping all sequence numbers the checkpoint encompasses.
+func (iss *ISS) restoreCheckpoint(epoch t.EpochNr, checkpoint *isspb.PersistCheckpoint) {
+
+	// Discard the orderers of the skipped epochs, as they will never deliver anything.
+	iss.orderers = make(map[t.SBInstanceID]sbInstance)
+
+	// Initialize the epoch starting at the checkpoint, such that it has the same sequence numbers and orderer IDs
+	// as at the nodes that went through all the preceding epochs.
+	iss.nextDeliveredSN = t.SeqNr(checkpoint.Sn)
+	iss.nextOrdererID = t.SBInstanceID(checkpoint.FirstInstance)
+	config := *iss.config
+	config.Membership = t.NodeIDSlice(checkpoint.Membership)
+	config.Learners = t.NodeIDSlice(checkpoint.Learners)
+	if checkpoint.NumBuckets != 0 {
+		config.NumBuckets = int(checkpoint.NumBuckets)
+	}
+	if checkpoint.SegmentLength != 0 {
+		config.SegmentLength = int(checkpoint.SegmentLength)
+	}
+	iss.setConfig(epoch, &config)
+	iss.configEpoch = t.EpochNr(checkpoint.ConfigEpoch)
+	iss.initEpoch(epoch)
+	iss.clientKeys = restoreClientKeys(checkpoint.ClientKeys)
+	iss.nodeKeys = restoreNodeKeys(checkpoint.NodeKeys)
+
+	iss.restoreCheckpointTracker(epoch, checkpoint)
+}
+
+// restoreCheckpointTracker replaces the checkpoint trackers by a single (stable) one for a restored checkpoint.
+// It also restores the client watermarks as of the checkpoint, so that requests committed before it are not proposed again.
+// It must be called after the checkpoint's epoch has been initialized.
+func (iss *ISS) restoreCheckpointTracker(epoch t.EpochNr, checkpoint *isspb.PersistCheckpoint) {
+	ct := newCheckpointTracker(iss.ownID, t.SeqNr(checkpoint.Sn))
+	ct.epoch = epoch
+	ct.membership = iss.config.Membership
+	ct.learners = iss.config.Learners
+	ct.quorums = iss.quorums
+	ct.epochConfig = iss.config
+	ct.firstInstance = iss.firstInstanceOfEpoch()
+	ct.configEpoch = iss.configEpoch
+	ct.appSnapshot = checkpoint.AppSnapshot
+	ct.clientWatermarks = checkpoint.ClientWatermarks
+	ct.clientKeys = checkpoint.ClientKeys
+	ct.nodeKeys = checkpoint.NodeKeys
+	iss.checkpoints = map[t.SeqNr]*checkpointTracker{ct.seqNr: ct}
+
+	iss.clientWatermarks = restoreClientWatermarks(checkpoint.ClientWatermarks)
+}
+
+// applyStateRequestMessage responds to a node requesting the state at the latest stable checkpoint (see Config.Join).
+// The state is only sent if the requesting node is a member (or a learner) of the epoch starting at the checkpoint,
+// i.e., if the configuration change adding the node took effect at or before the checkpoint.
+func (iss *ISS) applyStateRequestMessage(from t.NodeID) *events.EventList {
+	ct, ok := iss.checkpoints[t.SeqNr(iss.lastStableCheckpoint.Sn)]
+	if !ok || ct.appSnapshot == nil {
+		iss.epochLogger.Log(logging.LevelDebug, "No stable checkpoint to send state from.", "to", from)
+		return &events.EventList{}
+	}
+
+	recipients := append(append([]t.NodeID{}, ct.epochConfig.Membership...), ct.epochConfig.Learners...)
+	if _, ok := membershipSet(recipients)[from]; !ok {
+		iss.epochLogger.Log(logging.LevelDebug, "Not sending state to node outside the checkpoint's membership.",
+			"to", from, "sn", ct.seqNr)
+		return &events.EventList{}
+	}
+
+	return (&events.EventList{}).PushBack(events.SendMessage(
+		StateTransferMessage(ct.epoch, ct.persistCheckpoint()),
+		[]t.NodeID{from},
+	))
+}
+
+// startJoining makes the node start joining a running network (see Config.Join).
+// Until it obtains the state to start from, the node does not participate in the protocol
+// (in particular, the orderers of the initial epoch are never initialized)
+// and periodically requests the state from the other nodes.
+func (iss *ISS) startJoining() *events.EventList {
+	iss.epochLogger.Log(logging.LevelInfo, "Joining running network.")
+	iss.joining = true
+	iss.stateTransfers = make(map[t.NodeID]*isspb.StateTransfer)
+	return iss.requestState()
+}
+
+// joinTick applies a tick of the logical clock while joining, repeating the request for the state if due.
+func (iss *ISS) joinTick() *events.EventList {
+	iss.ticksUntilStateRequest--
+	if iss.ticksUntilStateRequest > 0 {
+		return &events.EventList{}
+	}
+	return iss.requestState()
+}
+
+// requestState sends a StateRequest message to all other nodes.
+func (iss *ISS) requestState() *events.EventList {
+	iss.ticksUntilStateRequest = iss.config.RequestNAckTimeout
+	return (&events.EventList{}).PushBack(events.SendMessage(
+		StateRequestMessage(),
+		removeNodeID(iss.config.Membership, iss.ownID),
+	))
+}
+
+// applyMessageWhileJoining applies a message received while joining.
+// Apart from the state to start from, only SB and Checkpoint messages are relevant.
+// They are buffered (as they belong to epochs the node did not reach yet) and applied after the state is obtained.
+func (iss *ISS) applyMessageWhileJoining(msg *isspb.ISSMessage, from t.NodeID) *events.EventList {
+	switch m := msg.Type.(type) {
+	case *isspb.ISSMessage_StateTransfer:
+		return iss.applyStateTransferMessage(m.StateTransfer, from)
+	case *isspb.ISSMessage_Sb:
+		iss.messageBuffers[from].Store(m.Sb)
+	case *isspb.ISSMessage_Checkpoint:
+		iss.messageBuffers[from].Store(m.Checkpoint)
+	default:
+		iss.epochLogger.Log(logging.LevelDebug, "Ignoring message while joining.", "type", fmt.Sprintf("%T", m), "from", from)
+	}
+	return &events.EventList{}
+}
+
+// applyStateTransferMessage applies the state at a stable checkpoint received from another node while joining.
+// The node starts from the state as soon as someCorrect (f+1) nodes sent the same state,
+// at least one of which is guaranteed to be correct.
+func (iss *ISS) applyStateTransferMessage(transfer *isspb.StateTransfer, from t.NodeID) *events.EventList {
+
+	// The state is only useful if this node is a member (or a learner) of the epoch starting at the checkpoint.
+	if _, ok := membershipSet(append(
+		t.NodeIDSlice(transfer.Checkpoint.Membership),
+		t.NodeIDSlice(transfer.Checkpoint.Learners)...,
+	))[iss.ownID]; !ok {
+		iss.epochLogger.Log(logging.LevelDebug, "Ignoring state of checkpoint not including this node.",
+			"from", from, "sn", transfer.Checkpoint.Sn)
+		return &events.EventList{}
+	}
+
+	// Only the most recent state received from each node is considered.
+	iss.stateTransfers[from] = transfer
+
+	// Count the nodes that sent the same state.
+	matching := 0
+	for _, received := range iss.stateTransfers {
+		if proto.Equal(received, transfer) {
+			matching++
+		}
+	}
+	if matching < iss.quorums.someCorrect {
+		return &events.EventList{}
+	}
+
+	return iss.installState(transfer)
+}
+
+// installState makes the joining node start from the state obtained from the other nodes
+// and participate in the protocol from the epoch starting at the state's checkpoint on.
+func (iss *ISS) installState(transfer *isspb.StateTransfer) *events.EventList {
+	eventsOut := &events.EventList{}
+
+	epoch := t.EpochNr(transfer.Epoch)
+	checkpoint := transfer.Checkpoint
+	stableCheckpoint := &isspb.StableCheckpoint{Epoch: transfer.Epoch, Sn: checkpoint.Sn}
+
+	iss.epochLogger.Log(logging.LevelInfo, "Obtained state, joined network.", "epoch", epoch, "sn", checkpoint.Sn)
+
+	// Start from the obtained checkpoint.
+	iss.joining = false
+	iss.stateTransfers = nil
+	iss.restoreCheckpoint(epoch, checkpoint)
+	iss.lastStableCheckpoint = stableCheckpoint
+
+	// Record the start from the checkpoint in the epoch history.
+	iss.epochHistory.AddCause(isspb.EpochChangeReason_RESTORED, 0)
+	iss.epochHistory.EpochStarted(iss.epoch, iss.nextDeliveredSN, iss.epochLeaders)
+
+	// Persist the checkpoint as a stable one, such that the node recovers from it (and does not join again) on restart.
+	eventsOut.PushBack(events.WALAppend(PersistCheckpointEvent(checkpoint), t.WALRetIndex(epoch)))
+	eventsOut.PushBack(events.WALAppend(PersistStableCheckpointEvent(stableCheckpoint), t.WALRetIndex(epoch)))
+
+	// Have the application restore its state from the checkpoint's snapshot, register the client and node keys
+	// changed by configuration changes, and notify about the stable checkpoint and the start of the epoch.
+	eventsOut.PushBack(events.AppRestoreState(checkpoint.AppSnapshot))
+	eventsOut.PushBackList(iss.restoreKeys())
+	eventsOut.PushBack(events.CheckpointStable(epoch, t.SeqNr(checkpoint.Sn), checkpoint.AppSnapshot))
+	eventsOut.PushBack(events.EpochStarted(iss.epoch, iss.epochLeaders, iss.bucketLeaders()))
+
+	// Start participating in the protocol.
+	eventsOut.PushBackList(iss.initOrderers())
+
+	// Add the requests that became ready while joining to the buckets
+	// (skipping the ones below the restored client watermarks).
+	joinRequests := iss.joinRequests
+	iss.joinRequests = nil
+	for _, requestReady := range joinRequests {
+		eventsOut.PushBackList(iss.applyRequestReady(requestReady))
+	}
+
+	// Apply the messages received while joining.
+	return eventsOut.PushBackList(iss.applyBufferedMessages())
+}
+
+// clientKeysPb returns the protobuf representation of client keys, in increasing order of client IDs.
+func clientKeysPb(clientKeys map[t.ClientID]*isspb.ClientKey) []*isspb.ClientKey {
+	pb := make([]*isspb.ClientKey, 0, len(clientKeys))
+	for _, clientKey := range clientKeys {
+		pb = append(pb, clientKey)
+	}
+	sort.Slice(pb, func(i, j int) bool { return pb[i].ClientId < pb[j].ClientId })
+	return pb
+}
+
+// restoreClientKeys returns the client keys represented by their protobuf representation.
+func restoreClientKeys(pb []*isspb.ClientKey) map[t.ClientID]*isspb.ClientKey {
+	clientKeys := make(map[t.ClientID]*isspb.ClientKey, len(pb))
+	for _, clientKey := range pb {
+		clientKeys[t.ClientID(clientKey.ClientId)] = clientKey
+	}
+	return clientKeys
+}
+
+// retireNodeKeys retires the previous keys of the nodes whose overlap period ends with the start of epoch e
+// (see Config.KeyRotationOverlap) and returns the corresponding event, if any.
+func (iss *ISS) retireNodeKeys(e t.EpochNr) *events.EventList {
+	retired := make([]t.NodeID, 0)
+	for nodeID, nodeKey := range iss.nodeKeys {
+		if nodeKey.RetireEpoch != 0 && t.EpochNr(nodeKey.RetireEpoch) <= e {
+			nodeKey.RetireEpoch = 0
+			retired = append(retired, nodeID)
+		}
+	}
+	if len(retired) == 0 {
+		return &events.EventList{}
+	}
+
+	sortNodeIDs(retired)
+	for _, nodeID := range retired {
+		iss.epochLogger.Log(logging.LevelInfo, "Retiring previous node key.", "nodeID", nodeID, "epoch", e)
+	}
+	return (&events.EventList{}).PushBack(events.RetireNodeKeys(retired))
+}
+
+// restoreKeys returns the events registering the client and node keys changed by configuration requests
+// with the Crypto module again, when starting from a checkpoint.
+// The previous keys of the rotated node keys are those the Crypto module has been initialized with.
+// They are retired right away, unless their overlap period still lasts (see Config.KeyRotationOverlap).
+func (iss *ISS) restoreKeys() *events.EventList {
+	eventsOut := &events.EventList{}
+	if len(iss.clientKeys) > 0 {
+		eventsOut.PushBack(events.UpdateClientKeys(clientKeysPb(iss.clientKeys)))
+	}
+	if len(iss.nodeKeys) == 0 {
+		return eventsOut
+	}
+
+	eventsOut.PushBack(events.UpdateNodeKeys(nodeKeysPb(iss.nodeKeys)))
+	retired := make([]t.NodeID, 0)
+	for nodeID, nodeKey := range iss.nodeKeys {
+		if t.EpochNr(nodeKey.RetireEpoch) <= iss.epoch {
+			nodeKey.RetireEpoch = 0
+			retired = append(retired, nodeID)
+		}
+	}
+	if len(retired) > 0 {
+		sortNodeIDs(retired)
+		eventsOut.PushBack(events.RetireNodeKeys(retired))
+	}
+	return eventsOut
+}
+
+// nodeKeysPb returns the protobuf representation of the node keys, ordered by node ID.
+// The returned messages are copies, such that they are not affected by the retirement of the previous keys.
+func nodeKeysPb(nodeKeys map[t.NodeID]*isspb.NodeKey) []*isspb.NodeKey {
+	pb := make([]*isspb.NodeKey, 0, len(nodeKeys))
+	for _, nodeKey := range nodeKeys {
+		pb = append(pb, &isspb.NodeKey{NodeId: nodeKey.NodeId, PubKey: nodeKey.PubKey, RetireEpoch: nodeKey.RetireEpoch})
+	}
+	sort.Slice(pb, func(i, j int) bool { return pb[i].NodeId < pb[j].NodeId })
+	return pb
+}
+
+// restoreNodeKeys returns the node keys represented by their protobuf representation.
+func restoreNodeKeys(pb []*isspb.NodeKey) map[t.NodeID]*isspb.NodeKey {
+	nodeKeys := make(map[t.NodeID]*isspb.NodeKey, len(pb))
+	for _, nodeKey := range pb {
+		nodeKeys[t.NodeID(nodeKey.NodeId)] = &isspb.NodeKey{
+			NodeId:      nodeKey.NodeId,
+			PubKey:      nodeKey.PubKey,
+			RetireEpoch: nodeKey.RetireEpoch,
+		}
+	}
+	return nodeKeys
+}
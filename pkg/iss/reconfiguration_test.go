@@ -0,0 +1,261 @@
+package iss
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// nodeIDs returns a membership of n nodes.
+func nodeIDs(n int) []t.NodeID {
+	membership := make([]t.NodeID, n)
+	for i := range membership {
+		membership[i] = t.NodeID(i)
+	}
+	return membership
+}
+
+var _ = Describe("checkConfigChange", func() {
+
+	DescribeTable("requires the changed membership to tolerate as many faulty nodes as the current one",
+		func(current int, changed int, valid bool) {
+			err := checkConfigChange(DefaultConfig(nodeIDs(current)), DefaultConfig(nodeIDs(changed)))
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring("cannot tolerate")))
+			}
+		},
+		Entry("removing a node from 4", 4, 3, false),
+		Entry("keeping 4 nodes", 4, 4, true),
+		Entry("adding a node to 4", 4, 5, true),
+		Entry("removing a node from 5", 5, 4, true),
+		Entry("removing a node from 7", 7, 6, false),
+		Entry("removing a node from 8", 8, 7, true),
+		Entry("growing from 1 node", 1, 2, true),
+	)
+})
+
+var _ = Describe("Configuration request delivery", func() {
+
+	var (
+		iss       *ISS
+		instance  t.SBInstanceID
+		reqRef    *requestpb.RequestRef
+		reqData   []byte
+		delivered func(eventsOut *events.EventList) bool
+	)
+
+	BeforeEach(func() {
+		var err error
+		iss, err = New(1, DefaultConfig(nodeIDs(4)), logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Find the orderer of sequence number 0, led by another node.
+		for id, orderer := range iss.orderers {
+			if orderer.Segment().SeqNrs[0] == 0 {
+				instance = id
+			}
+		}
+		Expect(iss.orderers[instance].Segment().Leader).To(Equal(t.NodeID(0)))
+
+		reqData, err = proto.Marshal(&isspb.ConfigChange{AddNodes: []uint64{4}})
+		Expect(err).NotTo(HaveOccurred())
+		reqRef = &requestpb.RequestRef{ClientId: t.ConfigClientID.Pb(), ReqNo: 0, Digest: []byte{1}}
+
+		delivered = func(eventsOut *events.EventList) bool {
+			iter := eventsOut.Iterator()
+			for event := iter.Next(); event != nil; event = iter.Next() {
+				if _, ok := event.Type.(*eventpb.Event_Deliver); ok {
+					return true
+				}
+			}
+			return false
+		}
+	})
+
+	commit := func() *events.EventList {
+		return iss.applySBInstDeliver(&isspb.SBDeliver{
+			Sn:    0,
+			Batch: &requestpb.Batch{Requests: []*requestpb.RequestRef{reqRef}},
+		}, instance)
+	}
+
+	It("delivers a batch with a configuration request of known content right away", func() {
+		iss.applyRequestReady(&eventpb.RequestReady{RequestRef: reqRef, Data: reqData})
+
+		Expect(delivered(commit())).To(BeTrue())
+		Expect(iss.nextDeliveredSN).To(Equal(t.SeqNr(1)))
+		Expect(iss.pendingConfig).NotTo(BeNil())
+		Expect(iss.pendingConfig.Membership).To(Equal(nodeIDs(5)))
+	})
+
+	It("blocks delivery until the content of a configuration request has been fetched", func() {
+		eventsOut := commit()
+		Expect(delivered(eventsOut)).To(BeFalse())
+		Expect(iss.nextDeliveredSN).To(Equal(t.SeqNr(0)))
+		Expect(iss.pendingConfig).To(BeNil())
+
+		// The content is fetched from the leader who proposed the batch.
+		var fetch *eventpb.SendMessage
+		iter := eventsOut.Iterator()
+		for event := iter.Next(); event != nil; event = iter.Next() {
+			if e, ok := event.Type.(*eventpb.Event_SendMessage); ok {
+				fetch = e.SendMessage
+			}
+		}
+		Expect(fetch).NotTo(BeNil())
+		Expect(fetch.Destinations).To(Equal([]uint64{0}))
+		Expect(fetch.Msg.Type.(*messagepb.Message_Iss).Iss.Type).To(BeAssignableToTypeOf(&isspb.ISSMessage_FetchRequests{}))
+
+		// The epoch cannot end while the batch is undelivered.
+		Expect(iss.epochFinished()).To(BeFalse())
+
+		Expect(delivered(iss.applyRequestReady(&eventpb.RequestReady{RequestRef: reqRef, Data: reqData}))).To(BeTrue())
+		Expect(iss.nextDeliveredSN).To(Equal(t.SeqNr(1)))
+		Expect(iss.pendingConfig).NotTo(BeNil())
+		Expect(iss.pendingConfig.Membership).To(Equal(nodeIDs(5)))
+	})
+})
+
+var _ = Describe("Client quotas", func() {
+
+	var iss *ISS
+
+	BeforeEach(func() {
+		config := DefaultConfig(nodeIDs(4))
+		config.MaxClients = 2
+		config.MaxClientWindows = 10
+		var err error
+		iss, err = New(0, config, logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	addClient := func(clientID t.ClientID, window uint64) *isspb.ClientKey {
+		return &isspb.ClientKey{ClientId: clientID.Pb(), PubKey: []byte{1}, Window: window}
+	}
+
+	registered := func() []t.ClientID {
+		clientIDs := make([]t.ClientID, 0)
+		for clientID, clientKey := range iss.pendingClientKeys {
+			if len(clientKey.PubKey) > 0 {
+				clientIDs = append(clientIDs, clientID)
+			}
+		}
+		return clientIDs
+	}
+
+	It("admits clients within the quotas", func() {
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(10, 4), addClient(11, 6)}})
+		Expect(registered()).To(ConsistOf(t.ClientID(10), t.ClientID(11)))
+	})
+
+	It("rejects a client exceeding the maximal number of clients", func() {
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(10, 2), addClient(11, 2)}})
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(12, 2)}})
+		Expect(registered()).To(ConsistOf(t.ClientID(10), t.ClientID(11)))
+
+		// Clients registered in previous epochs count towards the quota as well.
+		iss.activateConfig(1)
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(12, 2)}})
+		Expect(iss.pendingClientKeys).To(BeNil())
+
+		// Removing a client makes room for another one.
+		iss.applyConfigChange(&isspb.ConfigChange{
+			AddClients:    []*isspb.ClientKey{addClient(12, 2)},
+			RemoveClients: []uint64{10},
+		})
+		Expect(registered()).To(ConsistOf(t.ClientID(12)))
+	})
+
+	It("rejects a client exceeding the aggregate window", func() {
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(10, 8)}})
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(11, 3)}})
+		Expect(registered()).To(ConsistOf(t.ClientID(10)))
+	})
+
+	It("rejects a client without a window", func() {
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(10, 0)}})
+		Expect(iss.pendingClientKeys).To(BeNil())
+	})
+
+	It("ignores requests beyond the window of their client", func() {
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{addClient(10, 4)}})
+		iss.activateConfig(1)
+
+		for reqNo := t.ReqNo(0); reqNo < 6; reqNo++ {
+			iss.applyRequestReady(&eventpb.RequestReady{RequestRef: &requestpb.RequestRef{
+				ClientId: 10,
+				ReqNo:    reqNo.Pb(),
+				Digest:   []byte{1},
+			}})
+		}
+
+		for reqNo := t.ReqNo(0); reqNo < 6; reqNo++ {
+			reqRef := &requestpb.RequestRef{ClientId: 10, ReqNo: reqNo.Pb(), Digest: []byte{1}}
+			Expect(iss.buckets.RequestBucket(reqRef).Contains(reqRef)).To(Equal(reqNo < 4), "request %d", reqNo)
+		}
+	})
+})
+
+var _ = Describe("Client removal", func() {
+
+	It("ignores requests of a removed client once the removal took effect", func() {
+		iss, err := New(0, DefaultConfig(nodeIDs(4)), logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+
+		ready := func(reqNo t.ReqNo) bool {
+			reqRef := &requestpb.RequestRef{ClientId: 10, ReqNo: reqNo.Pb(), Digest: []byte{1}}
+			iss.applyRequestReady(&eventpb.RequestReady{RequestRef: reqRef})
+			return iss.buckets.RequestBucket(reqRef).Contains(reqRef)
+		}
+
+		iss.applyConfigChange(&isspb.ConfigChange{AddClients: []*isspb.ClientKey{{ClientId: 10, PubKey: []byte{1}}}})
+		iss.activateConfig(1)
+		Expect(ready(0)).To(BeTrue())
+
+		// The removal only takes effect at the start of the next epoch.
+		iss.applyConfigChange(&isspb.ConfigChange{RemoveClients: []uint64{10}})
+		Expect(ready(1)).To(BeTrue())
+
+		iss.activateConfig(2)
+		Expect(ready(2)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Membership tracking", func() {
+
+	It("knows the nodes added by a configuration change before it takes effect", func() {
+		iss, err := New(0, DefaultConfig(nodeIDs(4)), logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(iss.IsMember(3)).To(BeTrue())
+		Expect(iss.IsMember(4)).To(BeFalse())
+		Expect(iss.IsLearner(5)).To(BeFalse())
+
+		// Messages from added nodes are accepted right away, as they might start participating
+		// before the change takes effect locally.
+		iss.applyConfigChange(&isspb.ConfigChange{AddNodes: []uint64{4}, AddLearners: []uint64{5}})
+		Expect(iss.IsMember(4)).To(BeTrue())
+		Expect(iss.IsLearner(5)).To(BeTrue())
+
+		// Removed nodes are members until the change takes effect.
+		iss.activateConfig(1)
+		iss.applyConfigChange(&isspb.ConfigChange{RemoveNodes: []uint64{4}, RemoveLearners: []uint64{5}})
+		Expect(iss.IsMember(4)).To(BeTrue())
+		Expect(iss.IsLearner(5)).To(BeTrue())
+
+		iss.activateConfig(2)
+		Expect(iss.IsMember(4)).To(BeFalse())
+		Expect(iss.IsLearner(5)).To(BeFalse())
+	})
+})
@@ -34,12 +34,15 @@ import (
 type sbEventService struct {
 	epoch      t.EpochNr
 	instanceID t.SBInstanceID
+
+	// Version of the configuration the orderer's epoch uses, attached to all the orderer's messages.
+	configEpoch t.EpochNr
 }
 
 // SendMessage creates an event for sending a message that will be processed
 // by the corresponding orderer instance at each of the destination.
 func (ec *sbEventService) SendMessage(message *isspb.SBInstanceMessage, destinations []t.NodeID) *eventpb.Event {
-	return events.SendMessage(SBMessage(ec.epoch, ec.instanceID, message), destinations)
+	return events.SendMessage(SBMessage(ec.epoch, ec.instanceID, ec.configEpoch, message), destinations)
 }
 
 // WALAppend creates an event for appending an isspb.SBInstanceEvent to the WAL.
@@ -53,3 +56,9 @@ func (ec *sbEventService) WALAppend(event *isspb.SBInstanceEvent) *eventpb.Event
 func (ec *sbEventService) SBEvent(event *isspb.SBInstanceEvent) *eventpb.Event {
 	return SBEvent(ec.epoch, ec.instanceID, event)
 }
+
+// Equivocation creates a notification about node nodeID having sent conflicting messages of type messageType
+// for sequence number sn within the orderer's epoch.
+func (ec *sbEventService) Equivocation(nodeID t.NodeID, sn t.SeqNr, messageType string) *eventpb.Event {
+	return events.Equivocation(nodeID, ec.epoch, sn, messageType)
+}
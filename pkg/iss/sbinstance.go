@@ -36,6 +36,10 @@ type sbInstance interface {
 	// This functionality is meant mostly for debugging and is *not* meant to provide an interface for
 	// serializing and deserializing the whole protocol state.
 	Status() *isspb.SBStatus
+
+	// DumpState returns a representation of the complete state of the SB instance's implementation
+	// that can be serialized using encoding/json (see ISS.DumpState). It is only meant for debugging.
+	DumpState() interface{}
 }
 
 // ============================================================
@@ -64,10 +68,26 @@ func (iss *ISS) applySBInstanceEvent(event *isspb.SBInstanceEvent, instance t.SB
 // applySBInstDeliver processes the event of an SB instance delivering a request batch (or the special abort value)
 // for a sequence number. It inserts a corresponding entry to the commitLog.
 func (iss *ISS) applySBInstDeliver(deliver *isspb.SBDeliver, instance t.SBInstanceID) *events.EventList {
+	eventsOut := &events.EventList{}
+
+	// The node might be missing some of the payloads of the delivered requests
+	// (with hash-only ordering, or the content of configuration requests).
+	// This needs to be checked before the requests are removed from their buckets.
+	eventsOut.PushBackList(iss.registerMissingPayloads(t.SeqNr(deliver.Sn), deliver.Batch, iss.orderers[instance]))
 
 	// Remove the delivered requests from their respective buckets.
 	iss.removeFromBuckets(deliver.Batch.Requests)
 
+	// Update the statistics and the commit latencies of the orderer's leader.
+	// The empty batches proposed when handing off a segment are not counted, but the handoff is recorded instead.
+	if deliver.Handoff {
+		iss.handoffLeaders[iss.orderers[instance].Segment().Leader] = struct{}{}
+	} else {
+		leader := iss.orderers[instance].Segment().Leader
+		reqAges := iss.leaderStats.batchCommitted(leader, deliver.Batch)
+		iss.commitLatency.batchCommitted(leader, reqAges, iss.buckets)
+	}
+
 	// Insert a new entry to the commitLog.
 	iss.commitLog[t.SeqNr(deliver.Sn)] = &commitLogEntry{
 		Sn:    t.SeqNr(deliver.Sn),
@@ -78,7 +98,7 @@ func (iss *ISS) applySBInstDeliver(deliver *isspb.SBDeliver, instance t.SBInstan
 	// Deliver commitLog entries to the application in sequence number order.
 	// This is relevant in the case when the sequence number of the currently SB-delivered batch
 	// is the first sequence number not yet delivered to the application.
-	return iss.deliverCommitted()
+	return eventsOut.PushBackList(iss.deliverCommitted())
 }
 
 // applySBInstCutBatch processes a request by an orderer for a new request batch that the orderer will propose.
@@ -121,6 +141,12 @@ func (iss *ISS) applySBInstWaitForRequests(
 	// Get sequence number of the proposal being verified.
 	sn := t.SeqNr(waitForRequests.Sn)
 
+	// With hash-only ordering, the proposal is accepted without waiting for the requests' payloads.
+	// Missing payloads are only fetched after the proposal is committed.
+	if iss.config.HashOnlyOrdering {
+		return iss.orderers[instanceID].ApplyEvent(SBRequestsReady(sn))
+	}
+
 	// Initialize a new missingRequestInfo entry that will contain a reference to all missing requests.
 	missingReqs := &missingRequestInfo{
 		Sn:             sn,
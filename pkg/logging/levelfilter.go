@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import "sync/atomic"
+
+// LevelFilter is a Logger that only passes the messages at or above a minimal level to an underlying Logger.
+// The minimal level can be changed at any time, e.g., to temporarily enable debug output on a running node.
+// LevelFilter is safe for concurrent use, provided the underlying Logger is.
+type LevelFilter struct {
+	logger Logger
+	level  int32
+}
+
+// NewLevelFilter returns a new LevelFilter passing the messages at or above level to logger.
+func NewLevelFilter(logger Logger, level LogLevel) *LevelFilter {
+	return &LevelFilter{
+		logger: logger,
+		level:  int32(level),
+	}
+}
+
+// Log passes the message to the underlying Logger if level is at or above the minimal level.
+func (lf *LevelFilter) Log(level LogLevel, text string, args ...interface{}) {
+	if level < lf.Level() {
+		return
+	}
+	lf.logger.Log(level, text, args...)
+}
+
+// Level returns the current minimal level.
+func (lf *LevelFilter) Level() LogLevel {
+	return LogLevel(atomic.LoadInt32(&lf.level))
+}
+
+// SetLevel sets the minimal level of the messages passed to the underlying Logger.
+func (lf *LevelFilter) SetLevel(level LogLevel) {
+	atomic.StoreInt32(&lf.level, int32(level))
+}
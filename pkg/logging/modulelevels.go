@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import "sync"
+
+// ModuleLevels is a Logger that filters the messages of different subsystems (modules) by independent minimal levels,
+// such that, e.g., debug output can be enabled for a single subsystem without being flooded by the others.
+// Each module logs through its own LevelFilter (see Module), obtained by the subsystem when it is created,
+// typically using ForModule. The levels can be changed at any time, e.g., on a running node.
+// Messages logged through the ModuleLevels itself (not attributed to any module) are filtered by the default level.
+// ModuleLevels is safe for concurrent use, provided the underlying Logger is.
+type ModuleLevels struct {
+	logger Logger
+
+	// Filter for the messages not attributed to any module. Its level is also the initial level of new modules.
+	defaultFilter *LevelFilter
+
+	// The filters of the modules, indexed by module name. Protected by lock.
+	modules map[string]*LevelFilter
+
+	lock sync.Mutex
+}
+
+// NewModuleLevels returns a new ModuleLevels passing the messages to logger.
+// All modules start with the minimal level defaultLevel.
+func NewModuleLevels(logger Logger, defaultLevel LogLevel) *ModuleLevels {
+	return &ModuleLevels{
+		logger:        logger,
+		defaultFilter: NewLevelFilter(logger, defaultLevel),
+		modules:       make(map[string]*LevelFilter),
+	}
+}
+
+// Log passes a message not attributed to any module to the underlying Logger if level is at or above the default level.
+func (ml *ModuleLevels) Log(level LogLevel, text string, args ...interface{}) {
+	ml.defaultFilter.Log(level, text, args...)
+}
+
+// Module returns the Logger of the module with the given name.
+// The module is created with the current default level if it does not exist yet.
+// Adjusting the level of the returned filter is equivalent to calling SetLevel for the module.
+func (ml *ModuleLevels) Module(name string) *LevelFilter {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	filter, ok := ml.modules[name]
+	if !ok {
+		filter = NewLevelFilter(ml.logger, ml.defaultFilter.Level())
+		ml.modules[name] = filter
+	}
+	return filter
+}
+
+// SetLevel sets the minimal level of the messages logged by the module with the given name,
+// creating the module if it does not exist yet (so levels can be set before the subsystems start).
+func (ml *ModuleLevels) SetLevel(module string, level LogLevel) {
+	ml.Module(module).SetLevel(level)
+}
+
+// SetDefaultLevel sets the minimal level of the messages not attributed to any module,
+// as well as the initial level of the modules created from now on. The levels of existing modules are not affected.
+func (ml *ModuleLevels) SetDefaultLevel(level LogLevel) {
+	ml.defaultFilter.SetLevel(level)
+}
+
+// DefaultLevel returns the current default level.
+func (ml *ModuleLevels) DefaultLevel() LogLevel {
+	return ml.defaultFilter.Level()
+}
+
+// Levels returns the current minimal levels of all the modules, indexed by module name.
+func (ml *ModuleLevels) Levels() map[string]LogLevel {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	levels := make(map[string]LogLevel, len(ml.modules))
+	for name, filter := range ml.modules {
+		levels[name] = filter.Level()
+	}
+	return levels
+}
+
+// ForModule returns the Logger a subsystem called module should use.
+// If logger is a ModuleLevels (possibly wrapped by Decorate), this is the (equally decorated) logger of the module,
+// otherwise it is logger itself.
+// This way, subsystems can be given a single Logger, regardless of whether per-module levels are used.
+func ForModule(logger Logger, module string) Logger {
+	switch l := logger.(type) {
+	case *ModuleLevels:
+		return l.Module(module)
+	case *decoratedLogger:
+		if inner := ForModule(l.logger, module); inner != l.logger {
+			return Decorate(inner, l.prefix, l.args...)
+		}
+	}
+	return logger
+}
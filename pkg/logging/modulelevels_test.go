@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging_test
+
+import (
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+)
+
+var _ = Describe("Module log levels test", func() {
+
+	It("filters the log messages of each subsystem independently", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The first replica only logs debug messages of its orderers, until the general ISS debug output is enabled.
+		recorder := &recordingLogger{}
+		levels := logging.NewModuleLevels(recorder, logging.LevelInfo)
+		levels.SetLevel(iss.LogModuleOrderer, logging.LevelDebug)
+		deployment.TestReplicas[0].Config.Logger = logging.Decorate(levels, "Node 0: ")
+
+		// Enable the ISS debug output once all requests have been delivered and wait until ISS logs a delivery.
+		finalStatuses := deployment.RunUntilThen(deployment.AllProcessed(testConfig.NumFakeRequests), func() {
+			recorder.mark()
+			levels.SetLevel(iss.LogModuleISS, logging.LevelDebug)
+			Eventually(func() bool {
+				return recorder.logged(logging.LevelDebug, "Delivering entry.")
+			}, deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+
+		// The modules were created by the Node and ISS, with the levels set before or inherited from the default.
+		Expect(levels.Levels()).To(HaveKeyWithValue(mirbft.LogModuleNode, logging.LevelInfo))
+		Expect(levels.Levels()).To(HaveKeyWithValue(iss.LogModuleEpoch, logging.LevelInfo))
+		Expect(levels.Levels()).To(HaveKeyWithValue(iss.LogModuleOrderer, logging.LevelDebug))
+		Expect(levels.Levels()).To(HaveKeyWithValue(iss.LogModuleISS, logging.LevelDebug))
+
+		// The orderers logged debug messages all the time, ISS only after enabling them.
+		var proposing, delivering, deliveringBeforeMark int
+		for _, entry := range recorder.entries {
+			Expect(entry.text).To(HavePrefix("Node 0: "))
+			if entry.level != logging.LevelDebug {
+				continue
+			}
+			switch {
+			case strings.Contains(entry.text, "PBFT: Proposing."):
+				proposing++
+			case strings.Contains(entry.text, "Delivering entry."):
+				delivering++
+				if !entry.marked {
+					deliveringBeforeMark++
+				}
+			}
+		}
+		Expect(proposing).To(BeNumerically(">", 0))
+		Expect(delivering).To(BeNumerically(">", 0))
+		Expect(deliveringBeforeMark).To(BeZero())
+	})
+})
+
+// recordingLogger is a Logger that records all logged messages,
+// noting whether they have been logged before or after calling mark.
+type recordingLogger struct {
+	lock    sync.Mutex
+	marked  bool
+	entries []recordedLogEntry
+}
+
+type recordedLogEntry struct {
+	level  logging.LogLevel
+	text   string
+	marked bool
+}
+
+func (rl *recordingLogger) Log(level logging.LogLevel, text string, args ...interface{}) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.entries = append(rl.entries, recordedLogEntry{level: level, text: text, marked: rl.marked})
+}
+
+// logged returns true if a message containing text has been logged at the given level.
+func (rl *recordingLogger) logged(level logging.LogLevel, text string) bool {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	for _, entry := range rl.entries {
+		if entry.level == level && strings.Contains(entry.text, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rl *recordingLogger) mark() {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.marked = true
+}
@@ -137,6 +137,21 @@ func (mb *MessageBuffer) Store(msg proto.Message) bool {
 	return true
 }
 
+// Len returns the number of messages currently stored in the MessageBuffer.
+func (mb *MessageBuffer) Len() int {
+	return mb.messages.Len()
+}
+
+// Size returns the number of bytes occupied by the messages currently stored in the MessageBuffer.
+func (mb *MessageBuffer) Size() int {
+	return mb.size
+}
+
+// Capacity returns the maximal number of bytes of message data the MessageBuffer can store.
+func (mb *MessageBuffer) Capacity() int {
+	return mb.capacity
+}
+
 // Resize changes the capacity of the MessageBuffer to newCapacity.
 // If newCapacity is smaller than the current capacity,
 // Resize removes as many least recently added messages
@@ -0,0 +1,317 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics collects metrics about the operation of a Node and exposes them to Prometheus.
+//
+// A Collector is attached to a Node through two lightweight hooks the Node already provides:
+// as the Node's event Interceptor (modules.Modules.Interceptor), it observes the events processed by the Node's
+// state machine (committed batches, sent and received messages, epoch changes, client windows), and
+// as the Node's ProcessorMetrics (mirbft.NodeConfig.Metrics), it observes the latencies of the processing stages.
+// Further values (e.g. the number of bytes of messages buffered by the protocol) can be registered as gauges
+// evaluated when the metrics are scraped, and so can the commit latency histograms maintained by the protocol
+// (see RegisterCommitLatencies). The Collector is an http.Handler serving the metrics in the
+// Prometheus text exposition format, such that it can be registered directly with an HTTP server.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Prefix of the names of all metrics exposed by a Collector.
+const namePrefix = "mirbft_"
+
+// Collector collects metrics about the operation of a single Node (see package documentation).
+// All its methods are safe for concurrent use.
+type Collector struct {
+
+	// Metric families in the order in which they are exposed.
+	committedBatches  *family
+	committedRequests *family
+	messagesSent      *family
+	messagesReceived  *family
+	epochChanges      *family
+	epoch             *family
+	clientWindow      *family
+	clientWatermark   *family
+	stageLatency      *family
+	stageItems        *family
+	bucketLatency     *family
+	leaderLatency     *family
+	registered        []*registeredGauge
+
+	// Source of the commit latency histograms, if registered.
+	commitLatencies CommitLatencySource
+
+	// For each client, the request numbers of the requests that became ready, but have not been committed yet.
+	// This constitutes the occupancy of the client's window.
+	pendingRequests map[t.ClientID]map[t.ReqNo]struct{}
+
+	// Protects all the fields of the Collector.
+	lock sync.Mutex
+}
+
+// CommitLatencySource provides histograms of the commit latencies of requests per bucket and per leader,
+// and is implemented by the iss.ISS protocol (see iss.LatencyHistogram).
+// CommitLatencies must be safe to be called concurrently with the operation of the Node.
+type CommitLatencySource interface {
+	CommitLatencies() (map[int]iss.LatencyHistogram, map[t.NodeID]iss.LatencyHistogram)
+}
+
+// registeredGauge is a gauge whose value is obtained from a function when the metrics are scraped.
+type registeredGauge struct {
+	family *family
+	value  func() float64
+}
+
+// NewCollector returns a new Collector, with all metrics initially at zero.
+func NewCollector() *Collector {
+	c := &Collector{
+		committedBatches: newFamily(namePrefix+"committed_batches_total",
+			"Number of batches committed.", "counter", ""),
+		committedRequests: newFamily(namePrefix+"committed_requests_total",
+			"Number of requests committed.", "counter", ""),
+		messagesSent: newFamily(namePrefix+"messages_sent_total",
+			"Number of messages sent to other nodes, by message type.", "counter", "type"),
+		messagesReceived: newFamily(namePrefix+"messages_received_total",
+			"Number of messages received from other nodes, by message type.", "counter", "type"),
+		epochChanges: newFamily(namePrefix+"epoch_changes_total",
+			"Number of epochs started (including the initial one).", "counter", ""),
+		epoch: newFamily(namePrefix+"epoch",
+			"Number of the current epoch.", "gauge", ""),
+		clientWindow: newFamily(namePrefix+"client_window_occupancy",
+			"Number of requests of a client that are ready, but not yet committed.", "gauge", "client"),
+		clientWatermark: newFamily(namePrefix+"client_low_watermark",
+			"Low watermark of a client's window.", "gauge", "client"),
+		stageLatency: newFamily(namePrefix+"stage_duration_seconds",
+			"Duration of processing a list of events by a processing stage.", "histogram", "stage"),
+		stageItems: newFamily(namePrefix+"stage_items_total",
+			"Number of items processed by a processing stage.", "counter", "stage"),
+		bucketLatency: newFamily(namePrefix+"bucket_commit_latency_ticks",
+			"Latency (in ticks) from a request becoming ready to it being committed, by bucket.", "histogram", "bucket"),
+		leaderLatency: newFamily(namePrefix+"leader_commit_latency_ticks",
+			"Latency (in ticks) from a request becoming ready to it being committed, by leader.", "histogram", "leader"),
+		pendingRequests: make(map[t.ClientID]map[t.ReqNo]struct{}),
+	}
+
+	// Expose the counters without labels even before they are first increased.
+	c.committedBatches.add("", 0)
+	c.committedRequests.add("", 0)
+	c.epochChanges.add("", 0)
+	c.epoch.set("", 0)
+
+	return c
+}
+
+// RegisterGauge registers a gauge with the given name (to which the common prefix "mirbft_" is prepended)
+// and description, the value of which is obtained by calling value each time the metrics are scraped.
+// value must be safe to be called concurrently with the operation of the Node.
+func (c *Collector) RegisterGauge(name string, help string, value func() float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.registered = append(c.registered, &registeredGauge{
+		family: newFamily(namePrefix+name, help, "gauge", ""),
+		value:  value,
+	})
+}
+
+// RegisterCommitLatencies registers a source of commit latency histograms (e.g., the iss.ISS protocol of the Node),
+// which are obtained from the source each time the metrics are scraped.
+func (c *Collector) RegisterCommitLatencies(source CommitLatencySource) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.commitLatencies = source
+}
+
+// ============================================================
+// State machine events (modules.EventInterceptor)
+// ============================================================
+
+// Intercept updates the metrics based on the events processed by the Node.
+// It implements the modules.EventInterceptor interface and never returns an error.
+func (c *Collector) Intercept(eventList *events.EventList) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	iter := eventList.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		switch e := event.Type.(type) {
+		case *eventpb.Event_SendMessage:
+			c.messagesSent.add(e.SendMessage.Msg.TypeName(), float64(len(e.SendMessage.Destinations)))
+		case *eventpb.Event_MessageReceived:
+			c.messagesReceived.add(e.MessageReceived.Msg.TypeName(), 1)
+		case *eventpb.Event_RequestReady:
+			c.requestReady(e.RequestReady.RequestRef)
+		case *eventpb.Event_Deliver:
+			c.deliver(e.Deliver)
+		case *eventpb.Event_Notification:
+			c.notification(e.Notification)
+		}
+	}
+	return nil
+}
+
+// requestReady registers a request that became ready as occupying its client's window.
+func (c *Collector) requestReady(reqRef *requestpb.RequestRef) {
+	clientID := t.ClientID(reqRef.ClientId)
+	if c.pendingRequests[clientID] == nil {
+		c.pendingRequests[clientID] = make(map[t.ReqNo]struct{})
+	}
+	c.pendingRequests[clientID][t.ReqNo(reqRef.ReqNo)] = struct{}{}
+}
+
+// deliver accounts for a committed batch, whose requests stop occupying the windows of their clients.
+func (c *Collector) deliver(deliver *eventpb.Deliver) {
+	c.committedBatches.add("", 1)
+	c.committedRequests.add("", float64(len(deliver.Batch.Requests)))
+	for _, reqRef := range deliver.Batch.Requests {
+		delete(c.pendingRequests[t.ClientID(reqRef.ClientId)], t.ReqNo(reqRef.ReqNo))
+	}
+}
+
+// notification accounts for epoch changes and moved client windows.
+func (c *Collector) notification(notification *eventpb.Notification) {
+	switch n := notification.Type.(type) {
+	case *eventpb.Notification_EpochStarted:
+		c.epochChanges.add("", 1)
+		c.epoch.set("", float64(n.EpochStarted.Epoch))
+	case *eventpb.Notification_ClientWindowMoved:
+		clientID := t.ClientID(n.ClientWindowMoved.ClientId)
+		c.clientWatermark.set(fmt.Sprint(clientID), float64(n.ClientWindowMoved.LowWatermark))
+		for reqNo := range c.pendingRequests[clientID] {
+			if reqNo < t.ReqNo(n.ClientWindowMoved.LowWatermark) {
+				delete(c.pendingRequests[clientID], reqNo)
+			}
+		}
+	}
+}
+
+// ============================================================
+// Processing stages (mirbft.ProcessorMetrics)
+// ============================================================
+
+// OnPersist records the latency of appending n entries to the WAL.
+func (c *Collector) OnPersist(d time.Duration, n int) {
+	c.observeStage("persist", d, n)
+}
+
+// OnSync records the latency of syncing the WAL.
+func (c *Collector) OnSync(d time.Duration) {
+	c.observeStage("sync", d, 1)
+}
+
+// OnTransmit records the latency of sending n messages.
+func (c *Collector) OnTransmit(d time.Duration, n int) {
+	c.observeStage("transmit", d, n)
+}
+
+// OnHash records the latency of computing n digests.
+func (c *Collector) OnHash(d time.Duration, n int) {
+	c.observeStage("hash", d, n)
+}
+
+// OnCommit records the latency of applying n batches to the application.
+func (c *Collector) OnCommit(d time.Duration, n int) {
+	c.observeStage("commit", d, n)
+}
+
+// observeStage records the latency of processing n items by the given stage.
+func (c *Collector) observeStage(stage string, d time.Duration, n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.stageLatency.observe(stage, d)
+	c.stageItems.add(stage, float64(n))
+}
+
+// ============================================================
+// Exposition
+// ============================================================
+
+// WriteMetrics writes the current values of all metrics to w in the Prometheus text exposition format.
+func (c *Collector) WriteMetrics(w io.Writer) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Compute the occupancy of the client windows.
+	for clientID, pending := range c.pendingRequests {
+		c.clientWindow.set(fmt.Sprint(clientID), float64(len(pending)))
+	}
+
+	// Obtain the commit latency histograms.
+	if c.commitLatencies != nil {
+		byBucket, byLeader := c.commitLatencies.CommitLatencies()
+		for bucketID, h := range byBucket {
+			setLatencyHistogram(c.bucketLatency, fmt.Sprint(bucketID), h)
+		}
+		for leader, h := range byLeader {
+			setLatencyHistogram(c.leaderLatency, fmt.Sprint(leader), h)
+		}
+	}
+
+	// Evaluate the registered gauges.
+	for _, gauge := range c.registered {
+		gauge.family.set("", gauge.value())
+	}
+
+	families := []*family{
+		c.committedBatches,
+		c.committedRequests,
+		c.messagesSent,
+		c.messagesReceived,
+		c.epochChanges,
+		c.epoch,
+		c.clientWindow,
+		c.clientWatermark,
+		c.stageLatency,
+		c.stageItems,
+		c.bucketLatency,
+		c.leaderLatency,
+	}
+	for _, gauge := range c.registered {
+		families = append(families, gauge.family)
+	}
+
+	for _, f := range families {
+		if err := f.write(w); err != nil {
+			return fmt.Errorf("could not write metric %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// setLatencyHistogram sets the histogram with the given label value of family f to h.
+func setLatencyHistogram(f *family, label string, h iss.LatencyHistogram) {
+	bounds := make([]float64, len(h.UpperBounds))
+	for i, bound := range h.UpperBounds {
+		bounds[i] = float64(bound)
+	}
+	f.setHistogram(label, bounds, h.Counts, float64(h.Sum))
+}
+
+// ServeHTTP serves the current values of all metrics in the Prometheus text exposition format.
+// This makes the Collector an http.Handler that can be registered with an HTTP server as the scrape target.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	if err := c.WriteMetrics(bw); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_ = bw.Flush()
+}
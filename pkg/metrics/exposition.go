@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The upper bounds (in seconds) of the buckets of the latency histograms.
+var latencyBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// family is a metric family with an optional single label, written in the Prometheus text format.
+// The metric of each label value is created when first updated.
+// A family is not safe for concurrent use, it is protected by the lock of the Collector it belongs to.
+type family struct {
+	name      string
+	help      string
+	typ       string // "counter", "gauge" or "histogram"
+	labelName string // Empty if the metrics of the family are not labeled.
+
+	// Values of counters and gauges, indexed by label value.
+	values map[string]float64
+
+	// Histograms, indexed by label value.
+	histograms map[string]*histogram
+}
+
+// histogram counts observations in buckets with fixed upper bounds (latencyBuckets, unless set using setHistogram).
+type histogram struct {
+	bounds []float64
+	counts []uint64 // Number of observations in each bucket (not cumulative), excluding the +Inf bucket.
+	sum    float64
+	count  uint64
+}
+
+// newFamily returns a new, empty metric family.
+func newFamily(name string, help string, typ string, labelName string) *family {
+	return &family{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelName:  labelName,
+		values:     make(map[string]float64),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+// add increases the counter or gauge with the given label value by delta.
+func (f *family) add(label string, delta float64) {
+	f.values[label] += delta
+}
+
+// set sets the gauge with the given label value to value.
+func (f *family) set(label string, value float64) {
+	f.values[label] = value
+}
+
+// observe adds an observed duration to the histogram with the given label value.
+func (f *family) observe(label string, d time.Duration) {
+	h, ok := f.histograms[label]
+	if !ok {
+		h = &histogram{bounds: latencyBuckets, counts: make([]uint64, len(latencyBuckets))}
+		f.histograms[label] = h
+	}
+
+	seconds := d.Seconds()
+	for i, bound := range h.bounds {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// setHistogram replaces the histogram with the given label value by one with the given bucket upper bounds,
+// numbers of observations in each bucket (not cumulative, with an extra last element for the +Inf bucket),
+// and sum of observations. This is used for exposing histograms maintained elsewhere.
+func (f *family) setHistogram(label string, bounds []float64, counts []uint64, sum float64) {
+	h := &histogram{bounds: bounds, counts: counts[:len(bounds)], sum: sum}
+	for _, count := range counts {
+		h.count += count
+	}
+	f.histograms[label] = h
+}
+
+// write writes the family in the Prometheus text format to w, the metrics ordered by label value.
+func (f *family) write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ); err != nil {
+		return err
+	}
+
+	if f.typ != "histogram" {
+		for _, label := range sortedKeys(f.values) {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, f.labels(label, ""), formatValue(f.values[label])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, label := range sortedKeys(f.histograms) {
+		h := f.histograms[label]
+		cumulative := uint64(0)
+		for i, bound := range h.bounds {
+			cumulative += h.counts[i]
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n",
+				f.name, f.labels(label, formatValue(bound)), cumulative); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			f.name, f.labels(label, "+Inf"), h.count,
+			f.name, f.labels(label, ""), formatValue(h.sum),
+			f.name, f.labels(label, ""), h.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labels returns the label set of a metric of the family with the given label value and,
+// for histogram buckets, the given upper bound.
+func (f *family) labels(label string, le string) string {
+	pairs := make([]string, 0, 2)
+	if f.labelName != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", f.labelName, label))
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf("le=%q", le))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue formats a sample value as expected by the Prometheus text format.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of a map indexed by label values in increasing order.
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	switch m := m.(type) {
+	case map[string]float64:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]*histogram:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
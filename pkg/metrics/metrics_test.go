@@ -0,0 +1,231 @@
+package metrics_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/metrics"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// fixedLatencies is a CommitLatencySource always returning the same histograms.
+type fixedLatencies struct {
+	byBucket map[int]iss.LatencyHistogram
+	byLeader map[t.NodeID]iss.LatencyHistogram
+}
+
+func (fl *fixedLatencies) CommitLatencies() (map[int]iss.LatencyHistogram, map[t.NodeID]iss.LatencyHistogram) {
+	return fl.byBucket, fl.byLeader
+}
+
+// reqRef returns a reference to request reqNo of client clientID.
+func reqRef(clientID t.ClientID, reqNo t.ReqNo) *requestpb.RequestRef {
+	return &requestpb.RequestRef{ClientId: clientID.Pb(), ReqNo: reqNo.Pb(), Digest: []byte{0}}
+}
+
+var _ = Describe("Collector", func() {
+	var collector *metrics.Collector
+
+	// scrape returns the current metrics of the collector.
+	scrape := func() string {
+		var buf bytes.Buffer
+		Expect(collector.WriteMetrics(&buf)).To(Succeed())
+		return buf.String()
+	}
+
+	// intercept passes the given events to the collector.
+	intercept := func(evts ...*eventpb.Event) {
+		eventList := &events.EventList{}
+		for _, event := range evts {
+			eventList.PushBack(event)
+		}
+		Expect(collector.Intercept(eventList)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		collector = metrics.NewCollector()
+	})
+
+	It("exposes the counters before they are increased", func() {
+		Expect(scrape()).To(ContainSubstring(
+			"# HELP mirbft_committed_batches_total Number of batches committed.\n" +
+				"# TYPE mirbft_committed_batches_total counter\n" +
+				"mirbft_committed_batches_total 0\n"))
+		Expect(scrape()).To(ContainSubstring("mirbft_epoch 0\n"))
+	})
+
+	It("counts committed batches and requests", func() {
+		intercept(
+			events.Deliver(0, &requestpb.Batch{Requests: []*requestpb.RequestRef{reqRef(1, 0), reqRef(2, 0)}}),
+			events.Deliver(1, &requestpb.Batch{}),
+		)
+		Expect(scrape()).To(ContainSubstring("mirbft_committed_batches_total 2\n"))
+		Expect(scrape()).To(ContainSubstring("mirbft_committed_requests_total 2\n"))
+	})
+
+	It("counts messages by type", func() {
+		checkpoint := iss.CheckpointMessage(0, 0, 0, 0)
+		intercept(
+			events.SendMessage(checkpoint, []t.NodeID{1, 2, 3}),
+			events.MessageReceived(1, checkpoint),
+			events.MessageReceived(1, &messagepb.Message{Type: &messagepb.Message_DummyPreprepare{
+				DummyPreprepare: &messagepb.DummyPreprepare{},
+			}}),
+		)
+		Expect(scrape()).To(ContainSubstring("mirbft_messages_sent_total{type=\"checkpoint\"} 3\n"))
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_messages_received_total{type=\"checkpoint\"} 1\n" +
+				"mirbft_messages_received_total{type=\"dummy_preprepare\"} 1\n"))
+	})
+
+	It("tracks the epoch", func() {
+		intercept(events.EpochStarted(0, nil, nil), events.EpochStarted(3, nil, nil))
+		Expect(scrape()).To(ContainSubstring("mirbft_epoch_changes_total 2\n"))
+		Expect(scrape()).To(ContainSubstring("mirbft_epoch 3\n"))
+	})
+
+	It("tracks the occupancy of the client windows", func() {
+		intercept(
+			events.RequestReady(reqRef(1, 0), nil),
+			events.RequestReady(reqRef(1, 1), nil),
+			events.RequestReady(reqRef(1, 2), nil),
+			events.RequestReady(reqRef(2, 0), nil),
+		)
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_client_window_occupancy{client=\"1\"} 3\n" +
+				"mirbft_client_window_occupancy{client=\"2\"} 1\n"))
+
+		// Committed requests stop occupying the window, and so do the requests below a moved watermark.
+		intercept(
+			events.Deliver(0, &requestpb.Batch{Requests: []*requestpb.RequestRef{reqRef(1, 2)}}),
+			events.ClientWindowMoved(1, 1),
+			events.ClientWindowMoved(2, 1),
+		)
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_client_window_occupancy{client=\"1\"} 1\n" +
+				"mirbft_client_window_occupancy{client=\"2\"} 0\n"))
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_client_low_watermark{client=\"1\"} 1\n" +
+				"mirbft_client_low_watermark{client=\"2\"} 1\n"))
+	})
+
+	It("records the latencies of the processing stages in histograms", func() {
+		collector.OnSync(3 * time.Millisecond)
+		collector.OnPersist(20*time.Millisecond, 5)
+		collector.OnPersist(2*time.Second, 7)
+
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_stage_duration_seconds_bucket{stage=\"sync\",le=\"0.001\"} 0\n" +
+				"mirbft_stage_duration_seconds_bucket{stage=\"sync\",le=\"0.005\"} 1\n"))
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_stage_duration_seconds_bucket{stage=\"persist\",le=\"0.05\"} 1\n"))
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_stage_duration_seconds_bucket{stage=\"persist\",le=\"5\"} 2\n" +
+				"mirbft_stage_duration_seconds_bucket{stage=\"persist\",le=\"+Inf\"} 2\n" +
+				"mirbft_stage_duration_seconds_sum{stage=\"persist\"} 2.02\n" +
+				"mirbft_stage_duration_seconds_count{stage=\"persist\"} 2\n"))
+		Expect(scrape()).To(ContainSubstring("mirbft_stage_items_total{stage=\"persist\"} 12\n"))
+		Expect(scrape()).To(ContainSubstring("mirbft_stage_items_total{stage=\"sync\"} 1\n"))
+	})
+
+	It("exposes the commit latencies of the registered source", func() {
+		collector.RegisterCommitLatencies(&fixedLatencies{
+			byBucket: map[int]iss.LatencyHistogram{0: {UpperBounds: []uint64{1, 10}, Counts: []uint64{2, 1, 1}, Sum: 20}},
+			byLeader: map[t.NodeID]iss.LatencyHistogram{3: {UpperBounds: []uint64{1}, Counts: []uint64{0, 1}, Sum: 5}},
+		})
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_bucket_commit_latency_ticks_bucket{bucket=\"0\",le=\"1\"} 2\n" +
+				"mirbft_bucket_commit_latency_ticks_bucket{bucket=\"0\",le=\"10\"} 3\n" +
+				"mirbft_bucket_commit_latency_ticks_bucket{bucket=\"0\",le=\"+Inf\"} 4\n" +
+				"mirbft_bucket_commit_latency_ticks_sum{bucket=\"0\"} 20\n" +
+				"mirbft_bucket_commit_latency_ticks_count{bucket=\"0\"} 4\n"))
+		Expect(scrape()).To(ContainSubstring(
+			"mirbft_leader_commit_latency_ticks_bucket{leader=\"3\",le=\"+Inf\"} 1\n"))
+	})
+
+	It("evaluates the registered gauges on each scrape", func() {
+		value := 1.0
+		collector.RegisterGauge("buffered_bytes", "Bytes buffered.", func() float64 { return value })
+		Expect(scrape()).To(ContainSubstring(
+			"# HELP mirbft_buffered_bytes Bytes buffered.\n" +
+				"# TYPE mirbft_buffered_bytes gauge\n" +
+				"mirbft_buffered_bytes 1\n"))
+
+		value = 2.5
+		Expect(scrape()).To(ContainSubstring("mirbft_buffered_bytes 2.5\n"))
+	})
+
+	It("serves the metrics over HTTP", func() {
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		Expect(recorder.Code).To(Equal(200))
+		Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+		Expect(recorder.Body.String()).To(Equal(scrape()))
+	})
+})
+
+var _ = Describe("Metrics test", func() {
+
+	It("exports node metrics in the Prometheus format", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Attach a metrics collector to each replica, both as processor metrics and as event interceptor.
+		collectors := make([]*metrics.Collector, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			app := replica.App
+			collectors[i] = metrics.NewCollector()
+			collectors[i].RegisterGauge("app_requests_processed", "Number of requests applied by the test app.",
+				func() float64 { return float64(atomic.LoadUint64(&app.RequestsProcessed)) })
+			replica.Config.Metrics = collectors[i]
+			replica.Interceptor = collectors[i]
+		}
+
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllInEpoch(1)))
+
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(deployment.TestReplicas[i].App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+			// Scrape the metrics of the replica.
+			recorder := httptest.NewRecorder()
+			collectors[i].ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+			scraped := recorder.Body.String()
+
+			Expect(scraped).To(ContainSubstring("# TYPE mirbft_committed_requests_total counter\n"))
+			Expect(scraped).To(ContainSubstring(
+				fmt.Sprintf("\nmirbft_committed_requests_total %d\n", testConfig.NumFakeRequests)))
+			Expect(scraped).To(ContainSubstring(
+				fmt.Sprintf("\nmirbft_app_requests_processed %d\n", testConfig.NumFakeRequests)))
+			Expect(scraped).To(MatchRegexp(`\nmirbft_epoch_changes_total [1-9][0-9]*\n`))
+			Expect(scraped).To(MatchRegexp(`\nmirbft_messages_sent_total\{type="pbft_preprepare"\} [1-9][0-9]*\n`))
+			Expect(scraped).To(MatchRegexp(`\nmirbft_messages_received_total\{type="pbft_preprepare"\} [1-9][0-9]*\n`))
+			Expect(scraped).To(ContainSubstring(`mirbft_client_window_occupancy{client="0"} 0`))
+			Expect(scraped).To(ContainSubstring("# TYPE mirbft_stage_duration_seconds histogram\n"))
+			Expect(scraped).To(MatchRegexp(`\nmirbft_stage_duration_seconds_count\{stage="commit"\} [1-9][0-9]*\n`))
+			Expect(scraped).To(ContainSubstring(`mirbft_stage_duration_seconds_bucket{stage="persist",le="+Inf"}`))
+		}
+	})
+})
@@ -8,6 +8,7 @@ package modules
 
 import (
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
 )
 
 // App represents an application this library is used for replicating.
@@ -34,3 +35,28 @@ type App interface {
 	//       modifying the application state (which should exclusively be modified by the Apply and RestoreState
 	//       methods).
 }
+
+// QueryableApp is an App that can serve read-only queries against its state without them being ordered.
+// By implementing this interface, an App opts in to linearizable reads through Node.Read.
+type QueryableApp interface {
+	App
+
+	// Query executes a read-only query against the current application state and returns its result.
+	// Query must not modify the application state.
+	// It is invoked by the same goroutine as Apply, never concurrently with it.
+	Query(query []byte) ([]byte, error)
+}
+
+// ClientPartitionedApp is an App whose state is partitioned by client,
+// i.e., an App for which the requests of different clients commute.
+// By implementing this interface, an App opts in to having the requests of different clients applied concurrently.
+// The Node then applies each batch by invoking ApplyClientRequests concurrently for all clients with requests in the
+// batch, instead of invoking Apply. The order of requests of the same client is always preserved,
+// and batches are still applied one after the other, in the order of their delivery.
+type ClientPartitionedApp interface {
+	App
+
+	// ApplyClientRequests applies, in the given order, the requests of client clientID contained in a batch.
+	// It is invoked concurrently for different clients, but never concurrently for the same client.
+	ApplyClientRequests(clientID t.ClientID, reqRefs []*requestpb.RequestRef) error
+}
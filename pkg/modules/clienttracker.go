@@ -12,9 +12,24 @@ import (
 	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
 )
 
-// TODO: Document this.
-
+// The ClientTracker admits the requests submitted by clients (or forwarded by other nodes) to the system.
+// It has each request authenticated (e.g., by having the Crypto module verify the client's signature),
+// stores the requests that pass the authentication in the request store
+// and only then announces them to the protocol (see events.RequestReady).
+// Requests that fail the authentication are dropped.
+//
+// The set of clients is not static. Configuration requests (see isspb.ConfigChange) add and remove clients,
+// and the changes take effect at the start of the epoch following the one the request is committed in.
+// The protocol then updates the client keys known to the Crypto module (see events.UpdateClientKeys),
+// such that the requests of added clients pass the authentication from that point on,
+// and those of removed clients fail it. After a restart, the client keys are restored from the recovered checkpoint.
+// Thus, a ClientTracker relying on the Crypto module for authentication need not track the clients itself.
 type ClientTracker interface {
+
+	// ApplyEvent processes an event incoming to the ClientTracker
+	// and returns a (potentially empty) list of new events to be processed by the Node.
 	ApplyEvent(event *eventpb.Event) *events.EventList
+
+	// Status returns the current state of the ClientTracker, e.g., for debugging purposes.
 	Status() (s *statuspb.ClientTrackerStatus, err error)
 }
@@ -56,3 +56,20 @@ type Crypto interface {
 	// otherwise VerifyClientSig will fail.
 	VerifyClientSig(data [][]byte, signature []byte, clientID t.ClientID) error
 }
+
+// NodeKeyRotator is an optional interface a Crypto module can implement to support the rotation of node keys
+// without downtime (see isspb.ConfigChange). When a node's key is rotated, both the new and the previous key
+// are accepted for an overlap period, during which the node switches to signing with the new key.
+// If the Crypto module does not implement NodeKeyRotator, a rotated key immediately replaces the previous one.
+type NodeKeyRotator interface {
+
+	// RotateNodeKey associates a new public key with a numeric node ID.
+	// Until RetireNodeKey is called for the node, VerifyNodeSig still accepts signatures
+	// verifiable with the key that was associated with the node before.
+	// Returns nil on success, a non-nil error on failure, in which case the node's keys remain unchanged.
+	RotateNodeKey(pubKey []byte, nodeID t.NodeID) error
+
+	// RetireNodeKey stops accepting the key replaced by the last invocation of RotateNodeKey for nodeID.
+	// Any subsequent call to VerifyNodeSig(..., nodeID) only succeeds with the node's new key.
+	RetireNodeKey(nodeID t.NodeID)
+}
@@ -18,3 +18,26 @@ type EventInterceptor interface {
 	// TODO: In the comment, also refer to the way events can be analyzed or replayed.
 	Intercept(events *events.EventList) error
 }
+
+// Interceptors returns an EventInterceptor passing the intercepted events to all the given interceptors,
+// in the given order. Nil interceptors are skipped. If one of the interceptors returns an error,
+// the following ones are not invoked and the error is returned.
+func Interceptors(interceptors ...EventInterceptor) EventInterceptor {
+	return multiInterceptor(interceptors)
+}
+
+// multiInterceptor is the EventInterceptor returned by Interceptors.
+type multiInterceptor []EventInterceptor
+
+// Intercept passes events to all the interceptors.
+func (mi multiInterceptor) Intercept(events *events.EventList) error {
+	for _, interceptor := range mi {
+		if interceptor == nil {
+			continue
+		}
+		if err := interceptor.Intercept(events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
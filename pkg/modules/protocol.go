@@ -7,9 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package modules
 
 import (
+	"fmt"
+
 	"github.com/hyperledger-labs/mirbft/pkg/events"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
 )
 
 // Protocol represents the logic of a protocol.
@@ -27,3 +31,66 @@ type Protocol interface {
 	// Mostly for debugging purposes.
 	Status() (s *statuspb.ProtocolStatus, err error)
 }
+
+// Errors classifying why a message received from another node is invalid (see MessageValidator).
+// The errors describing invalid messages wrap one of them, such that they can be inspected using errors.Is.
+var (
+	// ErrUnknownNode means that the message has been sent by a node that is not part of the membership.
+	ErrUnknownNode = fmt.Errorf("unknown node")
+
+	// ErrEpochOutOfRange means that the message belongs to an epoch the protocol does not process (any more).
+	ErrEpochOutOfRange = fmt.Errorf("message outside any epoch")
+
+	// ErrMalformedMessage means that the message is of an unexpected type or that some of its fields are missing.
+	ErrMalformedMessage = fmt.Errorf("malformed message")
+)
+
+// MessageValidator is a Protocol that can check the messages received from other nodes before they are applied.
+// By implementing this interface, a Protocol lets the Node reject invalid messages (see Node.Step)
+// instead of having to cope with them in ApplyEvent (e.g. by panicking on a missing field).
+type MessageValidator interface {
+	Protocol
+
+	// ValidateMessage returns an error wrapping ErrUnknownNode, ErrEpochOutOfRange, or ErrMalformedMessage
+	// if msg, received from node from, must not be applied to the protocol, and nil otherwise.
+	// ValidateMessage is invoked by the goroutines submitting messages to the Node,
+	// concurrently with each other and with ApplyEvent.
+	// It thus must be safe for concurrent use and must not modify the state of the protocol.
+	// As the state of the protocol might change before the message is applied,
+	// ApplyEvent must still handle valid messages that became irrelevant (e.g. after an epoch change).
+	ValidateMessage(from t.NodeID, msg *messagepb.Message) error
+}
+
+// MembershipProvider is a Protocol that knows the (possibly changing) membership of the system.
+// By implementing this interface, a Protocol lets the Node contact a quorum of the current members
+// (e.g. for serving linearizable reads, see Node.Read) using the same quorum size the protocol uses,
+// and check the senders of the messages the Node handles itself (see Node.Step).
+// All methods are invoked concurrently with ApplyEvent and thus must be safe for concurrent use.
+type MembershipProvider interface {
+	Protocol
+
+	// Membership returns the current members and the size of a quorum among them,
+	// such that any two quorums intersect in at least one correct member.
+	// The returned slice must not be modified by the protocol afterwards.
+	Membership() (members []t.NodeID, quorum int)
+
+	// IsMember returns true if nodeID is a current member
+	// or a member added by a configuration change that has not taken effect yet.
+	IsMember(nodeID t.NodeID) bool
+
+	// IsLearner returns true if nodeID is a learner, i.e., a non-voting node following the protocol,
+	// including learners added by a configuration change that has not taken effect yet.
+	IsLearner(nodeID t.NodeID) bool
+}
+
+// StateDumper is a Protocol that can produce a complete representation of its internal state (see Node.Dump).
+// Unlike Status, the dump is meant to contain all the details needed for debugging a stuck system offline.
+type StateDumper interface {
+	Protocol
+
+	// DumpState returns a representation of the internal state of the protocol
+	// that can be serialized using encoding/json.
+	// DumpState is never invoked concurrently with ApplyEvent.
+	// The returned value must not be modified by the protocol afterwards.
+	DumpState() (interface{}, error)
+}
@@ -33,10 +33,14 @@ import (
 // (e.g. if the request has ben obtained directly from the client, but is not signed).
 // For proposing a request, an authenticator is necessary to make sure that other correct nodes will accept the request.
 //
+// To enable implementations to use a single write batch (or a single multi-get) for multiple requests,
+// StoreBatch() and GetBatch() store and retrieve the information about multiple requests at once.
+//
+// Data of requests that are not needed any more (e.g. because they have been committed
+// and are covered by a stable checkpoint) is removed using Prune().
+//
 // All effects of method invocations that change the state of the RequestStore can only be guaranteed to be persisted
 // When a subsequent invocation of Sync() returns. Without a call to Sync(), the effects may or may not be persisted.
-//
-// TODO: Define functions for removal of old data.
 type RequestStore interface {
 
 	// PutRequest stores request the passed request data associated with the request reference.
@@ -68,6 +72,19 @@ type RequestStore interface {
 	// (request data, authentication, or authenticator) is stored in the RequestStore.
 	GetDigestsByID(clientID t.ClientID, reqNo t.ReqNo) ([][]byte, error)
 
+	// StoreBatch stores all the information about multiple requests at once.
+	// Its effect is the same as invoking PutRequest, SetAuthenticated (if the request is authenticated),
+	// and PutAuthenticator (if the request has an authenticator) for each of the requests, in order.
+	StoreBatch(requests []*requestpb.StoredRequest) error
+
+	// GetBatch returns the stored request data associated with each of the passed request references,
+	// in the same order. If no data is stored under any of the references, the returned error will be non-nil.
+	GetBatch(reqRefs []*requestpb.RequestRef) ([][]byte, error)
+
+	// Prune deletes all information (request data, authentication, and authenticators) stored for requests
+	// of client clientID with request numbers lower than belowReqNo.
+	Prune(clientID t.ClientID, belowReqNo t.ReqNo) error
+
 	// Sync blocks until the effects of all preceding method invocations have been persisted.
 	Sync() error
 }
@@ -17,6 +17,8 @@ import (
 // a restart or a crash/recovery event.
 // An integer retentionIndex is specified both when appending entries and when truncating the WAL.
 // On truncation, the WAL removes all entries whose retentionIndex is smaller than the specified one.
+// The Node calls Sync() concurrently with Append() and Truncate() (but never Append() concurrently with Truncate()),
+// in order to append new entries while previously appended ones are being synced.
 type WAL interface {
 
 	// Append appends an entry with a retentionIndex to the WAL.
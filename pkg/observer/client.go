@@ -0,0 +1,324 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+	"google.golang.org/grpc"
+)
+
+// A CertifiedEntry is a committed batch or a stable checkpoint reported identically by (at least) a threshold of nodes.
+type CertifiedEntry struct {
+
+	// The committed batch or stable checkpoint.
+	Entry *ObservedEntry
+
+	// The IDs of the nodes that reported the entry, in increasing order.
+	// Together, their reports form the certificate of the entry.
+	Nodes []t.NodeID
+}
+
+// Client observes the log committed by multiple nodes, each running a Server.
+// It verifies the received entries locally, by only outputting the entries reported identically by
+// a threshold of nodes. With a threshold of f+1 (f being the number of tolerated faulty nodes),
+// every output entry has been reported by at least one correct node.
+type Client struct {
+
+	// The ID the observer is registered with at the Servers.
+	ownID t.ClientID
+
+	// The Crypto module used for signing the requests, holding the observer's private key.
+	crypto modules.Crypto
+
+	// The number of nodes that must report an entry identically for it to be output.
+	threshold int
+
+	// Source of time for timestamping the observer's requests.
+	clock clock.Clock
+
+	// Logger use for all logging events of this Client.
+	logger logging.Logger
+}
+
+// NewClient returns a new Client observing the log as the observer with ID ownID,
+// authenticating using crypto and outputting only the entries reported identically by threshold nodes.
+func NewClient(ownID t.ClientID, crypto modules.Crypto, threshold int, logger logging.Logger) *Client {
+	// If no logger was given, only write errors to the console.
+	if logger == nil {
+		logger = logging.ConsoleErrorLogger
+	}
+
+	return &Client{
+		ownID:     ownID,
+		crypto:    crypto,
+		threshold: threshold,
+		clock:     clock.System,
+		logger:    logger,
+	}
+}
+
+// SetClock makes the Client timestamp its requests using c instead of the system clock.
+// It must be called before the Client is used.
+func (c *Client) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// report is an entry reported by a single node.
+type report struct {
+	from  t.NodeID
+	entry *ObservedEntry
+}
+
+// Observe connects to the Servers at the given addresses (indexed by node ID) and returns a channel
+// to which it writes the certified entries: the committed batches in the order of their sequence numbers,
+// starting at fromSn, interleaved with the certified stable checkpoints.
+// A checkpoint is only output after all the batches it encompasses.
+//
+// The nodes only stream the batches they commit after the Client connects. If the batches following fromSn
+// are not available, the Client skips to the first certified checkpoint following them,
+// as the checkpoint's application snapshot represents the state resulting from all the batches it encompasses.
+// The batch output next then has the sequence number of that checkpoint.
+//
+// The channel is closed when ctx is canceled or when the streams of all nodes ended.
+// Observe returns an error if the observer's request cannot be signed.
+// The nodes that cannot be reached or that reject the observer are ignored (and the failure is logged).
+func (c *Client) Observe(ctx context.Context, addrs map[t.NodeID]string, fromSn t.SeqNr) (<-chan *CertifiedEntry, error) {
+
+	// Create the request, signed by the observer.
+	request := &ObserveRequest{
+		ObserverId: c.ownID.Pb(),
+		FromSn:     fromSn.Pb(),
+		Timestamp:  c.clock.Now().UnixNano(),
+	}
+	signature, err := c.crypto.Sign(requestData(request))
+	if err != nil {
+		return nil, fmt.Errorf("could not sign observe request: %w", err)
+	}
+	request.Signature = signature
+
+	// Receive the entries of each node in a separate goroutine.
+	reports := make(chan report)
+	var wg sync.WaitGroup
+	for nodeID, addr := range addrs {
+		wg.Add(1)
+		go func(nodeID t.NodeID, addr string) {
+			defer wg.Done()
+			c.receive(ctx, nodeID, addr, request, reports)
+		}(nodeID, addr)
+	}
+	go func() {
+		wg.Wait()
+		close(reports)
+	}()
+
+	// Certify the reported entries.
+	outC := make(chan *CertifiedEntry)
+	go func() {
+		defer close(outC)
+		newCertifier(fromSn, c.threshold, c.logger).run(ctx, reports, outC)
+	}()
+
+	return outC, nil
+}
+
+// receive connects to a single Server and writes the entries it streams to reports,
+// until the stream ends or ctx is canceled.
+func (c *Client) receive(ctx context.Context, nodeID t.NodeID, addr string, request *ObserveRequest, reports chan<- report) {
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		c.logger.Log(logging.LevelWarn, "Could not connect to node.", "nodeID", nodeID, "addr", addr, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	stream, err := NewObserverClient(conn).Observe(ctx, request)
+	if err != nil {
+		c.logger.Log(logging.LevelWarn, "Could not observe node.", "nodeID", nodeID, "error", err)
+		return
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err != nil {
+			c.logger.Log(logging.LevelDebug, "Observer stream ended.", "nodeID", nodeID, "error", err)
+			return
+		}
+		select {
+		case reports <- report{from: nodeID, entry: entry}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// certifier collects the entries reported by the nodes and outputs the certified ones.
+// It is only accessed by a single goroutine.
+type certifier struct {
+
+	// The number of nodes that must report an entry identically for it to be certified.
+	threshold int
+
+	// The sequence number of the next batch to output.
+	nextSn t.SeqNr
+
+	// The (not yet certified) batches and checkpoints reported by each node, indexed by sequence number.
+	batches     map[t.SeqNr]map[t.NodeID]*ObservedEntry
+	checkpoints map[t.SeqNr]map[t.NodeID]*ObservedEntry
+
+	logger logging.Logger
+}
+
+// newCertifier returns a new certifier outputting batches starting from sequence number fromSn.
+func newCertifier(fromSn t.SeqNr, threshold int, logger logging.Logger) *certifier {
+	return &certifier{
+		threshold:   threshold,
+		nextSn:      fromSn,
+		batches:     make(map[t.SeqNr]map[t.NodeID]*ObservedEntry),
+		checkpoints: make(map[t.SeqNr]map[t.NodeID]*ObservedEntry),
+		logger:      logger,
+	}
+}
+
+// run applies the reports until the reports channel is closed or ctx is canceled,
+// writing the certified entries to outC.
+func (cf *certifier) run(ctx context.Context, reports <-chan report, outC chan<- *CertifiedEntry) {
+	for {
+		select {
+		case r, ok := <-reports:
+			if !ok {
+				return
+			}
+			for _, certified := range cf.apply(r) {
+				select {
+				case outC <- certified:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// apply registers an entry reported by a node and returns the entries that became certified as a result.
+func (cf *certifier) apply(r report) []*CertifiedEntry {
+	switch e := r.entry.Type.(type) {
+	case *ObservedEntry_Deliver:
+		if e.Deliver == nil || t.SeqNr(e.Deliver.Sn) < cf.nextSn {
+			return nil
+		}
+		addReport(cf.batches, t.SeqNr(e.Deliver.Sn), r)
+	case *ObservedEntry_Checkpoint:
+		if e.Checkpoint == nil || t.SeqNr(e.Checkpoint.Sn) < cf.nextSn {
+			return nil
+		}
+		addReport(cf.checkpoints, t.SeqNr(e.Checkpoint.Sn), r)
+	default:
+		cf.logger.Log(logging.LevelWarn, "Ignoring observed entry of unknown type.", "from", r.from)
+		return nil
+	}
+
+	certified := make([]*CertifiedEntry, 0)
+	for {
+		// Output the next batch, if certified.
+		if entry := cf.certify(cf.batches[cf.nextSn]); entry != nil {
+			certified = append(certified, entry)
+			delete(cf.batches, cf.nextSn)
+			cf.nextSn++
+			continue
+		}
+
+		// Output the lowest certified checkpoint.
+		// As a Server sends the committed batches before the checkpoint encompassing them,
+		// the nodes certifying a checkpoint that encompasses batches not yet output have not streamed those batches
+		// (they were committed before the Client connected). Such batches are skipped.
+		if sn, entry := cf.lowestCertifiedCheckpoint(); entry != nil {
+			if sn > cf.nextSn {
+				cf.logger.Log(logging.LevelInfo, "Skipping to certified checkpoint.", "from", cf.nextSn, "sn", sn)
+			}
+			certified = append(certified, entry)
+			cf.skipTo(sn)
+			continue
+		}
+
+		return certified
+	}
+}
+
+// lowestCertifiedCheckpoint returns the certified checkpoint with the lowest sequence number, if any,
+// along with its sequence number.
+func (cf *certifier) lowestCertifiedCheckpoint() (t.SeqNr, *CertifiedEntry) {
+	sns := make([]t.SeqNr, 0, len(cf.checkpoints))
+	for sn := range cf.checkpoints {
+		sns = append(sns, sn)
+	}
+	sort.Slice(sns, func(i, j int) bool { return sns[i] < sns[j] })
+
+	for _, sn := range sns {
+		if entry := cf.certify(cf.checkpoints[sn]); entry != nil {
+			return sn, entry
+		}
+	}
+	return 0, nil
+}
+
+// skipTo discards all batches below sn and all checkpoints up to sn, continuing with the batch at sn.
+func (cf *certifier) skipTo(sn t.SeqNr) {
+	for batchSn := range cf.batches {
+		if batchSn < sn {
+			delete(cf.batches, batchSn)
+		}
+	}
+	for checkpointSn := range cf.checkpoints {
+		if checkpointSn <= sn {
+			delete(cf.checkpoints, checkpointSn)
+		}
+	}
+	cf.nextSn = sn
+}
+
+// certify returns the entry reported identically by a threshold of nodes, if any, along with the reporting nodes.
+func (cf *certifier) certify(reports map[t.NodeID]*ObservedEntry) *CertifiedEntry {
+	if len(reports) < cf.threshold {
+		return nil
+	}
+
+	for _, candidate := range reports {
+		nodes := make([]t.NodeID, 0, len(reports))
+		for nodeID, entry := range reports {
+			if proto.Equal(entry, candidate) {
+				nodes = append(nodes, nodeID)
+			}
+		}
+		if len(nodes) >= cf.threshold {
+			sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+			return &CertifiedEntry{Entry: candidate, Nodes: nodes}
+		}
+	}
+	return nil
+}
+
+// addReport adds the entry reported by a node for a sequence number to the given reports.
+// Only the first entry reported by each node for each sequence number is considered.
+func addReport(reports map[t.SeqNr]map[t.NodeID]*ObservedEntry, sn t.SeqNr, r report) {
+	if reports[sn] == nil {
+		reports[sn] = make(map[t.NodeID]*ObservedEntry)
+	}
+	if _, ok := reports[sn][r.from]; !ok {
+		reports[sn][r.from] = r.entry
+	}
+}
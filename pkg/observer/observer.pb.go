@@ -0,0 +1,311 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: observer/observer.proto
+
+package observer
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	eventpb "github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// ObserveRequest starts observing the committed log from sequence number from_sn.
+// The observer authenticates with a signature over observer_id, from_sn, and timestamp,
+// produced with the key registered for the observer's (client) ID at the node.
+type ObserveRequest struct {
+	ObserverId           uint64   `protobuf:"varint,1,opt,name=observer_id,json=observerId,proto3" json:"observer_id,omitempty"`
+	FromSn               uint64   `protobuf:"varint,2,opt,name=from_sn,json=fromSn,proto3" json:"from_sn,omitempty"`
+	Timestamp            int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ObserveRequest) Reset()         { *m = ObserveRequest{} }
+func (m *ObserveRequest) String() string { return proto.CompactTextString(m) }
+func (*ObserveRequest) ProtoMessage()    {}
+func (*ObserveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3004233a4a5969ce, []int{0}
+}
+
+func (m *ObserveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ObserveRequest.Unmarshal(m, b)
+}
+func (m *ObserveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ObserveRequest.Marshal(b, m, deterministic)
+}
+func (m *ObserveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObserveRequest.Merge(m, src)
+}
+func (m *ObserveRequest) XXX_Size() int {
+	return xxx_messageInfo_ObserveRequest.Size(m)
+}
+func (m *ObserveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObserveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObserveRequest proto.InternalMessageInfo
+
+func (m *ObserveRequest) GetObserverId() uint64 {
+	if m != nil {
+		return m.ObserverId
+	}
+	return 0
+}
+
+func (m *ObserveRequest) GetFromSn() uint64 {
+	if m != nil {
+		return m.FromSn
+	}
+	return 0
+}
+
+func (m *ObserveRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *ObserveRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// ObservedEntry is either a committed batch or a stable checkpoint.
+type ObservedEntry struct {
+	// Types that are valid to be assigned to Type:
+	//	*ObservedEntry_Deliver
+	//	*ObservedEntry_Checkpoint
+	Type                 isObservedEntry_Type `protobuf_oneof:"type"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ObservedEntry) Reset()         { *m = ObservedEntry{} }
+func (m *ObservedEntry) String() string { return proto.CompactTextString(m) }
+func (*ObservedEntry) ProtoMessage()    {}
+func (*ObservedEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3004233a4a5969ce, []int{1}
+}
+
+func (m *ObservedEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ObservedEntry.Unmarshal(m, b)
+}
+func (m *ObservedEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ObservedEntry.Marshal(b, m, deterministic)
+}
+func (m *ObservedEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObservedEntry.Merge(m, src)
+}
+func (m *ObservedEntry) XXX_Size() int {
+	return xxx_messageInfo_ObservedEntry.Size(m)
+}
+func (m *ObservedEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObservedEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObservedEntry proto.InternalMessageInfo
+
+type isObservedEntry_Type interface {
+	isObservedEntry_Type()
+}
+
+type ObservedEntry_Deliver struct {
+	Deliver *eventpb.Deliver `protobuf:"bytes,1,opt,name=deliver,proto3,oneof"`
+}
+
+type ObservedEntry_Checkpoint struct {
+	Checkpoint *eventpb.CheckpointStable `protobuf:"bytes,2,opt,name=checkpoint,proto3,oneof"`
+}
+
+func (*ObservedEntry_Deliver) isObservedEntry_Type() {}
+
+func (*ObservedEntry_Checkpoint) isObservedEntry_Type() {}
+
+func (m *ObservedEntry) GetType() isObservedEntry_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *ObservedEntry) GetDeliver() *eventpb.Deliver {
+	if x, ok := m.GetType().(*ObservedEntry_Deliver); ok {
+		return x.Deliver
+	}
+	return nil
+}
+
+func (m *ObservedEntry) GetCheckpoint() *eventpb.CheckpointStable {
+	if x, ok := m.GetType().(*ObservedEntry_Checkpoint); ok {
+		return x.Checkpoint
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ObservedEntry) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ObservedEntry_Deliver)(nil),
+		(*ObservedEntry_Checkpoint)(nil),
+	}
+}
+
+func init() {
+	proto.RegisterType((*ObserveRequest)(nil), "observer.ObserveRequest")
+	proto.RegisterType((*ObservedEntry)(nil), "observer.ObservedEntry")
+}
+
+func init() { proto.RegisterFile("observer/observer.proto", fileDescriptor_3004233a4a5969ce) }
+
+var fileDescriptor_3004233a4a5969ce = []byte{
+	// 306 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xcf, 0x4f, 0xfa, 0x40,
+	0x10, 0xc5, 0xd9, 0x2f, 0x04, 0xf8, 0x0e, 0x6a, 0xcc, 0x26, 0x86, 0x4a, 0x4c, 0x24, 0x9c, 0x38,
+	0x68, 0xab, 0x78, 0x34, 0xf1, 0x80, 0x9a, 0xa0, 0x17, 0x93, 0x72, 0xf3, 0x42, 0xba, 0x74, 0x28,
+	0x1b, 0xfa, 0x63, 0xdd, 0x1d, 0x48, 0x7a, 0xf5, 0xe0, 0xdf, 0x6d, 0x68, 0xbb, 0x45, 0xe3, 0x69,
+	0x66, 0xde, 0x7b, 0x93, 0x7c, 0x32, 0x03, 0xfd, 0x4c, 0x18, 0xd4, 0x3b, 0xd4, 0x9e, 0x6d, 0x5c,
+	0xa5, 0x33, 0xca, 0x78, 0xd7, 0xce, 0x83, 0x33, 0xdc, 0x61, 0x4a, 0x4a, 0x78, 0x55, 0x2d, 0x03,
+	0xa3, 0x2f, 0x06, 0x27, 0x6f, 0x65, 0xc6, 0xc7, 0x8f, 0x2d, 0x1a, 0xe2, 0x97, 0xd0, 0xb3, 0x5b,
+	0x0b, 0x19, 0x3a, 0x6c, 0xc8, 0xc6, 0x2d, 0x1f, 0xac, 0xf4, 0x12, 0xf2, 0x3e, 0x74, 0x56, 0x3a,
+	0x4b, 0x16, 0x26, 0x75, 0xfe, 0x15, 0x66, 0x7b, 0x3f, 0xce, 0x53, 0x7e, 0x01, 0xff, 0x49, 0x26,
+	0x68, 0x28, 0x48, 0x94, 0xd3, 0x1c, 0xb2, 0x71, 0xd3, 0x3f, 0x08, 0x7b, 0xd7, 0xc8, 0x28, 0x0d,
+	0x68, 0xab, 0xd1, 0x69, 0x0d, 0xd9, 0xf8, 0xc8, 0x3f, 0x08, 0xa3, 0x4f, 0x06, 0xc7, 0x15, 0x48,
+	0xf8, 0x9c, 0x92, 0xce, 0xf9, 0x15, 0x74, 0x42, 0x8c, 0xe5, 0x0e, 0x75, 0xc1, 0xd0, 0x9b, 0x9c,
+	0xba, 0x96, 0xfd, 0xa9, 0xd4, 0x67, 0x0d, 0xdf, 0x46, 0xf8, 0x3d, 0xc0, 0x72, 0x8d, 0xcb, 0x8d,
+	0xca, 0x64, 0x4a, 0x05, 0x57, 0x6f, 0x72, 0x5e, 0x2f, 0x3c, 0xd6, 0xd6, 0x9c, 0x02, 0x11, 0xe3,
+	0xac, 0xe1, 0xff, 0x88, 0x4f, 0xdb, 0xd0, 0xa2, 0x5c, 0xe1, 0xe4, 0x15, 0xba, 0x15, 0x83, 0xe6,
+	0x0f, 0xd0, 0xa9, 0x7a, 0xee, 0xb8, 0xf5, 0x59, 0x7f, 0xdf, 0x6a, 0xd0, 0xff, 0xe3, 0x94, 0xf0,
+	0x37, 0x6c, 0x7a, 0xfb, 0xee, 0x45, 0x92, 0xd6, 0x5b, 0xe1, 0x2e, 0xb3, 0xc4, 0x5b, 0xe7, 0x0a,
+	0x75, 0x8c, 0x61, 0x84, 0xfa, 0x3a, 0x0e, 0x84, 0xf1, 0x12, 0xa9, 0xc5, 0x8a, 0x3c, 0xb5, 0x89,
+	0xea, 0x9f, 0x89, 0x76, 0xf1, 0x93, 0xbb, 0xef, 0x01, 0x00, 0x17, 0x7d, 0xa0, 0x8d, 0xcf, 0x01,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ObserverClient is the client API for Observer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ObserverClient interface {
+	Observe(ctx context.Context, in *ObserveRequest, opts ...grpc.CallOption) (Observer_ObserveClient, error)
+}
+
+type observerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewObserverClient(cc *grpc.ClientConn) ObserverClient {
+	return &observerClient{cc}
+}
+
+func (c *observerClient) Observe(ctx context.Context, in *ObserveRequest, opts ...grpc.CallOption) (Observer_ObserveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Observer_serviceDesc.Streams[0], "/observer.Observer/Observe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &observerObserveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Observer_ObserveClient interface {
+	Recv() (*ObservedEntry, error)
+	grpc.ClientStream
+}
+
+type observerObserveClient struct {
+	grpc.ClientStream
+}
+
+func (x *observerObserveClient) Recv() (*ObservedEntry, error) {
+	m := new(ObservedEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ObserverServer is the server API for Observer service.
+type ObserverServer interface {
+	Observe(*ObserveRequest, Observer_ObserveServer) error
+}
+
+// UnimplementedObserverServer can be embedded to have forward compatible implementations.
+type UnimplementedObserverServer struct {
+}
+
+func (*UnimplementedObserverServer) Observe(req *ObserveRequest, srv Observer_ObserveServer) error {
+	return status.Errorf(codes.Unimplemented, "method Observe not implemented")
+}
+
+func RegisterObserverServer(s *grpc.Server, srv ObserverServer) {
+	s.RegisterService(&_Observer_serviceDesc, srv)
+}
+
+func _Observer_Observe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ObserveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ObserverServer).Observe(m, &observerObserveServer{stream})
+}
+
+type Observer_ObserveServer interface {
+	Send(*ObservedEntry) error
+	grpc.ServerStream
+}
+
+type observerObserveServer struct {
+	grpc.ServerStream
+}
+
+func (x *observerObserveServer) Send(m *ObservedEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Observer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "observer.Observer",
+	HandlerType: (*ObserverServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Observe",
+			Handler:       _Observer_Observe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "observer/observer.proto",
+}
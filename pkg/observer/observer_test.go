@@ -0,0 +1,358 @@
+package observer_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/observer"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// fakeServer is an observer server that streams a fixed sequence of entries to each observer
+// and records the requests it received.
+type fakeServer struct {
+	entries    []*observer.ObservedEntry
+	requests   chan *observer.ObserveRequest
+	grpcServer *grpc.Server
+	addr       string
+}
+
+// startFakeServer starts a fakeServer streaming the given entries on a free local port.
+func startFakeServer(entries ...*observer.ObservedEntry) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+
+	fs := &fakeServer{
+		entries:    entries,
+		requests:   make(chan *observer.ObserveRequest, 1),
+		grpcServer: grpc.NewServer(),
+		addr:       listener.Addr().String(),
+	}
+	observer.RegisterObserverServer(fs.grpcServer, fs)
+	go func() {
+		_ = fs.grpcServer.Serve(listener)
+	}()
+	return fs
+}
+
+func (fs *fakeServer) Observe(request *observer.ObserveRequest, srv observer.Observer_ObserveServer) error {
+	fs.requests <- request
+	for _, entry := range fs.entries {
+		if err := srv.Send(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batch returns an observed batch committed at sequence number sn, containing a request with the given number.
+func batch(sn t.SeqNr, reqNo t.ReqNo) *observer.ObservedEntry {
+	return &observer.ObservedEntry{Type: &observer.ObservedEntry_Deliver{Deliver: &eventpb.Deliver{
+		Sn:    sn.Pb(),
+		Batch: &requestpb.Batch{Requests: []*requestpb.RequestRef{{ClientId: 0, ReqNo: reqNo.Pb()}}},
+	}}}
+}
+
+// checkpoint returns an observed stable checkpoint encompassing the batches below sn.
+func checkpoint(sn t.SeqNr) *observer.ObservedEntry {
+	return &observer.ObservedEntry{Type: &observer.ObservedEntry_Checkpoint{
+		Checkpoint: &eventpb.CheckpointStable{Sn: sn.Pb(), AppSnapshot: []byte{1}},
+	}}
+}
+
+var _ = Describe("Client", func() {
+	var (
+		membership   []t.NodeID
+		observerID   t.ClientID
+		client       *observer.Client
+		servers      []*fakeServer
+		ctx          context.Context
+		cancel       context.CancelFunc
+		certifiedC   <-chan *observer.CertifiedEntry
+		clientCrypto *mirCrypto.Crypto
+		mockClock    *clock.Mock
+	)
+
+	// observe starts a fake server for each given sequence of entries and observes them from fromSn.
+	observe := func(fromSn t.SeqNr, entries ...[]*observer.ObservedEntry) {
+		addrs := make(map[t.NodeID]string)
+		for i, nodeEntries := range entries {
+			servers = append(servers, startFakeServer(nodeEntries...))
+			addrs[t.NodeID(i)] = servers[i].addr
+		}
+		var err error
+		certifiedC, err = client.Observe(ctx, addrs, fromSn)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// certified returns all the entries output by the client until all streams ended.
+	certified := func() []*observer.CertifiedEntry {
+		entries := make([]*observer.CertifiedEntry, 0)
+		for entry := range certifiedC {
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	BeforeEach(func() {
+		membership = []t.NodeID{0, 1, 2, 3}
+		observerID = 100
+		var err error
+		clientCrypto, err = mirCrypto.ClientPseudo(membership, []t.ClientID{observerID}, observerID, mirCrypto.DefaultPseudoSeed)
+		Expect(err).NotTo(HaveOccurred())
+		client = observer.NewClient(observerID, clientCrypto, 2, logging.NilLogger)
+		mockClock = clock.NewMock(time.Unix(1000, 0))
+		client.SetClock(mockClock)
+		servers = nil
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	})
+
+	AfterEach(func() {
+		cancel()
+		for _, server := range servers {
+			server.grpcServer.Stop()
+		}
+	})
+
+	It("outputs the batches reported identically by a threshold of nodes, in order", func() {
+		observe(0,
+			[]*observer.ObservedEntry{batch(0, 0), batch(1, 1), batch(2, 2)},
+			[]*observer.ObservedEntry{batch(0, 0), batch(1, 1), batch(2, 2)},
+			[]*observer.ObservedEntry{batch(0, 0), batch(1, 100), batch(2, 2)},
+		)
+
+		entries := certified()
+		Expect(entries).To(HaveLen(3))
+		for i, entry := range entries {
+			Expect(entry.Entry.GetDeliver().Sn).To(Equal(uint64(i)))
+			Expect(entry.Entry.GetDeliver().Batch.Requests[0].ReqNo).To(Equal(uint64(i)))
+			Expect(len(entry.Nodes)).To(BeNumerically(">=", 2))
+		}
+		Expect(entries[1].Nodes).To(Equal([]t.NodeID{0, 1}))
+	})
+
+	It("does not output entries reported by less than a threshold of nodes", func() {
+		observe(0,
+			[]*observer.ObservedEntry{batch(0, 0), batch(1, 1)},
+			[]*observer.ObservedEntry{batch(0, 0), batch(1, 100)},
+		)
+
+		entries := certified()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Entry.GetDeliver().Sn).To(BeZero())
+	})
+
+	It("skips batches committed before it connected using a certified checkpoint", func() {
+		entries := []*observer.ObservedEntry{batch(5, 5), checkpoint(5), batch(6, 6)}
+		observe(0, entries, entries)
+
+		output := certified()
+		Expect(output).To(HaveLen(3))
+		Expect(output[0].Entry.GetCheckpoint().GetSn()).To(Equal(uint64(5)))
+		Expect(output[1].Entry.GetDeliver().GetSn()).To(Equal(uint64(5)))
+		Expect(output[2].Entry.GetDeliver().GetSn()).To(Equal(uint64(6)))
+	})
+
+	It("ignores entries below the requested sequence number", func() {
+		entries := []*observer.ObservedEntry{batch(0, 0), batch(1, 1), batch(2, 2)}
+		observe(1, entries, entries)
+
+		output := certified()
+		Expect(output).To(HaveLen(2))
+		Expect(output[0].Entry.GetDeliver().GetSn()).To(Equal(uint64(1)))
+	})
+
+	Describe("the observe request", func() {
+		var request *observer.ObserveRequest
+
+		// server returns a Server authenticating observers using the keys of the membership.
+		// Only requests the Server rejects can be tested without a Node.
+		server := func(observers ...t.ClientID) *observer.Server {
+			nodeCrypto, err := mirCrypto.NodePseudo(membership, []t.ClientID{observerID}, 0, mirCrypto.DefaultPseudoSeed)
+			Expect(err).NotTo(HaveOccurred())
+			s := observer.NewServer(nil, nodeCrypto, observers, logging.NilLogger)
+			s.SetClock(mockClock)
+			return s
+		}
+
+		// expectRejected expects the Server to reject the request as unauthenticated.
+		expectRejected := func(s *observer.Server, request *observer.ObserveRequest) {
+			err := s.Observe(request, nil)
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		}
+
+		BeforeEach(func() {
+			observe(3, nil)
+			Eventually(servers[0].requests).Should(Receive(&request))
+		})
+
+		It("identifies the observer and the first sequence number", func() {
+			Expect(request.ObserverId).To(Equal(observerID.Pb()))
+			Expect(request.FromSn).To(Equal(uint64(3)))
+			Expect(request.Signature).NotTo(BeEmpty())
+		})
+
+		It("is timestamped using the observer's clock", func() {
+			Expect(request.Timestamp).To(Equal(mockClock.Now().UnixNano()))
+		})
+
+		It("is rejected from observers that are not registered", func() {
+			expectRejected(server(observerID+1), request)
+		})
+
+		It("is rejected if tampered with", func() {
+			request.FromSn = 0
+			expectRejected(server(observerID), request)
+		})
+
+		It("is rejected if too old", func() {
+			request.Timestamp -= int64(time.Hour)
+			expectRejected(server(observerID), request)
+		})
+
+		It("is rejected if the clock of the server is too far ahead", func() {
+			mockClock.Advance(time.Hour)
+			expectRejected(server(observerID), request)
+		})
+	})
+})
+
+// The observer test runs a deployment in which every node streams its committed log to an observer.
+// The observer must only receive the batches and checkpoints certified by enough nodes,
+// and nodes must reject clients that are not registered as observers.
+var _ = Describe("Observer test", func() {
+
+	It("streams the certified log to an authenticated observer", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Register the observer's key at all nodes and start an observer server along each node.
+		observerID := t.ClientID(100)
+		membership := deployment.TestReplicas[0].Membership
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), observerID)
+		servers := make([]*observer.Server, len(deployment.TestReplicas))
+		serverErrs := make([]error, len(deployment.TestReplicas))
+		addrs := make(map[t.NodeID]string)
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica := replica
+			replica.ClientIDs = clientIDs
+			addrs[replica.Id] = fmt.Sprintf("127.0.0.1:%d", 30000+i)
+			replica.OnNode = func(node *mirbft.Node) {
+				cryptoModule, err := mirCrypto.NodePseudo(membership, clientIDs, replica.Id, mirCrypto.DefaultPseudoSeed)
+				if err != nil {
+					serverErrs[i] = err
+					return
+				}
+				servers[i] = observer.NewServer(node, cryptoModule, []t.ClientID{observerID}, nil)
+				serverErrs[i] = servers[i].Start(30000 + i)
+			}
+		}
+
+		// Observe the log with the registered observer (requiring f+1 matching reports),
+		// as well as with a client not registered as an observer.
+		observerCrypto, err := mirCrypto.ClientPseudo(membership, clientIDs, observerID, mirCrypto.DefaultPseudoSeed)
+		Expect(err).NotTo(HaveOccurred())
+		intruderCrypto, err := mirCrypto.ClientPseudo(membership, clientIDs, 0, mirCrypto.DefaultPseudoSeed)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Once the nodes are running, observe the log until the observer received a certified checkpoint.
+		var certified []*observer.CertifiedEntry
+		var rejected []*observer.CertifiedEntry
+		var observeErr, intruderErr error
+		defer func() {
+			for _, server := range servers {
+				if server != nil {
+					server.Stop()
+				}
+			}
+		}()
+		finalStatuses := deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 0), func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var entries, intruderEntries <-chan *observer.CertifiedEntry
+			entries, observeErr = observer.NewClient(observerID, observerCrypto, 2, nil).Observe(ctx, addrs, 0)
+			Expect(observeErr).NotTo(HaveOccurred())
+			intruderEntries, intruderErr = observer.NewClient(0, intruderCrypto, 2, nil).Observe(ctx, addrs, 0)
+			Expect(intruderErr).NotTo(HaveOccurred())
+			intruderDone := make(chan struct{})
+			go func() {
+				defer close(intruderDone)
+				for entry := range intruderEntries {
+					rejected = append(rejected, entry)
+				}
+			}()
+
+			Eventually(func() bool {
+				for {
+					select {
+					case entry, ok := <-entries:
+						if !ok {
+							return false
+						}
+						certified = append(certified, entry)
+					default:
+						for _, entry := range certified {
+							if _, ok := entry.Entry.Type.(*observer.ObservedEntry_Checkpoint); ok {
+								return true
+							}
+						}
+						return false
+					}
+				}
+			}, deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+			cancel()
+			<-intruderDone
+		})
+
+		Expect(observeErr).NotTo(HaveOccurred())
+		Expect(intruderErr).NotTo(HaveOccurred())
+		for i, finalStatus := range finalStatuses {
+			Expect(serverErrs[i]).NotTo(HaveOccurred())
+			Expect(finalStatus.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		// The unregistered client was rejected by all nodes.
+		Expect(rejected).To(BeEmpty())
+
+		// The observer received consecutive batches, only skipping batches committed before it connected
+		// by continuing at a certified checkpoint, and all entries were reported by at least f+1 nodes.
+		numCheckpoints := 0
+		nextSn := uint64(0)
+		for _, entry := range certified {
+			Expect(len(entry.Nodes)).To(BeNumerically(">=", 2))
+			switch e := entry.Entry.Type.(type) {
+			case *observer.ObservedEntry_Deliver:
+				Expect(e.Deliver.Sn).To(Equal(nextSn))
+				nextSn++
+			case *observer.ObservedEntry_Checkpoint:
+				Expect(e.Checkpoint.Sn).To(BeNumerically(">=", nextSn))
+				nextSn = e.Checkpoint.Sn
+				numCheckpoints++
+			}
+		}
+		Expect(numCheckpoints).To(BeNumerically(">", 0))
+		Expect(nextSn).To(BeNumerically(">", 0))
+	})
+})
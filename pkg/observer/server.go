@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package observer lets read-only observers (e.g. audit systems) follow the log committed by the nodes
+// without being able to influence the ordering.
+// The Server runs along a Node and streams the batches the Node commits, as well as its stable checkpoints,
+// to authenticated observers over gRPC.
+// The Client connects to multiple Servers and outputs only the entries reported identically by enough nodes
+// for at least one of them to be correct, such that the observer does not need to trust any single node.
+package observer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Maximal difference between the timestamp of an ObserveRequest and the local time of the Server.
+	// Requests outside of this window are rejected, which prevents replaying captured requests later.
+	maxRequestAge = time.Minute
+
+	// Interval in which the Server checks the Node for a new stable checkpoint to send to the observers.
+	checkpointPollInterval = 100 * time.Millisecond
+)
+
+// Server streams the log committed by a Node to the observers connecting to it.
+type Server struct {
+
+	// The Node whose committed log is streamed.
+	node *mirbft.Node
+
+	// The Crypto module used for verifying the signatures of the observers.
+	// The observers authenticate with the keys registered for their (client) IDs.
+	crypto modules.Crypto
+
+	// The IDs of the observers allowed to connect.
+	observers map[t.ClientID]struct{}
+
+	// The gRPC server used by this Server.
+	grpcServer *grpc.Server
+
+	// Error returned from the grpcServer.Serve() call (see Start() method).
+	grpcServerError error
+
+	// Source of time for checking the age of the observers' requests and polling the stable checkpoints.
+	clock clock.Clock
+
+	// Logger use for all logging events of this Server.
+	logger logging.Logger
+}
+
+// NewServer returns a new Server streaming the log committed by node to the given observers,
+// authenticated using the given Crypto module.
+// The returned Server is not yet running. This needs to be done explicitly by calling the Start() method.
+// For observers to receive the whole log, the Server must be started before the Node.
+func NewServer(node *mirbft.Node, crypto modules.Crypto, observers []t.ClientID, logger logging.Logger) *Server {
+	// If no logger was given, only write errors to the console.
+	if logger == nil {
+		logger = logging.ConsoleErrorLogger
+	}
+
+	observerSet := make(map[t.ClientID]struct{}, len(observers))
+	for _, observerID := range observers {
+		observerSet[observerID] = struct{}{}
+	}
+
+	return &Server{
+		node:      node,
+		crypto:    crypto,
+		observers: observerSet,
+		clock:     clock.System,
+		logger:    logger,
+	}
+}
+
+// SetClock makes the Server check the age of the observers' requests and poll the stable checkpoints using c
+// instead of the system clock. It must be called before the Server is started.
+func (s *Server) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Observe implements the gRPC Observe service (single-request-multi-response).
+// After authenticating the observer, it streams the batches the Node commits from now on
+// (starting at the requested sequence number at the earliest), interleaved with the Node's stable checkpoints.
+// The stream ends when the observer disconnects or the Node stops.
+func (s *Server) Observe(request *ObserveRequest, srv Observer_ObserveServer) error {
+	if err := s.authenticate(request); err != nil {
+		s.logger.Log(logging.LevelWarn, "Rejecting observer.", "observerID", request.ObserverId, "error", err)
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	s.logger.Log(logging.LevelInfo, "Observer connected.", "observerID", request.ObserverId, "fromSn", request.FromSn)
+
+	committed := s.node.Committed(srv.Context(), t.SeqNr(request.FromSn))
+	ticker := s.clock.NewTicker(checkpointPollInterval)
+	defer ticker.Stop()
+
+	// The sequence number following the last batch sent. Only valid if a batch has already been sent.
+	sentBatch := false
+	var nextSn uint64
+
+	// Send the latest stable checkpoint, unless already sent.
+	// The checkpoint is only sent after all the batches it encompasses (that are streamed at all),
+	// as the Client relies on this order for detecting batches committed before the observer connected.
+	sentCheckpoint := false
+	var lastCheckpointSn uint64
+	sendCheckpoint := func() error {
+		checkpoint := s.node.StableCheckpoint()
+		if checkpoint == nil || checkpoint.Sn < request.FromSn || (sentCheckpoint && checkpoint.Sn <= lastCheckpointSn) {
+			return nil
+		}
+		if !sentBatch || checkpoint.Sn > nextSn {
+			return nil
+		}
+		sentCheckpoint = true
+		lastCheckpointSn = checkpoint.Sn
+		return srv.Send(&ObservedEntry{Type: &ObservedEntry_Checkpoint{Checkpoint: checkpoint}})
+	}
+
+	for {
+		select {
+		case deliver, ok := <-committed:
+			if !ok {
+				return nil
+			}
+			if err := srv.Send(&ObservedEntry{Type: &ObservedEntry_Deliver{Deliver: deliver}}); err != nil {
+				return err
+			}
+			sentBatch = true
+			nextSn = deliver.Sn + 1
+			if err := sendCheckpoint(); err != nil {
+				return err
+			}
+		case <-ticker.C():
+			if err := sendCheckpoint(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// authenticate returns an error if the observer sending the request is not allowed to connect,
+// if the request is too old (or too far in the future), or if its signature is invalid.
+func (s *Server) authenticate(request *ObserveRequest) error {
+	observerID := t.ClientID(request.ObserverId)
+	if _, ok := s.observers[observerID]; !ok {
+		return fmt.Errorf("unknown observer: %d", observerID)
+	}
+
+	age := s.clock.Since(time.Unix(0, request.Timestamp))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("request timestamp out of range (age: %v)", age)
+	}
+
+	if err := s.crypto.VerifyClientSig(requestData(request), request.Signature, observerID); err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	return nil
+}
+
+// Start starts the Server by initializing and starting the internal gRPC server, listening on the passed port.
+// Before ths method is called, no observer connections are accepted.
+func (s *Server) Start(port int) error {
+
+	s.logger.Log(logging.LevelInfo, fmt.Sprintf("Listening for observer connections on port %d", port))
+
+	// Create a gRPC server and assign it the logic of this Server.
+	s.grpcServer = grpc.NewServer()
+	RegisterObserverServer(s.grpcServer, s)
+
+	// Start listening on the network
+	conn, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return fmt.Errorf("failed to listen for connections on port %d: %w", port, err)
+	}
+
+	// Start the gRPC server in a separate goroutine.
+	// When the server stops, it will write its exit error into s.grpcServerError.
+	go func() {
+		s.grpcServerError = s.grpcServer.Serve(conn)
+	}()
+
+	// If we got all the way here, no error occurred.
+	return nil
+}
+
+// Stop stops the gRPC server, closing the connections to all observers.
+// After Stop() returns, the error returned by the gRPC server's Serve() call
+// can be obtained through the ServerError() method.
+func (s *Server) Stop() {
+
+	s.logger.Log(logging.LevelDebug, "Stopping observer server.")
+
+	s.grpcServer.Stop()
+
+	s.logger.Log(logging.LevelDebug, "Observer server stopped.")
+}
+
+// ServerError returns the error returned by the gRPC server's Serve() call.
+// ServerError() must not be called before the Server is stopped and its Stop() method has returned.
+func (s *Server) ServerError() error {
+	return s.grpcServerError
+}
+
+// requestData returns the data the observer signs when creating an ObserveRequest.
+func requestData(request *ObserveRequest) [][]byte {
+	var data [24]byte
+	binary.BigEndian.PutUint64(data[:8], request.ObserverId)
+	binary.BigEndian.PutUint64(data[8:16], request.FromSn)
+	binary.BigEndian.PutUint64(data[16:], uint64(request.Timestamp))
+	return [][]byte{data[:]}
+}
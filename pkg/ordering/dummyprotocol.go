@@ -65,7 +65,7 @@ func (dp *DummyProtocol) ApplyEvent(event *eventpb.Event) *events.EventList {
 	switch e := event.Type.(type) {
 	case *eventpb.Event_PersistDummyBatch:
 		dp.logger.Log(logging.LevelDebug, "Loading dummy batch from WAL.")
-	case *eventpb.Event_Tick:
+	case *eventpb.Event_Tick, *eventpb.Event_PeerHealth, *eventpb.Event_StepDown, *eventpb.Event_LocalParams:
 		// Do nothing in the dummy SM.
 	case *eventpb.Event_RequestReady:
 		return dp.handleRequest(e.RequestReady.RequestRef)
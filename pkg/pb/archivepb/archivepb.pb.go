@@ -0,0 +1,221 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: archivepb/archivepb.proto
+
+package archivepb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	eventpb "github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	isspb "github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	requestpb "github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// StateArchive holds the complete durable state of a node, as produced by mirbft.ExportState.
+type StateArchive struct {
+	StableCheckpoint     *isspb.StableCheckpoint    `protobuf:"bytes,1,opt,name=stable_checkpoint,json=stableCheckpoint,proto3" json:"stable_checkpoint,omitempty"`
+	WalEntries           []*WALEntry                `protobuf:"bytes,2,rep,name=wal_entries,json=walEntries,proto3" json:"wal_entries,omitempty"`
+	Requests             []*requestpb.StoredRequest `protobuf:"bytes,3,rep,name=requests,proto3" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *StateArchive) Reset()         { *m = StateArchive{} }
+func (m *StateArchive) String() string { return proto.CompactTextString(m) }
+func (*StateArchive) ProtoMessage()    {}
+func (*StateArchive) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb86d657e00f737b, []int{0}
+}
+
+func (m *StateArchive) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateArchive.Unmarshal(m, b)
+}
+func (m *StateArchive) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateArchive.Marshal(b, m, deterministic)
+}
+func (m *StateArchive) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateArchive.Merge(m, src)
+}
+func (m *StateArchive) XXX_Size() int {
+	return xxx_messageInfo_StateArchive.Size(m)
+}
+func (m *StateArchive) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateArchive.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateArchive proto.InternalMessageInfo
+
+func (m *StateArchive) GetStableCheckpoint() *isspb.StableCheckpoint {
+	if m != nil {
+		return m.StableCheckpoint
+	}
+	return nil
+}
+
+func (m *StateArchive) GetWalEntries() []*WALEntry {
+	if m != nil {
+		return m.WalEntries
+	}
+	return nil
+}
+
+func (m *StateArchive) GetRequests() []*requestpb.StoredRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+type WALEntry struct {
+	RetentionIndex       uint64         `protobuf:"varint,1,opt,name=retention_index,json=retentionIndex,proto3" json:"retention_index,omitempty"`
+	Event                *eventpb.Event `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *WALEntry) Reset()         { *m = WALEntry{} }
+func (m *WALEntry) String() string { return proto.CompactTextString(m) }
+func (*WALEntry) ProtoMessage()    {}
+func (*WALEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb86d657e00f737b, []int{1}
+}
+
+func (m *WALEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WALEntry.Unmarshal(m, b)
+}
+func (m *WALEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WALEntry.Marshal(b, m, deterministic)
+}
+func (m *WALEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WALEntry.Merge(m, src)
+}
+func (m *WALEntry) XXX_Size() int {
+	return xxx_messageInfo_WALEntry.Size(m)
+}
+func (m *WALEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_WALEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WALEntry proto.InternalMessageInfo
+
+func (m *WALEntry) GetRetentionIndex() uint64 {
+	if m != nil {
+		return m.RetentionIndex
+	}
+	return 0
+}
+
+func (m *WALEntry) GetEvent() *eventpb.Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+// CleanStopMarker is persisted by a node that stopped gracefully (see NodeConfig.CleanStopFile).
+// It identifies the tail of the WAL at the time the node stopped,
+// at which point all requests referenced by the WAL are known to be persisted in the request store.
+type CleanStopMarker struct {
+	WalLength            uint64   `protobuf:"varint,1,opt,name=wal_length,json=walLength,proto3" json:"wal_length,omitempty"`
+	TailRetentionIndex   uint64   `protobuf:"varint,2,opt,name=tail_retention_index,json=tailRetentionIndex,proto3" json:"tail_retention_index,omitempty"`
+	TailDigest           []byte   `protobuf:"bytes,3,opt,name=tail_digest,json=tailDigest,proto3" json:"tail_digest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CleanStopMarker) Reset()         { *m = CleanStopMarker{} }
+func (m *CleanStopMarker) String() string { return proto.CompactTextString(m) }
+func (*CleanStopMarker) ProtoMessage()    {}
+func (*CleanStopMarker) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb86d657e00f737b, []int{2}
+}
+
+func (m *CleanStopMarker) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CleanStopMarker.Unmarshal(m, b)
+}
+func (m *CleanStopMarker) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CleanStopMarker.Marshal(b, m, deterministic)
+}
+func (m *CleanStopMarker) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CleanStopMarker.Merge(m, src)
+}
+func (m *CleanStopMarker) XXX_Size() int {
+	return xxx_messageInfo_CleanStopMarker.Size(m)
+}
+func (m *CleanStopMarker) XXX_DiscardUnknown() {
+	xxx_messageInfo_CleanStopMarker.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CleanStopMarker proto.InternalMessageInfo
+
+func (m *CleanStopMarker) GetWalLength() uint64 {
+	if m != nil {
+		return m.WalLength
+	}
+	return 0
+}
+
+func (m *CleanStopMarker) GetTailRetentionIndex() uint64 {
+	if m != nil {
+		return m.TailRetentionIndex
+	}
+	return 0
+}
+
+func (m *CleanStopMarker) GetTailDigest() []byte {
+	if m != nil {
+		return m.TailDigest
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*StateArchive)(nil), "archivepb.StateArchive")
+	proto.RegisterType((*WALEntry)(nil), "archivepb.WALEntry")
+	proto.RegisterType((*CleanStopMarker)(nil), "archivepb.CleanStopMarker")
+}
+
+func init() { proto.RegisterFile("archivepb/archivepb.proto", fileDescriptor_bb86d657e00f737b) }
+
+var fileDescriptor_bb86d657e00f737b = []byte{
+	// 375 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0x41, 0x6f, 0xd3, 0x30,
+	0x1c, 0xc5, 0x95, 0x16, 0xd0, 0xe6, 0x4c, 0x1b, 0x33, 0x20, 0xb2, 0x49, 0x88, 0xaa, 0x42, 0xa2,
+	0x17, 0x12, 0xb4, 0x8d, 0x0f, 0x50, 0xda, 0x1e, 0x90, 0xca, 0xc5, 0x39, 0x20, 0xb8, 0x44, 0x76,
+	0xf2, 0x27, 0xb1, 0xea, 0x3a, 0xc6, 0xfe, 0xb7, 0xa5, 0x67, 0xbe, 0x18, 0x1f, 0x0d, 0xc5, 0x49,
+	0x53, 0xd6, 0x4b, 0xec, 0xfc, 0xde, 0xf3, 0xd3, 0xf3, 0x3f, 0x21, 0x37, 0xdc, 0xe6, 0x95, 0xdc,
+	0x82, 0x11, 0x49, 0xbf, 0x8b, 0x8d, 0xad, 0xb1, 0xa6, 0xe7, 0x3d, 0xb8, 0x7d, 0x05, 0x5b, 0xd0,
+	0x68, 0x44, 0xd2, 0xad, 0xad, 0xe3, 0xf6, 0x5a, 0x3a, 0x67, 0x44, 0xe2, 0x9f, 0x1d, 0xba, 0xb1,
+	0xf0, 0x6b, 0x03, 0xae, 0xf1, 0xf6, 0xbb, 0x56, 0x1a, 0xff, 0x0d, 0xc8, 0x45, 0x8a, 0x1c, 0x61,
+	0xda, 0xe6, 0xd2, 0x39, 0xb9, 0x76, 0xc8, 0x85, 0x82, 0x2c, 0xaf, 0x20, 0x5f, 0x99, 0x5a, 0x6a,
+	0x8c, 0x82, 0x51, 0x30, 0x09, 0xef, 0x5e, 0xc7, 0x6d, 0x68, 0xea, 0xf5, 0x59, 0x2f, 0xb3, 0xe7,
+	0xee, 0x84, 0xd0, 0x07, 0x12, 0xee, 0xb8, 0xca, 0x40, 0xa3, 0x95, 0xe0, 0xa2, 0xc1, 0x68, 0x38,
+	0x09, 0xef, 0x5e, 0xc4, 0xc7, 0xdb, 0x7c, 0x9b, 0x2e, 0x17, 0x1a, 0xed, 0x9e, 0x91, 0x1d, 0x57,
+	0x8b, 0xd6, 0x46, 0x1f, 0xc8, 0x59, 0xd7, 0xcf, 0x45, 0x43, 0x7f, 0x24, 0x8a, 0x8f, 0x85, 0x53,
+	0xac, 0x2d, 0x14, 0xac, 0x7d, 0x67, 0xbd, 0x73, 0xfc, 0x9d, 0x9c, 0x1d, 0xd2, 0xe8, 0x7b, 0x72,
+	0x65, 0x01, 0x41, 0xa3, 0xac, 0x75, 0x26, 0x75, 0x01, 0xbf, 0x7d, 0xf7, 0x27, 0xec, 0xb2, 0xc7,
+	0x5f, 0x1a, 0x4a, 0xdf, 0x91, 0xa7, 0x7e, 0x6c, 0xd1, 0xc0, 0x5f, 0xed, 0x32, 0x3e, 0x0c, 0x71,
+	0xd1, 0xac, 0xac, 0x15, 0xc7, 0x7f, 0x02, 0x72, 0x35, 0x53, 0xc0, 0x75, 0x8a, 0xb5, 0xf9, 0xca,
+	0xed, 0x0a, 0x2c, 0x7d, 0x43, 0x9a, 0xca, 0x99, 0x02, 0x5d, 0x62, 0xd5, 0xa5, 0x9f, 0xef, 0xb8,
+	0x5a, 0x7a, 0x40, 0x3f, 0x92, 0x97, 0xc8, 0xa5, 0xca, 0x4e, 0x6b, 0x0c, 0xbc, 0x91, 0x36, 0x1a,
+	0x7b, 0x5c, 0xe5, 0x2d, 0x09, 0xfd, 0x89, 0x42, 0x96, 0xe0, 0x30, 0x1a, 0x8e, 0x82, 0xc9, 0x05,
+	0x23, 0x0d, 0x9a, 0x7b, 0xf2, 0xf9, 0xd3, 0x8f, 0xfb, 0x52, 0x62, 0xb5, 0x11, 0x71, 0x5e, 0xaf,
+	0x93, 0x6a, 0x6f, 0xc0, 0x2a, 0x28, 0x4a, 0xb0, 0x1f, 0x14, 0x17, 0x2e, 0x59, 0x4b, 0x2b, 0x7e,
+	0x62, 0x62, 0x56, 0x65, 0xf2, 0xff, 0x0f, 0x23, 0x9e, 0xf9, 0x2f, 0x7c, 0xff, 0x6f, 0x00, 0x41,
+	0x18, 0x15, 0x38, 0x4e, 0x02, 0x00, 0x00,
+}
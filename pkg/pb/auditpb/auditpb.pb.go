@@ -0,0 +1,128 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: auditpb/auditpb.proto
+
+package auditpb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	eventpb "github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Record is an entry of the audit log of a node (see the auditlog package).
+// Each record describes one notable protocol event, along with the context in which the node observed it.
+type Record struct {
+	Index                uint64                `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	NodeId               uint64                `protobuf:"varint,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Timestamp            int64                 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Epoch                uint64                `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	DeliveredSn          uint64                `protobuf:"varint,5,opt,name=delivered_sn,json=deliveredSn,proto3" json:"delivered_sn,omitempty"`
+	Event                *eventpb.Notification `protobuf:"bytes,6,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+func (*Record) Descriptor() ([]byte, []int) {
+	return fileDescriptor_df601f0d2b0cd7b7, []int{0}
+}
+
+func (m *Record) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Record.Unmarshal(m, b)
+}
+func (m *Record) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Record.Marshal(b, m, deterministic)
+}
+func (m *Record) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Record.Merge(m, src)
+}
+func (m *Record) XXX_Size() int {
+	return xxx_messageInfo_Record.Size(m)
+}
+func (m *Record) XXX_DiscardUnknown() {
+	xxx_messageInfo_Record.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Record proto.InternalMessageInfo
+
+func (m *Record) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Record) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *Record) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Record) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *Record) GetDeliveredSn() uint64 {
+	if m != nil {
+		return m.DeliveredSn
+	}
+	return 0
+}
+
+func (m *Record) GetEvent() *eventpb.Notification {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Record)(nil), "auditpb.Record")
+}
+
+func init() { proto.RegisterFile("auditpb/auditpb.proto", fileDescriptor_df601f0d2b0cd7b7) }
+
+var fileDescriptor_df601f0d2b0cd7b7 = []byte{
+	// 238 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x90, 0xb1, 0x4e, 0xf3, 0x30,
+	0x14, 0x85, 0xe5, 0xbf, 0x4d, 0xaa, 0xdf, 0x65, 0xb2, 0x88, 0xb0, 0x10, 0x43, 0x60, 0x8a, 0x84,
+	0x88, 0x05, 0x7d, 0x03, 0x36, 0x16, 0x86, 0xb0, 0xb1, 0x54, 0x71, 0xee, 0x6d, 0x72, 0x45, 0x62,
+	0x5b, 0x8e, 0x5b, 0xc1, 0xb3, 0xf1, 0x72, 0x28, 0x4e, 0xa0, 0xd3, 0xd1, 0x77, 0x8e, 0x75, 0x74,
+	0x7c, 0x79, 0x56, 0x1f, 0x81, 0x82, 0xd3, 0x6a, 0xd1, 0xd2, 0x79, 0x1b, 0xac, 0xd8, 0x2c, 0x78,
+	0x9d, 0xe1, 0x09, 0xcd, 0x94, 0x2f, 0x3a, 0xe7, 0x77, 0xdf, 0x8c, 0xa7, 0x15, 0x36, 0xd6, 0x83,
+	0xb8, 0xe4, 0x09, 0x19, 0xc0, 0x4f, 0xc9, 0x72, 0x56, 0xac, 0xab, 0x19, 0xc4, 0x15, 0xdf, 0x18,
+	0x0b, 0xb8, 0x27, 0x90, 0xff, 0xa2, 0x9f, 0x4e, 0xf8, 0x02, 0xe2, 0x86, 0xff, 0x0f, 0x34, 0xe0,
+	0x18, 0xea, 0xc1, 0xc9, 0x55, 0xce, 0x8a, 0x55, 0x75, 0x36, 0xa6, 0x32, 0x74, 0xb6, 0xe9, 0xe4,
+	0x7a, 0x2e, 0x8b, 0x20, 0x6e, 0xf9, 0x05, 0x60, 0x4f, 0x27, 0xf4, 0x08, 0xfb, 0xd1, 0xc8, 0x24,
+	0x86, 0xdb, 0x3f, 0xef, 0xcd, 0x88, 0x7b, 0x9e, 0xc4, 0x85, 0x32, 0xcd, 0x59, 0xb1, 0x7d, 0xca,
+	0xca, 0xdf, 0xbd, 0xaf, 0x36, 0xd0, 0x81, 0x9a, 0x3a, 0x90, 0x35, 0xd5, 0xfc, 0xe6, 0x79, 0xf7,
+	0xfe, 0xd8, 0x52, 0xe8, 0x8e, 0xba, 0x6c, 0xec, 0xa0, 0xba, 0x2f, 0x87, 0xbe, 0x47, 0x68, 0xd1,
+	0x3f, 0xf4, 0xb5, 0x1e, 0xd5, 0x40, 0x5e, 0x1f, 0x82, 0x72, 0x1f, 0xad, 0x3a, 0x1f, 0x46, 0xa7,
+	0xf1, 0xe7, 0xbb, 0x9f, 0x01, 0x00, 0x5a, 0xa7, 0x17, 0x6a, 0x32, 0x01, 0x00, 0x00,
+}
@@ -46,6 +46,23 @@ type Event struct {
 	//	*Event_StoreVerifiedRequest
 	//	*Event_AppSnapshotRequest
 	//	*Event_AppSnapshot
+	//	*Event_AppRestoreState
+	//	*Event_PoisonedBatch
+	//	*Event_PruneRequests
+	//	*Event_ForwardRequests
+	//	*Event_RequestTooLarge
+	//	*Event_PeerHealth
+	//	*Event_ForwardedRequest
+	//	*Event_SendQueueOverflow
+	//	*Event_MessageTooLarge
+	//	*Event_Notification
+	//	*Event_AppQuery
+	//	*Event_AppQueryResult
+	//	*Event_UpdateClientKeys
+	//	*Event_UpdateNodeKeys
+	//	*Event_RetireNodeKeys
+	//	*Event_StepDown
+	//	*Event_LocalParams
 	//	*Event_PersistDummyBatch
 	//	*Event_AnnounceDummyBatch
 	//	*Event_StoreDummyRequest
@@ -56,7 +73,11 @@ type Event struct {
 	// after the corresponding entry has been persisted in the write-ahead log (WAL).
 	// In this case, the WAL append event would be this event
 	// and the next field would contain the message sending event.
-	Next                 []*Event `protobuf:"bytes,100,rep,name=next,proto3" json:"next,omitempty"`
+	Next []*Event `protobuf:"bytes,100,rep,name=next,proto3" json:"next,omitempty"`
+	// If causality tracing (see package causality) is enabled,
+	// the causal ID of the received message this event (directly or indirectly) resulted from.
+	// Zero if the event is not the result of a traced message.
+	CausalId             uint64   `protobuf:"varint,200,opt,name=causal_id,json=causalId,proto3" json:"causal_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -163,6 +184,74 @@ type Event_AppSnapshot struct {
 	AppSnapshot *AppSnapshot `protobuf:"bytes,18,opt,name=app_snapshot,json=appSnapshot,proto3,oneof"`
 }
 
+type Event_AppRestoreState struct {
+	AppRestoreState *AppRestoreState `protobuf:"bytes,19,opt,name=app_restore_state,json=appRestoreState,proto3,oneof"`
+}
+
+type Event_PoisonedBatch struct {
+	PoisonedBatch *PoisonedBatch `protobuf:"bytes,20,opt,name=poisoned_batch,json=poisonedBatch,proto3,oneof"`
+}
+
+type Event_PruneRequests struct {
+	PruneRequests *PruneRequests `protobuf:"bytes,21,opt,name=prune_requests,json=pruneRequests,proto3,oneof"`
+}
+
+type Event_ForwardRequests struct {
+	ForwardRequests *ForwardRequests `protobuf:"bytes,22,opt,name=forward_requests,json=forwardRequests,proto3,oneof"`
+}
+
+type Event_RequestTooLarge struct {
+	RequestTooLarge *RequestTooLarge `protobuf:"bytes,23,opt,name=request_too_large,json=requestTooLarge,proto3,oneof"`
+}
+
+type Event_PeerHealth struct {
+	PeerHealth *PeerHealth `protobuf:"bytes,24,opt,name=peer_health,json=peerHealth,proto3,oneof"`
+}
+
+type Event_ForwardedRequest struct {
+	ForwardedRequest *ForwardedRequest `protobuf:"bytes,25,opt,name=forwarded_request,json=forwardedRequest,proto3,oneof"`
+}
+
+type Event_SendQueueOverflow struct {
+	SendQueueOverflow *SendQueueOverflow `protobuf:"bytes,26,opt,name=send_queue_overflow,json=sendQueueOverflow,proto3,oneof"`
+}
+
+type Event_MessageTooLarge struct {
+	MessageTooLarge *MessageTooLarge `protobuf:"bytes,27,opt,name=message_too_large,json=messageTooLarge,proto3,oneof"`
+}
+
+type Event_Notification struct {
+	Notification *Notification `protobuf:"bytes,28,opt,name=notification,proto3,oneof"`
+}
+
+type Event_AppQuery struct {
+	AppQuery *AppQuery `protobuf:"bytes,29,opt,name=app_query,json=appQuery,proto3,oneof"`
+}
+
+type Event_AppQueryResult struct {
+	AppQueryResult *AppQueryResult `protobuf:"bytes,30,opt,name=app_query_result,json=appQueryResult,proto3,oneof"`
+}
+
+type Event_UpdateClientKeys struct {
+	UpdateClientKeys *UpdateClientKeys `protobuf:"bytes,31,opt,name=update_client_keys,json=updateClientKeys,proto3,oneof"`
+}
+
+type Event_UpdateNodeKeys struct {
+	UpdateNodeKeys *UpdateNodeKeys `protobuf:"bytes,32,opt,name=update_node_keys,json=updateNodeKeys,proto3,oneof"`
+}
+
+type Event_RetireNodeKeys struct {
+	RetireNodeKeys *RetireNodeKeys `protobuf:"bytes,33,opt,name=retire_node_keys,json=retireNodeKeys,proto3,oneof"`
+}
+
+type Event_StepDown struct {
+	StepDown *StepDown `protobuf:"bytes,34,opt,name=step_down,json=stepDown,proto3,oneof"`
+}
+
+type Event_LocalParams struct {
+	LocalParams *LocalParams `protobuf:"bytes,35,opt,name=local_params,json=localParams,proto3,oneof"`
+}
+
 type Event_PersistDummyBatch struct {
 	PersistDummyBatch *PersistDummyBatch `protobuf:"bytes,101,opt,name=persist_dummy_batch,json=persistDummyBatch,proto3,oneof"`
 }
@@ -211,6 +300,40 @@ func (*Event_AppSnapshotRequest) isEvent_Type() {}
 
 func (*Event_AppSnapshot) isEvent_Type() {}
 
+func (*Event_AppRestoreState) isEvent_Type() {}
+
+func (*Event_PoisonedBatch) isEvent_Type() {}
+
+func (*Event_PruneRequests) isEvent_Type() {}
+
+func (*Event_ForwardRequests) isEvent_Type() {}
+
+func (*Event_RequestTooLarge) isEvent_Type() {}
+
+func (*Event_PeerHealth) isEvent_Type() {}
+
+func (*Event_ForwardedRequest) isEvent_Type() {}
+
+func (*Event_SendQueueOverflow) isEvent_Type() {}
+
+func (*Event_MessageTooLarge) isEvent_Type() {}
+
+func (*Event_Notification) isEvent_Type() {}
+
+func (*Event_AppQuery) isEvent_Type() {}
+
+func (*Event_AppQueryResult) isEvent_Type() {}
+
+func (*Event_UpdateClientKeys) isEvent_Type() {}
+
+func (*Event_UpdateNodeKeys) isEvent_Type() {}
+
+func (*Event_RetireNodeKeys) isEvent_Type() {}
+
+func (*Event_StepDown) isEvent_Type() {}
+
+func (*Event_LocalParams) isEvent_Type() {}
+
 func (*Event_PersistDummyBatch) isEvent_Type() {}
 
 func (*Event_AnnounceDummyBatch) isEvent_Type() {}
@@ -350,6 +473,125 @@ func (m *Event) GetAppSnapshot() *AppSnapshot {
 	return nil
 }
 
+func (m *Event) GetAppRestoreState() *AppRestoreState {
+	if x, ok := m.GetType().(*Event_AppRestoreState); ok {
+		return x.AppRestoreState
+	}
+	return nil
+}
+
+func (m *Event) GetPoisonedBatch() *PoisonedBatch {
+	if x, ok := m.GetType().(*Event_PoisonedBatch); ok {
+		return x.PoisonedBatch
+	}
+	return nil
+}
+
+func (m *Event) GetPruneRequests() *PruneRequests {
+	if x, ok := m.GetType().(*Event_PruneRequests); ok {
+		return x.PruneRequests
+	}
+	return nil
+}
+
+func (m *Event) GetForwardRequests() *ForwardRequests {
+	if x, ok := m.GetType().(*Event_ForwardRequests); ok {
+		return x.ForwardRequests
+	}
+	return nil
+}
+
+func (m *Event) GetRequestTooLarge() *RequestTooLarge {
+	if x, ok := m.GetType().(*Event_RequestTooLarge); ok {
+		return x.RequestTooLarge
+	}
+	return nil
+}
+
+func (m *Event) GetPeerHealth() *PeerHealth {
+	if x, ok := m.GetType().(*Event_PeerHealth); ok {
+		return x.PeerHealth
+	}
+	return nil
+}
+
+func (m *Event) GetForwardedRequest() *ForwardedRequest {
+	if x, ok := m.GetType().(*Event_ForwardedRequest); ok {
+		return x.ForwardedRequest
+	}
+	return nil
+}
+
+func (m *Event) GetSendQueueOverflow() *SendQueueOverflow {
+	if x, ok := m.GetType().(*Event_SendQueueOverflow); ok {
+		return x.SendQueueOverflow
+	}
+	return nil
+}
+
+func (m *Event) GetMessageTooLarge() *MessageTooLarge {
+	if x, ok := m.GetType().(*Event_MessageTooLarge); ok {
+		return x.MessageTooLarge
+	}
+	return nil
+}
+
+func (m *Event) GetNotification() *Notification {
+	if x, ok := m.GetType().(*Event_Notification); ok {
+		return x.Notification
+	}
+	return nil
+}
+
+func (m *Event) GetAppQuery() *AppQuery {
+	if x, ok := m.GetType().(*Event_AppQuery); ok {
+		return x.AppQuery
+	}
+	return nil
+}
+
+func (m *Event) GetAppQueryResult() *AppQueryResult {
+	if x, ok := m.GetType().(*Event_AppQueryResult); ok {
+		return x.AppQueryResult
+	}
+	return nil
+}
+
+func (m *Event) GetUpdateClientKeys() *UpdateClientKeys {
+	if x, ok := m.GetType().(*Event_UpdateClientKeys); ok {
+		return x.UpdateClientKeys
+	}
+	return nil
+}
+
+func (m *Event) GetUpdateNodeKeys() *UpdateNodeKeys {
+	if x, ok := m.GetType().(*Event_UpdateNodeKeys); ok {
+		return x.UpdateNodeKeys
+	}
+	return nil
+}
+
+func (m *Event) GetRetireNodeKeys() *RetireNodeKeys {
+	if x, ok := m.GetType().(*Event_RetireNodeKeys); ok {
+		return x.RetireNodeKeys
+	}
+	return nil
+}
+
+func (m *Event) GetStepDown() *StepDown {
+	if x, ok := m.GetType().(*Event_StepDown); ok {
+		return x.StepDown
+	}
+	return nil
+}
+
+func (m *Event) GetLocalParams() *LocalParams {
+	if x, ok := m.GetType().(*Event_LocalParams); ok {
+		return x.LocalParams
+	}
+	return nil
+}
+
 func (m *Event) GetPersistDummyBatch() *PersistDummyBatch {
 	if x, ok := m.GetType().(*Event_PersistDummyBatch); ok {
 		return x.PersistDummyBatch
@@ -378,6 +620,13 @@ func (m *Event) GetNext() []*Event {
 	return nil
 }
 
+func (m *Event) GetCausalId() uint64 {
+	if m != nil {
+		return m.CausalId
+	}
+	return 0
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Event) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -399,12 +648,71 @@ func (*Event) XXX_OneofWrappers() []interface{} {
 		(*Event_StoreVerifiedRequest)(nil),
 		(*Event_AppSnapshotRequest)(nil),
 		(*Event_AppSnapshot)(nil),
+		(*Event_AppRestoreState)(nil),
+		(*Event_PoisonedBatch)(nil),
+		(*Event_PruneRequests)(nil),
+		(*Event_ForwardRequests)(nil),
+		(*Event_RequestTooLarge)(nil),
+		(*Event_PeerHealth)(nil),
+		(*Event_ForwardedRequest)(nil),
+		(*Event_SendQueueOverflow)(nil),
+		(*Event_MessageTooLarge)(nil),
+		(*Event_Notification)(nil),
+		(*Event_AppQuery)(nil),
+		(*Event_AppQueryResult)(nil),
+		(*Event_UpdateClientKeys)(nil),
+		(*Event_UpdateNodeKeys)(nil),
+		(*Event_RetireNodeKeys)(nil),
+		(*Event_StepDown)(nil),
+		(*Event_LocalParams)(nil),
 		(*Event_PersistDummyBatch)(nil),
 		(*Event_AnnounceDummyBatch)(nil),
 		(*Event_StoreDummyRequest)(nil),
 	}
 }
 
+// EventList is the serializable representation of a list of events (see events.EventList),
+// e.g. the events passed to or produced by a module.
+// It is used when lists of events need to be logged, shipped across processes, or stored.
+type EventList struct {
+	Events               []*Event `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventList) Reset()         { *m = EventList{} }
+func (m *EventList) String() string { return proto.CompactTextString(m) }
+func (*EventList) ProtoMessage()    {}
+func (*EventList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{1}
+}
+
+func (m *EventList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventList.Unmarshal(m, b)
+}
+func (m *EventList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventList.Marshal(b, m, deterministic)
+}
+func (m *EventList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventList.Merge(m, src)
+}
+func (m *EventList) XXX_Size() int {
+	return xxx_messageInfo_EventList.Size(m)
+}
+func (m *EventList) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventList proto.InternalMessageInfo
+
+func (m *EventList) GetEvents() []*Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 type Init struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -415,7 +723,7 @@ func (m *Init) Reset()         { *m = Init{} }
 func (m *Init) String() string { return proto.CompactTextString(m) }
 func (*Init) ProtoMessage()    {}
 func (*Init) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{1}
+	return fileDescriptor_e1d62373b81ab9ca, []int{2}
 }
 
 func (m *Init) XXX_Unmarshal(b []byte) error {
@@ -446,7 +754,7 @@ func (m *Tick) Reset()         { *m = Tick{} }
 func (m *Tick) String() string { return proto.CompactTextString(m) }
 func (*Tick) ProtoMessage()    {}
 func (*Tick) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{2}
+	return fileDescriptor_e1d62373b81ab9ca, []int{3}
 }
 
 func (m *Tick) XXX_Unmarshal(b []byte) error {
@@ -479,7 +787,7 @@ func (m *HashRequest) Reset()         { *m = HashRequest{} }
 func (m *HashRequest) String() string { return proto.CompactTextString(m) }
 func (*HashRequest) ProtoMessage()    {}
 func (*HashRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{3}
+	return fileDescriptor_e1d62373b81ab9ca, []int{4}
 }
 
 func (m *HashRequest) XXX_Unmarshal(b []byte) error {
@@ -526,7 +834,7 @@ func (m *HashResult) Reset()         { *m = HashResult{} }
 func (m *HashResult) String() string { return proto.CompactTextString(m) }
 func (*HashResult) ProtoMessage()    {}
 func (*HashResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{4}
+	return fileDescriptor_e1d62373b81ab9ca, []int{5}
 }
 
 func (m *HashResult) XXX_Unmarshal(b []byte) error {
@@ -564,6 +872,7 @@ func (m *HashResult) GetOrigin() *HashOrigin {
 type HashOrigin struct {
 	// Types that are valid to be assigned to Type:
 	//	*HashOrigin_Request
+	//	*HashOrigin_ForwardedRequest
 	Type                 isHashOrigin_Type `protobuf_oneof:"type"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
@@ -574,7 +883,7 @@ func (m *HashOrigin) Reset()         { *m = HashOrigin{} }
 func (m *HashOrigin) String() string { return proto.CompactTextString(m) }
 func (*HashOrigin) ProtoMessage()    {}
 func (*HashOrigin) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{5}
+	return fileDescriptor_e1d62373b81ab9ca, []int{6}
 }
 
 func (m *HashOrigin) XXX_Unmarshal(b []byte) error {
@@ -603,8 +912,14 @@ type HashOrigin_Request struct {
 	Request *requestpb.Request `protobuf:"bytes,1,opt,name=request,proto3,oneof"`
 }
 
+type HashOrigin_ForwardedRequest struct {
+	ForwardedRequest *ForwardedRequest `protobuf:"bytes,2,opt,name=forwarded_request,json=forwardedRequest,proto3,oneof"`
+}
+
 func (*HashOrigin_Request) isHashOrigin_Type() {}
 
+func (*HashOrigin_ForwardedRequest) isHashOrigin_Type() {}
+
 func (m *HashOrigin) GetType() isHashOrigin_Type {
 	if m != nil {
 		return m.Type
@@ -619,15 +934,72 @@ func (m *HashOrigin) GetRequest() *requestpb.Request {
 	return nil
 }
 
+func (m *HashOrigin) GetForwardedRequest() *ForwardedRequest {
+	if x, ok := m.GetType().(*HashOrigin_ForwardedRequest); ok {
+		return x.ForwardedRequest
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*HashOrigin) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*HashOrigin_Request)(nil),
+		(*HashOrigin_ForwardedRequest)(nil),
+	}
+}
+
+// ForwardedRequest is a request received from another node (and not directly from the client).
+type ForwardedRequest struct {
+	From                 uint64             `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	Request              *requestpb.Request `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ForwardedRequest) Reset()         { *m = ForwardedRequest{} }
+func (m *ForwardedRequest) String() string { return proto.CompactTextString(m) }
+func (*ForwardedRequest) ProtoMessage()    {}
+func (*ForwardedRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{7}
+}
+
+func (m *ForwardedRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForwardedRequest.Unmarshal(m, b)
+}
+func (m *ForwardedRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForwardedRequest.Marshal(b, m, deterministic)
+}
+func (m *ForwardedRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForwardedRequest.Merge(m, src)
+}
+func (m *ForwardedRequest) XXX_Size() int {
+	return xxx_messageInfo_ForwardedRequest.Size(m)
+}
+func (m *ForwardedRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForwardedRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForwardedRequest proto.InternalMessageInfo
+
+func (m *ForwardedRequest) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *ForwardedRequest) GetRequest() *requestpb.Request {
+	if m != nil {
+		return m.Request
 	}
+	return nil
 }
 
 type RequestReady struct {
 	RequestRef           *requestpb.RequestRef `protobuf:"bytes,1,opt,name=request_ref,json=requestRef,proto3" json:"request_ref,omitempty"`
+	Data                 []byte                `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
 	XXX_unrecognized     []byte                `json:"-"`
 	XXX_sizecache        int32                 `json:"-"`
@@ -637,7 +1009,7 @@ func (m *RequestReady) Reset()         { *m = RequestReady{} }
 func (m *RequestReady) String() string { return proto.CompactTextString(m) }
 func (*RequestReady) ProtoMessage()    {}
 func (*RequestReady) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{6}
+	return fileDescriptor_e1d62373b81ab9ca, []int{8}
 }
 
 func (m *RequestReady) XXX_Unmarshal(b []byte) error {
@@ -665,6 +1037,13 @@ func (m *RequestReady) GetRequestRef() *requestpb.RequestRef {
 	return nil
 }
 
+func (m *RequestReady) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type SendMessage struct {
 	Destinations         []uint64           `protobuf:"varint,1,rep,packed,name=destinations,proto3" json:"destinations,omitempty"`
 	Msg                  *messagepb.Message `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
@@ -677,7 +1056,7 @@ func (m *SendMessage) Reset()         { *m = SendMessage{} }
 func (m *SendMessage) String() string { return proto.CompactTextString(m) }
 func (*SendMessage) ProtoMessage()    {}
 func (*SendMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{7}
+	return fileDescriptor_e1d62373b81ab9ca, []int{9}
 }
 
 func (m *SendMessage) XXX_Unmarshal(b []byte) error {
@@ -724,7 +1103,7 @@ func (m *MessageReceived) Reset()         { *m = MessageReceived{} }
 func (m *MessageReceived) String() string { return proto.CompactTextString(m) }
 func (*MessageReceived) ProtoMessage()    {}
 func (*MessageReceived) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{8}
+	return fileDescriptor_e1d62373b81ab9ca, []int{10}
 }
 
 func (m *MessageReceived) XXX_Unmarshal(b []byte) error {
@@ -771,7 +1150,7 @@ func (m *WALAppend) Reset()         { *m = WALAppend{} }
 func (m *WALAppend) String() string { return proto.CompactTextString(m) }
 func (*WALAppend) ProtoMessage()    {}
 func (*WALAppend) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{9}
+	return fileDescriptor_e1d62373b81ab9ca, []int{11}
 }
 
 func (m *WALAppend) XXX_Unmarshal(b []byte) error {
@@ -817,7 +1196,7 @@ func (m *WALEntry) Reset()         { *m = WALEntry{} }
 func (m *WALEntry) String() string { return proto.CompactTextString(m) }
 func (*WALEntry) ProtoMessage()    {}
 func (*WALEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{10}
+	return fileDescriptor_e1d62373b81ab9ca, []int{12}
 }
 
 func (m *WALEntry) XXX_Unmarshal(b []byte) error {
@@ -856,7 +1235,7 @@ func (m *WALTruncate) Reset()         { *m = WALTruncate{} }
 func (m *WALTruncate) String() string { return proto.CompactTextString(m) }
 func (*WALTruncate) ProtoMessage()    {}
 func (*WALTruncate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{11}
+	return fileDescriptor_e1d62373b81ab9ca, []int{13}
 }
 
 func (m *WALTruncate) XXX_Unmarshal(b []byte) error {
@@ -896,7 +1275,7 @@ func (m *Deliver) Reset()         { *m = Deliver{} }
 func (m *Deliver) String() string { return proto.CompactTextString(m) }
 func (*Deliver) ProtoMessage()    {}
 func (*Deliver) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{12}
+	return fileDescriptor_e1d62373b81ab9ca, []int{14}
 }
 
 func (m *Deliver) XXX_Unmarshal(b []byte) error {
@@ -943,7 +1322,7 @@ func (m *VerifyRequestSig) Reset()         { *m = VerifyRequestSig{} }
 func (m *VerifyRequestSig) String() string { return proto.CompactTextString(m) }
 func (*VerifyRequestSig) ProtoMessage()    {}
 func (*VerifyRequestSig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{13}
+	return fileDescriptor_e1d62373b81ab9ca, []int{15}
 }
 
 func (m *VerifyRequestSig) XXX_Unmarshal(b []byte) error {
@@ -991,7 +1370,7 @@ func (m *RequestSigVerified) Reset()         { *m = RequestSigVerified{} }
 func (m *RequestSigVerified) String() string { return proto.CompactTextString(m) }
 func (*RequestSigVerified) ProtoMessage()    {}
 func (*RequestSigVerified) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{14}
+	return fileDescriptor_e1d62373b81ab9ca, []int{16}
 }
 
 func (m *RequestSigVerified) XXX_Unmarshal(b []byte) error {
@@ -1046,7 +1425,7 @@ func (m *StoreVerifiedRequest) Reset()         { *m = StoreVerifiedRequest{} }
 func (m *StoreVerifiedRequest) String() string { return proto.CompactTextString(m) }
 func (*StoreVerifiedRequest) ProtoMessage()    {}
 func (*StoreVerifiedRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{15}
+	return fileDescriptor_e1d62373b81ab9ca, []int{17}
 }
 
 func (m *StoreVerifiedRequest) XXX_Unmarshal(b []byte) error {
@@ -1088,88 +1467,1552 @@ func (m *StoreVerifiedRequest) GetAuthenticator() []byte {
 	return nil
 }
 
-type AppSnapshotRequest struct {
-	Sn                   uint64   `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
+type PruneRequests struct {
+	ClientId             uint64   `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	BelowReqNo           uint64   `protobuf:"varint,2,opt,name=below_req_no,json=belowReqNo,proto3" json:"below_req_no,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *AppSnapshotRequest) Reset()         { *m = AppSnapshotRequest{} }
-func (m *AppSnapshotRequest) String() string { return proto.CompactTextString(m) }
-func (*AppSnapshotRequest) ProtoMessage()    {}
-func (*AppSnapshotRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{16}
+func (m *PruneRequests) Reset()         { *m = PruneRequests{} }
+func (m *PruneRequests) String() string { return proto.CompactTextString(m) }
+func (*PruneRequests) ProtoMessage()    {}
+func (*PruneRequests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{18}
 }
 
-func (m *AppSnapshotRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AppSnapshotRequest.Unmarshal(m, b)
+func (m *PruneRequests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruneRequests.Unmarshal(m, b)
 }
-func (m *AppSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AppSnapshotRequest.Marshal(b, m, deterministic)
+func (m *PruneRequests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruneRequests.Marshal(b, m, deterministic)
 }
-func (m *AppSnapshotRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AppSnapshotRequest.Merge(m, src)
+func (m *PruneRequests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruneRequests.Merge(m, src)
 }
-func (m *AppSnapshotRequest) XXX_Size() int {
-	return xxx_messageInfo_AppSnapshotRequest.Size(m)
+func (m *PruneRequests) XXX_Size() int {
+	return xxx_messageInfo_PruneRequests.Size(m)
 }
-func (m *AppSnapshotRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_AppSnapshotRequest.DiscardUnknown(m)
+func (m *PruneRequests) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruneRequests.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AppSnapshotRequest proto.InternalMessageInfo
+var xxx_messageInfo_PruneRequests proto.InternalMessageInfo
 
-func (m *AppSnapshotRequest) GetSn() uint64 {
+func (m *PruneRequests) GetClientId() uint64 {
 	if m != nil {
-		return m.Sn
+		return m.ClientId
 	}
 	return 0
 }
 
-type AppSnapshot struct {
-	Sn                   uint64   `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
-	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *PruneRequests) GetBelowReqNo() uint64 {
+	if m != nil {
+		return m.BelowReqNo
+	}
+	return 0
 }
 
-func (m *AppSnapshot) Reset()         { *m = AppSnapshot{} }
-func (m *AppSnapshot) String() string { return proto.CompactTextString(m) }
-func (*AppSnapshot) ProtoMessage()    {}
-func (*AppSnapshot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{17}
+type ForwardRequests struct {
+	RequestRefs          []*requestpb.RequestRef `protobuf:"bytes,1,rep,name=request_refs,json=requestRefs,proto3" json:"request_refs,omitempty"`
+	Destinations         []uint64                `protobuf:"varint,2,rep,packed,name=destinations,proto3" json:"destinations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
-func (m *AppSnapshot) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AppSnapshot.Unmarshal(m, b)
+func (m *ForwardRequests) Reset()         { *m = ForwardRequests{} }
+func (m *ForwardRequests) String() string { return proto.CompactTextString(m) }
+func (*ForwardRequests) ProtoMessage()    {}
+func (*ForwardRequests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{19}
 }
-func (m *AppSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AppSnapshot.Marshal(b, m, deterministic)
+
+func (m *ForwardRequests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForwardRequests.Unmarshal(m, b)
+}
+func (m *ForwardRequests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForwardRequests.Marshal(b, m, deterministic)
+}
+func (m *ForwardRequests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForwardRequests.Merge(m, src)
+}
+func (m *ForwardRequests) XXX_Size() int {
+	return xxx_messageInfo_ForwardRequests.Size(m)
+}
+func (m *ForwardRequests) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForwardRequests.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForwardRequests proto.InternalMessageInfo
+
+func (m *ForwardRequests) GetRequestRefs() []*requestpb.RequestRef {
+	if m != nil {
+		return m.RequestRefs
+	}
+	return nil
+}
+
+func (m *ForwardRequests) GetDestinations() []uint64 {
+	if m != nil {
+		return m.Destinations
+	}
+	return nil
+}
+
+type AppSnapshotRequest struct {
+	Sn                   uint64   `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppSnapshotRequest) Reset()         { *m = AppSnapshotRequest{} }
+func (m *AppSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*AppSnapshotRequest) ProtoMessage()    {}
+func (*AppSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{20}
+}
+
+func (m *AppSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppSnapshotRequest.Unmarshal(m, b)
+}
+func (m *AppSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *AppSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppSnapshotRequest.Merge(m, src)
+}
+func (m *AppSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_AppSnapshotRequest.Size(m)
+}
+func (m *AppSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppSnapshotRequest proto.InternalMessageInfo
+
+func (m *AppSnapshotRequest) GetSn() uint64 {
+	if m != nil {
+		return m.Sn
+	}
+	return 0
+}
+
+type AppSnapshot struct {
+	Sn                   uint64   `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppSnapshot) Reset()         { *m = AppSnapshot{} }
+func (m *AppSnapshot) String() string { return proto.CompactTextString(m) }
+func (*AppSnapshot) ProtoMessage()    {}
+func (*AppSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{21}
+}
+
+func (m *AppSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppSnapshot.Unmarshal(m, b)
+}
+func (m *AppSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppSnapshot.Marshal(b, m, deterministic)
 }
 func (m *AppSnapshot) XXX_Merge(src proto.Message) {
 	xxx_messageInfo_AppSnapshot.Merge(m, src)
 }
-func (m *AppSnapshot) XXX_Size() int {
-	return xxx_messageInfo_AppSnapshot.Size(m)
+func (m *AppSnapshot) XXX_Size() int {
+	return xxx_messageInfo_AppSnapshot.Size(m)
+}
+func (m *AppSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppSnapshot proto.InternalMessageInfo
+
+func (m *AppSnapshot) GetSn() uint64 {
+	if m != nil {
+		return m.Sn
+	}
+	return 0
+}
+
+func (m *AppSnapshot) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type AppRestoreState struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppRestoreState) Reset()         { *m = AppRestoreState{} }
+func (m *AppRestoreState) String() string { return proto.CompactTextString(m) }
+func (*AppRestoreState) ProtoMessage()    {}
+func (*AppRestoreState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{22}
+}
+
+func (m *AppRestoreState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppRestoreState.Unmarshal(m, b)
+}
+func (m *AppRestoreState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppRestoreState.Marshal(b, m, deterministic)
+}
+func (m *AppRestoreState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppRestoreState.Merge(m, src)
+}
+func (m *AppRestoreState) XXX_Size() int {
+	return xxx_messageInfo_AppRestoreState.Size(m)
+}
+func (m *AppRestoreState) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppRestoreState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppRestoreState proto.InternalMessageInfo
+
+func (m *AppRestoreState) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type PoisonedBatch struct {
+	Sn                   uint64           `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
+	Batch                *requestpb.Batch `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	Reason               string           `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *PoisonedBatch) Reset()         { *m = PoisonedBatch{} }
+func (m *PoisonedBatch) String() string { return proto.CompactTextString(m) }
+func (*PoisonedBatch) ProtoMessage()    {}
+func (*PoisonedBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{23}
+}
+
+func (m *PoisonedBatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PoisonedBatch.Unmarshal(m, b)
+}
+func (m *PoisonedBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PoisonedBatch.Marshal(b, m, deterministic)
+}
+func (m *PoisonedBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PoisonedBatch.Merge(m, src)
+}
+func (m *PoisonedBatch) XXX_Size() int {
+	return xxx_messageInfo_PoisonedBatch.Size(m)
+}
+func (m *PoisonedBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_PoisonedBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PoisonedBatch proto.InternalMessageInfo
+
+func (m *PoisonedBatch) GetSn() uint64 {
+	if m != nil {
+		return m.Sn
+	}
+	return 0
+}
+
+func (m *PoisonedBatch) GetBatch() *requestpb.Batch {
+	if m != nil {
+		return m.Batch
+	}
+	return nil
+}
+
+func (m *PoisonedBatch) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// RequestTooLarge records a request that has been rejected because its payload exceeds the maximal request size.
+// It is not processed by any module and only serves as a record for the event Interceptor.
+type RequestTooLarge struct {
+	ClientId             uint64   `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ReqNo                uint64   `protobuf:"varint,2,opt,name=req_no,json=reqNo,proto3" json:"req_no,omitempty"`
+	Size                 uint64   `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	MaxSize              uint64   `protobuf:"varint,4,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	Forwarded            bool     `protobuf:"varint,5,opt,name=forwarded,proto3" json:"forwarded,omitempty"`
+	ForwardedBy          uint64   `protobuf:"varint,6,opt,name=forwarded_by,json=forwardedBy,proto3" json:"forwarded_by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RequestTooLarge) Reset()         { *m = RequestTooLarge{} }
+func (m *RequestTooLarge) String() string { return proto.CompactTextString(m) }
+func (*RequestTooLarge) ProtoMessage()    {}
+func (*RequestTooLarge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{24}
+}
+
+func (m *RequestTooLarge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequestTooLarge.Unmarshal(m, b)
+}
+func (m *RequestTooLarge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequestTooLarge.Marshal(b, m, deterministic)
+}
+func (m *RequestTooLarge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequestTooLarge.Merge(m, src)
+}
+func (m *RequestTooLarge) XXX_Size() int {
+	return xxx_messageInfo_RequestTooLarge.Size(m)
+}
+func (m *RequestTooLarge) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequestTooLarge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequestTooLarge proto.InternalMessageInfo
+
+func (m *RequestTooLarge) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *RequestTooLarge) GetReqNo() uint64 {
+	if m != nil {
+		return m.ReqNo
+	}
+	return 0
+}
+
+func (m *RequestTooLarge) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *RequestTooLarge) GetMaxSize() uint64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *RequestTooLarge) GetForwarded() bool {
+	if m != nil {
+		return m.Forwarded
+	}
+	return false
+}
+
+func (m *RequestTooLarge) GetForwardedBy() uint64 {
+	if m != nil {
+		return m.ForwardedBy
+	}
+	return 0
+}
+
+// MessageTooLarge records a message received from another node that has been dropped
+// because its size exceeds the maximal message size.
+type MessageTooLarge struct {
+	From                 uint64   `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	Size                 uint64   `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	MaxSize              uint64   `protobuf:"varint,3,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageTooLarge) Reset()         { *m = MessageTooLarge{} }
+func (m *MessageTooLarge) String() string { return proto.CompactTextString(m) }
+func (*MessageTooLarge) ProtoMessage()    {}
+func (*MessageTooLarge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{25}
+}
+
+func (m *MessageTooLarge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageTooLarge.Unmarshal(m, b)
+}
+func (m *MessageTooLarge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageTooLarge.Marshal(b, m, deterministic)
+}
+func (m *MessageTooLarge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageTooLarge.Merge(m, src)
+}
+func (m *MessageTooLarge) XXX_Size() int {
+	return xxx_messageInfo_MessageTooLarge.Size(m)
+}
+func (m *MessageTooLarge) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageTooLarge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageTooLarge proto.InternalMessageInfo
+
+func (m *MessageTooLarge) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *MessageTooLarge) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *MessageTooLarge) GetMaxSize() uint64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+// AppQuery instructs the application to execute a read-only query against its current state (see Node.Read).
+type AppQuery struct {
+	ReadId               uint64   `protobuf:"varint,1,opt,name=read_id,json=readId,proto3" json:"read_id,omitempty"`
+	Query                []byte   `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppQuery) Reset()         { *m = AppQuery{} }
+func (m *AppQuery) String() string { return proto.CompactTextString(m) }
+func (*AppQuery) ProtoMessage()    {}
+func (*AppQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{26}
+}
+
+func (m *AppQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppQuery.Unmarshal(m, b)
+}
+func (m *AppQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppQuery.Marshal(b, m, deterministic)
+}
+func (m *AppQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppQuery.Merge(m, src)
+}
+func (m *AppQuery) XXX_Size() int {
+	return xxx_messageInfo_AppQuery.Size(m)
+}
+func (m *AppQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppQuery proto.InternalMessageInfo
+
+func (m *AppQuery) GetReadId() uint64 {
+	if m != nil {
+		return m.ReadId
+	}
+	return 0
+}
+
+func (m *AppQuery) GetQuery() []byte {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
+// AppQueryResult carries the result of an AppQuery back to the caller of Node.Read.
+type AppQueryResult struct {
+	ReadId               uint64   `protobuf:"varint,1,opt,name=read_id,json=readId,proto3" json:"read_id,omitempty"`
+	Result               []byte   `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppQueryResult) Reset()         { *m = AppQueryResult{} }
+func (m *AppQueryResult) String() string { return proto.CompactTextString(m) }
+func (*AppQueryResult) ProtoMessage()    {}
+func (*AppQueryResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{27}
+}
+
+func (m *AppQueryResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppQueryResult.Unmarshal(m, b)
+}
+func (m *AppQueryResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppQueryResult.Marshal(b, m, deterministic)
+}
+func (m *AppQueryResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppQueryResult.Merge(m, src)
+}
+func (m *AppQueryResult) XXX_Size() int {
+	return xxx_messageInfo_AppQueryResult.Size(m)
+}
+func (m *AppQueryResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppQueryResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppQueryResult proto.InternalMessageInfo
+
+func (m *AppQueryResult) GetReadId() uint64 {
+	if m != nil {
+		return m.ReadId
+	}
+	return 0
+}
+
+func (m *AppQueryResult) GetResult() []byte {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *AppQueryResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// UpdateClientKeys makes the Crypto module register (or, for an empty key, delete) the given client public keys.
+type UpdateClientKeys struct {
+	ClientKeys           []*isspb.ClientKey `protobuf:"bytes,1,rep,name=client_keys,json=clientKeys,proto3" json:"client_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *UpdateClientKeys) Reset()         { *m = UpdateClientKeys{} }
+func (m *UpdateClientKeys) String() string { return proto.CompactTextString(m) }
+func (*UpdateClientKeys) ProtoMessage()    {}
+func (*UpdateClientKeys) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{28}
+}
+
+func (m *UpdateClientKeys) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateClientKeys.Unmarshal(m, b)
+}
+func (m *UpdateClientKeys) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateClientKeys.Marshal(b, m, deterministic)
+}
+func (m *UpdateClientKeys) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateClientKeys.Merge(m, src)
+}
+func (m *UpdateClientKeys) XXX_Size() int {
+	return xxx_messageInfo_UpdateClientKeys.Size(m)
+}
+func (m *UpdateClientKeys) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateClientKeys.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateClientKeys proto.InternalMessageInfo
+
+func (m *UpdateClientKeys) GetClientKeys() []*isspb.ClientKey {
+	if m != nil {
+		return m.ClientKeys
+	}
+	return nil
+}
+
+// UpdateNodeKeys makes the Crypto module associate new public keys with nodes,
+// while still accepting the nodes' previous keys until the corresponding RetireNodeKeys event.
+type UpdateNodeKeys struct {
+	NodeKeys             []*isspb.NodeKey `protobuf:"bytes,1,rep,name=node_keys,json=nodeKeys,proto3" json:"node_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *UpdateNodeKeys) Reset()         { *m = UpdateNodeKeys{} }
+func (m *UpdateNodeKeys) String() string { return proto.CompactTextString(m) }
+func (*UpdateNodeKeys) ProtoMessage()    {}
+func (*UpdateNodeKeys) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{29}
+}
+
+func (m *UpdateNodeKeys) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateNodeKeys.Unmarshal(m, b)
+}
+func (m *UpdateNodeKeys) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateNodeKeys.Marshal(b, m, deterministic)
+}
+func (m *UpdateNodeKeys) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateNodeKeys.Merge(m, src)
+}
+func (m *UpdateNodeKeys) XXX_Size() int {
+	return xxx_messageInfo_UpdateNodeKeys.Size(m)
+}
+func (m *UpdateNodeKeys) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateNodeKeys.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateNodeKeys proto.InternalMessageInfo
+
+func (m *UpdateNodeKeys) GetNodeKeys() []*isspb.NodeKey {
+	if m != nil {
+		return m.NodeKeys
+	}
+	return nil
+}
+
+// RetireNodeKeys makes the Crypto module stop accepting the previous keys of nodes whose keys have been rotated.
+type RetireNodeKeys struct {
+	NodeIds              []uint64 `protobuf:"varint,1,rep,packed,name=node_ids,json=nodeIds,proto3" json:"node_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetireNodeKeys) Reset()         { *m = RetireNodeKeys{} }
+func (m *RetireNodeKeys) String() string { return proto.CompactTextString(m) }
+func (*RetireNodeKeys) ProtoMessage()    {}
+func (*RetireNodeKeys) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{30}
+}
+
+func (m *RetireNodeKeys) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetireNodeKeys.Unmarshal(m, b)
+}
+func (m *RetireNodeKeys) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetireNodeKeys.Marshal(b, m, deterministic)
+}
+func (m *RetireNodeKeys) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetireNodeKeys.Merge(m, src)
+}
+func (m *RetireNodeKeys) XXX_Size() int {
+	return xxx_messageInfo_RetireNodeKeys.Size(m)
+}
+func (m *RetireNodeKeys) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetireNodeKeys.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetireNodeKeys proto.InternalMessageInfo
+
+func (m *RetireNodeKeys) GetNodeIds() []uint64 {
+	if m != nil {
+		return m.NodeIds
+	}
+	return nil
+}
+
+// StepDown makes the protocol stop leading, e.g., in preparation of a planned shutdown of the node.
+// Instead of being killed while leading, which would leave its segments unfinished,
+// the node hands off its segments by proposing empty batches for their remaining sequence numbers.
+type StepDown struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StepDown) Reset()         { *m = StepDown{} }
+func (m *StepDown) String() string { return proto.CompactTextString(m) }
+func (*StepDown) ProtoMessage()    {}
+func (*StepDown) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{31}
+}
+
+func (m *StepDown) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepDown.Unmarshal(m, b)
+}
+func (m *StepDown) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepDown.Marshal(b, m, deterministic)
+}
+func (m *StepDown) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepDown.Merge(m, src)
+}
+func (m *StepDown) XXX_Size() int {
+	return xxx_messageInfo_StepDown.Size(m)
+}
+func (m *StepDown) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepDown.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepDown proto.InternalMessageInfo
+
+// LocalParams adjusts the parameters of the protocol that only affect the local node,
+// not its agreement with other nodes (see Node.Reconfigure).
+type LocalParams struct {
+	MaxProposeDelay      uint64   `protobuf:"varint,1,opt,name=max_propose_delay,json=maxProposeDelay,proto3" json:"max_propose_delay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LocalParams) Reset()         { *m = LocalParams{} }
+func (m *LocalParams) String() string { return proto.CompactTextString(m) }
+func (*LocalParams) ProtoMessage()    {}
+func (*LocalParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{32}
+}
+
+func (m *LocalParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocalParams.Unmarshal(m, b)
+}
+func (m *LocalParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocalParams.Marshal(b, m, deterministic)
+}
+func (m *LocalParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocalParams.Merge(m, src)
+}
+func (m *LocalParams) XXX_Size() int {
+	return xxx_messageInfo_LocalParams.Size(m)
+}
+func (m *LocalParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocalParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocalParams proto.InternalMessageInfo
+
+func (m *LocalParams) GetMaxProposeDelay() uint64 {
+	if m != nil {
+		return m.MaxProposeDelay
+	}
+	return 0
+}
+
+// PeerHealth reports a change of the reachability of another node, as observed when sending messages to it.
+// A node becomes unreachable when sending a message to it persistently fails
+// and reachable again when a message is sent to it successfully.
+type PeerHealth struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Reachable            bool     `protobuf:"varint,2,opt,name=reachable,proto3" json:"reachable,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerHealth) Reset()         { *m = PeerHealth{} }
+func (m *PeerHealth) String() string { return proto.CompactTextString(m) }
+func (*PeerHealth) ProtoMessage()    {}
+func (*PeerHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{33}
+}
+
+func (m *PeerHealth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerHealth.Unmarshal(m, b)
+}
+func (m *PeerHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerHealth.Marshal(b, m, deterministic)
+}
+func (m *PeerHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerHealth.Merge(m, src)
+}
+func (m *PeerHealth) XXX_Size() int {
+	return xxx_messageInfo_PeerHealth.Size(m)
+}
+func (m *PeerHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerHealth proto.InternalMessageInfo
+
+func (m *PeerHealth) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *PeerHealth) GetReachable() bool {
+	if m != nil {
+		return m.Reachable
+	}
+	return false
+}
+
+func (m *PeerHealth) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// SendQueueOverflow records a message dropped because the outbound queue of its destination was full
+// (see NodeConfig.SendQueueLength).
+type SendQueueOverflow struct {
+	Destination          uint64   `protobuf:"varint,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	Critical             bool     `protobuf:"varint,2,opt,name=critical,proto3" json:"critical,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendQueueOverflow) Reset()         { *m = SendQueueOverflow{} }
+func (m *SendQueueOverflow) String() string { return proto.CompactTextString(m) }
+func (*SendQueueOverflow) ProtoMessage()    {}
+func (*SendQueueOverflow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{34}
+}
+
+func (m *SendQueueOverflow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendQueueOverflow.Unmarshal(m, b)
+}
+func (m *SendQueueOverflow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendQueueOverflow.Marshal(b, m, deterministic)
+}
+func (m *SendQueueOverflow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendQueueOverflow.Merge(m, src)
+}
+func (m *SendQueueOverflow) XXX_Size() int {
+	return xxx_messageInfo_SendQueueOverflow.Size(m)
+}
+func (m *SendQueueOverflow) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendQueueOverflow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendQueueOverflow proto.InternalMessageInfo
+
+func (m *SendQueueOverflow) GetDestination() uint64 {
+	if m != nil {
+		return m.Destination
+	}
+	return 0
+}
+
+func (m *SendQueueOverflow) GetCritical() bool {
+	if m != nil {
+		return m.Critical
+	}
+	return false
+}
+
+// Notification reports a notable change of the state of the protocol to the subscribers of the Node's events
+// (see Node.Events()). Notifications are not processed by any module.
+type Notification struct {
+	// Types that are valid to be assigned to Type:
+	//	*Notification_EpochStarted
+	//	*Notification_CheckpointStable
+	//	*Notification_ClientWindowMoved
+	//	*Notification_NodeSuspected
+	//	*Notification_Equivocation
+	//	*Notification_ConfigChanged
+	//	*Notification_Oddity
+	//	*Notification_NodeSlow
+	//	*Notification_SlowPersist
+	Type                 isNotification_Type `protobuf_oneof:"type"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Notification) Reset()         { *m = Notification{} }
+func (m *Notification) String() string { return proto.CompactTextString(m) }
+func (*Notification) ProtoMessage()    {}
+func (*Notification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{35}
+}
+
+func (m *Notification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Notification.Unmarshal(m, b)
+}
+func (m *Notification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Notification.Marshal(b, m, deterministic)
+}
+func (m *Notification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Notification.Merge(m, src)
+}
+func (m *Notification) XXX_Size() int {
+	return xxx_messageInfo_Notification.Size(m)
+}
+func (m *Notification) XXX_DiscardUnknown() {
+	xxx_messageInfo_Notification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Notification proto.InternalMessageInfo
+
+type isNotification_Type interface {
+	isNotification_Type()
+}
+
+type Notification_EpochStarted struct {
+	EpochStarted *EpochStarted `protobuf:"bytes,1,opt,name=epoch_started,json=epochStarted,proto3,oneof"`
+}
+
+type Notification_CheckpointStable struct {
+	CheckpointStable *CheckpointStable `protobuf:"bytes,2,opt,name=checkpoint_stable,json=checkpointStable,proto3,oneof"`
+}
+
+type Notification_ClientWindowMoved struct {
+	ClientWindowMoved *ClientWindowMoved `protobuf:"bytes,3,opt,name=client_window_moved,json=clientWindowMoved,proto3,oneof"`
+}
+
+type Notification_NodeSuspected struct {
+	NodeSuspected *NodeSuspected `protobuf:"bytes,4,opt,name=node_suspected,json=nodeSuspected,proto3,oneof"`
+}
+
+type Notification_Equivocation struct {
+	Equivocation *Equivocation `protobuf:"bytes,5,opt,name=equivocation,proto3,oneof"`
+}
+
+type Notification_ConfigChanged struct {
+	ConfigChanged *ConfigChanged `protobuf:"bytes,6,opt,name=config_changed,json=configChanged,proto3,oneof"`
+}
+
+type Notification_Oddity struct {
+	Oddity *Oddity `protobuf:"bytes,7,opt,name=oddity,proto3,oneof"`
+}
+
+type Notification_NodeSlow struct {
+	NodeSlow *NodeSlow `protobuf:"bytes,8,opt,name=node_slow,json=nodeSlow,proto3,oneof"`
+}
+
+type Notification_SlowPersist struct {
+	SlowPersist *SlowPersist `protobuf:"bytes,9,opt,name=slow_persist,json=slowPersist,proto3,oneof"`
+}
+
+func (*Notification_EpochStarted) isNotification_Type() {}
+
+func (*Notification_CheckpointStable) isNotification_Type() {}
+
+func (*Notification_ClientWindowMoved) isNotification_Type() {}
+
+func (*Notification_NodeSuspected) isNotification_Type() {}
+
+func (*Notification_Equivocation) isNotification_Type() {}
+
+func (*Notification_ConfigChanged) isNotification_Type() {}
+
+func (*Notification_Oddity) isNotification_Type() {}
+
+func (*Notification_NodeSlow) isNotification_Type() {}
+
+func (*Notification_SlowPersist) isNotification_Type() {}
+
+func (m *Notification) GetType() isNotification_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *Notification) GetEpochStarted() *EpochStarted {
+	if x, ok := m.GetType().(*Notification_EpochStarted); ok {
+		return x.EpochStarted
+	}
+	return nil
+}
+
+func (m *Notification) GetCheckpointStable() *CheckpointStable {
+	if x, ok := m.GetType().(*Notification_CheckpointStable); ok {
+		return x.CheckpointStable
+	}
+	return nil
+}
+
+func (m *Notification) GetClientWindowMoved() *ClientWindowMoved {
+	if x, ok := m.GetType().(*Notification_ClientWindowMoved); ok {
+		return x.ClientWindowMoved
+	}
+	return nil
+}
+
+func (m *Notification) GetNodeSuspected() *NodeSuspected {
+	if x, ok := m.GetType().(*Notification_NodeSuspected); ok {
+		return x.NodeSuspected
+	}
+	return nil
+}
+
+func (m *Notification) GetEquivocation() *Equivocation {
+	if x, ok := m.GetType().(*Notification_Equivocation); ok {
+		return x.Equivocation
+	}
+	return nil
+}
+
+func (m *Notification) GetConfigChanged() *ConfigChanged {
+	if x, ok := m.GetType().(*Notification_ConfigChanged); ok {
+		return x.ConfigChanged
+	}
+	return nil
+}
+
+func (m *Notification) GetOddity() *Oddity {
+	if x, ok := m.GetType().(*Notification_Oddity); ok {
+		return x.Oddity
+	}
+	return nil
+}
+
+func (m *Notification) GetNodeSlow() *NodeSlow {
+	if x, ok := m.GetType().(*Notification_NodeSlow); ok {
+		return x.NodeSlow
+	}
+	return nil
+}
+
+func (m *Notification) GetSlowPersist() *SlowPersist {
+	if x, ok := m.GetType().(*Notification_SlowPersist); ok {
+		return x.SlowPersist
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Notification) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Notification_EpochStarted)(nil),
+		(*Notification_CheckpointStable)(nil),
+		(*Notification_ClientWindowMoved)(nil),
+		(*Notification_NodeSuspected)(nil),
+		(*Notification_Equivocation)(nil),
+		(*Notification_ConfigChanged)(nil),
+		(*Notification_Oddity)(nil),
+		(*Notification_NodeSlow)(nil),
+		(*Notification_SlowPersist)(nil),
+	}
+}
+
+// EpochStarted notifies about the start of a new epoch (including the initial one).
+// As ISS epochs end when all their sequence numbers have been delivered, this is also the only notification
+// of an epoch change.
+type EpochStarted struct {
+	Epoch                uint64   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Leaders              []uint64 `protobuf:"varint,2,rep,packed,name=leaders,proto3" json:"leaders,omitempty"`
+	BucketLeaders        []uint64 `protobuf:"varint,3,rep,packed,name=bucket_leaders,json=bucketLeaders,proto3" json:"bucket_leaders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EpochStarted) Reset()         { *m = EpochStarted{} }
+func (m *EpochStarted) String() string { return proto.CompactTextString(m) }
+func (*EpochStarted) ProtoMessage()    {}
+func (*EpochStarted) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{36}
+}
+
+func (m *EpochStarted) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EpochStarted.Unmarshal(m, b)
+}
+func (m *EpochStarted) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EpochStarted.Marshal(b, m, deterministic)
+}
+func (m *EpochStarted) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EpochStarted.Merge(m, src)
+}
+func (m *EpochStarted) XXX_Size() int {
+	return xxx_messageInfo_EpochStarted.Size(m)
+}
+func (m *EpochStarted) XXX_DiscardUnknown() {
+	xxx_messageInfo_EpochStarted.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EpochStarted proto.InternalMessageInfo
+
+func (m *EpochStarted) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *EpochStarted) GetLeaders() []uint64 {
+	if m != nil {
+		return m.Leaders
+	}
+	return nil
+}
+
+func (m *EpochStarted) GetBucketLeaders() []uint64 {
+	if m != nil {
+		return m.BucketLeaders
+	}
+	return nil
 }
-func (m *AppSnapshot) XXX_DiscardUnknown() {
-	xxx_messageInfo_AppSnapshot.DiscardUnknown(m)
+
+// CheckpointStable notifies about a new stable checkpoint.
+type CheckpointStable struct {
+	Epoch                uint64   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sn                   uint64   `protobuf:"varint,2,opt,name=sn,proto3" json:"sn,omitempty"`
+	AppSnapshot          []byte   `protobuf:"bytes,3,opt,name=app_snapshot,json=appSnapshot,proto3" json:"app_snapshot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-var xxx_messageInfo_AppSnapshot proto.InternalMessageInfo
+func (m *CheckpointStable) Reset()         { *m = CheckpointStable{} }
+func (m *CheckpointStable) String() string { return proto.CompactTextString(m) }
+func (*CheckpointStable) ProtoMessage()    {}
+func (*CheckpointStable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{37}
+}
 
-func (m *AppSnapshot) GetSn() uint64 {
+func (m *CheckpointStable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointStable.Unmarshal(m, b)
+}
+func (m *CheckpointStable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointStable.Marshal(b, m, deterministic)
+}
+func (m *CheckpointStable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointStable.Merge(m, src)
+}
+func (m *CheckpointStable) XXX_Size() int {
+	return xxx_messageInfo_CheckpointStable.Size(m)
+}
+func (m *CheckpointStable) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointStable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointStable proto.InternalMessageInfo
+
+func (m *CheckpointStable) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *CheckpointStable) GetSn() uint64 {
 	if m != nil {
 		return m.Sn
 	}
 	return 0
 }
 
-func (m *AppSnapshot) GetData() []byte {
+func (m *CheckpointStable) GetAppSnapshot() []byte {
 	if m != nil {
-		return m.Data
+		return m.AppSnapshot
+	}
+	return nil
+}
+
+// ClientWindowMoved notifies about an advanced low watermark of a client.
+// All requests of the client below the low watermark have been delivered and garbage-collected.
+type ClientWindowMoved struct {
+	ClientId             uint64   `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	LowWatermark         uint64   `protobuf:"varint,2,opt,name=low_watermark,json=lowWatermark,proto3" json:"low_watermark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientWindowMoved) Reset()         { *m = ClientWindowMoved{} }
+func (m *ClientWindowMoved) String() string { return proto.CompactTextString(m) }
+func (*ClientWindowMoved) ProtoMessage()    {}
+func (*ClientWindowMoved) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{38}
+}
+
+func (m *ClientWindowMoved) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientWindowMoved.Unmarshal(m, b)
+}
+func (m *ClientWindowMoved) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientWindowMoved.Marshal(b, m, deterministic)
+}
+func (m *ClientWindowMoved) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientWindowMoved.Merge(m, src)
+}
+func (m *ClientWindowMoved) XXX_Size() int {
+	return xxx_messageInfo_ClientWindowMoved.Size(m)
+}
+func (m *ClientWindowMoved) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientWindowMoved.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientWindowMoved proto.InternalMessageInfo
+
+func (m *ClientWindowMoved) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ClientWindowMoved) GetLowWatermark() uint64 {
+	if m != nil {
+		return m.LowWatermark
+	}
+	return 0
+}
+
+// NodeSuspected notifies about a leader being suspected by this node, i.e., reported to the leader selection policy.
+type NodeSuspected struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Epoch                uint64   `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeSuspected) Reset()         { *m = NodeSuspected{} }
+func (m *NodeSuspected) String() string { return proto.CompactTextString(m) }
+func (*NodeSuspected) ProtoMessage()    {}
+func (*NodeSuspected) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{39}
+}
+
+func (m *NodeSuspected) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeSuspected.Unmarshal(m, b)
+}
+func (m *NodeSuspected) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeSuspected.Marshal(b, m, deterministic)
+}
+func (m *NodeSuspected) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeSuspected.Merge(m, src)
+}
+func (m *NodeSuspected) XXX_Size() int {
+	return xxx_messageInfo_NodeSuspected.Size(m)
+}
+func (m *NodeSuspected) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeSuspected.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeSuspected proto.InternalMessageInfo
+
+func (m *NodeSuspected) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *NodeSuspected) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+// Equivocation notifies about a node that has been detected sending conflicting messages,
+// e.g., a leader proposing two different batches for the same sequence number.
+type Equivocation struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Epoch                uint64   `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Sn                   uint64   `protobuf:"varint,3,opt,name=sn,proto3" json:"sn,omitempty"`
+	MessageType          string   `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Equivocation) Reset()         { *m = Equivocation{} }
+func (m *Equivocation) String() string { return proto.CompactTextString(m) }
+func (*Equivocation) ProtoMessage()    {}
+func (*Equivocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{40}
+}
+
+func (m *Equivocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Equivocation.Unmarshal(m, b)
+}
+func (m *Equivocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Equivocation.Marshal(b, m, deterministic)
+}
+func (m *Equivocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Equivocation.Merge(m, src)
+}
+func (m *Equivocation) XXX_Size() int {
+	return xxx_messageInfo_Equivocation.Size(m)
+}
+func (m *Equivocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Equivocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Equivocation proto.InternalMessageInfo
+
+func (m *Equivocation) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *Equivocation) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *Equivocation) GetSn() uint64 {
+	if m != nil {
+		return m.Sn
+	}
+	return 0
+}
+
+func (m *Equivocation) GetMessageType() string {
+	if m != nil {
+		return m.MessageType
+	}
+	return ""
+}
+
+// NodeSlow notifies about a node persistently lagging behind the stable checkpoints of this node
+// (see iss.Config.MaxCheckpointLag), or about such a node having caught up.
+type NodeSlow struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Epoch                uint64   `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	StableSn             uint64   `protobuf:"varint,3,opt,name=stable_sn,json=stableSn,proto3" json:"stable_sn,omitempty"`
+	CheckpointSn         uint64   `protobuf:"varint,4,opt,name=checkpoint_sn,json=checkpointSn,proto3" json:"checkpoint_sn,omitempty"`
+	CaughtUp             bool     `protobuf:"varint,5,opt,name=caught_up,json=caughtUp,proto3" json:"caught_up,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeSlow) Reset()         { *m = NodeSlow{} }
+func (m *NodeSlow) String() string { return proto.CompactTextString(m) }
+func (*NodeSlow) ProtoMessage()    {}
+func (*NodeSlow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{41}
+}
+
+func (m *NodeSlow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeSlow.Unmarshal(m, b)
+}
+func (m *NodeSlow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeSlow.Marshal(b, m, deterministic)
+}
+func (m *NodeSlow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeSlow.Merge(m, src)
+}
+func (m *NodeSlow) XXX_Size() int {
+	return xxx_messageInfo_NodeSlow.Size(m)
+}
+func (m *NodeSlow) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeSlow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeSlow proto.InternalMessageInfo
+
+func (m *NodeSlow) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *NodeSlow) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *NodeSlow) GetStableSn() uint64 {
+	if m != nil {
+		return m.StableSn
+	}
+	return 0
+}
+
+func (m *NodeSlow) GetCheckpointSn() uint64 {
+	if m != nil {
+		return m.CheckpointSn
+	}
+	return 0
+}
+
+func (m *NodeSlow) GetCaughtUp() bool {
+	if m != nil {
+		return m.CaughtUp
+	}
+	return false
+}
+
+// SlowPersist notifies about a persistence operation (e.g. syncing the WAL) having taken longer
+// than mirbft.NodeConfig.SlowPersistThreshold, which usually indicates a slow disk.
+type SlowPersist struct {
+	Operation            string   `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	DurationUs           uint64   `protobuf:"varint,2,opt,name=duration_us,json=durationUs,proto3" json:"duration_us,omitempty"`
+	ThresholdUs          uint64   `protobuf:"varint,3,opt,name=threshold_us,json=thresholdUs,proto3" json:"threshold_us,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SlowPersist) Reset()         { *m = SlowPersist{} }
+func (m *SlowPersist) String() string { return proto.CompactTextString(m) }
+func (*SlowPersist) ProtoMessage()    {}
+func (*SlowPersist) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{42}
+}
+
+func (m *SlowPersist) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SlowPersist.Unmarshal(m, b)
+}
+func (m *SlowPersist) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SlowPersist.Marshal(b, m, deterministic)
+}
+func (m *SlowPersist) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SlowPersist.Merge(m, src)
+}
+func (m *SlowPersist) XXX_Size() int {
+	return xxx_messageInfo_SlowPersist.Size(m)
+}
+func (m *SlowPersist) XXX_DiscardUnknown() {
+	xxx_messageInfo_SlowPersist.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SlowPersist proto.InternalMessageInfo
+
+func (m *SlowPersist) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *SlowPersist) GetDurationUs() uint64 {
+	if m != nil {
+		return m.DurationUs
+	}
+	return 0
+}
+
+func (m *SlowPersist) GetThresholdUs() uint64 {
+	if m != nil {
+		return m.ThresholdUs
+	}
+	return 0
+}
+
+// Oddity notifies about suspicious behavior of another node observed by this node,
+// e.g., a malformed or oversized message, which may indicate a misbehaving or buggy peer.
+type Oddity struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Kind                 string   `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Timestamp            int64    `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Oddity) Reset()         { *m = Oddity{} }
+func (m *Oddity) String() string { return proto.CompactTextString(m) }
+func (*Oddity) ProtoMessage()    {}
+func (*Oddity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{43}
+}
+
+func (m *Oddity) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Oddity.Unmarshal(m, b)
+}
+func (m *Oddity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Oddity.Marshal(b, m, deterministic)
+}
+func (m *Oddity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Oddity.Merge(m, src)
+}
+func (m *Oddity) XXX_Size() int {
+	return xxx_messageInfo_Oddity.Size(m)
+}
+func (m *Oddity) XXX_DiscardUnknown() {
+	xxx_messageInfo_Oddity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Oddity proto.InternalMessageInfo
+
+func (m *Oddity) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *Oddity) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *Oddity) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Oddity) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+// ConfigChanged notifies about the configuration changes committed in the previous epoch
+// taking effect with the start of a new epoch.
+type ConfigChanged struct {
+	Epoch                uint64   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Membership           []uint64 `protobuf:"varint,2,rep,packed,name=membership,proto3" json:"membership,omitempty"`
+	Learners             []uint64 `protobuf:"varint,3,rep,packed,name=learners,proto3" json:"learners,omitempty"`
+	RotatedNodes         []uint64 `protobuf:"varint,4,rep,packed,name=rotated_nodes,json=rotatedNodes,proto3" json:"rotated_nodes,omitempty"`
+	UpdatedClients       []uint64 `protobuf:"varint,5,rep,packed,name=updated_clients,json=updatedClients,proto3" json:"updated_clients,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConfigChanged) Reset()         { *m = ConfigChanged{} }
+func (m *ConfigChanged) String() string { return proto.CompactTextString(m) }
+func (*ConfigChanged) ProtoMessage()    {}
+func (*ConfigChanged) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e1d62373b81ab9ca, []int{44}
+}
+
+func (m *ConfigChanged) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigChanged.Unmarshal(m, b)
+}
+func (m *ConfigChanged) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigChanged.Marshal(b, m, deterministic)
+}
+func (m *ConfigChanged) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigChanged.Merge(m, src)
+}
+func (m *ConfigChanged) XXX_Size() int {
+	return xxx_messageInfo_ConfigChanged.Size(m)
+}
+func (m *ConfigChanged) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigChanged.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigChanged proto.InternalMessageInfo
+
+func (m *ConfigChanged) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *ConfigChanged) GetMembership() []uint64 {
+	if m != nil {
+		return m.Membership
+	}
+	return nil
+}
+
+func (m *ConfigChanged) GetLearners() []uint64 {
+	if m != nil {
+		return m.Learners
+	}
+	return nil
+}
+
+func (m *ConfigChanged) GetRotatedNodes() []uint64 {
+	if m != nil {
+		return m.RotatedNodes
+	}
+	return nil
+}
+
+func (m *ConfigChanged) GetUpdatedClients() []uint64 {
+	if m != nil {
+		return m.UpdatedClients
 	}
 	return nil
 }
@@ -1186,7 +3029,7 @@ func (m *StoreDummyRequest) Reset()         { *m = StoreDummyRequest{} }
 func (m *StoreDummyRequest) String() string { return proto.CompactTextString(m) }
 func (*StoreDummyRequest) ProtoMessage()    {}
 func (*StoreDummyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{18}
+	return fileDescriptor_e1d62373b81ab9ca, []int{45}
 }
 
 func (m *StoreDummyRequest) XXX_Unmarshal(b []byte) error {
@@ -1233,7 +3076,7 @@ func (m *PersistDummyBatch) Reset()         { *m = PersistDummyBatch{} }
 func (m *PersistDummyBatch) String() string { return proto.CompactTextString(m) }
 func (*PersistDummyBatch) ProtoMessage()    {}
 func (*PersistDummyBatch) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{19}
+	return fileDescriptor_e1d62373b81ab9ca, []int{46}
 }
 
 func (m *PersistDummyBatch) XXX_Unmarshal(b []byte) error {
@@ -1280,7 +3123,7 @@ func (m *AnnounceDummyBatch) Reset()         { *m = AnnounceDummyBatch{} }
 func (m *AnnounceDummyBatch) String() string { return proto.CompactTextString(m) }
 func (*AnnounceDummyBatch) ProtoMessage()    {}
 func (*AnnounceDummyBatch) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e1d62373b81ab9ca, []int{20}
+	return fileDescriptor_e1d62373b81ab9ca, []int{47}
 }
 
 func (m *AnnounceDummyBatch) XXX_Unmarshal(b []byte) error {
@@ -1317,11 +3160,13 @@ func (m *AnnounceDummyBatch) GetBatch() *requestpb.Batch {
 
 func init() {
 	proto.RegisterType((*Event)(nil), "eventpb.Event")
+	proto.RegisterType((*EventList)(nil), "eventpb.EventList")
 	proto.RegisterType((*Init)(nil), "eventpb.Init")
 	proto.RegisterType((*Tick)(nil), "eventpb.Tick")
 	proto.RegisterType((*HashRequest)(nil), "eventpb.HashRequest")
 	proto.RegisterType((*HashResult)(nil), "eventpb.HashResult")
 	proto.RegisterType((*HashOrigin)(nil), "eventpb.HashOrigin")
+	proto.RegisterType((*ForwardedRequest)(nil), "eventpb.ForwardedRequest")
 	proto.RegisterType((*RequestReady)(nil), "eventpb.RequestReady")
 	proto.RegisterType((*SendMessage)(nil), "eventpb.SendMessage")
 	proto.RegisterType((*MessageReceived)(nil), "eventpb.MessageReceived")
@@ -1332,8 +3177,33 @@ func init() {
 	proto.RegisterType((*VerifyRequestSig)(nil), "eventpb.VerifyRequestSig")
 	proto.RegisterType((*RequestSigVerified)(nil), "eventpb.RequestSigVerified")
 	proto.RegisterType((*StoreVerifiedRequest)(nil), "eventpb.StoreVerifiedRequest")
+	proto.RegisterType((*PruneRequests)(nil), "eventpb.PruneRequests")
+	proto.RegisterType((*ForwardRequests)(nil), "eventpb.ForwardRequests")
 	proto.RegisterType((*AppSnapshotRequest)(nil), "eventpb.AppSnapshotRequest")
 	proto.RegisterType((*AppSnapshot)(nil), "eventpb.AppSnapshot")
+	proto.RegisterType((*AppRestoreState)(nil), "eventpb.AppRestoreState")
+	proto.RegisterType((*PoisonedBatch)(nil), "eventpb.PoisonedBatch")
+	proto.RegisterType((*RequestTooLarge)(nil), "eventpb.RequestTooLarge")
+	proto.RegisterType((*MessageTooLarge)(nil), "eventpb.MessageTooLarge")
+	proto.RegisterType((*AppQuery)(nil), "eventpb.AppQuery")
+	proto.RegisterType((*AppQueryResult)(nil), "eventpb.AppQueryResult")
+	proto.RegisterType((*UpdateClientKeys)(nil), "eventpb.UpdateClientKeys")
+	proto.RegisterType((*UpdateNodeKeys)(nil), "eventpb.UpdateNodeKeys")
+	proto.RegisterType((*RetireNodeKeys)(nil), "eventpb.RetireNodeKeys")
+	proto.RegisterType((*StepDown)(nil), "eventpb.StepDown")
+	proto.RegisterType((*LocalParams)(nil), "eventpb.LocalParams")
+	proto.RegisterType((*PeerHealth)(nil), "eventpb.PeerHealth")
+	proto.RegisterType((*SendQueueOverflow)(nil), "eventpb.SendQueueOverflow")
+	proto.RegisterType((*Notification)(nil), "eventpb.Notification")
+	proto.RegisterType((*EpochStarted)(nil), "eventpb.EpochStarted")
+	proto.RegisterType((*CheckpointStable)(nil), "eventpb.CheckpointStable")
+	proto.RegisterType((*ClientWindowMoved)(nil), "eventpb.ClientWindowMoved")
+	proto.RegisterType((*NodeSuspected)(nil), "eventpb.NodeSuspected")
+	proto.RegisterType((*Equivocation)(nil), "eventpb.Equivocation")
+	proto.RegisterType((*NodeSlow)(nil), "eventpb.NodeSlow")
+	proto.RegisterType((*SlowPersist)(nil), "eventpb.SlowPersist")
+	proto.RegisterType((*Oddity)(nil), "eventpb.Oddity")
+	proto.RegisterType((*ConfigChanged)(nil), "eventpb.ConfigChanged")
 	proto.RegisterType((*StoreDummyRequest)(nil), "eventpb.StoreDummyRequest")
 	proto.RegisterType((*PersistDummyBatch)(nil), "eventpb.PersistDummyBatch")
 	proto.RegisterType((*AnnounceDummyBatch)(nil), "eventpb.AnnounceDummyBatch")
@@ -1342,72 +3212,160 @@ func init() {
 func init() { proto.RegisterFile("eventpb/eventpb.proto", fileDescriptor_e1d62373b81ab9ca) }
 
 var fileDescriptor_e1d62373b81ab9ca = []byte{
-	// 1069 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x6d, 0x4f, 0x23, 0x37,
-	0x10, 0xde, 0x40, 0x08, 0x30, 0x09, 0x2f, 0xf1, 0xc1, 0x69, 0xa1, 0xad, 0x84, 0xf6, 0x50, 0x8b,
-	0xd4, 0x36, 0xb9, 0x3b, 0xa4, 0x93, 0x2a, 0xf5, 0x0b, 0x08, 0xaa, 0x45, 0x47, 0x7b, 0xad, 0x73,
-	0xbd, 0x93, 0xee, 0xcb, 0xca, 0xc9, 0x3a, 0xbb, 0x16, 0x89, 0xb3, 0xb5, 0x9d, 0x40, 0xfe, 0x41,
-	0xff, 0x56, 0xff, 0x59, 0x65, 0xaf, 0xf7, 0x25, 0xbb, 0x7c, 0xa0, 0xa8, 0x5f, 0xc0, 0x9e, 0x79,
-	0xe6, 0x99, 0xf1, 0x78, 0xf2, 0x78, 0xe1, 0x90, 0x2e, 0x28, 0x57, 0xc9, 0xb0, 0x6f, 0xff, 0xf7,
-	0x12, 0x31, 0x53, 0x33, 0xb4, 0x69, 0xb7, 0xc7, 0x47, 0x82, 0xfe, 0x35, 0xa7, 0x52, 0x23, 0xf2,
-	0x55, 0x8a, 0x39, 0x3e, 0x9a, 0x52, 0x29, 0x49, 0x44, 0x93, 0x61, 0x3f, 0x5f, 0x59, 0x57, 0x97,
-	0x49, 0x99, 0x0c, 0xfb, 0xe6, 0x6f, 0x6a, 0xf2, 0xfe, 0x01, 0xd8, 0xb8, 0xd6, 0xa4, 0xe8, 0x15,
-	0x34, 0x19, 0x67, 0xca, 0x6d, 0x9c, 0x34, 0xce, 0xda, 0x6f, 0x77, 0x7a, 0x59, 0xe6, 0x1b, 0xce,
-	0x94, 0xef, 0x60, 0xe3, 0xd4, 0x20, 0xc5, 0x46, 0x77, 0xee, 0x5a, 0x05, 0xf4, 0x91, 0x8d, 0xee,
-	0x34, 0x48, 0x3b, 0xd1, 0x39, 0xc0, 0x3d, 0x99, 0x04, 0x24, 0x49, 0x28, 0x0f, 0xdd, 0x75, 0x03,
-	0x45, 0x39, 0xf4, 0xf3, 0xc5, 0xed, 0x85, 0xf1, 0xf8, 0x0e, 0xde, 0xbe, 0x27, 0x93, 0x74, 0x83,
-	0x5e, 0x83, 0xde, 0x04, 0x94, 0x2b, 0xb1, 0x74, 0x9b, 0x26, 0xa6, 0x5b, 0x8e, 0xb9, 0xd6, 0x0e,
-	0xdf, 0xc1, 0x5b, 0xf7, 0x64, 0x62, 0xd6, 0xe8, 0x27, 0xe8, 0xe8, 0x08, 0x25, 0xe6, 0x7c, 0x44,
-	0x14, 0x75, 0x37, 0x4c, 0xd0, 0x41, 0x39, 0xe8, 0xa3, 0xf5, 0xf9, 0x0e, 0x6e, 0xdf, 0x93, 0x49,
-	0xb6, 0x45, 0x3d, 0xd8, 0xb4, 0x6d, 0x73, 0x5b, 0xb6, 0xbc, 0xa2, 0x8d, 0x38, 0x5d, 0xf9, 0x0e,
-	0xce, 0x40, 0x3a, 0x55, 0x4c, 0x64, 0x1c, 0x64, 0x41, 0x9b, 0x95, 0x54, 0x3e, 0x91, 0x71, 0x11,
-	0xd6, 0x8e, 0x8b, 0x2d, 0x7a, 0x07, 0x6d, 0x1b, 0x2a, 0xe7, 0x13, 0xe5, 0x6e, 0x99, 0xc8, 0x17,
-	0x95, 0x48, 0xed, 0xf2, 0x1d, 0x0c, 0x71, 0xbe, 0x43, 0x3f, 0xc3, 0x8e, 0xcd, 0x16, 0x08, 0x4a,
-	0xc2, 0xa5, 0xbb, 0x6d, 0x22, 0x0f, 0xf3, 0x48, 0x9b, 0x00, 0x6b, 0xa7, 0xef, 0xe0, 0x8e, 0x28,
-	0xed, 0x75, 0xc1, 0x92, 0xf2, 0x30, 0xb0, 0x13, 0xe0, 0x42, 0xa5, 0xe0, 0x01, 0xe5, 0xe1, 0xaf,
-	0xa9, 0x4f, 0x17, 0x2c, 0x8b, 0x2d, 0xba, 0x86, 0x7d, 0x1b, 0x15, 0x08, 0x3a, 0xa2, 0x6c, 0x41,
-	0x43, 0xb7, 0x6d, 0xc2, 0xdd, 0x3c, 0xdc, 0x62, 0xb1, 0xf5, 0xfb, 0x0e, 0xde, 0x9b, 0xae, 0x9a,
-	0xd0, 0x0f, 0xb0, 0x19, 0xd2, 0x09, 0x5b, 0x50, 0xe1, 0x76, 0x4c, 0xf4, 0x7e, 0x1e, 0x7d, 0x95,
-	0xda, 0x75, 0x83, 0x2d, 0x04, 0xbd, 0x82, 0x75, 0x26, 0xa5, 0xbb, 0x63, 0x90, 0x7b, 0xbd, 0x74,
-	0x42, 0x6f, 0x06, 0x03, 0x33, 0x9a, 0xbe, 0x83, 0xb5, 0x17, 0xdd, 0x00, 0x5a, 0x50, 0xc1, 0xc6,
-	0xcb, 0xec, 0x1e, 0x02, 0xc9, 0x22, 0x77, 0xd7, 0xc4, 0x1c, 0xe5, 0xec, 0x9f, 0x0c, 0xc4, 0x76,
-	0x67, 0xc0, 0x22, 0xdf, 0xc1, 0xfb, 0x8b, 0x8a, 0x0d, 0x7d, 0x80, 0x83, 0x12, 0x47, 0x60, 0xfc,
-	0x8c, 0x86, 0xee, 0x9e, 0x21, 0xfb, 0xaa, 0xda, 0xe4, 0x01, 0x8b, 0x3e, 0x59, 0x88, 0xef, 0x60,
-	0x24, 0x6a, 0x56, 0xf4, 0x27, 0xbc, 0x94, 0x6a, 0x26, 0x68, 0x4e, 0x95, 0xcf, 0xca, 0xbe, 0xa1,
-	0xfc, 0xa6, 0x68, 0xbd, 0x86, 0x65, 0x71, 0xc5, 0xd0, 0x1c, 0xc8, 0x47, 0xec, 0xba, 0x4e, 0x92,
-	0x24, 0x81, 0xe4, 0x24, 0x91, 0xf1, 0x4c, 0xe5, 0xa4, 0xdd, 0x4a, 0x9d, 0x17, 0x49, 0x32, 0xb0,
-	0x98, 0x82, 0x12, 0x91, 0x9a, 0x55, 0x0f, 0x46, 0x99, 0xd0, 0x45, 0x95, 0xc1, 0x28, 0x11, 0xe9,
-	0xc1, 0x28, 0x31, 0xa0, 0x5b, 0x78, 0x91, 0x50, 0x21, 0x99, 0x54, 0x41, 0x38, 0x9f, 0x4e, 0x97,
-	0xc1, 0x90, 0xa8, 0x51, 0xec, 0x52, 0xc3, 0x70, 0x9c, 0x33, 0xfc, 0x9e, 0x62, 0xae, 0x34, 0xe4,
-	0x52, 0x23, 0x7c, 0x07, 0x77, 0x93, 0xaa, 0xd1, 0x9c, 0x8c, 0xf3, 0xd9, 0x9c, 0x8f, 0xe8, 0x0a,
-	0xdd, 0xb8, 0x7a, 0x32, 0x0b, 0x5a, 0xe1, 0x43, 0xa4, 0x66, 0xd5, 0xe5, 0xa5, 0x37, 0x90, 0xb2,
-	0x65, 0x9d, 0x8a, 0x2a, 0xe5, 0x99, 0xf6, 0x9b, 0xb0, 0xa2, 0x51, 0x5d, 0x59, 0x35, 0x22, 0x0f,
-	0x9a, 0x9c, 0x3e, 0x28, 0x37, 0x3c, 0x59, 0x3f, 0x6b, 0xbf, 0xdd, 0xcd, 0xc3, 0xcd, 0x40, 0x62,
-	0xe3, 0xbb, 0x6c, 0x41, 0x53, 0x2d, 0x13, 0xea, 0xb5, 0xa0, 0xa9, 0x45, 0x52, 0xff, 0xd7, 0x3a,
-	0xe8, 0xfd, 0x06, 0xed, 0x92, 0x20, 0x20, 0x04, 0xcd, 0x90, 0x28, 0xe2, 0x36, 0x4e, 0xd6, 0xcf,
-	0x3a, 0xd8, 0xac, 0xd1, 0xf7, 0xd0, 0x9a, 0x09, 0x16, 0x31, 0x6e, 0x95, 0x74, 0x55, 0x10, 0x3e,
-	0x18, 0x17, 0xb6, 0x10, 0xef, 0x0f, 0x80, 0x42, 0x26, 0xd0, 0x4b, 0x68, 0x85, 0x2c, 0xd2, 0x47,
-	0xd3, 0x4a, 0xdd, 0xc1, 0x76, 0xf7, 0xdf, 0x28, 0xaf, 0x52, 0xca, 0xd4, 0x5a, 0x96, 0xc3, 0xc6,
-	0x13, 0xe4, 0x30, 0x3f, 0xf8, 0x2f, 0xd0, 0x29, 0xab, 0x90, 0xd6, 0xba, 0x42, 0xb3, 0xc6, 0x96,
-	0xeb, 0xb0, 0xce, 0x85, 0xe9, 0x18, 0x43, 0xae, 0x57, 0x63, 0xef, 0x33, 0xb4, 0x4b, 0x82, 0x84,
-	0x3c, 0xe8, 0x84, 0x54, 0x2a, 0xc6, 0x89, 0x62, 0x33, 0x2e, 0x4d, 0xe3, 0x9a, 0x78, 0xc5, 0x86,
-	0x4e, 0x61, 0x7d, 0x2a, 0x23, 0x7b, 0x54, 0xd4, 0x2b, 0x5e, 0xba, 0x4c, 0x9a, 0xb4, 0xdb, 0x7b,
-	0x0f, 0x7b, 0x15, 0xa9, 0xd2, 0xb7, 0x31, 0x16, 0xb3, 0xa9, 0x29, 0xae, 0x89, 0xcd, 0xfa, 0x89,
-	0x64, 0x5f, 0x60, 0x3b, 0x7f, 0xbb, 0xd0, 0x29, 0x6c, 0x98, 0xf6, 0xda, 0x43, 0x56, 0x07, 0x24,
-	0x75, 0xa2, 0xef, 0x60, 0x4f, 0x50, 0x45, 0xb9, 0xae, 0x39, 0x60, 0x3c, 0xa4, 0x0f, 0x26, 0x49,
-	0x13, 0xef, 0xe6, 0xe6, 0x1b, 0x6d, 0xf5, 0x5e, 0xc3, 0x56, 0xf6, 0xc6, 0x3d, 0x8d, 0xda, 0x7b,
-	0x07, 0xed, 0xd2, 0x03, 0xf7, 0x58, 0xa6, 0xc6, 0xa3, 0x99, 0x2e, 0x60, 0xd3, 0xea, 0x2f, 0xda,
-	0x85, 0x35, 0xc9, 0x2d, 0x6c, 0x4d, 0x72, 0xf4, 0x2d, 0x6c, 0xa4, 0xbf, 0xc1, 0x35, 0x2b, 0xd8,
-	0xc5, 0xc5, 0x99, 0x9f, 0x18, 0x4e, 0xdd, 0x5e, 0x0c, 0xfb, 0x55, 0x91, 0x7d, 0xee, 0xd5, 0xa3,
-	0xaf, 0x61, 0x5b, 0xb2, 0x88, 0x13, 0x35, 0x17, 0xd4, 0xe4, 0xed, 0xe0, 0xc2, 0xe0, 0x3d, 0x00,
-	0xaa, 0x2b, 0xf0, 0xb3, 0x73, 0x1d, 0xc0, 0xc6, 0x82, 0x4c, 0x58, 0x68, 0xf2, 0x6c, 0xe1, 0x74,
-	0xa3, 0xad, 0x54, 0x88, 0x99, 0x30, 0x1f, 0x2a, 0xdb, 0x38, 0xdd, 0x78, 0x7f, 0x37, 0xe0, 0xe0,
-	0x31, 0xa5, 0x7e, 0x76, 0xf2, 0x4c, 0x05, 0xd2, 0x33, 0xa6, 0x2a, 0x70, 0x0a, 0x3b, 0x64, 0xae,
-	0x62, 0x7d, 0x3d, 0x23, 0xa2, 0x6c, 0x09, 0x1d, 0xbc, 0x6a, 0xf4, 0x4e, 0x01, 0xd5, 0xe5, 0xbd,
-	0x7a, 0x79, 0xde, 0x1b, 0x68, 0x97, 0x50, 0xb5, 0xbb, 0x7d, 0x24, 0xbd, 0x17, 0x40, 0xb7, 0xa6,
-	0x86, 0xff, 0xe7, 0xf9, 0xbc, 0xf7, 0xd0, 0xad, 0xbd, 0x06, 0xcf, 0x9e, 0xba, 0x5b, 0x40, 0xf5,
-	0xb7, 0xe0, 0xb9, 0x6c, 0x97, 0xe7, 0x5f, 0xde, 0x44, 0x4c, 0xc5, 0xf3, 0x61, 0x6f, 0x34, 0x9b,
-	0xf6, 0xe3, 0x65, 0x42, 0xc5, 0x84, 0x86, 0x11, 0x15, 0x3f, 0x4e, 0xc8, 0x50, 0xf6, 0xa7, 0x4c,
-	0x0c, 0xc7, 0xaa, 0x9f, 0xdc, 0x45, 0xfd, 0xe2, 0x23, 0x7c, 0xd8, 0x32, 0xdf, 0xcc, 0xe7, 0xff,
-	0x06, 0x00, 0x00, 0xff, 0xff, 0x0f, 0xe6, 0x74, 0x2f, 0x9e, 0x0b, 0x00, 0x00,
+	// 2477 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0x5b, 0x73, 0x1b, 0xb7,
+	0xf5, 0xa7, 0x24, 0x4a, 0x22, 0x0f, 0x29, 0x91, 0x84, 0x65, 0x67, 0x7d, 0x49, 0xe2, 0xac, 0x9d,
+	0xfc, 0xf3, 0x6f, 0x5a, 0x2b, 0x89, 0x67, 0x32, 0xcd, 0xf4, 0x36, 0xf2, 0x25, 0x43, 0x8d, 0x15,
+	0x5f, 0x96, 0x56, 0x3c, 0x75, 0x1f, 0x76, 0xc0, 0x5d, 0x90, 0xdc, 0xd1, 0x72, 0x77, 0x0d, 0xec,
+	0x8a, 0x62, 0x3f, 0x41, 0x9f, 0xfa, 0xdc, 0x6f, 0xd1, 0xa7, 0x7e, 0x82, 0xce, 0x74, 0xfa, 0xb1,
+	0x3a, 0x07, 0xc0, 0xde, 0x40, 0x2a, 0x63, 0x6b, 0xf2, 0x22, 0xed, 0xf9, 0x9d, 0x0b, 0x80, 0x83,
+	0x83, 0x83, 0x73, 0x40, 0xb8, 0xce, 0xce, 0x59, 0x94, 0x26, 0xe3, 0x43, 0xfd, 0xff, 0x41, 0xc2,
+	0xe3, 0x34, 0x26, 0xbb, 0x9a, 0xbc, 0x75, 0x93, 0xb3, 0x77, 0x19, 0x13, 0x28, 0x51, 0x7c, 0x29,
+	0x99, 0x5b, 0x37, 0xe7, 0x4c, 0x08, 0x3a, 0x65, 0xc9, 0xf8, 0xb0, 0xf8, 0xd2, 0xac, 0x41, 0x20,
+	0x44, 0x32, 0x3e, 0x94, 0x7f, 0x15, 0x64, 0xff, 0xe7, 0x1a, 0x6c, 0x3f, 0x45, 0xa3, 0xe4, 0x1e,
+	0x34, 0x83, 0x28, 0x48, 0xad, 0x8d, 0xbb, 0x1b, 0x5f, 0x76, 0xbe, 0xdd, 0x7b, 0x90, 0x8f, 0x7c,
+	0x1c, 0x05, 0xe9, 0xb0, 0xe1, 0x48, 0x26, 0x0a, 0xa5, 0x81, 0x77, 0x66, 0x6d, 0x1a, 0x42, 0xaf,
+	0x03, 0xef, 0x0c, 0x85, 0x90, 0x49, 0x1e, 0x02, 0x2c, 0x68, 0xe8, 0xd2, 0x24, 0x61, 0x91, 0x6f,
+	0x6d, 0x49, 0x51, 0x52, 0x88, 0xbe, 0x39, 0x3a, 0x39, 0x92, 0x9c, 0x61, 0xc3, 0x69, 0x2f, 0x68,
+	0xa8, 0x08, 0xf2, 0x35, 0x20, 0xe1, 0xb2, 0x28, 0xe5, 0x4b, 0xab, 0x29, 0x75, 0x06, 0x55, 0x9d,
+	0xa7, 0xc8, 0x18, 0x36, 0x9c, 0xd6, 0x82, 0x86, 0xf2, 0x9b, 0x7c, 0x0f, 0x5d, 0xd4, 0x48, 0x79,
+	0x16, 0x79, 0x34, 0x65, 0xd6, 0xb6, 0x54, 0x3a, 0xa8, 0x2a, 0xbd, 0xd6, 0xbc, 0x61, 0xc3, 0xe9,
+	0x2c, 0x68, 0x98, 0x93, 0xe4, 0x01, 0xec, 0x6a, 0xb7, 0x59, 0x3b, 0x7a, 0x7a, 0xa5, 0x1b, 0x1d,
+	0xf5, 0x35, 0x6c, 0x38, 0xb9, 0x10, 0x0e, 0x35, 0xa3, 0x62, 0xe6, 0xe6, 0x4a, 0xbb, 0xc6, 0x50,
+	0x43, 0x2a, 0x66, 0xa5, 0x5a, 0x67, 0x56, 0x92, 0xe4, 0x3b, 0xe8, 0x68, 0x55, 0x91, 0x85, 0xa9,
+	0xd5, 0x92, 0x9a, 0xd7, 0x0c, 0x4d, 0x64, 0x0d, 0x1b, 0x0e, 0xcc, 0x0a, 0x8a, 0xfc, 0x1e, 0xf6,
+	0xf4, 0x68, 0x2e, 0x67, 0xd4, 0x5f, 0x5a, 0x6d, 0xa9, 0x79, 0xbd, 0xd0, 0xd4, 0x03, 0x38, 0xc8,
+	0x1c, 0x36, 0x9c, 0x2e, 0xaf, 0xd0, 0x38, 0x61, 0xc1, 0x22, 0xdf, 0xd5, 0x11, 0x60, 0x81, 0x31,
+	0xe1, 0x11, 0x8b, 0xfc, 0x1f, 0x15, 0x0f, 0x27, 0x2c, 0x4a, 0x92, 0x3c, 0x85, 0xbe, 0xd6, 0x72,
+	0x39, 0xf3, 0x58, 0x70, 0xce, 0x7c, 0xab, 0x23, 0xd5, 0xad, 0x42, 0x5d, 0xcb, 0x3a, 0x9a, 0x3f,
+	0x6c, 0x38, 0xbd, 0x79, 0x1d, 0x22, 0xbf, 0x86, 0x5d, 0x9f, 0x85, 0xc1, 0x39, 0xe3, 0x56, 0x57,
+	0x6a, 0xf7, 0x0b, 0xed, 0x27, 0x0a, 0x47, 0x07, 0x6b, 0x11, 0x72, 0x0f, 0xb6, 0x02, 0x21, 0xac,
+	0x3d, 0x29, 0xd9, 0x7b, 0xa0, 0x22, 0xf4, 0x78, 0x34, 0x92, 0xa1, 0x39, 0x6c, 0x38, 0xc8, 0x25,
+	0xc7, 0x40, 0xce, 0x19, 0x0f, 0x26, 0xcb, 0x7c, 0x1f, 0x5c, 0x11, 0x4c, 0xad, 0x7d, 0xa9, 0x73,
+	0xb3, 0xb0, 0xfe, 0x93, 0x14, 0xd1, 0xde, 0x19, 0x05, 0xd3, 0x61, 0xc3, 0xe9, 0x9f, 0x1b, 0x18,
+	0x79, 0x01, 0x07, 0x15, 0x1b, 0xae, 0xe4, 0x07, 0xcc, 0xb7, 0x7a, 0xd2, 0xd8, 0x6d, 0xd3, 0xc9,
+	0xa3, 0x60, 0xfa, 0x93, 0x16, 0x19, 0x36, 0x1c, 0xc2, 0x57, 0x50, 0x72, 0x0a, 0x37, 0x44, 0x1a,
+	0x73, 0x56, 0x98, 0x2a, 0x62, 0xa5, 0x2f, 0x4d, 0x7e, 0x5c, 0xba, 0x1e, 0xc5, 0x72, 0xbd, 0x32,
+	0x68, 0x0e, 0xc4, 0x1a, 0x1c, 0xe7, 0x49, 0x93, 0xc4, 0x15, 0x11, 0x4d, 0xc4, 0x2c, 0x4e, 0x0b,
+	0xa3, 0x03, 0x63, 0x9e, 0x47, 0x49, 0x32, 0xd2, 0x32, 0xa5, 0x49, 0x42, 0x57, 0x50, 0x0c, 0x8c,
+	0xaa, 0x41, 0x8b, 0x18, 0x81, 0x51, 0x31, 0x84, 0x81, 0x51, 0xb1, 0x40, 0x7e, 0x80, 0x01, 0xaa,
+	0x72, 0xa6, 0x16, 0x2a, 0x52, 0x3c, 0x74, 0xd7, 0x8c, 0xc8, 0x38, 0x4a, 0x12, 0x47, 0x09, 0x8c,
+	0x52, 0x75, 0xf0, 0x7a, 0xb4, 0x0e, 0x91, 0x3f, 0xc1, 0x7e, 0x12, 0x07, 0x22, 0x8e, 0x98, 0xef,
+	0x8e, 0x69, 0xea, 0xcd, 0xac, 0x03, 0x69, 0xe4, 0x46, 0x61, 0xe4, 0xa5, 0x66, 0x3f, 0x42, 0xee,
+	0xb0, 0xe1, 0xec, 0x25, 0x55, 0x40, 0x1a, 0xe0, 0x59, 0xc4, 0x72, 0x6f, 0x08, 0xeb, 0xba, 0x69,
+	0x00, 0xd9, 0x7a, 0xc9, 0x42, 0x1a, 0xa8, 0x02, 0x18, 0xe2, 0x93, 0x98, 0x2f, 0x28, 0xf7, 0x4b,
+	0x13, 0x37, 0x8c, 0x85, 0xfc, 0xa0, 0x04, 0x2a, 0x46, 0x7a, 0x93, 0x3a, 0x84, 0x0e, 0xc9, 0x83,
+	0x28, 0x8d, 0x63, 0x37, 0xa4, 0x7c, 0xca, 0xac, 0x8f, 0x0c, 0x3b, 0x5a, 0xfa, 0x75, 0x1c, 0x9f,
+	0x20, 0x1f, 0xed, 0xf0, 0x3a, 0x84, 0x29, 0x22, 0x61, 0x8c, 0xbb, 0x33, 0x46, 0xc3, 0x74, 0x66,
+	0x59, 0x46, 0x8a, 0x78, 0xc9, 0x18, 0x1f, 0x4a, 0x16, 0xa6, 0x88, 0xa4, 0xa0, 0xc8, 0x10, 0x06,
+	0x7a, 0x4a, 0x95, 0x70, 0xbb, 0x69, 0x1c, 0x87, 0x1f, 0x72, 0x89, 0x32, 0x2e, 0xfa, 0x13, 0x03,
+	0x23, 0x27, 0x70, 0x4d, 0xa6, 0x8b, 0x77, 0x19, 0xcb, 0x98, 0x1b, 0x9f, 0x33, 0x3e, 0x09, 0xe3,
+	0x85, 0x75, 0x4b, 0xda, 0xba, 0x55, 0xcb, 0x1a, 0xaf, 0x50, 0xe4, 0x85, 0x96, 0x18, 0x36, 0x9c,
+	0x81, 0x30, 0x41, 0xf4, 0x4b, 0x9e, 0x41, 0x4a, 0xbf, 0xdc, 0x5e, 0x9f, 0x42, 0xaa, 0x7e, 0x99,
+	0xd7, 0x21, 0xf2, 0x3b, 0xe8, 0x46, 0x71, 0x1a, 0x4c, 0x02, 0x8f, 0xa6, 0x41, 0x1c, 0x59, 0x77,
+	0x8c, 0x0c, 0xf8, 0xbc, 0xc2, 0xc4, 0x0c, 0x58, 0x15, 0xc6, 0xfb, 0x04, 0xa3, 0xf5, 0x5d, 0xc6,
+	0xf8, 0xd2, 0xfa, 0xd8, 0xb8, 0x4f, 0x8e, 0x92, 0xe4, 0x55, 0xc6, 0xd4, 0x7d, 0x42, 0xf5, 0x37,
+	0x79, 0x0c, 0xfd, 0x42, 0x23, 0x4f, 0xd7, 0x9f, 0x48, 0xc5, 0x8f, 0x56, 0x14, 0x8b, 0x94, 0xbd,
+	0x4f, 0x6b, 0x08, 0xe6, 0xa8, 0x2c, 0xf1, 0x69, 0xca, 0x5c, 0x2f, 0x0c, 0x58, 0x94, 0xba, 0x67,
+	0x6c, 0x29, 0xac, 0x4f, 0x8d, 0x4d, 0x39, 0x95, 0x22, 0x8f, 0xa5, 0xc4, 0x33, 0xb6, 0xc4, 0xe8,
+	0xea, 0x67, 0x06, 0x86, 0xf3, 0xd1, 0xa6, 0xa2, 0xd8, 0x67, 0xca, 0xd0, 0x5d, 0x63, 0x3e, 0xca,
+	0xd0, 0xf3, 0xd8, 0x67, 0xda, 0xcc, 0x7e, 0x56, 0x43, 0xd0, 0x08, 0x67, 0x69, 0xc0, 0xab, 0x46,
+	0x3e, 0x33, 0x8c, 0x38, 0x52, 0xa0, 0x6a, 0x84, 0xd7, 0x10, 0xf4, 0xa5, 0x48, 0x59, 0xe2, 0xfa,
+	0xf1, 0x22, 0xb2, 0x6c, 0xc3, 0x97, 0xa3, 0x94, 0x25, 0x4f, 0xe2, 0x05, 0xee, 0x40, 0x4b, 0xe8,
+	0x6f, 0x4c, 0x33, 0x61, 0xec, 0xd1, 0xd0, 0x4d, 0x28, 0xa7, 0x73, 0x61, 0xdd, 0x33, 0xd2, 0xcc,
+	0x09, 0x32, 0x5f, 0x4a, 0x1e, 0xa6, 0x99, 0xb0, 0x24, 0x31, 0x16, 0x13, 0xc6, 0x45, 0x20, 0x52,
+	0xd7, 0xcf, 0xe6, 0xf3, 0xa5, 0xce, 0x11, 0xcc, 0x88, 0xc5, 0x97, 0x4a, 0xe6, 0x09, 0x8a, 0xe4,
+	0x79, 0x62, 0x90, 0x98, 0xa0, 0x4c, 0xa0, 0x51, 0x14, 0x67, 0x91, 0xc7, 0x6a, 0xe6, 0x26, 0x66,
+	0x02, 0xd5, 0x42, 0x35, 0x7b, 0x84, 0xae, 0xa0, 0xf2, 0xa8, 0xc8, 0xfc, 0xa7, 0xac, 0xe5, 0xc7,
+	0x6e, 0x6a, 0x1e, 0x15, 0x94, 0x91, 0x6a, 0xe5, 0xb9, 0x1b, 0x08, 0x13, 0x24, 0x36, 0x34, 0x23,
+	0x76, 0x91, 0x5a, 0xfe, 0xdd, 0xad, 0x2f, 0x3b, 0xdf, 0xee, 0x17, 0xea, 0xf2, 0xde, 0x73, 0x24,
+	0x8f, 0xdc, 0x81, 0xb6, 0x47, 0x33, 0x41, 0x43, 0x37, 0xf0, 0xad, 0xff, 0x62, 0x79, 0xd6, 0x74,
+	0x5a, 0x0a, 0x39, 0xf6, 0x1f, 0xed, 0x40, 0x33, 0x5d, 0x26, 0xcc, 0x7e, 0x08, 0x6d, 0xa9, 0x74,
+	0x12, 0x88, 0x94, 0x7c, 0x01, 0x3b, 0xd2, 0x92, 0xb0, 0x36, 0xd6, 0x1a, 0xd6, 0x5c, 0x7b, 0x07,
+	0x9a, 0x58, 0xde, 0xe1, 0x7f, 0xac, 0xe0, 0xec, 0xe7, 0xd0, 0xa9, 0x94, 0x32, 0x84, 0x40, 0xd3,
+	0xa7, 0x29, 0x95, 0x46, 0xba, 0x8e, 0xfc, 0x26, 0x5f, 0xc1, 0x4e, 0xcc, 0x83, 0x69, 0x10, 0x59,
+	0x9b, 0x46, 0x9e, 0x42, 0xcd, 0x17, 0x92, 0xe5, 0x68, 0x11, 0xfb, 0x15, 0x40, 0x59, 0xe0, 0x90,
+	0x1b, 0xb0, 0xe3, 0x07, 0x53, 0xf4, 0x16, 0x2e, 0xa2, 0xeb, 0x68, 0xea, 0xc3, 0x4c, 0xfe, 0x7d,
+	0x03, 0xa0, 0x84, 0xab, 0x95, 0xdc, 0xc6, 0xfb, 0x54, 0x72, 0x6b, 0x73, 0xe6, 0xe6, 0x15, 0x72,
+	0x66, 0xe1, 0xf8, 0xd7, 0xd0, 0x37, 0xe5, 0xd1, 0x71, 0x13, 0x1e, 0xcf, 0x2d, 0xb5, 0x59, 0xf2,
+	0x1b, 0x0b, 0xa2, 0xfa, 0x78, 0x6b, 0x66, 0x5a, 0xcc, 0xd3, 0x7e, 0x0b, 0xdd, 0x6a, 0x81, 0x87,
+	0x77, 0x44, 0x59, 0x0e, 0x4e, 0xf4, 0x5a, 0xaf, 0xaf, 0xb1, 0xc0, 0x26, 0x0e, 0x14, 0xa5, 0xe0,
+	0xa4, 0xd8, 0xc2, 0x4d, 0xe9, 0x71, 0xf9, 0x6d, 0xbf, 0x81, 0x4e, 0xa5, 0xfe, 0x23, 0x36, 0x74,
+	0x7d, 0x26, 0xd2, 0x20, 0x92, 0x89, 0x53, 0x85, 0x4c, 0xd3, 0xa9, 0x61, 0xe4, 0x3e, 0x6c, 0xcd,
+	0xc5, 0xb4, 0x98, 0x78, 0xd9, 0x58, 0x68, 0x23, 0x0e, 0xb2, 0xed, 0x67, 0xd0, 0x33, 0x2a, 0xc3,
+	0xb5, 0x9e, 0x78, 0x3f, 0x63, 0x6f, 0xa1, 0x5d, 0xb4, 0x0a, 0xe4, 0x3e, 0x6c, 0xcb, 0xcd, 0xd1,
+	0x0b, 0x37, 0xe3, 0x59, 0x31, 0xc9, 0xff, 0x41, 0x8f, 0xb3, 0x94, 0x45, 0x38, 0x67, 0x37, 0x88,
+	0x7c, 0x76, 0x21, 0x07, 0x69, 0x3a, 0xfb, 0x05, 0x7c, 0x8c, 0xa8, 0xfd, 0x35, 0xb4, 0xf2, 0x96,
+	0xe2, 0xfd, 0x4c, 0xdb, 0xdf, 0x41, 0xa7, 0xd2, 0x4f, 0xac, 0x1b, 0x69, 0x63, 0xed, 0x48, 0x47,
+	0xb0, 0xab, 0xcb, 0x5d, 0xb2, 0x0f, 0x9b, 0x22, 0xd2, 0x62, 0x9b, 0x22, 0x22, 0x5f, 0xc0, 0xb6,
+	0xca, 0x45, 0x9b, 0xba, 0x3e, 0x2e, 0x37, 0x53, 0xa6, 0x1a, 0x47, 0xb1, 0xed, 0x19, 0xf4, 0xcd,
+	0x9a, 0xf6, 0xca, 0xe1, 0x70, 0x07, 0xda, 0x22, 0x98, 0x46, 0x34, 0xcd, 0x38, 0xd3, 0x31, 0x51,
+	0x02, 0xf6, 0x05, 0x90, 0xd5, 0x82, 0xf7, 0xca, 0x63, 0x1d, 0xc0, 0xf6, 0x39, 0x0d, 0x03, 0x5f,
+	0x8e, 0xd3, 0x72, 0x14, 0x81, 0x28, 0xe3, 0x3c, 0xe6, 0xb2, 0x2f, 0x6c, 0x3b, 0x8a, 0xb0, 0xff,
+	0xb6, 0x01, 0x07, 0xeb, 0x0a, 0xe3, 0x5f, 0x32, 0xee, 0xc9, 0x7d, 0xd8, 0xa3, 0x59, 0x3a, 0xc3,
+	0xed, 0xf1, 0x68, 0xaa, 0xa7, 0xd0, 0x75, 0xea, 0xa0, 0xfd, 0x1c, 0xf6, 0x6a, 0xe5, 0x23, 0xb9,
+	0x0d, 0x6d, 0x7d, 0x97, 0x07, 0xbe, 0x95, 0xa7, 0x5f, 0x09, 0x1c, 0xfb, 0xe4, 0x2e, 0x74, 0xc7,
+	0x2c, 0x8c, 0x17, 0x98, 0x4b, 0xdc, 0x28, 0xd6, 0xf1, 0x06, 0x12, 0x73, 0xd8, 0xbb, 0xe7, 0xb1,
+	0x1d, 0x43, 0xcf, 0xa8, 0x25, 0xc9, 0x6f, 0xa1, 0x5b, 0x59, 0x54, 0x9e, 0xa4, 0x2f, 0x59, 0x55,
+	0xa7, 0x5c, 0x95, 0x58, 0x39, 0xab, 0x9b, 0xab, 0x67, 0xd5, 0xbe, 0x0f, 0x64, 0xb5, 0x1d, 0x30,
+	0xa3, 0xcf, 0xfe, 0x06, 0x3a, 0x15, 0x29, 0x93, 0xbd, 0x36, 0x6f, 0x7c, 0x0e, 0x3d, 0xa3, 0xbc,
+	0xaf, 0xdc, 0x10, 0xa5, 0x98, 0x0b, 0x7b, 0xb5, 0x02, 0xfe, 0xaa, 0x81, 0x8f, 0xf7, 0x05, 0x67,
+	0x54, 0xc4, 0x91, 0x8e, 0x15, 0x4d, 0xd9, 0xff, 0xda, 0x80, 0x9e, 0x51, 0x56, 0xff, 0xfc, 0x26,
+	0x5d, 0x87, 0x9d, 0xda, 0xf6, 0x6c, 0x73, 0xdc, 0x19, 0x9c, 0xbc, 0x08, 0xfe, 0xca, 0xa4, 0xf5,
+	0xa6, 0x23, 0xbf, 0xc9, 0x4d, 0x68, 0xcd, 0xe9, 0x85, 0x2b, 0xf1, 0xa6, 0xc4, 0x77, 0xe7, 0xf4,
+	0x62, 0x84, 0xac, 0x3b, 0xd0, 0x2e, 0x2e, 0x01, 0xf9, 0xd8, 0xd0, 0x72, 0x4a, 0x80, 0x7c, 0x06,
+	0xdd, 0x82, 0x70, 0xc7, 0x4b, 0xf9, 0xae, 0xd0, 0x74, 0x3a, 0x05, 0xf6, 0x68, 0x69, 0xbf, 0x2e,
+	0xd2, 0x63, 0x31, 0xed, 0x75, 0xe9, 0x31, 0x9f, 0xd6, 0xe6, 0x25, 0xd3, 0xda, 0xaa, 0x4d, 0xcb,
+	0xfe, 0x1e, 0x5a, 0x79, 0x55, 0x4a, 0x3e, 0xc2, 0x3b, 0x86, 0xfa, 0xa5, 0x0f, 0xd0, 0x65, 0xfe,
+	0xb1, 0x3c, 0x75, 0xaa, 0x12, 0x56, 0xfb, 0xa9, 0x08, 0xfb, 0x0d, 0xec, 0xd7, 0x0b, 0xda, 0xcb,
+	0x0d, 0xc8, 0xbd, 0x40, 0x11, 0x6d, 0x41, 0x53, 0x97, 0x1c, 0xe7, 0xa7, 0xd0, 0x37, 0x4b, 0x5c,
+	0xf2, 0x0d, 0x74, 0xaa, 0x25, 0xb1, 0x8a, 0xf9, 0xbe, 0x6e, 0xf5, 0x0b, 0x39, 0x07, 0xbc, 0x42,
+	0xc5, 0xfe, 0x03, 0xec, 0xd7, 0x0b, 0x5c, 0xf2, 0x15, 0xb4, 0xcb, 0x3a, 0x36, 0xaf, 0x6d, 0x94,
+	0x09, 0x2d, 0xe3, 0xb4, 0x22, 0x2d, 0x6c, 0x7f, 0x05, 0xfb, 0xf5, 0xd2, 0x16, 0xdd, 0x28, 0xd5,
+	0x03, 0x3f, 0xbf, 0xe6, 0x76, 0x91, 0x3e, 0xf6, 0x85, 0x0d, 0xd0, 0xca, 0x2b, 0x59, 0xfb, 0x7b,
+	0xe8, 0x54, 0x0a, 0x54, 0xf2, 0x2b, 0x18, 0xa0, 0xf3, 0x13, 0x1e, 0x27, 0xb1, 0x60, 0xae, 0xcf,
+	0x42, 0xba, 0xd4, 0xee, 0xe9, 0xcd, 0xe9, 0xc5, 0x4b, 0x85, 0x3f, 0x41, 0xd8, 0xfe, 0x33, 0x40,
+	0xd9, 0xaf, 0xa1, 0x3b, 0xf5, 0x78, 0xb9, 0x3b, 0xd5, 0x70, 0x18, 0x4b, 0x9c, 0x51, 0x6f, 0x46,
+	0xc7, 0x21, 0xd3, 0xf9, 0xb1, 0x04, 0x2e, 0x71, 0xea, 0x2b, 0x18, 0xac, 0x34, 0x60, 0xe4, 0x2e,
+	0x74, 0x2a, 0x87, 0x5f, 0x8f, 0x52, 0x85, 0xc8, 0x2d, 0x68, 0x79, 0x3c, 0xc0, 0xec, 0x16, 0xea,
+	0x91, 0x0a, 0xda, 0xfe, 0x77, 0x13, 0xba, 0xd5, 0x2e, 0x0a, 0x5f, 0x9d, 0x58, 0x12, 0x7b, 0x33,
+	0xec, 0xee, 0x79, 0xca, 0xfc, 0x22, 0xe1, 0x16, 0x97, 0x22, 0x72, 0x47, 0x8a, 0x89, 0x3d, 0x17,
+	0xab, 0xd0, 0x58, 0x5c, 0x79, 0x33, 0xe6, 0x9d, 0x25, 0x71, 0x10, 0xa5, 0x68, 0x22, 0x5f, 0x5d,
+	0xb5, 0xb8, 0x7a, 0x5c, 0x48, 0x8c, 0xa4, 0x00, 0x16, 0x57, 0x9e, 0x81, 0x61, 0x95, 0xad, 0x83,
+	0x65, 0x11, 0x44, 0x7e, 0xbc, 0x70, 0xe7, 0x31, 0xbe, 0x43, 0x6d, 0x19, 0x55, 0xb6, 0x0a, 0x9b,
+	0x37, 0x52, 0xe4, 0xc7, 0x58, 0xbd, 0x44, 0x0d, 0x3c, 0x13, 0xc4, 0x07, 0x03, 0xb9, 0x0d, 0x22,
+	0x13, 0x09, 0xf3, 0x70, 0x59, 0x4d, 0xe3, 0xc1, 0x00, 0x23, 0x64, 0x94, 0x73, 0xf1, 0xc1, 0x20,
+	0xaa, 0x02, 0xd8, 0x89, 0xb2, 0x77, 0x59, 0x70, 0x1e, 0xeb, 0x4e, 0x74, 0xdb, 0xf4, 0x4a, 0x85,
+	0x29, 0xbd, 0x52, 0xa1, 0x71, 0x74, 0x2f, 0x8e, 0x26, 0xc1, 0xd4, 0xf5, 0x66, 0x34, 0x9a, 0x32,
+	0xdf, 0xda, 0x31, 0x46, 0x7f, 0x2c, 0xd9, 0x8f, 0x15, 0x17, 0x47, 0xf7, 0xaa, 0x00, 0xf9, 0x7f,
+	0xd8, 0x89, 0x7d, 0x3f, 0x48, 0x97, 0xfa, 0xdd, 0xb1, 0x57, 0x28, 0xbe, 0x90, 0xf0, 0xb0, 0xe1,
+	0x68, 0x01, 0xec, 0xd4, 0xd4, 0x4a, 0xb1, 0x7d, 0x6f, 0x19, 0x9d, 0x9a, 0x5c, 0xa4, 0xea, 0xda,
+	0x5b, 0x91, 0xfe, 0x96, 0x2f, 0x85, 0x78, 0x7f, 0xe9, 0xd6, 0xc9, 0x6a, 0x1b, 0x9d, 0x1a, 0x0a,
+	0xe9, 0x5e, 0x4b, 0xbe, 0x14, 0x96, 0x64, 0x51, 0x01, 0x33, 0xe8, 0x56, 0xc3, 0x42, 0x86, 0x2f,
+	0xd2, 0x3a, 0x1a, 0x15, 0x41, 0x2c, 0xd8, 0x0d, 0x19, 0xf5, 0x19, 0xcf, 0x6f, 0xad, 0x9c, 0x24,
+	0x9f, 0xc3, 0xfe, 0x38, 0xf3, 0xce, 0x58, 0xea, 0xe6, 0x02, 0x5b, 0x52, 0x60, 0x4f, 0xa1, 0x27,
+	0x0a, 0xb4, 0xff, 0x02, 0x7d, 0x33, 0x76, 0x2e, 0x19, 0x4a, 0x5d, 0x38, 0x9b, 0xc5, 0x85, 0xf3,
+	0x99, 0xf1, 0xe8, 0xa5, 0xee, 0xfd, 0xea, 0xe3, 0x96, 0x7d, 0x0a, 0x83, 0x95, 0x60, 0xfa, 0xf9,
+	0x4b, 0xe5, 0x1e, 0xec, 0xa1, 0xdf, 0x16, 0x34, 0x65, 0x7c, 0x4e, 0xf9, 0x99, 0x1e, 0xaf, 0x1b,
+	0xc6, 0x8b, 0x37, 0x39, 0x66, 0xff, 0x11, 0xf6, 0x6a, 0xa1, 0x75, 0x79, 0x46, 0x28, 0x56, 0xb2,
+	0x59, 0x59, 0x89, 0x9d, 0x40, 0xb7, 0x1a, 0x5b, 0x1f, 0xa8, 0xae, 0x1d, 0xb1, 0x55, 0x75, 0x44,
+	0xf1, 0x32, 0xb3, 0x4c, 0xd4, 0x0d, 0xd7, 0x76, 0x3a, 0xf9, 0xc3, 0x0b, 0x6e, 0xe6, 0x3f, 0x36,
+	0xa0, 0x95, 0x07, 0xca, 0x87, 0x0e, 0x77, 0x1b, 0xdf, 0x09, 0x70, 0x5f, 0xdc, 0x62, 0xd4, 0x96,
+	0x02, 0x46, 0x11, 0xfa, 0xab, 0x9a, 0x1c, 0x22, 0x7d, 0xbd, 0x76, 0x2b, 0x67, 0x3f, 0x92, 0x1e,
+	0xa7, 0xd9, 0x74, 0x96, 0xba, 0x59, 0xa2, 0xef, 0xd8, 0x96, 0x02, 0x4e, 0x13, 0x3b, 0x86, 0x4e,
+	0x25, 0x1a, 0x31, 0x87, 0xc6, 0x09, 0xe3, 0x65, 0xe2, 0x6b, 0x3b, 0x25, 0x40, 0x3e, 0x85, 0x8e,
+	0x9f, 0xa9, 0x6f, 0x37, 0x13, 0x79, 0x5d, 0x96, 0x43, 0xa7, 0x02, 0x7d, 0x91, 0xce, 0x38, 0x13,
+	0xb3, 0x38, 0xf4, 0x51, 0x42, 0xcd, 0xb7, 0x53, 0x60, 0xa7, 0xc2, 0xce, 0x60, 0x47, 0x9d, 0xb0,
+	0xcb, 0x1d, 0x41, 0xa0, 0x79, 0x16, 0x44, 0xaa, 0xc6, 0x6d, 0x3b, 0xf2, 0x5b, 0xe7, 0x64, 0x8f,
+	0x07, 0x89, 0x9c, 0x9a, 0x4a, 0xe2, 0x55, 0x08, 0xa7, 0x9e, 0x06, 0x73, 0x26, 0x52, 0x3a, 0x4f,
+	0xa4, 0x1f, 0xb6, 0x9c, 0x12, 0xb0, 0xff, 0xb9, 0x01, 0x7b, 0xb5, 0x94, 0x70, 0x49, 0x98, 0x7f,
+	0x02, 0x30, 0x67, 0xf3, 0x31, 0xe3, 0x62, 0x16, 0x24, 0xfa, 0x50, 0x55, 0x10, 0xcc, 0xfc, 0x21,
+	0xa3, 0x3c, 0x2a, 0x4f, 0x54, 0x41, 0xe3, 0x6e, 0xf0, 0x38, 0xa5, 0x29, 0xf3, 0xe5, 0xc3, 0x90,
+	0xb0, 0x9a, 0xaa, 0x92, 0xd4, 0x20, 0x46, 0x80, 0xc0, 0x2e, 0x47, 0x3d, 0x27, 0xf9, 0xfa, 0x35,
+	0x4b, 0x58, 0xdb, 0x52, 0x4c, 0xbf, 0x32, 0xf9, 0xea, 0xc8, 0x08, 0xdb, 0x85, 0xc1, 0xca, 0x83,
+	0xc7, 0x2f, 0xda, 0xb2, 0x3e, 0x83, 0xc1, 0xca, 0x83, 0xcf, 0x95, 0x1b, 0xaa, 0x13, 0x20, 0xab,
+	0xcf, 0x3d, 0x57, 0xb5, 0xf6, 0xe8, 0xe1, 0xdb, 0x6f, 0xa6, 0x41, 0x3a, 0xcb, 0xc6, 0x0f, 0xbc,
+	0x78, 0x7e, 0x38, 0x5b, 0x26, 0x8c, 0x87, 0xcc, 0x9f, 0x32, 0xfe, 0x9b, 0x90, 0x8e, 0xc5, 0xe1,
+	0x3c, 0xe0, 0xe3, 0x49, 0x7a, 0x98, 0x9c, 0x4d, 0x0f, 0xcb, 0x9f, 0xf3, 0xc6, 0x3b, 0xf2, 0xd7,
+	0xb7, 0x87, 0xff, 0x1b, 0x00, 0xab, 0x79, 0x38, 0x8b, 0xe8, 0x1b, 0x00, 0x00,
 }
@@ -0,0 +1,186 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: fabricpb/fabricpb.proto
+
+package fabricpb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Block represents a Fabric-style block produced by the Fabric adapter from batches delivered by mirbft.
+type Block struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	PreviousHash         []byte   `protobuf:"bytes,2,opt,name=previous_hash,json=previousHash,proto3" json:"previous_hash,omitempty"`
+	DataHash             []byte   `protobuf:"bytes,3,opt,name=data_hash,json=dataHash,proto3" json:"data_hash,omitempty"`
+	Envelopes            [][]byte `protobuf:"bytes,4,rep,name=envelopes,proto3" json:"envelopes,omitempty"`
+	Config               bool     `protobuf:"varint,5,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46a94854e9231920, []int{0}
+}
+
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Block.Unmarshal(m, b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return xxx_messageInfo_Block.Size(m)
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *Block) GetPreviousHash() []byte {
+	if m != nil {
+		return m.PreviousHash
+	}
+	return nil
+}
+
+func (m *Block) GetDataHash() []byte {
+	if m != nil {
+		return m.DataHash
+	}
+	return nil
+}
+
+func (m *Block) GetEnvelopes() [][]byte {
+	if m != nil {
+		return m.Envelopes
+	}
+	return nil
+}
+
+func (m *Block) GetConfig() bool {
+	if m != nil {
+		return m.Config
+	}
+	return false
+}
+
+// AdapterState is the serialized state of the Fabric adapter, used as the application snapshot.
+type AdapterState struct {
+	NextBlockNumber      uint64   `protobuf:"varint,1,opt,name=next_block_number,json=nextBlockNumber,proto3" json:"next_block_number,omitempty"`
+	PreviousHash         []byte   `protobuf:"bytes,2,opt,name=previous_hash,json=previousHash,proto3" json:"previous_hash,omitempty"`
+	Pending              [][]byte `protobuf:"bytes,3,rep,name=pending,proto3" json:"pending,omitempty"`
+	PendingBatches       uint64   `protobuf:"varint,4,opt,name=pending_batches,json=pendingBatches,proto3" json:"pending_batches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdapterState) Reset()         { *m = AdapterState{} }
+func (m *AdapterState) String() string { return proto.CompactTextString(m) }
+func (*AdapterState) ProtoMessage()    {}
+func (*AdapterState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46a94854e9231920, []int{1}
+}
+
+func (m *AdapterState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdapterState.Unmarshal(m, b)
+}
+func (m *AdapterState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdapterState.Marshal(b, m, deterministic)
+}
+func (m *AdapterState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdapterState.Merge(m, src)
+}
+func (m *AdapterState) XXX_Size() int {
+	return xxx_messageInfo_AdapterState.Size(m)
+}
+func (m *AdapterState) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdapterState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdapterState proto.InternalMessageInfo
+
+func (m *AdapterState) GetNextBlockNumber() uint64 {
+	if m != nil {
+		return m.NextBlockNumber
+	}
+	return 0
+}
+
+func (m *AdapterState) GetPreviousHash() []byte {
+	if m != nil {
+		return m.PreviousHash
+	}
+	return nil
+}
+
+func (m *AdapterState) GetPending() [][]byte {
+	if m != nil {
+		return m.Pending
+	}
+	return nil
+}
+
+func (m *AdapterState) GetPendingBatches() uint64 {
+	if m != nil {
+		return m.PendingBatches
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Block)(nil), "fabricpb.Block")
+	proto.RegisterType((*AdapterState)(nil), "fabricpb.AdapterState")
+}
+
+func init() { proto.RegisterFile("fabricpb/fabricpb.proto", fileDescriptor_46a94854e9231920) }
+
+var fileDescriptor_46a94854e9231920 = []byte{
+	// 277 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0xcf, 0x4a, 0xc4, 0x30,
+	0x10, 0x87, 0x89, 0xfb, 0xc7, 0x6e, 0xa8, 0x2e, 0xe6, 0xa0, 0x01, 0x3d, 0x94, 0xf5, 0x60, 0x11,
+	0xdc, 0x82, 0xfa, 0x02, 0xf6, 0xe4, 0xc9, 0x43, 0xbd, 0x79, 0x29, 0x49, 0x3b, 0x6d, 0xc2, 0xb6,
+	0x49, 0x48, 0xd3, 0x45, 0x9f, 0xc4, 0xbb, 0x4f, 0x2a, 0xcd, 0xb6, 0xa8, 0x47, 0x6f, 0xf3, 0xfb,
+	0x66, 0x60, 0xbe, 0x61, 0xf0, 0x45, 0xc5, 0xb8, 0x95, 0x85, 0xe1, 0xc9, 0x54, 0x6c, 0x8d, 0xd5,
+	0x4e, 0x93, 0x60, 0xca, 0x9b, 0x4f, 0x84, 0x17, 0x69, 0xa3, 0x8b, 0x1d, 0x39, 0xc7, 0x4b, 0xd5,
+	0xb7, 0x1c, 0x2c, 0x45, 0x11, 0x8a, 0xe7, 0xd9, 0x98, 0xc8, 0x35, 0x3e, 0x31, 0x16, 0xf6, 0x52,
+	0xf7, 0x5d, 0x2e, 0x58, 0x27, 0xe8, 0x51, 0x84, 0xe2, 0x30, 0x0b, 0x27, 0xf8, 0xcc, 0x3a, 0x41,
+	0x2e, 0xf1, 0xaa, 0x64, 0x8e, 0x1d, 0x06, 0x66, 0x7e, 0x20, 0x18, 0x80, 0x6f, 0x5e, 0xe1, 0x15,
+	0xa8, 0x3d, 0x34, 0xda, 0x40, 0x47, 0xe7, 0xd1, 0x2c, 0x0e, 0xb3, 0x1f, 0x30, 0xec, 0x2d, 0xb4,
+	0xaa, 0x64, 0x4d, 0x17, 0x11, 0x8a, 0x83, 0x6c, 0x4c, 0x9b, 0x2f, 0x84, 0xc3, 0xa7, 0x92, 0x19,
+	0x07, 0xf6, 0xd5, 0x31, 0x07, 0xe4, 0x16, 0x9f, 0x29, 0x78, 0x77, 0x39, 0x1f, 0x74, 0xf3, 0x3f,
+	0xae, 0xeb, 0xa1, 0xe1, 0xcf, 0x78, 0xf9, 0x87, 0x34, 0xc5, 0xc7, 0x06, 0x54, 0x29, 0x55, 0x4d,
+	0x67, 0xde, 0x6a, 0x8a, 0xe4, 0x06, 0xaf, 0xc7, 0x32, 0xe7, 0xcc, 0x15, 0xc2, 0x7b, 0x0f, 0x8b,
+	0x4e, 0x47, 0x9c, 0x1e, 0x68, 0xfa, 0xf8, 0x76, 0x5f, 0x4b, 0x27, 0x7a, 0xbe, 0x2d, 0x74, 0x9b,
+	0x88, 0x0f, 0x03, 0xb6, 0x81, 0xb2, 0x06, 0x7b, 0xd7, 0x30, 0xde, 0x25, 0xad, 0xb4, 0xbc, 0x72,
+	0x89, 0xd9, 0xd5, 0xc9, 0xaf, 0x27, 0xf0, 0xa5, 0xff, 0xc2, 0xc3, 0xf7, 0x00, 0xfb, 0x09, 0x96,
+	0xe1, 0xa0, 0x01, 0x00, 0x00,
+}
@@ -22,11 +22,49 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type EpochChangeReason int32
+
+const (
+	EpochChangeReason_LEADER_UNRESPONSIVE EpochChangeReason = 0
+	EpochChangeReason_LEADER_STATS        EpochChangeReason = 1
+	EpochChangeReason_LEADER_HANDOFF      EpochChangeReason = 2
+	EpochChangeReason_CONFIG_CHANGE       EpochChangeReason = 3
+	EpochChangeReason_RESTORED            EpochChangeReason = 4
+)
+
+var EpochChangeReason_name = map[int32]string{
+	0: "LEADER_UNRESPONSIVE",
+	1: "LEADER_STATS",
+	2: "LEADER_HANDOFF",
+	3: "CONFIG_CHANGE",
+	4: "RESTORED",
+}
+
+var EpochChangeReason_value = map[string]int32{
+	"LEADER_UNRESPONSIVE": 0,
+	"LEADER_STATS":        1,
+	"LEADER_HANDOFF":      2,
+	"CONFIG_CHANGE":       3,
+	"RESTORED":            4,
+}
+
+func (x EpochChangeReason) String() string {
+	return proto.EnumName(EpochChangeReason_name, int32(x))
+}
+
+func (EpochChangeReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{0}
+}
+
 type ISSMessage struct {
 	// Types that are valid to be assigned to Type:
 	//	*ISSMessage_Sb
 	//	*ISSMessage_Checkpoint
 	//	*ISSMessage_RetransmitRequests
+	//	*ISSMessage_FetchRequests
+	//	*ISSMessage_Heartbeat
+	//	*ISSMessage_StateRequest
+	//	*ISSMessage_StateTransfer
 	Type                 isISSMessage_Type `protobuf_oneof:"type"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
@@ -74,12 +112,36 @@ type ISSMessage_RetransmitRequests struct {
 	RetransmitRequests *RetransmitRequests `protobuf:"bytes,3,opt,name=retransmit_requests,json=retransmitRequests,proto3,oneof"`
 }
 
+type ISSMessage_FetchRequests struct {
+	FetchRequests *FetchRequests `protobuf:"bytes,4,opt,name=fetch_requests,json=fetchRequests,proto3,oneof"`
+}
+
+type ISSMessage_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,5,opt,name=heartbeat,proto3,oneof"`
+}
+
+type ISSMessage_StateRequest struct {
+	StateRequest *StateRequest `protobuf:"bytes,6,opt,name=state_request,json=stateRequest,proto3,oneof"`
+}
+
+type ISSMessage_StateTransfer struct {
+	StateTransfer *StateTransfer `protobuf:"bytes,7,opt,name=state_transfer,json=stateTransfer,proto3,oneof"`
+}
+
 func (*ISSMessage_Sb) isISSMessage_Type() {}
 
 func (*ISSMessage_Checkpoint) isISSMessage_Type() {}
 
 func (*ISSMessage_RetransmitRequests) isISSMessage_Type() {}
 
+func (*ISSMessage_FetchRequests) isISSMessage_Type() {}
+
+func (*ISSMessage_Heartbeat) isISSMessage_Type() {}
+
+func (*ISSMessage_StateRequest) isISSMessage_Type() {}
+
+func (*ISSMessage_StateTransfer) isISSMessage_Type() {}
+
 func (m *ISSMessage) GetType() isISSMessage_Type {
 	if m != nil {
 		return m.Type
@@ -108,12 +170,44 @@ func (m *ISSMessage) GetRetransmitRequests() *RetransmitRequests {
 	return nil
 }
 
+func (m *ISSMessage) GetFetchRequests() *FetchRequests {
+	if x, ok := m.GetType().(*ISSMessage_FetchRequests); ok {
+		return x.FetchRequests
+	}
+	return nil
+}
+
+func (m *ISSMessage) GetHeartbeat() *Heartbeat {
+	if x, ok := m.GetType().(*ISSMessage_Heartbeat); ok {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+func (m *ISSMessage) GetStateRequest() *StateRequest {
+	if x, ok := m.GetType().(*ISSMessage_StateRequest); ok {
+		return x.StateRequest
+	}
+	return nil
+}
+
+func (m *ISSMessage) GetStateTransfer() *StateTransfer {
+	if x, ok := m.GetType().(*ISSMessage_StateTransfer); ok {
+		return x.StateTransfer
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*ISSMessage) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*ISSMessage_Sb)(nil),
 		(*ISSMessage_Checkpoint)(nil),
 		(*ISSMessage_RetransmitRequests)(nil),
+		(*ISSMessage_FetchRequests)(nil),
+		(*ISSMessage_Heartbeat)(nil),
+		(*ISSMessage_StateRequest)(nil),
+		(*ISSMessage_StateTransfer)(nil),
 	}
 }
 
@@ -156,10 +250,166 @@ func (m *RetransmitRequests) GetRequests() []*requestpb.RequestRef {
 	return nil
 }
 
+// FetchRequests asks the receiver to forward the payloads of committed requests
+// that the sender is missing (see Config.HashOnlyOrdering).
+type FetchRequests struct {
+	Requests             []*requestpb.RequestRef `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *FetchRequests) Reset()         { *m = FetchRequests{} }
+func (m *FetchRequests) String() string { return proto.CompactTextString(m) }
+func (*FetchRequests) ProtoMessage()    {}
+func (*FetchRequests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{2}
+}
+
+func (m *FetchRequests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FetchRequests.Unmarshal(m, b)
+}
+func (m *FetchRequests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FetchRequests.Marshal(b, m, deterministic)
+}
+func (m *FetchRequests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchRequests.Merge(m, src)
+}
+func (m *FetchRequests) XXX_Size() int {
+	return xxx_messageInfo_FetchRequests.Size(m)
+}
+func (m *FetchRequests) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchRequests.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchRequests proto.InternalMessageInfo
+
+func (m *FetchRequests) GetRequests() []*requestpb.RequestRef {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+// Heartbeat is periodically sent to all other nodes to signal that the sender is alive (see Config.HeartbeatPeriod).
+type Heartbeat struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Heartbeat) Reset()         { *m = Heartbeat{} }
+func (m *Heartbeat) String() string { return proto.CompactTextString(m) }
+func (*Heartbeat) ProtoMessage()    {}
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{3}
+}
+
+func (m *Heartbeat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Heartbeat.Unmarshal(m, b)
+}
+func (m *Heartbeat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Heartbeat.Marshal(b, m, deterministic)
+}
+func (m *Heartbeat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Heartbeat.Merge(m, src)
+}
+func (m *Heartbeat) XXX_Size() int {
+	return xxx_messageInfo_Heartbeat.Size(m)
+}
+func (m *Heartbeat) XXX_DiscardUnknown() {
+	xxx_messageInfo_Heartbeat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Heartbeat proto.InternalMessageInfo
+
+// StateRequest asks the receiver for the state of the system at its latest stable checkpoint.
+// It is periodically sent by a node joining a running network (see Config.Join)
+// until the node obtains the state to start from.
+type StateRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateRequest) Reset()         { *m = StateRequest{} }
+func (m *StateRequest) String() string { return proto.CompactTextString(m) }
+func (*StateRequest) ProtoMessage()    {}
+func (*StateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{4}
+}
+
+func (m *StateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateRequest.Unmarshal(m, b)
+}
+func (m *StateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateRequest.Marshal(b, m, deterministic)
+}
+func (m *StateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateRequest.Merge(m, src)
+}
+func (m *StateRequest) XXX_Size() int {
+	return xxx_messageInfo_StateRequest.Size(m)
+}
+func (m *StateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateRequest proto.InternalMessageInfo
+
+// StateTransfer carries the state of the system at a stable checkpoint (as a response to a StateRequest).
+type StateTransfer struct {
+	Epoch                uint64             `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Checkpoint           *PersistCheckpoint `protobuf:"bytes,2,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StateTransfer) Reset()         { *m = StateTransfer{} }
+func (m *StateTransfer) String() string { return proto.CompactTextString(m) }
+func (*StateTransfer) ProtoMessage()    {}
+func (*StateTransfer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{5}
+}
+
+func (m *StateTransfer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateTransfer.Unmarshal(m, b)
+}
+func (m *StateTransfer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateTransfer.Marshal(b, m, deterministic)
+}
+func (m *StateTransfer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateTransfer.Merge(m, src)
+}
+func (m *StateTransfer) XXX_Size() int {
+	return xxx_messageInfo_StateTransfer.Size(m)
+}
+func (m *StateTransfer) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateTransfer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateTransfer proto.InternalMessageInfo
+
+func (m *StateTransfer) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *StateTransfer) GetCheckpoint() *PersistCheckpoint {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return nil
+}
+
 type SBMessage struct {
 	Epoch                uint64             `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	Instance             uint64             `protobuf:"varint,2,opt,name=instance,proto3" json:"instance,omitempty"`
 	Msg                  *SBInstanceMessage `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	ConfigEpoch          uint64             `protobuf:"varint,4,opt,name=config_epoch,json=configEpoch,proto3" json:"config_epoch,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
 	XXX_sizecache        int32              `json:"-"`
@@ -169,7 +419,7 @@ func (m *SBMessage) Reset()         { *m = SBMessage{} }
 func (m *SBMessage) String() string { return proto.CompactTextString(m) }
 func (*SBMessage) ProtoMessage()    {}
 func (*SBMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{2}
+	return fileDescriptor_67c987db0a07e2d8, []int{6}
 }
 
 func (m *SBMessage) XXX_Unmarshal(b []byte) error {
@@ -211,9 +461,18 @@ func (m *SBMessage) GetMsg() *SBInstanceMessage {
 	return nil
 }
 
+func (m *SBMessage) GetConfigEpoch() uint64 {
+	if m != nil {
+		return m.ConfigEpoch
+	}
+	return 0
+}
+
 type Checkpoint struct {
 	Epoch                uint64   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	Sn                   uint64   `protobuf:"varint,2,opt,name=sn,proto3" json:"sn,omitempty"`
+	Timestamp            int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ConfigEpoch          uint64   `protobuf:"varint,4,opt,name=config_epoch,json=configEpoch,proto3" json:"config_epoch,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -223,7 +482,7 @@ func (m *Checkpoint) Reset()         { *m = Checkpoint{} }
 func (m *Checkpoint) String() string { return proto.CompactTextString(m) }
 func (*Checkpoint) ProtoMessage()    {}
 func (*Checkpoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{3}
+	return fileDescriptor_67c987db0a07e2d8, []int{7}
 }
 
 func (m *Checkpoint) XXX_Unmarshal(b []byte) error {
@@ -258,6 +517,20 @@ func (m *Checkpoint) GetSn() uint64 {
 	return 0
 }
 
+func (m *Checkpoint) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Checkpoint) GetConfigEpoch() uint64 {
+	if m != nil {
+		return m.ConfigEpoch
+	}
+	return 0
+}
+
 type SBInstanceMessage struct {
 	// Types that are valid to be assigned to Type:
 	//	*SBInstanceMessage_PbftPreprepare
@@ -271,7 +544,7 @@ func (m *SBInstanceMessage) Reset()         { *m = SBInstanceMessage{} }
 func (m *SBInstanceMessage) String() string { return proto.CompactTextString(m) }
 func (*SBInstanceMessage) ProtoMessage()    {}
 func (*SBInstanceMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{4}
+	return fileDescriptor_67c987db0a07e2d8, []int{8}
 }
 
 func (m *SBInstanceMessage) XXX_Unmarshal(b []byte) error {
@@ -329,6 +602,7 @@ type ISSEvent struct {
 	//	*ISSEvent_StableCheckpoint
 	//	*ISSEvent_PersistStableCheckpoint
 	//	*ISSEvent_Sb
+	//	*ISSEvent_PersistConfigRequest
 	Type                 isISSEvent_Type `protobuf_oneof:"type"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
@@ -339,7 +613,7 @@ func (m *ISSEvent) Reset()         { *m = ISSEvent{} }
 func (m *ISSEvent) String() string { return proto.CompactTextString(m) }
 func (*ISSEvent) ProtoMessage()    {}
 func (*ISSEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{5}
+	return fileDescriptor_67c987db0a07e2d8, []int{9}
 }
 
 func (m *ISSEvent) XXX_Unmarshal(b []byte) error {
@@ -380,6 +654,10 @@ type ISSEvent_Sb struct {
 	Sb *SBEvent `protobuf:"bytes,4,opt,name=sb,proto3,oneof"`
 }
 
+type ISSEvent_PersistConfigRequest struct {
+	PersistConfigRequest *PersistConfigRequest `protobuf:"bytes,5,opt,name=persist_config_request,json=persistConfigRequest,proto3,oneof"`
+}
+
 func (*ISSEvent_PersistCheckpoint) isISSEvent_Type() {}
 
 func (*ISSEvent_StableCheckpoint) isISSEvent_Type() {}
@@ -388,6 +666,8 @@ func (*ISSEvent_PersistStableCheckpoint) isISSEvent_Type() {}
 
 func (*ISSEvent_Sb) isISSEvent_Type() {}
 
+func (*ISSEvent_PersistConfigRequest) isISSEvent_Type() {}
+
 func (m *ISSEvent) GetType() isISSEvent_Type {
 	if m != nil {
 		return m.Type
@@ -423,6 +703,13 @@ func (m *ISSEvent) GetSb() *SBEvent {
 	return nil
 }
 
+func (m *ISSEvent) GetPersistConfigRequest() *PersistConfigRequest {
+	if x, ok := m.GetType().(*ISSEvent_PersistConfigRequest); ok {
+		return x.PersistConfigRequest
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*ISSEvent) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -430,22 +717,32 @@ func (*ISSEvent) XXX_OneofWrappers() []interface{} {
 		(*ISSEvent_StableCheckpoint)(nil),
 		(*ISSEvent_PersistStableCheckpoint)(nil),
 		(*ISSEvent_Sb)(nil),
+		(*ISSEvent_PersistConfigRequest)(nil),
 	}
 }
 
 type PersistCheckpoint struct {
-	Sn                   uint64   `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
-	AppSnapshot          []byte   `protobuf:"bytes,2,opt,name=app_snapshot,json=appSnapshot,proto3" json:"app_snapshot,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Sn                   uint64             `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
+	AppSnapshot          []byte             `protobuf:"bytes,2,opt,name=app_snapshot,json=appSnapshot,proto3" json:"app_snapshot,omitempty"`
+	ClientWatermarks     []*ClientWatermark `protobuf:"bytes,3,rep,name=client_watermarks,json=clientWatermarks,proto3" json:"client_watermarks,omitempty"`
+	Membership           []uint64           `protobuf:"varint,4,rep,packed,name=membership,proto3" json:"membership,omitempty"`
+	FirstInstance        uint64             `protobuf:"varint,5,opt,name=first_instance,json=firstInstance,proto3" json:"first_instance,omitempty"`
+	NumBuckets           uint64             `protobuf:"varint,6,opt,name=num_buckets,json=numBuckets,proto3" json:"num_buckets,omitempty"`
+	SegmentLength        uint64             `protobuf:"varint,7,opt,name=segment_length,json=segmentLength,proto3" json:"segment_length,omitempty"`
+	ClientKeys           []*ClientKey       `protobuf:"bytes,8,rep,name=client_keys,json=clientKeys,proto3" json:"client_keys,omitempty"`
+	ConfigEpoch          uint64             `protobuf:"varint,9,opt,name=config_epoch,json=configEpoch,proto3" json:"config_epoch,omitempty"`
+	NodeKeys             []*NodeKey         `protobuf:"bytes,10,rep,name=node_keys,json=nodeKeys,proto3" json:"node_keys,omitempty"`
+	Learners             []uint64           `protobuf:"varint,11,rep,packed,name=learners,proto3" json:"learners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *PersistCheckpoint) Reset()         { *m = PersistCheckpoint{} }
 func (m *PersistCheckpoint) String() string { return proto.CompactTextString(m) }
 func (*PersistCheckpoint) ProtoMessage()    {}
 func (*PersistCheckpoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{6}
+	return fileDescriptor_67c987db0a07e2d8, []int{10}
 }
 
 func (m *PersistCheckpoint) XXX_Unmarshal(b []byte) error {
@@ -480,6 +777,116 @@ func (m *PersistCheckpoint) GetAppSnapshot() []byte {
 	return nil
 }
 
+func (m *PersistCheckpoint) GetClientWatermarks() []*ClientWatermark {
+	if m != nil {
+		return m.ClientWatermarks
+	}
+	return nil
+}
+
+func (m *PersistCheckpoint) GetMembership() []uint64 {
+	if m != nil {
+		return m.Membership
+	}
+	return nil
+}
+
+func (m *PersistCheckpoint) GetFirstInstance() uint64 {
+	if m != nil {
+		return m.FirstInstance
+	}
+	return 0
+}
+
+func (m *PersistCheckpoint) GetNumBuckets() uint64 {
+	if m != nil {
+		return m.NumBuckets
+	}
+	return 0
+}
+
+func (m *PersistCheckpoint) GetSegmentLength() uint64 {
+	if m != nil {
+		return m.SegmentLength
+	}
+	return 0
+}
+
+func (m *PersistCheckpoint) GetClientKeys() []*ClientKey {
+	if m != nil {
+		return m.ClientKeys
+	}
+	return nil
+}
+
+func (m *PersistCheckpoint) GetConfigEpoch() uint64 {
+	if m != nil {
+		return m.ConfigEpoch
+	}
+	return 0
+}
+
+func (m *PersistCheckpoint) GetNodeKeys() []*NodeKey {
+	if m != nil {
+		return m.NodeKeys
+	}
+	return nil
+}
+
+func (m *PersistCheckpoint) GetLearners() []uint64 {
+	if m != nil {
+		return m.Learners
+	}
+	return nil
+}
+
+type ClientWatermark struct {
+	ClientId             uint64   `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ReqNo                uint64   `protobuf:"varint,2,opt,name=req_no,json=reqNo,proto3" json:"req_no,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientWatermark) Reset()         { *m = ClientWatermark{} }
+func (m *ClientWatermark) String() string { return proto.CompactTextString(m) }
+func (*ClientWatermark) ProtoMessage()    {}
+func (*ClientWatermark) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{11}
+}
+
+func (m *ClientWatermark) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientWatermark.Unmarshal(m, b)
+}
+func (m *ClientWatermark) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientWatermark.Marshal(b, m, deterministic)
+}
+func (m *ClientWatermark) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientWatermark.Merge(m, src)
+}
+func (m *ClientWatermark) XXX_Size() int {
+	return xxx_messageInfo_ClientWatermark.Size(m)
+}
+func (m *ClientWatermark) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientWatermark.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientWatermark proto.InternalMessageInfo
+
+func (m *ClientWatermark) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ClientWatermark) GetReqNo() uint64 {
+	if m != nil {
+		return m.ReqNo
+	}
+	return 0
+}
+
 type StableCheckpoint struct {
 	Epoch                uint64   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	Sn                   uint64   `protobuf:"varint,2,opt,name=sn,proto3" json:"sn,omitempty"`
@@ -492,7 +899,7 @@ func (m *StableCheckpoint) Reset()         { *m = StableCheckpoint{} }
 func (m *StableCheckpoint) String() string { return proto.CompactTextString(m) }
 func (*StableCheckpoint) ProtoMessage()    {}
 func (*StableCheckpoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{7}
+	return fileDescriptor_67c987db0a07e2d8, []int{12}
 }
 
 func (m *StableCheckpoint) XXX_Unmarshal(b []byte) error {
@@ -541,7 +948,7 @@ func (m *PersistStableCheckpoint) Reset()         { *m = PersistStableCheckpoint
 func (m *PersistStableCheckpoint) String() string { return proto.CompactTextString(m) }
 func (*PersistStableCheckpoint) ProtoMessage()    {}
 func (*PersistStableCheckpoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{8}
+	return fileDescriptor_67c987db0a07e2d8, []int{13}
 }
 
 func (m *PersistStableCheckpoint) XXX_Unmarshal(b []byte) error {
@@ -569,6 +976,55 @@ func (m *PersistStableCheckpoint) GetStableCheckpoint() *StableCheckpoint {
 	return nil
 }
 
+// PersistConfigRequest records the content of a configuration request (see ConfigChange) in the WAL,
+// such that it is known when the request is committed again after recovering from the WAL.
+type PersistConfigRequest struct {
+	RequestRef           *requestpb.RequestRef `protobuf:"bytes,1,opt,name=request_ref,json=requestRef,proto3" json:"request_ref,omitempty"`
+	Data                 []byte                `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *PersistConfigRequest) Reset()         { *m = PersistConfigRequest{} }
+func (m *PersistConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*PersistConfigRequest) ProtoMessage()    {}
+func (*PersistConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{14}
+}
+
+func (m *PersistConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PersistConfigRequest.Unmarshal(m, b)
+}
+func (m *PersistConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PersistConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *PersistConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PersistConfigRequest.Merge(m, src)
+}
+func (m *PersistConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_PersistConfigRequest.Size(m)
+}
+func (m *PersistConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PersistConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PersistConfigRequest proto.InternalMessageInfo
+
+func (m *PersistConfigRequest) GetRequestRef() *requestpb.RequestRef {
+	if m != nil {
+		return m.RequestRef
+	}
+	return nil
+}
+
+func (m *PersistConfigRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type SBEvent struct {
 	Epoch                uint64           `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	Instance             uint64           `protobuf:"varint,2,opt,name=instance,proto3" json:"instance,omitempty"`
@@ -582,7 +1038,7 @@ func (m *SBEvent) Reset()         { *m = SBEvent{} }
 func (m *SBEvent) String() string { return proto.CompactTextString(m) }
 func (*SBEvent) ProtoMessage()    {}
 func (*SBEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{9}
+	return fileDescriptor_67c987db0a07e2d8, []int{15}
 }
 
 func (m *SBEvent) XXX_Unmarshal(b []byte) error {
@@ -635,6 +1091,8 @@ type SBInstanceEvent struct {
 	//	*SBInstanceEvent_BatchReady
 	//	*SBInstanceEvent_WaitForRequests
 	//	*SBInstanceEvent_RequestsReady
+	//	*SBInstanceEvent_StepDown
+	//	*SBInstanceEvent_LocalParams
 	//	*SBInstanceEvent_PbftPersistPreprepare
 	Type                 isSBInstanceEvent_Type `protobuf_oneof:"type"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
@@ -646,7 +1104,7 @@ func (m *SBInstanceEvent) Reset()         { *m = SBInstanceEvent{} }
 func (m *SBInstanceEvent) String() string { return proto.CompactTextString(m) }
 func (*SBInstanceEvent) ProtoMessage()    {}
 func (*SBInstanceEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{10}
+	return fileDescriptor_67c987db0a07e2d8, []int{16}
 }
 
 func (m *SBInstanceEvent) XXX_Unmarshal(b []byte) error {
@@ -707,6 +1165,14 @@ type SBInstanceEvent_RequestsReady struct {
 	RequestsReady *SBRequestsReady `protobuf:"bytes,9,opt,name=requests_ready,json=requestsReady,proto3,oneof"`
 }
 
+type SBInstanceEvent_StepDown struct {
+	StepDown *SBStepDown `protobuf:"bytes,11,opt,name=step_down,json=stepDown,proto3,oneof"`
+}
+
+type SBInstanceEvent_LocalParams struct {
+	LocalParams *SBLocalParams `protobuf:"bytes,12,opt,name=local_params,json=localParams,proto3,oneof"`
+}
+
 type SBInstanceEvent_PbftPersistPreprepare struct {
 	PbftPersistPreprepare *isspbftpb.PersistPreprepare `protobuf:"bytes,10,opt,name=pbft_persist_preprepare,json=pbftPersistPreprepare,proto3,oneof"`
 }
@@ -729,6 +1195,10 @@ func (*SBInstanceEvent_WaitForRequests) isSBInstanceEvent_Type() {}
 
 func (*SBInstanceEvent_RequestsReady) isSBInstanceEvent_Type() {}
 
+func (*SBInstanceEvent_StepDown) isSBInstanceEvent_Type() {}
+
+func (*SBInstanceEvent_LocalParams) isSBInstanceEvent_Type() {}
+
 func (*SBInstanceEvent_PbftPersistPreprepare) isSBInstanceEvent_Type() {}
 
 func (m *SBInstanceEvent) GetType() isSBInstanceEvent_Type {
@@ -801,6 +1271,20 @@ func (m *SBInstanceEvent) GetRequestsReady() *SBRequestsReady {
 	return nil
 }
 
+func (m *SBInstanceEvent) GetStepDown() *SBStepDown {
+	if x, ok := m.GetType().(*SBInstanceEvent_StepDown); ok {
+		return x.StepDown
+	}
+	return nil
+}
+
+func (m *SBInstanceEvent) GetLocalParams() *SBLocalParams {
+	if x, ok := m.GetType().(*SBInstanceEvent_LocalParams); ok {
+		return x.LocalParams
+	}
+	return nil
+}
+
 func (m *SBInstanceEvent) GetPbftPersistPreprepare() *isspbftpb.PersistPreprepare {
 	if x, ok := m.GetType().(*SBInstanceEvent_PbftPersistPreprepare); ok {
 		return x.PbftPersistPreprepare
@@ -820,6 +1304,8 @@ func (*SBInstanceEvent) XXX_OneofWrappers() []interface{} {
 		(*SBInstanceEvent_BatchReady)(nil),
 		(*SBInstanceEvent_WaitForRequests)(nil),
 		(*SBInstanceEvent_RequestsReady)(nil),
+		(*SBInstanceEvent_StepDown)(nil),
+		(*SBInstanceEvent_LocalParams)(nil),
 		(*SBInstanceEvent_PbftPersistPreprepare)(nil),
 	}
 }
@@ -834,7 +1320,7 @@ func (m *SBInit) Reset()         { *m = SBInit{} }
 func (m *SBInit) String() string { return proto.CompactTextString(m) }
 func (*SBInit) ProtoMessage()    {}
 func (*SBInit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{11}
+	return fileDescriptor_67c987db0a07e2d8, []int{17}
 }
 
 func (m *SBInit) XXX_Unmarshal(b []byte) error {
@@ -866,7 +1352,7 @@ func (m *SBCutBatch) Reset()         { *m = SBCutBatch{} }
 func (m *SBCutBatch) String() string { return proto.CompactTextString(m) }
 func (*SBCutBatch) ProtoMessage()    {}
 func (*SBCutBatch) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{12}
+	return fileDescriptor_67c987db0a07e2d8, []int{18}
 }
 
 func (m *SBCutBatch) XXX_Unmarshal(b []byte) error {
@@ -906,7 +1392,7 @@ func (m *SBBatchReady) Reset()         { *m = SBBatchReady{} }
 func (m *SBBatchReady) String() string { return proto.CompactTextString(m) }
 func (*SBBatchReady) ProtoMessage()    {}
 func (*SBBatchReady) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{13}
+	return fileDescriptor_67c987db0a07e2d8, []int{19}
 }
 
 func (m *SBBatchReady) XXX_Unmarshal(b []byte) error {
@@ -953,7 +1439,7 @@ func (m *SBWaitForRequests) Reset()         { *m = SBWaitForRequests{} }
 func (m *SBWaitForRequests) String() string { return proto.CompactTextString(m) }
 func (*SBWaitForRequests) ProtoMessage()    {}
 func (*SBWaitForRequests) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{14}
+	return fileDescriptor_67c987db0a07e2d8, []int{20}
 }
 
 func (m *SBWaitForRequests) XXX_Unmarshal(b []byte) error {
@@ -999,7 +1485,7 @@ func (m *SBRequestsReady) Reset()         { *m = SBRequestsReady{} }
 func (m *SBRequestsReady) String() string { return proto.CompactTextString(m) }
 func (*SBRequestsReady) ProtoMessage()    {}
 func (*SBRequestsReady) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{15}
+	return fileDescriptor_67c987db0a07e2d8, []int{21}
 }
 
 func (m *SBRequestsReady) XXX_Unmarshal(b []byte) error {
@@ -1030,6 +1516,7 @@ func (m *SBRequestsReady) GetSn() uint64 {
 type SBDeliver struct {
 	Sn                   uint64           `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
 	Batch                *requestpb.Batch `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	Handoff              bool             `protobuf:"varint,3,opt,name=handoff,proto3" json:"handoff,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -1039,7 +1526,7 @@ func (m *SBDeliver) Reset()         { *m = SBDeliver{} }
 func (m *SBDeliver) String() string { return proto.CompactTextString(m) }
 func (*SBDeliver) ProtoMessage()    {}
 func (*SBDeliver) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{16}
+	return fileDescriptor_67c987db0a07e2d8, []int{22}
 }
 
 func (m *SBDeliver) XXX_Unmarshal(b []byte) error {
@@ -1074,6 +1561,13 @@ func (m *SBDeliver) GetBatch() *requestpb.Batch {
 	return nil
 }
 
+func (m *SBDeliver) GetHandoff() bool {
+	if m != nil {
+		return m.Handoff
+	}
+	return false
+}
+
 type SBMessageReceived struct {
 	From                 uint64             `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
 	Msg                  *SBInstanceMessage `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
@@ -1086,7 +1580,7 @@ func (m *SBMessageReceived) Reset()         { *m = SBMessageReceived{} }
 func (m *SBMessageReceived) String() string { return proto.CompactTextString(m) }
 func (*SBMessageReceived) ProtoMessage()    {}
 func (*SBMessageReceived) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{17}
+	return fileDescriptor_67c987db0a07e2d8, []int{23}
 }
 
 func (m *SBMessageReceived) XXX_Unmarshal(b []byte) error {
@@ -1132,7 +1626,7 @@ func (m *SBPendingRequests) Reset()         { *m = SBPendingRequests{} }
 func (m *SBPendingRequests) String() string { return proto.CompactTextString(m) }
 func (*SBPendingRequests) ProtoMessage()    {}
 func (*SBPendingRequests) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{18}
+	return fileDescriptor_67c987db0a07e2d8, []int{24}
 }
 
 func (m *SBPendingRequests) XXX_Unmarshal(b []byte) error {
@@ -1170,7 +1664,7 @@ func (m *SBTick) Reset()         { *m = SBTick{} }
 func (m *SBTick) String() string { return proto.CompactTextString(m) }
 func (*SBTick) ProtoMessage()    {}
 func (*SBTick) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{19}
+	return fileDescriptor_67c987db0a07e2d8, []int{25}
 }
 
 func (m *SBTick) XXX_Unmarshal(b []byte) error {
@@ -1191,19 +1685,330 @@ func (m *SBTick) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SBTick proto.InternalMessageInfo
 
+// SBLocalParams adjusts the local parameters of the orderer (see eventpb.LocalParams).
+type SBLocalParams struct {
+	MaxProposeDelay      uint64   `protobuf:"varint,1,opt,name=max_propose_delay,json=maxProposeDelay,proto3" json:"max_propose_delay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SBLocalParams) Reset()         { *m = SBLocalParams{} }
+func (m *SBLocalParams) String() string { return proto.CompactTextString(m) }
+func (*SBLocalParams) ProtoMessage()    {}
+func (*SBLocalParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{26}
+}
+
+func (m *SBLocalParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SBLocalParams.Unmarshal(m, b)
+}
+func (m *SBLocalParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SBLocalParams.Marshal(b, m, deterministic)
+}
+func (m *SBLocalParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SBLocalParams.Merge(m, src)
+}
+func (m *SBLocalParams) XXX_Size() int {
+	return xxx_messageInfo_SBLocalParams.Size(m)
+}
+func (m *SBLocalParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_SBLocalParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SBLocalParams proto.InternalMessageInfo
+
+func (m *SBLocalParams) GetMaxProposeDelay() uint64 {
+	if m != nil {
+		return m.MaxProposeDelay
+	}
+	return 0
+}
+
+// SBStepDown makes the orderer hand off the remainder of its segment, if this node leads it (see eventpb.StepDown).
+type SBStepDown struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SBStepDown) Reset()         { *m = SBStepDown{} }
+func (m *SBStepDown) String() string { return proto.CompactTextString(m) }
+func (*SBStepDown) ProtoMessage()    {}
+func (*SBStepDown) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{27}
+}
+
+func (m *SBStepDown) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SBStepDown.Unmarshal(m, b)
+}
+func (m *SBStepDown) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SBStepDown.Marshal(b, m, deterministic)
+}
+func (m *SBStepDown) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SBStepDown.Merge(m, src)
+}
+func (m *SBStepDown) XXX_Size() int {
+	return xxx_messageInfo_SBStepDown.Size(m)
+}
+func (m *SBStepDown) XXX_DiscardUnknown() {
+	xxx_messageInfo_SBStepDown.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SBStepDown proto.InternalMessageInfo
+
+// ConfigChange is the content of a configuration request, i.e., of a request submitted by the reserved
+// configuration client (see types.ConfigClientID). The change takes effect at the first checkpoint
+// after the request has been committed, i.e., at the start of the epoch following the one it is committed in.
+// The nodes are added before others are removed. Adding a learner to the membership promotes it,
+// i.e., it stops being a learner. A change that would leave the membership empty
+// or make it tolerate fewer faulty nodes than the current membership,
+// or that would result in an invalid configuration, is rejected as a whole.
+type ConfigChange struct {
+	AddNodes             []uint64     `protobuf:"varint,1,rep,packed,name=add_nodes,json=addNodes,proto3" json:"add_nodes,omitempty"`
+	RemoveNodes          []uint64     `protobuf:"varint,2,rep,packed,name=remove_nodes,json=removeNodes,proto3" json:"remove_nodes,omitempty"`
+	NumBuckets           uint64       `protobuf:"varint,3,opt,name=num_buckets,json=numBuckets,proto3" json:"num_buckets,omitempty"`
+	SegmentLength        uint64       `protobuf:"varint,4,opt,name=segment_length,json=segmentLength,proto3" json:"segment_length,omitempty"`
+	AddClients           []*ClientKey `protobuf:"bytes,5,rep,name=add_clients,json=addClients,proto3" json:"add_clients,omitempty"`
+	RemoveClients        []uint64     `protobuf:"varint,6,rep,packed,name=remove_clients,json=removeClients,proto3" json:"remove_clients,omitempty"`
+	RotateNodes          []*NodeKey   `protobuf:"bytes,7,rep,name=rotate_nodes,json=rotateNodes,proto3" json:"rotate_nodes,omitempty"`
+	AddLearners          []uint64     `protobuf:"varint,8,rep,packed,name=add_learners,json=addLearners,proto3" json:"add_learners,omitempty"`
+	RemoveLearners       []uint64     `protobuf:"varint,9,rep,packed,name=remove_learners,json=removeLearners,proto3" json:"remove_learners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ConfigChange) Reset()         { *m = ConfigChange{} }
+func (m *ConfigChange) String() string { return proto.CompactTextString(m) }
+func (*ConfigChange) ProtoMessage()    {}
+func (*ConfigChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{28}
+}
+
+func (m *ConfigChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigChange.Unmarshal(m, b)
+}
+func (m *ConfigChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigChange.Marshal(b, m, deterministic)
+}
+func (m *ConfigChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigChange.Merge(m, src)
+}
+func (m *ConfigChange) XXX_Size() int {
+	return xxx_messageInfo_ConfigChange.Size(m)
+}
+func (m *ConfigChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigChange proto.InternalMessageInfo
+
+func (m *ConfigChange) GetAddNodes() []uint64 {
+	if m != nil {
+		return m.AddNodes
+	}
+	return nil
+}
+
+func (m *ConfigChange) GetRemoveNodes() []uint64 {
+	if m != nil {
+		return m.RemoveNodes
+	}
+	return nil
+}
+
+func (m *ConfigChange) GetNumBuckets() uint64 {
+	if m != nil {
+		return m.NumBuckets
+	}
+	return 0
+}
+
+func (m *ConfigChange) GetSegmentLength() uint64 {
+	if m != nil {
+		return m.SegmentLength
+	}
+	return 0
+}
+
+func (m *ConfigChange) GetAddClients() []*ClientKey {
+	if m != nil {
+		return m.AddClients
+	}
+	return nil
+}
+
+func (m *ConfigChange) GetRemoveClients() []uint64 {
+	if m != nil {
+		return m.RemoveClients
+	}
+	return nil
+}
+
+func (m *ConfigChange) GetRotateNodes() []*NodeKey {
+	if m != nil {
+		return m.RotateNodes
+	}
+	return nil
+}
+
+func (m *ConfigChange) GetAddLearners() []uint64 {
+	if m != nil {
+		return m.AddLearners
+	}
+	return nil
+}
+
+func (m *ConfigChange) GetRemoveLearners() []uint64 {
+	if m != nil {
+		return m.RemoveLearners
+	}
+	return nil
+}
+
+// ClientKey associates a client with its public key, in the representation used by the Crypto module.
+// In the list of client changes made by configuration requests, an empty key represents a removed client.
+// The window is the number of request numbers, starting at the client's low watermark, that the client can use.
+// Requests beyond the window are ignored until the low watermark advances. A window of 0 means no limit,
+// which is only allowed if the aggregate window of the clients is not limited (see iss.Config.MaxClientWindows).
+type ClientKey struct {
+	ClientId             uint64   `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	PubKey               []byte   `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Window               uint64   `protobuf:"varint,3,opt,name=window,proto3" json:"window,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientKey) Reset()         { *m = ClientKey{} }
+func (m *ClientKey) String() string { return proto.CompactTextString(m) }
+func (*ClientKey) ProtoMessage()    {}
+func (*ClientKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{29}
+}
+
+func (m *ClientKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientKey.Unmarshal(m, b)
+}
+func (m *ClientKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientKey.Marshal(b, m, deterministic)
+}
+func (m *ClientKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientKey.Merge(m, src)
+}
+func (m *ClientKey) XXX_Size() int {
+	return xxx_messageInfo_ClientKey.Size(m)
+}
+func (m *ClientKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientKey proto.InternalMessageInfo
+
+func (m *ClientKey) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ClientKey) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *ClientKey) GetWindow() uint64 {
+	if m != nil {
+		return m.Window
+	}
+	return 0
+}
+
+// NodeKey associates a node with its (new) public key, in the representation used by the Crypto module.
+// When a node's key is rotated, the previous key keeps being accepted until the start of retire_epoch
+// (see Config.KeyRotationOverlap). A retire_epoch of 0 means the previous key has already been retired.
+type NodeKey struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	PubKey               []byte   `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	RetireEpoch          uint64   `protobuf:"varint,3,opt,name=retire_epoch,json=retireEpoch,proto3" json:"retire_epoch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeKey) Reset()         { *m = NodeKey{} }
+func (m *NodeKey) String() string { return proto.CompactTextString(m) }
+func (*NodeKey) ProtoMessage()    {}
+func (*NodeKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{30}
+}
+
+func (m *NodeKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeKey.Unmarshal(m, b)
+}
+func (m *NodeKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeKey.Marshal(b, m, deterministic)
+}
+func (m *NodeKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeKey.Merge(m, src)
+}
+func (m *NodeKey) XXX_Size() int {
+	return xxx_messageInfo_NodeKey.Size(m)
+}
+func (m *NodeKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeKey proto.InternalMessageInfo
+
+func (m *NodeKey) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *NodeKey) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *NodeKey) GetRetireEpoch() uint64 {
+	if m != nil {
+		return m.RetireEpoch
+	}
+	return 0
+}
+
 type Status struct {
-	Epoch                uint64      `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
-	Orderers             []*SBStatus `protobuf:"bytes,2,rep,name=orderers,proto3" json:"orderers,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Epoch                 uint64                 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Orderers              []*SBStatus            `protobuf:"bytes,2,rep,name=orderers,proto3" json:"orderers,omitempty"`
+	ClockSkews            []*ClockSkew           `protobuf:"bytes,3,rep,name=clock_skews,json=clockSkews,proto3" json:"clock_skews,omitempty"`
+	BucketCommitLatencies []*CommitLatency       `protobuf:"bytes,4,rep,name=bucket_commit_latencies,json=bucketCommitLatencies,proto3" json:"bucket_commit_latencies,omitempty"`
+	LeaderCommitLatencies []*CommitLatency       `protobuf:"bytes,5,rep,name=leader_commit_latencies,json=leaderCommitLatencies,proto3" json:"leader_commit_latencies,omitempty"`
+	NodeLags              []*NodeLag             `protobuf:"bytes,6,rep,name=node_lags,json=nodeLags,proto3" json:"node_lags,omitempty"`
+	NextDeliveredSn       uint64                 `protobuf:"varint,7,opt,name=next_delivered_sn,json=nextDeliveredSn,proto3" json:"next_delivered_sn,omitempty"`
+	LastStableSn          uint64                 `protobuf:"varint,8,opt,name=last_stable_sn,json=lastStableSn,proto3" json:"last_stable_sn,omitempty"`
+	Buckets               []*BucketStatus        `protobuf:"bytes,9,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Clients               []*ClientStatus        `protobuf:"bytes,10,rep,name=clients,proto3" json:"clients,omitempty"`
+	MessageBuffers        []*MessageBufferStatus `protobuf:"bytes,11,rep,name=message_buffers,json=messageBuffers,proto3" json:"message_buffers,omitempty"`
+	NextEpoch             *EpochTransitionStatus `protobuf:"bytes,12,opt,name=next_epoch,json=nextEpoch,proto3" json:"next_epoch,omitempty"`
+	EpochHistory          []*EpochChange         `protobuf:"bytes,13,rep,name=epoch_history,json=epochHistory,proto3" json:"epoch_history,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}               `json:"-"`
+	XXX_unrecognized      []byte                 `json:"-"`
+	XXX_sizecache         int32                  `json:"-"`
 }
 
 func (m *Status) Reset()         { *m = Status{} }
 func (m *Status) String() string { return proto.CompactTextString(m) }
 func (*Status) ProtoMessage()    {}
 func (*Status) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{20}
+	return fileDescriptor_67c987db0a07e2d8, []int{31}
 }
 
 func (m *Status) XXX_Unmarshal(b []byte) error {
@@ -1238,55 +2043,764 @@ func (m *Status) GetOrderers() []*SBStatus {
 	return nil
 }
 
-type SBStatus struct {
-	Leader               uint64   `protobuf:"varint,1,opt,name=leader,proto3" json:"leader,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Status) GetClockSkews() []*ClockSkew {
+	if m != nil {
+		return m.ClockSkews
+	}
+	return nil
 }
 
-func (m *SBStatus) Reset()         { *m = SBStatus{} }
-func (m *SBStatus) String() string { return proto.CompactTextString(m) }
-func (*SBStatus) ProtoMessage()    {}
-func (*SBStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_67c987db0a07e2d8, []int{21}
+func (m *Status) GetBucketCommitLatencies() []*CommitLatency {
+	if m != nil {
+		return m.BucketCommitLatencies
+	}
+	return nil
 }
 
-func (m *SBStatus) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SBStatus.Unmarshal(m, b)
+func (m *Status) GetLeaderCommitLatencies() []*CommitLatency {
+	if m != nil {
+		return m.LeaderCommitLatencies
+	}
+	return nil
 }
-func (m *SBStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SBStatus.Marshal(b, m, deterministic)
+
+func (m *Status) GetNodeLags() []*NodeLag {
+	if m != nil {
+		return m.NodeLags
+	}
+	return nil
 }
-func (m *SBStatus) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SBStatus.Merge(m, src)
+
+func (m *Status) GetNextDeliveredSn() uint64 {
+	if m != nil {
+		return m.NextDeliveredSn
+	}
+	return 0
 }
-func (m *SBStatus) XXX_Size() int {
-	return xxx_messageInfo_SBStatus.Size(m)
+
+func (m *Status) GetLastStableSn() uint64 {
+	if m != nil {
+		return m.LastStableSn
+	}
+	return 0
 }
-func (m *SBStatus) XXX_DiscardUnknown() {
-	xxx_messageInfo_SBStatus.DiscardUnknown(m)
+
+func (m *Status) GetBuckets() []*BucketStatus {
+	if m != nil {
+		return m.Buckets
+	}
+	return nil
 }
 
-var xxx_messageInfo_SBStatus proto.InternalMessageInfo
+func (m *Status) GetClients() []*ClientStatus {
+	if m != nil {
+		return m.Clients
+	}
+	return nil
+}
 
-func (m *SBStatus) GetLeader() uint64 {
+func (m *Status) GetMessageBuffers() []*MessageBufferStatus {
 	if m != nil {
-		return m.Leader
+		return m.MessageBuffers
 	}
-	return 0
+	return nil
+}
+
+func (m *Status) GetNextEpoch() *EpochTransitionStatus {
+	if m != nil {
+		return m.NextEpoch
+	}
+	return nil
+}
+
+func (m *Status) GetEpochHistory() []*EpochChange {
+	if m != nil {
+		return m.EpochHistory
+	}
+	return nil
+}
+
+// EpochChange describes the start of an epoch and why its leaders (or membership) differ from the previous epoch's.
+type EpochChange struct {
+	Epoch                uint64              `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	FirstSn              uint64              `protobuf:"varint,2,opt,name=first_sn,json=firstSn,proto3" json:"first_sn,omitempty"`
+	Leaders              []uint64            `protobuf:"varint,3,rep,packed,name=leaders,proto3" json:"leaders,omitempty"`
+	Causes               []*EpochChangeCause `protobuf:"bytes,4,rep,name=causes,proto3" json:"causes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *EpochChange) Reset()         { *m = EpochChange{} }
+func (m *EpochChange) String() string { return proto.CompactTextString(m) }
+func (*EpochChange) ProtoMessage()    {}
+func (*EpochChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{32}
+}
+
+func (m *EpochChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EpochChange.Unmarshal(m, b)
+}
+func (m *EpochChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EpochChange.Marshal(b, m, deterministic)
+}
+func (m *EpochChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EpochChange.Merge(m, src)
+}
+func (m *EpochChange) XXX_Size() int {
+	return xxx_messageInfo_EpochChange.Size(m)
+}
+func (m *EpochChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_EpochChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EpochChange proto.InternalMessageInfo
+
+func (m *EpochChange) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *EpochChange) GetFirstSn() uint64 {
+	if m != nil {
+		return m.FirstSn
+	}
+	return 0
+}
+
+func (m *EpochChange) GetLeaders() []uint64 {
+	if m != nil {
+		return m.Leaders
+	}
+	return nil
+}
+
+func (m *EpochChange) GetCauses() []*EpochChangeCause {
+	if m != nil {
+		return m.Causes
+	}
+	return nil
+}
+
+// EpochChangeCause is an event observed during the previous epoch that influenced the started epoch.
+type EpochChangeCause struct {
+	Reason               EpochChangeReason `protobuf:"varint,1,opt,name=reason,proto3,enum=isspb.EpochChangeReason" json:"reason,omitempty"`
+	NodeId               uint64            `protobuf:"varint,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *EpochChangeCause) Reset()         { *m = EpochChangeCause{} }
+func (m *EpochChangeCause) String() string { return proto.CompactTextString(m) }
+func (*EpochChangeCause) ProtoMessage()    {}
+func (*EpochChangeCause) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{33}
+}
+
+func (m *EpochChangeCause) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EpochChangeCause.Unmarshal(m, b)
+}
+func (m *EpochChangeCause) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EpochChangeCause.Marshal(b, m, deterministic)
+}
+func (m *EpochChangeCause) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EpochChangeCause.Merge(m, src)
+}
+func (m *EpochChangeCause) XXX_Size() int {
+	return xxx_messageInfo_EpochChangeCause.Size(m)
+}
+func (m *EpochChangeCause) XXX_DiscardUnknown() {
+	xxx_messageInfo_EpochChangeCause.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EpochChangeCause proto.InternalMessageInfo
+
+func (m *EpochChangeCause) GetReason() EpochChangeReason {
+	if m != nil {
+		return m.Reason
+	}
+	return EpochChangeReason_LEADER_UNRESPONSIVE
+}
+
+func (m *EpochChangeCause) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+// BucketStatus describes a request bucket and the orderer it is assigned to in the current epoch.
+type BucketStatus struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Leader               uint64   `protobuf:"varint,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	Instance             uint64   `protobuf:"varint,3,opt,name=instance,proto3" json:"instance,omitempty"`
+	Requests             uint64   `protobuf:"varint,4,opt,name=requests,proto3" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BucketStatus) Reset()         { *m = BucketStatus{} }
+func (m *BucketStatus) String() string { return proto.CompactTextString(m) }
+func (*BucketStatus) ProtoMessage()    {}
+func (*BucketStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{34}
+}
+
+func (m *BucketStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BucketStatus.Unmarshal(m, b)
+}
+func (m *BucketStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BucketStatus.Marshal(b, m, deterministic)
+}
+func (m *BucketStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BucketStatus.Merge(m, src)
+}
+func (m *BucketStatus) XXX_Size() int {
+	return xxx_messageInfo_BucketStatus.Size(m)
+}
+func (m *BucketStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_BucketStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BucketStatus proto.InternalMessageInfo
+
+func (m *BucketStatus) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *BucketStatus) GetLeader() uint64 {
+	if m != nil {
+		return m.Leader
+	}
+	return 0
+}
+
+func (m *BucketStatus) GetInstance() uint64 {
+	if m != nil {
+		return m.Instance
+	}
+	return 0
+}
+
+func (m *BucketStatus) GetRequests() uint64 {
+	if m != nil {
+		return m.Requests
+	}
+	return 0
+}
+
+// ClientStatus describes the progress of the requests of a single client.
+type ClientStatus struct {
+	ClientId             uint64   `protobuf:"varint,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Ready                uint64   `protobuf:"varint,2,opt,name=ready,proto3" json:"ready,omitempty"`
+	LowWatermark         uint64   `protobuf:"varint,3,opt,name=low_watermark,json=lowWatermark,proto3" json:"low_watermark,omitempty"`
+	Committed            uint64   `protobuf:"varint,4,opt,name=committed,proto3" json:"committed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientStatus) Reset()         { *m = ClientStatus{} }
+func (m *ClientStatus) String() string { return proto.CompactTextString(m) }
+func (*ClientStatus) ProtoMessage()    {}
+func (*ClientStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{35}
+}
+
+func (m *ClientStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientStatus.Unmarshal(m, b)
+}
+func (m *ClientStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientStatus.Marshal(b, m, deterministic)
+}
+func (m *ClientStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientStatus.Merge(m, src)
+}
+func (m *ClientStatus) XXX_Size() int {
+	return xxx_messageInfo_ClientStatus.Size(m)
+}
+func (m *ClientStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientStatus proto.InternalMessageInfo
+
+func (m *ClientStatus) GetClientId() uint64 {
+	if m != nil {
+		return m.ClientId
+	}
+	return 0
+}
+
+func (m *ClientStatus) GetReady() uint64 {
+	if m != nil {
+		return m.Ready
+	}
+	return 0
+}
+
+func (m *ClientStatus) GetLowWatermark() uint64 {
+	if m != nil {
+		return m.LowWatermark
+	}
+	return 0
+}
+
+func (m *ClientStatus) GetCommitted() uint64 {
+	if m != nil {
+		return m.Committed
+	}
+	return 0
+}
+
+// MessageBufferStatus describes the messages buffered for future epochs from a single node.
+type MessageBufferStatus struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Messages             uint64   `protobuf:"varint,2,opt,name=messages,proto3" json:"messages,omitempty"`
+	Size                 uint64   `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Capacity             uint64   `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageBufferStatus) Reset()         { *m = MessageBufferStatus{} }
+func (m *MessageBufferStatus) String() string { return proto.CompactTextString(m) }
+func (*MessageBufferStatus) ProtoMessage()    {}
+func (*MessageBufferStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{36}
+}
+
+func (m *MessageBufferStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageBufferStatus.Unmarshal(m, b)
+}
+func (m *MessageBufferStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageBufferStatus.Marshal(b, m, deterministic)
+}
+func (m *MessageBufferStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageBufferStatus.Merge(m, src)
+}
+func (m *MessageBufferStatus) XXX_Size() int {
+	return xxx_messageInfo_MessageBufferStatus.Size(m)
+}
+func (m *MessageBufferStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageBufferStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageBufferStatus proto.InternalMessageInfo
+
+func (m *MessageBufferStatus) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *MessageBufferStatus) GetMessages() uint64 {
+	if m != nil {
+		return m.Messages
+	}
+	return 0
+}
+
+func (m *MessageBufferStatus) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *MessageBufferStatus) GetCapacity() uint64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+// EpochTransitionStatus describes what the transition to the next epoch is waiting for.
+// The next epoch starts when all sequence numbers of the current epoch have been committed.
+type EpochTransitionStatus struct {
+	Epoch                uint64   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	FirstSn              uint64   `protobuf:"varint,2,opt,name=first_sn,json=firstSn,proto3" json:"first_sn,omitempty"`
+	Uncommitted          uint64   `protobuf:"varint,3,opt,name=uncommitted,proto3" json:"uncommitted,omitempty"`
+	Leaders              []uint64 `protobuf:"varint,4,rep,packed,name=leaders,proto3" json:"leaders,omitempty"`
+	UnresponsiveLeaders  []uint64 `protobuf:"varint,5,rep,packed,name=unresponsive_leaders,json=unresponsiveLeaders,proto3" json:"unresponsive_leaders,omitempty"`
+	HandoffLeaders       []uint64 `protobuf:"varint,6,rep,packed,name=handoff_leaders,json=handoffLeaders,proto3" json:"handoff_leaders,omitempty"`
+	PendingMembership    []uint64 `protobuf:"varint,7,rep,packed,name=pending_membership,json=pendingMembership,proto3" json:"pending_membership,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EpochTransitionStatus) Reset()         { *m = EpochTransitionStatus{} }
+func (m *EpochTransitionStatus) String() string { return proto.CompactTextString(m) }
+func (*EpochTransitionStatus) ProtoMessage()    {}
+func (*EpochTransitionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{37}
+}
+
+func (m *EpochTransitionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EpochTransitionStatus.Unmarshal(m, b)
+}
+func (m *EpochTransitionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EpochTransitionStatus.Marshal(b, m, deterministic)
+}
+func (m *EpochTransitionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EpochTransitionStatus.Merge(m, src)
+}
+func (m *EpochTransitionStatus) XXX_Size() int {
+	return xxx_messageInfo_EpochTransitionStatus.Size(m)
+}
+func (m *EpochTransitionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_EpochTransitionStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EpochTransitionStatus proto.InternalMessageInfo
+
+func (m *EpochTransitionStatus) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *EpochTransitionStatus) GetFirstSn() uint64 {
+	if m != nil {
+		return m.FirstSn
+	}
+	return 0
+}
+
+func (m *EpochTransitionStatus) GetUncommitted() uint64 {
+	if m != nil {
+		return m.Uncommitted
+	}
+	return 0
+}
+
+func (m *EpochTransitionStatus) GetLeaders() []uint64 {
+	if m != nil {
+		return m.Leaders
+	}
+	return nil
+}
+
+func (m *EpochTransitionStatus) GetUnresponsiveLeaders() []uint64 {
+	if m != nil {
+		return m.UnresponsiveLeaders
+	}
+	return nil
+}
+
+func (m *EpochTransitionStatus) GetHandoffLeaders() []uint64 {
+	if m != nil {
+		return m.HandoffLeaders
+	}
+	return nil
+}
+
+func (m *EpochTransitionStatus) GetPendingMembership() []uint64 {
+	if m != nil {
+		return m.PendingMembership
+	}
+	return nil
+}
+
+// CommitLatency is a histogram of the commit latencies of requests, i.e., the times (in ticks of the logical clock)
+// from the requests becoming ready (available and authenticated) at the local node to the requests being committed.
+type CommitLatency struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UpperBounds          []uint64 `protobuf:"varint,2,rep,packed,name=upper_bounds,json=upperBounds,proto3" json:"upper_bounds,omitempty"`
+	Counts               []uint64 `protobuf:"varint,3,rep,packed,name=counts,proto3" json:"counts,omitempty"`
+	Sum                  uint64   `protobuf:"varint,4,opt,name=sum,proto3" json:"sum,omitempty"`
+	Count                uint64   `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitLatency) Reset()         { *m = CommitLatency{} }
+func (m *CommitLatency) String() string { return proto.CompactTextString(m) }
+func (*CommitLatency) ProtoMessage()    {}
+func (*CommitLatency) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{38}
+}
+
+func (m *CommitLatency) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitLatency.Unmarshal(m, b)
+}
+func (m *CommitLatency) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitLatency.Marshal(b, m, deterministic)
+}
+func (m *CommitLatency) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitLatency.Merge(m, src)
+}
+func (m *CommitLatency) XXX_Size() int {
+	return xxx_messageInfo_CommitLatency.Size(m)
+}
+func (m *CommitLatency) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitLatency.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitLatency proto.InternalMessageInfo
+
+func (m *CommitLatency) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *CommitLatency) GetUpperBounds() []uint64 {
+	if m != nil {
+		return m.UpperBounds
+	}
+	return nil
+}
+
+func (m *CommitLatency) GetCounts() []uint64 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+func (m *CommitLatency) GetSum() uint64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *CommitLatency) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// NodeLag describes how far another node lags behind the stable checkpoints of this node.
+type NodeLag struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	CheckpointSn         uint64   `protobuf:"varint,2,opt,name=checkpoint_sn,json=checkpointSn,proto3" json:"checkpoint_sn,omitempty"`
+	Lag                  uint64   `protobuf:"varint,3,opt,name=lag,proto3" json:"lag,omitempty"`
+	BehindCheckpoints    uint64   `protobuf:"varint,4,opt,name=behind_checkpoints,json=behindCheckpoints,proto3" json:"behind_checkpoints,omitempty"`
+	Slow                 bool     `protobuf:"varint,5,opt,name=slow,proto3" json:"slow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeLag) Reset()         { *m = NodeLag{} }
+func (m *NodeLag) String() string { return proto.CompactTextString(m) }
+func (*NodeLag) ProtoMessage()    {}
+func (*NodeLag) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{39}
+}
+
+func (m *NodeLag) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeLag.Unmarshal(m, b)
+}
+func (m *NodeLag) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeLag.Marshal(b, m, deterministic)
+}
+func (m *NodeLag) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeLag.Merge(m, src)
+}
+func (m *NodeLag) XXX_Size() int {
+	return xxx_messageInfo_NodeLag.Size(m)
+}
+func (m *NodeLag) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeLag.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeLag proto.InternalMessageInfo
+
+func (m *NodeLag) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *NodeLag) GetCheckpointSn() uint64 {
+	if m != nil {
+		return m.CheckpointSn
+	}
+	return 0
+}
+
+func (m *NodeLag) GetLag() uint64 {
+	if m != nil {
+		return m.Lag
+	}
+	return 0
+}
+
+func (m *NodeLag) GetBehindCheckpoints() uint64 {
+	if m != nil {
+		return m.BehindCheckpoints
+	}
+	return 0
+}
+
+func (m *NodeLag) GetSlow() bool {
+	if m != nil {
+		return m.Slow
+	}
+	return false
+}
+
+type ClockSkew struct {
+	NodeId               uint64   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	SkewMs               int64    `protobuf:"varint,2,opt,name=skew_ms,json=skewMs,proto3" json:"skew_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClockSkew) Reset()         { *m = ClockSkew{} }
+func (m *ClockSkew) String() string { return proto.CompactTextString(m) }
+func (*ClockSkew) ProtoMessage()    {}
+func (*ClockSkew) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{40}
+}
+
+func (m *ClockSkew) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClockSkew.Unmarshal(m, b)
+}
+func (m *ClockSkew) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClockSkew.Marshal(b, m, deterministic)
+}
+func (m *ClockSkew) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClockSkew.Merge(m, src)
+}
+func (m *ClockSkew) XXX_Size() int {
+	return xxx_messageInfo_ClockSkew.Size(m)
+}
+func (m *ClockSkew) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClockSkew.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClockSkew proto.InternalMessageInfo
+
+func (m *ClockSkew) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *ClockSkew) GetSkewMs() int64 {
+	if m != nil {
+		return m.SkewMs
+	}
+	return 0
+}
+
+type SBStatus struct {
+	Leader               uint64   `protobuf:"varint,1,opt,name=leader,proto3" json:"leader,omitempty"`
+	Instance             uint64   `protobuf:"varint,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	BucketIds            []uint64 `protobuf:"varint,3,rep,packed,name=bucket_ids,json=bucketIds,proto3" json:"bucket_ids,omitempty"`
+	SeqNrs               []uint64 `protobuf:"varint,4,rep,packed,name=seq_nrs,json=seqNrs,proto3" json:"seq_nrs,omitempty"`
+	Proposed             []uint64 `protobuf:"varint,5,rep,packed,name=proposed,proto3" json:"proposed,omitempty"`
+	Committed            []uint64 `protobuf:"varint,6,rep,packed,name=committed,proto3" json:"committed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SBStatus) Reset()         { *m = SBStatus{} }
+func (m *SBStatus) String() string { return proto.CompactTextString(m) }
+func (*SBStatus) ProtoMessage()    {}
+func (*SBStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67c987db0a07e2d8, []int{41}
+}
+
+func (m *SBStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SBStatus.Unmarshal(m, b)
+}
+func (m *SBStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SBStatus.Marshal(b, m, deterministic)
+}
+func (m *SBStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SBStatus.Merge(m, src)
+}
+func (m *SBStatus) XXX_Size() int {
+	return xxx_messageInfo_SBStatus.Size(m)
+}
+func (m *SBStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_SBStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SBStatus proto.InternalMessageInfo
+
+func (m *SBStatus) GetLeader() uint64 {
+	if m != nil {
+		return m.Leader
+	}
+	return 0
+}
+
+func (m *SBStatus) GetInstance() uint64 {
+	if m != nil {
+		return m.Instance
+	}
+	return 0
+}
+
+func (m *SBStatus) GetBucketIds() []uint64 {
+	if m != nil {
+		return m.BucketIds
+	}
+	return nil
+}
+
+func (m *SBStatus) GetSeqNrs() []uint64 {
+	if m != nil {
+		return m.SeqNrs
+	}
+	return nil
+}
+
+func (m *SBStatus) GetProposed() []uint64 {
+	if m != nil {
+		return m.Proposed
+	}
+	return nil
+}
+
+func (m *SBStatus) GetCommitted() []uint64 {
+	if m != nil {
+		return m.Committed
+	}
+	return nil
 }
 
 func init() {
+	proto.RegisterEnum("isspb.EpochChangeReason", EpochChangeReason_name, EpochChangeReason_value)
 	proto.RegisterType((*ISSMessage)(nil), "isspb.ISSMessage")
 	proto.RegisterType((*RetransmitRequests)(nil), "isspb.RetransmitRequests")
+	proto.RegisterType((*FetchRequests)(nil), "isspb.FetchRequests")
+	proto.RegisterType((*Heartbeat)(nil), "isspb.Heartbeat")
+	proto.RegisterType((*StateRequest)(nil), "isspb.StateRequest")
+	proto.RegisterType((*StateTransfer)(nil), "isspb.StateTransfer")
 	proto.RegisterType((*SBMessage)(nil), "isspb.SBMessage")
 	proto.RegisterType((*Checkpoint)(nil), "isspb.Checkpoint")
 	proto.RegisterType((*SBInstanceMessage)(nil), "isspb.SBInstanceMessage")
 	proto.RegisterType((*ISSEvent)(nil), "isspb.ISSEvent")
 	proto.RegisterType((*PersistCheckpoint)(nil), "isspb.PersistCheckpoint")
+	proto.RegisterType((*ClientWatermark)(nil), "isspb.ClientWatermark")
 	proto.RegisterType((*StableCheckpoint)(nil), "isspb.StableCheckpoint")
 	proto.RegisterType((*PersistStableCheckpoint)(nil), "isspb.PersistStableCheckpoint")
+	proto.RegisterType((*PersistConfigRequest)(nil), "isspb.PersistConfigRequest")
 	proto.RegisterType((*SBEvent)(nil), "isspb.SBEvent")
 	proto.RegisterType((*SBInstanceEvent)(nil), "isspb.SBInstanceEvent")
 	proto.RegisterType((*SBInit)(nil), "isspb.SBInit")
@@ -1298,72 +2812,179 @@ func init() {
 	proto.RegisterType((*SBMessageReceived)(nil), "isspb.SBMessageReceived")
 	proto.RegisterType((*SBPendingRequests)(nil), "isspb.SBPendingRequests")
 	proto.RegisterType((*SBTick)(nil), "isspb.SBTick")
+	proto.RegisterType((*SBLocalParams)(nil), "isspb.SBLocalParams")
+	proto.RegisterType((*SBStepDown)(nil), "isspb.SBStepDown")
+	proto.RegisterType((*ConfigChange)(nil), "isspb.ConfigChange")
+	proto.RegisterType((*ClientKey)(nil), "isspb.ClientKey")
+	proto.RegisterType((*NodeKey)(nil), "isspb.NodeKey")
 	proto.RegisterType((*Status)(nil), "isspb.Status")
+	proto.RegisterType((*EpochChange)(nil), "isspb.EpochChange")
+	proto.RegisterType((*EpochChangeCause)(nil), "isspb.EpochChangeCause")
+	proto.RegisterType((*BucketStatus)(nil), "isspb.BucketStatus")
+	proto.RegisterType((*ClientStatus)(nil), "isspb.ClientStatus")
+	proto.RegisterType((*MessageBufferStatus)(nil), "isspb.MessageBufferStatus")
+	proto.RegisterType((*EpochTransitionStatus)(nil), "isspb.EpochTransitionStatus")
+	proto.RegisterType((*CommitLatency)(nil), "isspb.CommitLatency")
+	proto.RegisterType((*NodeLag)(nil), "isspb.NodeLag")
+	proto.RegisterType((*ClockSkew)(nil), "isspb.ClockSkew")
 	proto.RegisterType((*SBStatus)(nil), "isspb.SBStatus")
 }
 
 func init() { proto.RegisterFile("isspb/isspb.proto", fileDescriptor_67c987db0a07e2d8) }
 
 var fileDescriptor_67c987db0a07e2d8 = []byte{
-	// 957 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xeb, 0x6e, 0x1b, 0x45,
-	0x14, 0xb6, 0x1d, 0xc7, 0x71, 0x8e, 0xd3, 0x38, 0x9e, 0x90, 0xc6, 0x8e, 0x10, 0x4a, 0x17, 0x09,
-	0x2a, 0x28, 0x36, 0x4d, 0x05, 0xe2, 0x1f, 0xc8, 0x69, 0x83, 0x23, 0x8a, 0x14, 0xcd, 0xa2, 0x22,
-	0x21, 0xd0, 0x6a, 0x77, 0x3d, 0xb6, 0x87, 0x78, 0x2f, 0xcc, 0x8c, 0xd3, 0xa6, 0xef, 0xc3, 0x53,
-	0xf0, 0x46, 0x3c, 0x05, 0x9a, 0xcb, 0xce, 0xde, 0x92, 0x2a, 0x42, 0x8a, 0xe2, 0x99, 0x73, 0xbe,
-	0x39, 0x73, 0xbe, 0x73, 0x9b, 0x85, 0x01, 0xe5, 0x3c, 0x0d, 0x26, 0xea, 0xff, 0x38, 0x65, 0x89,
-	0x48, 0xd0, 0xb6, 0xda, 0x9c, 0x8c, 0xd4, 0xcf, 0x42, 0x64, 0x5a, 0xb9, 0xd2, 0x88, 0x93, 0x11,
-	0x23, 0x7f, 0x6d, 0x08, 0x97, 0x2a, 0xbb, 0xd2, 0x2a, 0xe7, 0x9f, 0x26, 0xc0, 0xa5, 0xeb, 0xfe,
-	0x4c, 0x38, 0xf7, 0x97, 0x04, 0x39, 0xd0, 0xe2, 0xc1, 0xb0, 0x79, 0xda, 0x7c, 0xda, 0x3b, 0x3b,
-	0x18, 0xeb, 0x5b, 0xdc, 0xa9, 0xd1, 0xce, 0x1a, 0xb8, 0xc5, 0x03, 0xf4, 0x02, 0x20, 0x5c, 0x91,
-	0xf0, 0x3a, 0x4d, 0x68, 0x2c, 0x86, 0x2d, 0x85, 0x1d, 0x18, 0xec, 0xb9, 0x55, 0xcc, 0x1a, 0xb8,
-	0x00, 0x43, 0xaf, 0xe1, 0x90, 0x11, 0xc1, 0xfc, 0x98, 0x47, 0x54, 0x78, 0xc6, 0x0b, 0x3e, 0xdc,
-	0x52, 0xa7, 0x47, 0xe6, 0x34, 0xb6, 0x08, 0x6c, 0x00, 0xb3, 0x06, 0x46, 0xac, 0x26, 0x9d, 0x76,
-	0xa0, 0x2d, 0x6e, 0x53, 0xe2, 0xfc, 0x08, 0xa8, 0x7e, 0x06, 0x3d, 0x87, 0xae, 0xbd, 0xa0, 0x79,
-	0xba, 0xf5, 0xb4, 0x77, 0x76, 0x34, 0xce, 0x79, 0x1b, 0x18, 0x26, 0x0b, 0x6c, 0x61, 0x0e, 0x85,
-	0x5d, 0x4b, 0x13, 0x7d, 0x04, 0xdb, 0x24, 0x4d, 0xc2, 0x95, 0x8a, 0x43, 0x1b, 0xeb, 0x0d, 0x3a,
-	0x81, 0x2e, 0x8d, 0xb9, 0xf0, 0xe3, 0x90, 0x28, 0xd2, 0x6d, 0x6c, 0xf7, 0xe8, 0x0b, 0xd8, 0x8a,
-	0xf8, 0xd2, 0xb0, 0x19, 0xda, 0xb8, 0x5d, 0x1a, 0xbd, 0x31, 0x8c, 0x25, 0xc8, 0x39, 0x03, 0xc8,
-	0xa3, 0x74, 0xcf, 0x5d, 0xfb, 0xd0, 0xe2, 0xb1, 0xb9, 0xa5, 0xc5, 0x63, 0xe7, 0x0f, 0x18, 0xd4,
-	0xac, 0xa1, 0x1f, 0xa0, 0x2f, 0xb3, 0xec, 0xa5, 0x8c, 0xc8, 0x3f, 0x9f, 0x11, 0xe3, 0xc0, 0xd1,
-	0x38, 0x2f, 0x80, 0x2b, 0xab, 0x9c, 0x35, 0xf0, 0xbe, 0x14, 0xe6, 0x12, 0x1b, 0xc6, 0xbf, 0x5b,
-	0xd0, 0xbd, 0x74, 0xdd, 0x57, 0x37, 0x24, 0x16, 0xe8, 0x12, 0x50, 0x4a, 0x18, 0xa7, 0x5c, 0x78,
-	0x85, 0x34, 0x37, 0x4b, 0xd4, 0xae, 0x34, 0xa0, 0x94, 0xed, 0x41, 0x5a, 0x15, 0xa2, 0x0b, 0x18,
-	0x70, 0xe1, 0x07, 0x6b, 0xe2, 0xd5, 0x0a, 0xe6, 0x38, 0x0b, 0x92, 0xd2, 0x97, 0x0c, 0x1d, 0xf0,
-	0x8a, 0x0c, 0xfd, 0x0e, 0xa3, 0xcc, 0xa5, 0xba, 0x3d, 0xcd, 0xf9, 0x93, 0xb2, 0x67, 0x77, 0x98,
-	0x3d, 0x4e, 0xef, 0x56, 0xa1, 0x53, 0x55, 0xf3, 0x6d, 0x65, 0x66, 0xdf, 0xe6, 0x4e, 0x05, 0x43,
-	0x57, 0xbc, 0x8d, 0xd3, 0x05, 0x0c, 0x6a, 0xcc, 0x4d, 0xae, 0x9a, 0x59, 0xae, 0xd0, 0x13, 0xd8,
-	0xf3, 0xd3, 0xd4, 0xe3, 0xb1, 0x9f, 0xf2, 0x55, 0xa2, 0xf9, 0xee, 0xe1, 0x9e, 0x9f, 0xa6, 0xae,
-	0x11, 0x39, 0xdf, 0xc1, 0x41, 0xcd, 0x8b, 0x87, 0x15, 0x82, 0x07, 0xc7, 0xf7, 0x30, 0x44, 0x2f,
-	0xef, 0x0a, 0x76, 0xf3, 0x83, 0xc1, 0xae, 0x87, 0xda, 0xa1, 0xb0, 0x63, 0xb8, 0xff, 0x8f, 0x36,
-	0x78, 0x06, 0xdb, 0x44, 0x1e, 0x35, 0x39, 0x79, 0x5c, 0x6b, 0x04, 0x65, 0x18, 0x6b, 0x90, 0xf3,
-	0x6f, 0x1b, 0xfa, 0x15, 0x15, 0xfa, 0x14, 0xda, 0x34, 0xa6, 0x99, 0xdf, 0x8f, 0x0a, 0x06, 0xa8,
-	0x4c, 0x86, 0x52, 0xa2, 0x67, 0xb0, 0x33, 0x27, 0x6b, 0x7a, 0x43, 0x98, 0x29, 0xa6, 0x7c, 0x52,
-	0xbd, 0xd4, 0xf2, 0x59, 0x03, 0x67, 0x10, 0xf4, 0x0a, 0x0e, 0x22, 0xdd, 0x31, 0x1e, 0x23, 0x21,
-	0xa1, 0x37, 0x64, 0x5e, 0x6b, 0xd4, 0xac, 0x41, 0x8d, 0x7e, 0xd6, 0xc0, 0xfd, 0xa8, 0x2c, 0x92,
-	0x66, 0x52, 0x12, 0xcf, 0x69, 0xbc, 0xcc, 0xa7, 0x57, 0xbb, 0x62, 0xe6, 0x4a, 0x03, 0x0a, 0xc3,
-	0xab, 0x9f, 0x96, 0x45, 0x92, 0xa0, 0xa0, 0xe1, 0xf5, 0x70, 0xbb, 0x42, 0xf0, 0x17, 0x1a, 0x5e,
-	0x4b, 0x82, 0x52, 0x89, 0xbe, 0x86, 0xdd, 0x70, 0x23, 0xbc, 0xc0, 0x17, 0xe1, 0x6a, 0xd8, 0x29,
-	0x0d, 0x58, 0x77, 0x7a, 0xbe, 0x11, 0x53, 0xa9, 0x98, 0x35, 0x70, 0x37, 0x34, 0x6b, 0xf4, 0x2d,
-	0xf4, 0x14, 0xda, 0x63, 0xc4, 0x9f, 0xdf, 0x0e, 0x77, 0xd4, 0x99, 0x43, 0x7b, 0x46, 0x81, 0xb0,
-	0x54, 0xc9, 0xb1, 0x1c, 0xd8, 0x9d, 0xec, 0xd0, 0xb7, 0x3e, 0x15, 0xde, 0x22, 0x61, 0x39, 0xad,
-	0x6e, 0x85, 0xd6, 0xaf, 0x3e, 0x15, 0x17, 0x09, 0x2b, 0xd2, 0x7a, 0x5b, 0x16, 0xa1, 0xef, 0x61,
-	0x3f, 0x3b, 0x6e, 0x5c, 0xd8, 0xad, 0x94, 0x40, 0x06, 0xcd, 0xbc, 0x78, 0xc4, 0x8a, 0x02, 0xf4,
-	0x06, 0x8e, 0xf5, 0x30, 0x33, 0x7d, 0x5e, 0x18, 0x6a, 0xa0, 0x2c, 0x7d, 0x5c, 0x1c, 0x6a, 0x1a,
-	0x54, 0x9a, 0x6d, 0x47, 0x6a, 0xb6, 0x55, 0x15, 0xb6, 0x75, 0xbb, 0xd0, 0xd1, 0x55, 0xe4, 0x7c,
-	0x0e, 0x90, 0x07, 0x11, 0x8d, 0xa0, 0x1b, 0xf9, 0xef, 0x3c, 0x4e, 0xdf, 0x13, 0x53, 0xe7, 0x3b,
-	0x91, 0xff, 0xce, 0xa5, 0xef, 0x89, 0xf3, 0x27, 0xec, 0x15, 0x23, 0x87, 0x3e, 0x83, 0x6d, 0x9d,
-	0x91, 0xec, 0x79, 0xcc, 0xdf, 0x14, 0x8d, 0xd2, 0x6a, 0x74, 0x06, 0x47, 0xd5, 0x4a, 0xf1, 0xd6,
-	0x64, 0x21, 0x4c, 0xbb, 0x1c, 0x56, 0x4a, 0xe2, 0x35, 0x59, 0x08, 0xe7, 0x8d, 0x1c, 0xf0, 0x95,
-	0x38, 0xd7, 0x26, 0x4b, 0xf1, 0x5d, 0x6b, 0x3d, 0xec, 0x5d, 0x7b, 0x22, 0x5b, 0xac, 0x14, 0xfa,
-	0xaa, 0x55, 0xe7, 0x5c, 0x3e, 0x7d, 0xa6, 0x6f, 0x6a, 0x57, 0x5a, 0xce, 0xad, 0x0f, 0x72, 0x76,
-	0x5c, 0xe9, 0x7f, 0xa5, 0x8b, 0x10, 0x82, 0xf6, 0x82, 0x25, 0x91, 0x31, 0xa7, 0xd6, 0xd9, 0x4b,
-	0xd9, 0x7a, 0xc8, 0x4b, 0xf9, 0x8d, 0x34, 0x5a, 0xe9, 0x29, 0x74, 0x0a, 0xbd, 0x78, 0x13, 0xe1,
-	0xfc, 0x7d, 0x97, 0xb6, 0x8b, 0x22, 0x9d, 0x6a, 0xd9, 0x4f, 0xce, 0x4f, 0xd0, 0x71, 0x85, 0x2f,
-	0x36, 0xfc, 0x9e, 0x59, 0xf6, 0x25, 0x74, 0x13, 0x36, 0x27, 0x8c, 0xb0, 0x2c, 0xa0, 0x7d, 0xeb,
-	0x91, 0x3e, 0x88, 0x2d, 0xc0, 0x71, 0xa0, 0x9b, 0x49, 0xd1, 0x63, 0xe8, 0xac, 0x89, 0x3f, 0x27,
-	0xcc, 0xd8, 0x33, 0xbb, 0xe9, 0xf3, 0xdf, 0x26, 0x4b, 0x2a, 0x56, 0x9b, 0x60, 0x1c, 0x26, 0xd1,
-	0x64, 0x75, 0x9b, 0x12, 0xb6, 0x26, 0xf3, 0x25, 0x61, 0x5f, 0xad, 0xfd, 0x80, 0x4f, 0x22, 0xca,
-	0x82, 0x85, 0x98, 0xa4, 0xd7, 0xcb, 0x49, 0xf6, 0x95, 0x16, 0x74, 0xd4, 0x77, 0xd8, 0x8b, 0xff,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0xc2, 0xcd, 0xbb, 0x06, 0xd9, 0x09, 0x00, 0x00,
+	// 2445 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x59, 0x59, 0x6f, 0x1b, 0xc9,
+	0x11, 0xe6, 0x7d, 0x14, 0x0f, 0x91, 0x2d, 0xc9, 0xa2, 0xb4, 0xce, 0x46, 0x1e, 0x27, 0xb1, 0xe1,
+	0x5d, 0x4b, 0x6b, 0x2f, 0x92, 0x6c, 0xb2, 0x58, 0x24, 0xa6, 0x0e, 0x53, 0x08, 0x2d, 0x0b, 0x43,
+	0xc7, 0x8b, 0x04, 0x71, 0x06, 0xcd, 0x99, 0x26, 0x39, 0x11, 0xe7, 0xf0, 0x74, 0xd3, 0xb4, 0xfc,
+	0x18, 0xe4, 0x25, 0x40, 0x80, 0xfc, 0x81, 0xbc, 0xe7, 0x35, 0xc8, 0x5b, 0x7e, 0x49, 0x7e, 0x46,
+	0xde, 0xf3, 0x14, 0xf4, 0x35, 0x07, 0x29, 0x09, 0xce, 0x02, 0x8b, 0x75, 0x77, 0x55, 0x75, 0x4d,
+	0x55, 0xf5, 0x57, 0x47, 0x53, 0xd0, 0x75, 0x29, 0x0d, 0xc7, 0x87, 0xe2, 0xff, 0x07, 0x61, 0x14,
+	0xb0, 0x00, 0x95, 0xc5, 0x66, 0x6f, 0x57, 0xfc, 0x33, 0x61, 0x9a, 0x3b, 0x61, 0x5a, 0x62, 0x6f,
+	0x37, 0x22, 0x6f, 0x17, 0x84, 0x72, 0x56, 0xbc, 0x92, 0x2c, 0xe3, 0xef, 0x45, 0x80, 0xb3, 0xd1,
+	0xe8, 0x05, 0xa1, 0x14, 0x4f, 0x09, 0x32, 0xa0, 0x40, 0xc7, 0xbd, 0xfc, 0x7e, 0xfe, 0x61, 0xe3,
+	0x69, 0xe7, 0x40, 0x7e, 0x65, 0xd4, 0x57, 0xdc, 0x41, 0xce, 0x2c, 0xd0, 0x31, 0xfa, 0x12, 0xc0,
+	0x9e, 0x11, 0xfb, 0x32, 0x0c, 0x5c, 0x9f, 0xf5, 0x0a, 0x42, 0xb6, 0xab, 0x64, 0x8f, 0x62, 0xc6,
+	0x20, 0x67, 0xa6, 0xc4, 0xd0, 0x10, 0x36, 0x23, 0xc2, 0x22, 0xec, 0x53, 0xcf, 0x65, 0x96, 0xb2,
+	0x82, 0xf6, 0x8a, 0xe2, 0xf4, 0xae, 0x3a, 0x6d, 0xc6, 0x12, 0xa6, 0x12, 0x18, 0xe4, 0x4c, 0x14,
+	0xad, 0x51, 0xd1, 0x37, 0xd0, 0x9e, 0x10, 0x66, 0xcf, 0x12, 0x45, 0x25, 0xa1, 0x68, 0x4b, 0x29,
+	0x3a, 0xe5, 0xcc, 0x94, 0x8e, 0xd6, 0x24, 0x4d, 0x40, 0x5f, 0x40, 0x7d, 0x46, 0x70, 0xc4, 0xc6,
+	0x04, 0xb3, 0x5e, 0x39, 0xe3, 0xec, 0x40, 0xd3, 0x07, 0x39, 0x33, 0x11, 0x42, 0x3f, 0x87, 0x16,
+	0x65, 0x98, 0x11, 0xfd, 0xc1, 0x5e, 0x45, 0x9c, 0xda, 0xd4, 0x21, 0xe2, 0x3c, 0xa5, 0x7e, 0x90,
+	0x33, 0x9b, 0x34, 0xb5, 0xe7, 0xc6, 0xca, 0xb3, 0xc2, 0x8d, 0x09, 0x89, 0x7a, 0xd5, 0x8c, 0xb1,
+	0xe2, 0xf0, 0x2b, 0xc5, 0xe3, 0xc6, 0xd2, 0x34, 0xa1, 0x5f, 0x81, 0x12, 0xbb, 0x0a, 0x89, 0xf1,
+	0x1c, 0xd0, 0x7a, 0x7c, 0xd0, 0x13, 0xa8, 0xc5, 0x31, 0xc8, 0xef, 0x17, 0x1f, 0x36, 0x9e, 0x6e,
+	0x1f, 0x24, 0x77, 0xac, 0xc4, 0x4c, 0x32, 0x31, 0x63, 0x31, 0xa3, 0x0f, 0xad, 0x4c, 0x7c, 0xbe,
+	0x8b, 0x8e, 0x06, 0xd4, 0xe3, 0x48, 0x19, 0x6d, 0x68, 0xa6, 0x03, 0x60, 0x58, 0xd0, 0xca, 0xf8,
+	0x84, 0xb6, 0xa0, 0x4c, 0xc2, 0xc0, 0x9e, 0x09, 0x60, 0x95, 0x4c, 0xb9, 0x41, 0x5f, 0x5d, 0x83,
+	0xa3, 0x9e, 0x8a, 0xc9, 0x05, 0x89, 0xa8, 0x4b, 0x59, 0x02, 0xa7, 0x34, 0x98, 0x8c, 0xbf, 0xe4,
+	0xa1, 0x1e, 0xa3, 0xf2, 0x06, 0xed, 0x7b, 0x50, 0x73, 0x7d, 0xca, 0xb0, 0x6f, 0x13, 0xa1, 0xbb,
+	0x64, 0xc6, 0x7b, 0xf4, 0x08, 0x8a, 0x1e, 0x9d, 0xf6, 0x8a, 0x99, 0x4f, 0x8e, 0xfa, 0x67, 0x8a,
+	0xaf, 0x14, 0x9b, 0x5c, 0x08, 0xdd, 0x83, 0xa6, 0x1d, 0xf8, 0x13, 0x77, 0x6a, 0xc9, 0x8f, 0x94,
+	0x84, 0xae, 0x86, 0xa4, 0x9d, 0x70, 0x92, 0x41, 0x01, 0x12, 0x43, 0x6f, 0x30, 0xa7, 0x0d, 0x05,
+	0xea, 0x2b, 0x43, 0x0a, 0xd4, 0x47, 0x77, 0xa1, 0xce, 0x5c, 0x8f, 0x50, 0x86, 0xbd, 0x50, 0x18,
+	0x52, 0x34, 0x13, 0xc2, 0xc7, 0x7c, 0xf4, 0x0d, 0x74, 0xd7, 0x2c, 0x46, 0xbf, 0x84, 0x0d, 0x9e,
+	0xf8, 0x56, 0x18, 0x11, 0xfe, 0x1f, 0x8e, 0x88, 0x72, 0x72, 0xfb, 0x20, 0xa9, 0x09, 0x17, 0x31,
+	0x73, 0x90, 0x33, 0xdb, 0x9c, 0x98, 0x50, 0x62, 0xb4, 0xfd, 0xb7, 0x00, 0xb5, 0xb3, 0xd1, 0xe8,
+	0xe4, 0x1d, 0xf1, 0x19, 0x3a, 0x03, 0x14, 0xca, 0x0b, 0xb1, 0x52, 0x37, 0x96, 0xbf, 0xfd, 0xc6,
+	0x06, 0x39, 0xb3, 0x1b, 0xae, 0x12, 0xd1, 0x29, 0x74, 0x29, 0xc3, 0xe3, 0x39, 0xb1, 0xd6, 0xee,
+	0x7e, 0x27, 0xc9, 0x87, 0xf1, 0x9c, 0x64, 0x14, 0x75, 0xe8, 0x0a, 0x0d, 0xfd, 0x0e, 0x76, 0xb5,
+	0x49, 0xeb, 0xfa, 0xa4, 0xcf, 0x9f, 0x66, 0x2d, 0xbb, 0x46, 0xed, 0x4e, 0x78, 0x3d, 0x0b, 0xed,
+	0x8b, 0x32, 0x28, 0x6b, 0x4a, 0x3b, 0xc6, 0x87, 0x08, 0x86, 0x2a, 0x82, 0x23, 0xb8, 0x13, 0x87,
+	0x44, 0xde, 0x94, 0xae, 0x0c, 0xb2, 0x9e, 0x7c, 0xb2, 0x12, 0x16, 0x21, 0x93, 0x54, 0x88, 0xad,
+	0xf0, 0x1a, 0x7a, 0x1c, 0xfc, 0x7f, 0x15, 0xa1, 0xbb, 0x16, 0x4f, 0x05, 0xa1, 0x7c, 0x0c, 0xa1,
+	0x7b, 0xd0, 0xc4, 0x61, 0x68, 0x51, 0x1f, 0x87, 0x74, 0x16, 0xc8, 0x28, 0x36, 0xcd, 0x06, 0x0e,
+	0xc3, 0x91, 0x22, 0xa1, 0x23, 0xe8, 0xda, 0x73, 0x97, 0xf8, 0xcc, 0x5a, 0x62, 0x46, 0x22, 0x0f,
+	0x47, 0x97, 0xbc, 0xe6, 0xf2, 0x14, 0xbf, 0xa3, 0x2b, 0xb6, 0xe0, 0x7f, 0xab, 0xd9, 0x66, 0xc7,
+	0xce, 0x12, 0x28, 0xfa, 0x14, 0xc0, 0x23, 0xde, 0x98, 0x44, 0x74, 0xe6, 0x86, 0xbd, 0xd2, 0x7e,
+	0xf1, 0x61, 0xc9, 0x4c, 0x51, 0xd0, 0x0f, 0xa1, 0x3d, 0x71, 0x23, 0xca, 0xac, 0x38, 0xdf, 0xca,
+	0xc2, 0xc6, 0x96, 0xa0, 0x6a, 0x88, 0xa2, 0xef, 0x43, 0xc3, 0x5f, 0x78, 0xd6, 0x78, 0x61, 0x5f,
+	0x12, 0x46, 0x45, 0x01, 0x2d, 0x99, 0xe0, 0x2f, 0xbc, 0xbe, 0xa4, 0x70, 0x3d, 0x94, 0x4c, 0x3d,
+	0x6e, 0xed, 0x9c, 0xf8, 0x53, 0x36, 0x13, 0x75, 0xb2, 0x64, 0xb6, 0x14, 0x75, 0x28, 0x88, 0xe8,
+	0x09, 0x34, 0x94, 0x4f, 0x97, 0xe4, 0x8a, 0xf6, 0x6a, 0xfb, 0xc5, 0x54, 0xf9, 0x96, 0xde, 0xfc,
+	0x8a, 0x5c, 0x99, 0x60, 0xeb, 0x25, 0x5d, 0x4b, 0xa7, 0xfa, 0x5a, 0x3a, 0xa1, 0xcf, 0xa0, 0xee,
+	0x07, 0x0e, 0x91, 0x3a, 0x61, 0xbf, 0x98, 0xba, 0xf8, 0xf3, 0xc0, 0x21, 0x5c, 0x63, 0xcd, 0x97,
+	0x0b, 0xca, 0x6b, 0xcb, 0x9c, 0xe0, 0xc8, 0x27, 0x11, 0xed, 0x35, 0x44, 0x3c, 0xe2, 0xbd, 0x71,
+	0x02, 0x1b, 0x2b, 0x21, 0x45, 0x9f, 0x40, 0x5d, 0x59, 0xec, 0x3a, 0xea, 0xfe, 0x6a, 0x92, 0x70,
+	0xe6, 0xa0, 0x6d, 0xa8, 0x44, 0xe4, 0xad, 0xe5, 0x07, 0xaa, 0x38, 0x94, 0x23, 0xf2, 0xf6, 0x3c,
+	0x30, 0xbe, 0x82, 0xce, 0x1a, 0x2a, 0x3f, 0xaa, 0xb2, 0x18, 0x16, 0xec, 0xdc, 0x80, 0x78, 0x74,
+	0x7c, 0x5d, 0xf2, 0xe5, 0x6f, 0x4d, 0xbe, 0xf5, 0xd4, 0x33, 0xc6, 0xb0, 0x75, 0x1d, 0xaa, 0xd1,
+	0x4f, 0xa0, 0xa1, 0x72, 0xc0, 0x8a, 0xc8, 0x44, 0xe9, 0xbd, 0xa1, 0x93, 0x40, 0x14, 0xaf, 0x11,
+	0x82, 0x92, 0x83, 0x19, 0x56, 0xf8, 0x15, 0x6b, 0xc3, 0x85, 0xaa, 0xca, 0xb7, 0xef, 0x50, 0xde,
+	0x3f, 0x87, 0x32, 0x79, 0x47, 0xe2, 0x3a, 0x70, 0x67, 0xad, 0xc0, 0x0b, 0xc5, 0xa6, 0x14, 0x32,
+	0xfe, 0x5d, 0x86, 0x8d, 0x15, 0x16, 0xba, 0x0f, 0x25, 0xd7, 0x77, 0x75, 0x6c, 0x5a, 0x29, 0x05,
+	0x2e, 0xcf, 0x5e, 0xc1, 0x44, 0x9f, 0x43, 0xd5, 0x21, 0x73, 0xf7, 0x1d, 0x89, 0x54, 0x01, 0x4b,
+	0x06, 0xa6, 0x63, 0x49, 0x1f, 0xe4, 0x4c, 0x2d, 0x82, 0x4e, 0xa0, 0xe3, 0xc9, 0x2a, 0x6d, 0x45,
+	0xc4, 0x26, 0xee, 0x3b, 0xe2, 0xac, 0x35, 0x20, 0xdd, 0x78, 0x14, 0x7f, 0x90, 0x33, 0x37, 0xbc,
+	0x2c, 0x89, 0xab, 0x09, 0x89, 0xef, 0xb8, 0xfe, 0x74, 0x75, 0xf6, 0x49, 0xd4, 0x5c, 0x48, 0x81,
+	0xd4, 0xfc, 0xb3, 0x11, 0x66, 0x49, 0xdc, 0x41, 0xe6, 0xda, 0x97, 0xbd, 0xf2, 0x8a, 0x83, 0xaf,
+	0x5c, 0xfb, 0x92, 0x3b, 0xc8, 0x99, 0x7c, 0x4c, 0xb2, 0x17, 0xcc, 0x1a, 0x63, 0x66, 0xcf, 0x7a,
+	0x95, 0xcc, 0x9c, 0x37, 0xea, 0x1f, 0x2d, 0x58, 0x9f, 0x33, 0x06, 0x39, 0xb3, 0x66, 0xab, 0x35,
+	0x87, 0x80, 0x90, 0xb6, 0x22, 0x82, 0x9d, 0xab, 0x5e, 0x35, 0x3b, 0x24, 0xf5, 0x85, 0x90, 0xc9,
+	0x59, 0x7c, 0x3a, 0x1c, 0xc7, 0x3b, 0xde, 0x15, 0x96, 0xd8, 0x65, 0xd6, 0x24, 0x88, 0x12, 0xb7,
+	0x6a, 0x2b, 0x6e, 0x7d, 0x8b, 0x5d, 0x76, 0x1a, 0x44, 0x69, 0xb7, 0x96, 0x59, 0x12, 0xfa, 0x05,
+	0xb4, 0xf5, 0x71, 0x65, 0x42, 0x7d, 0x05, 0x02, 0x5a, 0x54, 0x5b, 0xd1, 0x8a, 0xd2, 0x04, 0xee,
+	0x32, 0x65, 0x24, 0xb4, 0x9c, 0x60, 0xe9, 0xf7, 0x1a, 0x2b, 0x2e, 0x8f, 0x18, 0x09, 0x8f, 0x83,
+	0xa5, 0xcf, 0x5d, 0xa6, 0x6a, 0x8d, 0x7e, 0x06, 0xcd, 0x79, 0x60, 0xe3, 0xb9, 0x15, 0xe2, 0x08,
+	0x7b, 0xb4, 0xd7, 0xcc, 0xce, 0x76, 0xfd, 0x21, 0x67, 0x5e, 0x08, 0xde, 0x20, 0x67, 0x36, 0xe6,
+	0xc9, 0x16, 0xbd, 0x86, 0x1d, 0xd9, 0xad, 0x55, 0x23, 0x49, 0x75, 0x6d, 0x10, 0x5a, 0xee, 0xa6,
+	0xbb, 0xb6, 0x14, 0xca, 0x34, 0xef, 0x6d, 0xd1, 0xbc, 0x57, 0x19, 0x71, 0x1b, 0xa9, 0x41, 0x45,
+	0x42, 0xd6, 0x78, 0x00, 0x90, 0xdc, 0x18, 0xda, 0x85, 0x9a, 0x87, 0xdf, 0x5b, 0xd4, 0xfd, 0x40,
+	0x54, 0x52, 0x55, 0x3d, 0xfc, 0x7e, 0xe4, 0x7e, 0x20, 0xc6, 0x1f, 0xa0, 0x99, 0xbe, 0x26, 0xf4,
+	0x23, 0x28, 0xcb, 0xeb, 0xd7, 0x4f, 0x82, 0x24, 0x9b, 0xa5, 0x94, 0x64, 0xa3, 0xa7, 0xb0, 0xbd,
+	0x0a, 0x4b, 0x6b, 0x4e, 0x26, 0x4c, 0xe5, 0xe6, 0xe6, 0x0a, 0xfe, 0x86, 0x64, 0xc2, 0x8c, 0xd7,
+	0xd0, 0x5d, 0xbb, 0xd4, 0xb5, 0x26, 0x97, 0x9e, 0x4d, 0x0b, 0x1f, 0x37, 0x9b, 0xde, 0xe3, 0xf9,
+	0x9c, 0xb9, 0xe7, 0x55, 0xad, 0xc6, 0x1b, 0xa8, 0xc7, 0x49, 0xba, 0xf6, 0xc9, 0xd8, 0xe7, 0xc2,
+	0xed, 0x3e, 0xf7, 0xa0, 0x3a, 0xc3, 0xbe, 0x13, 0x4c, 0x26, 0x22, 0x91, 0x6b, 0xa6, 0xde, 0x1a,
+	0x23, 0xe8, 0xae, 0x25, 0x33, 0x2f, 0x73, 0x93, 0x28, 0xf0, 0xd4, 0x87, 0xc4, 0x5a, 0x0f, 0xa2,
+	0x85, 0x8f, 0x18, 0x44, 0x8d, 0x1f, 0x43, 0x77, 0x2d, 0xb5, 0xd1, 0xbe, 0x68, 0xaa, 0x66, 0x32,
+	0xbd, 0x8b, 0xc6, 0x96, 0x22, 0x49, 0x10, 0xf0, 0xb4, 0x36, 0xbe, 0x86, 0x56, 0x06, 0x8e, 0xe8,
+	0x11, 0x74, 0x39, 0x0e, 0xc2, 0x28, 0x08, 0x03, 0x4a, 0x2c, 0x87, 0xcc, 0xf1, 0x95, 0x52, 0xb1,
+	0xe1, 0xe1, 0xf7, 0x17, 0x92, 0x7e, 0xcc, 0xc9, 0x46, 0x13, 0x20, 0x49, 0x00, 0xe3, 0x3f, 0x05,
+	0x68, 0xca, 0xe2, 0x7f, 0x34, 0xc3, 0xfe, 0x94, 0xf0, 0x16, 0x87, 0x1d, 0xc7, 0xe2, 0x1d, 0x52,
+	0xbe, 0x21, 0x4a, 0x66, 0x0d, 0x3b, 0x0e, 0x6f, 0x9d, 0xa2, 0xfd, 0x46, 0xc4, 0x0b, 0xde, 0x11,
+	0xc5, 0x2f, 0x08, 0x7e, 0x43, 0xd2, 0xa4, 0xc8, 0xca, 0x70, 0x50, 0xfc, 0x88, 0xe1, 0xa0, 0x74,
+	0xc3, 0x70, 0xc0, 0xed, 0x90, 0xdd, 0x95, 0xf6, 0xca, 0x37, 0x0d, 0x07, 0xd8, 0x71, 0xe4, 0x4e,
+	0x68, 0x56, 0xd6, 0xe9, 0x53, 0x15, 0x61, 0x5f, 0x4b, 0x52, 0xb5, 0xd8, 0x13, 0x68, 0x46, 0x81,
+	0x78, 0xc6, 0x49, 0x27, 0xaa, 0xd7, 0xce, 0x08, 0x0d, 0x29, 0x13, 0xfb, 0xcd, 0x8d, 0x89, 0x47,
+	0x85, 0x9a, 0xf4, 0x1b, 0x3b, 0xce, 0x50, 0x91, 0xd0, 0x03, 0xd8, 0x50, 0x1f, 0x8f, 0xa5, 0xea,
+	0x42, 0x4a, 0xd9, 0xa4, 0x05, 0x8d, 0xdf, 0x40, 0x3d, 0x36, 0xff, 0xf6, 0x81, 0x62, 0x07, 0xaa,
+	0xe1, 0x62, 0xcc, 0x07, 0x19, 0xd5, 0x51, 0x2b, 0xe1, 0x62, 0xcc, 0x4f, 0xdd, 0x81, 0xca, 0xd2,
+	0xf5, 0x9d, 0x60, 0xa9, 0xc2, 0xab, 0x76, 0xc6, 0xef, 0xa1, 0xaa, 0xcc, 0xe7, 0x67, 0xc5, 0x14,
+	0x14, 0xab, 0xad, 0xf0, 0xed, 0x6d, 0x4a, 0xc5, 0xdd, 0x32, 0x37, 0x22, 0x6a, 0xb4, 0x92, 0xaa,
+	0x1b, 0x92, 0x26, 0x5f, 0x2a, 0xff, 0x2c, 0x43, 0x85, 0xbf, 0x07, 0x17, 0xf4, 0x86, 0x5e, 0xfe,
+	0x19, 0xd4, 0x82, 0xc8, 0x21, 0x11, 0x89, 0x24, 0x36, 0x1a, 0x4f, 0x37, 0x52, 0x35, 0x97, 0x1f,
+	0x34, 0x63, 0x01, 0x39, 0xfe, 0x05, 0xf6, 0xa5, 0x45, 0x2f, 0xc9, 0x52, 0x0f, 0xb3, 0xc9, 0x0d,
+	0x07, 0xf6, 0xe5, 0xe8, 0x92, 0x2c, 0xf9, 0xf8, 0xa7, 0x96, 0x14, 0x0d, 0x61, 0x47, 0x02, 0xcb,
+	0xb2, 0x03, 0x8f, 0xff, 0xfc, 0x30, 0xc7, 0x8c, 0xf8, 0xb6, 0x4b, 0xa8, 0x98, 0x66, 0x93, 0x6a,
+	0x7d, 0x24, 0xd8, 0x43, 0xc1, 0xbd, 0x32, 0xb7, 0xe5, 0xa1, 0x34, 0xd1, 0x25, 0x42, 0xdb, 0x9c,
+	0x60, 0x87, 0x44, 0xeb, 0xda, 0xca, 0xb7, 0x69, 0x93, 0x87, 0x56, 0xb5, 0xe9, 0xb9, 0x73, 0x8e,
+	0xa7, 0x12, 0x78, 0x59, 0x4c, 0x0d, 0xf1, 0x54, 0xce, 0x9d, 0x43, 0x3c, 0x15, 0x09, 0xeb, 0x93,
+	0xf7, 0xcc, 0x52, 0x33, 0x05, 0x71, 0x2c, 0xea, 0xab, 0x21, 0x79, 0x83, 0x33, 0x8e, 0x35, 0x7d,
+	0xe4, 0xa3, 0x1f, 0x40, 0x7b, 0x8e, 0x93, 0xd7, 0x11, 0xf5, 0x45, 0x3f, 0x2d, 0x99, 0x4d, 0x4e,
+	0x95, 0x53, 0xde, 0xc8, 0x47, 0x8f, 0xa1, 0xaa, 0x73, 0xae, 0xbe, 0x5f, 0x4c, 0x35, 0x6b, 0x99,
+	0x77, 0x2a, 0xfa, 0x5a, 0x86, 0x8b, 0xeb, 0x24, 0x81, 0x8c, 0xb8, 0xc4, 0xa6, 0x16, 0x57, 0x32,
+	0xe8, 0x08, 0xf4, 0xfc, 0x62, 0x8d, 0x17, 0x93, 0x89, 0x1e, 0x97, 0x1b, 0x4f, 0xf7, 0xd4, 0x31,
+	0x55, 0xe0, 0xfa, 0x82, 0xa9, 0x4e, 0xb7, 0xbd, 0x34, 0x91, 0xa2, 0xaf, 0x01, 0x84, 0xd3, 0x12,
+	0x38, 0xcd, 0x74, 0x63, 0x3c, 0x10, 0x00, 0x13, 0x3f, 0x33, 0xb8, 0xcc, 0x0d, 0x7c, 0xa5, 0xa1,
+	0xce, 0xe5, 0x05, 0x0b, 0xfd, 0x14, 0x5a, 0xe2, 0x9c, 0x35, 0x73, 0x29, 0x0b, 0xa2, 0xab, 0x5e,
+	0x4b, 0x7c, 0x1f, 0xa5, 0xcf, 0xcb, 0x12, 0x66, 0x36, 0x85, 0xe0, 0x40, 0xca, 0x19, 0x7f, 0xce,
+	0x43, 0x23, 0xc5, 0xbd, 0x01, 0xb9, 0xbb, 0x50, 0x93, 0x4f, 0x9f, 0x78, 0x02, 0xaf, 0x8a, 0xfd,
+	0xc8, 0xe7, 0xdd, 0x41, 0xde, 0xb8, 0xc4, 0x68, 0xc9, 0xd4, 0x5b, 0x74, 0x08, 0x15, 0x1b, 0x2f,
+	0x68, 0x8c, 0xbe, 0x9d, 0x75, 0x63, 0x8e, 0x38, 0xdf, 0x54, 0x62, 0xc6, 0x1b, 0xe8, 0xac, 0xf2,
+	0xd0, 0x17, 0xfc, 0xd9, 0x80, 0x69, 0x20, 0x1b, 0x57, 0x3b, 0x6e, 0x1e, 0x69, 0x8f, 0x04, 0xdf,
+	0x54, 0x72, 0xe9, 0xdc, 0x2e, 0xa4, 0x73, 0xdb, 0xf0, 0xa1, 0x99, 0xbe, 0x6d, 0xde, 0x0f, 0xe3,
+	0xfc, 0x2f, 0xb8, 0x0e, 0xaf, 0x1b, 0xd2, 0x74, 0x7d, 0x4e, 0xee, 0x32, 0x23, 0x78, 0x71, 0x65,
+	0x04, 0xdf, 0x4b, 0xb5, 0x6d, 0x59, 0xa8, 0x93, 0xfe, 0xfc, 0xa7, 0x3c, 0x34, 0xd3, 0x78, 0xb9,
+	0xbd, 0x9c, 0x6d, 0x41, 0x59, 0x4e, 0x72, 0xf1, 0xf3, 0x88, 0x37, 0xf4, 0xfb, 0xd0, 0x9a, 0x07,
+	0xcb, 0xe4, 0x55, 0xab, 0x0c, 0x68, 0xce, 0x83, 0x65, 0xf2, 0xee, 0xba, 0x0b, 0x75, 0x99, 0xa2,
+	0x8c, 0x38, 0xca, 0x8a, 0x84, 0x60, 0x7c, 0x80, 0xcd, 0x6b, 0xe0, 0x77, 0x73, 0x09, 0xdc, 0x83,
+	0x9a, 0x42, 0x26, 0xd5, 0x2f, 0x0e, 0xbd, 0xe7, 0xbd, 0x5d, 0x4c, 0x53, 0xd2, 0x0a, 0xb1, 0xe6,
+	0xf2, 0x36, 0x0e, 0xb1, 0xed, 0xb2, 0x2b, 0x1d, 0x02, 0xbd, 0x37, 0xfe, 0x5a, 0x80, 0xed, 0x6b,
+	0xb1, 0xfb, 0xff, 0xe3, 0x6c, 0x1f, 0x1a, 0x0b, 0x3f, 0x71, 0x53, 0xd5, 0xdf, 0x14, 0x29, 0x8d,
+	0xc4, 0x52, 0x16, 0x89, 0x4f, 0x60, 0x6b, 0xe1, 0x47, 0x84, 0x86, 0x81, 0x4f, 0x5d, 0xd9, 0x83,
+	0x84, 0x58, 0x59, 0x88, 0x6d, 0xa6, 0x79, 0x43, 0x75, 0xe4, 0x01, 0x6c, 0xa8, 0x29, 0x27, 0x96,
+	0x96, 0xed, 0xb2, 0xad, 0xc8, 0x5a, 0xf0, 0x31, 0xff, 0xcd, 0x48, 0x4e, 0x84, 0xa9, 0x5f, 0x0f,
+	0xaa, 0x42, 0xb6, 0xab, 0x38, 0x2f, 0x62, 0x86, 0xf1, 0xc7, 0x3c, 0xb4, 0x32, 0x05, 0x73, 0x0d,
+	0x86, 0xf7, 0xa0, 0xb9, 0x08, 0x43, 0x12, 0x59, 0xe3, 0x60, 0xe1, 0x3b, 0xf1, 0x14, 0x21, 0x68,
+	0x7d, 0x41, 0xe2, 0x48, 0xb5, 0x83, 0x85, 0xcf, 0x74, 0xca, 0xa9, 0x1d, 0xea, 0x40, 0x91, 0x2e,
+	0x3c, 0x75, 0x0b, 0x7c, 0xc9, 0xc3, 0x2c, 0x78, 0xea, 0xa7, 0x0a, 0xb9, 0x31, 0xfe, 0x96, 0x97,
+	0xad, 0x70, 0x88, 0xa7, 0x37, 0xe3, 0xe0, 0x3e, 0xb4, 0x92, 0xd7, 0x73, 0x72, 0x21, 0xcd, 0x84,
+	0x38, 0xf2, 0xf9, 0x17, 0xe7, 0x78, 0xaa, 0x6e, 0x83, 0x2f, 0x79, 0x3c, 0xc6, 0x64, 0xe6, 0xfa,
+	0x4e, 0xea, 0xed, 0xad, 0x73, 0xa3, 0x2b, 0x39, 0xc9, 0x1b, 0x5b, 0x22, 0x6a, 0x1e, 0x2c, 0x85,
+	0x7d, 0x35, 0x53, 0xac, 0x8d, 0x6f, 0xf8, 0x0c, 0xa0, 0xba, 0xda, 0xad, 0xad, 0x9a, 0xb7, 0x46,
+	0xcb, 0x93, 0x30, 0x2d, 0x9a, 0x15, 0xbe, 0x7d, 0x41, 0x8d, 0x7f, 0xe4, 0xa1, 0xa6, 0x1b, 0x6a,
+	0x2a, 0xa9, 0xf3, 0x37, 0x26, 0xf5, 0xea, 0xbb, 0xfa, 0x7b, 0x00, 0xaa, 0x8f, 0xba, 0x8e, 0x0e,
+	0x71, 0x5d, 0x52, 0xce, 0x1c, 0x91, 0x39, 0x94, 0xff, 0x92, 0x11, 0xe3, 0xac, 0x42, 0xc9, 0xdb,
+	0xf3, 0x48, 0xfc, 0x5c, 0xa2, 0x66, 0x4c, 0x47, 0x41, 0x2b, 0xde, 0x67, 0x73, 0x54, 0x22, 0x29,
+	0x21, 0x3c, 0xa2, 0xd0, 0x5d, 0x2b, 0x68, 0x68, 0x07, 0x36, 0x87, 0x27, 0xcf, 0x8e, 0x4f, 0x4c,
+	0xeb, 0xd7, 0xe7, 0xe6, 0xc9, 0xe8, 0xe2, 0xe5, 0xf9, 0xe8, 0xec, 0xf5, 0x49, 0x27, 0x87, 0x3a,
+	0xd0, 0x54, 0x8c, 0xd1, 0xab, 0x67, 0xaf, 0x46, 0x9d, 0x3c, 0x42, 0xd0, 0x56, 0x94, 0xc1, 0xb3,
+	0xf3, 0xe3, 0x97, 0xa7, 0xa7, 0x9d, 0x02, 0xea, 0x42, 0xeb, 0xe8, 0xe5, 0xf9, 0xe9, 0xd9, 0x73,
+	0xeb, 0x68, 0xf0, 0xec, 0xfc, 0xf9, 0x49, 0xa7, 0x88, 0x9a, 0x50, 0x33, 0x4f, 0x46, 0xaf, 0x5e,
+	0x9a, 0x27, 0xc7, 0x9d, 0x52, 0xff, 0xc9, 0x6f, 0x0f, 0xa7, 0x2e, 0x9b, 0x2d, 0xc6, 0x07, 0x76,
+	0xe0, 0x1d, 0xce, 0xae, 0x42, 0x12, 0xcd, 0x89, 0x33, 0x25, 0xd1, 0xe3, 0x39, 0x1e, 0xd3, 0x43,
+	0xcf, 0x8d, 0xc6, 0x13, 0x76, 0x18, 0x5e, 0x4e, 0x0f, 0xf5, 0x9f, 0x5a, 0xc6, 0x15, 0xf1, 0xc7,
+	0x94, 0x2f, 0xff, 0x37, 0x00, 0x13, 0x14, 0x99, 0x22, 0x9e, 0x19, 0x00, 0x00,
 }
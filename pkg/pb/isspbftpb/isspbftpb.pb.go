@@ -24,6 +24,7 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type Preprepare struct {
 	Sn                   uint64           `protobuf:"varint,1,opt,name=sn,proto3" json:"sn,omitempty"`
 	Batch                *requestpb.Batch `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	Handoff              bool             `protobuf:"varint,3,opt,name=handoff,proto3" json:"handoff,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -68,6 +69,13 @@ func (m *Preprepare) GetBatch() *requestpb.Batch {
 	return nil
 }
 
+func (m *Preprepare) GetHandoff() bool {
+	if m != nil {
+		return m.Handoff
+	}
+	return false
+}
+
 type PersistPreprepare struct {
 	Preprepare           *Preprepare `protobuf:"bytes,1,opt,name=preprepare,proto3" json:"preprepare,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
@@ -147,19 +155,19 @@ func init() {
 func init() { proto.RegisterFile("isspbftpb/isspbftpb.proto", fileDescriptor_71680d36650d395f) }
 
 var fileDescriptor_71680d36650d395f = []byte{
-	// 210 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x8f, 0x41, 0x4b, 0xc3, 0x40,
-	0x10, 0x85, 0x49, 0xd0, 0xa2, 0x53, 0x10, 0x0d, 0x08, 0xad, 0xa7, 0x92, 0x83, 0xf4, 0xe2, 0x2e,
-	0xb4, 0xf4, 0x0f, 0x14, 0x4f, 0x9e, 0x4a, 0xbc, 0x79, 0xdb, 0xad, 0xd3, 0x64, 0xb1, 0x49, 0xc6,
-	0x99, 0xc9, 0xc1, 0x7f, 0x2f, 0x49, 0x60, 0x93, 0xdb, 0x9b, 0x37, 0xdf, 0x3c, 0xe6, 0xc1, 0x3a,
-	0x88, 0x90, 0xbf, 0x28, 0x79, 0x1b, 0x95, 0x21, 0x6e, 0xb5, 0xcd, 0xee, 0xa3, 0xf1, 0xb2, 0x66,
-	0xfc, 0xed, 0x50, 0x7a, 0x2a, 0xaa, 0x91, 0xca, 0xdf, 0x01, 0x4e, 0x8c, 0xc4, 0x48, 0x8e, 0x31,
-	0x7b, 0x80, 0x54, 0x9a, 0x55, 0xb2, 0x49, 0xb6, 0x37, 0x45, 0x2a, 0x4d, 0xf6, 0x0a, 0xb7, 0xde,
-	0xe9, 0xb9, 0x5a, 0xa5, 0x9b, 0x64, 0xbb, 0xdc, 0x3d, 0x9a, 0xe9, 0xfc, 0xd8, 0xfb, 0xc5, 0xb8,
-	0xce, 0x3f, 0xe0, 0xe9, 0x84, 0x2c, 0x41, 0x74, 0x16, 0x76, 0x00, 0xa0, 0x38, 0x0d, 0xa1, 0xcb,
-	0xdd, 0xb3, 0x99, 0xde, 0x9c, 0xd0, 0x62, 0x06, 0xe6, 0x77, 0xb0, 0xf8, 0x54, 0xa7, 0x9d, 0x1c,
-	0x0f, 0x5f, 0xfb, 0x32, 0x68, 0xd5, 0x79, 0x73, 0x6e, 0x6b, 0x5b, 0xfd, 0x11, 0xf2, 0x15, 0xbf,
-	0x4b, 0xe4, 0xb7, 0xab, 0xf3, 0x62, 0xeb, 0xc0, 0xfe, 0xa2, 0x96, 0x7e, 0x4a, 0x3b, 0xaf, 0xef,
-	0x17, 0x43, 0xb3, 0xfd, 0x7f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x7f, 0x96, 0xb9, 0xfa, 0x1c, 0x01,
-	0x00, 0x00,
+	// 223 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0x4f, 0x4b, 0xc3, 0x40,
+	0x10, 0xc5, 0x49, 0xd4, 0x5a, 0xa7, 0x20, 0x1a, 0x10, 0x52, 0x4f, 0x21, 0x07, 0xc9, 0xc5, 0x2c,
+	0xb4, 0xf4, 0x0b, 0xf4, 0xe8, 0xa9, 0xc4, 0x9b, 0x07, 0x61, 0xb7, 0x9d, 0xfc, 0xc1, 0x36, 0x19,
+	0x67, 0x26, 0x07, 0xbf, 0xbd, 0xb4, 0xa1, 0x9b, 0xdc, 0xde, 0x7b, 0xfb, 0x5b, 0x1e, 0x6f, 0x60,
+	0xd9, 0x88, 0x90, 0x2b, 0x95, 0x9c, 0xf1, 0x2a, 0x27, 0xee, 0xb4, 0x8b, 0x1e, 0x7c, 0xf0, 0xba,
+	0x64, 0xfc, 0xed, 0x51, 0xce, 0x94, 0x57, 0x03, 0x95, 0x7e, 0x03, 0xec, 0x18, 0x89, 0x91, 0x2c,
+	0x63, 0xf4, 0x08, 0xa1, 0xb4, 0x71, 0x90, 0x04, 0xd9, 0x6d, 0x11, 0x4a, 0x1b, 0xbd, 0xc1, 0x9d,
+	0xb3, 0xba, 0xaf, 0xe3, 0x30, 0x09, 0xb2, 0xc5, 0xea, 0x29, 0x1f, 0xbf, 0x6f, 0xcf, 0x79, 0x31,
+	0x3c, 0x47, 0x31, 0xdc, 0xd7, 0xb6, 0x3d, 0x74, 0x65, 0x19, 0xdf, 0x24, 0x41, 0x36, 0x2f, 0xae,
+	0x36, 0xfd, 0x80, 0xe7, 0x1d, 0xb2, 0x34, 0xa2, 0x93, 0x9a, 0x0d, 0x00, 0x79, 0x77, 0xa9, 0x5b,
+	0xac, 0x5e, 0xf2, 0x71, 0xc0, 0x88, 0x16, 0x13, 0x30, 0x9d, 0xc3, 0xec, 0x53, 0xad, 0xf6, 0xb2,
+	0xdd, 0x7c, 0xad, 0xab, 0x46, 0xeb, 0xde, 0xe5, 0xfb, 0xee, 0x64, 0xea, 0x3f, 0x42, 0x3e, 0xe2,
+	0xa1, 0x42, 0x7e, 0x3f, 0x5a, 0x27, 0xe6, 0xd4, 0xb0, 0x2b, 0xd5, 0xd0, 0x4f, 0x65, 0xa6, 0x87,
+	0x71, 0xb3, 0xcb, 0xe6, 0xf5, 0xff, 0x00, 0x43, 0x90, 0x9e, 0xd9, 0x36, 0x01, 0x00, 0x00,
 }
@@ -22,14 +22,56 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Algorithms for compressing messages (see package compressnet).
+type CompressionAlgorithm int32
+
+const (
+	CompressionAlgorithm_NONE   CompressionAlgorithm = 0
+	CompressionAlgorithm_SNAPPY CompressionAlgorithm = 1
+	CompressionAlgorithm_GZIP   CompressionAlgorithm = 2
+)
+
+var CompressionAlgorithm_name = map[int32]string{
+	0: "NONE",
+	1: "SNAPPY",
+	2: "GZIP",
+}
+
+var CompressionAlgorithm_value = map[string]int32{
+	"NONE":   0,
+	"SNAPPY": 1,
+	"GZIP":   2,
+}
+
+func (x CompressionAlgorithm) String() string {
+	return proto.EnumName(CompressionAlgorithm_name, int32(x))
+}
+
+func (CompressionAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{0}
+}
+
 type Message struct {
 	// Types that are valid to be assigned to Type:
 	//	*Message_Iss
+	//	*Message_ForwardedRequest
+	//	*Message_ReliableData
+	//	*Message_ReliableAck
+	//	*Message_Bundle
+	//	*Message_Compressed
+	//	*Message_CompressionHello
+	//	*Message_Authenticated
+	//	*Message_ReadIndexRequest
+	//	*Message_ReadIndexResponse
 	//	*Message_DummyPreprepare
-	Type                 isMessage_Type `protobuf_oneof:"type"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Type isMessage_Type `protobuf_oneof:"type"`
+	// ID assigned to the message by causality tracing (see package causality),
+	// relating the reception of the message to its sending.
+	// Zero if causality tracing is disabled at the sender.
+	CausalId             uint64   `protobuf:"varint,200,opt,name=causal_id,json=causalId,proto3" json:"causal_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Message) Reset()         { *m = Message{} }
@@ -65,12 +107,66 @@ type Message_Iss struct {
 	Iss *isspb.ISSMessage `protobuf:"bytes,1,opt,name=iss,proto3,oneof"`
 }
 
+type Message_ForwardedRequest struct {
+	ForwardedRequest *requestpb.Request `protobuf:"bytes,2,opt,name=forwarded_request,json=forwardedRequest,proto3,oneof"`
+}
+
+type Message_ReliableData struct {
+	ReliableData *ReliableData `protobuf:"bytes,3,opt,name=reliable_data,json=reliableData,proto3,oneof"`
+}
+
+type Message_ReliableAck struct {
+	ReliableAck *ReliableAck `protobuf:"bytes,4,opt,name=reliable_ack,json=reliableAck,proto3,oneof"`
+}
+
+type Message_Bundle struct {
+	Bundle *MessageBundle `protobuf:"bytes,5,opt,name=bundle,proto3,oneof"`
+}
+
+type Message_Compressed struct {
+	Compressed *CompressedMessage `protobuf:"bytes,6,opt,name=compressed,proto3,oneof"`
+}
+
+type Message_CompressionHello struct {
+	CompressionHello *CompressionHello `protobuf:"bytes,7,opt,name=compression_hello,json=compressionHello,proto3,oneof"`
+}
+
+type Message_Authenticated struct {
+	Authenticated *AuthenticatedMessage `protobuf:"bytes,8,opt,name=authenticated,proto3,oneof"`
+}
+
+type Message_ReadIndexRequest struct {
+	ReadIndexRequest *ReadIndexRequest `protobuf:"bytes,9,opt,name=read_index_request,json=readIndexRequest,proto3,oneof"`
+}
+
+type Message_ReadIndexResponse struct {
+	ReadIndexResponse *ReadIndexResponse `protobuf:"bytes,10,opt,name=read_index_response,json=readIndexResponse,proto3,oneof"`
+}
+
 type Message_DummyPreprepare struct {
 	DummyPreprepare *DummyPreprepare `protobuf:"bytes,100,opt,name=dummy_preprepare,json=dummyPreprepare,proto3,oneof"`
 }
 
 func (*Message_Iss) isMessage_Type() {}
 
+func (*Message_ForwardedRequest) isMessage_Type() {}
+
+func (*Message_ReliableData) isMessage_Type() {}
+
+func (*Message_ReliableAck) isMessage_Type() {}
+
+func (*Message_Bundle) isMessage_Type() {}
+
+func (*Message_Compressed) isMessage_Type() {}
+
+func (*Message_CompressionHello) isMessage_Type() {}
+
+func (*Message_Authenticated) isMessage_Type() {}
+
+func (*Message_ReadIndexRequest) isMessage_Type() {}
+
+func (*Message_ReadIndexResponse) isMessage_Type() {}
+
 func (*Message_DummyPreprepare) isMessage_Type() {}
 
 func (m *Message) GetType() isMessage_Type {
@@ -87,6 +183,69 @@ func (m *Message) GetIss() *isspb.ISSMessage {
 	return nil
 }
 
+func (m *Message) GetForwardedRequest() *requestpb.Request {
+	if x, ok := m.GetType().(*Message_ForwardedRequest); ok {
+		return x.ForwardedRequest
+	}
+	return nil
+}
+
+func (m *Message) GetReliableData() *ReliableData {
+	if x, ok := m.GetType().(*Message_ReliableData); ok {
+		return x.ReliableData
+	}
+	return nil
+}
+
+func (m *Message) GetReliableAck() *ReliableAck {
+	if x, ok := m.GetType().(*Message_ReliableAck); ok {
+		return x.ReliableAck
+	}
+	return nil
+}
+
+func (m *Message) GetBundle() *MessageBundle {
+	if x, ok := m.GetType().(*Message_Bundle); ok {
+		return x.Bundle
+	}
+	return nil
+}
+
+func (m *Message) GetCompressed() *CompressedMessage {
+	if x, ok := m.GetType().(*Message_Compressed); ok {
+		return x.Compressed
+	}
+	return nil
+}
+
+func (m *Message) GetCompressionHello() *CompressionHello {
+	if x, ok := m.GetType().(*Message_CompressionHello); ok {
+		return x.CompressionHello
+	}
+	return nil
+}
+
+func (m *Message) GetAuthenticated() *AuthenticatedMessage {
+	if x, ok := m.GetType().(*Message_Authenticated); ok {
+		return x.Authenticated
+	}
+	return nil
+}
+
+func (m *Message) GetReadIndexRequest() *ReadIndexRequest {
+	if x, ok := m.GetType().(*Message_ReadIndexRequest); ok {
+		return x.ReadIndexRequest
+	}
+	return nil
+}
+
+func (m *Message) GetReadIndexResponse() *ReadIndexResponse {
+	if x, ok := m.GetType().(*Message_ReadIndexResponse); ok {
+		return x.ReadIndexResponse
+	}
+	return nil
+}
+
 func (m *Message) GetDummyPreprepare() *DummyPreprepare {
 	if x, ok := m.GetType().(*Message_DummyPreprepare); ok {
 		return x.DummyPreprepare
@@ -94,10 +253,26 @@ func (m *Message) GetDummyPreprepare() *DummyPreprepare {
 	return nil
 }
 
+func (m *Message) GetCausalId() uint64 {
+	if m != nil {
+		return m.CausalId
+	}
+	return 0
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Message) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*Message_Iss)(nil),
+		(*Message_ForwardedRequest)(nil),
+		(*Message_ReliableData)(nil),
+		(*Message_ReliableAck)(nil),
+		(*Message_Bundle)(nil),
+		(*Message_Compressed)(nil),
+		(*Message_CompressionHello)(nil),
+		(*Message_Authenticated)(nil),
+		(*Message_ReadIndexRequest)(nil),
+		(*Message_ReadIndexResponse)(nil),
 		(*Message_DummyPreprepare)(nil),
 	}
 }
@@ -149,29 +324,514 @@ func (m *DummyPreprepare) GetBatch() *requestpb.Batch {
 	return nil
 }
 
+// Multiple messages to the same destination, sent together to reduce the per-message overhead of the network.
+// The receiver processes the messages in the order in which they appear in the bundle.
+type MessageBundle struct {
+	Msgs                 []*Message `protobuf:"bytes,1,rep,name=msgs,proto3" json:"msgs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *MessageBundle) Reset()         { *m = MessageBundle{} }
+func (m *MessageBundle) String() string { return proto.CompactTextString(m) }
+func (*MessageBundle) ProtoMessage()    {}
+func (*MessageBundle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{2}
+}
+
+func (m *MessageBundle) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageBundle.Unmarshal(m, b)
+}
+func (m *MessageBundle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageBundle.Marshal(b, m, deterministic)
+}
+func (m *MessageBundle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageBundle.Merge(m, src)
+}
+func (m *MessageBundle) XXX_Size() int {
+	return xxx_messageInfo_MessageBundle.Size(m)
+}
+func (m *MessageBundle) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageBundle.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageBundle proto.InternalMessageInfo
+
+func (m *MessageBundle) GetMsgs() []*Message {
+	if m != nil {
+		return m.Msgs
+	}
+	return nil
+}
+
+// Wraps a message sent using the reliable retransmission layer (see package reliablenet).
+type ReliableData struct {
+	Session              uint64   `protobuf:"varint,1,opt,name=session,proto3" json:"session,omitempty"`
+	Sn                   uint64   `protobuf:"varint,2,opt,name=sn,proto3" json:"sn,omitempty"`
+	LowSn                uint64   `protobuf:"varint,3,opt,name=low_sn,json=lowSn,proto3" json:"low_sn,omitempty"`
+	Msg                  *Message `protobuf:"bytes,4,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReliableData) Reset()         { *m = ReliableData{} }
+func (m *ReliableData) String() string { return proto.CompactTextString(m) }
+func (*ReliableData) ProtoMessage()    {}
+func (*ReliableData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{3}
+}
+
+func (m *ReliableData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReliableData.Unmarshal(m, b)
+}
+func (m *ReliableData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReliableData.Marshal(b, m, deterministic)
+}
+func (m *ReliableData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReliableData.Merge(m, src)
+}
+func (m *ReliableData) XXX_Size() int {
+	return xxx_messageInfo_ReliableData.Size(m)
+}
+func (m *ReliableData) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReliableData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReliableData proto.InternalMessageInfo
+
+func (m *ReliableData) GetSession() uint64 {
+	if m != nil {
+		return m.Session
+	}
+	return 0
+}
+
+func (m *ReliableData) GetSn() uint64 {
+	if m != nil {
+		return m.Sn
+	}
+	return 0
+}
+
+func (m *ReliableData) GetLowSn() uint64 {
+	if m != nil {
+		return m.LowSn
+	}
+	return 0
+}
+
+func (m *ReliableData) GetMsg() *Message {
+	if m != nil {
+		return m.Msg
+	}
+	return nil
+}
+
+// Acknowledges the reception of messages sent using the reliable retransmission layer (see package reliablenet).
+type ReliableAck struct {
+	Session              uint64     `protobuf:"varint,1,opt,name=session,proto3" json:"session,omitempty"`
+	Ranges               []*SnRange `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ReliableAck) Reset()         { *m = ReliableAck{} }
+func (m *ReliableAck) String() string { return proto.CompactTextString(m) }
+func (*ReliableAck) ProtoMessage()    {}
+func (*ReliableAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{4}
+}
+
+func (m *ReliableAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReliableAck.Unmarshal(m, b)
+}
+func (m *ReliableAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReliableAck.Marshal(b, m, deterministic)
+}
+func (m *ReliableAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReliableAck.Merge(m, src)
+}
+func (m *ReliableAck) XXX_Size() int {
+	return xxx_messageInfo_ReliableAck.Size(m)
+}
+func (m *ReliableAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReliableAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReliableAck proto.InternalMessageInfo
+
+func (m *ReliableAck) GetSession() uint64 {
+	if m != nil {
+		return m.Session
+	}
+	return 0
+}
+
+func (m *ReliableAck) GetRanges() []*SnRange {
+	if m != nil {
+		return m.Ranges
+	}
+	return nil
+}
+
+// Range of sequence numbers, from (inclusive) to (exclusive).
+type SnRange struct {
+	From                 uint64   `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   uint64   `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnRange) Reset()         { *m = SnRange{} }
+func (m *SnRange) String() string { return proto.CompactTextString(m) }
+func (*SnRange) ProtoMessage()    {}
+func (*SnRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{5}
+}
+
+func (m *SnRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnRange.Unmarshal(m, b)
+}
+func (m *SnRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnRange.Marshal(b, m, deterministic)
+}
+func (m *SnRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnRange.Merge(m, src)
+}
+func (m *SnRange) XXX_Size() int {
+	return xxx_messageInfo_SnRange.Size(m)
+}
+func (m *SnRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnRange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnRange proto.InternalMessageInfo
+
+func (m *SnRange) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *SnRange) GetTo() uint64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+// Wraps a compressed message (see package compressnet).
+type CompressedMessage struct {
+	Algorithm            CompressionAlgorithm `protobuf:"varint,1,opt,name=algorithm,proto3,enum=messagepb.CompressionAlgorithm" json:"algorithm,omitempty"`
+	Data                 []byte               `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CompressedMessage) Reset()         { *m = CompressedMessage{} }
+func (m *CompressedMessage) String() string { return proto.CompactTextString(m) }
+func (*CompressedMessage) ProtoMessage()    {}
+func (*CompressedMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{6}
+}
+
+func (m *CompressedMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompressedMessage.Unmarshal(m, b)
+}
+func (m *CompressedMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompressedMessage.Marshal(b, m, deterministic)
+}
+func (m *CompressedMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompressedMessage.Merge(m, src)
+}
+func (m *CompressedMessage) XXX_Size() int {
+	return xxx_messageInfo_CompressedMessage.Size(m)
+}
+func (m *CompressedMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompressedMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompressedMessage proto.InternalMessageInfo
+
+func (m *CompressedMessage) GetAlgorithm() CompressionAlgorithm {
+	if m != nil {
+		return m.Algorithm
+	}
+	return CompressionAlgorithm_NONE
+}
+
+func (m *CompressedMessage) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// Announces the compression algorithms supported by the sender (see package compressnet).
+type CompressionHello struct {
+	Algorithms           []CompressionAlgorithm `protobuf:"varint,1,rep,packed,name=algorithms,proto3,enum=messagepb.CompressionAlgorithm" json:"algorithms,omitempty"`
+	Reply                bool                   `protobuf:"varint,2,opt,name=reply,proto3" json:"reply,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *CompressionHello) Reset()         { *m = CompressionHello{} }
+func (m *CompressionHello) String() string { return proto.CompactTextString(m) }
+func (*CompressionHello) ProtoMessage()    {}
+func (*CompressionHello) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{7}
+}
+
+func (m *CompressionHello) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompressionHello.Unmarshal(m, b)
+}
+func (m *CompressionHello) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompressionHello.Marshal(b, m, deterministic)
+}
+func (m *CompressionHello) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompressionHello.Merge(m, src)
+}
+func (m *CompressionHello) XXX_Size() int {
+	return xxx_messageInfo_CompressionHello.Size(m)
+}
+func (m *CompressionHello) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompressionHello.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompressionHello proto.InternalMessageInfo
+
+func (m *CompressionHello) GetAlgorithms() []CompressionAlgorithm {
+	if m != nil {
+		return m.Algorithms
+	}
+	return nil
+}
+
+func (m *CompressionHello) GetReply() bool {
+	if m != nil {
+		return m.Reply
+	}
+	return false
+}
+
+// Wraps a message authenticated using a message authentication code or a signature (see package authnet).
+type AuthenticatedMessage struct {
+	Msg                  []byte   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	Mac                  []byte   `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthenticatedMessage) Reset()         { *m = AuthenticatedMessage{} }
+func (m *AuthenticatedMessage) String() string { return proto.CompactTextString(m) }
+func (*AuthenticatedMessage) ProtoMessage()    {}
+func (*AuthenticatedMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{8}
+}
+
+func (m *AuthenticatedMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticatedMessage.Unmarshal(m, b)
+}
+func (m *AuthenticatedMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticatedMessage.Marshal(b, m, deterministic)
+}
+func (m *AuthenticatedMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticatedMessage.Merge(m, src)
+}
+func (m *AuthenticatedMessage) XXX_Size() int {
+	return xxx_messageInfo_AuthenticatedMessage.Size(m)
+}
+func (m *AuthenticatedMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticatedMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticatedMessage proto.InternalMessageInfo
+
+func (m *AuthenticatedMessage) GetMsg() []byte {
+	if m != nil {
+		return m.Msg
+	}
+	return nil
+}
+
+func (m *AuthenticatedMessage) GetMac() []byte {
+	if m != nil {
+		return m.Mac
+	}
+	return nil
+}
+
+func (m *AuthenticatedMessage) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// Asks the receiver how many batches it has committed, in order to serve a linearizable read (see Node.Read).
+type ReadIndexRequest struct {
+	ReadId               uint64   `protobuf:"varint,1,opt,name=read_id,json=readId,proto3" json:"read_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadIndexRequest) Reset()         { *m = ReadIndexRequest{} }
+func (m *ReadIndexRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadIndexRequest) ProtoMessage()    {}
+func (*ReadIndexRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{9}
+}
+
+func (m *ReadIndexRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadIndexRequest.Unmarshal(m, b)
+}
+func (m *ReadIndexRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadIndexRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadIndexRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadIndexRequest.Merge(m, src)
+}
+func (m *ReadIndexRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadIndexRequest.Size(m)
+}
+func (m *ReadIndexRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadIndexRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadIndexRequest proto.InternalMessageInfo
+
+func (m *ReadIndexRequest) GetReadId() uint64 {
+	if m != nil {
+		return m.ReadId
+	}
+	return 0
+}
+
+// Answers a ReadIndexRequest (see Node.Read).
+type ReadIndexResponse struct {
+	ReadId               uint64   `protobuf:"varint,1,opt,name=read_id,json=readId,proto3" json:"read_id,omitempty"`
+	Committed            uint64   `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadIndexResponse) Reset()         { *m = ReadIndexResponse{} }
+func (m *ReadIndexResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadIndexResponse) ProtoMessage()    {}
+func (*ReadIndexResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5e5db85d23e1fb5b, []int{10}
+}
+
+func (m *ReadIndexResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadIndexResponse.Unmarshal(m, b)
+}
+func (m *ReadIndexResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadIndexResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadIndexResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadIndexResponse.Merge(m, src)
+}
+func (m *ReadIndexResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadIndexResponse.Size(m)
+}
+func (m *ReadIndexResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadIndexResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadIndexResponse proto.InternalMessageInfo
+
+func (m *ReadIndexResponse) GetReadId() uint64 {
+	if m != nil {
+		return m.ReadId
+	}
+	return 0
+}
+
+func (m *ReadIndexResponse) GetCommitted() uint64 {
+	if m != nil {
+		return m.Committed
+	}
+	return 0
+}
+
 func init() {
+	proto.RegisterEnum("messagepb.CompressionAlgorithm", CompressionAlgorithm_name, CompressionAlgorithm_value)
 	proto.RegisterType((*Message)(nil), "messagepb.Message")
 	proto.RegisterType((*DummyPreprepare)(nil), "messagepb.DummyPreprepare")
+	proto.RegisterType((*MessageBundle)(nil), "messagepb.MessageBundle")
+	proto.RegisterType((*ReliableData)(nil), "messagepb.ReliableData")
+	proto.RegisterType((*ReliableAck)(nil), "messagepb.ReliableAck")
+	proto.RegisterType((*SnRange)(nil), "messagepb.SnRange")
+	proto.RegisterType((*CompressedMessage)(nil), "messagepb.CompressedMessage")
+	proto.RegisterType((*CompressionHello)(nil), "messagepb.CompressionHello")
+	proto.RegisterType((*AuthenticatedMessage)(nil), "messagepb.AuthenticatedMessage")
+	proto.RegisterType((*ReadIndexRequest)(nil), "messagepb.ReadIndexRequest")
+	proto.RegisterType((*ReadIndexResponse)(nil), "messagepb.ReadIndexResponse")
 }
 
 func init() { proto.RegisterFile("messagepb/messagepb.proto", fileDescriptor_5e5db85d23e1fb5b) }
 
 var fileDescriptor_5e5db85d23e1fb5b = []byte{
-	// 249 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0x41, 0x4b, 0xc3, 0x30,
-	0x14, 0xc7, 0xd7, 0x3a, 0x27, 0x46, 0x70, 0x5b, 0x4e, 0x5d, 0x4f, 0x32, 0x50, 0xbc, 0x98, 0x80,
-	0xc3, 0x2f, 0x50, 0x04, 0xb7, 0x83, 0x20, 0xdd, 0xcd, 0x8b, 0x34, 0xeb, 0xb3, 0x2d, 0x2e, 0xeb,
-	0x33, 0x2f, 0x3d, 0xf4, 0xe8, 0x37, 0x97, 0x26, 0x92, 0xa9, 0x10, 0xc2, 0xe3, 0xf7, 0xff, 0x3d,
-	0xf2, 0xf2, 0xd8, 0x42, 0x03, 0x51, 0x51, 0x01, 0x2a, 0x19, 0x2a, 0x81, 0xa6, 0xb5, 0x2d, 0x3f,
-	0x0f, 0x20, 0x5d, 0x18, 0xf8, 0xec, 0x80, 0x2c, 0x2a, 0x19, 0x2a, 0x6f, 0xa5, 0xf3, 0x86, 0x08,
-	0x95, 0x74, 0xb7, 0x47, 0xcb, 0xaf, 0x88, 0x9d, 0x3d, 0xfb, 0x5e, 0x7e, 0xcd, 0x4e, 0x1a, 0xa2,
-	0x24, 0xba, 0x8a, 0x6e, 0x2f, 0xee, 0xe7, 0xc2, 0x6b, 0x9b, 0xed, 0xf6, 0x27, 0x5f, 0x8f, 0xf2,
-	0x21, 0xe7, 0x4f, 0x6c, 0x56, 0x76, 0x5a, 0xf7, 0x6f, 0x68, 0x60, 0x38, 0x85, 0x81, 0xa4, 0x74,
-	0x3d, 0xa9, 0x38, 0xce, 0xf5, 0x38, 0x28, 0x2f, 0xc1, 0x58, 0x8f, 0xf2, 0x69, 0xf9, 0x17, 0x65,
-	0x13, 0x36, 0xb6, 0x3d, 0xc2, 0x72, 0xc3, 0xa6, 0xff, 0x6c, 0x7e, 0xc9, 0x62, 0x3a, 0xb8, 0x49,
-	0xc6, 0x79, 0x4c, 0x07, 0x7e, 0xc3, 0x4e, 0x55, 0x61, 0x77, 0x75, 0x12, 0xbb, 0x87, 0x66, 0xe2,
-	0xf8, 0xb5, 0x6c, 0xe0, 0xb9, 0x8f, 0xb3, 0x87, 0xd7, 0x55, 0xd5, 0xd8, 0xba, 0x53, 0x62, 0xd7,
-	0x6a, 0x59, 0xf7, 0x08, 0x66, 0x0f, 0x65, 0x05, 0xe6, 0x6e, 0x5f, 0x28, 0x92, 0xba, 0x31, 0xea,
-	0xdd, 0x4a, 0xfc, 0xa8, 0xe4, 0xef, 0x25, 0xaa, 0x89, 0x5b, 0xc6, 0xea, 0x3b, 0x00, 0x00, 0xff,
-	0xff, 0x39, 0x65, 0x14, 0x1b, 0x62, 0x01, 0x00, 0x00,
+	// 795 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x6d, 0x6f, 0xdc, 0x44,
+	0x10, 0x76, 0x2e, 0x17, 0x5f, 0x32, 0x49, 0x5a, 0xdf, 0x12, 0xa8, 0x1b, 0x2a, 0x81, 0x2c, 0xa8,
+	0x50, 0x51, 0xef, 0xa4, 0x56, 0xc0, 0x07, 0x44, 0xd1, 0x85, 0xa2, 0xf8, 0x8a, 0x08, 0xd1, 0xde,
+	0x17, 0xe8, 0x17, 0x6b, 0xed, 0xdd, 0xf8, 0xac, 0xd8, 0x5e, 0xb3, 0xbb, 0x56, 0xb8, 0x1f, 0xc0,
+	0x7f, 0xe3, 0x67, 0xa1, 0xdd, 0xf5, 0xd9, 0xbe, 0x17, 0x50, 0xa5, 0x28, 0x9a, 0x7d, 0x66, 0x9e,
+	0x99, 0xd9, 0x9d, 0x79, 0xce, 0xf0, 0xb4, 0x60, 0x52, 0x92, 0x94, 0x55, 0xf1, 0xb4, 0xb5, 0x26,
+	0x95, 0xe0, 0x8a, 0xa3, 0x93, 0x16, 0xb8, 0x7c, 0x2a, 0xd8, 0x9f, 0x35, 0x93, 0xaa, 0x8a, 0xa7,
+	0xad, 0x65, 0xa3, 0x2e, 0xc7, 0x99, 0x94, 0x55, 0x3c, 0x35, 0xff, 0x2d, 0x14, 0xfc, 0xed, 0xc2,
+	0xe8, 0x57, 0xcb, 0x45, 0x5f, 0xc2, 0x61, 0x26, 0xa5, 0x7f, 0xf0, 0xf9, 0xc1, 0x57, 0xa7, 0xaf,
+	0xc6, 0x13, 0x1b, 0x36, 0x5f, 0x2c, 0x1a, 0x7f, 0xe8, 0x60, 0xed, 0x47, 0x33, 0x18, 0xdf, 0x71,
+	0xf1, 0x40, 0x04, 0x65, 0x34, 0x6a, 0x4a, 0xf8, 0x03, 0x43, 0x42, 0x93, 0xae, 0x24, 0xb6, 0x56,
+	0xe8, 0x60, 0xaf, 0x0d, 0x6f, 0x30, 0xf4, 0x06, 0xce, 0x05, 0xcb, 0x33, 0x12, 0xe7, 0x2c, 0xa2,
+	0x44, 0x11, 0xff, 0xd0, 0xd0, 0x9f, 0x4c, 0xba, 0x7b, 0xe1, 0xc6, 0xff, 0x96, 0x28, 0x12, 0x3a,
+	0xf8, 0x4c, 0xf4, 0xce, 0xe8, 0x7b, 0x68, 0xcf, 0x11, 0x49, 0xee, 0xfd, 0xa1, 0xa1, 0x7f, 0xb2,
+	0x87, 0x3e, 0x4b, 0xee, 0x43, 0x07, 0x9f, 0x8a, 0xee, 0x88, 0x5e, 0x81, 0x1b, 0xd7, 0x25, 0xcd,
+	0x99, 0x7f, 0x64, 0x68, 0x7e, 0x8f, 0xd6, 0x5c, 0xf5, 0xca, 0xf8, 0x43, 0x07, 0x37, 0x91, 0xe8,
+	0x0d, 0x40, 0xc2, 0x8b, 0x4a, 0x30, 0x29, 0x19, 0xf5, 0x5d, 0xc3, 0x7b, 0xd6, 0xe3, 0xfd, 0xd4,
+	0x3a, 0xbb, 0xc7, 0xea, 0x31, 0xd0, 0x3b, 0x18, 0xaf, 0x4f, 0x19, 0x2f, 0xa3, 0x25, 0xcb, 0x73,
+	0xee, 0x8f, 0x4c, 0x9a, 0x4f, 0xf7, 0xa4, 0xc9, 0x78, 0x19, 0xea, 0x10, 0xfd, 0x78, 0xc9, 0x16,
+	0x86, 0xae, 0xe1, 0x9c, 0xd4, 0x6a, 0xc9, 0x4a, 0x95, 0x25, 0x44, 0x31, 0xea, 0x1f, 0x9b, 0x3c,
+	0x9f, 0xf5, 0xf2, 0xcc, 0xfa, 0xfe, 0xae, 0xa3, 0x4d, 0x1e, 0xfa, 0x05, 0x90, 0x60, 0x84, 0x46,
+	0x59, 0x49, 0xd9, 0x5f, 0xed, 0x24, 0x4f, 0x76, 0xba, 0xc2, 0x8c, 0xd0, 0xb9, 0x8e, 0xe9, 0x8d,
+	0x54, 0x6c, 0x61, 0xe8, 0x06, 0x3e, 0xda, 0x48, 0x26, 0x2b, 0x5e, 0x4a, 0xe6, 0xc3, 0xce, 0x53,
+	0xf5, 0xb2, 0xd9, 0x98, 0xd0, 0xc1, 0x63, 0xb1, 0x0d, 0xa2, 0x6b, 0xf0, 0x68, 0x5d, 0x14, 0xab,
+	0xa8, 0x12, 0x4c, 0xff, 0x11, 0xc1, 0x7c, 0x6a, 0x92, 0x5d, 0xf6, 0x92, 0xbd, 0xd5, 0x21, 0xb7,
+	0x6d, 0x44, 0xe8, 0xe0, 0xc7, 0x74, 0x13, 0x42, 0xcf, 0xe0, 0x24, 0x21, 0xb5, 0x24, 0x79, 0x94,
+	0x51, 0xff, 0x1f, 0xbd, 0xdc, 0x43, 0x7c, 0x6c, 0x91, 0x39, 0xbd, 0x72, 0x61, 0xa8, 0x56, 0x15,
+	0x0b, 0xe6, 0xf0, 0x78, 0x2b, 0x17, 0x7a, 0x04, 0x03, 0x59, 0xfa, 0x96, 0x30, 0x90, 0x25, 0x7a,
+	0x0e, 0x47, 0x31, 0x51, 0xc9, 0xb2, 0xd9, 0x75, 0xaf, 0xb7, 0xeb, 0x57, 0x1a, 0xc7, 0xd6, 0x1d,
+	0x7c, 0x07, 0xe7, 0x1b, 0x6b, 0x84, 0x9e, 0xc3, 0xb0, 0x90, 0xa9, 0x16, 0xd6, 0xa1, 0xd1, 0xc8,
+	0xce, 0xba, 0x61, 0xe3, 0x0f, 0x6a, 0x38, 0xeb, 0x6f, 0x3d, 0xf2, 0x61, 0x24, 0xed, 0xe0, 0x9b,
+	0x2e, 0xd6, 0xc7, 0xa6, 0xb5, 0x41, 0xdb, 0xda, 0xc7, 0xe0, 0xe6, 0xfc, 0x21, 0x92, 0xa5, 0x11,
+	0xd2, 0x10, 0x1f, 0xe5, 0xfc, 0x61, 0x51, 0xa2, 0x2f, 0xe0, 0xb0, 0x90, 0x69, 0xa3, 0x8e, 0x7d,
+	0x75, 0xb5, 0x3b, 0x58, 0xc0, 0x69, 0x4f, 0x2d, 0xff, 0x53, 0xf5, 0x05, 0xb8, 0x82, 0x94, 0x29,
+	0x93, 0xfe, 0x60, 0xe7, 0x26, 0x8b, 0x12, 0x6b, 0x17, 0x6e, 0x22, 0x82, 0x97, 0x30, 0x6a, 0x20,
+	0x84, 0x60, 0x78, 0x27, 0x78, 0xd1, 0x64, 0x33, 0xb6, 0xbe, 0x80, 0xe2, 0xeb, 0x0b, 0x28, 0x1e,
+	0xdc, 0xc1, 0x78, 0x47, 0x42, 0xe8, 0x07, 0x38, 0x21, 0x79, 0xca, 0x45, 0xa6, 0x96, 0x96, 0xfd,
+	0x68, 0x63, 0xc9, 0x7b, 0x62, 0x99, 0xad, 0xc3, 0x70, 0xc7, 0xd0, 0x75, 0xcd, 0x6f, 0x8b, 0xae,
+	0x72, 0x86, 0x8d, 0x1d, 0x64, 0xe0, 0x6d, 0x6b, 0x0c, 0xfd, 0x08, 0xd0, 0x92, 0xec, 0x90, 0x3e,
+	0xa0, 0x4e, 0x8f, 0x82, 0x2e, 0xe0, 0x48, 0xb0, 0x2a, 0x5f, 0x99, 0x4a, 0xc7, 0xd8, 0x1e, 0x82,
+	0xdf, 0xe1, 0x62, 0x9f, 0x0c, 0x91, 0x67, 0x87, 0x72, 0x60, 0xba, 0xd2, 0xa6, 0x41, 0x48, 0xd2,
+	0xf4, 0xa9, 0x4d, 0xbd, 0xb3, 0x32, 0x4b, 0x4b, 0xa2, 0x6a, 0xc1, 0xcc, 0x48, 0xcf, 0x70, 0x07,
+	0x04, 0x5f, 0x83, 0xb7, 0x2d, 0x49, 0xf4, 0x04, 0x46, 0x56, 0x7e, 0xb4, 0x79, 0x67, 0xd7, 0x48,
+	0x8a, 0x06, 0xef, 0x60, 0xbc, 0xa3, 0xb8, 0xff, 0x8c, 0x36, 0x62, 0xe1, 0x45, 0x91, 0x29, 0xfd,
+	0xbb, 0x62, 0xc7, 0xd3, 0x01, 0x2f, 0xbe, 0x85, 0x8b, 0x7d, 0x8f, 0x81, 0x8e, 0x61, 0x78, 0xf3,
+	0xdb, 0xcd, 0xcf, 0x9e, 0x83, 0x00, 0xdc, 0xc5, 0xcd, 0xec, 0xf6, 0xf6, 0x0f, 0xef, 0x40, 0xa3,
+	0xd7, 0xef, 0xe7, 0xb7, 0xde, 0xe0, 0xea, 0x9b, 0xf7, 0xaf, 0xd3, 0x4c, 0x2d, 0xeb, 0x78, 0x92,
+	0xf0, 0x62, 0xba, 0x5c, 0x55, 0x4c, 0xe4, 0x8c, 0xa6, 0x4c, 0xbc, 0xcc, 0x49, 0x2c, 0xa7, 0x45,
+	0x26, 0xe2, 0x3b, 0x35, 0xad, 0xee, 0xd3, 0x69, 0xff, 0xd3, 0x16, 0xbb, 0xe6, 0x13, 0xf5, 0xfa,
+	0xdf, 0x01, 0x00, 0xf6, 0x91, 0x67, 0x59, 0xf8, 0x06, 0x00, 0x00,
 }
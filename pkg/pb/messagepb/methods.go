@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepb
+
+import (
+	"fmt"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+)
+
+// TypeName returns a short name of the type of the message (e.g. "checkpoint" or "forwarded_request"),
+// e.g., for labeling statistics about messages.
+// The messages of the ordering sub-protocol are distinguished by their phase (e.g. "pbft_preprepare").
+// TypeName does not require the message to be valid, as it is also used for accounting for invalid messages.
+func (m *Message) TypeName() string {
+	switch t := m.GetType().(type) {
+	case *Message_Iss:
+		switch it := t.Iss.GetType().(type) {
+		case *isspb.ISSMessage_Sb:
+			switch sbt := it.Sb.GetMsg().GetType().(type) {
+			case *isspb.SBInstanceMessage_PbftPreprepare:
+				return "pbft_preprepare"
+			default:
+				return fmt.Sprintf("%T", sbt)
+			}
+		case *isspb.ISSMessage_Checkpoint:
+			return "checkpoint"
+		case *isspb.ISSMessage_RetransmitRequests:
+			return "retransmit_requests"
+		case *isspb.ISSMessage_FetchRequests:
+			return "fetch_requests"
+		case *isspb.ISSMessage_Heartbeat:
+			return "heartbeat"
+		case *isspb.ISSMessage_StateRequest:
+			return "state_request"
+		case *isspb.ISSMessage_StateTransfer:
+			return "state_transfer"
+		default:
+			return fmt.Sprintf("%T", it)
+		}
+	case *Message_ForwardedRequest:
+		return "forwarded_request"
+	case *Message_ReliableData:
+		return "reliable_data"
+	case *Message_ReliableAck:
+		return "reliable_ack"
+	case *Message_Bundle:
+		return "bundle"
+	case *Message_Compressed:
+		return "compressed"
+	case *Message_CompressionHello:
+		return "compression_hello"
+	case *Message_Authenticated:
+		return "authenticated"
+	case *Message_ReadIndexRequest:
+		return "read_index_request"
+	case *Message_ReadIndexResponse:
+		return "read_index_response"
+	case *Message_DummyPreprepare:
+		return "dummy_preprepare"
+	default:
+		return fmt.Sprintf("%T", t)
+	}
+}
+
+// BestEffort returns true if the protocol tolerates the loss of the message, as it repeats such messages itself
+// when needed: forwarded requests, requests for their retransmission, and heartbeats.
+// A MessageBundle is best-effort if all the contained messages are, and so is a ReliableData message
+// if the wrapped message is. All other messages are critical for the progress of the protocol.
+func (m *Message) BestEffort() bool {
+	switch t := m.GetType().(type) {
+	case *Message_Bundle:
+		for _, bundled := range t.Bundle.Msgs {
+			if !bundled.BestEffort() {
+				return false
+			}
+		}
+		return true
+	case *Message_ReliableData:
+		return t.ReliableData.GetMsg().BestEffort()
+	case *Message_ForwardedRequest:
+		return true
+	case *Message_Iss:
+		switch t.Iss.GetType().(type) {
+		case *isspb.ISSMessage_RetransmitRequests, *isspb.ISSMessage_FetchRequests, *isspb.ISSMessage_Heartbeat:
+			return true
+		}
+	}
+	return false
+}
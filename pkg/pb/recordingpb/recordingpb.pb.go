@@ -22,12 +22,15 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Entry struct {
-	NodeId               uint64           `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	Time                 int64            `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
-	Events               []*eventpb.Event `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	NodeId uint64           `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Time   int64            `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Events []*eventpb.Event `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	// Sequence number of the entry, starting at 1 and assigned in the (deterministic) order
+	// in which the Node dispatched the events. Zero if the recording does not contain sequence numbers.
+	SeqNo                uint64   `protobuf:"varint,4,opt,name=seq_no,json=seqNo,proto3" json:"seq_no,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Entry) Reset()         { *m = Entry{} }
@@ -76,24 +79,122 @@ func (m *Entry) GetEvents() []*eventpb.Event {
 	return nil
 }
 
+func (m *Entry) GetSeqNo() uint64 {
+	if m != nil {
+		return m.SeqNo
+	}
+	return 0
+}
+
+// ProtocolStep records the application of a single input event to the protocol state machine of a node
+// (see eventlog.ProtocolRecorder), together with the output events the protocol produced in response.
+type ProtocolStep struct {
+	NodeId uint64 `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// Sequence number of the step, starting at 1.
+	StepNo uint64 `protobuf:"varint,2,opt,name=step_no,json=stepNo,proto3" json:"step_no,omitempty"`
+	// Number of Tick events applied to the protocol up to and including this step.
+	Tick uint64 `protobuf:"varint,3,opt,name=tick,proto3" json:"tick,omitempty"`
+	// Wall clock time (in nanoseconds since the Unix epoch) the protocol observed during the step.
+	Time                 int64            `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"`
+	Input                *eventpb.Event   `protobuf:"bytes,5,opt,name=input,proto3" json:"input,omitempty"`
+	Output               []*eventpb.Event `protobuf:"bytes,6,rep,name=output,proto3" json:"output,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ProtocolStep) Reset()         { *m = ProtocolStep{} }
+func (m *ProtocolStep) String() string { return proto.CompactTextString(m) }
+func (*ProtocolStep) ProtoMessage()    {}
+func (*ProtocolStep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a019c0035c997111, []int{1}
+}
+
+func (m *ProtocolStep) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProtocolStep.Unmarshal(m, b)
+}
+func (m *ProtocolStep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProtocolStep.Marshal(b, m, deterministic)
+}
+func (m *ProtocolStep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProtocolStep.Merge(m, src)
+}
+func (m *ProtocolStep) XXX_Size() int {
+	return xxx_messageInfo_ProtocolStep.Size(m)
+}
+func (m *ProtocolStep) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProtocolStep.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProtocolStep proto.InternalMessageInfo
+
+func (m *ProtocolStep) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *ProtocolStep) GetStepNo() uint64 {
+	if m != nil {
+		return m.StepNo
+	}
+	return 0
+}
+
+func (m *ProtocolStep) GetTick() uint64 {
+	if m != nil {
+		return m.Tick
+	}
+	return 0
+}
+
+func (m *ProtocolStep) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *ProtocolStep) GetInput() *eventpb.Event {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+func (m *ProtocolStep) GetOutput() []*eventpb.Event {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Entry)(nil), "recordingpb.Entry")
+	proto.RegisterType((*ProtocolStep)(nil), "recordingpb.ProtocolStep")
 }
 
 func init() { proto.RegisterFile("recordingpb/recordingpb.proto", fileDescriptor_a019c0035c997111) }
 
 var fileDescriptor_a019c0035c997111 = []byte{
-	// 186 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2d, 0x4a, 0x4d, 0xce,
-	0x2f, 0x4a, 0xc9, 0xcc, 0x4b, 0x2f, 0x48, 0xd2, 0x47, 0x62, 0xeb, 0x15, 0x14, 0xe5, 0x97, 0xe4,
-	0x0b, 0x71, 0x23, 0x09, 0x49, 0x89, 0xa6, 0x96, 0xa5, 0xe6, 0x95, 0x14, 0x24, 0xe9, 0x43, 0x69,
-	0x88, 0x1a, 0xa5, 0x18, 0x2e, 0x56, 0xd7, 0xbc, 0x92, 0xa2, 0x4a, 0x21, 0x71, 0x2e, 0xf6, 0xbc,
-	0xfc, 0x94, 0xd4, 0xf8, 0xcc, 0x14, 0x09, 0x46, 0x05, 0x46, 0x0d, 0x96, 0x20, 0x36, 0x10, 0xd7,
-	0x33, 0x45, 0x48, 0x88, 0x8b, 0xa5, 0x24, 0x33, 0x37, 0x55, 0x82, 0x49, 0x81, 0x51, 0x83, 0x39,
-	0x08, 0xcc, 0x16, 0x52, 0xe3, 0x62, 0x03, 0x1b, 0x53, 0x2c, 0xc1, 0xac, 0xc0, 0xac, 0xc1, 0x6d,
-	0xc4, 0xa7, 0x07, 0x33, 0xd5, 0x15, 0x44, 0x07, 0x41, 0x65, 0x9d, 0xcc, 0xa3, 0x4c, 0xd3, 0x33,
-	0x4b, 0x32, 0x4a, 0x93, 0xf4, 0x92, 0xf3, 0x73, 0xf5, 0x33, 0x2a, 0x0b, 0x52, 0x8b, 0x72, 0x52,
-	0x53, 0xd2, 0x53, 0x8b, 0x74, 0x73, 0x12, 0x93, 0x8a, 0xf5, 0x73, 0x33, 0x8b, 0x92, 0xd2, 0x4a,
-	0xf4, 0x0b, 0xb2, 0xd3, 0xf5, 0x51, 0x3d, 0x90, 0xc4, 0x06, 0x76, 0x9d, 0x31, 0x20, 0x00, 0x00,
-	0xff, 0xff, 0x75, 0x22, 0x37, 0xb2, 0xe2, 0x00, 0x00, 0x00,
+	// 273 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0x4d, 0x4b, 0x03, 0x31,
+	0x10, 0x65, 0xbb, 0x1f, 0x85, 0x54, 0x3c, 0x04, 0x4a, 0x83, 0x20, 0x2c, 0x45, 0x64, 0x2f, 0x6e,
+	0x40, 0x11, 0xef, 0x42, 0x0f, 0x5e, 0x8a, 0xac, 0x37, 0x2f, 0xc5, 0xec, 0x8e, 0xdb, 0xd0, 0xdd,
+	0x24, 0x4d, 0xb2, 0x42, 0xff, 0x98, 0xbf, 0x4f, 0x92, 0x54, 0x59, 0xa1, 0x3d, 0xcd, 0x9b, 0x79,
+	0xc3, 0x9b, 0xf7, 0x12, 0x74, 0xad, 0xa1, 0x96, 0xba, 0xe1, 0xa2, 0x55, 0x8c, 0x8e, 0x70, 0xa9,
+	0xb4, 0xb4, 0x12, 0xcf, 0x46, 0xa3, 0xab, 0x39, 0x7c, 0x81, 0xb0, 0x8a, 0xd1, 0x63, 0x0d, 0x3b,
+	0x4b, 0x83, 0xd2, 0x95, 0xb0, 0xfa, 0x80, 0x17, 0x68, 0x2a, 0x64, 0x03, 0x1b, 0xde, 0x90, 0x28,
+	0x8f, 0x8a, 0xa4, 0xca, 0x5c, 0xfb, 0xd2, 0x60, 0x8c, 0x12, 0xcb, 0x7b, 0x20, 0x93, 0x3c, 0x2a,
+	0xe2, 0xca, 0x63, 0x7c, 0x8b, 0x32, 0x2f, 0x63, 0x48, 0x9c, 0xc7, 0xc5, 0xec, 0xfe, 0xb2, 0xfc,
+	0x55, 0x5d, 0xb9, 0x5a, 0x1d, 0x59, 0x3c, 0x47, 0x99, 0x81, 0xfd, 0x46, 0x48, 0x92, 0x78, 0xcd,
+	0xd4, 0xc0, 0x7e, 0x2d, 0x97, 0xdf, 0x11, 0xba, 0x78, 0x75, 0xe7, 0x6b, 0xd9, 0xbd, 0x59, 0x50,
+	0xe7, 0x8f, 0x2f, 0xd0, 0xd4, 0x58, 0x50, 0x4e, 0x61, 0x12, 0x08, 0xd7, 0xae, 0x65, 0x70, 0x55,
+	0xef, 0x48, 0xec, 0xa7, 0x1e, 0xff, 0x39, 0x4d, 0x46, 0x4e, 0x6f, 0x50, 0xca, 0x85, 0x1a, 0x2c,
+	0x49, 0xf3, 0xe8, 0x84, 0xd1, 0x40, 0xba, 0x3c, 0x72, 0xb0, 0x6e, 0x2d, 0x3b, 0x9d, 0x27, 0xb0,
+	0xcf, 0x4f, 0xef, 0x8f, 0x2d, 0xb7, 0xdb, 0x81, 0x95, 0xb5, 0xec, 0xe9, 0xf6, 0xa0, 0x40, 0x77,
+	0xd0, 0xb4, 0xa0, 0xef, 0xba, 0x0f, 0x66, 0x68, 0xcf, 0x35, 0xfb, 0xb4, 0x54, 0xed, 0x5a, 0xfa,
+	0xff, 0x43, 0x58, 0xe6, 0x5f, 0xfb, 0xe1, 0x67, 0x00, 0x64, 0x52, 0x60, 0xdf, 0xb2, 0x01, 0x00,
+	0x00,
 }
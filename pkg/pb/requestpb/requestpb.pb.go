@@ -177,30 +177,97 @@ func (m *Batch) GetRequests() []*RequestRef {
 	return nil
 }
 
+// StoredRequest holds all the information a RequestStore stores about a single request.
+type StoredRequest struct {
+	RequestRef           *RequestRef `protobuf:"bytes,1,opt,name=request_ref,json=requestRef,proto3" json:"request_ref,omitempty"`
+	Data                 []byte      `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Authenticated        bool        `protobuf:"varint,3,opt,name=authenticated,proto3" json:"authenticated,omitempty"`
+	Authenticator        []byte      `protobuf:"bytes,4,opt,name=authenticator,proto3" json:"authenticator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *StoredRequest) Reset()         { *m = StoredRequest{} }
+func (m *StoredRequest) String() string { return proto.CompactTextString(m) }
+func (*StoredRequest) ProtoMessage()    {}
+func (*StoredRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_16d6f1a52788cfe8, []int{3}
+}
+
+func (m *StoredRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoredRequest.Unmarshal(m, b)
+}
+func (m *StoredRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoredRequest.Marshal(b, m, deterministic)
+}
+func (m *StoredRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoredRequest.Merge(m, src)
+}
+func (m *StoredRequest) XXX_Size() int {
+	return xxx_messageInfo_StoredRequest.Size(m)
+}
+func (m *StoredRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoredRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoredRequest proto.InternalMessageInfo
+
+func (m *StoredRequest) GetRequestRef() *RequestRef {
+	if m != nil {
+		return m.RequestRef
+	}
+	return nil
+}
+
+func (m *StoredRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *StoredRequest) GetAuthenticated() bool {
+	if m != nil {
+		return m.Authenticated
+	}
+	return false
+}
+
+func (m *StoredRequest) GetAuthenticator() []byte {
+	if m != nil {
+		return m.Authenticator
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Request)(nil), "requestpb.Request")
 	proto.RegisterType((*RequestRef)(nil), "requestpb.RequestRef")
 	proto.RegisterType((*Batch)(nil), "requestpb.Batch")
+	proto.RegisterType((*StoredRequest)(nil), "requestpb.StoredRequest")
 }
 
 func init() { proto.RegisterFile("requestpb/requestpb.proto", fileDescriptor_16d6f1a52788cfe8) }
 
 var fileDescriptor_16d6f1a52788cfe8 = []byte{
-	// 241 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x90, 0xbf, 0x4f, 0x83, 0x40,
-	0x14, 0xc7, 0x83, 0xa5, 0xd8, 0x3e, 0x75, 0xb9, 0xa4, 0xe6, 0x8c, 0x0b, 0x21, 0x0e, 0x2c, 0x72,
-	0xd1, 0xc6, 0xc5, 0xb1, 0x9b, 0x8b, 0xc3, 0x4d, 0xc6, 0xa5, 0xb9, 0xe3, 0x5e, 0xe1, 0x22, 0xe5,
-	0xe0, 0x78, 0xc4, 0xf8, 0xdf, 0x9b, 0x50, 0x02, 0xba, 0x76, 0xfb, 0xfe, 0x78, 0xc9, 0x27, 0xef,
-	0x0b, 0x77, 0x1e, 0xdb, 0x1e, 0x3b, 0x6a, 0xb4, 0x98, 0x54, 0xd6, 0x78, 0x47, 0x8e, 0xad, 0xa7,
-	0x20, 0xf9, 0x86, 0x4b, 0x79, 0x32, 0xec, 0x1e, 0xd6, 0x79, 0x65, 0xb1, 0xa6, 0xbd, 0x35, 0x3c,
-	0x88, 0x83, 0x34, 0x94, 0xab, 0x53, 0xf0, 0x66, 0xd8, 0x06, 0x22, 0x8f, 0xed, 0xbe, 0x76, 0xfc,
-	0x62, 0x68, 0x96, 0x1e, 0xdb, 0x77, 0xc7, 0x18, 0x84, 0x46, 0x91, 0xe2, 0x8b, 0x38, 0x48, 0xaf,
-	0xe5, 0xa0, 0xd9, 0x03, 0xdc, 0xa8, 0x9e, 0x4a, 0xac, 0xc9, 0xe6, 0x8a, 0x9c, 0xe7, 0xe1, 0x50,
-	0xfe, 0x0f, 0x93, 0x0f, 0x80, 0x11, 0x2c, 0xf1, 0x70, 0x16, 0xfb, 0x16, 0x22, 0x63, 0x0b, 0xec,
-	0x68, 0xa4, 0x8f, 0x2e, 0x79, 0x85, 0xe5, 0x4e, 0x51, 0x5e, 0xb2, 0x27, 0x58, 0x8d, 0x8f, 0x76,
-	0x3c, 0x88, 0x17, 0xe9, 0xd5, 0xf3, 0x26, 0x9b, 0xa7, 0x98, 0xe9, 0x72, 0x3a, 0xdb, 0xbd, 0x7c,
-	0x6e, 0x0b, 0x4b, 0x65, 0xaf, 0xb3, 0xdc, 0x1d, 0x45, 0xf9, 0xd3, 0xa0, 0xaf, 0xd0, 0x14, 0xe8,
-	0x1f, 0x2b, 0xa5, 0x3b, 0x71, 0xb4, 0x5e, 0x1f, 0x48, 0x34, 0x5f, 0x85, 0xf8, 0x3b, 0xab, 0x8e,
-	0x86, 0x5d, 0xb7, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x03, 0xbc, 0x67, 0x7f, 0x74, 0x01, 0x00,
-	0x00,
+	// 285 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x3f, 0x4f, 0xf3, 0x30,
+	0x10, 0xc6, 0x95, 0xfe, 0x7b, 0xdb, 0xeb, 0xdb, 0xc5, 0x52, 0x51, 0x10, 0x4b, 0x15, 0x31, 0x64,
+	0x21, 0x11, 0x54, 0x30, 0x30, 0x76, 0x63, 0x61, 0x30, 0x0b, 0x62, 0x89, 0x9c, 0xf8, 0x92, 0x58,
+	0xa4, 0x71, 0xe2, 0x5c, 0x84, 0xf8, 0x44, 0x7c, 0x4d, 0x24, 0x37, 0x4d, 0xe8, 0x50, 0x09, 0xb1,
+	0xdd, 0x3d, 0x8f, 0xe5, 0xe7, 0xee, 0xa7, 0x83, 0x4b, 0x83, 0x75, 0x8b, 0x0d, 0x55, 0x71, 0xd8,
+	0x57, 0x41, 0x65, 0x34, 0x69, 0xb6, 0xe8, 0x05, 0xef, 0x03, 0xfe, 0xf1, 0x43, 0xc3, 0xae, 0x60,
+	0x91, 0x14, 0x0a, 0x4b, 0x8a, 0x94, 0x74, 0x9d, 0x8d, 0xe3, 0x4f, 0xf8, 0xfc, 0x20, 0x3c, 0x49,
+	0xb6, 0x86, 0x99, 0xc1, 0x3a, 0x2a, 0xb5, 0x3b, 0xb2, 0xce, 0xd4, 0x60, 0xfd, 0xac, 0x19, 0x83,
+	0x89, 0x14, 0x24, 0xdc, 0xf1, 0xc6, 0xf1, 0xff, 0x73, 0x5b, 0xb3, 0x6b, 0x58, 0x89, 0x96, 0x72,
+	0x2c, 0x49, 0x25, 0x82, 0xb4, 0x71, 0x27, 0xd6, 0x3c, 0x15, 0xbd, 0x57, 0x80, 0x2e, 0x98, 0x63,
+	0xfa, 0xa7, 0xec, 0x0b, 0x98, 0x49, 0x95, 0x61, 0x43, 0x5d, 0x7a, 0xd7, 0x79, 0x8f, 0x30, 0xdd,
+	0x09, 0x4a, 0x72, 0x76, 0x0b, 0xf3, 0x6e, 0xd1, 0xc6, 0x75, 0x36, 0x63, 0x7f, 0x79, 0xb7, 0x0e,
+	0x06, 0x14, 0x43, 0x3a, 0xef, 0x9f, 0x79, 0x5f, 0x0e, 0xac, 0x5e, 0x48, 0x1b, 0x94, 0x47, 0x2a,
+	0x0f, 0xb0, 0xec, 0xdc, 0xc8, 0x60, 0x6a, 0x67, 0x3b, 0xfb, 0x0f, 0x98, 0x61, 0xa3, 0x23, 0x99,
+	0xd1, 0x59, 0x32, 0x28, 0xed, 0xe0, 0x73, 0x7e, 0x2a, 0xfe, 0x8e, 0xdf, 0xee, 0xfe, 0x6d, 0x9b,
+	0x29, 0xca, 0xdb, 0x38, 0x48, 0xf4, 0x3e, 0xcc, 0x3f, 0x2b, 0x34, 0x05, 0xca, 0x0c, 0xcd, 0x4d,
+	0x21, 0xe2, 0x26, 0xdc, 0x2b, 0x13, 0xa7, 0x14, 0x56, 0xef, 0x59, 0xf8, 0xf3, 0x00, 0xe2, 0x99,
+	0xbd, 0x80, 0xed, 0xf7, 0x00, 0xae, 0xa6, 0x52, 0x8c, 0x1e, 0x02, 0x00, 0x00,
 }
@@ -6,6 +6,7 @@ package statuspb
 import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	eventpb "github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
 	isspb "github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
 	math "math"
 )
@@ -24,6 +25,10 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type NodeStatus struct {
 	Protocol             *ProtocolStatus      `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
 	ClientTracker        *ClientTrackerStatus `protobuf:"bytes,2,opt,name=client_tracker,json=clientTracker,proto3" json:"client_tracker,omitempty"`
+	WorkItems            *WorkItemsStatus     `protobuf:"bytes,3,opt,name=work_items,json=workItems,proto3" json:"work_items,omitempty"`
+	Oddities             []*NodeOddities      `protobuf:"bytes,4,rep,name=oddities,proto3" json:"oddities,omitempty"`
+	PeerTraffic          []*PeerTraffic       `protobuf:"bytes,5,rep,name=peer_traffic,json=peerTraffic,proto3" json:"peer_traffic,omitempty"`
+	PersistLatencies     []*PersistLatency    `protobuf:"bytes,6,rep,name=persist_latencies,json=persistLatencies,proto3" json:"persist_latencies,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -68,6 +73,373 @@ func (m *NodeStatus) GetClientTracker() *ClientTrackerStatus {
 	return nil
 }
 
+func (m *NodeStatus) GetWorkItems() *WorkItemsStatus {
+	if m != nil {
+		return m.WorkItems
+	}
+	return nil
+}
+
+func (m *NodeStatus) GetOddities() []*NodeOddities {
+	if m != nil {
+		return m.Oddities
+	}
+	return nil
+}
+
+func (m *NodeStatus) GetPeerTraffic() []*PeerTraffic {
+	if m != nil {
+		return m.PeerTraffic
+	}
+	return nil
+}
+
+func (m *NodeStatus) GetPersistLatencies() []*PersistLatency {
+	if m != nil {
+		return m.PersistLatencies
+	}
+	return nil
+}
+
+// PersistLatency describes the latencies of a persistence operation (e.g. syncing the WAL, see mirbft.PersistWALSync).
+// The percentiles and the maximum are computed over the most recent invocations of the operation,
+// while the count includes all invocations since the start of the node.
+type PersistLatency struct {
+	Operation            string   `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Count                uint64   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	P50Us                uint64   `protobuf:"varint,3,opt,name=p50_us,json=p50Us,proto3" json:"p50_us,omitempty"`
+	P90Us                uint64   `protobuf:"varint,4,opt,name=p90_us,json=p90Us,proto3" json:"p90_us,omitempty"`
+	P99Us                uint64   `protobuf:"varint,5,opt,name=p99_us,json=p99Us,proto3" json:"p99_us,omitempty"`
+	MaxUs                uint64   `protobuf:"varint,6,opt,name=max_us,json=maxUs,proto3" json:"max_us,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PersistLatency) Reset()         { *m = PersistLatency{} }
+func (m *PersistLatency) String() string { return proto.CompactTextString(m) }
+func (*PersistLatency) ProtoMessage()    {}
+func (*PersistLatency) Descriptor() ([]byte, []int) {
+	return fileDescriptor_64cf36d54cdce33a, []int{1}
+}
+
+func (m *PersistLatency) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PersistLatency.Unmarshal(m, b)
+}
+func (m *PersistLatency) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PersistLatency.Marshal(b, m, deterministic)
+}
+func (m *PersistLatency) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PersistLatency.Merge(m, src)
+}
+func (m *PersistLatency) XXX_Size() int {
+	return xxx_messageInfo_PersistLatency.Size(m)
+}
+func (m *PersistLatency) XXX_DiscardUnknown() {
+	xxx_messageInfo_PersistLatency.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PersistLatency proto.InternalMessageInfo
+
+func (m *PersistLatency) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *PersistLatency) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *PersistLatency) GetP50Us() uint64 {
+	if m != nil {
+		return m.P50Us
+	}
+	return 0
+}
+
+func (m *PersistLatency) GetP90Us() uint64 {
+	if m != nil {
+		return m.P90Us
+	}
+	return 0
+}
+
+func (m *PersistLatency) GetP99Us() uint64 {
+	if m != nil {
+		return m.P99Us
+	}
+	return 0
+}
+
+func (m *PersistLatency) GetMaxUs() uint64 {
+	if m != nil {
+		return m.MaxUs
+	}
+	return 0
+}
+
+// PeerTraffic contains the numbers of messages (and their sizes in bytes) exchanged with another node
+// since the start of the node, by message type (see messagepb.Message.TypeName).
+// Messages are counted as passed to and received from the Net module, e.g., a bundle of messages counts as one.
+type PeerTraffic struct {
+	NodeId               uint64                   `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Sent                 map[string]*MessageCount `protobuf:"bytes,2,rep,name=sent,proto3" json:"sent,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Received             map[string]*MessageCount `protobuf:"bytes,3,rep,name=received,proto3" json:"received,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *PeerTraffic) Reset()         { *m = PeerTraffic{} }
+func (m *PeerTraffic) String() string { return proto.CompactTextString(m) }
+func (*PeerTraffic) ProtoMessage()    {}
+func (*PeerTraffic) Descriptor() ([]byte, []int) {
+	return fileDescriptor_64cf36d54cdce33a, []int{2}
+}
+
+func (m *PeerTraffic) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerTraffic.Unmarshal(m, b)
+}
+func (m *PeerTraffic) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerTraffic.Marshal(b, m, deterministic)
+}
+func (m *PeerTraffic) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerTraffic.Merge(m, src)
+}
+func (m *PeerTraffic) XXX_Size() int {
+	return xxx_messageInfo_PeerTraffic.Size(m)
+}
+func (m *PeerTraffic) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerTraffic.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerTraffic proto.InternalMessageInfo
+
+func (m *PeerTraffic) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *PeerTraffic) GetSent() map[string]*MessageCount {
+	if m != nil {
+		return m.Sent
+	}
+	return nil
+}
+
+func (m *PeerTraffic) GetReceived() map[string]*MessageCount {
+	if m != nil {
+		return m.Received
+	}
+	return nil
+}
+
+type MessageCount struct {
+	Messages             uint64   `protobuf:"varint,1,opt,name=messages,proto3" json:"messages,omitempty"`
+	Bytes                uint64   `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageCount) Reset()         { *m = MessageCount{} }
+func (m *MessageCount) String() string { return proto.CompactTextString(m) }
+func (*MessageCount) ProtoMessage()    {}
+func (*MessageCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_64cf36d54cdce33a, []int{3}
+}
+
+func (m *MessageCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageCount.Unmarshal(m, b)
+}
+func (m *MessageCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageCount.Marshal(b, m, deterministic)
+}
+func (m *MessageCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageCount.Merge(m, src)
+}
+func (m *MessageCount) XXX_Size() int {
+	return xxx_messageInfo_MessageCount.Size(m)
+}
+func (m *MessageCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageCount proto.InternalMessageInfo
+
+func (m *MessageCount) GetMessages() uint64 {
+	if m != nil {
+		return m.Messages
+	}
+	return 0
+}
+
+func (m *MessageCount) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+// NodeOddities summarizes the suspicious behavior of another node observed by this node.
+type NodeOddities struct {
+	NodeId               uint64            `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Counts               map[string]uint64 `protobuf:"bytes,2,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Recent               []*eventpb.Oddity `protobuf:"bytes,3,rep,name=recent,proto3" json:"recent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *NodeOddities) Reset()         { *m = NodeOddities{} }
+func (m *NodeOddities) String() string { return proto.CompactTextString(m) }
+func (*NodeOddities) ProtoMessage()    {}
+func (*NodeOddities) Descriptor() ([]byte, []int) {
+	return fileDescriptor_64cf36d54cdce33a, []int{4}
+}
+
+func (m *NodeOddities) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeOddities.Unmarshal(m, b)
+}
+func (m *NodeOddities) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeOddities.Marshal(b, m, deterministic)
+}
+func (m *NodeOddities) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeOddities.Merge(m, src)
+}
+func (m *NodeOddities) XXX_Size() int {
+	return xxx_messageInfo_NodeOddities.Size(m)
+}
+func (m *NodeOddities) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeOddities.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeOddities proto.InternalMessageInfo
+
+func (m *NodeOddities) GetNodeId() uint64 {
+	if m != nil {
+		return m.NodeId
+	}
+	return 0
+}
+
+func (m *NodeOddities) GetCounts() map[string]uint64 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+func (m *NodeOddities) GetRecent() []*eventpb.Oddity {
+	if m != nil {
+		return m.Recent
+	}
+	return nil
+}
+
+// WorkItemsStatus contains the numbers of events pending in the Node's internal buffers, one for each module.
+type WorkItemsStatus struct {
+	Wal                  uint64   `protobuf:"varint,1,opt,name=wal,proto3" json:"wal,omitempty"`
+	Net                  uint64   `protobuf:"varint,2,opt,name=net,proto3" json:"net,omitempty"`
+	Hash                 uint64   `protobuf:"varint,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	Client               uint64   `protobuf:"varint,4,opt,name=client,proto3" json:"client,omitempty"`
+	App                  uint64   `protobuf:"varint,5,opt,name=app,proto3" json:"app,omitempty"`
+	ReqStore             uint64   `protobuf:"varint,6,opt,name=req_store,json=reqStore,proto3" json:"req_store,omitempty"`
+	Protocol             uint64   `protobuf:"varint,7,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Crypto               uint64   `protobuf:"varint,8,opt,name=crypto,proto3" json:"crypto,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkItemsStatus) Reset()         { *m = WorkItemsStatus{} }
+func (m *WorkItemsStatus) String() string { return proto.CompactTextString(m) }
+func (*WorkItemsStatus) ProtoMessage()    {}
+func (*WorkItemsStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_64cf36d54cdce33a, []int{5}
+}
+
+func (m *WorkItemsStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkItemsStatus.Unmarshal(m, b)
+}
+func (m *WorkItemsStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkItemsStatus.Marshal(b, m, deterministic)
+}
+func (m *WorkItemsStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkItemsStatus.Merge(m, src)
+}
+func (m *WorkItemsStatus) XXX_Size() int {
+	return xxx_messageInfo_WorkItemsStatus.Size(m)
+}
+func (m *WorkItemsStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkItemsStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkItemsStatus proto.InternalMessageInfo
+
+func (m *WorkItemsStatus) GetWal() uint64 {
+	if m != nil {
+		return m.Wal
+	}
+	return 0
+}
+
+func (m *WorkItemsStatus) GetNet() uint64 {
+	if m != nil {
+		return m.Net
+	}
+	return 0
+}
+
+func (m *WorkItemsStatus) GetHash() uint64 {
+	if m != nil {
+		return m.Hash
+	}
+	return 0
+}
+
+func (m *WorkItemsStatus) GetClient() uint64 {
+	if m != nil {
+		return m.Client
+	}
+	return 0
+}
+
+func (m *WorkItemsStatus) GetApp() uint64 {
+	if m != nil {
+		return m.App
+	}
+	return 0
+}
+
+func (m *WorkItemsStatus) GetReqStore() uint64 {
+	if m != nil {
+		return m.ReqStore
+	}
+	return 0
+}
+
+func (m *WorkItemsStatus) GetProtocol() uint64 {
+	if m != nil {
+		return m.Protocol
+	}
+	return 0
+}
+
+func (m *WorkItemsStatus) GetCrypto() uint64 {
+	if m != nil {
+		return m.Crypto
+	}
+	return 0
+}
+
 type ProtocolStatus struct {
 	// Types that are valid to be assigned to Type:
 	//	*ProtocolStatus_Iss
@@ -81,7 +453,7 @@ func (m *ProtocolStatus) Reset()         { *m = ProtocolStatus{} }
 func (m *ProtocolStatus) String() string { return proto.CompactTextString(m) }
 func (*ProtocolStatus) ProtoMessage()    {}
 func (*ProtocolStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_64cf36d54cdce33a, []int{1}
+	return fileDescriptor_64cf36d54cdce33a, []int{6}
 }
 
 func (m *ProtocolStatus) XXX_Unmarshal(b []byte) error {
@@ -143,7 +515,7 @@ func (m *ClientTrackerStatus) Reset()         { *m = ClientTrackerStatus{} }
 func (m *ClientTrackerStatus) String() string { return proto.CompactTextString(m) }
 func (*ClientTrackerStatus) ProtoMessage()    {}
 func (*ClientTrackerStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_64cf36d54cdce33a, []int{2}
+	return fileDescriptor_64cf36d54cdce33a, []int{7}
 }
 
 func (m *ClientTrackerStatus) XXX_Unmarshal(b []byte) error {
@@ -166,6 +538,14 @@ var xxx_messageInfo_ClientTrackerStatus proto.InternalMessageInfo
 
 func init() {
 	proto.RegisterType((*NodeStatus)(nil), "statuspb.NodeStatus")
+	proto.RegisterType((*PersistLatency)(nil), "statuspb.PersistLatency")
+	proto.RegisterType((*PeerTraffic)(nil), "statuspb.PeerTraffic")
+	proto.RegisterMapType((map[string]*MessageCount)(nil), "statuspb.PeerTraffic.ReceivedEntry")
+	proto.RegisterMapType((map[string]*MessageCount)(nil), "statuspb.PeerTraffic.SentEntry")
+	proto.RegisterType((*MessageCount)(nil), "statuspb.MessageCount")
+	proto.RegisterType((*NodeOddities)(nil), "statuspb.NodeOddities")
+	proto.RegisterMapType((map[string]uint64)(nil), "statuspb.NodeOddities.CountsEntry")
+	proto.RegisterType((*WorkItemsStatus)(nil), "statuspb.WorkItemsStatus")
 	proto.RegisterType((*ProtocolStatus)(nil), "statuspb.ProtocolStatus")
 	proto.RegisterType((*ClientTrackerStatus)(nil), "statuspb.ClientTrackerStatus")
 }
@@ -173,19 +553,51 @@ func init() {
 func init() { proto.RegisterFile("statuspb/statuspb.proto", fileDescriptor_64cf36d54cdce33a) }
 
 var fileDescriptor_64cf36d54cdce33a = []byte{
-	// 224 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2f, 0x2e, 0x49, 0x2c,
-	0x29, 0x2d, 0x2e, 0x48, 0xd2, 0x87, 0x31, 0xf4, 0x0a, 0x8a, 0xf2, 0x4b, 0xf2, 0x85, 0x38, 0x60,
-	0x7c, 0x29, 0xc1, 0xcc, 0x62, 0x90, 0x3c, 0x98, 0x84, 0x48, 0x2a, 0x75, 0x30, 0x72, 0x71, 0xf9,
-	0xe5, 0xa7, 0xa4, 0x06, 0x83, 0xd5, 0x08, 0x99, 0x70, 0x71, 0x80, 0xc5, 0x93, 0xf3, 0x73, 0x24,
-	0x18, 0x15, 0x18, 0x35, 0xb8, 0x8d, 0x24, 0xf4, 0xe0, 0xc6, 0x05, 0x40, 0x65, 0x20, 0x6a, 0x83,
-	0xe0, 0x2a, 0x85, 0x5c, 0xb8, 0xf8, 0x92, 0x73, 0x32, 0x53, 0xf3, 0x4a, 0xe2, 0x4b, 0x8a, 0x12,
-	0x93, 0xb3, 0x53, 0x8b, 0x24, 0x98, 0xc0, 0x7a, 0x65, 0x11, 0x7a, 0x9d, 0xc1, 0xf2, 0x21, 0x10,
-	0x69, 0xa8, 0x01, 0xbc, 0xc9, 0xc8, 0x82, 0x4a, 0xd6, 0x5c, 0x7c, 0xa8, 0x36, 0x08, 0x29, 0x72,
-	0x31, 0x67, 0x16, 0x17, 0x43, 0x1d, 0xc2, 0xab, 0x07, 0x71, 0x37, 0x44, 0xce, 0x83, 0x21, 0x08,
-	0x24, 0xe7, 0xc4, 0xc6, 0xc5, 0x52, 0x52, 0x59, 0x90, 0xaa, 0x24, 0xca, 0x25, 0x8c, 0xc5, 0x0a,
-	0x27, 0x93, 0x28, 0xa3, 0xf4, 0xcc, 0x92, 0x8c, 0xd2, 0x24, 0xbd, 0xe4, 0xfc, 0x5c, 0xfd, 0x8c,
-	0xca, 0x82, 0xd4, 0xa2, 0x9c, 0xd4, 0x94, 0xf4, 0xd4, 0x22, 0xdd, 0x9c, 0xc4, 0xa4, 0x62, 0xfd,
-	0xdc, 0xcc, 0xa2, 0xa4, 0xb4, 0x12, 0xfd, 0x82, 0xec, 0x74, 0x7d, 0xa4, 0x70, 0x4b, 0x62, 0x03,
-	0xfb, 0xcc, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0x1c, 0x86, 0x7d, 0x02, 0x53, 0x01, 0x00, 0x00,
+	// 724 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x4d, 0x6f, 0xdb, 0x46,
+	0x10, 0xad, 0x24, 0x8a, 0x96, 0x46, 0xfe, 0xdc, 0x5a, 0x36, 0xab, 0xb6, 0xa8, 0xcb, 0x1e, 0xea,
+	0x43, 0x2b, 0x19, 0xb2, 0x0d, 0x58, 0xee, 0xa1, 0x81, 0x1d, 0x03, 0x31, 0x90, 0xc4, 0x01, 0x65,
+	0x23, 0x40, 0x2e, 0x02, 0x45, 0x8e, 0x25, 0x42, 0x12, 0xb9, 0xde, 0x5d, 0xd9, 0xe6, 0xbf, 0xc9,
+	0x6f, 0x09, 0x02, 0xe4, 0x27, 0xe5, 0x1a, 0xec, 0x07, 0x29, 0xda, 0x50, 0x6e, 0xb9, 0x48, 0xfb,
+	0xde, 0xcc, 0xdb, 0x8f, 0x37, 0xc3, 0x81, 0x5d, 0x2e, 0x7c, 0x31, 0xe7, 0x74, 0xd8, 0xc9, 0x16,
+	0x6d, 0xca, 0x12, 0x91, 0x90, 0x5a, 0x86, 0x5b, 0x4d, 0xbc, 0xc7, 0x58, 0xd0, 0x61, 0xc7, 0xfc,
+	0xeb, 0x84, 0xd6, 0x56, 0xc4, 0xa5, 0x4c, 0xfd, 0x6a, 0xca, 0xfd, 0x5a, 0x06, 0x78, 0x9b, 0x84,
+	0xd8, 0x57, 0x52, 0x72, 0x04, 0x35, 0xc5, 0x07, 0xc9, 0xd4, 0x29, 0xed, 0x95, 0xf6, 0x1b, 0x5d,
+	0xa7, 0x9d, 0x9f, 0xf2, 0xce, 0x44, 0x74, 0xae, 0x97, 0x67, 0x92, 0x97, 0xb0, 0x1e, 0x4c, 0x23,
+	0x8c, 0xc5, 0x40, 0x30, 0x3f, 0x98, 0x20, 0x73, 0xca, 0x4a, 0xfb, 0xfb, 0x42, 0x7b, 0xae, 0xe2,
+	0xd7, 0x3a, 0x6c, 0x36, 0x58, 0x0b, 0x8a, 0x24, 0x39, 0x01, 0x78, 0x48, 0xd8, 0x64, 0x10, 0x09,
+	0x9c, 0x71, 0xa7, 0xa2, 0x76, 0xf8, 0x65, 0xb1, 0xc3, 0xfb, 0x84, 0x4d, 0x2e, 0x65, 0xc8, 0xa8,
+	0xeb, 0x0f, 0x19, 0x41, 0xba, 0x50, 0x4b, 0xc2, 0x30, 0x12, 0x11, 0x72, 0xc7, 0xda, 0xab, 0xec,
+	0x37, 0xba, 0x3b, 0x0b, 0x9d, 0x7c, 0xdd, 0x95, 0x89, 0x7a, 0x79, 0x1e, 0x39, 0x81, 0x55, 0x8a,
+	0xc8, 0xe4, 0x8d, 0x6f, 0x6f, 0xa3, 0xc0, 0xa9, 0x2a, 0x5d, 0xb3, 0xf0, 0x5a, 0x44, 0x76, 0xad,
+	0x83, 0x5e, 0x83, 0x2e, 0x00, 0xb9, 0x80, 0x2d, 0x8a, 0x8c, 0x47, 0x5c, 0x0c, 0xa6, 0xbe, 0xc0,
+	0x38, 0x90, 0xc7, 0xda, 0x7b, 0x95, 0x67, 0x66, 0xe9, 0x94, 0xd7, 0x2a, 0x23, 0xf5, 0x36, 0x69,
+	0x11, 0x47, 0xc8, 0xdd, 0x8f, 0x25, 0x58, 0x7f, 0x9a, 0x44, 0x7e, 0x83, 0x7a, 0x42, 0x91, 0xf9,
+	0x22, 0x4a, 0x62, 0x65, 0x7f, 0xdd, 0x5b, 0x10, 0x64, 0x1b, 0xaa, 0x41, 0x32, 0x8f, 0x85, 0x32,
+	0xd7, 0xf2, 0x34, 0x20, 0x4d, 0xb0, 0xe9, 0xf1, 0xc1, 0x60, 0xae, 0x1d, 0xb3, 0xbc, 0x2a, 0x3d,
+	0x3e, 0xb8, 0xe1, 0x8a, 0xee, 0x29, 0xda, 0x32, 0x74, 0x2f, 0xa7, 0x7b, 0x92, 0xae, 0x66, 0x74,
+	0x4f, 0xd3, 0x33, 0xff, 0x51, 0xd2, 0xb6, 0xa6, 0x67, 0xfe, 0xe3, 0x0d, 0x77, 0x3f, 0x95, 0xa1,
+	0x51, 0xb0, 0x81, 0xec, 0xc2, 0x4a, 0x9c, 0x84, 0x38, 0x88, 0x42, 0x75, 0x3b, 0xcb, 0xb3, 0x25,
+	0xbc, 0x0c, 0xc9, 0x21, 0x58, 0x1c, 0xd5, 0xcd, 0xa4, 0x0b, 0x7f, 0x2c, 0x35, 0xb1, 0xdd, 0xc7,
+	0x58, 0x5c, 0xc4, 0x82, 0xa5, 0x9e, 0x4a, 0x26, 0xff, 0x43, 0x8d, 0x61, 0x80, 0xd1, 0x3d, 0x86,
+	0x4e, 0x45, 0x09, 0xff, 0x5a, 0x2e, 0xf4, 0x4c, 0x96, 0x16, 0xe7, 0xa2, 0xd6, 0x15, 0xd4, 0xf3,
+	0x3d, 0xc9, 0x26, 0x54, 0x26, 0x98, 0x1a, 0xd7, 0xe4, 0x92, 0xfc, 0x03, 0xd5, 0x7b, 0x7f, 0x3a,
+	0x47, 0xd3, 0x8c, 0x85, 0x96, 0x78, 0x83, 0x9c, 0xfb, 0x23, 0x3c, 0x97, 0x06, 0x7a, 0x3a, 0xe9,
+	0xb4, 0x7c, 0x52, 0x6a, 0xf5, 0x61, 0xed, 0xc9, 0x59, 0x3f, 0x62, 0x53, 0xf7, 0x05, 0xac, 0x16,
+	0x43, 0xa4, 0x05, 0xb5, 0x99, 0xc6, 0xdc, 0xb8, 0x98, 0x63, 0x59, 0xe2, 0x61, 0x2a, 0x90, 0x67,
+	0x25, 0x56, 0xc0, 0xfd, 0x52, 0x82, 0xd5, 0x62, 0x17, 0x7f, 0xbf, 0x0e, 0xa7, 0x60, 0xab, 0xae,
+	0xe0, 0xa6, 0x12, 0xee, 0xf2, 0xcf, 0xa0, 0xad, 0x6e, 0xc2, 0xb5, 0x9f, 0x46, 0x41, 0xfe, 0x06,
+	0x5b, 0x3a, 0x1b, 0x0b, 0x53, 0x8c, 0x8d, 0x76, 0x36, 0x3c, 0x94, 0x2c, 0xf5, 0x4c, 0xb8, 0xd5,
+	0x83, 0x46, 0x41, 0xbf, 0xc4, 0xa3, 0xed, 0xa2, 0x47, 0x56, 0xd1, 0x8b, 0xcf, 0x25, 0xd8, 0x78,
+	0xf6, 0x1d, 0x4b, 0xfd, 0x83, 0x3f, 0x35, 0x0f, 0x91, 0x4b, 0xc9, 0xc4, 0x98, 0xb5, 0xb9, 0x5c,
+	0x12, 0x02, 0xd6, 0xd8, 0xe7, 0x63, 0xd3, 0xe2, 0x6a, 0x4d, 0x76, 0xc0, 0xd6, 0xf3, 0xc3, 0x74,
+	0xb8, 0x41, 0x52, 0xed, 0x53, 0x6a, 0xfa, 0x5b, 0x2e, 0xc9, 0xaf, 0x50, 0x67, 0x78, 0x37, 0xe0,
+	0x22, 0x61, 0x68, 0x1a, 0xbc, 0xc6, 0xf0, 0xae, 0x2f, 0xb1, 0x2c, 0x47, 0x3e, 0xf1, 0x56, 0x74,
+	0x2c, 0xc3, 0xea, 0x08, 0x96, 0x52, 0x91, 0x38, 0x35, 0x73, 0x84, 0x42, 0xee, 0x7f, 0xb0, 0xfe,
+	0x74, 0x16, 0x92, 0x3f, 0xa1, 0x12, 0x71, 0x6e, 0x46, 0xe6, 0x5a, 0x5b, 0x4f, 0x58, 0x1d, 0x7b,
+	0xf5, 0x93, 0x27, 0x63, 0x67, 0x36, 0x58, 0x22, 0xa5, 0xe8, 0x36, 0xe1, 0xe7, 0x25, 0xc3, 0xf0,
+	0xec, 0xe8, 0x43, 0x77, 0x14, 0x89, 0xf1, 0x7c, 0xd8, 0x0e, 0x92, 0x59, 0x67, 0x9c, 0x52, 0x64,
+	0x53, 0x0c, 0x47, 0xc8, 0xfe, 0x9d, 0xfa, 0x43, 0xde, 0x99, 0x45, 0x6c, 0x78, 0x2b, 0x3a, 0x74,
+	0x32, 0xea, 0x14, 0x06, 0xff, 0xd0, 0x56, 0x77, 0x3d, 0xfc, 0x36, 0x00, 0x57, 0x88, 0x98, 0x52,
+	0x14, 0x06, 0x00, 0x00,
 }
@@ -0,0 +1,751 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package quictransport implements a networking module that is based on QUIC,
+// a variant of the gRPC-based transport (see package grpctransport) intended for WAN deployments.
+//
+// Each node sends messages to another node over a QUIC connection it establishes to that node,
+// using two separate unidirectional streams: one for protocol-critical messages (e.g. ordering and checkpoint
+// messages) and one for best-effort messages (e.g. forwarded requests, see messagepb.Message.BestEffort).
+// Unlike with TCP, a packet lost on one stream does not delay the data of the other stream,
+// such that bulk payload traffic does not block the ordering protocol (head-of-line blocking).
+// The order of the messages is preserved among the critical messages and among the best-effort ones.
+//
+// When reconnecting to a node it was connected to before, the QuicTransport resumes the previous TLS session
+// and sends its first messages without waiting for the handshake to complete (0-RTT).
+// Note that an attacker can replay the messages sent this way (but not forge or modify them),
+// which is tolerated, as the protocol copes with duplicated and delayed messages anyway.
+//
+// Since this package requires a more recent Go version than the rest of the library,
+// it is a separate Go module.
+package quictransport
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/quic-go/quic-go"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/grpctransport"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+const (
+	// Application protocol negotiated (using ALPN) by the QuicTransports of the nodes.
+	alpnProtocol = "mirbft"
+
+	// Maximum size of a serialized message.
+	maxMessageSize = 1073741824
+
+	// Maximal time to wait for a connection to another node to be established when calling Connect().
+	connectTimeout = 10 * time.Second
+
+	// Maximal time to wait for a connection to be re-established when sending a message to a node.
+	// Since sending blocks until the connection is re-established, this timeout is kept short.
+	reconnectTimeout = time.Second
+
+	// Minimal and maximal time between two attempts to re-establish a connection to a node.
+	// After each failed attempt, the time to the next one doubles (up to maxReconnectBackoff).
+	// Messages sent to the node before the next attempt fail immediately.
+	minReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff = 10 * time.Second
+
+	// Period of keep-alive packets sent over idle connections, preventing them from timing out.
+	keepAlivePeriod = 10 * time.Second
+)
+
+// QuicTransport represents a networking module that is based on QUIC.
+// Each node's networking module listens for QUIC connections, to which other nodes' modules connect.
+// Each module establishes one connection to each other node and only uses it for sending messages to that node.
+// It is used the same way as a grpctransport.GrpcTransport.
+type QuicTransport struct {
+
+	// The numeric ID of the node that uses this networking module.
+	ownId t.NodeID
+
+	// Maps the numeric node ID of each node in the system to a string representation of its network address.
+	// The address format "IPAddress:port"
+	// Initialized with the membership of the system, but can be updated at runtime (see AddressBook).
+	addressBook *grpctransport.AddressBook
+
+	// Channel to which all incoming messages are written.
+	// This channel is also returned by the ReceiveChan() method.
+	incomingMessages chan modules.ReceivedMessage
+
+	// For each node ID, stores the connection to that node.
+	// If there is no entry for a node, no connection to the node is established.
+	// Sending a message to such a node attempts to (re-)establish the connection first.
+	connections map[t.NodeID]*connection
+
+	// For each node ID that is not connected, the reconnection backoff state.
+	reconnects map[t.NodeID]*reconnectState
+
+	// Source of time for the reconnection backoff (see SetClock).
+	clock clock.Clock
+
+	// Mutual TLS configuration. If nil, connections are encrypted, but not authenticated.
+	tlsConfig *grpctransport.TLSConfig
+
+	// TLS configuration of the QUIC listener.
+	// Without mutual TLS, it contains an ephemeral self-signed certificate the connecting nodes do not verify.
+	serverTLS *tls.Config
+
+	// Stores the TLS sessions established with the other nodes, to be resumed (with 0-RTT) when reconnecting.
+	sessionCache tls.ClientSessionCache
+
+	// The QUIC transport owning the UDP socket the QuicTransport listens on.
+	transport *quic.Transport
+
+	// The QUIC listener accepting the connections of the other nodes.
+	listener *quic.EarlyListener
+
+	// Canceled when the QuicTransport is stopped, closing the incoming connections.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Tracks the goroutines accepting connections and receiving messages.
+	wg sync.WaitGroup
+
+	// Logger use for all logging events of this QuicTransport
+	logger logging.Logger
+}
+
+// NewQuicTransport returns a pointer to a new initialized QuicTransport networking module
+// that encrypts all communication, but does not authenticate the other nodes.
+// The parameters have the same meaning as with grpctransport.NewGrpcTransport.
+// The returned QuicTransport is not yet running (able to receive messages),
+// nor is it connected to any nodes (able to send messages).
+// This needs to be done explicitly by calling the respective Start() and Connect() methods.
+func NewQuicTransport(membership map[t.NodeID]string, ownId t.NodeID, l logging.Logger) (*QuicTransport, error) {
+
+	// QUIC connections are always encrypted. The server presents a certificate nobody verifies.
+	cert, err := ephemeralCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate certificate: %w", err)
+	}
+
+	return newQuicTransport(membership, ownId, nil, &tls.Config{Certificates: []tls.Certificate{cert}}, l), nil
+}
+
+// NewTLSQuicTransport returns a pointer to a new initialized QuicTransport networking module
+// that authenticates the other nodes using mutual TLS, the same way as grpctransport.NewTLSGrpcTransport.
+// The tlsConfig must contain exactly one certificate for each node in the membership (including the own node),
+// otherwise NewTLSQuicTransport returns an error.
+func NewTLSQuicTransport(
+	membership map[t.NodeID]string,
+	ownId t.NodeID,
+	tlsConfig *grpctransport.TLSConfig,
+	l logging.Logger,
+) (*QuicTransport, error) {
+
+	// Check that the certificates correspond to the membership.
+	for id := range membership {
+		if _, ok := tlsConfig.NodeCertificates[id]; !ok {
+			return nil, fmt.Errorf("no certificate for node %d", id)
+		}
+	}
+	for id := range tlsConfig.NodeCertificates {
+		if _, ok := membership[id]; !ok {
+			return nil, fmt.Errorf("certificate for node %d not in membership", id)
+		}
+	}
+
+	return newQuicTransport(membership, ownId, tlsConfig, tlsConfig.ServerConfig(), l), nil
+}
+
+// newQuicTransport returns a new QuicTransport listening with the given server TLS configuration.
+func newQuicTransport(
+	membership map[t.NodeID]string,
+	ownId t.NodeID,
+	tlsConfig *grpctransport.TLSConfig,
+	serverTLS *tls.Config,
+	l logging.Logger,
+) *QuicTransport {
+
+	// If no logger was given, only write errors to the console.
+	if l == nil {
+		l = logging.ConsoleErrorLogger
+	}
+
+	serverTLS.NextProtos = []string{alpnProtocol}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &QuicTransport{
+		ownId:            ownId,
+		incomingMessages: make(chan modules.ReceivedMessage),
+		addressBook:      grpctransport.NewAddressBook(membership),
+		connections:      make(map[t.NodeID]*connection),
+		reconnects:       make(map[t.NodeID]*reconnectState),
+		clock:            clock.System,
+		tlsConfig:        tlsConfig,
+		serverTLS:        serverTLS,
+		sessionCache:     tls.NewLRUClientSessionCache(0),
+		ctx:              ctx,
+		cancel:           cancel,
+		logger:           l,
+	}
+}
+
+// quicConfig returns the QUIC configuration used for all connections.
+func quicConfig() *quic.Config {
+	return &quic.Config{
+		KeepAlivePeriod: keepAlivePeriod,
+		Allow0RTT:       true,
+	}
+}
+
+// A connection to another node.
+type connection struct {
+
+	// The underlying QUIC connection.
+	conn *quic.Conn
+
+	// The address of the node to which the connection was established.
+	addr string
+
+	// The ID of the node sending over the connection, written at the start of each stream.
+	ownId t.NodeID
+
+	// The streams over which critical and best-effort messages are sent, respectively.
+	critical   *quic.SendStream
+	bestEffort *quic.SendStream
+
+	// The messages sent with 0-RTT, before the handshake completed.
+	// If the node rejects 0-RTT, they are re-sent after the handshake (see awaitHandshake).
+	// Nil once the handshake completed.
+	early []*messagepb.Message
+
+	// Protects the fields above, as they are modified when the handshake completes.
+	lock sync.Mutex
+}
+
+// openStreams opens the streams of the connection, each starting with the ID of the sending node.
+func (c *connection) openStreams() error {
+	var err error
+	if c.critical, err = openStream(c.conn, c.ownId); err != nil {
+		return err
+	}
+	c.bestEffort, err = openStream(c.conn, c.ownId)
+	return err
+}
+
+// openStream opens a unidirectional stream over conn and writes the ID of the sending node to it.
+func openStream(conn *quic.Conn, ownId t.NodeID) (*quic.SendStream, error) {
+	stream, err := conn.OpenUniStream()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stream.Write(binary.BigEndian.AppendUint64(nil, ownId.Pb())); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// send writes msg, prefixed by its length, to the stream corresponding to its priority.
+// Before the handshake completes, msg is also kept for being re-sent if the node rejects 0-RTT.
+func (c *connection) send(msg *messagepb.Message) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.early != nil {
+		c.early = append(c.early, msg)
+	}
+
+	err := c.write(msg)
+	if errors.Is(err, quic.Err0RTTRejected) && c.early != nil {
+		// Re-sent once the handshake completes.
+		return nil
+	}
+	return err
+}
+
+// write writes msg, prefixed by its length, to the stream corresponding to its priority.
+func (c *connection) write(msg *messagepb.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("could not serialize message: %w", err)
+	}
+
+	stream := c.critical
+	if msg.BestEffort() {
+		stream = c.bestEffort
+	}
+	_, err = stream.Write(append(binary.AppendUvarint(nil, uint64(len(data))), data...))
+	return err
+}
+
+// awaitHandshake waits until the handshake of a connection established with 0-RTT completes.
+// If the node rejected 0-RTT (e.g. because it restarted and cannot resume the session any more),
+// the data sent before has been discarded. awaitHandshake then re-opens the streams and re-sends the early messages.
+// If that fails (or the handshake does not complete within timeout), the connection is closed,
+// such that it is re-established when sending the next message.
+func (c *connection) awaitHandshake(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	select {
+	case <-c.conn.HandshakeComplete():
+	case <-ctx.Done():
+		_ = c.close()
+		return fmt.Errorf("handshake did not complete: %w", ctx.Err())
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	early := c.early
+	c.early = nil
+	if c.conn.ConnectionState().Used0RTT {
+		return nil
+	}
+
+	err := c.resend(ctx, early)
+	if err != nil {
+		_ = c.conn.CloseWithError(0, "")
+	}
+	return err
+}
+
+// resend re-opens the streams of a connection over which 0-RTT has been rejected and re-sends the given messages.
+func (c *connection) resend(ctx context.Context, msgs []*messagepb.Message) error {
+	var err error
+	if c.conn, err = c.conn.NextConnection(ctx); err != nil {
+		return err
+	}
+	if err := c.openStreams(); err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if err := c.write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close closes the underlying QUIC connection, discarding the data not yet transmitted.
+func (c *connection) close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.conn.CloseWithError(0, "")
+}
+
+// Backoff state for re-establishing a connection to a node.
+type reconnectState struct {
+
+	// Time after which the next attempt to reconnect can be made.
+	next time.Time
+
+	// Time to wait after the next failed attempt.
+	backoff time.Duration
+
+	// The address of the node to which the last attempt was made.
+	// If the address of the node changes, the backoff is reset.
+	addr string
+}
+
+// SetClock makes the QuicTransport measure the backoff between reconnection attempts using c
+// instead of the system clock. It must be called before the QuicTransport is used.
+func (qt *QuicTransport) SetClock(c clock.Clock) {
+	qt.clock = c
+}
+
+// AddressBook returns the AddressBook used by the QuicTransport.
+// Updating it makes the QuicTransport connect to the new addresses.
+func (qt *QuicTransport) AddressBook() *grpctransport.AddressBook {
+	return qt.addressBook
+}
+
+// Send sends msg to the node with ID dest, over the stream corresponding to the priority of msg.
+// If the QuicTransport is not connected to dest, Send first tries to (re-)establish the connection,
+// the same way as the GrpcTransport does. If sending over a connection fails, the connection is closed,
+// Send returns the error, and the connection is re-established when sending the next message.
+// The messages sent to dest before the handshake of a resumed session completes (0-RTT)
+// are re-sent if dest did not accept the resumption.
+// Concurrent calls to Send are not (yet? TODO) supported.
+func (qt *QuicTransport) Send(dest t.NodeID, msg *messagepb.Message) error {
+
+	// Drop the connection to the destination node if the node's address changed.
+	conn, ok := qt.connections[dest]
+	if addr, known := qt.addressBook.Lookup(dest); ok && (!known || addr != conn.addr) {
+		qt.logger.Log(logging.LevelInfo,
+			fmt.Sprintf("Address of node %d changed (%s -> %s). Dropping connection.", dest, conn.addr, addr))
+		qt.dropConnection(dest, conn)
+		ok = false
+	}
+
+	// (Re-)connect to the destination node if necessary.
+	if !ok {
+		var err error
+		if conn, err = qt.reconnect(dest); err != nil {
+			return err
+		}
+	}
+
+	// Send the message, dropping the connection on failure.
+	if err := conn.send(msg); err != nil {
+		qt.logger.Log(logging.LevelWarn, fmt.Sprintf("Failed sending to node %d: %v. Dropping connection.", dest, err))
+		qt.dropConnection(dest, conn)
+		return fmt.Errorf("failed sending to node %d: %w", dest, err)
+	}
+
+	return nil
+}
+
+// dropConnection removes the connection to node dest and closes it.
+func (qt *QuicTransport) dropConnection(dest t.NodeID, conn *connection) {
+	delete(qt.connections, dest)
+	if err := conn.close(); err != nil {
+		qt.logger.Log(logging.LevelDebug, fmt.Sprintf("Failed to close connection to node %d: %v", dest, err))
+	}
+}
+
+// reconnect tries to establish a connection to node dest, unless the backoff after the last failed attempt
+// has not yet elapsed. On success, the connection is stored and returned.
+func (qt *QuicTransport) reconnect(dest t.NodeID) (*connection, error) {
+
+	// Look up the current address of the node.
+	addr, ok := qt.addressBook.Lookup(dest)
+	if !ok {
+		return nil, fmt.Errorf("node %d not in address book", dest)
+	}
+
+	// Look up the backoff state, creating a new one if this is the first attempt
+	// or if the last attempt was made to a different address.
+	state, ok := qt.reconnects[dest]
+	if !ok || state.addr != addr {
+		state = &reconnectState{backoff: minReconnectBackoff, addr: addr}
+		qt.reconnects[dest] = state
+	}
+
+	// Fail immediately if the last attempt was too recent.
+	if qt.clock.Now().Before(state.next) {
+		return nil, fmt.Errorf("not connected to node %d", dest)
+	}
+
+	// Try to connect.
+	conn, err := qt.connectToNode(dest, addr, reconnectTimeout)
+	if err != nil {
+		// Schedule the next attempt with exponential backoff.
+		state.next = qt.clock.Now().Add(state.backoff)
+		if state.backoff *= 2; state.backoff > maxReconnectBackoff {
+			state.backoff = maxReconnectBackoff
+		}
+		return nil, fmt.Errorf("could not connect to node %d (%s): %w", dest, addr, err)
+	}
+
+	qt.logger.Log(logging.LevelInfo, fmt.Sprintf("Node %d (%s) reconnected.", dest, addr))
+	delete(qt.reconnects, dest)
+	qt.connections[dest] = conn
+	return conn, nil
+}
+
+// ReceiveChan returns a channel to which the Net module writes all received messages and sender IDs
+// (Both the message itself and the sender ID are part of the ReceivedMessage struct.)
+func (qt *QuicTransport) ReceiveChan() <-chan modules.ReceivedMessage {
+	return qt.incomingMessages
+}
+
+// Start starts the networking module by listening for QUIC connections
+// on the port determined by the own address in the AddressBook.
+// Before ths method is called, no other QuicTransports can connect to this one.
+func (qt *QuicTransport) Start() error {
+
+	// Obtain own port number from the address book.
+	ownAddr, _ := qt.addressBook.Lookup(qt.ownId)
+	_, ownPort, err := net.SplitHostPort(ownAddr)
+	if err != nil {
+		return fmt.Errorf("invalid own address (%s): %w", ownAddr, err)
+	}
+
+	qt.logger.Log(logging.LevelInfo, fmt.Sprintf("Listening for connections on port %s", ownPort))
+
+	// Start listening on the network
+	udpAddr, err := net.ResolveUDPAddr("udp", ":"+ownPort)
+	if err != nil {
+		return fmt.Errorf("invalid own port (%s): %w", ownPort, err)
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for connections on port %s: %w", ownPort, err)
+	}
+	qt.transport = &quic.Transport{Conn: conn}
+	if qt.listener, err = qt.transport.ListenEarly(qt.serverTLS, quicConfig()); err != nil {
+		return fmt.Errorf("failed to listen for connections on port %s: %w", ownPort, err)
+	}
+
+	// Accept connections in a separate goroutine, until the listener is closed.
+	qt.wg.Add(1)
+	go func() {
+		defer qt.wg.Done()
+		for {
+			conn, err := qt.listener.Accept(qt.ctx)
+			if err != nil {
+				qt.logger.Log(logging.LevelDebug, fmt.Sprintf("Stopped accepting connections: %v", err))
+				return
+			}
+			qt.wg.Add(1)
+			go func() {
+				defer qt.wg.Done()
+				qt.handleConnection(conn)
+			}()
+		}
+	}()
+
+	return nil
+}
+
+// handleConnection receives the messages sent by another node over the streams of an incoming connection
+// until the connection is closed.
+// If TLS is used, only the streams opened by the node the connection has been authenticated as are accepted.
+func (qt *QuicTransport) handleConnection(conn *quic.Conn) {
+	qt.logger.Log(logging.LevelDebug, fmt.Sprintf("Incoming connection from %s", conn.RemoteAddr().String()))
+
+	// Close the connection when the QuicTransport is stopped.
+	stop := context.AfterFunc(qt.ctx, func() {
+		_ = conn.CloseWithError(0, "")
+	})
+	defer stop()
+
+	// If TLS is used, determine the ID of the connected node.
+	var peerId t.NodeID
+	if qt.tlsConfig != nil {
+		var err error
+		if peerId, err = qt.peerNodeID(conn); err != nil {
+			qt.logger.Log(logging.LevelWarn,
+				fmt.Sprintf("Rejecting connection from %s: %v", conn.RemoteAddr().String(), err))
+			_ = conn.CloseWithError(0, "")
+			return
+		}
+	}
+
+	// Receive the messages of each stream in a separate goroutine.
+	var wg sync.WaitGroup
+	for {
+		stream, err := conn.AcceptUniStream(qt.ctx)
+		if err != nil {
+			qt.logger.Log(logging.LevelInfo,
+				fmt.Sprintf("Connection terminated: %s (%v)", conn.RemoteAddr().String(), err))
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := qt.receive(stream, peerId); err != nil {
+				qt.logger.Log(logging.LevelWarn,
+					fmt.Sprintf("Closing stream from %s: %v", conn.RemoteAddr().String(), err))
+				stream.CancelRead(0)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// peerNodeID returns the ID of the node the remote peer of an incoming connection has been authenticated as.
+// If the peer resumed a session with 0-RTT, it has already been authenticated when establishing the session.
+// Otherwise, the handshake must complete first.
+func (qt *QuicTransport) peerNodeID(conn *quic.Conn) (t.NodeID, error) {
+	if !conn.ConnectionState().Used0RTT {
+		select {
+		case <-conn.HandshakeComplete():
+		case <-conn.Context().Done():
+			return 0, context.Cause(conn.Context())
+		case <-qt.ctx.Done():
+			return 0, fmt.Errorf("transport stopped")
+		}
+	}
+
+	certs := conn.ConnectionState().TLS.PeerCertificates
+	if len(certs) == 0 {
+		return 0, fmt.Errorf("no certificate presented")
+	}
+	return qt.tlsConfig.NodeID(certs[0])
+}
+
+// receive reads the messages from a stream and writes them to the channel of incoming messages,
+// until the stream ends or the QuicTransport is stopped.
+// If TLS is used, a stream opened by another node than peerId is rejected.
+func (qt *QuicTransport) receive(stream *quic.ReceiveStream, peerId t.NodeID) error {
+	r := bufio.NewReader(stream)
+
+	// Read the ID of the sending node.
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return fmt.Errorf("could not read stream header: %w", err)
+	}
+	sender := t.NodeID(binary.BigEndian.Uint64(header[:]))
+	if qt.tlsConfig != nil && sender != peerId {
+		return fmt.Errorf("stream from node %d claiming to be sent by node %d", peerId, sender)
+	}
+
+	for {
+		// Read the next message.
+		size, err := binary.ReadUvarint(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not read message size: %w", err)
+		} else if size > maxMessageSize {
+			return fmt.Errorf("message too large (%d bytes)", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("could not read message: %w", err)
+		}
+		msg := &messagepb.Message{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return fmt.Errorf("could not parse message: %w", err)
+		}
+
+		// Write the message to the channel. This channel will be read by the user of the module.
+		select {
+		case qt.incomingMessages <- modules.ReceivedMessage{Sender: sender, Msg: msg}:
+		case <-qt.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Stop closes all open connections to other nodes and stops listening for incoming connections.
+// Stop returns after all the goroutines receiving messages returned.
+func (qt *QuicTransport) Stop() {
+
+	// Close connections to other nodes.
+	for id, connection := range qt.connections {
+		if err := connection.close(); err != nil {
+			qt.logger.Log(logging.LevelWarn, fmt.Sprintf("Could not close connection to node %d: %v", id, err))
+		}
+	}
+
+	// Close the incoming connections and stop listening.
+	qt.cancel()
+	qt.wg.Wait()
+	if qt.transport != nil {
+		if err := qt.transport.Close(); err != nil {
+			qt.logger.Log(logging.LevelWarn, fmt.Sprintf("Could not stop listening: %v", err))
+		}
+		if err := qt.transport.Conn.Close(); err != nil {
+			qt.logger.Log(logging.LevelWarn, fmt.Sprintf("Could not close socket: %v", err))
+		}
+	}
+
+	qt.logger.Log(logging.LevelDebug, "QuicTransport stopped.")
+}
+
+// Connect establishes (in parallel) network connections to all nodes in the AddressBook.
+// The other nodes' QuicTransport modules should be running.
+// If a connection cannot be established within connectTimeout, it is re-attempted when sending a message to the node.
+// Only after Connect() returns, sending messages over this QuicTransport is possible.
+func (qt *QuicTransport) Connect() {
+
+	// Obtain the current addresses of all nodes.
+	addrs := qt.addressBook.Snapshot()
+
+	// Initialize wait group used by the connecting goroutines
+	wg := sync.WaitGroup{}
+	wg.Add(len(addrs))
+
+	// Synchronizes concurrent access to connections.
+	lock := sync.Mutex{}
+
+	// For each node in the address book
+	for nodeId, nodeAddr := range addrs {
+
+		// Launch a goroutine that connects to the node.
+		go func(id t.NodeID, addr string) {
+			defer wg.Done()
+
+			// Create and store connection
+			connection, err := qt.connectToNode(id, addr, connectTimeout) // May take long, execute before acquiring the lock.
+			if err == nil {
+				lock.Lock()
+				qt.connections[id] = connection
+				lock.Unlock()
+			}
+
+			// Print debug info.
+			if err != nil {
+				qt.logger.Log(logging.LevelError,
+					fmt.Sprintf("Failed to connect to node %d (%s): %v", id, addr, err))
+			} else {
+				qt.logger.Log(logging.LevelDebug, fmt.Sprintf("Node %d (%s) connected.", id, addr))
+			}
+
+		}(nodeId, nodeAddr)
+	}
+
+	// Wait for connecting goroutines to finish.
+	wg.Wait()
+}
+
+// Establishes a connection to a single node with ID id at address addrString.
+// If a session with the node can be resumed, the connection is returned immediately (0-RTT).
+// Otherwise, it fails if the handshake does not complete within the given timeout.
+func (qt *QuicTransport) connectToNode(id t.NodeID, addrString string, timeout time.Duration) (*connection, error) {
+
+	qt.logger.Log(logging.LevelDebug, fmt.Sprintf("Connecting to node: %s", addrString))
+
+	// Authenticate the node if TLS is configured.
+	var tlsConf *tls.Config
+	if qt.tlsConfig != nil {
+		tlsConf = qt.tlsConfig.ClientConfig(id)
+	} else {
+		tlsConf = &tls.Config{InsecureSkipVerify: true} // The certificate of the node is ephemeral.
+	}
+	tlsConf.NextProtos = []string{alpnProtocol}
+	tlsConf.ClientSessionCache = qt.sessionCache
+
+	// The sessions are cached by server name. Since the nodes are not identified by host names, use the node ID.
+	tlsConf.ServerName = "node" + strconv.FormatUint(id.Pb(), 10)
+
+	// Set up a QUIC connection.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := quic.DialAddrEarly(ctx, addrString, tlsConf, quicConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	// Open the streams for sending messages.
+	c := &connection{conn: conn, addr: addrString, ownId: qt.ownId}
+	if err := c.openStreams(); err != nil {
+		if cerr := c.close(); cerr != nil {
+			qt.logger.Log(logging.LevelWarn, fmt.Sprintf("Failed to close connection: %v", cerr))
+		}
+		return nil, err
+	}
+
+	// If the session has been resumed with 0-RTT, keep the messages sent until the handshake completes.
+	select {
+	case <-conn.HandshakeComplete():
+	default:
+		c.early = make([]*messagepb.Message, 0)
+		go func() {
+			if err := c.awaitHandshake(timeout); err != nil {
+				qt.logger.Log(logging.LevelWarn, fmt.Sprintf("Failed to resume session with node %d: %v", id, err))
+			}
+		}()
+	}
+
+	// Return the connection to the node.
+	return c, nil
+}
@@ -0,0 +1,13 @@
+package quictransport
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestQuictransport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Quictransport Suite")
+}
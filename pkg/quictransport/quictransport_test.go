@@ -0,0 +1,178 @@
+package quictransport
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/grpctransport"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// criticalMessage returns a distinguishable protocol-critical message.
+func criticalMessage(sn uint64) *messagepb.Message {
+	return &messagepb.Message{Type: &messagepb.Message_DummyPreprepare{
+		DummyPreprepare: &messagepb.DummyPreprepare{Sn: sn},
+	}}
+}
+
+// bestEffortMessage returns a distinguishable best-effort message.
+func bestEffortMessage(reqNo uint64) *messagepb.Message {
+	return &messagepb.Message{Type: &messagepb.Message_ForwardedRequest{
+		ForwardedRequest: &requestpb.Request{ReqNo: reqNo},
+	}}
+}
+
+// localAddrs returns a local address with a free UDP port for each of the given nodes.
+func localAddrs(nodeIds ...t.NodeID) map[t.NodeID]string {
+	addrs := make(map[t.NodeID]string, len(nodeIds))
+	for _, id := range nodeIds {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		addrs[id] = "127.0.0.1:" + strconv.Itoa(conn.LocalAddr().(*net.UDPAddr).Port)
+		Expect(conn.Close()).To(Succeed())
+	}
+	return addrs
+}
+
+var _ = Describe("QuicTransport", func() {
+	var (
+		membership map[t.NodeID]string
+		tlsConfigs map[t.NodeID]*grpctransport.TLSConfig
+		transports map[t.NodeID]*QuicTransport
+	)
+
+	// start creates and starts the transport of node id, using mutual TLS if tlsConfig is not nil.
+	start := func(id t.NodeID, tlsConfig *grpctransport.TLSConfig) *QuicTransport {
+		var qt *QuicTransport
+		var err error
+		if tlsConfig != nil {
+			qt, err = NewTLSQuicTransport(membership, id, tlsConfig, logging.NilLogger)
+		} else {
+			qt, err = NewQuicTransport(membership, id, logging.NilLogger)
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qt.Start()).To(Succeed())
+		transports[id] = qt
+		return qt
+	}
+
+	// expectReceived expects the transport of node dest to receive msg from node from.
+	expectReceived := func(dest, from t.NodeID, msg *messagepb.Message) {
+		var received modules.ReceivedMessage
+		Eventually(transports[dest].ReceiveChan(), 5*time.Second).Should(Receive(&received))
+		Expect(received.Sender).To(Equal(from))
+		Expect(proto.Equal(received.Msg, msg)).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		membership = localAddrs(0, 1)
+		var err error
+		tlsConfigs, err = deploytest.LocalTLSConfigs([]t.NodeID{0, 1})
+		Expect(err).NotTo(HaveOccurred())
+		transports = make(map[t.NodeID]*QuicTransport)
+	})
+
+	AfterEach(func() {
+		for _, qt := range transports {
+			qt.Stop()
+		}
+	})
+
+	It("delivers messages of both priorities, preserving the order of each", func() {
+		start(1, nil)
+		sender := start(0, nil)
+		sender.Connect()
+
+		for i := uint64(0); i < 10; i++ {
+			Expect(sender.Send(1, criticalMessage(i))).To(Succeed())
+			Expect(sender.Send(1, bestEffortMessage(i))).To(Succeed())
+		}
+
+		var critical, bestEffort []uint64
+		for len(critical)+len(bestEffort) < 20 {
+			var received modules.ReceivedMessage
+			Eventually(transports[1].ReceiveChan(), 5*time.Second).Should(Receive(&received))
+			Expect(received.Sender).To(Equal(t.NodeID(0)))
+			if received.Msg.BestEffort() {
+				bestEffort = append(bestEffort, received.Msg.GetForwardedRequest().ReqNo)
+			} else {
+				critical = append(critical, received.Msg.GetDummyPreprepare().Sn)
+			}
+		}
+		expected := []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		Expect(critical).To(Equal(expected))
+		Expect(bestEffort).To(Equal(expected))
+	})
+
+	It("authenticates the sender using mutual TLS", func() {
+		start(1, tlsConfigs[1])
+		sender := start(0, tlsConfigs[0])
+		sender.Connect()
+
+		Expect(sender.Send(1, criticalMessage(1))).To(Succeed())
+		expectReceived(1, 0, criticalMessage(1))
+	})
+
+	It("rejects messages of nodes claiming another identity", func() {
+		start(1, tlsConfigs[1])
+
+		// Node 1 (impersonating node 0) sends to itself.
+		impostor := start(0, tlsConfigs[1])
+		impostor.Connect()
+
+		Expect(impostor.Send(1, criticalMessage(1))).To(Succeed())
+		Consistently(transports[1].ReceiveChan(), 500*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("resumes the session with 0-RTT when reconnecting", func() {
+		start(1, tlsConfigs[1])
+		sender := start(0, tlsConfigs[0])
+		sender.Connect()
+		Expect(sender.Send(1, criticalMessage(1))).To(Succeed())
+		expectReceived(1, 0, criticalMessage(1))
+
+		// Wait for the session ticket and drop the connection.
+		Eventually(func() bool {
+			_, ok := sender.sessionCache.Get("node1")
+			return ok
+		}).Should(BeTrue())
+		sender.dropConnection(1, sender.connections[1])
+
+		Expect(sender.Send(1, criticalMessage(2))).To(Succeed())
+		expectReceived(1, 0, criticalMessage(2))
+		Expect(sender.connections[1].conn.ConnectionState().Used0RTT).To(BeTrue())
+	})
+
+	It("falls back to a full handshake if the restarted node rejects 0-RTT", func() {
+		start(1, tlsConfigs[1])
+		sender := start(0, tlsConfigs[0])
+		sender.Connect()
+		Expect(sender.Send(1, criticalMessage(1))).To(Succeed())
+		expectReceived(1, 0, criticalMessage(1))
+		Eventually(func() bool {
+			_, ok := sender.sessionCache.Get("node1")
+			return ok
+		}).Should(BeTrue())
+
+		// The restarted node cannot resume the session, as it does not know the key of the session ticket.
+		transports[1].Stop()
+		Eventually(sender.connections[1].conn.Context().Done()).Should(BeClosed())
+		start(1, tlsConfigs[1])
+
+		// Sending over the closed connection fails, after which the sender reconnects.
+		Expect(sender.Send(1, criticalMessage(2))).NotTo(Succeed())
+		Expect(sender.Send(1, criticalMessage(3))).To(Succeed())
+		expectReceived(1, 0, criticalMessage(3))
+		Expect(sender.connections[1].conn.ConnectionState().Used0RTT).To(BeFalse())
+	})
+})
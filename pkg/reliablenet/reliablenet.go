@@ -0,0 +1,441 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package reliablenet provides a retransmission layer on top of a Net module that may lose messages
+// (e.g. one based on UDP, or one whose connections break and get re-established).
+// The protocol assumes that messages between correct nodes are eventually delivered,
+// while the Net module does not guarantee it. The retransmission layer bridges this gap
+// by keeping the sent messages in per-destination outbound queues and periodically retransmitting them
+// until the destination acknowledges their reception.
+// Both the sender and the receiver must use the retransmission layer.
+package reliablenet
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Config holds the parameters of the retransmission layer.
+type Config struct {
+
+	// Period of retransmitting unacknowledged messages and sending acknowledgments.
+	// A message is only retransmitted if it has not been (re)transmitted for at least RetransmitPeriod.
+	RetransmitPeriod time.Duration
+
+	// Maximal number of unacknowledged messages kept for retransmission per destination node.
+	// If the queue of a destination is full when sending a new message, the oldest queued message is dropped
+	// and not retransmitted any more. This prevents the queues from growing indefinitely when a node is down.
+	// If MaxQueueLength is not positive, the queues are not limited.
+	MaxQueueLength int
+
+	// Source of time for periodic retransmission. If nil, the system clock is used.
+	Clock clock.Clock
+
+	// ID of the session of this node, which must be different each time the node (re)starts,
+	// such that the receivers detect the restart and reset the sequence numbers expected from this node.
+	// If zero, the current time of Clock (in nanoseconds) is used.
+	Session uint64
+}
+
+// DefaultConfig returns the default configuration of the retransmission layer.
+func DefaultConfig() *Config {
+	return &Config{
+		RetransmitPeriod: 100 * time.Millisecond,
+		MaxQueueLength:   1024,
+	}
+}
+
+// Net is a modules.Net that wraps another modules.Net, adding reliability on top of it.
+// Each sent message is assigned a per-destination sequence number and kept in the destination's outbound queue
+// until the destination acknowledges it. The receivers periodically acknowledge ranges of received sequence numbers
+// and deliver each message only once, even if it is received multiple times.
+// Of the ISS Checkpoint messages, only the latest one is retransmitted to each destination,
+// as it supersedes all the previous ones.
+// Messages received from nodes not using the retransmission layer are delivered unchanged.
+// Net must be started using the Start method before use and stopped using the Stop method.
+type Net struct {
+
+	// The wrapped Net module.
+	net modules.Net
+
+	// Configuration parameters.
+	config *Config
+
+	// Source of time for retransmission (see Config.Clock).
+	clock clock.Clock
+
+	// ID of this instance of the retransmission layer (see Config.Session),
+	// used by the receivers to detect that the sender restarted and started assigning sequence numbers anew.
+	session uint64
+
+	// Logger used for all logging events of this Net.
+	logger logging.Logger
+
+	// Serializes the calls to the wrapped Net module's Send method and protects the state below.
+	lock sync.Mutex
+
+	// The outbound queues, indexed by destination node ID.
+	outbound map[t.NodeID]*outQueue
+
+	// The state of message reception, indexed by source node ID.
+	inbound map[t.NodeID]*inState
+
+	// Channel to which all delivered messages are written (see ReceiveChan).
+	incoming chan modules.ReceivedMessage
+
+	// Closed when the Net is stopped, making its goroutines exit.
+	stopC chan struct{}
+
+	// Used to wait for the goroutines to exit on Stop.
+	wg sync.WaitGroup
+}
+
+// Outbound queue of messages to a single destination node.
+type outQueue struct {
+
+	// Sequence number to assign to the next sent message.
+	nextSn uint64
+
+	// No queued message has a lower sequence number than lowSn.
+	lowSn uint64
+
+	// The queued (not yet acknowledged) messages, indexed by sequence number.
+	msgs map[uint64]*queuedMsg
+
+	// Sequence number of the queued Checkpoint message (if any).
+	checkpointSn  uint64
+	hasCheckpoint bool
+}
+
+// A message waiting for acknowledgment.
+type queuedMsg struct {
+
+	// The message as sent over the wrapped Net module (i.e., wrapped in a ReliableData message).
+	msg *messagepb.Message
+
+	// Time of the last (re)transmission.
+	lastSent time.Time
+}
+
+// The state of receiving messages from a single source node.
+type inState struct {
+
+	// Session of the source node. If a message with a different session is received, the state is reset.
+	session uint64
+
+	// All messages with lower sequence numbers have been received (or will never be sent).
+	lowSn uint64
+
+	// Set of sequence numbers (not lower than lowSn) of received messages.
+	received map[uint64]struct{}
+
+	// Set to true when a message is received and to false when an acknowledgment is sent.
+	ackPending bool
+}
+
+// New returns a new Net adding reliability to the given Net module.
+// If config is nil, the default configuration is used.
+// The returned Net is not yet running. This needs to be done explicitly by calling the Start() method.
+func New(net modules.Net, config *Config, logger logging.Logger) *Net {
+
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	// If no logger was given, only write errors to the console.
+	if logger == nil {
+		logger = logging.ConsoleErrorLogger
+	}
+
+	clk := clock.OrSystem(config.Clock)
+	session := config.Session
+	if session == 0 {
+		session = uint64(clk.Now().UnixNano())
+	}
+
+	return &Net{
+		net:      net,
+		config:   config,
+		clock:    clk,
+		session:  session,
+		logger:   logger,
+		outbound: make(map[t.NodeID]*outQueue),
+		inbound:  make(map[t.NodeID]*inState),
+		incoming: make(chan modules.ReceivedMessage),
+	}
+}
+
+// Start launches the goroutines receiving messages from the wrapped Net module and periodically
+// retransmitting unacknowledged messages and sending acknowledgments.
+// The wrapped Net module must be started separately (if it needs starting).
+func (n *Net) Start() {
+	n.stopC = make(chan struct{})
+	n.wg.Add(2)
+	go n.receive()
+	go n.retransmit()
+}
+
+// Stop stops the goroutines launched by Start and waits for them to exit.
+// The messages that have not been acknowledged yet are discarded.
+func (n *Net) Stop() {
+	close(n.stopC)
+	n.wg.Wait()
+}
+
+// Send assigns the next sequence number to msg, adds it to the outbound queue of node dest, and transmits it.
+// If transmitting the message fails, the failure is only logged and Send returns nil,
+// as the message will be retransmitted.
+// Send is safe for concurrent use.
+func (n *Net) Send(dest t.NodeID, msg *messagepb.Message) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// Look up the outbound queue of the destination, creating a new one if necessary.
+	q, ok := n.outbound[dest]
+	if !ok {
+		q = &outQueue{msgs: make(map[uint64]*queuedMsg)}
+		n.outbound[dest] = q
+	}
+
+	// Drop the previous Checkpoint message, which is superseded by the new one.
+	if isCheckpoint(msg) {
+		if q.hasCheckpoint {
+			delete(q.msgs, q.checkpointSn)
+		}
+		q.checkpointSn = q.nextSn
+		q.hasCheckpoint = true
+	}
+
+	// Drop the oldest messages if the queue is full.
+	for n.config.MaxQueueLength > 0 && len(q.msgs) >= n.config.MaxQueueLength {
+		if _, ok := q.msgs[q.lowSn]; ok {
+			n.logger.Log(logging.LevelWarn, "Outbound queue full, dropping message.", "dest", dest, "sn", q.lowSn)
+			delete(q.msgs, q.lowSn)
+		}
+		q.lowSn++
+	}
+
+	// Queue and transmit the message.
+	qm := &queuedMsg{msg: &messagepb.Message{Type: &messagepb.Message_ReliableData{ReliableData: &messagepb.ReliableData{
+		Session: n.session,
+		Sn:      q.nextSn,
+		LowSn:   q.lowSn,
+		Msg:     msg,
+	}}}}
+	q.msgs[q.nextSn] = qm
+	q.nextSn++
+	n.transmit(dest, qm)
+
+	return nil
+}
+
+// ReceiveChan returns a channel to which the Net writes all delivered messages and sender IDs.
+func (n *Net) ReceiveChan() <-chan modules.ReceivedMessage {
+	return n.incoming
+}
+
+// transmit sends a queued message using the wrapped Net module. Must be called with the lock held.
+func (n *Net) transmit(dest t.NodeID, qm *queuedMsg) {
+	qm.lastSent = n.clock.Now()
+	if err := n.net.Send(dest, qm.msg); err != nil {
+		n.logger.Log(logging.LevelDebug, "Failed transmitting message, will retransmit.", "dest", dest, "err", err)
+	}
+}
+
+// receive reads messages from the wrapped Net module, processes the retransmission layer's messages,
+// and delivers the contained protocol messages until the Net is stopped.
+func (n *Net) receive() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case received := <-n.net.ReceiveChan():
+			// Process the received message, obtaining the message to deliver (if any).
+			var deliver *messagepb.Message
+			switch msg := received.Msg.Type.(type) {
+			case *messagepb.Message_ReliableData:
+				deliver = n.handleData(received.Sender, msg.ReliableData)
+			case *messagepb.Message_ReliableAck:
+				n.handleAck(received.Sender, msg.ReliableAck)
+			default:
+				deliver = received.Msg
+			}
+
+			// Deliver the message.
+			if deliver != nil {
+				select {
+				case n.incoming <- modules.ReceivedMessage{Sender: received.Sender, Msg: deliver}:
+				case <-n.stopC:
+					return
+				}
+			}
+		case <-n.stopC:
+			return
+		}
+	}
+}
+
+// handleData records the reception of a message from node source
+// and returns the wrapped protocol message if it has not been received before. Otherwise, it returns nil.
+func (n *Net) handleData(source t.NodeID, data *messagepb.ReliableData) *messagepb.Message {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// Look up the reception state of the source, resetting it if the source restarted.
+	st, ok := n.inbound[source]
+	if !ok || st.session != data.Session {
+		st = &inState{session: data.Session, received: make(map[uint64]struct{})}
+		n.inbound[source] = st
+	}
+
+	// Even if the message is a duplicate, the source evidently did not receive the acknowledgment yet.
+	st.ackPending = true
+
+	// Forget about messages that will never be retransmitted.
+	for ; st.lowSn < data.LowSn; st.lowSn++ {
+		delete(st.received, st.lowSn)
+	}
+
+	// Ignore duplicates.
+	if _, ok := st.received[data.Sn]; ok || data.Sn < st.lowSn {
+		return nil
+	}
+
+	// Record the reception, advancing lowSn as far as possible.
+	st.received[data.Sn] = struct{}{}
+	for _, ok := st.received[st.lowSn]; ok; _, ok = st.received[st.lowSn] {
+		delete(st.received, st.lowSn)
+		st.lowSn++
+	}
+
+	return data.Msg
+}
+
+// handleAck removes the messages acknowledged by node source from the respective outbound queue.
+func (n *Net) handleAck(source t.NodeID, ack *messagepb.ReliableAck) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// Ignore acknowledgments of messages sent by a previous instance of this node.
+	q, ok := n.outbound[source]
+	if !ok || ack.Session != n.session {
+		return
+	}
+
+	// Remove the acknowledged messages.
+	for sn := range q.msgs {
+		for _, r := range ack.Ranges {
+			if sn >= r.From && sn < r.To {
+				delete(q.msgs, sn)
+				break
+			}
+		}
+	}
+	if _, ok := q.msgs[q.checkpointSn]; !ok {
+		q.hasCheckpoint = false
+	}
+
+	// Advance lowSn to the lowest sequence number of a queued message.
+	for _, ok := q.msgs[q.lowSn]; !ok && q.lowSn < q.nextSn; _, ok = q.msgs[q.lowSn] {
+		q.lowSn++
+	}
+}
+
+// retransmit periodically retransmits the unacknowledged messages and sends the pending acknowledgments
+// until the Net is stopped.
+func (n *Net) retransmit() {
+	defer n.wg.Done()
+
+	ticker := n.clock.NewTicker(n.config.RetransmitPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			n.retransmitOnce()
+		case <-n.stopC:
+			return
+		}
+	}
+}
+
+// retransmitOnce sends the pending acknowledgments and retransmits the messages
+// that have not been (re)transmitted for at least the retransmission period.
+func (n *Net) retransmitOnce() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// Send acknowledgments.
+	for source, st := range n.inbound {
+		if !st.ackPending {
+			continue
+		}
+		ack := &messagepb.Message{Type: &messagepb.Message_ReliableAck{ReliableAck: &messagepb.ReliableAck{
+			Session: st.session,
+			Ranges:  st.ackRanges(),
+		}}}
+		if err := n.net.Send(source, ack); err != nil {
+			n.logger.Log(logging.LevelDebug, "Failed sending acknowledgment.", "dest", source, "err", err)
+		}
+		st.ackPending = false
+	}
+
+	// Retransmit messages (in the order of their sequence numbers).
+	threshold := n.clock.Now().Add(-n.config.RetransmitPeriod)
+	for dest, q := range n.outbound {
+		sns := make([]uint64, 0, len(q.msgs))
+		for sn, qm := range q.msgs {
+			if !qm.lastSent.After(threshold) {
+				sns = append(sns, sn)
+			}
+		}
+		sort.Slice(sns, func(i, j int) bool { return sns[i] < sns[j] })
+		for _, sn := range sns {
+			n.transmit(dest, q.msgs[sn])
+		}
+	}
+}
+
+// ackRanges returns the ranges of sequence numbers of the received messages.
+func (st *inState) ackRanges() []*messagepb.SnRange {
+
+	// All messages below lowSn have been received.
+	ranges := []*messagepb.SnRange{{From: 0, To: st.lowSn}}
+
+	// Merge the sequence numbers of the other received messages in ranges.
+	sns := make([]uint64, 0, len(st.received))
+	for sn := range st.received {
+		sns = append(sns, sn)
+	}
+	sort.Slice(sns, func(i, j int) bool { return sns[i] < sns[j] })
+	for _, sn := range sns {
+		if last := ranges[len(ranges)-1]; last.To == sn {
+			last.To++
+		} else {
+			ranges = append(ranges, &messagepb.SnRange{From: sn, To: sn + 1})
+		}
+	}
+
+	return ranges
+}
+
+// isCheckpoint returns true if msg is an ISS Checkpoint message.
+func isCheckpoint(msg *messagepb.Message) bool {
+	issMsg, ok := msg.Type.(*messagepb.Message_Iss)
+	if !ok {
+		return false
+	}
+	_, ok = issMsg.Iss.Type.(*isspb.ISSMessage_Checkpoint)
+	return ok
+}
@@ -0,0 +1,13 @@
+package reliablenet_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestReliablenet(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Reliablenet Suite")
+}
@@ -0,0 +1,251 @@
+package reliablenet_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/reliablenet"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// lossyNet is a Net module wrapper that silently drops the sent messages for which drop returns true.
+type lossyNet struct {
+	modules.Net
+	lock sync.Mutex
+	drop func(msg *messagepb.Message) bool
+}
+
+func (ln *lossyNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	ln.lock.Lock()
+	drop := ln.drop != nil && ln.drop(msg)
+	ln.lock.Unlock()
+	if drop {
+		return nil
+	}
+	return ln.Net.Send(dest, msg)
+}
+
+func (ln *lossyNet) setDrop(drop func(msg *messagepb.Message) bool) {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+	ln.drop = drop
+}
+
+// dropAll drops all messages.
+func dropAll(*messagepb.Message) bool {
+	return true
+}
+
+// testMessage returns a distinguishable message.
+func testMessage(sn uint64) *messagepb.Message {
+	return &messagepb.Message{Type: &messagepb.Message_DummyPreprepare{
+		DummyPreprepare: &messagepb.DummyPreprepare{Sn: sn},
+	}}
+}
+
+// checkpointMessage returns an ISS Checkpoint message.
+func checkpointMessage(epoch uint64) *messagepb.Message {
+	return &messagepb.Message{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{
+		Type: &isspb.ISSMessage_Checkpoint{Checkpoint: &isspb.Checkpoint{Epoch: epoch}},
+	}}}
+}
+
+var _ = Describe("Net", func() {
+	var (
+		mockClock *clock.Mock
+		config    *reliablenet.Config
+		transport *deploytest.FakeTransport
+		links     []*lossyNet
+		nets      []*reliablenet.Net
+	)
+
+	// receive advances the clock, making the Nets retransmit, until node dest delivers a message, and returns it.
+	receive := func(dest t.NodeID) modules.ReceivedMessage {
+		var received modules.ReceivedMessage
+		Eventually(func() bool {
+			select {
+			case received = <-nets[dest].ReceiveChan():
+				return true
+			default:
+				mockClock.Advance(config.RetransmitPeriod)
+				return false
+			}
+		}).Should(BeTrue())
+		return received
+	}
+
+	// expectReceived expects node dest to deliver msg from source next.
+	expectReceived := func(dest t.NodeID, source t.NodeID, msg *messagepb.Message) {
+		received := receive(dest)
+		Expect(received.Sender).To(Equal(source))
+		Expect(proto.Equal(received.Msg, msg)).To(BeTrue(), "received %v instead of %v", received.Msg, msg)
+	}
+
+	// expectNothingReceived expects node dest not to deliver any message, even if the Nets keep retransmitting.
+	expectNothingReceived := func(dest t.NodeID) {
+		Consistently(func() bool {
+			select {
+			case <-nets[dest].ReceiveChan():
+				return true
+			default:
+				mockClock.Advance(config.RetransmitPeriod)
+				return false
+			}
+		}, "200ms").Should(BeFalse())
+	}
+
+	BeforeEach(func() {
+		mockClock = clock.NewMock(time.Unix(0, 0))
+		config = &reliablenet.Config{
+			RetransmitPeriod: time.Second,
+			MaxQueueLength:   2,
+			Clock:            mockClock,
+			Session:          1,
+		}
+		transport = deploytest.NewFakeTransport(2)
+		links = []*lossyNet{{Net: transport.Link(0)}, {Net: transport.Link(1)}}
+		nets = []*reliablenet.Net{
+			reliablenet.New(links[0], config, logging.NilLogger),
+			reliablenet.New(links[1], config, logging.NilLogger),
+		}
+		transport.Start()
+		for _, net := range nets {
+			net.Start()
+		}
+	})
+
+	AfterEach(func() {
+		for _, net := range nets {
+			net.Stop()
+		}
+		transport.Stop()
+	})
+
+	It("retransmits lost messages", func() {
+		links[0].setDrop(dropAll)
+		Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+		expectNothingReceived(1)
+
+		links[0].setDrop(nil)
+		expectReceived(1, 0, testMessage(1))
+	})
+
+	It("delivers each message only once", func() {
+		Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+		expectReceived(1, 0, testMessage(1))
+
+		// Before the acknowledgment arrives, the message is retransmitted, but never delivered again.
+		links[1].setDrop(dropAll)
+		expectNothingReceived(1)
+
+		links[1].setDrop(nil)
+		Expect(nets[0].Send(1, testMessage(2))).To(Succeed())
+		expectReceived(1, 0, testMessage(2))
+	})
+
+	It("drops the oldest messages when the queue is full", func() {
+		links[0].setDrop(dropAll)
+		for sn := uint64(1); sn <= 3; sn++ {
+			Expect(nets[0].Send(1, testMessage(sn))).To(Succeed())
+		}
+
+		links[0].setDrop(nil)
+		expectReceived(1, 0, testMessage(2))
+		expectReceived(1, 0, testMessage(3))
+		expectNothingReceived(1)
+	})
+
+	It("only retransmits the latest checkpoint", func() {
+		links[0].setDrop(dropAll)
+		Expect(nets[0].Send(1, checkpointMessage(1))).To(Succeed())
+		Expect(nets[0].Send(1, checkpointMessage(2))).To(Succeed())
+
+		links[0].setDrop(nil)
+		expectReceived(1, 0, checkpointMessage(2))
+		expectNothingReceived(1)
+	})
+
+	It("delivers the messages of a restarted sender", func() {
+		Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+		expectReceived(1, 0, testMessage(1))
+
+		// The restarted sender starts assigning sequence numbers anew, in a new session.
+		restartedConfig := *config
+		restartedConfig.Session = 2
+		nets[0].Stop()
+		nets[0] = reliablenet.New(links[0], &restartedConfig, logging.NilLogger)
+		nets[0].Start()
+		Expect(nets[0].Send(1, testMessage(2))).To(Succeed())
+		expectReceived(1, 0, testMessage(2))
+	})
+
+	It("derives the session of a restarted sender from the clock by default", func() {
+		Expect(nets[0].Send(1, testMessage(1))).To(Succeed())
+		expectReceived(1, 0, testMessage(1))
+
+		defaultConfig := *config
+		defaultConfig.Session = 0
+		mockClock.Advance(time.Second)
+		nets[0].Stop()
+		nets[0] = reliablenet.New(links[0], &defaultConfig, logging.NilLogger)
+		nets[0].Start()
+		Expect(nets[0].Send(1, testMessage(2))).To(Succeed())
+		expectReceived(1, 0, testMessage(2))
+	})
+
+	It("delivers messages of senders not using the retransmission layer unchanged", func() {
+		Expect(links[0].Send(1, testMessage(1))).To(Succeed())
+		expectReceived(1, 0, testMessage(1))
+	})
+})
+
+var _ = Describe("Reliable retransmission test", func() {
+
+	It("delivers all requests over links that lose messages", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Silently drop every other message, letting the retransmission layer make up for the lost ones.
+		for _, replica := range deployment.TestReplicas {
+			replica.Net = reliablenet.New(&alternatingLossyNet{Net: replica.Net}, &reliablenet.Config{
+				RetransmitPeriod: 10 * time.Millisecond,
+			}, nil)
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// alternatingLossyNet is a Net module wrapper that silently drops every other message.
+type alternatingLossyNet struct {
+	modules.Net
+	attempts uint64
+}
+
+func (ln *alternatingLossyNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if atomic.AddUint64(&ln.attempts, 1)%2 == 1 {
+		return nil
+	}
+	return ln.Net.Send(dest, msg)
+}
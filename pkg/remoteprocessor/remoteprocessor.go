@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remoteprocessor allows a Node to have the events of some of its modules processed in a separate process
+// (e.g. the heavy I/O of persisting data or applying requests to the application),
+// such that the Node and the processing of the events can be scaled independently
+// and a crash of one process does not directly affect the other.
+// The Server runs in the remote process and exposes a mirbft.Processor (see mirbft.ModuleProcessors) over gRPC.
+// The Client is a mirbft.Processor that the Node uses (see mirbft.NodeConfig.Processors)
+// to forward the events to the Server and obtain the resulting events.
+package remoteprocessor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	// Maximal size of a gRPC message carrying a list of events.
+	maxMessageSize = 1073741824
+)
+
+// Server processes lists of events received over gRPC from a Client using a local mirbft.Processor.
+type Server struct {
+
+	// The Processor used for processing the received events.
+	processor mirbft.Processor
+
+	// Serializes the invocations of processor.Process, which must not be called concurrently.
+	processorLock sync.Mutex
+
+	// The gRPC server used by this Server.
+	grpcServer *grpc.Server
+
+	// Error returned from the grpcServer.Serve() call (see Start() method).
+	grpcServerError error
+
+	// Logger use for all logging events of this Server.
+	logger logging.Logger
+}
+
+// NewServer returns a new Server processing events using the given Processor.
+// The returned Server is not yet running. This needs to be done explicitly by calling the Start() method.
+func NewServer(processor mirbft.Processor, logger logging.Logger) *Server {
+	// If no logger was given, only write errors to the console.
+	if logger == nil {
+		logger = logging.ConsoleErrorLogger
+	}
+
+	return &Server{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+// Process implements the gRPC Process service.
+// It processes the received list of events using the Server's Processor and returns the resulting events.
+// If the Processor returns an error, so does Process. In such a case, the Node using the Client halts.
+func (s *Server) Process(ctx context.Context, eventsIn *eventpb.EventList) (*eventpb.EventList, error) {
+	s.processorLock.Lock()
+	defer s.processorLock.Unlock()
+
+	eventsOut, err := s.processor.Process(events.FromPb(eventsIn))
+	if err != nil {
+		s.logger.Log(logging.LevelError, "Failed processing events.", "error", err)
+		return nil, err
+	}
+	return eventsOut.Pb(), nil
+}
+
+// Start starts the Server's Processor and the internal gRPC server, listening on the passed port.
+// Before ths method is called, no client connections are accepted.
+func (s *Server) Start(port int) error {
+
+	s.logger.Log(logging.LevelInfo, fmt.Sprintf("Listening for remote processor connections on port %d", port))
+
+	// Start the processor.
+	if err := s.processor.Start(); err != nil {
+		return fmt.Errorf("could not start processor: %w", err)
+	}
+
+	// Create a gRPC server and assign it the logic of this Server.
+	s.grpcServer = grpc.NewServer(grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize))
+	RegisterRemoteProcessorServer(s.grpcServer, s)
+
+	// Start listening on the network
+	conn, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		s.processor.Stop()
+		return fmt.Errorf("failed to listen for connections on port %d: %w", port, err)
+	}
+
+	// Start the gRPC server in a separate goroutine.
+	// When the server stops, it will write its exit error into s.grpcServerError.
+	go func() {
+		s.grpcServerError = s.grpcServer.Serve(conn)
+	}()
+
+	// If we got all the way here, no error occurred.
+	return nil
+}
+
+// Stop stops the gRPC server (waiting for the pending calls to finish) and the Server's Processor.
+// After Stop() returns, the error returned by the gRPC server's Serve() call
+// can be obtained through the ServerError() method.
+func (s *Server) Stop() {
+
+	s.logger.Log(logging.LevelDebug, "Stopping remote processor server.")
+
+	s.grpcServer.GracefulStop()
+	s.processor.Stop()
+
+	s.logger.Log(logging.LevelDebug, "Remote processor server stopped.")
+}
+
+// ServerError returns the error returned by the gRPC server's Serve() call.
+// ServerError() must not be called before the Server is stopped and its Stop() method has returned.
+func (s *Server) ServerError() error {
+	return s.grpcServerError
+}
+
+// Client is a mirbft.Processor that processes events by forwarding them to a Server.
+type Client struct {
+
+	// Address of the Server.
+	addr string
+
+	// The connection to the Server, established by Start.
+	conn   *grpc.ClientConn
+	client RemoteProcessorClient
+}
+
+// NewClient returns a new Client that forwards the events to the Server listening at the given address.
+// The connection is only established when the Client is started.
+func NewClient(addr string) *Client {
+	return &Client{addr: addr}
+}
+
+// Start connects to the Server. It blocks until the connection is established.
+func (c *Client) Start() error {
+
+	// Set general gRPC dial options.
+	dialOpts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize), grpc.MaxCallSendMsgSize(maxMessageSize)),
+		grpc.WithInsecure(),
+	}
+
+	// Set up a gRPC connection.
+	conn, err := grpc.Dial(c.addr, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("could not connect to remote processor at %s: %w", c.addr, err)
+	}
+
+	c.conn = conn
+	c.client = NewRemoteProcessorClient(conn)
+	return nil
+}
+
+// Process sends the list of events to the Server and returns the resulting events.
+// If the Server cannot be reached or fails processing the events, Process returns an error.
+func (c *Client) Process(eventsIn *events.EventList) (*events.EventList, error) {
+	eventsOut, err := c.client.Process(context.Background(), eventsIn.Pb())
+	if err != nil {
+		return nil, errors.WithMessage(err, "remote processing failed")
+	}
+	return events.FromPb(eventsOut), nil
+}
+
+// Stop closes the connection to the Server.
+func (c *Client) Stop() {
+	// Closing the connection can only fail if it has already been closed, in which case there is nothing to do.
+	_ = c.conn.Close()
+}
@@ -0,0 +1,124 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: remoteprocessor/remoteprocessor.proto
+
+package remoteprocessor
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	eventpb "github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+func init() {
+	proto.RegisterFile("remoteprocessor/remoteprocessor.proto", fileDescriptor_331caa69a64908d0)
+}
+
+var fileDescriptor_331caa69a64908d0 = []byte{
+	// 145 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2d, 0x4a, 0xcd, 0xcd,
+	0x2f, 0x49, 0x2d, 0x28, 0xca, 0x4f, 0x4e, 0x2d, 0x2e, 0xce, 0x2f, 0xd2, 0x47, 0xe3, 0xeb, 0x15,
+	0x14, 0xe5, 0x97, 0xe4, 0x0b, 0xf1, 0xa3, 0x09, 0x4b, 0x89, 0xa6, 0x96, 0xa5, 0xe6, 0x95, 0x14,
+	0x24, 0xe9, 0x43, 0x69, 0x88, 0x3a, 0x23, 0x17, 0x2e, 0xfe, 0x20, 0xb0, 0xca, 0x00, 0x98, 0x4a,
+	0x21, 0x43, 0x2e, 0x76, 0x28, 0x47, 0x48, 0x48, 0x0f, 0xa6, 0xda, 0x15, 0x44, 0xfb, 0x64, 0x16,
+	0x97, 0x48, 0x61, 0x11, 0x73, 0xb2, 0x88, 0x32, 0x4b, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b,
+	0xce, 0xcf, 0xd5, 0xcf, 0xa8, 0x2c, 0x48, 0x2d, 0xca, 0x49, 0x4d, 0x49, 0x4f, 0x2d, 0xd2, 0xcd,
+	0x49, 0x4c, 0x2a, 0xd6, 0xcf, 0xcd, 0x2c, 0x4a, 0x4a, 0x2b, 0xd1, 0x2f, 0xc8, 0x4e, 0x47, 0x77,
+	0x6d, 0x12, 0x1b, 0xd8, 0x19, 0xc6, 0x80, 0x01, 0x00, 0x52, 0x64, 0x61, 0x50, 0xd7, 0x00, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RemoteProcessorClient is the client API for RemoteProcessor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RemoteProcessorClient interface {
+	Process(ctx context.Context, in *eventpb.EventList, opts ...grpc.CallOption) (*eventpb.EventList, error)
+}
+
+type remoteProcessorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRemoteProcessorClient(cc *grpc.ClientConn) RemoteProcessorClient {
+	return &remoteProcessorClient{cc}
+}
+
+func (c *remoteProcessorClient) Process(ctx context.Context, in *eventpb.EventList, opts ...grpc.CallOption) (*eventpb.EventList, error) {
+	out := new(eventpb.EventList)
+	err := c.cc.Invoke(ctx, "/remoteprocessor.RemoteProcessor/Process", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RemoteProcessorServer is the server API for RemoteProcessor service.
+type RemoteProcessorServer interface {
+	Process(context.Context, *eventpb.EventList) (*eventpb.EventList, error)
+}
+
+// UnimplementedRemoteProcessorServer can be embedded to have forward compatible implementations.
+type UnimplementedRemoteProcessorServer struct {
+}
+
+func (*UnimplementedRemoteProcessorServer) Process(ctx context.Context, req *eventpb.EventList) (*eventpb.EventList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Process not implemented")
+}
+
+func RegisterRemoteProcessorServer(s *grpc.Server, srv RemoteProcessorServer) {
+	s.RegisterService(&_RemoteProcessor_serviceDesc, srv)
+}
+
+func _RemoteProcessor_Process_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(eventpb.EventList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteProcessorServer).Process(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/remoteprocessor.RemoteProcessor/Process",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteProcessorServer).Process(ctx, req.(*eventpb.EventList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RemoteProcessor_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "remoteprocessor.RemoteProcessor",
+	HandlerType: (*RemoteProcessorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Process",
+			Handler:    _RemoteProcessor_Process_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "remoteprocessor/remoteprocessor.proto",
+}
@@ -0,0 +1,13 @@
+package remoteprocessor_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRemoteprocessor(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Remoteprocessor Suite")
+}
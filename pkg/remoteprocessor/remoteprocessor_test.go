@@ -0,0 +1,161 @@
+package remoteprocessor_test
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/remoteprocessor"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// snapshotProcessor is a Processor answering each AppSnapshotRequest event with an AppSnapshot event
+// and failing on all other events. It records whether it is running and whether Process is invoked concurrently.
+type snapshotProcessor struct {
+	started    int32
+	processing int32
+	concurrent int32
+}
+
+func (sp *snapshotProcessor) Start() error {
+	atomic.StoreInt32(&sp.started, 1)
+	return nil
+}
+
+func (sp *snapshotProcessor) Process(eventsIn *events.EventList) (*events.EventList, error) {
+	if atomic.AddInt32(&sp.processing, 1) > 1 {
+		atomic.StoreInt32(&sp.concurrent, 1)
+	}
+	defer atomic.AddInt32(&sp.processing, -1)
+	runtime.Gosched()
+
+	eventsOut := &events.EventList{}
+	iter := eventsIn.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		request, ok := event.Type.(*eventpb.Event_AppSnapshotRequest)
+		if !ok {
+			return nil, fmt.Errorf("unexpected event: %T", event.Type)
+		}
+		eventsOut.PushBack(events.AppSnapshot(t.SeqNr(request.AppSnapshotRequest.Sn), []byte("snapshot")))
+	}
+	return eventsOut, nil
+}
+
+func (sp *snapshotProcessor) Stop() {
+	atomic.StoreInt32(&sp.started, 0)
+}
+
+// freePort returns a TCP port that is currently not in use.
+func freePort() int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+var _ = Describe("Remote processor", func() {
+	var (
+		processor *snapshotProcessor
+		server    *remoteprocessor.Server
+		client    *remoteprocessor.Client
+	)
+
+	BeforeEach(func() {
+		processor = &snapshotProcessor{}
+		server = remoteprocessor.NewServer(processor, logging.NilLogger)
+		port := freePort()
+		Expect(server.Start(port)).To(Succeed())
+		client = remoteprocessor.NewClient(fmt.Sprintf("127.0.0.1:%d", port))
+		Expect(client.Start()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		client.Stop()
+		server.Stop()
+		Expect(atomic.LoadInt32(&processor.started)).To(BeZero())
+	})
+
+	It("starts the processor of the server", func() {
+		Expect(atomic.LoadInt32(&processor.started)).To(Equal(int32(1)))
+	})
+
+	It("returns the events resulting from remote processing", func() {
+		eventsOut, err := client.Process((&events.EventList{}).
+			PushBack(events.AppSnapshotRequest(3)).
+			PushBack(events.AppSnapshotRequest(5)))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(eventsOut.Len()).To(Equal(2))
+		iter := eventsOut.Iterator()
+		for _, sn := range []uint64{3, 5} {
+			snapshot := iter.Next().Type.(*eventpb.Event_AppSnapshot).AppSnapshot
+			Expect(snapshot.Sn).To(Equal(sn))
+			Expect(snapshot.Data).To(Equal([]byte("snapshot")))
+		}
+	})
+
+	It("returns the errors of the remote processor", func() {
+		_, err := client.Process((&events.EventList{}).PushBack(events.Tick()))
+		Expect(err).To(MatchError(ContainSubstring("unexpected event")))
+	})
+
+	It("never invokes the processor concurrently", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(sn t.SeqNr) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := client.Process((&events.EventList{}).PushBack(events.AppSnapshotRequest(sn)))
+				Expect(err).NotTo(HaveOccurred())
+			}(t.SeqNr(i))
+		}
+		wg.Wait()
+		Expect(atomic.LoadInt32(&processor.concurrent)).To(BeZero())
+	})
+})
+
+var _ = Describe("Remote processor test", func() {
+
+	It("delivers all requests to an application processed remotely", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Process the events of each replica's App module by a remote processor server.
+		for _, replica := range deployment.TestReplicas {
+			port := remoteProcessorPort + int(replica.Id)
+			processors := mirbft.ModuleProcessors(replica.Id, replica.Config, &modules.Modules{App: replica.App})
+			server := remoteprocessor.NewServer(processors.App, replica.Config.Logger)
+			Expect(server.Start(port)).To(Succeed())
+			defer server.Stop()
+			replica.Config.Processors.App = remoteprocessor.NewClient(fmt.Sprintf("127.0.0.1:%d", port))
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// Port on which the remote processor server of the replica with ID 0 listens in the remote processor test.
+// The replica with ID id uses port remoteProcessorPort + id.
+const remoteProcessorPort = 22000
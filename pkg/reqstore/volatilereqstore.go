@@ -7,16 +7,33 @@ SPDX-License-Identifier: Apache-2.0
 package reqstore
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
+	"time"
 )
 
 // VolatileRequestStore is an in-memory implementation of modules.RequestStore.
-// All data is stored in RAM and the Sync() method does nothing.
-// TODO: implement pruning of old data.
+// All data is stored in RAM and the Sync() method does nothing (except for optionally simulating latency).
+// To simulate a real storage device in tests, latency can be injected into Sync
+// and failures into all modifying operations.
+//
+// If created using NewDedupVolatileRequestStore, the store is content-addressed:
+// request data is stored under the SHA-256 digest of the data itself and reference-counted,
+// such that identical payloads (e.g. retransmitted by the same client or submitted by different clients)
+// are only stored once.
 type VolatileRequestStore struct {
 
+	// If positive, each invocation of Sync blocks for this long, simulating the latency of fsync.
+	SyncLatency time.Duration
+
+	// If not nil, FailureHook is invoked at the start of each method modifying the RequestStore
+	// (PutRequest, SetAuthenticated, PutAuthenticator, StoreBatch, Prune) and of Sync, with the name of the method.
+	// If it returns a non-nil error, the method returns that error without having any effect,
+	// simulating a failure of the storage device.
+	FailureHook func(method string) error
+
 	// Stores request entries, indexed by request reference.
 	// Each entry holds all information (data, authentication, authenticator) about the referenced request.
 	requests map[string]*requestInfo
@@ -25,15 +42,36 @@ type VolatileRequestStore struct {
 	// The set of request digests is itself represented as a string map,
 	// where the key is the digest's string representation and the value is the digest as a byte slice.
 	idIndex map[string]map[string][]byte
+
+	// For each client, holds the set of request numbers for which any information is stored.
+	// Used for efficiently pruning the data of a client's old requests.
+	clientIndex map[t.ClientID]map[t.ReqNo]struct{}
+
+	// Stores the payloads of requests, indexed by the string representation of the payload's content digest.
+	// Only used in content-addressed (deduplicating) mode, nil otherwise.
+	payloads map[string]*payload
 }
 
 // Holds the data stored by a single entry of the VolatileRequestStore.
 type requestInfo struct {
-	data          []byte
+	// Request data. Not used in content-addressed mode.
+	data []byte
+
+	// Content digest of the request data (as a key to the payloads map). Only used in content-addressed mode.
+	// An empty string means that no data is stored for the request.
+	payloadKey string
+
 	authenticated bool
 	authenticator []byte
 }
 
+// Holds a single request payload stored in content-addressed mode,
+// along with the number of request entries referencing it.
+type payload struct {
+	data     []byte
+	refCount int
+}
+
 // Returns the string representation of a request reference.
 func requestKey(ref *requestpb.RequestRef) string {
 	return fmt.Sprintf("r-%d.%d.%x", ref.ClientId, ref.ReqNo, ref.Digest)
@@ -60,6 +98,14 @@ func (vrs *VolatileRequestStore) updateIdIndex(reqRef *requestpb.RequestRef) {
 	d := make([]byte, len(reqRef.Digest), len(reqRef.Digest))
 	copy(d, reqRef.Digest)
 	entry[fmt.Sprintf("%x", reqRef.Digest)] = d
+
+	// Add the request number to the client index.
+	reqNos, ok := vrs.clientIndex[t.ClientID(reqRef.ClientId)]
+	if !ok {
+		reqNos = make(map[t.ReqNo]struct{})
+		vrs.clientIndex[t.ClientID(reqRef.ClientId)] = reqNos
+	}
+	reqNos[t.ReqNo(reqRef.ReqNo)] = struct{}{}
 }
 
 // Looks up a stored entry and returns a pointer to it.
@@ -85,23 +131,109 @@ func (vrs *VolatileRequestStore) reqInfo(reqRef *requestpb.RequestRef) *requestI
 	return reqInfo
 }
 
+// setData stores a copy of the passed request data in the entry (potentially discarding old data).
+// In content-addressed mode, the entry references the payload with the data's content digest,
+// which is only allocated if not present yet.
+func (vrs *VolatileRequestStore) setData(reqInfo *requestInfo, data []byte) {
+
+	// Without deduplication, simply copy the data to the entry.
+	// Note that a full copy is made, so the stored data is not dependent on what happens with the original data.
+	if vrs.payloads == nil {
+		reqInfo.data = make([]byte, len(data), len(data))
+		copy(reqInfo.data, data)
+		return
+	}
+
+	// Look up the payload with the same content, storing a copy of the data if none is present.
+	digest := sha256.Sum256(data)
+	key := fmt.Sprintf("%x", digest)
+	p, ok := vrs.payloads[key]
+	if !ok {
+		p = &payload{data: make([]byte, len(data), len(data))}
+		copy(p.data, data)
+		vrs.payloads[key] = p
+	}
+
+	// Reference the new payload first and only then release the old one,
+	// such that the payload is not deleted if the entry is overwritten with the same data.
+	p.refCount++
+	vrs.releaseData(reqInfo)
+	reqInfo.payloadKey = key
+}
+
+// getData returns the request data stored in the entry (not a copy) or nil if the entry contains no data.
+func (vrs *VolatileRequestStore) getData(reqInfo *requestInfo) []byte {
+	if vrs.payloads == nil {
+		return reqInfo.data
+	} else if reqInfo.payloadKey == "" {
+		return nil
+	}
+	return vrs.payloads[reqInfo.payloadKey].data
+}
+
+// releaseData removes the reference of the entry to its payload in content-addressed mode,
+// deleting the payload if no other entry references it. Does nothing if not in content-addressed mode.
+func (vrs *VolatileRequestStore) releaseData(reqInfo *requestInfo) {
+	if vrs.payloads == nil || reqInfo.payloadKey == "" {
+		return
+	}
+
+	p := vrs.payloads[reqInfo.payloadKey]
+	p.refCount--
+	if p.refCount == 0 {
+		delete(vrs.payloads, reqInfo.payloadKey)
+	}
+	reqInfo.payloadKey = ""
+}
+
 func NewVolatileRequestStore() *VolatileRequestStore {
 	return &VolatileRequestStore{
-		requests: make(map[string]*requestInfo),
-		idIndex:  make(map[string]map[string][]byte),
+		requests:    make(map[string]*requestInfo),
+		idIndex:     make(map[string]map[string][]byte),
+		clientIndex: make(map[t.ClientID]map[t.ReqNo]struct{}),
 	}
 }
 
+// NewDedupVolatileRequestStore returns a new VolatileRequestStore in content-addressed mode,
+// storing each distinct request payload only once, regardless of how many requests it belongs to.
+func NewDedupVolatileRequestStore() *VolatileRequestStore {
+	vrs := NewVolatileRequestStore()
+	vrs.payloads = make(map[string]*payload)
+	return vrs
+}
+
+// PayloadStats returns the number of distinct request payloads stored and their total size in bytes.
+// Without deduplication, each request's data counts as a separate payload.
+func (vrs *VolatileRequestStore) PayloadStats() (count int, size int) {
+	if vrs.payloads != nil {
+		for _, p := range vrs.payloads {
+			count++
+			size += len(p.data)
+		}
+		return count, size
+	}
+
+	for _, reqInfo := range vrs.requests {
+		if reqInfo.data != nil {
+			count++
+			size += len(reqInfo.data)
+		}
+	}
+	return count, size
+}
+
 // PutRequest stores request the passed request data associated with the request reference.
 func (vrs *VolatileRequestStore) PutRequest(reqRef *requestpb.RequestRef, data []byte) error {
 
+	if err := vrs.injectFailure("PutRequest"); err != nil {
+		return err
+	}
+
 	// Look up entry for this request, creating a new one if necessary.
 	reqInfo := vrs.reqInfo(reqRef)
 
 	// Copy the request data to the entry (potentially discarding an old one).
-	// Note that a full copy is made, so the stored data is not dependent on what happens with the original data.
-	reqInfo.data = make([]byte, len(data), len(data))
-	copy(reqInfo.data, data)
+	vrs.setData(reqInfo, data)
 
 	return nil
 }
@@ -113,12 +245,12 @@ func (vrs *VolatileRequestStore) GetRequest(reqRef *requestpb.RequestRef) ([]byt
 	if reqInfo, ok := vrs.requests[requestKey(reqRef)]; ok {
 		// If an entry for the referenced request is present.
 
-		if reqInfo.data != nil {
+		if storedData := vrs.getData(reqInfo); storedData != nil {
 			// And if the referenced entry contains request data.
 
 			// Return a copy of the data (not a pointer to the data itself)
-			data := make([]byte, len(reqInfo.data), len(reqInfo.data))
-			copy(data, reqInfo.data)
+			data := make([]byte, len(storedData), len(storedData))
+			copy(data, storedData)
 			return data, nil
 		} else {
 			// If the entry exists, but contains no data, return an error.
@@ -139,6 +271,10 @@ func (vrs *VolatileRequestStore) GetRequest(reqRef *requestpb.RequestRef) ([]byt
 // (e.g. if the local node received the request over an authenticated channel but the request is not signed).
 func (vrs *VolatileRequestStore) SetAuthenticated(reqRef *requestpb.RequestRef) error {
 
+	if err := vrs.injectFailure("SetAuthenticated"); err != nil {
+		return err
+	}
+
 	// Look up entry for this request, creating a new one if necessary.
 	reqInfo := vrs.reqInfo(reqRef)
 
@@ -151,7 +287,7 @@ func (vrs *VolatileRequestStore) SetAuthenticated(reqRef *requestpb.RequestRef)
 // IsAuthenticated returns true if the request is authenticated, false otherwise.
 func (vrs *VolatileRequestStore) IsAuthenticated(reqRef *requestpb.RequestRef) (bool, error) {
 
-	if reqInfo, ok := vrs.requests[requestKey(reqRef)]; !ok {
+	if reqInfo, ok := vrs.requests[requestKey(reqRef)]; ok {
 		// If an entry for the referenced request is present, return the authenticated flag.
 		return reqInfo.authenticated, nil
 	} else {
@@ -165,6 +301,10 @@ func (vrs *VolatileRequestStore) IsAuthenticated(reqRef *requestpb.RequestRef) (
 // If an authenticator is already stored under the same reference, it will be overwritten.
 func (vrs *VolatileRequestStore) PutAuthenticator(reqRef *requestpb.RequestRef, auth []byte) error {
 
+	if err := vrs.injectFailure("PutAuthenticator"); err != nil {
+		return err
+	}
+
 	// Look up entry for this request, creating a new one if necessary.
 	reqInfo := vrs.reqInfo(reqRef)
 
@@ -181,7 +321,7 @@ func (vrs *VolatileRequestStore) PutAuthenticator(reqRef *requestpb.RequestRef,
 // If no authenticator is stored under the given reference, the returned error will be non-nil.
 func (vrs *VolatileRequestStore) GetAuthenticator(reqRef *requestpb.RequestRef) ([]byte, error) {
 
-	if reqInfo, ok := vrs.requests[requestKey(reqRef)]; !ok {
+	if reqInfo, ok := vrs.requests[requestKey(reqRef)]; ok {
 		// If an entry for the referenced request is present.
 
 		if reqInfo.authenticator != nil {
@@ -225,7 +365,107 @@ func (vrs *VolatileRequestStore) GetDigestsByID(clientId t.ClientID, reqNo t.Req
 	return digests, nil
 }
 
-// Sync does nothing in this volatile (in-memory) RequestStore implementation.
+// StoreBatch stores all the information about multiple requests at once.
+// If the FailureHook makes StoreBatch fail, none of the requests is stored.
+func (vrs *VolatileRequestStore) StoreBatch(requests []*requestpb.StoredRequest) error {
+
+	if err := vrs.injectFailure("StoreBatch"); err != nil {
+		return err
+	}
+
+	for _, req := range requests {
+
+		// Look up entry for this request, creating a new one if necessary.
+		reqInfo := vrs.reqInfo(req.RequestRef)
+
+		// Copy the request data to the entry (potentially discarding an old one).
+		vrs.setData(reqInfo, req.Data)
+
+		// Set the authenticated flag if applicable. (The flag is never cleared.)
+		if req.Authenticated {
+			reqInfo.authenticated = true
+		}
+
+		// Copy the authenticator to the entry if applicable (potentially discarding an old one).
+		if req.Authenticator != nil {
+			reqInfo.authenticator = make([]byte, len(req.Authenticator), len(req.Authenticator))
+			copy(reqInfo.authenticator, req.Authenticator)
+		}
+	}
+
+	return nil
+}
+
+// GetBatch returns the stored request data associated with each of the passed request references, in the same order.
+// If no data is stored under any of the references, the returned error will be non-nil.
+func (vrs *VolatileRequestStore) GetBatch(reqRefs []*requestpb.RequestRef) ([][]byte, error) {
+	data := make([][]byte, len(reqRefs))
+	for i, reqRef := range reqRefs {
+		var err error
+		if data[i], err = vrs.GetRequest(reqRef); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Prune deletes all information stored for requests of client clientID with request numbers lower than belowReqNo.
+func (vrs *VolatileRequestStore) Prune(clientID t.ClientID, belowReqNo t.ReqNo) error {
+
+	if err := vrs.injectFailure("Prune"); err != nil {
+		return err
+	}
+
+	// Only visit the request numbers actually stored for this client.
+	reqNos := vrs.clientIndex[clientID]
+	for reqNo := range reqNos {
+		if reqNo >= belowReqNo {
+			continue
+		}
+
+		// Delete the entries of all requests with this ID (there might be multiple ones with different digests)
+		// and remove the ID from the indices.
+		key := idKey(clientID, reqNo)
+		for _, digest := range vrs.idIndex[key] {
+			reqKey := requestKey(&requestpb.RequestRef{
+				ClientId: clientID.Pb(),
+				ReqNo:    reqNo.Pb(),
+				Digest:   digest,
+			})
+			if reqInfo, ok := vrs.requests[reqKey]; ok {
+				vrs.releaseData(reqInfo)
+				delete(vrs.requests, reqKey)
+			}
+		}
+		delete(vrs.idIndex, key)
+		delete(reqNos, reqNo)
+	}
+
+	// Remove the client from the index altogether if no more requests are stored for it.
+	if len(reqNos) == 0 {
+		delete(vrs.clientIndex, clientID)
+	}
+
+	return nil
+}
+
+// Sync does not persist anything in this volatile (in-memory) RequestStore implementation,
+// but blocks for SyncLatency (if positive).
 func (vrs *VolatileRequestStore) Sync() error {
+	if err := vrs.injectFailure("Sync"); err != nil {
+		return err
+	}
+
+	if vrs.SyncLatency > 0 {
+		time.Sleep(vrs.SyncLatency)
+	}
 	return nil
 }
+
+// injectFailure invokes the FailureHook, if any, and returns its result.
+func (vrs *VolatileRequestStore) injectFailure(method string) error {
+	if vrs.FailureHook == nil {
+		return nil
+	}
+	return vrs.FailureHook(method)
+}
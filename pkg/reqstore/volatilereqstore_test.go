@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reqstore_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The deduplicating request store test runs a deployment storing each request data only once.
+var _ = Describe("Deduplicating request store test", func() {
+
+	It("delivers all requests with a deduplicating request store", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     1,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			DedupRequests:   true,
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		deploytest.ExpectStopped(deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests)))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
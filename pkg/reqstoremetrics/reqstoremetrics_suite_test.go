@@ -0,0 +1,13 @@
+package reqstoremetrics_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestReqStoreMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ReqStoreMetrics Suite")
+}
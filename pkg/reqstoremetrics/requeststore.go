@@ -0,0 +1,245 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package reqstoremetrics provides a RequestStore wrapper that accounts for the size of stored request data
+// and the latency of the operations of the wrapped RequestStore.
+// It is meant to help operators determine whether request data (rather than consensus) is a bottleneck.
+package reqstoremetrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Metrics is a snapshot of the metrics of a RequestStore.
+// The counters and cumulative latencies only ever grow, while the gauges (stored and pending requests and bytes)
+// reflect the current contents of the RequestStore.
+type Metrics struct {
+
+	// Number of requests whose data is currently stored and their total size in bytes.
+	StoredRequests int
+	StoredBytes    int
+
+	// Number of requests whose data is stored, but that have not yet been committed, and their total size in bytes.
+	PendingRequests int
+	PendingBytes    int
+
+	// Number of requests (data) stored and retrieved, and the cumulative latency of doing so.
+	// A batch of requests counts as one store or get for the purpose of latency accounting.
+	Stores       uint64
+	StoreLatency time.Duration
+	Gets         uint64
+	GetLatency   time.Duration
+
+	// Number of invocations of Sync and their cumulative latency.
+	Syncs       uint64
+	SyncLatency time.Duration
+}
+
+// RequestStore wraps a modules.RequestStore and keeps track of its Metrics.
+// Unlike the methods of the wrapped RequestStore, Metrics can be called concurrently with any other method.
+type RequestStore struct {
+	modules.RequestStore
+
+	// Protects the metrics and the accounting data structures below.
+	mutex sync.Mutex
+
+	// The current metrics.
+	metrics Metrics
+
+	// For each client and request number, the sizes of the stored request data, indexed by request digest.
+	// Needed for subtracting the sizes of pruned requests.
+	sizes map[t.ClientID]map[t.ReqNo]map[string]int
+
+	// Set of stored requests that have not yet been committed, indexed by request reference.
+	pending map[string]struct{}
+}
+
+// NewRequestStore returns a new RequestStore wrapping the given RequestStore.
+// The wrapped RequestStore is expected to be empty, as data stored before wrapping it is not accounted for.
+func NewRequestStore(reqStore modules.RequestStore) *RequestStore {
+	return &RequestStore{
+		RequestStore: reqStore,
+		sizes:        make(map[t.ClientID]map[t.ReqNo]map[string]int),
+		pending:      make(map[string]struct{}),
+	}
+}
+
+// Metrics returns a snapshot of the RequestStore's current metrics.
+func (rs *RequestStore) Metrics() Metrics {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	return rs.metrics
+}
+
+// MarkCommitted informs the RequestStore that the referenced requests have been committed.
+// The data of committed requests is not accounted for as pending any more.
+func (rs *RequestStore) MarkCommitted(reqRefs []*requestpb.RequestRef) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	for _, reqRef := range reqRefs {
+		rs.removePending(reqRef)
+	}
+}
+
+// PutRequest stores the request data in the wrapped RequestStore and accounts for its size and latency.
+func (rs *RequestStore) PutRequest(reqRef *requestpb.RequestRef, data []byte) error {
+	start := time.Now()
+	if err := rs.RequestStore.PutRequest(reqRef, data); err != nil {
+		return err
+	}
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.metrics.Stores++
+	rs.metrics.StoreLatency += time.Since(start)
+	rs.setSize(reqRef, len(data))
+	return nil
+}
+
+// GetRequest retrieves the request data from the wrapped RequestStore and accounts for the latency.
+func (rs *RequestStore) GetRequest(reqRef *requestpb.RequestRef) ([]byte, error) {
+	start := time.Now()
+	data, err := rs.RequestStore.GetRequest(reqRef)
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.metrics.Gets++
+	rs.metrics.GetLatency += time.Since(start)
+	return data, err
+}
+
+// StoreBatch stores the requests in the wrapped RequestStore and accounts for their sizes and the latency.
+func (rs *RequestStore) StoreBatch(requests []*requestpb.StoredRequest) error {
+	start := time.Now()
+	if err := rs.RequestStore.StoreBatch(requests); err != nil {
+		return err
+	}
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.metrics.Stores += uint64(len(requests))
+	rs.metrics.StoreLatency += time.Since(start)
+	for _, req := range requests {
+		rs.setSize(req.RequestRef, len(req.Data))
+	}
+	return nil
+}
+
+// GetBatch retrieves the data of multiple requests from the wrapped RequestStore and accounts for the latency.
+func (rs *RequestStore) GetBatch(reqRefs []*requestpb.RequestRef) ([][]byte, error) {
+	start := time.Now()
+	data, err := rs.RequestStore.GetBatch(reqRefs)
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.metrics.Gets += uint64(len(reqRefs))
+	rs.metrics.GetLatency += time.Since(start)
+	return data, err
+}
+
+// Prune deletes the requests from the wrapped RequestStore and subtracts their sizes.
+func (rs *RequestStore) Prune(clientID t.ClientID, belowReqNo t.ReqNo) error {
+	if err := rs.RequestStore.Prune(clientID, belowReqNo); err != nil {
+		return err
+	}
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	reqNos := rs.sizes[clientID]
+	for reqNo, digests := range reqNos {
+		if reqNo >= belowReqNo {
+			continue
+		}
+		for digest := range digests {
+			rs.setSize(&requestpb.RequestRef{
+				ClientId: clientID.Pb(),
+				ReqNo:    reqNo.Pb(),
+				Digest:   []byte(digest),
+			}, -1)
+		}
+	}
+	return nil
+}
+
+// Sync syncs the wrapped RequestStore and accounts for the latency.
+func (rs *RequestStore) Sync() error {
+	start := time.Now()
+	err := rs.RequestStore.Sync()
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.metrics.Syncs++
+	rs.metrics.SyncLatency += time.Since(start)
+	return err
+}
+
+// setSize records the size of the data stored for the referenced request, replacing any previously recorded size.
+// A negative size means that the request's data has been deleted.
+// Must be called with the mutex held.
+func (rs *RequestStore) setSize(reqRef *requestpb.RequestRef, size int) {
+	clientID := t.ClientID(reqRef.ClientId)
+	reqNo := t.ReqNo(reqRef.ReqNo)
+	digest := string(reqRef.Digest)
+
+	// Look up the sizes recorded for this request ID, creating an entry if necessary.
+	if _, ok := rs.sizes[clientID]; !ok {
+		rs.sizes[clientID] = make(map[t.ReqNo]map[string]int)
+	}
+	digests, ok := rs.sizes[clientID][reqNo]
+	if !ok {
+		digests = make(map[string]int)
+		rs.sizes[clientID][reqNo] = digests
+	}
+
+	// Remove the previously recorded size (if any).
+	if oldSize, ok := digests[digest]; ok {
+		rs.metrics.StoredRequests--
+		rs.metrics.StoredBytes -= oldSize
+		rs.removePending(reqRef)
+		delete(digests, digest)
+	}
+
+	if size >= 0 {
+		// Record the new size. Newly stored data is pending until committed.
+		digests[digest] = size
+		rs.metrics.StoredRequests++
+		rs.metrics.StoredBytes += size
+		rs.pending[pendingKey(reqRef)] = struct{}{}
+		rs.metrics.PendingRequests++
+		rs.metrics.PendingBytes += size
+	} else if len(digests) == 0 {
+		// Clean up empty entries after deletion.
+		delete(rs.sizes[clientID], reqNo)
+		if len(rs.sizes[clientID]) == 0 {
+			delete(rs.sizes, clientID)
+		}
+	}
+}
+
+// removePending removes the referenced request from the set of pending requests, if present.
+// Must be called with the mutex held.
+func (rs *RequestStore) removePending(reqRef *requestpb.RequestRef) {
+	key := pendingKey(reqRef)
+	if _, ok := rs.pending[key]; !ok {
+		return
+	}
+	delete(rs.pending, key)
+	rs.metrics.PendingRequests--
+	rs.metrics.PendingBytes -= rs.sizes[t.ClientID(reqRef.ClientId)][t.ReqNo(reqRef.ReqNo)][string(reqRef.Digest)]
+}
+
+// pendingKey returns the string representation of a request reference.
+func pendingKey(reqRef *requestpb.RequestRef) string {
+	return fmt.Sprintf("%d.%d.%x", reqRef.ClientId, reqRef.ReqNo, reqRef.Digest)
+}
@@ -0,0 +1,60 @@
+package reqstoremetrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstoremetrics"
+)
+
+var _ = Describe("RequestStore", func() {
+	var reqStore *reqstoremetrics.RequestStore
+
+	// ref returns a reference to the request of client 1 with the given request number.
+	ref := func(reqNo uint64) *requestpb.RequestRef {
+		return &requestpb.RequestRef{ClientId: 1, ReqNo: reqNo, Digest: []byte{byte(reqNo)}}
+	}
+
+	BeforeEach(func() {
+		reqStore = reqstoremetrics.NewRequestStore(reqstore.NewVolatileRequestStore())
+	})
+
+	It("accounts for stored, pending, and pruned request data", func() {
+		Expect(reqStore.StoreBatch([]*requestpb.StoredRequest{
+			{RequestRef: ref(0), Data: make([]byte, 10)},
+			{RequestRef: ref(1), Data: make([]byte, 20)},
+		})).To(Succeed())
+		Expect(reqStore.PutRequest(ref(2), make([]byte, 30))).To(Succeed())
+
+		// Overwriting a request's data replaces its size.
+		Expect(reqStore.PutRequest(ref(2), make([]byte, 40))).To(Succeed())
+
+		metrics := reqStore.Metrics()
+		Expect(metrics.Stores).To(Equal(uint64(4)))
+		Expect(metrics.StoredRequests).To(Equal(3))
+		Expect(metrics.StoredBytes).To(Equal(70))
+		Expect(metrics.PendingRequests).To(Equal(3))
+		Expect(metrics.PendingBytes).To(Equal(70))
+
+		reqStore.MarkCommitted([]*requestpb.RequestRef{ref(0), ref(1)})
+		metrics = reqStore.Metrics()
+		Expect(metrics.StoredBytes).To(Equal(70))
+		Expect(metrics.PendingRequests).To(Equal(1))
+		Expect(metrics.PendingBytes).To(Equal(40))
+
+		Expect(reqStore.Prune(1, 2)).To(Succeed())
+		metrics = reqStore.Metrics()
+		Expect(metrics.StoredRequests).To(Equal(1))
+		Expect(metrics.StoredBytes).To(Equal(40))
+		Expect(metrics.PendingBytes).To(Equal(40))
+
+		_, err := reqStore.GetBatch([]*requestpb.RequestRef{ref(2)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reqStore.Sync()).To(Succeed())
+		metrics = reqStore.Metrics()
+		Expect(metrics.Gets).To(Equal(uint64(1)))
+		Expect(metrics.Syncs).To(Equal(uint64(1)))
+	})
+})
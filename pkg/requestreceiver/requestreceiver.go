@@ -30,6 +30,9 @@ type RequestReceiver struct {
 	// Error returned from the grpcServer.Serve() call (see Start() method).
 	grpcServerError error
 
+	// Closed when the grpcServer.Serve() call returns, after grpcServerError has been set.
+	grpcServerDone chan struct{}
+
 	// Logger use for all logging events of this RequestReceiver
 	logger logging.Logger
 }
@@ -120,8 +123,10 @@ func (rr *RequestReceiver) Start(port int) error {
 
 	// Start the gRPC server in a separate goroutine.
 	// When the server stops, it will write its exit error into gt.grpcServerError.
+	rr.grpcServerDone = make(chan struct{})
 	go func() {
 		rr.grpcServerError = rr.grpcServer.Serve(conn)
+		close(rr.grpcServerDone)
 	}()
 
 	// If we got all the way here, no error occurred.
@@ -137,6 +142,7 @@ func (rr *RequestReceiver) Stop() {
 
 	// Stop own gRPC server.
 	rr.grpcServer.Stop()
+	<-rr.grpcServerDone
 
 	rr.logger.Log(logging.LevelDebug, "Request receiver stopped.")
 }
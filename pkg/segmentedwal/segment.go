@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package segmentedwal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// Prefix and suffix of segment file names. The segment ID is between them.
+	segmentPrefix = "segment-"
+	segmentSuffix = ".wal"
+
+	// Length of the checksum preceding each record.
+	checksumLength = 4
+)
+
+// ErrCorrupted is returned by a SegmentIterator when it encounters a record that cannot be read.
+// This is expected to happen at the end of the last segment if the node crashed while writing it.
+var ErrCorrupted = errors.New("corrupted WAL segment")
+
+// SegmentInfo describes one segment file of the WAL.
+type SegmentInfo struct {
+
+	// Numeric ID of the segment. Segments are created with monotonically increasing IDs.
+	ID uint64
+
+	// Path to the segment file.
+	Path string
+
+	// Size of the segment file in bytes.
+	Size int64
+
+	// Number of entries contained in the segment.
+	NumEntries int
+
+	// The smallest and largest retention index of the entries contained in the segment.
+	// Only meaningful if NumEntries is greater than zero.
+	MinRetIndex uint64
+	MaxRetIndex uint64
+}
+
+// addEntry updates the segment information with a new entry.
+func (si *SegmentInfo) addEntry(retentionIndex uint64, recordSize int64) {
+	if si.NumEntries == 0 || retentionIndex < si.MinRetIndex {
+		si.MinRetIndex = retentionIndex
+	}
+	if si.NumEntries == 0 || retentionIndex > si.MaxRetIndex {
+		si.MaxRetIndex = retentionIndex
+	}
+	si.NumEntries++
+	si.Size += recordSize
+}
+
+// segmentPath returns the path of the segment file with the given ID in directory dir.
+func segmentPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%016d%s", segmentPrefix, id, segmentSuffix))
+}
+
+// ListSegments returns the IDs of all segments in directory dir, in increasing order.
+// Together with OpenSegment, it can be used by recovery and debugging tools
+// to inspect the WAL without opening it.
+func ListSegments(dir string) ([]uint64, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read WAL directory: %w", err)
+	}
+
+	ids := make([]uint64, 0)
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid segment file name: %s", name)
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// SegmentIterator reads the entries of a single segment file, in the order in which they have been appended.
+type SegmentIterator struct {
+	file   *os.File
+	reader *bufio.Reader
+
+	// Offset in the file just after the last successfully read entry.
+	offset int64
+}
+
+// OpenSegment returns a SegmentIterator over the entries of the segment with the given ID in directory dir.
+func OpenSegment(dir string, id uint64) (*SegmentIterator, error) {
+	file, err := os.Open(segmentPath(dir, id))
+	if err != nil {
+		return nil, fmt.Errorf("could not open WAL segment %d: %w", id, err)
+	}
+	return &SegmentIterator{
+		file:   file,
+		reader: bufio.NewReader(file),
+		offset: 0,
+	}, nil
+}
+
+// Next returns the next entry of the segment.
+// At the end of the segment, Next returns io.EOF.
+// If the next record cannot be read (e.g. it has only been partially written before a crash), Next returns ErrCorrupted.
+func (si *SegmentIterator) Next() (*WALEntry, error) {
+
+	// Read the length of the entry. A clean EOF here means the end of the segment.
+	length, err := binary.ReadUvarint(si.reader)
+	if err == io.EOF {
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: could not read record length: %v", ErrCorrupted, err)
+	}
+
+	// Read checksum and data.
+	record := make([]byte, checksumLength+int(length))
+	if _, err := io.ReadFull(si.reader, record); err != nil {
+		return nil, fmt.Errorf("%w: could not read record: %v", ErrCorrupted, err)
+	}
+	data := record[checksumLength:]
+	if binary.BigEndian.Uint32(record) != crc32.ChecksumIEEE(data) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+	}
+
+	// Decode the entry.
+	entry := &WALEntry{}
+	if err := proto.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("%w: could not unmarshal entry: %v", ErrCorrupted, err)
+	}
+
+	si.offset += int64(uvarintLength(length) + len(record))
+	return entry, nil
+}
+
+// Offset returns the offset in the segment file just after the last entry successfully returned by Next.
+func (si *SegmentIterator) Offset() int64 {
+	return si.offset
+}
+
+// Close closes the underlying segment file.
+func (si *SegmentIterator) Close() error {
+	return si.file.Close()
+}
+
+// encodeRecord returns the serialized form of an entry as stored in a segment file:
+// the length of the data (uvarint), the CRC-32 checksum of the data (4 bytes), and the data itself.
+func encodeRecord(entry *WALEntry) ([]byte, error) {
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal WAL entry: %w", err)
+	}
+
+	record := make([]byte, binary.MaxVarintLen64+checksumLength+len(data))
+	n := binary.PutUvarint(record, uint64(len(data)))
+	binary.BigEndian.PutUint32(record[n:], crc32.ChecksumIEEE(data))
+	copy(record[n+checksumLength:], data)
+	return record[:n+checksumLength+len(data)], nil
+}
+
+// uvarintLength returns the number of bytes of the uvarint encoding of x.
+func uvarintLength(x uint64) int {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return binary.PutUvarint(buf, x)
+}
@@ -0,0 +1,376 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package segmentedwal implements a WAL that stores its entries in a sequence of segment files of bounded size.
+// When the current segment reaches the configured maximal size, the WAL rotates to a new segment.
+// On truncation, whole segments that only contain entries below the retention index are deleted,
+// so that only segments containing entries at or above the stable checkpoint are retained
+// (the remaining entries below the retention index are skipped when loading the WAL).
+// The segments can be inspected independently of a running WAL using ListSegments and OpenSegment,
+// e.g., by recovery and debugging tools.
+package segmentedwal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Name of the file storing the retention index in the WAL directory.
+const retentionIndexFile = "retention"
+
+// Options holds the configuration parameters of the WAL.
+type Options struct {
+
+	// Maximal size of a segment file in bytes. When appending an entry would make the current segment exceed
+	// MaxSegmentSize, a new segment is created first. A segment containing a single entry can be larger.
+	// Must be positive.
+	MaxSegmentSize int64
+}
+
+// DefaultOptions returns the default WAL configuration.
+func DefaultOptions() *Options {
+	return &Options{
+		MaxSegmentSize: 64 * 1024 * 1024, // 64 MiB
+	}
+}
+
+// WAL is a write-ahead log, stored in segment files in a single directory.
+type WAL struct {
+	mutex sync.Mutex
+
+	// Directory containing the segment files.
+	dir string
+
+	// WAL configuration.
+	opts *Options
+
+	// Information about all the segments, in the order of their creation.
+	// The last segment is the one currently being written.
+	segments []*SegmentInfo
+
+	// The segment file currently being written and a buffered writer for it.
+	current *os.File
+	writer  *bufio.Writer
+
+	// Entries with retention index smaller than this one have been truncated.
+	// It is persisted in a separate file, since truncation only deletes whole segments.
+	retentionIndex t.WALRetIndex
+}
+
+// Open opens the WAL stored in directory dir, creating it if it does not exist.
+// Appending always starts in a new segment.
+// If the last existing segment ends with a partially written entry (e.g. due to a crash),
+// the partial entry is removed.
+func Open(dir string, opts *Options) (*WAL, error) {
+	if opts.MaxSegmentSize <= 0 {
+		return nil, fmt.Errorf("non-positive MaxSegmentSize: %d", opts.MaxSegmentSize)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create WAL directory: %w", err)
+	}
+
+	w := &WAL{
+		dir:      dir,
+		opts:     opts,
+		segments: make([]*SegmentInfo, 0),
+	}
+
+	// Load the retention index.
+	if err := w.loadRetentionIndex(); err != nil {
+		return nil, err
+	}
+
+	// Scan the existing segments.
+	ids, err := ListSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		info, err := w.scanSegment(id, i == len(ids)-1)
+		if err != nil {
+			return nil, err
+		}
+		w.segments = append(w.segments, info)
+	}
+
+	// Start a new segment.
+	nextID := uint64(0)
+	if len(ids) > 0 {
+		nextID = ids[len(ids)-1] + 1
+	}
+	if err := w.createSegment(nextID); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// IsEmpty returns true if the WAL contains no entries.
+func (w *WAL) IsEmpty() (bool, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, segment := range w.segments {
+		if segment.NumEntries > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Append appends an entry to the current segment, rotating to a new segment if necessary.
+func (w *WAL) Append(event *eventpb.Event, retentionIndex t.WALRetIndex) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	record, err := encodeRecord(&WALEntry{
+		RetentionIndex: retentionIndex.Pb(),
+		Event:          event,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Rotate to a new segment if the entry does not fit in the current one.
+	current := w.segments[len(w.segments)-1]
+	if current.NumEntries > 0 && current.Size+int64(len(record)) > w.opts.MaxSegmentSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+		current = w.segments[len(w.segments)-1]
+	}
+
+	if _, err := w.writer.Write(record); err != nil {
+		return fmt.Errorf("could not write WAL entry: %w", err)
+	}
+	current.addEntry(retentionIndex.Pb(), int64(len(record)))
+	return nil
+}
+
+// Truncate removes all entries with a retention index smaller than retentionIndex.
+// The retention index is persisted immediately and all segments (except for the current one)
+// containing only entries with smaller retention indices are deleted.
+func (w *WAL) Truncate(retentionIndex t.WALRetIndex) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// Never move the retention index backwards.
+	if retentionIndex <= w.retentionIndex {
+		return nil
+	}
+
+	// Persist the retention index first, so that truncated entries are never loaded again,
+	// even if the node crashes before deleting the segments.
+	if err := w.storeRetentionIndex(retentionIndex); err != nil {
+		return err
+	}
+	w.retentionIndex = retentionIndex
+
+	// Delete the segments that only contain truncated entries.
+	retained := make([]*SegmentInfo, 0, len(w.segments))
+	for i, segment := range w.segments {
+		if i < len(w.segments)-1 && (segment.NumEntries == 0 || segment.MaxRetIndex < retentionIndex.Pb()) {
+			if err := os.Remove(segment.Path); err != nil {
+				return fmt.Errorf("could not remove WAL segment %d: %w", segment.ID, err)
+			}
+		} else {
+			retained = append(retained, segment)
+		}
+	}
+	w.segments = retained
+
+	return nil
+}
+
+// Sync persists all appended entries to stable storage.
+func (w *WAL) Sync() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.sync()
+}
+
+// LoadAll applies forEach to all entries not truncated, in the order in which they have been appended.
+func (w *WAL) LoadAll(forEach func(index t.WALRetIndex, p *eventpb.Event)) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// Make sure all entries are written to the segment files.
+	if err := w.writer.Flush(); err != nil {
+		return fmt.Errorf("could not flush WAL segment: %w", err)
+	}
+
+	for _, segment := range w.segments {
+		if err := iterateSegment(w.dir, segment.ID, func(entry *WALEntry) {
+			if t.WALRetIndex(entry.RetentionIndex) >= w.retentionIndex {
+				forEach(t.WALRetIndex(entry.RetentionIndex), entry.Event)
+			}
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Segments returns information about all the segments of the WAL, in the order of their creation.
+func (w *WAL) Segments() []SegmentInfo {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	segments := make([]SegmentInfo, len(w.segments))
+	for i, segment := range w.segments {
+		segments[i] = *segment
+	}
+	return segments
+}
+
+// Close syncs and closes the WAL.
+func (w *WAL) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.sync(); err != nil {
+		return err
+	}
+	return w.current.Close()
+}
+
+// sync flushes the buffered entries to the current segment file and syncs it. The mutex must be held.
+func (w *WAL) sync() error {
+	if err := w.writer.Flush(); err != nil {
+		return fmt.Errorf("could not flush WAL segment: %w", err)
+	}
+	return w.current.Sync()
+}
+
+// rotate closes the current segment and creates a new one. The mutex must be held.
+func (w *WAL) rotate() error {
+	if err := w.sync(); err != nil {
+		return err
+	}
+	if err := w.current.Close(); err != nil {
+		return fmt.Errorf("could not close WAL segment: %w", err)
+	}
+	return w.createSegment(w.segments[len(w.segments)-1].ID + 1)
+}
+
+// createSegment creates a new segment file and makes it the current one.
+func (w *WAL) createSegment(id uint64) error {
+	path := segmentPath(w.dir, id)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create WAL segment %d: %w", id, err)
+	}
+
+	w.current = file
+	w.writer = bufio.NewWriter(file)
+	w.segments = append(w.segments, &SegmentInfo{ID: id, Path: path})
+	return nil
+}
+
+// scanSegment reads the whole segment with the given ID and returns information about it.
+// If last is true and the segment ends with a corrupted record, the segment file is truncated
+// to its last valid entry. Corruption of any other segment results in an error.
+func (w *WAL) scanSegment(id uint64, last bool) (*SegmentInfo, error) {
+	iter, err := OpenSegment(w.dir, id)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	info := &SegmentInfo{ID: id, Path: segmentPath(w.dir, id)}
+	for {
+		offset := iter.Offset()
+		entry, err := iter.Next()
+		if err == io.EOF {
+			return info, nil
+		} else if errors.Is(err, ErrCorrupted) && last {
+			if err := os.Truncate(info.Path, offset); err != nil {
+				return nil, fmt.Errorf("could not remove partial entry from WAL segment %d: %w", id, err)
+			}
+			return info, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read WAL segment %d: %w", id, err)
+		}
+		info.addEntry(entry.RetentionIndex, iter.Offset()-offset)
+	}
+}
+
+// iterateSegment applies forEach to all entries of the segment with the given ID.
+func iterateSegment(dir string, id uint64, forEach func(entry *WALEntry)) error {
+	iter, err := OpenSegment(dir, id)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for {
+		entry, err := iter.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not read WAL segment %d: %w", id, err)
+		}
+		forEach(entry)
+	}
+}
+
+// loadRetentionIndex reads the persisted retention index, if any.
+func (w *WAL) loadRetentionIndex() error {
+	data, err := ioutil.ReadFile(filepath.Join(w.dir, retentionIndexFile))
+	if os.IsNotExist(err) {
+		w.retentionIndex = 0
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not read WAL retention index: %w", err)
+	}
+
+	if len(data) != 8 {
+		return fmt.Errorf("invalid WAL retention index file length: %d", len(data))
+	}
+	w.retentionIndex = t.WALRetIndex(binary.BigEndian.Uint64(data))
+	return nil
+}
+
+// storeRetentionIndex atomically persists the retention index, writing it to a temporary file first.
+func (w *WAL) storeRetentionIndex(retentionIndex t.WALRetIndex) error {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, retentionIndex.Pb())
+
+	tmpPath := filepath.Join(w.dir, retentionIndexFile+".tmp")
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create WAL retention index file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("could not write WAL retention index: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("could not sync WAL retention index: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("could not close WAL retention index file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(w.dir, retentionIndexFile)); err != nil {
+		return fmt.Errorf("could not replace WAL retention index file: %w", err)
+	}
+	return nil
+}
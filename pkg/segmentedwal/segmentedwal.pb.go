@@ -0,0 +1,91 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: segmentedwal/segmentedwal.proto
+
+package segmentedwal
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	eventpb "github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type WALEntry struct {
+	RetentionIndex       uint64         `protobuf:"varint,1,opt,name=retention_index,json=retentionIndex,proto3" json:"retention_index,omitempty"`
+	Event                *eventpb.Event `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *WALEntry) Reset()         { *m = WALEntry{} }
+func (m *WALEntry) String() string { return proto.CompactTextString(m) }
+func (*WALEntry) ProtoMessage()    {}
+func (*WALEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad3d54a8baead8d0, []int{0}
+}
+
+func (m *WALEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WALEntry.Unmarshal(m, b)
+}
+func (m *WALEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WALEntry.Marshal(b, m, deterministic)
+}
+func (m *WALEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WALEntry.Merge(m, src)
+}
+func (m *WALEntry) XXX_Size() int {
+	return xxx_messageInfo_WALEntry.Size(m)
+}
+func (m *WALEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_WALEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WALEntry proto.InternalMessageInfo
+
+func (m *WALEntry) GetRetentionIndex() uint64 {
+	if m != nil {
+		return m.RetentionIndex
+	}
+	return 0
+}
+
+func (m *WALEntry) GetEvent() *eventpb.Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*WALEntry)(nil), "segmentedwal.WALEntry")
+}
+
+func init() { proto.RegisterFile("segmentedwal/segmentedwal.proto", fileDescriptor_ad3d54a8baead8d0) }
+
+var fileDescriptor_ad3d54a8baead8d0 = []byte{
+	// 178 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2f, 0x4e, 0x4d, 0xcf,
+	0x4d, 0xcd, 0x2b, 0x49, 0x4d, 0x29, 0x4f, 0xcc, 0xd1, 0x47, 0xe6, 0xe8, 0x15, 0x14, 0xe5, 0x97,
+	0xe4, 0x0b, 0xf1, 0x20, 0x8b, 0x49, 0x89, 0xa6, 0x96, 0xa5, 0xe6, 0x95, 0x14, 0x24, 0xe9, 0x43,
+	0x69, 0x88, 0x22, 0xa5, 0x48, 0x2e, 0x8e, 0x70, 0x47, 0x1f, 0xd7, 0xbc, 0x92, 0xa2, 0x4a, 0x21,
+	0x75, 0x2e, 0xfe, 0xa2, 0xd4, 0x92, 0xd4, 0xbc, 0x92, 0xcc, 0xfc, 0xbc, 0xf8, 0xcc, 0xbc, 0x94,
+	0xd4, 0x0a, 0x09, 0x46, 0x05, 0x46, 0x0d, 0x96, 0x20, 0x3e, 0xb8, 0xb0, 0x27, 0x48, 0x54, 0x48,
+	0x85, 0x8b, 0x15, 0x6c, 0x8a, 0x04, 0x93, 0x02, 0xa3, 0x06, 0xb7, 0x11, 0x9f, 0x1e, 0xcc, 0x4c,
+	0x57, 0x10, 0x1d, 0x04, 0x91, 0x74, 0x32, 0x8d, 0x32, 0x4e, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2,
+	0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0xa8, 0x2c, 0x48, 0x2d, 0xca, 0x49, 0x4d, 0x49, 0x4f, 0x2d, 0xd2,
+	0xcd, 0x49, 0x4c, 0x2a, 0xd6, 0xcf, 0xcd, 0x2c, 0x4a, 0x4a, 0x2b, 0xd1, 0x2f, 0xc8, 0x4e, 0x47,
+	0x71, 0x7c, 0x12, 0x1b, 0xd8, 0x61, 0xc6, 0x80, 0x01, 0x00, 0xa9, 0x7a, 0xed, 0xce, 0xe0, 0x00,
+	0x00, 0x00,
+}
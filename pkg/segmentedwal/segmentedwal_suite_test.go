@@ -0,0 +1,13 @@
+package segmentedwal_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSegmentedWAL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SegmentedWAL Suite")
+}
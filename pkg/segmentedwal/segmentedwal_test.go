@@ -0,0 +1,87 @@
+package segmentedwal_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/segmentedwal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("WAL", func() {
+	var (
+		dir string
+		wal *segmentedwal.WAL
+	)
+
+	// loadRetIndices returns the retention indices of all entries loaded from the WAL.
+	loadRetIndices := func() []t.WALRetIndex {
+		retIndices := make([]t.WALRetIndex, 0)
+		Expect(wal.LoadAll(func(retIdx t.WALRetIndex, _ *eventpb.Event) {
+			retIndices = append(retIndices, retIdx)
+		})).To(Succeed())
+		return retIndices
+	}
+
+	// appendEntries appends one entry with each of the given retention indices.
+	appendEntries := func(retIndices ...t.WALRetIndex) {
+		for _, retIdx := range retIndices {
+			Expect(wal.Append(events.Tick(), retIdx)).To(Succeed())
+		}
+		Expect(wal.Sync()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "segmentedwal")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Small enough segments to only fit a single entry.
+		wal, err = segmentedwal.Open(dir, &segmentedwal.Options{MaxSegmentSize: 1})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(wal.Close()).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("rotates segments and retains only segments at or above the retention index", func() {
+		appendEntries(0, 0, 1, 2)
+		Expect(wal.Segments()).To(HaveLen(4))
+
+		Expect(wal.Truncate(1)).To(Succeed())
+		Expect(wal.Segments()).To(HaveLen(2))
+		Expect(loadRetIndices()).To(Equal([]t.WALRetIndex{1, 2}))
+
+		ids, err := segmentedwal.ListSegments(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids).To(HaveLen(2))
+	})
+
+	It("recovers entries and retention index after reopening", func() {
+		appendEntries(0, 1, 1, 2)
+		Expect(wal.Truncate(1)).To(Succeed())
+		Expect(wal.Close()).To(Succeed())
+
+		// Simulate a crash in the middle of writing an entry.
+		segments := wal.Segments()
+		f, err := os.OpenFile(segments[len(segments)-1].Path, os.O_APPEND|os.O_WRONLY, 0600)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Write([]byte{42, 1, 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		wal, err = segmentedwal.Open(dir, segmentedwal.DefaultOptions())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loadRetIndices()).To(Equal([]t.WALRetIndex{1, 1, 2}))
+
+		appendEntries(3)
+		Expect(loadRetIndices()).To(Equal([]t.WALRetIndex{1, 1, 2, 3}))
+	})
+})
@@ -8,6 +8,8 @@ package serializing
 
 import (
 	"encoding/binary"
+	"io"
+
 	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
 )
 
@@ -25,3 +27,23 @@ func RequestForHash(req *requestpb.Request) [][]byte {
 
 	return [][]byte{clientIDBuf, reqNoBuf, req.Data}
 }
+
+// WriteRequestForHash writes the same data as returned by RequestForHash directly to w (usually a hash.Hash),
+// such that a request digest can be computed without allocating the intermediate slices.
+// Writing to w = h for some hash.Hash h is equivalent to writing all the slices returned by RequestForHash to h.
+// Returns the first error returned by w.
+func WriteRequestForHash(w io.Writer, req *requestpb.Request) error {
+
+	// Encode the client ID and request number into a buffer on the stack.
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], req.ClientId)
+	binary.LittleEndian.PutUint64(buf[8:], req.ReqNo)
+
+	// Note that the signature is *not* part of the hashed data.
+
+	if _, err := w.Write(buf[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(req.Data)
+	return err
+}
@@ -16,7 +16,9 @@ SPDX-License-Identifier: Apache-2.0
 package simplewal
 
 import (
+	"encoding/binary"
 	"fmt"
+	"github.com/hyperledger-labs/mirbft/pkg/encryption"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
 	"sync"
@@ -39,6 +41,21 @@ type WAL struct {
 	// Otherwise it could be completely ephemeral.
 	// TODO: Implement persisting and loading the retentionIndex
 	retentionIndex t.WALRetIndex
+
+	// If not nil, used to encrypt all entries before writing them to the underlying log
+	// (and to decrypt them when loading).
+	aead *encryption.AEAD
+}
+
+// OpenEncrypted opens a WAL (just like Open), the entries of which are encrypted at rest using the given AEAD.
+// A WAL written by an encrypted WAL can only be loaded by an encrypted WAL using the same keys.
+func OpenEncrypted(path string, aead *encryption.AEAD) (*WAL, error) {
+	w, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	w.aead = aead
+	return w, nil
 }
 
 func Open(path string) (*WAL, error) {
@@ -97,15 +114,9 @@ func (w *WAL) LoadAll(forEach func(index t.WALRetIndex, p *eventpb.Event)) error
 	}
 
 	for i := firstIndex; i <= lastIndex; i++ {
-		data, err := w.log.Read(i)
+		result, err := w.read(i)
 		if err != nil {
-			return errors.WithMessagef(err, "could not read index %d", i)
-		}
-
-		result := &WALEntry{}
-		err = proto.Unmarshal(data, result)
-		if err != nil {
-			return errors.WithMessage(err, "error decoding to proto, is the WAL corrupt?")
+			return err
 		}
 
 		if t.WALRetIndex(result.RetentionIndex) >= w.retentionIndex {
@@ -116,6 +127,26 @@ func (w *WAL) LoadAll(forEach func(index t.WALRetIndex, p *eventpb.Event)) error
 	return nil
 }
 
+// read reads and decodes the entry at the given index of the underlying log.
+func (w *WAL) read(index uint64) (*WALEntry, error) {
+	data, err := w.log.Read(index)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not read index %d", index)
+	}
+
+	if w.aead != nil {
+		if data, err = w.aead.Open(data, indexBytes(index)); err != nil {
+			return nil, errors.WithMessagef(err, "could not decrypt entry at index %d", index)
+		}
+	}
+
+	result := &WALEntry{}
+	if err := proto.Unmarshal(data, result); err != nil {
+		return nil, errors.WithMessage(err, "error decoding to proto, is the WAL corrupt?")
+	}
+	return result, nil
+}
+
 func (w *WAL) write(index uint64, entry *WALEntry) error {
 
 	// Check whether the index corresponds to the next index
@@ -128,6 +159,13 @@ func (w *WAL) write(index uint64, entry *WALEntry) error {
 		return errors.WithMessage(err, "could not marshal")
 	}
 
+	// Encrypt the entry if configured to do so, binding the ciphertext to its position in the underlying log.
+	if w.aead != nil {
+		if data, err = w.aead.Seal(data, indexBytes(index+1)); err != nil {
+			return errors.WithMessage(err, "could not encrypt")
+		}
+	}
+
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
@@ -147,8 +185,40 @@ func (w *WAL) Truncate(retentionIndex t.WALRetIndex) error {
 	defer w.mutex.Unlock()
 
 	// TODO: Persist retention index first, probably in a separate file in the same directory.
+	w.retentionIndex = retentionIndex
 
-	return w.log.TruncateFront(retentionIndex.Pb())
+	firstIndex, err := w.log.FirstIndex()
+	if err != nil {
+		return errors.WithMessage(err, "could not read first index")
+	}
+	lastIndex, err := w.log.LastIndex()
+	if err != nil {
+		return errors.WithMessage(err, "could not read last index")
+	}
+	if firstIndex == 0 {
+		// WAL is empty
+		return nil
+	}
+
+	// Find the first entry that must be retained.
+	// Entries are not necessarily ordered by retention index, so only the prefix of the log
+	// consisting of entries with a smaller retention index is removed.
+	// The underlying log cannot remove its last entry, which is skipped on loading if outdated.
+	newFirstIndex := firstIndex
+	for ; newFirstIndex < lastIndex; newFirstIndex++ {
+		entry, err := w.read(newFirstIndex)
+		if err != nil {
+			return err
+		}
+		if t.WALRetIndex(entry.RetentionIndex) >= retentionIndex {
+			break
+		}
+	}
+
+	if newFirstIndex == firstIndex {
+		return nil
+	}
+	return w.log.TruncateFront(newFirstIndex)
 }
 
 func (w *WAL) Sync() error {
@@ -158,3 +228,11 @@ func (w *WAL) Sync() error {
 func (w *WAL) Close() error {
 	return w.log.Close()
 }
+
+// indexBytes returns the serialized index of an entry in the underlying log,
+// used as additional authenticated data when encrypting the entry.
+func indexBytes(index uint64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, index)
+	return data
+}
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simplewal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// VolatileWAL is an in-memory implementation of modules.WAL.
+// All entries are stored in RAM and nothing is ever written to disk.
+// It is meant for testing, where the contents of the WAL only need to survive a restart of the Node
+// (by passing the same VolatileWAL instance to the restarted Node), but not of the whole process.
+// To simulate a real storage device, latency can be injected into Sync and failures into all modifying operations.
+type VolatileWAL struct {
+	mutex sync.Mutex
+
+	// Entries of the WAL, in the order in which they have been appended.
+	entries []*WALEntry
+
+	// Entries with retention index smaller than this one have been truncated.
+	retentionIndex t.WALRetIndex
+
+	// If positive, each invocation of Sync blocks for this long, simulating the latency of fsync.
+	SyncLatency time.Duration
+
+	// If not nil, FailureHook is invoked at the start of Append, Truncate, and Sync with the name of the method.
+	// If it returns a non-nil error, the method returns that error without having any effect,
+	// simulating a failure of the storage device.
+	FailureHook func(method string) error
+}
+
+// NewVolatileWAL returns a new empty VolatileWAL.
+func NewVolatileWAL() *VolatileWAL {
+	return &VolatileWAL{
+		entries:        make([]*WALEntry, 0),
+		retentionIndex: 0,
+	}
+}
+
+// IsEmpty returns true if the WAL contains no entries.
+func (w *VolatileWAL) IsEmpty() (bool, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return len(w.entries) == 0, nil
+}
+
+// Append appends an entry with a retentionIndex to the WAL.
+// The entry is copied, so the stored entry is not dependent on what happens with the original event.
+func (w *VolatileWAL) Append(event *eventpb.Event, retentionIndex t.WALRetIndex) error {
+	if err := w.injectFailure("Append"); err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.entries = append(w.entries, &WALEntry{
+		RetentionIndex: retentionIndex.Pb(),
+		Event:          proto.Clone(event).(*eventpb.Event),
+	})
+	return nil
+}
+
+// Truncate removes all entries from the WAL that have been appended
+// with a retentionIndex smaller than the specified one.
+func (w *VolatileWAL) Truncate(retentionIndex t.WALRetIndex) error {
+	if err := w.injectFailure("Truncate"); err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.retentionIndex = retentionIndex
+
+	retained := make([]*WALEntry, 0, len(w.entries))
+	for _, entry := range w.entries {
+		if t.WALRetIndex(entry.RetentionIndex) >= retentionIndex {
+			retained = append(retained, entry)
+		}
+	}
+	w.entries = retained
+
+	return nil
+}
+
+// Sync does not persist anything, but blocks for SyncLatency (if positive).
+func (w *VolatileWAL) Sync() error {
+	if err := w.injectFailure("Sync"); err != nil {
+		return err
+	}
+
+	if w.SyncLatency > 0 {
+		time.Sleep(w.SyncLatency)
+	}
+	return nil
+}
+
+// LoadAll applies forEach to (copies of) all WAL entries in the order in which they have been appended.
+func (w *VolatileWAL) LoadAll(forEach func(index t.WALRetIndex, p *eventpb.Event)) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, entry := range w.entries {
+		forEach(t.WALRetIndex(entry.RetentionIndex), proto.Clone(entry.Event).(*eventpb.Event))
+	}
+	return nil
+}
+
+// Close does nothing in this volatile (in-memory) WAL implementation.
+// In particular, the content of the WAL is retained and the WAL can still be used after Close returns.
+func (w *VolatileWAL) Close() error {
+	return nil
+}
+
+// injectFailure invokes the FailureHook, if any, and returns its result.
+func (w *VolatileWAL) injectFailure(method string) error {
+	if w.FailureHook == nil {
+		return nil
+	}
+	return w.FailureHook(method)
+}
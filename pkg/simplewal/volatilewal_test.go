@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simplewal_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The volatile WAL test runs a deployment keeping its WAL (and request store) in memory.
+var _ = Describe("Volatile WAL test", func() {
+
+	It("delivers all requests with in-memory storage", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     1,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			VolatileWAL:     true,
+			SyncLatency:     time.Millisecond,
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		deploytest.ExpectStopped(deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests)))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
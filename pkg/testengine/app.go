@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+)
+
+// App is the application replicated by the simulated nodes.
+// It records all the requests delivered to it, in the order of delivery,
+// such that the logs of different nodes can be compared.
+type App struct {
+
+	// The requests delivered so far, in the order of delivery.
+	Requests []*requestpb.RequestRef
+
+	// Number of batches delivered so far.
+	Batches int
+
+	// The history of the node, shared by all incarnations of the node. Nil if the App is used outside an Engine.
+	history *history
+}
+
+// Apply appends the requests of a batch to the log.
+func (app *App) Apply(batch *requestpb.Batch) error {
+	if app.history != nil {
+		for i, reqRef := range batch.Requests {
+			app.history.delivered(len(app.Requests)+i, reqRef)
+		}
+	}
+	app.Requests = append(app.Requests, batch.Requests...)
+	app.Batches++
+	return nil
+}
+
+// Snapshot returns the serialized log of delivered requests.
+func (app *App) Snapshot() ([]byte, error) {
+	snapshot, err := proto.Marshal(&requestpb.Batch{Requests: app.Requests})
+	if err != nil {
+		return nil, err
+	}
+	if app.history != nil {
+		app.history.snapshotted(len(app.Requests), snapshot)
+	}
+	return snapshot, nil
+}
+
+// RestoreState replaces the log of delivered requests by the one contained in the snapshot.
+// The number of delivered batches is not part of the state and is left unchanged.
+func (app *App) RestoreState(snapshot []byte) error {
+	var batch requestpb.Batch
+	if err := proto.Unmarshal(snapshot, &batch); err != nil {
+		return err
+	}
+	app.Requests = batch.Requests
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"math/rand"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// A Behavior makes a simulated node Byzantine (see Spec.Byzantine).
+// The node runs the protocol like any other node, but all the messages it sends pass through its Behavior,
+// which can alter, multiply, or suppress them.
+type Behavior interface {
+
+	// Send is invoked for each message msg the node sends to node dest
+	// and returns the messages that are actually sent to dest instead.
+	// Send may modify msg, which is a copy owned by the Behavior.
+	// All random decisions must be taken using rnd, such that the behavior can be reproduced from the simulation seed.
+	Send(dest t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []*messagepb.Message
+}
+
+// Behaviors returns a Behavior combining all the given behaviors, applied in the given order.
+// Each message produced by a behavior is passed to the following one.
+func Behaviors(behaviors ...Behavior) Behavior {
+	return behaviorChain(behaviors)
+}
+
+// behaviorChain is the Behavior returned by Behaviors.
+type behaviorChain []Behavior
+
+// Send passes the message through all the behaviors of the chain.
+func (bc behaviorChain) Send(dest t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []*messagepb.Message {
+	msgs := []*messagepb.Message{msg}
+	for _, behavior := range bc {
+		next := make([]*messagepb.Message, 0, len(msgs))
+		for _, m := range msgs {
+			next = append(next, behavior.Send(dest, m, rnd)...)
+		}
+		msgs = next
+	}
+	return msgs
+}
+
+// EquivocatePreprepares makes a leader propose different batches for the same sequence number to different nodes.
+// The nodes in Victims receive preprepares whose batches have the order of their requests reversed
+// and the last request removed. All the other nodes receive the original proposals.
+type EquivocatePreprepares struct {
+	Victims []t.NodeID
+}
+
+// Send alters the preprepares sent to the victims.
+func (ep *EquivocatePreprepares) Send(dest t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []*messagepb.Message {
+	preprepare := msg.GetIss().GetSb().GetMsg().GetPbftPreprepare()
+	if preprepare == nil || preprepare.Batch == nil || !containsNodeID(ep.Victims, dest) {
+		return []*messagepb.Message{msg}
+	}
+
+	requests := preprepare.Batch.Requests
+	if len(requests) > 0 {
+		requests = requests[:len(requests)-1]
+	}
+	conflicting := make([]*requestpb.RequestRef, len(requests))
+	for i, reqRef := range requests {
+		conflicting[len(requests)-1-i] = reqRef
+	}
+	preprepare.Batch = &requestpb.Batch{Requests: conflicting}
+	return []*messagepb.Message{msg}
+}
+
+// ConflictingCheckpoints makes a node send Checkpoint messages that do not match the checkpoints actually reached.
+// Each Checkpoint message is replaced by one announcing a later sequence number (shifted by SNOffset)
+// and a different configuration version, and by another one from a future epoch.
+type ConflictingCheckpoints struct {
+	SNOffset uint64
+}
+
+// Send replaces the Checkpoint messages.
+func (cc *ConflictingCheckpoints) Send(dest t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []*messagepb.Message {
+	checkpoint := msg.GetIss().GetCheckpoint()
+	if checkpoint == nil {
+		return []*messagepb.Message{msg}
+	}
+
+	shifted := proto.Clone(checkpoint).(*isspb.Checkpoint)
+	shifted.Sn += cc.SNOffset
+	shifted.ConfigEpoch++
+	future := proto.Clone(checkpoint).(*isspb.Checkpoint)
+	future.Epoch++
+	return []*messagepb.Message{checkpointMessage(shifted), checkpointMessage(future)}
+}
+
+// AckSpam makes a node flood the other nodes with acknowledgments:
+// with each message, it also sends Count Checkpoint messages for random (mostly future) sequence numbers and epochs,
+// as well as Count acknowledgments of the retransmission layer (which no correct node expects at this level).
+type AckSpam struct {
+	Count int
+
+	// Upper bounds (inclusive) on the epochs and sequence numbers of the bogus Checkpoint messages.
+	MaxEpoch uint64
+	MaxSN    uint64
+}
+
+// Send adds the bogus acknowledgments to the message.
+func (as *AckSpam) Send(dest t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []*messagepb.Message {
+	msgs := []*messagepb.Message{msg}
+	for i := 0; i < as.Count; i++ {
+		msgs = append(msgs,
+			checkpointMessage(&isspb.Checkpoint{
+				Epoch: uint64(rnd.Int63n(int64(as.MaxEpoch) + 1)),
+				Sn:    uint64(rnd.Int63n(int64(as.MaxSN) + 1)),
+			}),
+			&messagepb.Message{Type: &messagepb.Message_ReliableAck{ReliableAck: &messagepb.ReliableAck{
+				Session: rnd.Uint64(),
+				Ranges:  []*messagepb.SnRange{{From: 0, To: rnd.Uint64()}},
+			}}},
+		)
+	}
+	return msgs
+}
+
+// WithholdCommits makes a node withhold the messages by which it contributes to committing:
+// its Checkpoint messages (sent to nobody) and, if it is a leader, its proposals to the nodes in Victims.
+type WithholdCommits struct {
+	Victims []t.NodeID
+}
+
+// Send drops the withheld messages.
+func (wc *WithholdCommits) Send(dest t.NodeID, msg *messagepb.Message, rnd *rand.Rand) []*messagepb.Message {
+	if msg.GetIss().GetCheckpoint() != nil {
+		return nil
+	}
+	if msg.GetIss().GetSb().GetMsg().GetPbftPreprepare() != nil && containsNodeID(wc.Victims, dest) {
+		return nil
+	}
+	return []*messagepb.Message{msg}
+}
+
+// checkpointMessage wraps a Checkpoint in a Message.
+func checkpointMessage(checkpoint *isspb.Checkpoint) *messagepb.Message {
+	return &messagepb.Message{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{
+		Type: &isspb.ISSMessage_Checkpoint{Checkpoint: checkpoint},
+	}}}
+}
+
+// containsNodeID returns true if nodeID is in nodeIDs.
+func containsNodeID(nodeIDs []t.NodeID, nodeID t.NodeID) bool {
+	for _, n := range nodeIDs {
+		if n == nodeID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// ChaosBounds configure the generation of chaos schedules (see GenerateChaos).
+type ChaosBounds struct {
+
+	// The simulated system. Each client keeps submitting a request every ClientRequestInterval
+	// throughout the fault period.
+	NumNodes              int
+	NumClients            int
+	ClientRequestInterval time.Duration
+	SegmentLength         int
+
+	// Virtual duration of the fault period, within which all faults start.
+	Duration time.Duration
+
+	// Mean virtual time between the starts of two subsequent faults. The times are exponentially distributed.
+	MeanFaultInterval time.Duration
+
+	// Maximal duration of a partition, of the downtime of a crashed leader, and of a period of slow disk, respectively.
+	MaxFaultDuration time.Duration
+
+	// Maximal latency of a slow disk.
+	MaxDiskLatency time.Duration
+
+	// Leaders are crashed when proposing sequence numbers up to MaxCrashSn,
+	// which should be reached within the fault period.
+	MaxCrashSn t.SeqNr
+}
+
+// DefaultChaosBounds returns bounds producing schedules of 10 seconds of virtual time,
+// in which 4 nodes order the requests of 2 clients while a fault starts every half a second on average.
+func DefaultChaosBounds() *ChaosBounds {
+	return &ChaosBounds{
+		NumNodes:              4,
+		NumClients:            2,
+		ClientRequestInterval: 10 * time.Millisecond,
+		SegmentLength:         4,
+		Duration:              10 * time.Second,
+		MeanFaultInterval:     500 * time.Millisecond,
+		MaxFaultDuration:      500 * time.Millisecond,
+		MaxDiskLatency:        50 * time.Millisecond,
+		MaxCrashSn:            1000,
+	}
+}
+
+// GenerateChaos returns a random Schedule within bounds, fully determined by seed,
+// consisting of a steady load and a dense sequence of faults: healing partitions of the nodes in a majority
+// and a minority (see TimedPartition), crashes of the leaders proposing particular sequence numbers
+// (each followed by a restart), and periods of slow disk of single nodes. The faults may overlap.
+// Such schedules are meant for soak tests (see Soak), in which only safety is expected to hold,
+// as the messages lost while a leader is down are never retransmitted.
+func GenerateChaos(bounds *ChaosBounds, seed int64) *Schedule {
+	rnd := rand.New(rand.NewSource(seed))
+	s := &Schedule{
+		Seed:                  rnd.Int63(),
+		NumNodes:              bounds.NumNodes,
+		NumClients:            bounds.NumClients,
+		RequestsPerClient:     int(bounds.Duration / bounds.ClientRequestInterval),
+		ClientRequestInterval: bounds.ClientRequestInterval,
+		MinLatency:            time.Millisecond,
+		MaxLatency:            10 * time.Millisecond,
+		SegmentLength:         bounds.SegmentLength,
+	}
+
+	crashedSNs := make(map[t.SeqNr]struct{})
+	for start := randomExp(bounds.MeanFaultInterval, rnd); start < bounds.Duration; start += randomExp(bounds.MeanFaultInterval, rnd) {
+		duration := time.Duration(1 + rnd.Int63n(int64(bounds.MaxFaultDuration)))
+
+		switch rnd.Intn(3) {
+		case 0:
+			if s.NumNodes == 1 {
+				continue
+			}
+			nodes := rnd.Perm(s.NumNodes)
+			majority := s.NumNodes - s.f()
+			s.Partitions = append(s.Partitions, TimedPartition{
+				Start:   start,
+				End:     start + duration,
+				Groups:  [][]t.NodeID{nodeIDs(nodes[:majority]), nodeIDs(nodes[majority:])},
+				Healing: true,
+			})
+		case 1:
+			sn := t.SeqNr(1 + rnd.Int63n(int64(bounds.MaxCrashSn)))
+			if _, ok := crashedSNs[sn]; ok {
+				continue
+			}
+			crashedSNs[sn] = struct{}{}
+			s.LeaderCrashes = append(s.LeaderCrashes, faults.LeaderCrash{Sn: sn, Downtime: duration})
+		case 2:
+			s.SlowDisks = append(s.SlowDisks, faults.SlowDisk{
+				Node:    t.NodeID(rnd.Intn(s.NumNodes)),
+				Start:   start,
+				End:     start + duration,
+				Latency: time.Duration(1 + rnd.Int63n(int64(bounds.MaxDiskLatency))),
+			})
+		}
+	}
+
+	return s
+}
+
+// Soak simulates chaos schedules generated within bounds (see GenerateChaos) from the consecutive seeds
+// starting at seed, for as long as the (real) time budget lasts, but at least one schedule.
+// Each schedule is simulated for twice its fault period, such that the system can settle after the last fault,
+// and must satisfy all the given properties (Safety, if none are given).
+// The seed of each schedule is logged before the schedule is simulated,
+// such that a failure can be reproduced by generating the schedule from the same seed.
+// Soak returns an error wrapping the violation of the first property that does not hold,
+// naming the seed of the schedule that violated it.
+func Soak(bounds *ChaosBounds, seed int64, budget time.Duration, logger logging.Logger, properties ...Property) error {
+	if len(properties) == 0 {
+		properties = []Property{Safety}
+	}
+
+	deadline := time.Now().Add(budget)
+	for first := true; first || time.Now().Before(deadline); first, seed = false, seed+1 {
+		logger.Log(logging.LevelInfo, "Simulating chaos schedule.", "seed", seed)
+
+		schedule := GenerateChaos(bounds, seed)
+		violation, err := checkSchedule(schedule, 2*bounds.Duration, properties)
+		if err != nil {
+			return fmt.Errorf("could not simulate chaos schedule of seed %d: %w", seed, err)
+		}
+		if violation != nil {
+			return fmt.Errorf("chaos schedule of seed %d violated a property: %w\nschedule: %s", seed, violation, schedule)
+		}
+	}
+	return nil
+}
+
+// randomExp returns an exponentially distributed duration with the given mean.
+func randomExp(mean time.Duration, rnd *rand.Rand) time.Duration {
+	return time.Duration(rnd.ExpFloat64() * float64(mean))
+}
@@ -0,0 +1,241 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// history records everything a node delivered and all the checkpoints of its application state,
+// across all incarnations of the node. It is shared by the Apps of all incarnations of the node,
+// such that a restarted node that delivers requests again (after restoring an older snapshot)
+// can be checked to deliver exactly the same requests at the same positions.
+type history struct {
+
+	// All the requests delivered by any incarnation of the node, indexed by their position in the total order.
+	log []*requestpb.RequestRef
+
+	// Digests of the snapshots of the application state, indexed by the number of requests they contain.
+	snapshots map[int][]byte
+
+	// Inconsistencies observed within the node (see CheckConsistency).
+	violations []string
+}
+
+// newHistory returns a new empty history.
+func newHistory() *history {
+	return &history{snapshots: make(map[int][]byte)}
+}
+
+// delivered records the delivery of reqRef at position pos of the total order.
+// If reqRef has already been delivered at this position before, it must be the same request.
+func (h *history) delivered(pos int, reqRef *requestpb.RequestRef) {
+	switch {
+	case pos < len(h.log):
+		if !proto.Equal(h.log[pos], reqRef) {
+			h.violate("delivered %s at position %d, previously delivered %s", requestString(reqRef), pos, requestString(h.log[pos]))
+		}
+	case pos == len(h.log):
+		h.log = append(h.log, reqRef)
+	default:
+		h.violate("delivered %s at position %d, skipping positions %d to %d", requestString(reqRef), pos, len(h.log), pos-1)
+	}
+}
+
+// snapshotted records the snapshot of the application state after delivering numRequests requests.
+// All the snapshots after delivering the same number of requests must be identical.
+func (h *history) snapshotted(numRequests int, snapshot []byte) {
+	digest := sha256.Sum256(snapshot)
+	if previous, ok := h.snapshots[numRequests]; ok && !bytes.Equal(previous, digest[:]) {
+		h.violate("took differing snapshots after delivering %d requests", numRequests)
+	}
+	h.snapshots[numRequests] = digest[:]
+}
+
+// violate records a violation of consistency within the node.
+func (h *history) violate(format string, args ...interface{}) {
+	h.violations = append(h.violations, fmt.Sprintf(format, args...))
+}
+
+// A ClientResult is the outcome of a request as observed by the client that submitted it:
+// the request is committed once f+1 nodes (at least one of them correct) delivered it at the same position.
+type ClientResult struct {
+	Client t.ClientID
+	ReqNo  t.ReqNo
+
+	// Position of the request in the total order.
+	Position int
+
+	// Virtual time at which the client observed the result.
+	At time.Duration
+}
+
+// requestKey identifies a request.
+type requestKey struct {
+	client t.ClientID
+	reqNo  t.ReqNo
+}
+
+// observeDeliveries updates the client-observed results with the requests node delivered
+// since it had delivered delivered requests.
+func (e *Engine) observeDeliveries(node *Node, delivered int) {
+	f := (len(e.Nodes) - 1) / 3
+	for pos := delivered; pos < len(node.history.log); pos++ {
+		reqRef := node.history.log[pos]
+		key := requestKey{client: t.ClientID(reqRef.ClientId), reqNo: t.ReqNo(reqRef.ReqNo)}
+		if _, ok := e.results[key]; ok {
+			continue
+		}
+
+		if e.deliveries[key] == nil {
+			e.deliveries[key] = make(map[int]int)
+		}
+		e.deliveries[key][pos]++
+		if e.deliveries[key][pos] == f+1 {
+			e.results[key] = &ClientResult{Client: key.client, ReqNo: key.reqNo, Position: pos, At: e.now}
+			delete(e.deliveries, key)
+		}
+	}
+}
+
+// ClientResults returns the results the clients observed so far, ordered by the positions of the requests.
+func (e *Engine) ClientResults() []*ClientResult {
+	results := make([]*ClientResult, 0, len(e.results))
+	for _, result := range e.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Position < results[j].Position
+	})
+	return results
+}
+
+// A ConsistencyError lists the violations of consistency detected by CheckConsistency.
+type ConsistencyError struct {
+	Violations []string
+}
+
+func (ce *ConsistencyError) Error() string {
+	return fmt.Sprintf("%d consistency violations:\n%s", len(ce.Violations), strings.Join(ce.Violations, "\n"))
+}
+
+// CheckConsistency checks that the correct (i.e., not Byzantine) nodes agree on a single total order
+// and returns a *ConsistencyError if they do not. It can be called at any time, typically after a run.
+// The checked invariants are:
+//   - No node delivers the same request twice, and a restarted node re-delivers exactly what it delivered before.
+//   - No fork: of the logs of any two nodes, one is a prefix of the other.
+//   - The snapshots of the application state taken at checkpoints after the same number of requests are identical.
+//   - No client-observed result (see ClientResults) is contradicted by any node.
+//
+// Crashed nodes are included in the check with everything they delivered before crashing.
+func (e *Engine) CheckConsistency() error {
+	var violations []string
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	correct := make([]*Node, 0, len(e.Nodes))
+	for _, node := range e.Nodes {
+		if _, byzantine := e.spec.Byzantine[node.ID]; !byzantine {
+			correct = append(correct, node)
+		}
+	}
+
+	// Check each node on its own and find the longest log.
+	var longest *Node
+	for _, node := range correct {
+		for _, v := range node.history.violations {
+			violate("node %d %s", node.ID, v)
+		}
+
+		positions := make(map[requestKey]int)
+		for pos, reqRef := range node.history.log {
+			key := requestKey{client: t.ClientID(reqRef.ClientId), reqNo: t.ReqNo(reqRef.ReqNo)}
+			if previous, ok := positions[key]; ok {
+				violate("node %d delivered %s twice, at positions %d and %d", node.ID, requestString(reqRef), previous, pos)
+			}
+			positions[key] = pos
+		}
+
+		if longest == nil || len(node.history.log) > len(longest.history.log) {
+			longest = node
+		}
+	}
+	if longest == nil {
+		return nil
+	}
+
+	// Check that all logs are prefixes of the longest one, reporting the first divergence of each node.
+	for _, node := range correct {
+		for pos, reqRef := range node.history.log {
+			if !proto.Equal(reqRef, longest.history.log[pos]) {
+				violate("fork at position %d: node %d delivered %s, node %d delivered %s",
+					pos, node.ID, requestString(reqRef), longest.ID, requestString(longest.history.log[pos]))
+				break
+			}
+		}
+	}
+
+	// Check that the snapshots at the same positions are identical.
+	snapshots := make(map[int][]byte)
+	snapshotNodes := make(map[int]t.NodeID)
+	for _, node := range correct {
+		for _, numRequests := range sortedSnapshotPositions(node.history.snapshots) {
+			digest := node.history.snapshots[numRequests]
+			if previous, ok := snapshots[numRequests]; !ok {
+				snapshots[numRequests] = digest
+				snapshotNodes[numRequests] = node.ID
+			} else if !bytes.Equal(previous, digest) {
+				violate("differing snapshots after %d requests at nodes %d and %d",
+					numRequests, snapshotNodes[numRequests], node.ID)
+			}
+		}
+	}
+
+	// Check that no node contradicts the results observed by the clients.
+	for _, result := range e.ClientResults() {
+		for _, node := range correct {
+			if result.Position >= len(node.history.log) {
+				continue
+			}
+			reqRef := node.history.log[result.Position]
+			if t.ClientID(reqRef.ClientId) != result.Client || t.ReqNo(reqRef.ReqNo) != result.ReqNo {
+				violate("client %d observed request %d committed at position %d, but node %d delivered %s there",
+					result.Client, result.ReqNo, result.Position, node.ID, requestString(reqRef))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ConsistencyError{Violations: violations}
+	}
+	return nil
+}
+
+// sortedSnapshotPositions returns the keys of snapshots in increasing order.
+func sortedSnapshotPositions(snapshots map[int][]byte) []int {
+	positions := make([]int, 0, len(snapshots))
+	for pos := range snapshots {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+	return positions
+}
+
+// requestString returns a short human-readable representation of a request reference.
+func requestString(reqRef *requestpb.RequestRef) string {
+	return fmt.Sprintf("request %d of client %d", reqRef.ReqNo, reqRef.ClientId)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Node is a simulated node, consisting of the modules of a real node driven by a mirbft.Stepper.
+// When a node restarts after a crash (see faults.Crash), its volatile modules (App and Protocol) are replaced
+// by new instances, which recover the state the node persisted in its WAL and request store.
+type Node struct {
+	ID t.NodeID
+
+	// The replicated application, recording the delivered requests.
+	App *App
+
+	// The ISS protocol instance of the node.
+	Protocol *iss.ISS
+
+	// The notifications produced by the node, in the order of their production.
+	Notifications []*eventpb.Event
+
+	// Non-nil if the node halted, e.g. due to a poisoned batch or a crash (see ErrCrashed),
+	// in which case all further input to it is dropped (until the node restarts, if it crashed).
+	Err error
+
+	// The storage modules of the node. They survive crashes.
+	WAL      *simplewal.VolatileWAL
+	ReqStore *reqstore.VolatileRequestStore
+
+	// Everything the node delivered, across all its incarnations (see CheckConsistency).
+	history *history
+
+	// Number of times the node has been restarted.
+	// Events produced by a previous incarnation of the node are dropped.
+	incarnation int
+
+	issConfig *iss.Config
+	net       modules.Net
+	logger    logging.Logger
+	stepper   *mirbft.Stepper
+
+	// If not nil, the protocol of each incarnation of the node is recorded to the writer record returns for it,
+	// observing the virtual wall clock now (see Spec.RecordProtocol).
+	record   func(incarnation int) io.Writer
+	now      func() time.Time
+	recorder *eventlog.ProtocolRecorder
+
+	// The first error encountered while recording the protocol.
+	recordingErr error
+}
+
+// newNode creates a simulated node with ID id, sending messages through net.
+// The node must be started using start. To record its protocol, record and now must be set before.
+func newNode(id t.NodeID, issConfig *iss.Config, net modules.Net, logger logging.Logger) *Node {
+	return &Node{
+		ID:        id,
+		WAL:       simplewal.NewVolatileWAL(),
+		ReqStore:  reqstore.NewVolatileRequestStore(),
+		history:   newHistory(),
+		issConfig: issConfig,
+		net:       net,
+		logger:    logger,
+	}
+}
+
+// start creates the volatile modules of the node and starts it, returning the node's initial events.
+// If restart is true, the node recovers the state persisted by its previous incarnation.
+func (n *Node) start(restart bool) (*events.EventList, error) {
+
+	// If recording, make the protocol observe the recorder's wall clock.
+	issConfig := n.issConfig
+	var recorder *eventlog.ProtocolRecorder
+	if n.record != nil {
+		var err error
+		if recorder, err = eventlog.NewProtocolRecorder(n.ID, n.record(n.incarnation), eventlog.WallClockOpt(n.now)); err != nil {
+			return nil, fmt.Errorf("could not create protocol recorder: %w", err)
+		}
+		recordedConfig := *n.issConfig
+		recordedConfig.Now = recorder.Now
+		issConfig = &recordedConfig
+	}
+
+	protocol, err := iss.New(n.ID, issConfig, n.logger)
+	if err != nil {
+		return nil, fmt.Errorf("could not create ISS protocol: %w", err)
+	}
+	var protocolModule modules.Protocol = protocol
+	if recorder != nil {
+		protocolModule = recorder.Wrap(protocol)
+	}
+	app := &App{history: n.history}
+
+	newStepper := mirbft.NewStepper
+	if restart {
+		newStepper = mirbft.RestartStepper
+	}
+
+	// Use a dummy crypto module that only produces signatures consisting of a single zero byte
+	// and treats those signatures as valid, such that the simulated clients need no keys.
+	stepper, err := newStepper(n.ID, &mirbft.NodeConfig{Logger: n.logger}, &modules.Modules{
+		Net:           n.net,
+		Hasher:        crypto.SHA256,
+		App:           app,
+		WAL:           n.WAL,
+		ClientTracker: clients.SigningTracker(n.logger),
+		RequestStore:  n.ReqStore,
+		Protocol:      protocolModule,
+		Crypto:        &mirCrypto.DummyCrypto{DummySig: dummySig},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	initial, err := stepper.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	n.App, n.Protocol, n.stepper, n.recorder, n.Err = app, protocol, stepper, recorder, nil
+	return initial, nil
+}
+
+// halt makes the node drop all further input, recording err as the reason.
+func (n *Node) halt(err error) {
+	n.Err = err
+	n.stepper.Stop()
+	n.stopRecording()
+}
+
+// stopRecording flushes the recording of the node's current incarnation, if any.
+func (n *Node) stopRecording() {
+	if n.recorder == nil {
+		return
+	}
+	if err := n.recorder.Stop(); err != nil && n.recordingErr == nil {
+		n.recordingErr = fmt.Errorf("could not record protocol of node %d: %w", n.ID, err)
+	}
+	n.recorder = nil
+}
+
+// Halted returns true if the node halted.
+func (n *Node) Halted() bool {
+	return n.Err != nil
+}
+
+// simNet is the Net module of a simulated node. Sent messages are scheduled for delivery by the Engine.
+type simNet struct {
+	engine *Engine
+	ownID  t.NodeID
+
+	// If not nil, the node is Byzantine and the behavior alters all the messages it sends.
+	behavior Behavior
+}
+
+// Send schedules the delivery of a copy of msg (or of the messages the node's Behavior replaces it by)
+// to node dest after a simulated network latency. Messages to nodes outside the simulation are dropped.
+func (sn *simNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	msg = proto.Clone(msg).(*messagepb.Message)
+	if sn.behavior == nil {
+		sn.engine.send(sn.ownID, dest, msg)
+		return nil
+	}
+	for _, m := range sn.behavior.Send(dest, msg, sn.engine.rand) {
+		sn.engine.send(sn.ownID, dest, m)
+	}
+	return nil
+}
+
+// ReceiveChan returns nil, as received messages are injected directly by the Engine.
+func (sn *simNet) ReceiveChan() <-chan modules.ReceivedMessage {
+	return nil
+}
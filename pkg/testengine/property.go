@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// A Property is an assertion about the outcome of simulating a Schedule.
+// It returns a non-nil error describing the violation if the property does not hold for the Engine after the run.
+type Property func(s *Schedule, e *Engine) error
+
+// Safety asserts that no node halted (except by crashing), in particular by violating an invariant of ISS
+// (see iss.InvariantViolation, checked by all simulated nodes), and that the nodes' logs are consistent
+// (see Engine.CheckConsistency).
+func Safety(s *Schedule, e *Engine) error {
+	for _, node := range e.Nodes {
+		if node.Err != nil && node.Err != ErrCrashed {
+			return fmt.Errorf("node %d halted: %w", node.ID, node.Err)
+		}
+	}
+	return e.CheckConsistency()
+}
+
+// Liveness asserts that all nodes delivered all requests if the schedule is lossless (see Schedule.Lossless).
+// It holds trivially for other schedules.
+func Liveness(s *Schedule, e *Engine) error {
+	if !s.Lossless() {
+		return nil
+	}
+	total := s.NumClients * s.RequestsPerClient
+	for _, node := range e.Nodes {
+		if len(node.App.Requests) != total {
+			return fmt.Errorf("node %d delivered %d of %d requests", node.ID, len(node.App.Requests), total)
+		}
+	}
+	return nil
+}
+
+// CheckConfig configures CheckProperties.
+type CheckConfig struct {
+
+	// Number of schedules to generate and check.
+	Trials int
+
+	// Seed of the generation of the schedules.
+	Seed int64
+
+	// Bounds of the generated schedules. Default: DefaultScheduleBounds().
+	Bounds *ScheduleBounds
+
+	// Limit of the virtual time of each simulation. Default: 1 minute.
+	Limit time.Duration
+
+	// Maximal number of simplifications of a failing schedule. Default: 100.
+	MaxShrinks int
+}
+
+// A Counterexample is a schedule for which a property does not hold.
+type Counterexample struct {
+
+	// The generated schedule that has been found to violate the property,
+	// and the number of the trial that generated it.
+	Original *Schedule
+	Trial    int
+
+	// The simplest variant of Original found to violate the property (see Schedule.Shrink)
+	// and the number of simplifications that led to it.
+	Schedule *Schedule
+	Shrinks  int
+
+	// The violation of the property by Schedule.
+	Err error
+}
+
+func (ce *Counterexample) Error() string {
+	return fmt.Sprintf("property violated by schedule of trial %d, shrunk %d times: %v\nschedule: %s",
+		ce.Trial, ce.Shrinks, ce.Err, ce.Schedule)
+}
+
+func (ce *Counterexample) Unwrap() error {
+	return ce.Err
+}
+
+// CheckProperties simulates config.Trials randomly generated schedules and checks that all the given properties
+// hold for each of them. For the first schedule violating any property, it looks for the simplest variant
+// of the schedule that still violates a property, by repeatedly simplifying it (see Schedule.Shrink),
+// and returns it as a *Counterexample. The schedules, and thus the Counterexample, are determined by config.Seed.
+// CheckProperties returns other errors if a simulation could not be run.
+func CheckProperties(config *CheckConfig, properties ...Property) error {
+	bounds := config.Bounds
+	if bounds == nil {
+		bounds = DefaultScheduleBounds()
+	}
+	limit := config.Limit
+	if limit == 0 {
+		limit = time.Minute
+	}
+	maxShrinks := config.MaxShrinks
+	if maxShrinks == 0 {
+		maxShrinks = 100
+	}
+
+	rnd := rand.New(rand.NewSource(config.Seed))
+	for trial := 0; trial < config.Trials; trial++ {
+		schedule := GenerateSchedule(bounds, rnd)
+		violation, err := checkSchedule(schedule, limit, properties)
+		if err != nil {
+			return fmt.Errorf("could not simulate schedule of trial %d: %w\nschedule: %s", trial, err, schedule)
+		}
+		if violation == nil {
+			continue
+		}
+
+		counterexample := &Counterexample{Original: schedule, Trial: trial, Schedule: schedule, Err: violation}
+		for shrunk := true; shrunk && counterexample.Shrinks < maxShrinks; {
+			shrunk = false
+			for _, variant := range counterexample.Schedule.Shrink() {
+				violation, err := checkSchedule(variant, limit, properties)
+				if err != nil {
+					return fmt.Errorf("could not simulate schedule: %w\nschedule: %s", err, variant)
+				}
+				if violation != nil {
+					counterexample.Schedule, counterexample.Err = variant, violation
+					counterexample.Shrinks++
+					shrunk = true
+					break
+				}
+			}
+		}
+		return counterexample
+	}
+	return nil
+}
+
+// checkSchedule simulates schedule and returns the violation of the first property that does not hold, if any.
+func checkSchedule(schedule *Schedule, limit time.Duration, properties []Property) (violation error, err error) {
+	engine, err := schedule.Run(limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range properties {
+		if violation = property(schedule, engine); violation != nil {
+			return violation, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// A scheduled piece of work for a single node: either a list of events to process or a message to receive.
+type item struct {
+
+	// Virtual time at which the item is due.
+	at time.Duration
+
+	// Order of scheduling, breaking ties between items due at the same time.
+	seq uint64
+
+	// The node the item is destined to.
+	node t.NodeID
+
+	// The events to process. Nil if the item is a message.
+	events *events.EventList
+
+	// True if the item is a tick of the node's logical clock.
+	tick bool
+
+	// If the item is a crash or a restart of the node (see faults.Crash), exactly one of these is true.
+	crash   bool
+	restart bool
+
+	// True if the item is a crash triggered by a leader's proposal (see Spec.LeaderCrashes).
+	leaderCrash bool
+
+	// True if the item originates outside the node (a message or a client request).
+	// Items that do not are dropped unless they have been produced by the current incarnation of the node.
+	external    bool
+	incarnation int
+
+	// If the item is a message, its sender and the message itself.
+	from t.NodeID
+	msg  *messagepb.Message
+}
+
+// queue holds the scheduled items, ordered by due time and, among items due at the same time, by order of scheduling.
+// It implements heap.Interface and must only be accessed through the push and pop methods.
+type queue []*item
+
+func (q queue) Len() int {
+	return len(q)
+}
+
+func (q queue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *queue) Push(x interface{}) {
+	*q = append(*q, x.(*item))
+}
+
+func (q *queue) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return it
+}
+
+// push adds an item to the queue.
+func (q *queue) push(it *item) {
+	heap.Push(q, it)
+}
+
+// pop removes and returns the next due item. The queue must not be empty.
+func (q *queue) pop() *item {
+	return heap.Pop(q).(*item)
+}
+
+// peek returns the next due item without removing it, or nil if the queue is empty.
+func (q queue) peek() *item {
+	if len(q) == 0 {
+		return nil
+	}
+	return q[0]
+}
@@ -0,0 +1,341 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// A Schedule describes a simulation: the system, the load, the faults, and the seed of the Engine,
+// which determines the order in which the messages in transit are delivered.
+// Schedules are generated at random (see GenerateSchedule) and simplified (see Schedule.Shrink)
+// for property-based testing (see CheckProperties).
+// Any Schedule is causally valid, as the Engine never delivers a message before it has been sent
+// and each node processes the events it produces in the order of their production.
+// A Schedule is serializable as JSON, such that a failing schedule can be reported and replayed.
+type Schedule struct {
+	Seed int64
+
+	NumNodes              int
+	NumClients            int
+	RequestsPerClient     int
+	ClientRequestInterval time.Duration
+	MinLatency            time.Duration
+	MaxLatency            time.Duration
+
+	// The ISS segment length. Short segments make epoch changes and the garbage collection
+	// of stable checkpoints interleave with the processing of the load.
+	SegmentLength int
+
+	// Parameters of the reordered (see faults.Reorder) and duplicated (see faults.Duplicate) messages.
+	// The rates are zero if the messages are not reordered or duplicated, respectively.
+	ReorderRate       float64
+	ReorderMaxDelay   time.Duration
+	DuplicateRate     float64
+	DuplicateMaxDelay time.Duration
+
+	// Temporary partitions of the network.
+	Partitions []TimedPartition
+
+	// Crashes (and restarts) of nodes. At most f nodes are crashed at any time.
+	Crashes []faults.Crash
+
+	// Crashes (and restarts) of the leaders proposing particular sequence numbers (see faults.LeaderCrash).
+	LeaderCrashes []faults.LeaderCrash
+
+	// Periods of slow persistent storage of nodes.
+	SlowDisks []faults.SlowDisk
+}
+
+// A TimedPartition is a partition of the network (see faults.Partition) during the period [Start, End).
+// If Healing is set, the messages between the groups are delivered at End instead of being lost.
+type TimedPartition struct {
+	Start   time.Duration
+	End     time.Duration
+	Groups  [][]t.NodeID
+	Healing bool
+}
+
+// ScheduleBounds bound the schedules produced by GenerateSchedule.
+type ScheduleBounds struct {
+	MaxNodes             int
+	MaxClients           int
+	MaxRequestsPerClient int
+	MaxSegmentLength     int
+
+	// Whether to generate partitions and crashes, respectively.
+	// Messages lost in a partition or while a node is down are never retransmitted,
+	// so the schedules with partitions or crashes are not expected to deliver the whole load (see Schedule.Lossless).
+	Partitions bool
+	Crashes    bool
+
+	// The period from the start of the simulation within which the faults happen.
+	FaultPeriod time.Duration
+}
+
+// DefaultScheduleBounds returns bounds producing small, but interesting, schedules:
+// up to 7 nodes with short segments, to which up to 3 clients submit up to 30 requests each.
+// Partitions and crashes are disabled.
+func DefaultScheduleBounds() *ScheduleBounds {
+	return &ScheduleBounds{
+		MaxNodes:             7,
+		MaxClients:           3,
+		MaxRequestsPerClient: 30,
+		MaxSegmentLength:     4,
+		FaultPeriod:          time.Second,
+	}
+}
+
+// GenerateSchedule returns a random Schedule within bounds, with all random decisions taken using rnd.
+func GenerateSchedule(bounds *ScheduleBounds, rnd *rand.Rand) *Schedule {
+	s := &Schedule{
+		Seed:                  rnd.Int63(),
+		NumNodes:              1 + rnd.Intn(bounds.MaxNodes),
+		NumClients:            1 + rnd.Intn(bounds.MaxClients),
+		RequestsPerClient:     1 + rnd.Intn(bounds.MaxRequestsPerClient),
+		ClientRequestInterval: randomMillis(1, 10, rnd),
+		MinLatency:            randomMillis(1, 5, rnd),
+		SegmentLength:         1 + rnd.Intn(bounds.MaxSegmentLength),
+	}
+	s.MaxLatency = s.MinLatency + randomMillis(0, 20, rnd)
+
+	if rnd.Intn(2) == 0 {
+		s.ReorderRate = 0.3 * rnd.Float64()
+		s.ReorderMaxDelay = randomMillis(1, 30, rnd)
+	}
+	if rnd.Intn(2) == 0 {
+		s.DuplicateRate = 0.2 * rnd.Float64()
+		s.DuplicateMaxDelay = randomMillis(0, 30, rnd)
+	}
+
+	// Partition the nodes in a majority, which can make progress, and the remaining nodes.
+	if bounds.Partitions && s.NumNodes > 1 && rnd.Intn(2) == 0 {
+		start := time.Duration(rnd.Int63n(int64(bounds.FaultPeriod)))
+		nodes := rnd.Perm(s.NumNodes)
+		majority := s.NumNodes - s.f()
+		s.Partitions = append(s.Partitions, TimedPartition{
+			Start:  start,
+			End:    start + time.Duration(1+rnd.Int63n(int64(bounds.FaultPeriod/2))),
+			Groups: [][]t.NodeID{nodeIDs(nodes[:majority]), nodeIDs(nodes[majority:])},
+		})
+	}
+
+	// Crash up to f distinct nodes, each restarting before the end of the fault period.
+	if bounds.Crashes {
+		for _, node := range rnd.Perm(s.NumNodes)[:rnd.Intn(s.f()+1)] {
+			at := time.Duration(rnd.Int63n(int64(bounds.FaultPeriod / 2)))
+			s.Crashes = append(s.Crashes, faults.Crash{
+				Node:      t.NodeID(node),
+				At:        at,
+				RestartAt: at + time.Duration(1+rnd.Int63n(int64(bounds.FaultPeriod/2))),
+			})
+		}
+	}
+
+	return s
+}
+
+// Lossless returns true if no message of the schedule is lost, in which case all requests are expected to be delivered.
+func (s *Schedule) Lossless() bool {
+	for _, partition := range s.Partitions {
+		if !partition.Healing {
+			return false
+		}
+	}
+	return len(s.Crashes) == 0 && len(s.LeaderCrashes) == 0
+}
+
+// Spec returns the specification of the simulated system described by the schedule.
+func (s *Schedule) Spec() *Spec {
+	var injectors []faults.Injector
+	if s.ReorderRate > 0 {
+		injectors = append(injectors, &faults.Reorder{Rate: s.ReorderRate, MaxDelay: s.ReorderMaxDelay})
+	}
+	if s.DuplicateRate > 0 {
+		injectors = append(injectors, &faults.Duplicate{Rate: s.DuplicateRate, MaxDelay: s.DuplicateMaxDelay})
+	}
+	for _, partition := range s.Partitions {
+		injector := &faults.Partition{Groups: partition.Groups}
+		if partition.Healing {
+			injector.HealAt = partition.End
+		}
+		injectors = append(injectors, &faults.During{Start: partition.Start, End: partition.End, Injector: injector})
+	}
+
+	spec := &Spec{
+		NumNodes:              s.NumNodes,
+		NumClients:            s.NumClients,
+		RequestsPerClient:     s.RequestsPerClient,
+		ClientRequestInterval: s.ClientRequestInterval,
+		MinLatency:            s.MinLatency,
+		MaxLatency:            s.MaxLatency,
+		Crashes:               s.Crashes,
+		LeaderCrashes:         s.LeaderCrashes,
+		SlowDisks:             s.SlowDisks,
+		ISSConfig: func(id t.NodeID, config *iss.Config) {
+			config.SegmentLength = s.SegmentLength
+		},
+	}
+	if len(injectors) > 0 {
+		spec.Faults = faults.Chain(injectors...)
+	}
+	return spec
+}
+
+// Run simulates the schedule until all requests are delivered or the virtual time reaches limit.
+// The returned Engine is stopped.
+func (s *Schedule) Run(limit time.Duration) (*Engine, error) {
+	engine, err := New(s.Spec(), s.Seed)
+	if err != nil {
+		return nil, err
+	}
+	defer engine.Stop()
+
+	if _, err := engine.RunUntil(engine.AllDelivered, limit); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// Shrink returns simpler variants of the schedule, each differing from it in a single aspect,
+// in the order in which they are worth trying: first without each of the faults,
+// then with less load, fewer nodes, and less variable latencies.
+// A schedule of one node, one client submitting a single request, without faults, has no simpler variants.
+func (s *Schedule) Shrink() []*Schedule {
+	var variants []*Schedule
+	variant := func(modify func(v *Schedule)) {
+		v := s.copy()
+		modify(v)
+		variants = append(variants, v)
+	}
+
+	for i := range s.Crashes {
+		i := i
+		variant(func(v *Schedule) { v.Crashes = append(v.Crashes[:i:i], v.Crashes[i+1:]...) })
+	}
+	for i := range s.LeaderCrashes {
+		i := i
+		variant(func(v *Schedule) { v.LeaderCrashes = append(v.LeaderCrashes[:i:i], v.LeaderCrashes[i+1:]...) })
+	}
+	for i := range s.Partitions {
+		i := i
+		variant(func(v *Schedule) { v.Partitions = append(v.Partitions[:i:i], v.Partitions[i+1:]...) })
+	}
+	for i := range s.SlowDisks {
+		i := i
+		variant(func(v *Schedule) { v.SlowDisks = append(v.SlowDisks[:i:i], v.SlowDisks[i+1:]...) })
+	}
+	if s.ReorderRate > 0 {
+		variant(func(v *Schedule) { v.ReorderRate, v.ReorderMaxDelay = 0, 0 })
+	}
+	if s.DuplicateRate > 0 {
+		variant(func(v *Schedule) { v.DuplicateRate, v.DuplicateMaxDelay = 0, 0 })
+	}
+
+	if s.NumClients > 1 {
+		variant(func(v *Schedule) { v.NumClients = 1 })
+		variant(func(v *Schedule) { v.NumClients-- })
+	}
+	if s.RequestsPerClient > 1 {
+		variant(func(v *Schedule) { v.RequestsPerClient /= 2 })
+		variant(func(v *Schedule) { v.RequestsPerClient-- })
+	}
+
+	// Removing a node must not remove crashed or partitioned nodes or exceed the tolerated number of crashes.
+	if s.NumNodes > 1 {
+		variant(func(v *Schedule) { v.NumNodes-- })
+	}
+
+	if s.MaxLatency > s.MinLatency {
+		variant(func(v *Schedule) { v.MaxLatency = v.MinLatency })
+	}
+
+	valid := variants[:0]
+	for _, v := range variants {
+		if v.valid() {
+			valid = append(valid, v)
+		}
+	}
+	return valid
+}
+
+// String returns the JSON representation of the schedule, which can be used to replay it.
+func (s *Schedule) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("unserializable schedule: %v", err)
+	}
+	return string(data)
+}
+
+// valid returns true if the schedule only crashes, partitions, and slows down existing nodes
+// and crashes at most f of them.
+func (s *Schedule) valid() bool {
+	crashed := make(map[t.NodeID]struct{})
+	for _, crash := range s.Crashes {
+		if int(crash.Node) >= s.NumNodes {
+			return false
+		}
+		crashed[crash.Node] = struct{}{}
+	}
+	if len(crashed) > s.f() {
+		return false
+	}
+
+	for _, partition := range s.Partitions {
+		for _, group := range partition.Groups {
+			for _, node := range group {
+				if int(node) >= s.NumNodes {
+					return false
+				}
+			}
+		}
+	}
+
+	for _, slowDisk := range s.SlowDisks {
+		if int(slowDisk.Node) >= s.NumNodes {
+			return false
+		}
+	}
+	return true
+}
+
+// f returns the number of faulty nodes the schedule's system tolerates.
+func (s *Schedule) f() int {
+	return (s.NumNodes - 1) / 3
+}
+
+// copy returns a copy of the schedule that can be modified without affecting the original.
+func (s *Schedule) copy() *Schedule {
+	c := *s
+	c.Crashes = append([]faults.Crash(nil), s.Crashes...)
+	c.Partitions = append([]TimedPartition(nil), s.Partitions...)
+	c.LeaderCrashes = append([]faults.LeaderCrash(nil), s.LeaderCrashes...)
+	c.SlowDisks = append([]faults.SlowDisk(nil), s.SlowDisks...)
+	return &c
+}
+
+// randomMillis returns a whole number of milliseconds drawn uniformly at random from [min, max].
+func randomMillis(min, max int, rnd *rand.Rand) time.Duration {
+	return time.Duration(min+rnd.Intn(max-min+1)) * time.Millisecond
+}
+
+// nodeIDs converts node indices to node IDs.
+func nodeIDs(nodes []int) []t.NodeID {
+	ids := make([]t.NodeID, len(nodes))
+	for i, node := range nodes {
+		ids[i] = t.NodeID(node)
+	}
+	return ids
+}
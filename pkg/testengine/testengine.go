@@ -0,0 +1,530 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package testengine runs multiple nodes in a single goroutine, connected by a simulated network,
+// such that a whole run of the protocol is determined by a single seed value.
+// Each node consists of the modules of a real node (in particular, the ISS protocol) driven by a mirbft.Stepper.
+// All processing happens in virtual time: the Engine maintains a queue of scheduled events
+// (messages in transit, ticks, client requests, and the output of the modules) and processes them one by one,
+// advancing the virtual time to the time each event is due. Processing itself takes no virtual time,
+// unless a slow disk is simulated (see Spec.SlowDisks).
+// Message latencies and the offsets of the nodes' ticks are drawn from a pseudo-random generator seeded by the seed,
+// so a run that exposes a protocol bug can be reproduced exactly by re-running the Engine with the same seed.
+// For property-based testing, CheckProperties simulates randomly generated schedules (see Schedule)
+// and shrinks the first one violating a property to a minimal counterexample.
+// For soak tests, GenerateChaos generates long schedules densely packed with faults, reproducible from their seed.
+package testengine
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// ErrCrashed is the error of a crashed node (see Node.Err).
+var ErrCrashed = errors.New("node crashed")
+
+// The signature of all requests submitted by the simulated clients (see the crypto module of Node).
+var dummySig = []byte{0}
+
+// The wall clock time corresponding to the start of a simulation.
+var epochStart = time.Unix(0, 0)
+
+// Spec describes the simulated system and the load submitted to it.
+// Zero values are replaced by defaults.
+type Spec struct {
+
+	// Number of nodes. The nodes have IDs 0 to NumNodes-1. Default: 4.
+	NumNodes int
+
+	// Number of clients. The clients have IDs 0 to NumClients-1. Default: 1.
+	NumClients int
+
+	// Number of requests each client submits. Default: 0 (no load).
+	RequestsPerClient int
+
+	// Virtual time between the submission of two subsequent requests of a client. Default: 1ms.
+	// Each request is submitted to all nodes at the same time.
+	ClientRequestInterval time.Duration
+
+	// Virtual time between two ticks of a node. Default: 10ms.
+	// The ticks of different nodes are offset by a random fraction of the interval.
+	TickInterval time.Duration
+
+	// Bounds of the latency of message delivery, drawn uniformly at random for each message. Default: 1ms to 10ms.
+	MinLatency time.Duration
+	MaxLatency time.Duration
+
+	// If not nil, all messages are subject to the faults injected by Faults
+	// (in addition to the message latency, which is added to each copy of a message).
+	Faults faults.Injector
+
+	// Crashes (and restarts) of nodes.
+	Crashes []faults.Crash
+
+	// Crashes (and restarts) of the leaders proposing particular sequence numbers.
+	LeaderCrashes []faults.LeaderCrash
+
+	// Periods during which the persistent storage of a node is slow.
+	SlowDisks []faults.SlowDisk
+
+	// Byzantine nodes, each with the behavior altering the messages it sends (see Behavior).
+	// All other nodes are correct.
+	Byzantine map[t.NodeID]Behavior
+
+	// If not nil, ISSConfig is invoked with the (default) protocol configuration of each node
+	// and can adjust it before the node is created.
+	// The default configuration has the invariant checks enabled (see iss.Config.CheckInvariants),
+	// such that a node violating an invariant halts with an *iss.InvariantViolation (see Node.Err).
+	ISSConfig func(id t.NodeID, config *iss.Config)
+
+	// Logger used by all the nodes. Default: logging.NilLogger.
+	Logger logging.Logger
+
+	// If not nil, the protocol of each node is recorded by an eventlog.ProtocolRecorder
+	// observing the virtual wall clock, such that an eventlog.ProtocolReplayer can replay it.
+	// Each incarnation of a node (the first one being 0, and each restart adding one) is recorded separately,
+	// to the writer RecordProtocol returns for it. The recordings are complete once the Engine is stopped.
+	RecordProtocol func(node t.NodeID, incarnation int) io.Writer
+}
+
+// withDefaults returns a copy of the Spec with zero values replaced by defaults.
+func (s Spec) withDefaults() *Spec {
+	if s.NumNodes == 0 {
+		s.NumNodes = 4
+	}
+	if s.NumClients == 0 {
+		s.NumClients = 1
+	}
+	if s.ClientRequestInterval == 0 {
+		s.ClientRequestInterval = time.Millisecond
+	}
+	if s.TickInterval == 0 {
+		s.TickInterval = 10 * time.Millisecond
+	}
+	if s.MinLatency == 0 && s.MaxLatency == 0 {
+		s.MinLatency = time.Millisecond
+		s.MaxLatency = 10 * time.Millisecond
+	}
+	if s.Logger == nil {
+		s.Logger = logging.NilLogger
+	}
+	return &s
+}
+
+// Engine runs a simulation.
+// It is not safe for concurrent use, which it never needs to be, as the simulation runs in the caller's goroutine.
+type Engine struct {
+
+	// The simulated nodes, indexed by node ID.
+	Nodes []*Node
+
+	spec *Spec
+	seed int64
+	rand *rand.Rand
+
+	// Current virtual time, i.e., the time elapsed since the start of the simulation.
+	now time.Duration
+
+	// The scheduled items and the sequence number of the next item to schedule.
+	queue   queue
+	nextSeq uint64
+
+	// Digest of all the items processed so far (see Trace) and the number of processed items.
+	trace hash.Hash
+	steps uint64
+
+	// The results observed by the clients (see ClientResults) and, for each request without a result yet,
+	// the number of nodes that delivered it at each position.
+	results    map[requestKey]*ClientResult
+	deliveries map[requestKey]map[int]int
+
+	// The leader crashes that have not been triggered yet, indexed by sequence number,
+	// and the number of triggered leader crashes scheduled, but not processed yet.
+	leaderCrashes  map[t.SeqNr]faults.LeaderCrash
+	pendingCrashes int
+}
+
+// New creates a new simulation of the system described by spec,
+// with all pseudo-random decisions determined by seed.
+// The nodes are started (i.e., they load their (empty) WALs and receive the Init event)
+// at virtual time 0, when the first item is processed.
+func New(spec *Spec, seed int64) (*Engine, error) {
+	e := &Engine{
+		spec:  spec.withDefaults(),
+		seed:  seed,
+		rand:  rand.New(rand.NewSource(seed)),
+		trace: sha256.New(),
+
+		results:    make(map[requestKey]*ClientResult),
+		deliveries: make(map[requestKey]map[int]int),
+
+		leaderCrashes: make(map[t.SeqNr]faults.LeaderCrash),
+	}
+	if e.spec.MinLatency < 0 || e.spec.MaxLatency < e.spec.MinLatency {
+		return nil, fmt.Errorf("invalid message latency bounds: %v to %v", e.spec.MinLatency, e.spec.MaxLatency)
+	}
+
+	membership := make([]t.NodeID, e.spec.NumNodes)
+	for i := range membership {
+		membership[i] = t.NodeID(i)
+	}
+
+	for _, id := range membership {
+		issConfig := iss.DefaultConfig(membership)
+		issConfig.Now = e.wallClock
+		issConfig.CheckInvariants = true
+		if e.spec.ISSConfig != nil {
+			e.spec.ISSConfig(id, issConfig)
+		}
+		net := &simNet{engine: e, ownID: id, behavior: e.spec.Byzantine[id]}
+		node := newNode(id, issConfig, net, e.spec.Logger)
+		if e.spec.RecordProtocol != nil {
+			id := id
+			node.record = func(incarnation int) io.Writer { return e.spec.RecordProtocol(id, incarnation) }
+			node.now = e.wallClock
+		}
+		e.Nodes = append(e.Nodes, node)
+	}
+
+	// Start the nodes and schedule their first ticks.
+	for _, node := range e.Nodes {
+		if err := e.startNode(node, false); err != nil {
+			return nil, err
+		}
+	}
+
+	// Schedule the crashes and restarts.
+	for _, crash := range e.spec.Crashes {
+		if int(crash.Node) >= len(e.Nodes) {
+			return nil, fmt.Errorf("cannot crash unknown node %d", crash.Node)
+		}
+		if crash.RestartAt != 0 && crash.RestartAt <= crash.At {
+			return nil, fmt.Errorf("node %d restarting at %v before crashing at %v", crash.Node, crash.RestartAt, crash.At)
+		}
+		e.push(&item{at: crash.At, node: crash.Node, crash: true, external: true})
+		if crash.RestartAt != 0 {
+			e.push(&item{at: crash.RestartAt, node: crash.Node, restart: true, external: true})
+		}
+	}
+
+	for _, crash := range e.spec.LeaderCrashes {
+		e.leaderCrashes[crash.Sn] = crash
+	}
+	for _, slowDisk := range e.spec.SlowDisks {
+		if int(slowDisk.Node) >= len(e.Nodes) {
+			return nil, fmt.Errorf("cannot slow down the disk of unknown node %d", slowDisk.Node)
+		}
+	}
+
+	// Schedule the client load.
+	for c := 0; c < e.spec.NumClients; c++ {
+		for r := 0; r < e.spec.RequestsPerClient; r++ {
+			at := time.Duration(r) * e.spec.ClientRequestInterval
+			for _, node := range e.Nodes {
+				e.push(&item{at: at, node: node.ID, external: true, events: events.ListOf(events.ClientRequest(
+					t.ClientID(c), t.ReqNo(r), []byte(fmt.Sprintf("Client %d request %d", c, r)), dummySig))})
+			}
+		}
+	}
+
+	return e, nil
+}
+
+// Seed returns the seed the simulation has been created with.
+func (e *Engine) Seed() int64 {
+	return e.seed
+}
+
+// Now returns the current virtual time, i.e., the time elapsed since the start of the simulation.
+func (e *Engine) Now() time.Duration {
+	return e.now
+}
+
+// Steps returns the number of items (lists of events or messages) processed so far.
+func (e *Engine) Steps() uint64 {
+	return e.steps
+}
+
+// Trace returns a digest of everything the simulation processed so far:
+// each processed list of events and message, along with the virtual time of processing and the processing node.
+// Two simulations with the same Spec and seed always produce the same Trace.
+func (e *Engine) Trace() []byte {
+	return e.trace.Sum(nil)
+}
+
+// Step processes the next scheduled item, advancing the virtual time to its due time.
+// It returns an error only if the item could not be processed for reasons other than a node halting
+// (a halted node's error is recorded in Node.Err), or if there is no item left, which only happens
+// if all the nodes halted (otherwise the nodes keep ticking).
+func (e *Engine) Step() error {
+	if e.queue.peek() == nil {
+		return fmt.Errorf("no scheduled items left")
+	}
+	it := e.queue.pop()
+	e.now = it.at
+	node := e.Nodes[it.node]
+
+	if it.crash || it.restart {
+		return e.crashOrRestart(it, node)
+	}
+
+	// Items destined to halted nodes and items produced by previous incarnations of the node are dropped.
+	if node.Halted() || (!it.external && it.incarnation != node.incarnation) {
+		return nil
+	}
+
+	// Processing a tick schedules the next one.
+	if it.tick {
+		e.scheduleTick(node.ID, it.at+e.spec.TickInterval)
+	}
+
+	// Turn a message into a list of events, dropping it if it is invalid.
+	eventsIn := it.events
+	if it.msg != nil {
+		var err error
+		if eventsIn, err = node.stepper.ReceiveMessage(it.from, it.msg); err != nil {
+			return e.record(it, events.ListOf())
+		}
+	}
+	if err := e.record(it, eventsIn); err != nil {
+		return err
+	}
+
+	delivered := len(node.history.log)
+	eventsOut, notifications, err := node.stepper.Step(eventsIn)
+	e.observeDeliveries(node, delivered)
+	if err != nil {
+		node.halt(err)
+		return nil
+	}
+	node.Notifications = append(node.Notifications, notifications.Slice()...)
+	if eventsOut.Len() > 0 {
+		e.schedule(e.now+e.persistLatency(node.ID, eventsIn), node.ID, eventsOut)
+	}
+	return nil
+}
+
+// RunFor processes all the items due within the next d of virtual time
+// and sets the virtual time to the end of that period.
+func (e *Engine) RunFor(d time.Duration) error {
+	end := e.now + d
+	for next := e.queue.peek(); next != nil && next.at <= end; next = e.queue.peek() {
+		if err := e.Step(); err != nil {
+			return err
+		}
+	}
+	e.now = end
+	return nil
+}
+
+// RunUntil processes items until condition returns true (checked after each item)
+// or the virtual time reaches limit. It returns true if the condition has been satisfied.
+func (e *Engine) RunUntil(condition func() bool, limit time.Duration) (bool, error) {
+	for !condition() {
+		if next := e.queue.peek(); next == nil || next.at > limit {
+			e.now = limit
+			return false, nil
+		}
+		if err := e.Step(); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// AllDelivered returns true if every node that did not halt delivered all the requests of the client load.
+func (e *Engine) AllDelivered() bool {
+	for _, node := range e.Nodes {
+		if !node.Halted() && len(node.App.Requests) < e.spec.NumClients*e.spec.RequestsPerClient {
+			return false
+		}
+	}
+	return true
+}
+
+// Stop stops all the nodes that did not halt and completes the protocol recordings (see Spec.RecordProtocol).
+// It returns the first error encountered while recording, if any. The Engine must not be used afterwards.
+func (e *Engine) Stop() error {
+	var err error
+	for _, node := range e.Nodes {
+		if !node.Halted() {
+			node.stepper.Stop()
+			node.stopRecording()
+		}
+		if node.recordingErr != nil && err == nil {
+			err = node.recordingErr
+		}
+	}
+	return err
+}
+
+// startNode starts (or restarts) a node at the current virtual time
+// and schedules its first tick, offset by a random fraction of the tick interval.
+func (e *Engine) startNode(node *Node, restart bool) error {
+	initial, err := node.start(restart)
+	if err != nil {
+		return fmt.Errorf("could not start node %d: %w", node.ID, err)
+	}
+	e.schedule(e.now, node.ID, initial)
+	e.scheduleTick(node.ID, e.now+time.Duration(e.rand.Int63n(int64(e.spec.TickInterval))))
+	return nil
+}
+
+// crashOrRestart crashes or restarts a node, as requested by it.
+// Crashing a halted node and restarting a node that did not crash have no effect.
+func (e *Engine) crashOrRestart(it *item, node *Node) error {
+	if it.leaderCrash {
+		e.pendingCrashes--
+	}
+	if it.crash && !node.Halted() {
+		node.halt(ErrCrashed)
+		return e.record(it, events.ListOf())
+	} else if it.restart && node.Err == ErrCrashed {
+		node.incarnation++
+		if err := e.record(it, events.ListOf()); err != nil {
+			return err
+		}
+		return e.startNode(node, true)
+	}
+	return nil
+}
+
+// push adds an item to the queue, assigning it the next sequence number.
+func (e *Engine) push(it *item) {
+	it.seq = e.nextSeq
+	e.nextSeq++
+	e.queue.push(it)
+}
+
+// schedule schedules a list of events to be processed by the current incarnation of node at virtual time at.
+func (e *Engine) schedule(at time.Duration, node t.NodeID, eventsIn *events.EventList) {
+	e.push(&item{at: at, node: node, events: eventsIn, incarnation: e.Nodes[node].incarnation})
+}
+
+// scheduleTick schedules a tick of the current incarnation of node at virtual time at.
+// Processing the tick schedules the next one.
+func (e *Engine) scheduleTick(node t.NodeID, at time.Duration) {
+	e.push(&item{at: at, node: node, events: events.ListOf(events.Tick()), tick: true,
+		incarnation: e.Nodes[node].incarnation})
+}
+
+// send schedules the delivery of msg from node from to node dest after a random latency,
+// subject to the injected faults.
+func (e *Engine) send(from t.NodeID, dest t.NodeID, msg *messagepb.Message) {
+	if int(dest) >= len(e.Nodes) {
+		return
+	}
+	if preprepare := msg.GetIss().GetSb().GetMsg().GetPbftPreprepare(); preprepare != nil {
+		e.triggerLeaderCrash(from, t.SeqNr(preprepare.Sn))
+	}
+
+	delays := []time.Duration{0}
+	if e.spec.Faults != nil {
+		delays = e.spec.Faults.Inject(e.now, from, dest, msg, e.rand)
+	}
+	for i, delay := range delays {
+		latency := e.spec.MinLatency + time.Duration(e.rand.Int63n(int64(e.spec.MaxLatency-e.spec.MinLatency)+1))
+		copied := msg
+		if i > 0 {
+			copied = proto.Clone(msg).(*messagepb.Message)
+		}
+		e.push(&item{at: e.now + delay + latency, node: dest, from: from, msg: copied, external: true})
+	}
+}
+
+// triggerLeaderCrash schedules the crash (and restart) of node leader, which is proposing sequence number sn,
+// if a leader crash is described for sn (see Spec.LeaderCrashes) and would not leave more than f nodes crashed.
+// The crash is processed right after the leader's current step, such that the messages it already sent
+// are delivered, but not the rest of its output.
+func (e *Engine) triggerLeaderCrash(leader t.NodeID, sn t.SeqNr) {
+	crash, ok := e.leaderCrashes[sn]
+	if !ok {
+		return
+	}
+	delete(e.leaderCrashes, sn)
+
+	crashed := e.pendingCrashes
+	for _, node := range e.Nodes {
+		if node.Err == ErrCrashed {
+			crashed++
+		}
+	}
+	if crashed >= (len(e.Nodes)-1)/3+1 {
+		return
+	}
+
+	e.pendingCrashes++
+	e.push(&item{at: e.now, node: leader, crash: true, leaderCrash: true, external: true})
+	if crash.Downtime != 0 {
+		e.push(&item{at: e.now + crash.Downtime, node: leader, restart: true, external: true})
+	}
+}
+
+// persistLatency returns the delay of the events node produced by processing eventsIn at the current virtual time,
+// which is the latency of the node's disk if it is slow (see Spec.SlowDisks) and eventsIn persist data.
+func (e *Engine) persistLatency(node t.NodeID, eventsIn *events.EventList) time.Duration {
+	var latency time.Duration
+	for _, slowDisk := range e.spec.SlowDisks {
+		if slowDisk.Node == node && e.now >= slowDisk.Start && e.now < slowDisk.End && slowDisk.Latency > latency {
+			latency = slowDisk.Latency
+		}
+	}
+	if latency == 0 {
+		return 0
+	}
+
+	iter := eventsIn.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		switch event.Type.(type) {
+		case *eventpb.Event_WalAppend, *eventpb.Event_WalTruncate, *eventpb.Event_PersistDummyBatch,
+			*eventpb.Event_StoreVerifiedRequest:
+			return latency
+		}
+	}
+	return 0
+}
+
+// record adds a processed item to the trace.
+func (e *Engine) record(it *item, eventsIn *events.EventList) error {
+	var header [17]byte
+	binary.BigEndian.PutUint64(header[:8], uint64(it.at))
+	binary.BigEndian.PutUint64(header[8:16], uint64(it.node))
+	if it.crash {
+		header[16] = 1
+	} else if it.restart {
+		header[16] = 2
+	}
+	e.trace.Write(header[:])
+
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(eventsIn.Pb()); err != nil {
+		return fmt.Errorf("could not serialize events for the trace: %w", err)
+	}
+	e.trace.Write(buf.Bytes())
+	e.steps++
+	return nil
+}
+
+// wallClock returns the wall clock time corresponding to the current virtual time (see iss.Config.Now).
+func (e *Engine) wallClock() time.Time {
+	return epochStart.Add(e.now)
+}
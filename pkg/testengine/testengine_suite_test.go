@@ -0,0 +1,13 @@
+package testengine_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTestEngine(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TestEngine Suite")
+}
@@ -0,0 +1,417 @@
+package testengine_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/faults"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Engine", func() {
+
+	spec := &testengine.Spec{
+		NumNodes:          4,
+		NumClients:        2,
+		RequestsPerClient: 20,
+	}
+
+	// run simulates spec with the given seed until all requests are delivered and returns the engine.
+	run := func(seed int64) *testengine.Engine {
+		engine, err := testengine.New(spec, seed)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		delivered, err := engine.RunUntil(engine.AllDelivered, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delivered).To(BeTrue())
+		return engine
+	}
+
+	It("delivers all requests in the same order at all nodes", func() {
+		engine := run(42)
+		for _, node := range engine.Nodes {
+			Expect(node.Err).NotTo(HaveOccurred())
+			Expect(node.App.Requests).To(HaveLen(40))
+			Expect(node.App.Requests).To(Equal(engine.Nodes[0].App.Requests))
+		}
+	})
+
+	It("reproduces a run from its seed", func() {
+		first := run(7)
+		second := run(7)
+		Expect(second.Steps()).To(Equal(first.Steps()))
+		Expect(second.Now()).To(Equal(first.Now()))
+		Expect(second.Trace()).To(Equal(first.Trace()))
+		for i, node := range second.Nodes {
+			Expect(node.App.Requests).To(Equal(first.Nodes[i].App.Requests))
+		}
+
+		// A different seed results in a different schedule.
+		Expect(run(8).Trace()).NotTo(Equal(first.Trace()))
+	})
+
+	It("delivers all requests despite the faults of a scenario file", func() {
+		scenario, err := faults.LoadScenario("testdata/faults.json")
+		Expect(err).NotTo(HaveOccurred())
+
+		faultySpec := *spec
+		faultySpec.ClientRequestInterval = 10 * time.Millisecond
+		faultySpec.Faults = scenario.Injector
+		engine, err := testengine.New(&faultySpec, 42)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		delivered, err := engine.RunUntil(engine.AllDelivered, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delivered).To(BeTrue())
+		for _, node := range engine.Nodes {
+			Expect(node.App.Requests).To(Equal(engine.Nodes[0].App.Requests))
+		}
+	})
+
+	It("keeps the logs consistent across partitions and crashes", func() {
+		// The messages lost in the partition and while the node is down are never retransmitted,
+		// which the protocol (assuming reliable links) does not recover from. Hence, only safety is checked.
+		faultySpec := *spec
+		faultySpec.RequestsPerClient = 100
+		faultySpec.ClientRequestInterval = 10 * time.Millisecond
+		faultySpec.Faults = &faults.During{
+			Start:    900 * time.Millisecond,
+			End:      time.Second,
+			Injector: &faults.Partition{Groups: [][]t.NodeID{{0, 1, 2}}},
+		}
+		faultySpec.Crashes = []faults.Crash{{Node: 1, At: 300 * time.Millisecond, RestartAt: 400 * time.Millisecond}}
+		engine, err := testengine.New(&faultySpec, 42)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		Expect(engine.RunFor(5 * time.Second)).To(Succeed())
+
+		// No node delivers a request another node did not deliver at the same position in its log.
+		longest := engine.Nodes[0].App.Requests
+		for _, node := range engine.Nodes {
+			Expect(node.Err).NotTo(HaveOccurred())
+			if len(node.App.Requests) > len(longest) {
+				longest = node.App.Requests
+			}
+		}
+		for _, node := range engine.Nodes {
+			Expect(isPrefix(node.App.Requests, longest)).To(BeTrue())
+		}
+
+		// The restarted node recovered the requests delivered before its crash.
+		Expect(engine.Nodes[1].App.Requests).NotTo(BeEmpty())
+	})
+})
+
+// isPrefix returns true if log is a prefix of other.
+func isPrefix(log, other []*requestpb.RequestRef) bool {
+	if len(log) > len(other) {
+		return false
+	}
+	for i, reqRef := range log {
+		if !proto.Equal(reqRef, other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+var _ = Describe("Byzantine nodes", func() {
+
+	// runWithByzantine simulates 4 nodes, of which node 3 exhibits behavior, and checks that all correct nodes
+	// deliver all requests in the same order.
+	runWithByzantine := func(behavior testengine.Behavior) {
+		engine, err := testengine.New(&testengine.Spec{
+			NumNodes:              4,
+			NumClients:            2,
+			RequestsPerClient:     50,
+			ClientRequestInterval: 10 * time.Millisecond,
+			Byzantine:             map[t.NodeID]testengine.Behavior{3: behavior},
+		}, 42)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		delivered, err := engine.RunUntil(engine.AllDelivered, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delivered).To(BeTrue())
+		for _, node := range engine.Nodes[:3] {
+			Expect(node.Err).NotTo(HaveOccurred())
+			Expect(node.App.Requests).To(HaveLen(100))
+			Expect(node.App.Requests).To(Equal(engine.Nodes[0].App.Requests))
+		}
+	}
+
+	It("tolerates conflicting checkpoints", func() {
+		runWithByzantine(&testengine.ConflictingCheckpoints{SNOffset: 10})
+	})
+
+	It("tolerates ack spam", func() {
+		runWithByzantine(&testengine.AckSpam{Count: 5, MaxEpoch: 10, MaxSN: 1000})
+	})
+
+	It("tolerates withheld checkpoints", func() {
+		runWithByzantine(&testengine.WithholdCommits{})
+	})
+
+	It("tolerates all of the above at once", func() {
+		runWithByzantine(testengine.Behaviors(
+			&testengine.WithholdCommits{},
+			&testengine.ConflictingCheckpoints{SNOffset: 10},
+			&testengine.AckSpam{Count: 5, MaxEpoch: 10, MaxSN: 1000},
+		))
+	})
+
+	// The PBFT orderer does not implement the prepare and commit phases nor view changes yet
+	// and commits each batch as soon as it receives the leader's proposal.
+	// Until it does, a Byzantine leader can break safety by equivocating and liveness by withholding proposals.
+
+	PIt("tolerates a leader equivocating preprepares", func() {
+		runWithByzantine(&testengine.EquivocatePreprepares{Victims: []t.NodeID{0, 1}})
+	})
+
+	PIt("tolerates a leader withholding proposals", func() {
+		runWithByzantine(&testengine.WithholdCommits{Victims: []t.NodeID{0}})
+	})
+})
+
+var _ = Describe("Consistency checker", func() {
+
+	It("finds no violations in a run with faults and crashes", func() {
+		scenario, err := faults.LoadScenario("testdata/faults.json")
+		Expect(err).NotTo(HaveOccurred())
+		engine, err := testengine.New(&testengine.Spec{
+			NumNodes:              4,
+			NumClients:            2,
+			RequestsPerClient:     50,
+			ClientRequestInterval: 10 * time.Millisecond,
+			Faults:                scenario.Injector,
+			Crashes:               []faults.Crash{{Node: 2, At: 300 * time.Millisecond, RestartAt: 400 * time.Millisecond}},
+		}, 42)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		Expect(engine.RunFor(5 * time.Second)).To(Succeed())
+		Expect(engine.CheckConsistency()).To(Succeed())
+
+		// The clients observed the results of their requests at consecutive positions.
+		results := engine.ClientResults()
+		Expect(results).NotTo(BeEmpty())
+		for i, result := range results {
+			Expect(result.Position).To(Equal(i))
+			Expect(result.At).To(BeNumerically("<=", engine.Now()))
+		}
+	})
+
+	It("detects the fork caused by an equivocating leader", func() {
+		engine, err := testengine.New(&testengine.Spec{
+			NumNodes:              4,
+			NumClients:            2,
+			RequestsPerClient:     50,
+			ClientRequestInterval: 10 * time.Millisecond,
+			Byzantine: map[t.NodeID]testengine.Behavior{
+				3: &testengine.EquivocatePreprepares{Victims: []t.NodeID{0, 1}},
+			},
+		}, 42)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		Expect(engine.RunFor(5 * time.Second)).To(Succeed())
+		err = engine.CheckConsistency()
+		var consistencyErr *testengine.ConsistencyError
+		Expect(errors.As(err, &consistencyErr)).To(BeTrue())
+		Expect(consistencyErr.Violations).To(ContainElement(HavePrefix("fork at position")))
+	})
+})
+
+var _ = Describe("Property-based testing", func() {
+
+	It("generates valid schedules determined by the seed", func() {
+		bounds := testengine.DefaultScheduleBounds()
+		bounds.Partitions = true
+		bounds.Crashes = true
+		for seed := int64(0); seed < 100; seed++ {
+			schedule := testengine.GenerateSchedule(bounds, rand.New(rand.NewSource(seed)))
+			Expect(testengine.GenerateSchedule(bounds, rand.New(rand.NewSource(seed)))).To(Equal(schedule))
+			Expect(schedule.NumNodes).To(BeNumerically("<=", bounds.MaxNodes))
+			Expect(len(schedule.Crashes)).To(BeNumerically("<=", (schedule.NumNodes-1)/3))
+			for _, crash := range schedule.Crashes {
+				Expect(crash.RestartAt).To(BeNumerically(">", crash.At))
+			}
+			for _, variant := range schedule.Shrink() {
+				Expect(variant).NotTo(Equal(schedule))
+				Expect(len(variant.Crashes)).To(BeNumerically("<=", (variant.NumNodes-1)/3))
+			}
+		}
+	})
+
+	It("finds no violations of safety and liveness on lossless schedules", func() {
+		Expect(testengine.CheckProperties(&testengine.CheckConfig{Trials: 10, Seed: 1},
+			testengine.Safety, testengine.Liveness)).To(Succeed())
+	})
+
+	It("finds no violations of safety on schedules with partitions and crashes", func() {
+		bounds := testengine.DefaultScheduleBounds()
+		bounds.Partitions = true
+		bounds.Crashes = true
+		Expect(testengine.CheckProperties(&testengine.CheckConfig{Trials: 10, Seed: 2, Bounds: bounds, Limit: 5 * time.Second},
+			testengine.Safety, testengine.Liveness)).To(Succeed())
+	})
+
+	It("shrinks a counterexample to the simplest schedule violating the property", func() {
+		// A property violated as soon as node 0 delivers 3 requests.
+		fewDeliveries := func(s *testengine.Schedule, e *testengine.Engine) error {
+			if len(e.Nodes[0].App.Requests) >= 3 {
+				return fmt.Errorf("node 0 delivered %d requests", len(e.Nodes[0].App.Requests))
+			}
+			return nil
+		}
+
+		err := testengine.CheckProperties(&testengine.CheckConfig{Trials: 10, Seed: 3}, fewDeliveries)
+		var counterexample *testengine.Counterexample
+		Expect(errors.As(err, &counterexample)).To(BeTrue())
+		Expect(counterexample.Shrinks).To(BeNumerically(">", 0))
+		Expect(counterexample.Err).To(MatchError("node 0 delivered 3 requests"))
+
+		shrunk := counterexample.Schedule
+		Expect(shrunk.NumNodes).To(Equal(1))
+		Expect(shrunk.NumClients).To(Equal(1))
+		Expect(shrunk.RequestsPerClient).To(Equal(3))
+		Expect(shrunk.ReorderRate).To(BeZero())
+		Expect(shrunk.DuplicateRate).To(BeZero())
+		Expect(shrunk.MaxLatency).To(Equal(shrunk.MinLatency))
+	})
+})
+
+var (
+	soakBudget = flag.Duration("soak", 0, "time budget of the soak test, which simulates at least one chaos schedule")
+	soakSeed   = flag.Int64("soak.seed", 0, "seed of the first chaos schedule of the soak test (default: random)")
+)
+
+// ginkgoLogger logs to the GinkgoWriter, whose output is shown for failed tests.
+type ginkgoLogger struct{}
+
+func (ginkgoLogger) Log(level logging.LogLevel, text string, args ...interface{}) {
+	fmt.Fprintln(GinkgoWriter, append([]interface{}{text}, args...)...)
+}
+
+var _ = Describe("Chaos schedules", func() {
+
+	It("are determined by the seed and contain all kinds of faults", func() {
+		bounds := testengine.DefaultChaosBounds()
+		schedule := testengine.GenerateChaos(bounds, 1)
+		Expect(testengine.GenerateChaos(bounds, 1)).To(Equal(schedule))
+		Expect(testengine.GenerateChaos(bounds, 2)).NotTo(Equal(schedule))
+
+		Expect(schedule.Partitions).NotTo(BeEmpty())
+		Expect(schedule.LeaderCrashes).NotTo(BeEmpty())
+		Expect(schedule.SlowDisks).NotTo(BeEmpty())
+		for _, partition := range schedule.Partitions {
+			Expect(partition.Healing).To(BeTrue())
+			Expect(partition.Start).To(BeNumerically("<", bounds.Duration))
+		}
+		for _, crash := range schedule.LeaderCrashes {
+			Expect(crash.Sn).To(BeNumerically("<=", bounds.MaxCrashSn))
+			Expect(crash.Downtime).To(BeNumerically(">", 0))
+		}
+	})
+
+	It("deliver all requests despite healing partitions", func() {
+		schedule := &testengine.Schedule{
+			Seed:                  1,
+			NumNodes:              4,
+			NumClients:            2,
+			RequestsPerClient:     20,
+			ClientRequestInterval: 10 * time.Millisecond,
+			MinLatency:            time.Millisecond,
+			MaxLatency:            10 * time.Millisecond,
+			SegmentLength:         4,
+			Partitions: []testengine.TimedPartition{{
+				Start:   50 * time.Millisecond,
+				End:     300 * time.Millisecond,
+				Groups:  [][]t.NodeID{{0, 1}, {2, 3}},
+				Healing: true,
+			}},
+		}
+		Expect(schedule.Lossless()).To(BeTrue())
+
+		engine, err := schedule.Run(10 * time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testengine.Safety(schedule, engine)).To(Succeed())
+		Expect(testengine.Liveness(schedule, engine)).To(Succeed())
+	})
+
+	It("crash the leader proposing the given sequence number", func() {
+		engine, err := testengine.New(&testengine.Spec{
+			NumNodes:              4,
+			NumClients:            2,
+			RequestsPerClient:     20,
+			ClientRequestInterval: 10 * time.Millisecond,
+			LeaderCrashes:         []faults.LeaderCrash{{Sn: 3}, {Sn: 7}},
+		}, 42)
+		Expect(err).NotTo(HaveOccurred())
+		defer engine.Stop()
+
+		Expect(engine.RunFor(2 * time.Second)).To(Succeed())
+		Expect(engine.CheckConsistency()).To(Succeed())
+
+		// The second crash is skipped, as it would leave more than f nodes crashed.
+		crashed := 0
+		for _, node := range engine.Nodes {
+			if node.Err == testengine.ErrCrashed {
+				crashed++
+			}
+		}
+		Expect(crashed).To(Equal(1))
+	})
+
+	It("slow down the nodes with slow disks", func() {
+		run := func(slowDisks []faults.SlowDisk) time.Duration {
+			engine, err := testengine.New(&testengine.Spec{
+				NumNodes:          4,
+				NumClients:        2,
+				RequestsPerClient: 20,
+				SlowDisks:         slowDisks,
+			}, 42)
+			Expect(err).NotTo(HaveOccurred())
+			defer engine.Stop()
+
+			delivered, err := engine.RunUntil(engine.AllDelivered, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(delivered).To(BeTrue())
+			return engine.Now()
+		}
+
+		var slowDisks []faults.SlowDisk
+		for node := t.NodeID(0); node < 4; node++ {
+			slowDisks = append(slowDisks, faults.SlowDisk{Node: node, End: 10 * time.Second, Latency: 20 * time.Millisecond})
+		}
+		Expect(run(slowDisks)).To(BeNumerically(">", run(nil)+20*time.Millisecond))
+	})
+
+	It("preserve safety in a soak test", func() {
+		seed := *soakSeed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		fmt.Fprintf(GinkgoWriter, "Soak test starting at seed %d (replay with -soak.seed %d)\n", seed, seed)
+
+		bounds := testengine.DefaultChaosBounds()
+		if *soakBudget == 0 {
+			bounds.Duration = time.Second
+		}
+		Expect(testengine.Soak(bounds, seed, *soakBudget, ginkgoLogger{})).To(Succeed())
+	})
+})
@@ -0,0 +1,435 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package testkit assembles clusters of Nodes running the ISS protocol in a single process,
+// for integration tests of applications replicated using mirbft.
+// Each Node of a Cluster runs its modules using the default (serial) Processors, persists its state in memory
+// (see simplewal.VolatileWAL and reqstore.VolatileRequestStore), and is connected to the other Nodes
+// by an in-memory Loopback network. Requests are signed and verified as in a real deployment (see crypto.NodePseudo).
+// A test of an application typically looks as follows:
+//
+//	cluster, err := testkit.NewCluster(&testkit.Config{
+//		NumNodes: 4,
+//		NewApp:   func(id t.NodeID) modules.App { return newMyApp() },
+//	})
+//	...
+//	cluster.Start()
+//	defer cluster.Stop()
+//	err = cluster.Submit(ctx, clientID, reqNo, data)
+//	...
+//	err = cluster.WaitCommitted(ctx, numRequests)
+package testkit
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/serializing"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Config configures a Cluster. Zero values are replaced by the defaults (see DefaultConfig).
+type Config struct {
+
+	// Number of Nodes, all of which are members, with IDs 0 to NumNodes-1.
+	NumNodes int
+
+	// Number of clients, with IDs 0 to NumClients-1, that can submit requests (see Cluster.Submit).
+	NumClients int
+
+	// Interval of the Nodes' logical time ticks.
+	TickInterval time.Duration
+
+	// If not nil, NewApp returns the application replicated by node id.
+	// By default, each Node replicates an application whose state only consists of the number of applied requests.
+	NewApp func(id t.NodeID) modules.App
+
+	// If not nil, ISSConfig adjusts the protocol configuration of node id,
+	// initialized by iss.DefaultConfig for the membership of the Cluster.
+	ISSConfig func(id t.NodeID, config *iss.Config)
+
+	// If not nil, NodeConfig adjusts the configuration of node id. TickInterval and Logger are already set.
+	NodeConfig func(id t.NodeID, config *mirbft.NodeConfig)
+
+	// Logger of the Nodes. If nil, nothing is logged.
+	Logger logging.Logger
+}
+
+// DefaultConfig returns the default configuration of a Cluster: 4 Nodes, a single client,
+// and a tick interval of 10ms.
+func DefaultConfig() *Config {
+	return &Config{
+		NumNodes:     4,
+		NumClients:   1,
+		TickInterval: 10 * time.Millisecond,
+	}
+}
+
+// withDefaults returns a copy of c with all zero values replaced by the defaults.
+func (c *Config) withDefaults() *Config {
+	defaults := DefaultConfig()
+	config := *c
+	if config.NumNodes == 0 {
+		config.NumNodes = defaults.NumNodes
+	}
+	if config.NumClients == 0 {
+		config.NumClients = defaults.NumClients
+	}
+	if config.TickInterval == 0 {
+		config.TickInterval = defaults.TickInterval
+	}
+	if config.NewApp == nil {
+		config.NewApp = func(id t.NodeID) modules.App { return &counterApp{} }
+	}
+	if config.Logger == nil {
+		config.Logger = logging.NilLogger
+	}
+	return &config
+}
+
+// Cluster is a set of Nodes running in the same process and connected by a Loopback network.
+type Cluster struct {
+
+	// IDs of the Nodes and of the clients.
+	Membership []t.NodeID
+	ClientIDs  []t.ClientID
+
+	// The Nodes and the applications they replicate, indexed by node ID.
+	Nodes []*mirbft.Node
+	Apps  []modules.App
+
+	// The network connecting the Nodes.
+	Net *Loopback
+
+	// Closed to stop each node, exactly once (see StopNode).
+	nodeExitCs   []chan struct{}
+	nodeStopOnce []sync.Once
+
+	// Closed when each node's Run method returned.
+	nodeDoneCs []chan struct{}
+
+	// The errors the nodes' Run methods returned. Written to by the goroutines running the nodes.
+	nodeErrs []error
+
+	// The requests committed by each node, in the order of their commits.
+	committed []*committedLog
+
+	// Signs the requests of each client.
+	clientCrypto []*mirCrypto.Crypto
+
+	// Cancels the consumption of the nodes' committed batches.
+	cancelCommitted context.CancelFunc
+	committedWg     sync.WaitGroup
+
+	stopOnce sync.Once
+}
+
+// NewCluster creates the Nodes of a Cluster. The Nodes only start running when Start is called.
+func NewCluster(config *Config) (*Cluster, error) {
+	config = config.withDefaults()
+
+	membership := make([]t.NodeID, config.NumNodes)
+	for i := range membership {
+		membership[i] = t.NodeID(i)
+	}
+	clientIDs := make([]t.ClientID, config.NumClients)
+	for i := range clientIDs {
+		clientIDs[i] = t.ClientID(i)
+	}
+
+	c := &Cluster{
+		Membership:   membership,
+		ClientIDs:    clientIDs,
+		Net:          NewLoopback(config.NumNodes),
+		nodeExitCs:   make([]chan struct{}, config.NumNodes),
+		nodeStopOnce: make([]sync.Once, config.NumNodes),
+		nodeDoneCs:   make([]chan struct{}, config.NumNodes),
+		nodeErrs:     make([]error, config.NumNodes),
+		committed:    make([]*committedLog, config.NumNodes),
+	}
+
+	for _, clientID := range clientIDs {
+		cryptoModule, err := mirCrypto.ClientPseudo(membership, clientIDs, clientID, mirCrypto.DefaultPseudoSeed)
+		if err != nil {
+			return nil, fmt.Errorf("could not create crypto module of client %d: %w", clientID, err)
+		}
+		c.clientCrypto = append(c.clientCrypto, cryptoModule)
+	}
+
+	for _, nodeID := range membership {
+		issConfig := iss.DefaultConfig(membership)
+		if config.ISSConfig != nil {
+			config.ISSConfig(nodeID, issConfig)
+		}
+		protocol, err := iss.New(nodeID, issConfig, logging.Decorate(config.Logger, "ISS: "))
+		if err != nil {
+			return nil, fmt.Errorf("could not create protocol of node %d: %w", nodeID, err)
+		}
+
+		cryptoModule, err := mirCrypto.NodePseudo(membership, clientIDs, nodeID, mirCrypto.DefaultPseudoSeed)
+		if err != nil {
+			return nil, fmt.Errorf("could not create crypto module of node %d: %w", nodeID, err)
+		}
+
+		nodeConfig := &mirbft.NodeConfig{
+			Logger:       logging.Decorate(config.Logger, fmt.Sprintf("Node %d: ", nodeID)),
+			TickInterval: config.TickInterval,
+		}
+		if config.NodeConfig != nil {
+			config.NodeConfig(nodeID, nodeConfig)
+		}
+
+		app := config.NewApp(nodeID)
+		node, err := mirbft.NewNode(nodeID, nodeConfig, &modules.Modules{
+			Net:           c.Net.Link(nodeID),
+			App:           app,
+			WAL:           simplewal.NewVolatileWAL(),
+			RequestStore:  reqstore.NewVolatileRequestStore(),
+			ClientTracker: clients.SigningTracker(logging.Decorate(config.Logger, "CT: ")),
+			Protocol:      protocol,
+			Crypto:        cryptoModule,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create node %d: %w", nodeID, err)
+		}
+
+		c.Nodes = append(c.Nodes, node)
+		c.Apps = append(c.Apps, app)
+		c.nodeExitCs[nodeID] = make(chan struct{})
+		c.nodeDoneCs[nodeID] = make(chan struct{})
+		c.committed[nodeID] = newCommittedLog()
+	}
+
+	return c, nil
+}
+
+// Start starts the network and runs all Nodes, each in its own goroutine. Start must be called at most once.
+func (c *Cluster) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelCommitted = cancel
+
+	c.Net.Start()
+	for i, node := range c.Nodes {
+
+		// Subscribe to the committed batches before the Node starts, such that none is missed.
+		committedC := node.Committed(ctx, 0)
+		c.committedWg.Add(1)
+		go func(log *committedLog) {
+			defer c.committedWg.Done()
+			for deliver := range committedC {
+				log.append(deliver.Batch.Requests)
+			}
+		}(c.committed[i])
+
+		go func(i int, node *mirbft.Node) {
+			defer close(c.nodeDoneCs[i])
+			c.nodeErrs[i] = node.Run(c.nodeExitCs[i], nil)
+		}(i, node)
+	}
+}
+
+// Stop stops all Nodes and the network and waits until they stopped.
+// It returns an error if any Node failed, i.e., stopped for another reason than being stopped by the Cluster.
+// Stop can be called multiple times, but only after Start.
+func (c *Cluster) Stop() error {
+	c.stopOnce.Do(func() {
+		for _, nodeID := range c.Membership {
+			c.StopNode(nodeID)
+		}
+		for _, doneC := range c.nodeDoneCs {
+			<-doneC
+		}
+		c.Net.Stop()
+		c.cancelCommitted()
+		c.committedWg.Wait()
+	})
+
+	for i, err := range c.nodeErrs {
+		if !errors.Is(err, mirbft.ErrStopped) {
+			return fmt.Errorf("node %d failed: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// StopNode makes a node stop immediately, abandoning its in-flight work, as if it crashed.
+// The Node rejects any further requests. StopNode can be called multiple times.
+func (c *Cluster) StopNode(nodeID t.NodeID) {
+	c.nodeStopOnce[nodeID].Do(func() {
+		close(c.nodeExitCs[nodeID])
+	})
+}
+
+// Submit signs a request of client clientID and submits it to all Nodes, except for the stopped ones.
+func (c *Cluster) Submit(ctx context.Context, clientID t.ClientID, reqNo t.ReqNo, data []byte) error {
+	if int(clientID) >= len(c.ClientIDs) {
+		return fmt.Errorf("unknown client %d", clientID)
+	}
+
+	req := &requestpb.Request{
+		ClientId: clientID.Pb(),
+		ReqNo:    reqNo.Pb(),
+		Data:     data,
+	}
+	h := crypto.SHA256.New()
+	if err := serializing.WriteRequestForHash(h, req); err != nil {
+		return err
+	}
+	authenticator, err := c.clientCrypto[clientID].Sign([][]byte{h.Sum(nil)})
+	if err != nil {
+		return err
+	}
+
+	for i, node := range c.Nodes {
+		err := node.SubmitRequest(ctx, clientID, reqNo, data, authenticator)
+		if err != nil && !errors.Is(err, mirbft.ErrStopping) && !errors.Is(err, mirbft.ErrStopped) {
+			return fmt.Errorf("node %d rejected request %d of client %d: %w", i, reqNo, clientID, err)
+		}
+	}
+	return nil
+}
+
+// Committed returns the references to the requests committed by node nodeID so far, in the order of their commits.
+func (c *Cluster) Committed(nodeID t.NodeID) []*requestpb.RequestRef {
+	return c.committed[nodeID].requests()
+}
+
+// WaitCommitted waits until each Node that has not been stopped committed at least numRequests requests.
+// A request counts as committed as soon as the Node scheduled its delivery to the application (see Node.Committed),
+// so the application may apply the last committed requests only shortly after WaitCommitted returns.
+// It returns an error if ctx is canceled before, or if a Node fails while waiting.
+func (c *Cluster) WaitCommitted(ctx context.Context, numRequests int) error {
+	for i, log := range c.committed {
+		for {
+			n, changedC := log.state()
+			if n >= numRequests {
+				break
+			}
+
+			select {
+			case <-changedC:
+			case <-c.nodeDoneCs[i]:
+				if !errors.Is(c.nodeErrs[i], mirbft.ErrStopped) {
+					return fmt.Errorf("node %d failed: %w", i, c.nodeErrs[i])
+				}
+			case <-c.nodeExitCs[i]:
+			case <-ctx.Done():
+				return fmt.Errorf("node %d committed only %d of %d requests: %w", i, n, numRequests, ctx.Err())
+			}
+
+			// A stopped Node does not commit any more requests.
+			if c.stopped(t.NodeID(i)) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// CheckConsistency returns an error if two Nodes committed different requests at the same position of their logs.
+// The logs of Nodes that are lagging behind are consistent with the others as long as they are their prefixes.
+func (c *Cluster) CheckConsistency() error {
+
+	// The longest of the logs checked so far, which all others must be consistent with.
+	var reference []*requestpb.RequestRef
+	for i := range c.committed {
+		log := c.Committed(t.NodeID(i))
+		for j := 0; j < len(log) && j < len(reference); j++ {
+			if log[j].ClientId != reference[j].ClientId || log[j].ReqNo != reference[j].ReqNo {
+				return fmt.Errorf("node %d committed request %d of client %d at position %d, "+
+					"where another node committed request %d of client %d",
+					i, log[j].ReqNo, log[j].ClientId, j, reference[j].ReqNo, reference[j].ClientId)
+			}
+		}
+		if len(log) > len(reference) {
+			reference = log
+		}
+	}
+	return nil
+}
+
+// stopped returns true if the node has been stopped (see StopNode).
+func (c *Cluster) stopped(nodeID t.NodeID) bool {
+	select {
+	case <-c.nodeExitCs[nodeID]:
+		return true
+	default:
+		return false
+	}
+}
+
+// committedLog records the requests committed by a node. All its methods are safe for concurrent use.
+type committedLog struct {
+	lock sync.Mutex
+	log  []*requestpb.RequestRef
+
+	// Closed and replaced each time requests are appended to the log.
+	changedC chan struct{}
+}
+
+// newCommittedLog returns a new empty committedLog.
+func newCommittedLog() *committedLog {
+	return &committedLog{changedC: make(chan struct{})}
+}
+
+// append appends the committed requests to the log.
+func (cl *committedLog) append(reqRefs []*requestpb.RequestRef) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	cl.log = append(cl.log, reqRefs...)
+	close(cl.changedC)
+	cl.changedC = make(chan struct{})
+}
+
+// requests returns a copy of the log.
+func (cl *committedLog) requests() []*requestpb.RequestRef {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	return append([]*requestpb.RequestRef(nil), cl.log...)
+}
+
+// state returns the length of the log and a channel that is closed when the log changes.
+func (cl *committedLog) state() (int, <-chan struct{}) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	return len(cl.log), cl.changedC
+}
+
+// counterApp is the default application replicated by the Nodes of a Cluster.
+// Its state only consists of the number of applied requests.
+type counterApp struct {
+	applied uint64
+}
+
+func (ca *counterApp) Apply(batch *requestpb.Batch) error {
+	ca.applied += uint64(len(batch.Requests))
+	return nil
+}
+
+func (ca *counterApp) Snapshot() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d", ca.applied)), nil
+}
+
+func (ca *counterApp) RestoreState(snapshot []byte) error {
+	_, err := fmt.Sscanf(string(snapshot), "%d", &ca.applied)
+	return err
+}
@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testkit
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Capacity of the queue of messages from one node to another.
+// Messages sent to a full queue are dropped, as by an overloaded network.
+const queueCapacity = 1 << 14
+
+// Loopback is an in-memory network connecting nodes running in the same process.
+// The messages from each node to each other node are delivered in order by a dedicated goroutine.
+type Loopback struct {
+
+	// Queues of messages, indexed by source and destination.
+	queues [][]chan *messagepb.Message
+
+	// Channels from which each node reads its received messages.
+	sinks []chan modules.ReceivedMessage
+
+	wg    sync.WaitGroup
+	doneC chan struct{}
+}
+
+// NewLoopback returns a new Loopback network connecting numNodes nodes with IDs 0 to numNodes-1.
+func NewLoopback(numNodes int) *Loopback {
+	queues := make([][]chan *messagepb.Message, numNodes)
+	sinks := make([]chan modules.ReceivedMessage, numNodes)
+	for i := range queues {
+		queues[i] = make([]chan *messagepb.Message, numNodes)
+		for j := range queues[i] {
+			queues[i][j] = make(chan *messagepb.Message, queueCapacity)
+		}
+		sinks[i] = make(chan modules.ReceivedMessage)
+	}
+
+	return &Loopback{
+		queues: queues,
+		sinks:  sinks,
+		doneC:  make(chan struct{}),
+	}
+}
+
+// Link returns the Net module of node ownID.
+func (l *Loopback) Link(ownID t.NodeID) modules.Net {
+	return &loopbackLink{loopback: l, ownID: ownID}
+}
+
+// Start launches the goroutines delivering the messages.
+// Messages sent before Start are queued and delivered after it.
+func (l *Loopback) Start() {
+	for i, queues := range l.queues {
+		for j, queue := range queues {
+			l.wg.Add(1)
+			go func(from t.NodeID, sink chan<- modules.ReceivedMessage, queue <-chan *messagepb.Message) {
+				defer l.wg.Done()
+				for {
+					select {
+					case msg := <-queue:
+						select {
+						case sink <- modules.ReceivedMessage{Sender: from, Msg: msg}:
+						case <-l.doneC:
+							return
+						}
+					case <-l.doneC:
+						return
+					}
+				}
+			}(t.NodeID(i), l.sinks[j], queue)
+		}
+	}
+}
+
+// Stop stops the delivery of messages and waits until all delivering goroutines returned.
+func (l *Loopback) Stop() {
+	close(l.doneC)
+	l.wg.Wait()
+}
+
+// loopbackLink is the Net module of a single node connected to a Loopback network.
+type loopbackLink struct {
+	loopback *Loopback
+	ownID    t.NodeID
+}
+
+// Send enqueues msg for delivery to dest, dropping it if the queue is full.
+func (ll *loopbackLink) Send(dest t.NodeID, msg *messagepb.Message) error {
+	select {
+	case ll.loopback.queues[ll.ownID][dest] <- msg:
+	default:
+	}
+	return nil
+}
+
+func (ll *loopbackLink) ReceiveChan() <-chan modules.ReceivedMessage {
+	return ll.loopback.sinks[ll.ownID]
+}
@@ -0,0 +1,13 @@
+package testkit_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTestkit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Testkit Suite")
+}
@@ -0,0 +1,136 @@
+package testkit_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/testkit"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// counterApp counts the applied requests of each client.
+type counterApp struct {
+	lock     sync.Mutex
+	counters map[t.ClientID]int
+}
+
+func (app *counterApp) Apply(batch *requestpb.Batch) error {
+	app.lock.Lock()
+	defer app.lock.Unlock()
+	for _, reqRef := range batch.Requests {
+		app.counters[t.ClientID(reqRef.ClientId)]++
+	}
+	return nil
+}
+
+func (app *counterApp) Snapshot() ([]byte, error) {
+	app.lock.Lock()
+	defer app.lock.Unlock()
+	return []byte(fmt.Sprint(app.counters)), nil
+}
+
+func (app *counterApp) RestoreState(snapshot []byte) error {
+	return fmt.Errorf("not supported")
+}
+
+func (app *counterApp) get(clientID t.ClientID) int {
+	app.lock.Lock()
+	defer app.lock.Unlock()
+	return app.counters[clientID]
+}
+
+var _ = Describe("Cluster", func() {
+	var (
+		cluster *testkit.Cluster
+		ctx     context.Context
+		cancel  context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	})
+
+	AfterEach(func() {
+		cancel()
+		if cluster != nil {
+			Expect(cluster.Stop()).To(Succeed())
+		}
+	})
+
+	submit := func(numClients, requestsPerClient int) {
+		for reqNo := 0; reqNo < requestsPerClient; reqNo++ {
+			for clientID := 0; clientID < numClients; clientID++ {
+				Expect(cluster.Submit(ctx, t.ClientID(clientID), t.ReqNo(reqNo), []byte("data"))).To(Succeed())
+			}
+		}
+	}
+
+	It("commits the submitted requests at all nodes", func() {
+		var err error
+		cluster, err = testkit.NewCluster(&testkit.Config{NumClients: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Nodes).To(HaveLen(4))
+
+		cluster.Start()
+		submit(2, 10)
+		Expect(cluster.WaitCommitted(ctx, 20)).To(Succeed())
+		Expect(cluster.CheckConsistency()).To(Succeed())
+		for _, nodeID := range cluster.Membership {
+			Expect(cluster.Committed(nodeID)).To(HaveLen(20))
+		}
+	})
+
+	It("replicates the given application", func() {
+		var err error
+		cluster, err = testkit.NewCluster(&testkit.Config{
+			NumNodes:   1,
+			NumClients: 3,
+			NewApp: func(id t.NodeID) modules.App {
+				return &counterApp{counters: make(map[t.ClientID]int)}
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		cluster.Start()
+		submit(3, 5)
+		Expect(cluster.WaitCommitted(ctx, 15)).To(Succeed())
+		for _, clientID := range cluster.ClientIDs {
+			app := cluster.Apps[0].(*counterApp)
+			Eventually(func() int { return app.get(clientID) }).Should(Equal(5))
+		}
+	})
+
+	It("keeps committing requests after a node that is not a leader stopped", func() {
+		var err error
+		cluster, err = testkit.NewCluster(&testkit.Config{
+			ISSConfig: func(id t.NodeID, config *iss.Config) {
+				config.LeaderPolicy = &iss.SimpleLeaderPolicy{Membership: []t.NodeID{0, 1, 2}}
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		cluster.Start()
+		cluster.StopNode(3)
+		submit(1, 10)
+		Expect(cluster.WaitCommitted(ctx, 10)).To(Succeed())
+		Expect(cluster.CheckConsistency()).To(Succeed())
+		Expect(cluster.Committed(3)).To(BeEmpty())
+	})
+
+	It("rejects requests of unknown clients", func() {
+		var err error
+		cluster, err = testkit.NewCluster(&testkit.Config{NumNodes: 1})
+		Expect(err).NotTo(HaveOccurred())
+
+		cluster.Start()
+		Expect(cluster.Submit(ctx, 1, 0, []byte("data"))).NotTo(Succeed())
+	})
+})
@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package types
 
+import "math"
+
 // ================================================================================
 
 // NodeID represents the numeric ID of a node.
@@ -26,6 +28,16 @@ func NodeIDSlicePb(nids []NodeID) []uint64 {
 	return pbSlice
 }
 
+// NodeIDSlice converts a slice of NodeIDs represented directly as their underlying native type
+// to a slice of abstractly typed node IDs.
+func NodeIDSlice(nids []uint64) []NodeID {
+	nodeIDs := make([]NodeID, len(nids), len(nids))
+	for i, nid := range nids {
+		nodeIDs[i] = NodeID(nid)
+	}
+	return nodeIDs
+}
+
 // ================================================================================
 
 // ClientID represents the numeric ID of a client.
@@ -36,6 +48,22 @@ func (cid ClientID) Pb() uint64 {
 	return uint64(cid)
 }
 
+// ClientIDSlicePb converts a slice of ClientIDs to a slice of the native type underlying ClientID.
+// This is required for serialization using Protocol Buffers.
+func ClientIDSlicePb(cids []ClientID) []uint64 {
+	pbSlice := make([]uint64, len(cids), len(cids))
+	for i, cid := range cids {
+		pbSlice[i] = cid.Pb()
+	}
+	return pbSlice
+}
+
+// ConfigClientID is the reserved ID of the client submitting configuration requests.
+// Configuration requests are submitted, authenticated, and ordered like any other requests,
+// but their payload is interpreted by the ordering protocol itself (e.g., as an isspb.ConfigChange with ISS),
+// at the point in the total order at which they are committed.
+const ConfigClientID = ClientID(math.MaxUint64)
+
 // ================================================================================
 
 // SeqNr represents the sequence number of a batch as assigned by the ordering protocol.
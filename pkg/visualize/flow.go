@@ -0,0 +1,258 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package visualize
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recordingpb"
+)
+
+// MessageFlow aggregates the messages sent and received by nodes, as recorded by an eventlog.Recorder,
+// and renders them as a DOT graph (see DOT).
+// The entries of the recordings of multiple nodes can be added to the same MessageFlow.
+// Sent messages are counted from the SendMessage events in the recording of the sender
+// and received messages from the MessageReceived events in the recording of the receiver.
+// Bundled and reliably transmitted messages are counted individually.
+type MessageFlow struct {
+
+	// A node is considered silent if it did not send any message
+	// during this period before the end of the recordings.
+	silence time.Duration
+
+	// The nodes whose recordings have been added.
+	recorded map[uint64]struct{}
+
+	// Number of sent and received messages of each type, per pair of nodes.
+	links map[link]*linkCounts
+
+	// Time (in milliseconds, as recorded) of the last message sent by each node.
+	lastSent map[uint64]int64
+
+	// Time of the last entry of any recording.
+	end int64
+}
+
+// link identifies the direction from a sender to a receiver.
+type link struct {
+	from uint64
+	to   uint64
+}
+
+// linkCounts counts the messages of each type sent and received on a link.
+type linkCounts struct {
+	sent     map[string]int
+	received map[string]int
+}
+
+// NewMessageFlow returns a new empty MessageFlow.
+// Nodes that did not send any message for the duration silence before the end of the recordings are highlighted.
+// If silence is not positive, no nodes are highlighted.
+func NewMessageFlow(silence time.Duration) *MessageFlow {
+	return &MessageFlow{
+		silence:  silence,
+		recorded: make(map[uint64]struct{}),
+		links:    make(map[link]*linkCounts),
+		lastSent: make(map[uint64]int64),
+	}
+}
+
+// Add adds the messages of a recorded entry to the flow.
+func (mf *MessageFlow) Add(entry *recordingpb.Entry) {
+	mf.recorded[entry.NodeId] = struct{}{}
+	if entry.Time > mf.end {
+		mf.end = entry.Time
+	}
+
+	for _, event := range entry.Events {
+		switch e := event.Type.(type) {
+		case *eventpb.Event_SendMessage:
+			mf.lastSent[entry.NodeId] = entry.Time
+			for _, msgType := range messageTypes(e.SendMessage.Msg) {
+				for _, dest := range e.SendMessage.Destinations {
+					mf.counts(entry.NodeId, dest).sent[msgType]++
+				}
+			}
+		case *eventpb.Event_MessageReceived:
+			for _, msgType := range messageTypes(e.MessageReceived.Msg) {
+				mf.counts(e.MessageReceived.From, entry.NodeId).received[msgType]++
+			}
+		}
+	}
+}
+
+// counts returns the message counts of the link from one node to another, creating them if necessary.
+func (mf *MessageFlow) counts(from uint64, to uint64) *linkCounts {
+	l := link{from: from, to: to}
+	counts, ok := mf.links[l]
+	if !ok {
+		counts = &linkCounts{sent: make(map[string]int), received: make(map[string]int)}
+		mf.links[l] = counts
+	}
+	return counts
+}
+
+// DOT returns a DOT graph of the message flow. Each node is drawn with the time of the last message it sent
+// and each link with the number of messages of each type sent and received on it.
+// Silent nodes are filled in red. A link is drawn in red if both its ends have been recorded
+// and fewer messages of some type have been received than sent on it.
+// Note that the time of the messages is taken from the recordings as-is, i.e., the recordings of different nodes
+// are only comparable if they have been started at the same time.
+func (mf *MessageFlow) DOT() string {
+	g := newGraph("message flow")
+	g.line(`node [shape=circle, style=filled, fillcolor=white, fontname="monospace"];`)
+	g.line(`edge [fontname="monospace", fontsize=10];`)
+
+	nodes := make(map[uint64]struct{})
+	for nodeID := range mf.recorded {
+		nodes[nodeID] = struct{}{}
+	}
+	for l := range mf.links {
+		nodes[l.from] = struct{}{}
+		nodes[l.to] = struct{}{}
+	}
+
+	for _, nodeID := range sortedNodes(nodes) {
+		label := fmt.Sprintf("node %d", nodeID)
+		attrs := ""
+		if _, ok := mf.recorded[nodeID]; !ok {
+			label += "\nnot recorded"
+			attrs = "style=dashed"
+		} else if lastSent, ok := mf.lastSent[nodeID]; ok {
+			label += fmt.Sprintf("\nlast sent at %d ms", lastSent)
+			if mf.silent(lastSent) {
+				attrs = "fillcolor=" + stalledColor
+			}
+		} else {
+			label += "\nnever sent"
+			if mf.silent(0) {
+				attrs = "fillcolor=" + stalledColor
+			}
+		}
+		g.node(nodeName(nodeID), label, attrs)
+	}
+
+	links := make([]link, 0, len(mf.links))
+	for l := range mf.links {
+		links = append(links, l)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].from != links[j].from {
+			return links[i].from < links[j].from
+		}
+		return links[i].to < links[j].to
+	})
+
+	for _, l := range links {
+		_, senderRecorded := mf.recorded[l.from]
+		_, receiverRecorded := mf.recorded[l.to]
+		counts := mf.links[l]
+
+		lost := false
+		lines := make([]string, 0)
+		for _, msgType := range sortedTypes(counts) {
+			sent, received := counts.sent[msgType], counts.received[msgType]
+			switch {
+			case senderRecorded && receiverRecorded:
+				lines = append(lines, fmt.Sprintf("%s %d/%d", msgType, sent, received))
+				lost = lost || received < sent
+			case senderRecorded:
+				lines = append(lines, fmt.Sprintf("%s %d sent", msgType, sent))
+			default:
+				lines = append(lines, fmt.Sprintf("%s %d received", msgType, received))
+			}
+		}
+
+		attrs := fmt.Sprintf("label=%s", quote(strings.Join(lines, "\n")))
+		if lost {
+			attrs += ", color=" + stalledColor + ", fontcolor=" + stalledColor
+		}
+		g.edge(nodeName(l.from), nodeName(l.to), attrs)
+	}
+
+	return g.String()
+}
+
+// silent returns true if a node that last sent a message at time lastSent is considered silent.
+func (mf *MessageFlow) silent(lastSent int64) bool {
+	return mf.silence > 0 && time.Duration(mf.end-lastSent)*time.Millisecond >= mf.silence
+}
+
+// messageTypes returns the types of the protocol messages contained in msg,
+// unwrapping bundles and reliably transmitted messages.
+func messageTypes(msg *messagepb.Message) []string {
+	switch m := msg.GetType().(type) {
+	case nil:
+		return nil
+	case *messagepb.Message_Bundle:
+		types := make([]string, 0, len(m.Bundle.Msgs))
+		for _, bundled := range m.Bundle.Msgs {
+			types = append(types, messageTypes(bundled)...)
+		}
+		return types
+	case *messagepb.Message_ReliableData:
+		return messageTypes(m.ReliableData.Msg)
+	case *messagepb.Message_Iss:
+		switch issMsg := m.Iss.GetType().(type) {
+		case nil:
+			return []string{"Iss"}
+		case *isspb.ISSMessage_Sb:
+			if sbMsg := issMsg.Sb.GetMsg().GetType(); sbMsg != nil {
+				return []string{typeName(sbMsg, "SBInstanceMessage_")}
+			}
+			return []string{"Sb"}
+		default:
+			return []string{typeName(issMsg, "ISSMessage_")}
+		}
+	default:
+		return []string{typeName(m, "Message_")}
+	}
+}
+
+// typeName returns the name of the type of a oneof field wrapper (a pointer to a struct), without the given prefix.
+func typeName(wrapper interface{}, prefix string) string {
+	return strings.TrimPrefix(reflect.TypeOf(wrapper).Elem().Name(), prefix)
+}
+
+// nodeName returns the ID of the graph node representing a node.
+func nodeName(nodeID uint64) string {
+	return fmt.Sprintf("node_%d", nodeID)
+}
+
+// sortedNodes returns the given node IDs in increasing order.
+func sortedNodes(nodes map[uint64]struct{}) []uint64 {
+	sorted := make([]uint64, 0, len(nodes))
+	for nodeID := range nodes {
+		sorted = append(sorted, nodeID)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// sortedTypes returns the types of all messages sent or received on a link in alphabetical order.
+func sortedTypes(counts *linkCounts) []string {
+	types := make([]string, 0, len(counts.sent)+len(counts.received))
+	for msgType := range counts.sent {
+		types = append(types, msgType)
+	}
+	for msgType := range counts.received {
+		if _, ok := counts.sent[msgType]; !ok {
+			types = append(types, msgType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package visualize
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// SVG converts a DOT graph to an SVG image using the dot tool of Graphviz, which must be installed.
+func SVG(dot string) ([]byte, error) {
+	path, err := exec.LookPath("dot")
+	if err != nil {
+		return nil, fmt.Errorf("rendering SVG requires Graphviz: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, "-Tsvg")
+	cmd.Stdin = strings.NewReader(dot)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("dot failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// graph accumulates the statements of a directed DOT graph.
+type graph struct {
+	out strings.Builder
+}
+
+// newGraph starts a new directed graph with the given name.
+func newGraph(name string) *graph {
+	g := &graph{}
+	g.out.WriteString("digraph " + quote(name) + " {\n")
+	return g
+}
+
+// String closes the graph and returns its DOT representation. No statements must be added afterwards.
+func (g *graph) String() string {
+	g.out.WriteString("}\n")
+	return g.out.String()
+}
+
+// line writes a raw statement.
+func (g *graph) line(statement string) {
+	g.out.WriteString(statement)
+	g.out.WriteByte('\n')
+}
+
+// attr sets an attribute of the current graph or subgraph.
+func (g *graph) attr(name string, value string) {
+	g.line(fmt.Sprintf("%s=%s;", name, quote(value)))
+}
+
+// node writes a node with the given label and additional (already formatted) attributes.
+func (g *graph) node(id string, label string, attrs string) {
+	if attrs != "" {
+		attrs = ", " + attrs
+	}
+	g.line(fmt.Sprintf("%s [label=%s%s];", quote(id), quote(label), attrs))
+}
+
+// edge writes an edge with the given (already formatted) attributes.
+func (g *graph) edge(from string, to string, attrs string) {
+	if attrs != "" {
+		attrs = " [" + attrs + "]"
+	}
+	g.line(fmt.Sprintf("%s -> %s%s;", quote(from), quote(to), attrs))
+}
+
+// quote returns s as a DOT string literal. Line breaks are represented by the \n escape sequence,
+// which Graphviz renders as a line break in labels.
+func quote(s string) string {
+	return strconv.Quote(s)
+}
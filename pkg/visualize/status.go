@@ -0,0 +1,209 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package visualize renders the state of a node and the message flow between nodes as Graphviz graphs
+// (in the DOT language), meant for debugging a network that stopped making progress.
+// The graphs highlight what the protocol is waiting for: the sequence number blocking the delivery,
+// the sequence numbers still uncommitted before the next epoch can start, suspected leaders,
+// nodes that fell silent and messages that were sent but never received.
+// The DOT output can be converted to an image using the dot tool of Graphviz (see SVG),
+// and its format may change at any time.
+package visualize
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+// Fill colors of the sequence numbers in the status graph.
+const (
+	committedColor = "palegreen"
+	proposedColor  = "khaki"
+	emptyColor     = "white"
+	stalledColor   = "tomato"
+)
+
+// StatusDOT returns a DOT graph of the status of a node of the ISS protocol.
+// Each orderer is drawn as a cluster containing its sequence numbers (colored by whether they are committed,
+// proposed, or empty), with the buckets assigned to it pointing at it.
+// The first sequence number not yet delivered to the application is outlined in red, and, if the node is waiting
+// for the next epoch, all uncommitted sequence numbers of the current epoch point at the next epoch.
+// The recent epoch changes are drawn as a chain labeled with their causes.
+func StatusDOT(s *statuspb.NodeStatus) string {
+	g := newGraph("status")
+	g.attr("rankdir", "LR")
+	g.line(`node [shape=box, style=filled, fillcolor=white, fontname="monospace"];`)
+
+	iss := s.GetProtocol().GetIss()
+	if iss == nil {
+		g.attr("label", "Protocol: no status available")
+		return g.String()
+	}
+
+	title := fmt.Sprintf("Epoch %d: delivered up to SN %d, last stable checkpoint at SN %d",
+		iss.Epoch, iss.NextDeliveredSn, iss.LastStableSn)
+	if slow := slowNodes(iss); slow != "" {
+		title += "\nslow nodes: " + slow
+	}
+	g.attr("label", title)
+	g.attr("labelloc", "t")
+
+	unresponsive := make(map[uint64]struct{})
+	handoff := make(map[uint64]struct{})
+	if next := iss.NextEpoch; next != nil {
+		for _, leader := range next.UnresponsiveLeaders {
+			unresponsive[leader] = struct{}{}
+		}
+		for _, leader := range next.HandoffLeaders {
+			handoff[leader] = struct{}{}
+		}
+	}
+
+	var uncommitted []string
+	for _, orderer := range iss.Orderers {
+		uncommitted = append(uncommitted, writeOrderer(g, iss, orderer, unresponsive, handoff)...)
+	}
+
+	for _, bucket := range iss.Buckets {
+		id := fmt.Sprintf("bucket_%d", bucket.Id)
+		g.node(id, fmt.Sprintf("bucket %d\n%d ready", bucket.Id, bucket.Requests), "shape=folder")
+		g.edge(id, ordererID(bucket.Instance), "")
+	}
+
+	if next := iss.NextEpoch; next != nil {
+		label := fmt.Sprintf("next epoch %d\nstarts at SN %d\n%d SNs uncommitted", next.Epoch, next.FirstSn, next.Uncommitted)
+		if len(next.PendingMembership) > 0 {
+			label += "\nnew members: " + joinUint64(next.PendingMembership)
+		}
+		g.node("next_epoch", label, "shape=doubleoctagon")
+		for _, sn := range uncommitted {
+			g.edge(sn, "next_epoch", "style=dashed, color=gray")
+		}
+	}
+
+	writeEpochHistory(g, iss.EpochHistory)
+
+	return g.String()
+}
+
+// writeOrderer writes the cluster of an orderer to g and returns the node IDs of its uncommitted sequence numbers.
+func writeOrderer(
+	g *graph,
+	iss *isspb.Status,
+	orderer *isspb.SBStatus,
+	unresponsive map[uint64]struct{},
+	handoff map[uint64]struct{},
+) []string {
+
+	committed := uint64Set(orderer.Committed)
+	proposed := uint64Set(orderer.Proposed)
+
+	g.line(fmt.Sprintf("subgraph cluster_instance_%d {", orderer.Instance))
+	g.attr("label", fmt.Sprintf("instance %d (%d/%d committed)",
+		orderer.Instance, len(orderer.Committed), len(orderer.SeqNrs)))
+
+	leaderLabel := fmt.Sprintf("instance %d\nleader %d", orderer.Instance, orderer.Leader)
+	leaderAttrs := "shape=oval"
+	if _, ok := unresponsive[orderer.Leader]; ok {
+		leaderLabel += "\nsuspected"
+		leaderAttrs += ", fillcolor=" + stalledColor
+	} else if _, ok := handoff[orderer.Leader]; ok {
+		leaderLabel += "\nhanded off"
+		leaderAttrs += ", fillcolor=lightgray"
+	}
+	g.node(ordererID(orderer.Instance), leaderLabel, leaderAttrs)
+
+	// Chain the sequence numbers in their order, starting at the orderer.
+	previous := ordererID(orderer.Instance)
+	var uncommitted []string
+	for _, sn := range orderer.SeqNrs {
+		id := fmt.Sprintf("sn_%d", sn)
+		attrs := "fillcolor=" + emptyColor
+		if _, ok := committed[sn]; ok {
+			attrs = "fillcolor=" + committedColor
+		} else {
+			if _, ok := proposed[sn]; ok {
+				attrs = "fillcolor=" + proposedColor
+			}
+			uncommitted = append(uncommitted, id)
+		}
+		if sn == iss.NextDeliveredSn {
+			attrs += ", color=" + stalledColor + ", penwidth=3"
+		}
+		g.node(id, fmt.Sprintf("%d", sn), attrs)
+		g.edge(previous, id, "arrowhead=none")
+		previous = id
+	}
+	g.line("}")
+
+	return uncommitted
+}
+
+// writeEpochHistory writes the chain of the given epoch changes to g.
+func writeEpochHistory(g *graph, history []*isspb.EpochChange) {
+	if len(history) == 0 {
+		return
+	}
+
+	g.line("subgraph cluster_epoch_history {")
+	g.attr("label", "epoch history")
+	previous := ""
+	for _, change := range history {
+		id := fmt.Sprintf("epoch_%d", change.Epoch)
+		label := fmt.Sprintf("epoch %d at SN %d\nleaders: %s", change.Epoch, change.FirstSn, joinUint64(change.Leaders))
+		for _, cause := range change.Causes {
+			switch cause.Reason {
+			case isspb.EpochChangeReason_CONFIG_CHANGE, isspb.EpochChangeReason_RESTORED:
+				label += "\n" + strings.ToLower(cause.Reason.String())
+			default:
+				label += fmt.Sprintf("\n%s: %d", strings.ToLower(cause.Reason.String()), cause.NodeId)
+			}
+		}
+		g.node(id, label, "shape=note")
+		if previous != "" {
+			g.edge(previous, id, "")
+		}
+		previous = id
+	}
+	g.line("}")
+}
+
+// slowNodes returns a comma-separated list of the nodes considered slow, with the number of SNs they are behind.
+func slowNodes(iss *isspb.Status) string {
+	slow := make([]string, 0)
+	for _, nodeLag := range iss.NodeLags {
+		if nodeLag.Slow {
+			slow = append(slow, fmt.Sprintf("%d (%d SNs behind)", nodeLag.NodeId, nodeLag.Lag))
+		}
+	}
+	return strings.Join(slow, ", ")
+}
+
+// ordererID returns the ID of the graph node representing an orderer.
+func ordererID(instance uint64) string {
+	return fmt.Sprintf("instance_%d", instance)
+}
+
+// joinUint64 returns a comma-separated list of the given numbers.
+func joinUint64(numbers []uint64) string {
+	strs := make([]string, len(numbers))
+	for i, n := range numbers {
+		strs[i] = fmt.Sprintf("%d", n)
+	}
+	return strings.Join(strs, ", ")
+}
+
+// uint64Set returns the set of the given numbers.
+func uint64Set(numbers []uint64) map[uint64]struct{} {
+	set := make(map[uint64]struct{}, len(numbers))
+	for _, n := range numbers {
+		set[n] = struct{}{}
+	}
+	return set
+}
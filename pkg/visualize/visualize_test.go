@@ -0,0 +1,127 @@
+package visualize_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspbftpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recordingpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	"github.com/hyperledger-labs/mirbft/pkg/visualize"
+)
+
+var _ = Describe("StatusDOT", func() {
+	var status *statuspb.NodeStatus
+
+	BeforeEach(func() {
+		status = &statuspb.NodeStatus{Protocol: &statuspb.ProtocolStatus{Type: &statuspb.ProtocolStatus_Iss{
+			Iss: &isspb.Status{
+				Epoch:           1,
+				NextDeliveredSn: 5,
+				LastStableSn:    4,
+				Orderers: []*isspb.SBStatus{
+					{Leader: 0, Instance: 0, SeqNrs: []uint64{4, 6}, Committed: []uint64{4, 6}},
+					{Leader: 1, Instance: 1, SeqNrs: []uint64{5, 7}, Proposed: []uint64{7}},
+				},
+				Buckets: []*isspb.BucketStatus{
+					{Id: 0, Leader: 0, Instance: 0, Requests: 2},
+					{Id: 1, Leader: 1, Instance: 1, Requests: 3},
+				},
+				NextEpoch: &isspb.EpochTransitionStatus{
+					Epoch:               2,
+					FirstSn:             8,
+					Uncommitted:         2,
+					Leaders:             []uint64{0, 1},
+					UnresponsiveLeaders: []uint64{1},
+				},
+				EpochHistory: []*isspb.EpochChange{{
+					Epoch:   1,
+					FirstSn: 4,
+					Leaders: []uint64{0, 1},
+					Causes:  []*isspb.EpochChangeCause{{Reason: isspb.EpochChangeReason_LEADER_STATS, NodeId: 2}},
+				}},
+			},
+		}}}
+	})
+
+	It("renders the orderers, buckets and epoch transition", func() {
+		dot := visualize.StatusDOT(status)
+		Expect(dot).To(HavePrefix(`digraph "status" {`))
+		Expect(dot).To(HaveSuffix("}\n"))
+		Expect(dot).To(ContainSubstring(`"sn_4" [label="4", fillcolor=palegreen];`))
+		Expect(dot).To(ContainSubstring(`"sn_7" [label="7", fillcolor=khaki];`))
+		Expect(dot).To(ContainSubstring(`"bucket_1" -> "instance_1";`))
+		Expect(dot).To(ContainSubstring(`"instance_1" -> "sn_5" [arrowhead=none];`))
+		Expect(dot).To(ContainSubstring(`"epoch_1" [label="epoch 1 at SN 4\nleaders: 0, 1\nleader_stats: 2", shape=note];`))
+	})
+
+	It("highlights what the node is waiting for", func() {
+		dot := visualize.StatusDOT(status)
+		Expect(dot).To(ContainSubstring(`"sn_5" [label="5", fillcolor=white, color=tomato, penwidth=3];`))
+		Expect(dot).To(ContainSubstring(`"instance_1" [label="instance 1\nleader 1\nsuspected", shape=oval, fillcolor=tomato];`))
+		Expect(dot).To(ContainSubstring(`"sn_5" -> "next_epoch"`))
+		Expect(dot).To(ContainSubstring(`"sn_7" -> "next_epoch"`))
+		Expect(dot).NotTo(ContainSubstring(`"sn_6" -> "next_epoch"`))
+	})
+
+	It("renders a status without protocol status", func() {
+		Expect(visualize.StatusDOT(&statuspb.NodeStatus{})).To(ContainSubstring("no status available"))
+	})
+})
+
+var _ = Describe("MessageFlow", func() {
+	preprepare := &messagepb.Message{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{
+		Type: &isspb.ISSMessage_Sb{Sb: &isspb.SBMessage{Msg: &isspb.SBInstanceMessage{
+			Type: &isspb.SBInstanceMessage_PbftPreprepare{PbftPreprepare: &isspbftpb.Preprepare{Sn: 1}},
+		}}},
+	}}}
+	checkpoint := &messagepb.Message{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{
+		Type: &isspb.ISSMessage_Checkpoint{Checkpoint: &isspb.Checkpoint{Sn: 4}},
+	}}}
+	bundle := &messagepb.Message{Type: &messagepb.Message_Bundle{Bundle: &messagepb.MessageBundle{
+		Msgs: []*messagepb.Message{preprepare, checkpoint},
+	}}}
+
+	send := func(msg *messagepb.Message, destinations ...uint64) *eventpb.Event {
+		return &eventpb.Event{Type: &eventpb.Event_SendMessage{SendMessage: &eventpb.SendMessage{
+			Destinations: destinations,
+			Msg:          msg,
+		}}}
+	}
+	receive := func(from uint64, msg *messagepb.Message) *eventpb.Event {
+		return &eventpb.Event{Type: &eventpb.Event_MessageReceived{MessageReceived: &eventpb.MessageReceived{
+			From: from,
+			Msg:  msg,
+		}}}
+	}
+
+	var flow *visualize.MessageFlow
+
+	BeforeEach(func() {
+		flow = visualize.NewMessageFlow(time.Second)
+		flow.Add(&recordingpb.Entry{NodeId: 0, Time: 10, Events: []*eventpb.Event{send(bundle, 1, 2)}})
+		flow.Add(&recordingpb.Entry{NodeId: 1, Time: 20, Events: []*eventpb.Event{receive(0, bundle)}})
+		flow.Add(&recordingpb.Entry{NodeId: 1, Time: 2000, Events: []*eventpb.Event{send(checkpoint, 0)}})
+	})
+
+	It("counts the unwrapped messages on each link", func() {
+		dot := flow.DOT()
+		Expect(dot).To(HavePrefix(`digraph "message flow" {`))
+		Expect(dot).To(ContainSubstring(`"node_0" -> "node_1" [label="Checkpoint 1/1\nPbftPreprepare 1/1"];`))
+		Expect(dot).To(ContainSubstring(`"node_0" -> "node_2" [label="Checkpoint 1 sent\nPbftPreprepare 1 sent"];`))
+		Expect(dot).To(ContainSubstring(`"node_2" [label="node 2\nnot recorded", style=dashed];`))
+	})
+
+	It("highlights silent nodes and lost messages", func() {
+		dot := flow.DOT()
+		Expect(dot).To(ContainSubstring(`"node_0" [label="node 0\nlast sent at 10 ms", fillcolor=tomato];`))
+		Expect(dot).To(ContainSubstring(`"node_1" [label="node 1\nlast sent at 2000 ms"];`))
+		Expect(dot).To(ContainSubstring(
+			`"node_1" -> "node_0" [label="Checkpoint 1/0", color=tomato, fontcolor=tomato];`))
+	})
+})
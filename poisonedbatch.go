@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// PoisonedBatchError is the error with which a Node halts when the application panics while applying a batch.
+// Instead of the panic bringing down the whole process (including all of the Node's goroutines),
+// the Node records a PoisonedBatch event (visible to the event Interceptor, if any)
+// and stops in an orderly manner, returning a PoisonedBatchError from Node.Run.
+// The error carries the offending batch, so that the application bug can be reproduced and diagnosed.
+type PoisonedBatchError struct {
+
+	// Sequence number at which the offending batch has been delivered.
+	Sn t.SeqNr
+
+	// The batch the application failed to apply.
+	Batch *requestpb.Batch
+
+	// Description of the panic, including the value passed to panic() and the stack trace.
+	Reason string
+}
+
+// newPoisonedBatchError creates a new PoisonedBatchError from the corresponding event.
+func newPoisonedBatchError(pb *eventpb.PoisonedBatch) *PoisonedBatchError {
+	return &PoisonedBatchError{
+		Sn:     t.SeqNr(pb.Sn),
+		Batch:  pb.Batch,
+		Reason: pb.Reason,
+	}
+}
+
+// Error returns a description of the poisoned batch, including the reason of the failure.
+func (e *PoisonedBatchError) Error() string {
+	return fmt.Sprintf("application failed applying batch %d (%d requests): %s",
+		e.Sn, len(e.Batch.GetRequests()), e.Reason)
+}
+
+// safeApplyBatch applies a batch to the application, recovering from any panic that occurs during the application.
+// If the application panics, safeApplyBatch returns a PoisonedBatch event describing the panic
+// (with the offending batch attached). Otherwise, it returns nil (and the error returned by the application, if any).
+// If the application implements modules.ClientPartitionedApp, the requests of different clients are applied concurrently.
+func safeApplyBatch(app modules.App, sn t.SeqNr, batch *requestpb.Batch) (poisoned *eventpb.Event, err error) {
+	if partitionedApp, ok := app.(modules.ClientPartitionedApp); ok {
+		return safeApplyBatchByClient(partitionedApp, sn, batch)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			poisoned = events.PoisonedBatch(sn, batch,
+				fmt.Sprintf("panic in application: %v\nStack trace:\n%s", r, string(debug.Stack())))
+			err = nil
+		}
+	}()
+
+	return nil, app.Apply(batch)
+}
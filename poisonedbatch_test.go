@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The poisoned batch test makes the application of each node panic
+// and checks that the nodes halt in an orderly manner, reporting the offending batch.
+var _ = Describe("Poisoned batch test", func() {
+
+	It("halts nodes whose application panics", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.App.PanicAt = 1
+		}
+
+		// The nodes are expected to halt on their own.
+		// Once some nodes halt, the others might not be able to deliver any batch and are stopped by runUntil.
+		finalStatuses := deployment.RunUntil(deployment.AnyHalted())
+		Expect(finalStatuses).NotTo(BeNil())
+
+		// At least one node must have halted on the poisoned batch.
+		numPoisoned := 0
+		for _, status := range finalStatuses {
+			if poisoned, ok := status.ExitErr.(*mirbft.PoisonedBatchError); ok {
+				Expect(poisoned.Batch).NotTo(BeNil())
+				numPoisoned++
+			} else {
+				Expect(status.ExitErr).To(Equal(mirbft.ErrStopped))
+			}
+		}
+		Expect(numPoisoned).To(BeNumerically(">", 0))
+	})
+})
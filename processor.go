@@ -0,0 +1,423 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstoremetrics"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// A Processor processes the lists of events destined to one of the Node's modules.
+// For each list of input events, it performs the corresponding work (e.g. computing hashes or sending messages)
+// and returns the list of resulting events, which the Node routes to the modules that consume them.
+// Using custom Processors (see NodeConfig.Processors), the user can change how the Node drives its modules,
+// e.g. by processing events in parallel or by batching expensive operations.
+type Processor interface {
+
+	// Start is called by the Node before the first invocation of Process.
+	// If Start returns an error, the Node does not start.
+	Start() error
+
+	// Process processes a list of input events and returns the list of resulting events.
+	// The follow-up events attached to the input events (see events.Strip) must be included in the output.
+	// The Node never invokes Process concurrently on the same Processor.
+	// Returning an error makes the Node halt.
+	Process(eventsIn *events.EventList) (*events.EventList, error)
+
+	// Stop is called by the Node after the last invocation of Process, when the Node stops.
+	Stop()
+}
+
+// The Processors struct groups the Processors the Node uses for driving its modules.
+// A nil Processor is replaced by the Node's default Processor for the respective module,
+// which processes the events of each list sequentially.
+// The same Processor must not be used for more than one module.
+// The WAL is always processed by the Node itself, as its processing is split in two pipelined stages
+// (see doWALWork and doWALSyncWork).
+type Processors struct {
+	Client   Processor // Processes events consumed by the ClientTracker module.
+	Hash     Processor // Processes events consumed by the Hasher module.
+	Crypto   Processor // Processes events consumed by the Crypto module.
+	Net      Processor // Processes events consumed by the Net module.
+	App      Processor // Processes events consumed by the App module.
+	ReqStore Processor // Processes events consumed by the RequestStore module.
+	Protocol Processor // Processes events consumed by the Protocol module.
+}
+
+// all returns all the Processors in a slice.
+func (p *Processors) all() []Processor {
+	return []Processor{p.Client, p.Hash, p.Crypto, p.Net, p.App, p.ReqStore, p.Protocol}
+}
+
+// startAll starts all the Processors.
+// If a Processor fails to start, startAll stops the already started ones and returns the error.
+func (p *Processors) startAll() error {
+	processors := p.all()
+	for i, processor := range processors {
+		if err := processor.Start(); err != nil {
+			for _, started := range processors[:i] {
+				started.Stop()
+			}
+			return fmt.Errorf("could not start processor: %w", err)
+		}
+	}
+	return nil
+}
+
+// stopAll stops all the Processors.
+func (p *Processors) stopAll() {
+	for _, processor := range p.all() {
+		processor.Stop()
+	}
+}
+
+// ModuleProcessors returns the default Processors for the modules in m,
+// as they would be used by a Node with ID id and configuration config.
+// Only the Processors of modules present in m are returned, the others are nil.
+// This is useful for processing the events of some modules in a separate process (see package remoteprocessor).
+func ModuleProcessors(id t.NodeID, config *NodeConfig, m *modules.Modules) Processors {
+
+	// Create a Node that is never run, only serving as the context of the default Processors.
+	n := &Node{
+		ID:              id,
+		Config:          config,
+		modules:         m,
+		causalTracer:    newCausalTracer(id, config.Tracer),
+		reqStoreMetrics: reqstoremetrics.NewRequestStore(m.RequestStore),
+		metrics:         processorMetrics(config),
+	}
+	n.initLocalParams()
+	if m.Net != nil {
+		n.sender = newSender(m.Net, n.localParams, &n.peerTraffic, n.clock())
+	}
+	if m.Hasher != nil {
+		n.hashers = newHasherPool(m.Hasher)
+	}
+	p := n.defaultProcessors(Processors{})
+
+	// Only keep the Processors of the present modules.
+	if m.ClientTracker == nil {
+		p.Client = nil
+	}
+	if m.Hasher == nil {
+		p.Hash = nil
+	}
+	if m.Crypto == nil {
+		p.Crypto = nil
+	}
+	if m.Net == nil {
+		p.Net = nil
+	}
+	if m.App == nil {
+		p.App = nil
+	}
+	if m.RequestStore == nil {
+		p.ReqStore = nil
+	}
+	if m.Protocol == nil {
+		p.Protocol = nil
+	}
+	return p
+}
+
+// defaultProcessors returns the Processors used by the Node,
+// where each Processor not specified in custom is replaced by the default one.
+func (n *Node) defaultProcessors(custom Processors) Processors {
+	p := custom
+
+	if p.Client == nil {
+		p.Client = SerialProcessor(func(eventsIn *events.EventList) (*events.EventList, error) {
+			return processClientEvents(n.modules.ClientTracker, eventsIn)
+		})
+	}
+
+	if p.Hash == nil {
+		p.Hash = SerialProcessor(func(eventsIn *events.EventList) (*events.EventList, error) {
+			start := time.Now()
+			eventsOut, err := processHashEvents(n.hashers, eventsIn)
+			n.metrics.OnHash(time.Since(start), eventsIn.Len())
+			return eventsOut, err
+		})
+	}
+
+	if p.Crypto == nil {
+		p.Crypto = SerialProcessor(func(eventsIn *events.EventList) (*events.EventList, error) {
+			return processCryptoEvents(n.modules.Crypto, n.localParams().NumVerifyWorkers, eventsIn)
+		})
+	}
+
+	if p.Net == nil {
+		p.Net = SerialProcessor(func(eventsIn *events.EventList) (*events.EventList, error) {
+			start := time.Now()
+			eventsOut, err := processSendEvents(
+				n.ID, n.sender, n.causalTracer, n.Config.CoalesceWindow > 0, n.Config.PrioritizeCriticalMessages, eventsIn)
+			n.metrics.OnTransmit(time.Since(start), eventsIn.Len())
+			return eventsOut, err
+		})
+	}
+
+	if p.App == nil {
+		p.App = SerialProcessor(func(eventsIn *events.EventList) (*events.EventList, error) {
+			start := time.Now()
+			eventsOut, err := processAppEvents(n.modules.App, n.reqStoreMetrics, eventsIn)
+			n.metrics.OnCommit(time.Since(start), countBatches(eventsIn))
+			return eventsOut, err
+		})
+	}
+
+	if p.ReqStore == nil {
+		p.ReqStore = SerialProcessor(func(eventsIn *events.EventList) (*events.EventList, error) {
+			return processReqStoreEvents(n.modules.RequestStore, n.persisted, eventsIn)
+		})
+	}
+
+	if p.Protocol == nil {
+		p.Protocol = SerialProcessor(func(eventsIn *events.EventList) (*events.EventList, error) {
+			return processProtocolEvents(n.modules.Protocol, n.causalTracer, eventsIn)
+		})
+	}
+
+	return p
+}
+
+// SerialProcessor is a Processor that processes each list of events by a single call to the underlying function.
+// It needs no starting or stopping.
+type SerialProcessor func(eventsIn *events.EventList) (*events.EventList, error)
+
+// Start does nothing and always returns nil.
+func (sp SerialProcessor) Start() error {
+	return nil
+}
+
+// Process invokes the underlying function on the list of input events.
+func (sp SerialProcessor) Process(eventsIn *events.EventList) (*events.EventList, error) {
+	return sp(eventsIn)
+}
+
+// Stop does nothing.
+func (sp SerialProcessor) Stop() {
+}
+
+// ParallelProcessor is a Processor that processes the events of each input list concurrently,
+// using a pool of worker goroutines.
+// It is suitable for modules whose events are independent of each other and whose implementation
+// is safe for concurrent use, e.g. the Hasher.
+// Regardless of the order in which the events are processed,
+// the output always corresponds to the order of the input events.
+// The number of worker goroutines can be changed at runtime, either explicitly (see Resize)
+// or automatically, based on how long events wait for a worker (see AutoTune).
+type ParallelProcessor struct {
+
+	// Processes a single event and returns the resulting events (including the event's follow-up events).
+	processEvent func(event *eventpb.Event) (*events.EventList, error)
+
+	// Feeds events to the worker goroutines. Created by Start and closed by Stop.
+	jobs chan parallelJob
+
+	// Each value written to this channel makes one worker goroutine exit (see Resize).
+	shrink chan struct{}
+
+	// Used to wait for the worker goroutines to exit on Stop.
+	wg sync.WaitGroup
+
+	// Protects numWorkers and the auto-tuning parameters.
+	lock sync.Mutex
+
+	// Number of worker goroutines.
+	numWorkers int
+
+	// Auto-tuning parameters (see AutoTune). Auto-tuning is disabled if maxQueueLatency is zero.
+	minWorkers      int
+	maxWorkers      int
+	maxQueueLatency time.Duration
+}
+
+// A single event to be processed by a worker goroutine of the ParallelProcessor
+// and the location where the worker stores the result of the processing.
+type parallelJob struct {
+	event    *eventpb.Event
+	result   *parallelResult
+	done     *sync.WaitGroup
+	enqueued time.Time
+}
+
+// The result of processing a single event by the ParallelProcessor.
+type parallelResult struct {
+	eventsOut *events.EventList
+	err       error
+
+	// Time the event waited until a worker started processing it.
+	queueLatency time.Duration
+}
+
+// NewParallelProcessor returns a new ParallelProcessor
+// that processes each event using the processEvent function, invoked by numWorkers concurrent goroutines.
+// processEvent must include the event's follow-up events in its output (see events.Strip).
+// If numWorkers is smaller than 1, a single worker is used.
+func NewParallelProcessor(
+	numWorkers int,
+	processEvent func(event *eventpb.Event) (*events.EventList, error),
+) *ParallelProcessor {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	return &ParallelProcessor{
+		numWorkers:   numWorkers,
+		processEvent: processEvent,
+	}
+}
+
+// Start launches the worker goroutines.
+func (pp *ParallelProcessor) Start() error {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	pp.jobs = make(chan parallelJob)
+	pp.shrink = make(chan struct{})
+	for i := 0; i < pp.numWorkers; i++ {
+		pp.startWorker()
+	}
+	return nil
+}
+
+// startWorker launches a single worker goroutine.
+// The worker processes events until the jobs channel is closed or until it reads a value from the shrink channel.
+func (pp *ParallelProcessor) startWorker() {
+	pp.wg.Add(1)
+	go func() {
+		defer pp.wg.Done()
+		for {
+			select {
+			case job, ok := <-pp.jobs:
+				if !ok {
+					return
+				}
+				job.result.queueLatency = time.Since(job.enqueued)
+				job.result.eventsOut, job.result.err = pp.processEvent(job.event)
+				job.done.Done()
+			case <-pp.shrink:
+				return
+			}
+		}
+	}()
+}
+
+// NumWorkers returns the current number of worker goroutines.
+func (pp *ParallelProcessor) NumWorkers() int {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	return pp.numWorkers
+}
+
+// Resize changes the number of worker goroutines to numWorkers (at least 1).
+// New workers are started immediately, while superfluous workers exit after processing their current event,
+// so Resize might block until the events currently being processed are done.
+// Resize can be called concurrently with Process, but only between Start and Stop.
+func (pp *ParallelProcessor) Resize(numWorkers int) {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	pp.resize(numWorkers)
+}
+
+// resize implements Resize. Must be called with pp.lock held.
+func (pp *ParallelProcessor) resize(numWorkers int) {
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	for ; pp.numWorkers < numWorkers; pp.numWorkers++ {
+		pp.startWorker()
+	}
+	for ; pp.numWorkers > numWorkers; pp.numWorkers-- {
+		pp.shrink <- struct{}{}
+	}
+}
+
+// AutoTune enables automatic adaptation of the number of worker goroutines to the load.
+// After each invocation of Process, if any event waited for a worker longer than maxQueueLatency,
+// the number of workers is doubled (up to maxWorkers).
+// If no event waited longer than a quarter of maxQueueLatency, the number of workers is decremented (down to minWorkers).
+// A zero maxQueueLatency disables auto-tuning, which is the default.
+func (pp *ParallelProcessor) AutoTune(minWorkers int, maxWorkers int, maxQueueLatency time.Duration) {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	pp.minWorkers = minWorkers
+	pp.maxWorkers = maxWorkers
+	pp.maxQueueLatency = maxQueueLatency
+}
+
+// autoTune adapts the number of worker goroutines to the maximal queue latency observed by the last Process call
+// (see AutoTune).
+func (pp *ParallelProcessor) autoTune(queueLatency time.Duration) {
+	pp.lock.Lock()
+	defer pp.lock.Unlock()
+
+	if pp.maxQueueLatency == 0 {
+		return
+	}
+
+	if queueLatency > pp.maxQueueLatency && pp.numWorkers < pp.maxWorkers {
+		numWorkers := 2 * pp.numWorkers
+		if numWorkers > pp.maxWorkers {
+			numWorkers = pp.maxWorkers
+		}
+		pp.resize(numWorkers)
+	} else if queueLatency < pp.maxQueueLatency/4 && pp.numWorkers > pp.minWorkers {
+		pp.resize(pp.numWorkers - 1)
+	}
+}
+
+// Process distributes the input events among the worker goroutines, waits until all of them are processed,
+// and returns the concatenation of the resulting events in the order of the input events.
+// If processing any of the events fails, Process returns the error corresponding to the first such event.
+func (pp *ParallelProcessor) Process(eventsIn *events.EventList) (*events.EventList, error) {
+
+	// Hand all events over to the workers.
+	results := make([]parallelResult, eventsIn.Len())
+	var done sync.WaitGroup
+	done.Add(len(results))
+	iter := eventsIn.Iterator()
+	for i, event := 0, iter.Next(); event != nil; i, event = i+1, iter.Next() {
+		pp.jobs <- parallelJob{event: event, result: &results[i], done: &done, enqueued: time.Now()}
+	}
+
+	// Wait for all events to be processed.
+	done.Wait()
+
+	// Concatenate the results in the input order, keeping track of the maximal queue latency.
+	var maxQueueLatency time.Duration
+	eventsOut := &events.EventList{}
+	for _, result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+		if result.queueLatency > maxQueueLatency {
+			maxQueueLatency = result.queueLatency
+		}
+		eventsOut.PushBackList(result.eventsOut)
+	}
+
+	// Adapt the number of workers to the load, if auto-tuning is enabled.
+	pp.autoTune(maxQueueLatency)
+
+	return eventsOut, nil
+}
+
+// Stop stops the worker goroutines and waits until they exit.
+func (pp *ParallelProcessor) Stop() {
+	close(pp.jobs)
+	pp.wg.Wait()
+}
@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"crypto"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+)
+
+// The parallel verification test has the replicas verify request signatures using multiple goroutines.
+var _ = Describe("Parallel signature verification test", func() {
+
+	It("delivers all requests", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			NumClients:      1,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			NumNetRequests:  10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.NumVerifyWorkers = 4
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests + testConfig.NumNetRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests + testConfig.NumNetRequests))
+		}
+	})
+})
+
+var _ = Describe("Custom processor test", func() {
+
+	It("keeps the order of events when resizing the parallel processor", func() {
+		// Each event is processed by producing an event with the same hash origin.
+		pp := mirbft.NewParallelProcessor(2, func(event *eventpb.Event) (*events.EventList, error) {
+			hashRequest := event.Type.(*eventpb.Event_HashRequest).HashRequest
+			return events.ListOf(events.HashResult(nil, hashRequest.Origin)), nil
+		})
+		Expect(pp.Start()).To(Succeed())
+		defer pp.Stop()
+
+		processAndCheck := func() {
+			eventsIn := &events.EventList{}
+			for i := 0; i < 100; i++ {
+				eventsIn.PushBack(events.HashRequest(nil, &eventpb.HashOrigin{Type: &eventpb.HashOrigin_Request{
+					Request: &requestpb.Request{ReqNo: uint64(i)},
+				}}))
+			}
+			eventsOut, err := pp.Process(eventsIn)
+			Expect(err).NotTo(HaveOccurred())
+			for i, event := range eventsOut.Slice() {
+				origin := event.Type.(*eventpb.Event_HashResult).HashResult.Origin
+				Expect(origin.Type.(*eventpb.HashOrigin_Request).Request.ReqNo).To(Equal(uint64(i)))
+			}
+		}
+
+		processAndCheck()
+		pp.Resize(8)
+		Expect(pp.NumWorkers()).To(Equal(8))
+		processAndCheck()
+		pp.Resize(1)
+		Expect(pp.NumWorkers()).To(Equal(1))
+		processAndCheck()
+
+		// With a negligible latency threshold, auto-tuning grows the pool up to the maximum.
+		pp.AutoTune(1, 4, time.Nanosecond)
+		for i := 0; i < 3; i++ {
+			processAndCheck()
+		}
+		Expect(pp.NumWorkers()).To(Equal(4))
+	})
+
+	It("delivers all requests when hashing in parallel", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.Processors.Hash = mirbft.NewParallelProcessor(4, func(event *eventpb.Event) (*events.EventList, error) {
+				hashRequest := event.Type.(*eventpb.Event_HashRequest).HashRequest
+				h := crypto.SHA256.New()
+				for _, data := range hashRequest.Data {
+					h.Write(data)
+				}
+				return events.Strip(event).PushBack(events.HashResult(h.Sum(nil), hashRequest.Origin)), nil
+			})
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
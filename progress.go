@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// progress keeps track of how far the Node got in committing batches and requests.
+// It is updated by the process() goroutine and read (without locking) by the accessors below,
+// which are thus cheap enough to be used on the hot path of the application.
+// The zero value is ready to use.
+type progress struct {
+
+	// One more than the highest sequence number committed so far (0 if none). Accessed atomically.
+	lastCommittedSn uint64
+
+	// The last stable checkpoint (*eventpb.CheckpointStable), if any.
+	stableCheckpoint atomic.Value
+
+	// For each client (t.ClientID), one more than its highest committed request number (*uint64, accessed atomically).
+	clientReqNos sync.Map
+}
+
+// LastCommitted returns the highest sequence number of a batch committed by the Node, i.e., delivered to the application.
+// The returned bool is false if the Node has not committed any batch yet (since it started).
+// LastCommitted never blocks and it is safe for concurrent use.
+func (n *Node) LastCommitted() (t.SeqNr, bool) {
+	sn := atomic.LoadUint64(&n.progress.lastCommittedSn)
+	if sn == 0 {
+		return 0, false
+	}
+	return t.SeqNr(sn - 1), true
+}
+
+// StableCheckpoint returns the last stable checkpoint observed by the Node,
+// including the snapshot of the application state associated with it,
+// or nil if no checkpoint has become stable yet (since the Node started).
+// The returned checkpoint must not be modified.
+// StableCheckpoint never blocks and it is safe for concurrent use.
+func (n *Node) StableCheckpoint() *eventpb.CheckpointStable {
+	checkpoint, _ := n.progress.stableCheckpoint.Load().(*eventpb.CheckpointStable)
+	return checkpoint
+}
+
+// ClientCommitted returns the highest request number of a request of client clientID committed by the Node.
+// The returned bool is false if the Node has not committed any request of the client yet (since it started).
+// Note that requests of a client are not necessarily committed in the order of their request numbers.
+// ClientCommitted never blocks and it is safe for concurrent use.
+func (n *Node) ClientCommitted(clientID t.ClientID) (t.ReqNo, bool) {
+	reqNo, ok := n.progress.clientReqNos.Load(clientID)
+	if !ok {
+		return 0, false
+	}
+	return t.ReqNo(atomic.LoadUint64(reqNo.(*uint64)) - 1), true
+}
+
+// recordCommitted records the batches delivered by the given App events as committed.
+// It must only be called from the process() goroutine.
+func (n *Node) recordCommitted(appEvents *events.EventList) {
+	iter := appEvents.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		deliver, ok := event.Type.(*eventpb.Event_Deliver)
+		if !ok {
+			continue
+		}
+
+		if deliver.Deliver.Sn+1 > atomic.LoadUint64(&n.progress.lastCommittedSn) {
+			atomic.StoreUint64(&n.progress.lastCommittedSn, deliver.Deliver.Sn+1)
+		}
+
+		for _, req := range deliver.Deliver.Batch.Requests {
+			reqNo, ok := n.progress.clientReqNos.Load(t.ClientID(req.ClientId))
+			if !ok {
+				reqNo, _ = n.progress.clientReqNos.LoadOrStore(t.ClientID(req.ClientId), new(uint64))
+			}
+			if req.ReqNo+1 > atomic.LoadUint64(reqNo.(*uint64)) {
+				atomic.StoreUint64(reqNo.(*uint64), req.ReqNo+1)
+			}
+		}
+	}
+}
+
+// recordStableCheckpoints records the last of the stable checkpoints announced by the given Notification events.
+// It must only be called from the process() goroutine.
+func (n *Node) recordStableCheckpoints(notifications *events.EventList) {
+	iter := notifications.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		notification := event.Type.(*eventpb.Event_Notification).Notification
+		if checkpoint, ok := notification.Type.(*eventpb.Notification_CheckpointStable); ok {
+			n.progress.stableCheckpoint.Store(checkpoint.CheckpointStable)
+		}
+	}
+}
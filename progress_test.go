@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The progress query test runs a deployment and checks that, at the end,
+// each node reports the last committed batch, the last stable checkpoint, and the last committed request of the client.
+var _ = Describe("Progress query test", func() {
+
+	It("reports the committed batches, checkpoints and requests", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Keep a reference to each node and check that nothing is reported before the node starts.
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		initiallyCommitted := make([]bool, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				nodes[i] = node
+				_, initiallyCommitted[i] = node.LastCommitted()
+			}
+		}
+
+		deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllCheckpointed(), func() bool {
+			for _, replica := range deployment.TestReplicas {
+				if reqNo, ok := replica.Node().ClientCommitted(0); !ok || reqNo < t.ReqNo(testConfig.NumFakeRequests-1) {
+					return false
+				}
+			}
+			return true
+		}))
+
+		for i, node := range nodes {
+			Expect(initiallyCommitted[i]).To(BeFalse())
+
+			lastCommitted, ok := node.LastCommitted()
+			Expect(ok).To(BeTrue())
+			Expect(lastCommitted).To(Equal(node.Healthy().LastCommitSn))
+
+			checkpoint := node.StableCheckpoint()
+			Expect(checkpoint).NotTo(BeNil())
+			Expect(t.SeqNr(checkpoint.Sn)).To(BeNumerically("<=", lastCommitted+1))
+			Expect(checkpoint.AppSnapshot).NotTo(BeEmpty())
+
+			reqNo, ok := node.ClientCommitted(0)
+			Expect(ok).To(BeTrue())
+			Expect(reqNo).To(Equal(t.ReqNo(testConfig.NumFakeRequests - 1)))
+			_, ok = node.ClientCommitted(1)
+			Expect(ok).To(BeFalse())
+		}
+	})
+})
@@ -13,8 +13,14 @@ package protos
 //go:generate protoc --proto_path=. --go_out=../pkg/pb/ --go_opt=paths=source_relative recordingpb/recordingpb.proto
 //go:generate protoc --proto_path=. --go_out=../pkg/pb/ --go_opt=paths=source_relative isspb/isspb.proto
 //go:generate protoc --proto_path=. --go_out=../pkg/pb/ --go_opt=paths=source_relative isspbftpb/isspbftpb.proto
+//go:generate protoc --proto_path=. --go_out=../pkg/pb/ --go_opt=paths=source_relative fabricpb/fabricpb.proto
+//go:generate protoc --proto_path=. --go_out=../pkg/pb/ --go_opt=paths=source_relative archivepb/archivepb.proto
+//go:generate protoc --proto_path=. --go_out=../pkg/pb/ --go_opt=paths=source_relative auditpb/auditpb.proto
 
 //go:generate protoc --proto_path=. --go_out=plugins=grpc:../pkg/ --go_opt=paths=source_relative grpctransport/grpctransport.proto
 //go:generate protoc --proto_path=. --go_out=plugins=grpc:../pkg/ --go_opt=paths=source_relative requestreceiver/requestreceiver.proto
+//go:generate protoc --proto_path=. --go_out=plugins=grpc:../pkg/ --go_opt=paths=source_relative remoteprocessor/remoteprocessor.proto
+//go:generate protoc --proto_path=. --go_out=plugins=grpc:../pkg/ --go_opt=paths=source_relative observer/observer.proto
 //go:generate protoc --proto_path=. --go_out=:../pkg/ --go_opt=paths=source_relative simplewal/simplewal.proto
+//go:generate protoc --proto_path=. --go_out=:../pkg/ --go_opt=paths=source_relative segmentedwal/segmentedwal.proto
 //go:generate protoc --proto_path=. --go_out=:../samples/ --go_opt=paths=source_relative chat-demo/chatdemo.proto
@@ -0,0 +1,268 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// ErrQueriesNotSupported is the error of a read (see Node.Read) if the application does not implement
+// modules.QueryableApp.
+var ErrQueriesNotSupported = fmt.Errorf("application does not support queries")
+
+// reads keeps track of the linearizable reads (see Node.Read) the Node is serving.
+// The zero value is ready to use.
+type reads struct {
+
+	// ID of the last read started at the Node. Accessed atomically.
+	lastID uint64
+
+	// The reads waiting for their result, indexed by their IDs. Protected by lock.
+	pending map[uint64]*read
+
+	lock sync.Mutex
+}
+
+// read represents a single caller of Node.Read.
+type read struct {
+
+	// The query to be executed by the application (see modules.QueryableApp).
+	query []byte
+
+	// The members the read's ReadIndexRequest was sent to and the number of their responses
+	// forming a quorum read certificate, as defined by the protocol when the read started.
+	// Responses from other nodes are ignored.
+	members map[t.NodeID]struct{}
+	quorum  int
+
+	// Number of batches committed by each member that responded to the read's ReadIndexRequest.
+	responses map[t.NodeID]uint64
+
+	// Once a quorum of nodes responded, readIndex is the number of batches
+	// that need to be applied before executing the query, and indexKnown is set.
+	readIndex  uint64
+	indexKnown bool
+
+	// Set when the query has been submitted to the application.
+	queried bool
+
+	// The result of the query is written to resultC (with a buffer of size 1).
+	resultC chan *eventpb.AppQueryResult
+}
+
+// Read executes a read-only query against the state of the application (see modules.QueryableApp)
+// without ordering it, while still guaranteeing linearizability:
+// The query observes the effects of every batch that was committed by at least a quorum of nodes
+// before Read was called, as well as of all the batches committed by this Node.
+//
+// To this end, Read obtains a quorum read certificate: it asks all current members of the protocol
+// (see modules.MembershipProvider) how many batches they committed and waits for a quorum of their responses
+// (including its own, if the Node is a member), the quorum size being defined by the protocol as well.
+// The highest reported number is the read index, and the query is executed by the application
+// only after the Node has applied that many batches. As any two quorums intersect in at least one correct node,
+// any batch committed by a quorum is reported by a responding correct node
+// and is thus always applied before executing the query.
+// Faulty nodes reporting too high numbers can only delay the query (until the corresponding batches are committed),
+// but never make it return stale data.
+// Thus, reading does not pay the cost of ordering, but takes a round-trip to the other nodes
+// and waits for the Node to catch up with the most advanced node of the quorum.
+//
+// Read returns the result of the query or an error if the query fails, if ctx is canceled, or if the Node halts.
+// If the application does not implement modules.QueryableApp, Read returns ErrQueriesNotSupported.
+// Read also fails if the protocol does not implement modules.MembershipProvider.
+// Read is safe to be called concurrently by multiple threads.
+func (n *Node) Read(ctx context.Context, query []byte) ([]byte, error) {
+
+	if _, ok := n.modules.App.(modules.QueryableApp); !ok {
+		return nil, ErrQueriesNotSupported
+	}
+	membershipProvider, ok := n.modules.Protocol.(modules.MembershipProvider)
+	if !ok {
+		return nil, fmt.Errorf("linearizable reads require a protocol implementing modules.MembershipProvider")
+	}
+	members, quorum := membershipProvider.Membership()
+
+	// Register the read.
+	id := atomic.AddUint64(&n.reads.lastID, 1)
+	r := &read{
+		query:     query,
+		members:   make(map[t.NodeID]struct{}, len(members)),
+		quorum:    quorum,
+		responses: make(map[t.NodeID]uint64),
+		resultC:   make(chan *eventpb.AppQueryResult, 1),
+	}
+	for _, nodeID := range members {
+		r.members[nodeID] = struct{}{}
+	}
+	n.reads.lock.Lock()
+	if n.reads.pending == nil {
+		n.reads.pending = make(map[uint64]*read)
+	}
+	n.reads.pending[id] = r
+	n.reads.lock.Unlock()
+	defer n.removeRead(id)
+
+	// Ask all members, as well as this Node (even if it is not a member), for the number of batches they committed.
+	// The request to this Node is processed locally (see handleReadIndexRequest).
+	request := &messagepb.Message{Type: &messagepb.Message_ReadIndexRequest{
+		ReadIndexRequest: &messagepb.ReadIndexRequest{ReadId: id},
+	}}
+	others := make([]t.NodeID, 0, len(members))
+	for _, nodeID := range members {
+		if nodeID != n.ID {
+			others = append(others, nodeID)
+		}
+	}
+	input := (&events.EventList{}).PushBack(events.MessageReceived(n.ID, request))
+	if len(others) > 0 {
+		input.PushBack(events.SendMessage(request, others))
+	}
+
+	select {
+	case n.externalInput <- input:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-n.workErrNotifier.ExitC():
+		return nil, n.workErrNotifier.Err()
+	}
+
+	// Wait for the result of the query.
+	select {
+	case result := <-r.resultC:
+		if result.Error != "" {
+			return nil, fmt.Errorf("query failed: %s", result.Error)
+		}
+		return result.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-n.workErrNotifier.ExitC():
+		return nil, n.workErrNotifier.Err()
+	}
+}
+
+// removeRead removes the read with the given ID (that stopped waiting) from the pending reads.
+func (n *Node) removeRead(id uint64) {
+	n.reads.lock.Lock()
+	defer n.reads.lock.Unlock()
+
+	delete(n.reads.pending, id)
+}
+
+// handleReads processes the given events serving linearizable reads, i.e., ReadIndexRequest and ReadIndexResponse
+// messages and the results of queries (see Node.Read).
+// It must only be called from the process() goroutine.
+func (n *Node) handleReads(readEvents *events.EventList) {
+	iter := readEvents.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		switch e := event.Type.(type) {
+		case *eventpb.Event_MessageReceived:
+			switch msg := e.MessageReceived.Msg.Type.(type) {
+			case *messagepb.Message_ReadIndexRequest:
+				n.handleReadIndexRequest(t.NodeID(e.MessageReceived.From), msg.ReadIndexRequest)
+			case *messagepb.Message_ReadIndexResponse:
+				n.handleReadIndexResponse(t.NodeID(e.MessageReceived.From), msg.ReadIndexResponse)
+			}
+		case *eventpb.Event_AppQueryResult:
+			n.reads.lock.Lock()
+			if r, ok := n.reads.pending[e.AppQueryResult.ReadId]; ok {
+				r.resultC <- e.AppQueryResult
+				delete(n.reads.pending, e.AppQueryResult.ReadId)
+			}
+			n.reads.lock.Unlock()
+		}
+	}
+}
+
+// handleReadIndexRequest responds to a ReadIndexRequest with the number of batches committed by the Node.
+// The response to a request of the Node itself is processed directly.
+// It must only be called from the process() goroutine.
+func (n *Node) handleReadIndexRequest(from t.NodeID, request *messagepb.ReadIndexRequest) {
+	response := &messagepb.ReadIndexResponse{
+		ReadId:    request.ReadId,
+		Committed: atomic.LoadUint64(&n.progress.lastCommittedSn),
+	}
+
+	if from == n.ID {
+		n.handleReadIndexResponse(n.ID, response)
+		return
+	}
+
+	msg := &messagepb.Message{Type: &messagepb.Message_ReadIndexResponse{ReadIndexResponse: response}}
+	if err := n.workItems.AddEvents((&events.EventList{}).PushBack(events.SendMessage(msg, []t.NodeID{from}))); err != nil {
+		n.workErrNotifier.Fail(err)
+	}
+}
+
+// handleReadIndexResponse records the response of a node to a ReadIndexRequest of the Node.
+// When a quorum of nodes responded, it fixes the read index of the read and submits the query
+// to the application as soon as the Node committed enough batches.
+// Responses to unknown reads (e.g. reads whose caller stopped waiting) and from nodes that were not members
+// when the read started are ignored.
+// It must only be called from the process() goroutine.
+func (n *Node) handleReadIndexResponse(from t.NodeID, response *messagepb.ReadIndexResponse) {
+	n.reads.lock.Lock()
+	defer n.reads.lock.Unlock()
+
+	r, ok := n.reads.pending[response.ReadId]
+	if !ok || r.indexKnown {
+		return
+	}
+	if _, member := r.members[from]; !member {
+		return
+	}
+
+	r.responses[from] = response.Committed
+	if len(r.responses) < r.quorum {
+		return
+	}
+
+	// The quorum read certificate is complete. The read index is the highest number of committed batches reported.
+	for _, committed := range r.responses {
+		if committed > r.readIndex {
+			r.readIndex = committed
+		}
+	}
+	r.indexKnown = true
+	n.submitReadyQueries()
+}
+
+// releaseReads submits the queries of the reads whose read index the Node reached to the application.
+// It must only be called from the process() goroutine, after passing delivered batches to the application.
+func (n *Node) releaseReads() {
+	n.reads.lock.Lock()
+	defer n.reads.lock.Unlock()
+
+	n.submitReadyQueries()
+}
+
+// submitReadyQueries submits the queries of the reads whose read index the Node reached to the application.
+// As the query events are added to the App events after all the batches passed to the application so far,
+// each query is executed only after the application applied all the batches up to the read index.
+// n.reads.lock must be held when calling submitReadyQueries.
+func (n *Node) submitReadyQueries() {
+	committed := atomic.LoadUint64(&n.progress.lastCommittedSn)
+	queries := &events.EventList{}
+	for id, r := range n.reads.pending {
+		if r.indexKnown && !r.queried && r.readIndex <= committed {
+			r.queried = true
+			queries.PushBack(events.AppQuery(id, r.query))
+		}
+	}
+
+	if err := n.workItems.AddEvents(queries); err != nil {
+		n.workErrNotifier.Fail(err)
+	}
+}
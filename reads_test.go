@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The linearizable read test runs a deployment and, after all requests have been committed,
+// reads the state of the application through each node (see Node.Read).
+var _ = Describe("Linearizable read test", func() {
+
+	table.DescribeTable("answers read-only queries reflecting all committed requests",
+		func(numReplicas int) {
+			testConfig := &deploytest.TestConfig{
+				NumReplicas:     numReplicas,
+				Transport:       "fake",
+				NumFakeRequests: 10,
+				Directory:       "",
+			}
+
+			deployment := deploytest.NewTestDeployment(testConfig)
+
+			// Read from each node (concurrently) after the requests have been committed.
+			var results [][]byte
+			var readErrs []error
+			deployment.RunUntilThen(deployment.AllProcessed(testConfig.NumFakeRequests), func() {
+				results, readErrs = readAll(deployment.TestReplicas)
+			})
+
+			for i := range deployment.TestReplicas {
+				Expect(readErrs[i]).NotTo(HaveOccurred())
+				Expect(results[i]).To(Equal(requestsProcessed(testConfig.NumFakeRequests)))
+			}
+		},
+		table.Entry("with 4 nodes", 4),
+		table.Entry("with 5 nodes", 5),
+	)
+
+	It("reads from the current membership after a node was added", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     5,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The network initially consists of the first four nodes and the fifth one joins it.
+		initialMembership := []t.NodeID{0, 1, 2, 3}
+		joiningNode := t.NodeID(4)
+		clientIDs := append(append([]t.ClientID{}, deployment.TestReplicas[0].ClientIDs...), t.ConfigClientID)
+		for _, replica := range deployment.TestReplicas {
+			replica.ClientIDs = clientIDs
+			if replica.Id == joiningNode {
+				replica.ISSConfig = iss.DefaultConfig(replica.Membership)
+				replica.ISSConfig.NumBuckets = len(initialMembership)
+				replica.ISSConfig.Join = true
+			} else {
+				replica.ISSConfig = iss.DefaultConfig(initialMembership)
+			}
+		}
+		configRequest := deploytest.ConfigRequest(deployment.TestReplicas[0].Membership, clientIDs, 0, &isspb.ConfigChange{
+			AddNodes: []uint64{joiningNode.Pb()},
+		})
+
+		// Add the fifth node and, once all nodes (including the joined one) applied all requests,
+		// read from each of them. The reads must contact the five members and wait for a quorum among them.
+		var submitErrs []error
+		var results [][]byte
+		var readErrs []error
+		members := deployment.TestReplicas[:len(initialMembership)]
+		deployment.RunUntilThen(deploytest.AllCommitted(members, 0), func() {
+			submitErrs = deploytest.SubmitToAll(members, configRequest)
+			Eventually(deployment.AllProcessed(testConfig.NumFakeRequests+1), deploytest.TestTimeout, deploytest.PollInterval).
+				Should(BeTrue())
+			results, readErrs = readAll(deployment.TestReplicas)
+		})
+
+		for _, err := range submitErrs {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		for i := range deployment.TestReplicas {
+			Expect(readErrs[i]).NotTo(HaveOccurred())
+			Expect(results[i]).To(Equal(requestsProcessed(testConfig.NumFakeRequests + 1)))
+		}
+	})
+})
+
+// readAll reads the number of requests processed by the application through the nodes of all the given replicas
+// (concurrently) and returns the results and errors of the reads.
+func readAll(replicas []*deploytest.TestReplica) ([][]byte, []error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	results := make([][]byte, len(replicas))
+	readErrs := make([]error, len(replicas))
+	var wg sync.WaitGroup
+	for i, replica := range replicas {
+		i, node := i, replica.Node()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], readErrs[i] = node.Read(ctx, []byte("requests processed"))
+		}()
+	}
+	wg.Wait()
+	return results, readErrs
+}
+
+// requestsProcessed returns the expected result of reading the number of requests processed by the application.
+func requestsProcessed(n int) []byte {
+	result := make([]byte, 8)
+	binary.LittleEndian.PutUint64(result, uint64(n))
+	return result
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/archivepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// checkRecoveredState checks whether the contents of the WAL is consistent with the contents of the request store.
+// Each request referenced by a WAL entry must have been persisted in the request store
+// (and marked as authenticated) before the WAL entry itself was appended.
+// If this is not the case (e.g. because the request store has been replaced by an empty one),
+// the node would not be able to recover properly and checkRecoveredState returns an error.
+// The WAL is represented by its entries, as returned by loadWAL.
+func checkRecoveredState(walEntries []*archivepb.WALEntry, reqStore modules.RequestStore) error {
+
+	// Collect all the requests referenced in the WAL.
+	var refs []*requestpb.RequestRef
+	for _, entry := range walEntries {
+		refs = append(refs, persistedRequestRefs(entry.Event)...)
+	}
+
+	// Check that each of them is present in the request store and authenticated.
+	for _, ref := range refs {
+		if authenticated, err := reqStore.IsAuthenticated(ref); err != nil {
+			return fmt.Errorf("request (c%dr%d) referenced in WAL not found in request store: %w",
+				ref.ClientId, ref.ReqNo, err)
+		} else if !authenticated {
+			return fmt.Errorf("request (c%dr%d) referenced in WAL not authenticated in request store",
+				ref.ClientId, ref.ReqNo)
+		}
+	}
+
+	return nil
+}
+
+// checkRecoveredCheckpoints checks whether each stable checkpoint persisted in the WAL
+// is preceded by the corresponding checkpoint (containing the application snapshot), from which it can be recovered.
+// Only the stable checkpoints that are more recent than all the preceding ones are checked,
+// as the protocol ignores the others when recovering.
+// The WAL is represented by its entries, as returned by loadWAL.
+func checkRecoveredCheckpoints(walEntries []*archivepb.WALEntry) error {
+	checkpoints := make(map[t.SeqNr]struct{})
+	lastStable := t.SeqNr(0)
+	for _, entry := range walEntries {
+		issEvent, ok := entry.Event.Type.(*eventpb.Event_Iss)
+		if !ok {
+			continue
+		}
+		switch e := issEvent.Iss.Type.(type) {
+		case *isspb.ISSEvent_PersistCheckpoint:
+			checkpoints[t.SeqNr(e.PersistCheckpoint.Sn)] = struct{}{}
+		case *isspb.ISSEvent_PersistStableCheckpoint:
+			sn := t.SeqNr(e.PersistStableCheckpoint.StableCheckpoint.Sn)
+			if sn <= lastStable {
+				continue
+			}
+			if _, ok := checkpoints[sn]; !ok {
+				return fmt.Errorf("no checkpoint (application snapshot) persisted for stable checkpoint at sn %d", sn)
+			}
+			lastStable = sn
+		}
+	}
+	return nil
+}
+
+// loadWAL returns all the entries stored in the WAL, in the order in which they were appended.
+func loadWAL(wal modules.WAL) ([]*archivepb.WALEntry, error) {
+	entries := make([]*archivepb.WALEntry, 0)
+	if err := wal.LoadAll(func(retIdx t.WALRetIndex, event *eventpb.Event) {
+		entries = append(entries, &archivepb.WALEntry{
+			RetentionIndex: retIdx.Pb(),
+			Event:          event,
+		})
+	}); err != nil {
+		return nil, fmt.Errorf("could not load WAL events: %w", err)
+	}
+	return entries, nil
+}
+
+// persistedRequestRefs returns the references to all requests contained in an event persisted in the WAL.
+func persistedRequestRefs(event *eventpb.Event) []*requestpb.RequestRef {
+	switch e := event.Type.(type) {
+	case *eventpb.Event_Iss:
+		if sbEvent, ok := e.Iss.Type.(*isspb.ISSEvent_Sb); ok {
+			if pp, ok := sbEvent.Sb.Event.Type.(*isspb.SBInstanceEvent_PbftPersistPreprepare); ok {
+				return pp.PbftPersistPreprepare.Preprepare.Batch.Requests
+			}
+		}
+	case *eventpb.Event_PersistDummyBatch:
+		return e.PersistDummyBatch.Batch.Requests
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The restart test kills all nodes of a deployment in the middle of the protocol execution
+// and restarts them with the state they persisted before being killed.
+var _ = Describe("Restart test", func() {
+
+	It("recovers nodes killed mid-protocol", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Collects the batches delivered by each replica, indexed by sequence number, over all runs.
+		// A batch committed again after a restart must be the same as before,
+		// otherwise its sequence number is recorded in redelivered.
+		delivered := make([]map[t.SeqNr]*requestpb.Batch, len(deployment.TestReplicas))
+		var redelivered []t.SeqNr
+		var lock sync.Mutex
+		var wg sync.WaitGroup
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			delivered[i] = make(map[t.SeqNr]*requestpb.Batch)
+			replica.OnNode = func(node *mirbft.Node) {
+				committedC := node.Committed(context.Background(), 0)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for deliver := range committedC {
+						lock.Lock()
+						if previous, ok := delivered[i][t.SeqNr(deliver.Sn)]; ok && !proto.Equal(deliver.Batch, previous) {
+							redelivered = append(redelivered, t.SeqNr(deliver.Sn))
+						}
+						delivered[i][t.SeqNr(deliver.Sn)] = deliver.Batch
+						lock.Unlock()
+					}
+				}()
+			}
+		}
+
+		// Run the deployment until all requests are delivered and covered by a stable checkpoint
+		// and stop it while the nodes are still working.
+		deploytest.ExpectStopped(deployment.RunUntilThen(deployment.AllProcessed(testConfig.NumFakeRequests), func() {
+			deployment.AwaitStableCheckpoint()
+		}))
+		wg.Wait()
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+
+		// Restart all replicas. The application state does not survive the crash
+		// and must be restored from the recovered stable checkpoint.
+		for _, replica := range deployment.TestReplicas {
+			replica.App = &deploytest.FakeApp{}
+			replica.NumFakeRequests = 0
+			replica.Restart = true
+		}
+		deploytest.ExpectStopped(deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests)))
+		wg.Wait()
+
+		// Each restarted replica must catch up with the state the replicas reached before the crash,
+		// without processing any request twice, and all replicas must have delivered the same batches.
+		Expect(redelivered).To(BeEmpty())
+		for i, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			for sn, batch := range delivered[i] {
+				if other, ok := delivered[0][sn]; ok {
+					Expect(proto.Equal(batch, other)).To(BeTrue())
+				}
+			}
+		}
+	})
+
+	It("refuses to restart from a stable checkpoint without its application snapshot", func() {
+		wal := simplewal.NewVolatileWAL()
+		Expect(wal.Append(iss.PersistStableCheckpointEvent(&isspb.StableCheckpoint{Epoch: 1, Sn: 5}), 0)).To(Succeed())
+
+		membership := []t.NodeID{0, 1, 2, 3}
+		protocol, err := iss.New(0, iss.DefaultConfig(membership), logging.NilLogger)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = mirbft.RestartNode(0, &mirbft.NodeConfig{Logger: logging.NilLogger}, &modules.Modules{
+			Net:           discardingNet{},
+			Hasher:        crypto.SHA256,
+			App:           &deploytest.FakeApp{},
+			WAL:           wal,
+			ClientTracker: clients.SigningTracker(logging.NilLogger),
+			RequestStore:  reqstore.NewVolatileRequestStore(),
+			Protocol:      protocol,
+			Crypto:        &mirCrypto.DummyCrypto{DummySig: []byte{0}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("no checkpoint (application snapshot) persisted")))
+	})
+})
+
+// The crash recovery test crashes a leader right after it persisted a proposal (a batch of requests)
+// in its WAL, but before it sent the proposal to the other nodes.
+// It then restarts the nodes and checks that the crashed node rejoins, re-sending the proposal,
+// and that all nodes commit the same batches (in particular the proposal of the crashed node).
+var _ = Describe("Crash recovery test", func() {
+
+	It("recovers a leader crashed between persisting and sending a proposal", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		crashing := deployment.TestReplicas[0]
+		wal := &crashingWAL{VolatileWAL: simplewal.NewVolatileWAL()}
+		crashing.WAL = wal
+
+		// Runs the deployment, collecting the committed log of each replica, until condition holds for the logs.
+		runUntilLogs := func(condition func(logs [][]*eventpb.Deliver) bool) ([]deploytest.NodeStatus, [][]*eventpb.Deliver) {
+			logs := make([][]*eventpb.Deliver, len(deployment.TestReplicas))
+			var lock sync.Mutex
+			var wg sync.WaitGroup
+			for i, replica := range deployment.TestReplicas {
+				i := i
+				replica.OnNode = func(node *mirbft.Node) {
+					committedC := node.Committed(context.Background(), 0)
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for deliver := range committedC {
+							lock.Lock()
+							logs[i] = append(logs[i], deliver)
+							lock.Unlock()
+						}
+					}()
+				}
+			}
+
+			finalStatuses := deployment.RunUntil(func() bool {
+				lock.Lock()
+				defer lock.Unlock()
+				return condition(logs)
+			})
+			wg.Wait()
+			return finalStatuses, logs
+		}
+
+		// In the first run, the crashing replica crashes, while the others keep going.
+		finalStatuses, logs := runUntilLogs(func([][]*eventpb.Deliver) bool {
+			node := crashing.Node()
+			return node != nil && node.Healthy().Halted
+		})
+		Expect(wal.crashedSn).NotTo(BeNil())
+		Expect(finalStatuses[0].ExitErr).To(MatchError(ContainSubstring(errCrash.Error())))
+		for _, status := range finalStatuses[1:] {
+			Expect(status.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		// The crashed proposal must not have been committed by anyone,
+		// as the crashed node never sent it.
+		for _, log := range logs {
+			for _, deliver := range log {
+				Expect(t.SeqNr(deliver.Sn)).NotTo(Equal(*wal.crashedSn))
+			}
+		}
+
+		// Restart all replicas. The crashing replica recovers the WAL as it was at the moment of the crash.
+		// The (uncommitted) requests only survive in the request stores. As the replicas' buckets of requests
+		// are not persisted, the client submits its requests again, as it never received a response to them.
+		for _, replica := range deployment.TestReplicas {
+			replica.App = &deploytest.FakeApp{}
+			replica.Restart = true
+		}
+		crashing.WAL = wal.VolatileWAL
+		finalStatuses, restartedLogs := runUntilLogs(func(restartedLogs [][]*eventpb.Deliver) bool {
+			for i := range deployment.TestReplicas {
+				crashedSnCommitted := false
+				committedRequests := make(map[t.ReqNo]struct{})
+				for _, log := range [][]*eventpb.Deliver{logs[i], restartedLogs[i]} {
+					for _, deliver := range log {
+						crashedSnCommitted = crashedSnCommitted || t.SeqNr(deliver.Sn) == *wal.crashedSn
+						for _, req := range deliver.Batch.Requests {
+							committedRequests[t.ReqNo(req.ReqNo)] = struct{}{}
+						}
+					}
+				}
+				if !crashedSnCommitted || len(committedRequests) < testConfig.NumFakeRequests {
+					return false
+				}
+			}
+			return true
+		})
+		for _, status := range finalStatuses {
+			Expect(status.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+
+		// All replicas must have committed the crashed proposal after the restart,
+		// all of them must have committed the same batch at each sequence number (across both runs),
+		// and no request must have been committed twice.
+		committed := make(map[t.SeqNr]*requestpb.Batch)
+		for i := range deployment.TestReplicas {
+			crashedSnCommitted := false
+			committedRequests := make(map[t.ReqNo]struct{})
+			for _, deliver := range append(logs[i], restartedLogs[i]...) {
+				if batch, ok := committed[t.SeqNr(deliver.Sn)]; ok {
+					Expect(proto.Equal(deliver.Batch, batch)).To(BeTrue())
+				} else {
+					committed[t.SeqNr(deliver.Sn)] = deliver.Batch
+				}
+				if t.SeqNr(deliver.Sn) == *wal.crashedSn {
+					crashedSnCommitted = true
+				}
+				for _, req := range deliver.Batch.Requests {
+					Expect(committedRequests).NotTo(HaveKey(t.ReqNo(req.ReqNo)))
+					committedRequests[t.ReqNo(req.ReqNo)] = struct{}{}
+				}
+			}
+			Expect(crashedSnCommitted).To(BeTrue())
+			Expect(committedRequests).To(HaveLen(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// errCrash is the error with which crashingWAL simulates a crash.
+var errCrash = fmt.Errorf("simulated crash")
+
+// crashingWAL is a WAL that simulates a crash of the node right after persisting the node's first proposal
+// of a non-empty batch, i.e. before the node could send the proposal to the other nodes.
+// It persists the proposal and returns an error, making the node halt. Afterwards, all its operations fail.
+type crashingWAL struct {
+	*simplewal.VolatileWAL
+
+	// Sequence number of the proposal after persisting which the node crashed, nil if it did not crash (yet).
+	crashedSn *t.SeqNr
+}
+
+func (cw *crashingWAL) Append(event *eventpb.Event, retentionIndex t.WALRetIndex) error {
+	if cw.crashedSn != nil {
+		return errCrash
+	}
+	if err := cw.VolatileWAL.Append(event, retentionIndex); err != nil {
+		return err
+	}
+
+	if e, ok := event.Type.(*eventpb.Event_Iss); ok {
+		if sb, ok := e.Iss.Type.(*isspb.ISSEvent_Sb); ok {
+			if pp, ok := sb.Sb.Event.Type.(*isspb.SBInstanceEvent_PbftPersistPreprepare); ok &&
+				len(pp.PbftPersistPreprepare.Preprepare.Batch.Requests) > 0 {
+				sn := t.SeqNr(pp.PbftPersistPreprepare.Preprepare.Sn)
+				cw.crashedSn = &sn
+				return errCrash
+			}
+		}
+	}
+	return nil
+}
+
+func (cw *crashingWAL) Truncate(retentionIndex t.WALRetIndex) error {
+	if cw.crashedSn != nil {
+		return errCrash
+	}
+	return cw.VolatileWAL.Truncate(retentionIndex)
+}
+
+func (cw *crashingWAL) Sync() error {
+	if cw.crashedSn != nil {
+		return errCrash
+	}
+	return cw.VolatileWAL.Sync()
+}
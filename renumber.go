@@ -0,0 +1,223 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// NodeIDMapping assigns new IDs to nodes, e.g., for resolving ID collisions when merging clusters
+// or when re-creating a node from the state of another one. Each key is an old node ID mapped to the new ID.
+// IDs not present in the mapping are left unchanged.
+// A mapping must not assign the same new ID to two nodes, including to a node that is not renumbered.
+type NodeIDMapping map[t.NodeID]t.NodeID
+
+// RenumberWAL rewrites the node IDs contained in the entries of the src WAL according to mapping
+// and appends the rewritten entries (with their original retention indexes) to the dest WAL, which is expected to be empty.
+// The src WAL is left untouched. This makes it possible to renumber the nodes of a cluster offline,
+// after which the nodes can be restarted (using RestartNode) with their new IDs.
+//
+// To keep the cluster consistent, all nodes must be stopped and the WALs of all of them must be renumbered
+// using the same mapping. The node configurations (e.g., the initial membership of the Protocol)
+// and the network configuration (see RenumberAddresses) must be renumbered accordingly.
+// The rewritten IDs are the ones persisted by the ISS protocol: the membership, the learners,
+// and the node keys recorded with each checkpoint, as well as the node IDs contained in the pending
+// configuration requests. The references to the configuration requests (and their copies in the request store)
+// are left unchanged, since their digests are only used to match the requests when they are committed.
+// The keys the Crypto module associates with the nodes are not affected and must be renumbered separately.
+// RenumberWAL must not be called while a Node is using either of the WALs.
+func RenumberWAL(src modules.WAL, dest modules.WAL, mapping NodeIDMapping) error {
+
+	renumber, err := newRenumbering(mapping)
+	if err != nil {
+		return err
+	}
+
+	// Load all WAL entries.
+	entries, err := loadWAL(src)
+	if err != nil {
+		return err
+	}
+
+	// Rewrite the entries (leaving the loaded ones untouched) and write them to the new WAL.
+	for _, entry := range entries {
+		event, err := renumber.event(entry.Event)
+		if err != nil {
+			return err
+		}
+		if err := dest.Append(event, t.WALRetIndex(entry.RetentionIndex)); err != nil {
+			return fmt.Errorf("could not append WAL entry: %w", err)
+		}
+	}
+	if err := dest.Sync(); err != nil {
+		return fmt.Errorf("could not sync WAL: %w", err)
+	}
+
+	return nil
+}
+
+// RenumberAddresses returns a copy of the network configuration addrs (the network addresses of the nodes,
+// as used by the grpctransport package) with the nodes renumbered according to mapping.
+func RenumberAddresses(addrs map[t.NodeID]string, mapping NodeIDMapping) (map[t.NodeID]string, error) {
+
+	renumber, err := newRenumbering(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	renumbered := make(map[t.NodeID]string, len(addrs))
+	for nodeID, addr := range addrs {
+		newID, err := renumber.nodeID(nodeID.Pb())
+		if err != nil {
+			return nil, err
+		}
+		renumbered[t.NodeID(newID)] = addr
+	}
+	return renumbered, nil
+}
+
+// renumbering applies a NodeIDMapping to the node IDs contained in persisted data.
+type renumbering struct {
+	mapping NodeIDMapping
+
+	// The new IDs assigned by the mapping.
+	newIDs map[t.NodeID]struct{}
+}
+
+// newRenumbering returns a new renumbering applying mapping,
+// or an error if mapping assigns the same new ID to two nodes.
+func newRenumbering(mapping NodeIDMapping) (*renumbering, error) {
+	newIDs := make(map[t.NodeID]struct{}, len(mapping))
+	for oldID, newID := range mapping {
+		if _, ok := newIDs[newID]; ok {
+			return nil, fmt.Errorf("node ID mapping assigns ID %d to multiple nodes (including %d)", newID, oldID)
+		}
+		newIDs[newID] = struct{}{}
+	}
+	return &renumbering{mapping: mapping, newIDs: newIDs}, nil
+}
+
+// nodeID returns the new ID of the node with the given (old) ID.
+// nodeID returns an error if the node is not renumbered, but another node is renumbered to its ID.
+func (r *renumbering) nodeID(id uint64) (uint64, error) {
+	if newID, ok := r.mapping[t.NodeID(id)]; ok {
+		return newID.Pb(), nil
+	}
+	if _, ok := r.newIDs[t.NodeID(id)]; ok {
+		return 0, fmt.Errorf("node ID mapping assigns ID %d to another node, but does not renumber node %d", id, id)
+	}
+	return id, nil
+}
+
+// nodeIDs returns the new IDs of the nodes with the given (old) IDs, in the same order.
+func (r *renumbering) nodeIDs(ids []uint64) ([]uint64, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	newIDs := make([]uint64, len(ids))
+	for i, id := range ids {
+		newID, err := r.nodeID(id)
+		if err != nil {
+			return nil, err
+		}
+		newIDs[i] = newID
+	}
+	return newIDs, nil
+}
+
+// nodeKeys renumbers the nodes of the given node keys in place.
+func (r *renumbering) nodeKeys(nodeKeys []*isspb.NodeKey) error {
+	for _, nodeKey := range nodeKeys {
+		newID, err := r.nodeID(nodeKey.NodeId)
+		if err != nil {
+			return err
+		}
+		nodeKey.NodeId = newID
+	}
+	return nil
+}
+
+// event returns a copy of the given WAL event with the node IDs it contains renumbered.
+// Events that do not contain node IDs are returned unchanged.
+func (r *renumbering) event(event *eventpb.Event) (*eventpb.Event, error) {
+	issEvent, ok := event.Type.(*eventpb.Event_Iss)
+	if !ok {
+		return event, nil
+	}
+
+	switch issEvent.Iss.Type.(type) {
+	case *isspb.ISSEvent_PersistCheckpoint, *isspb.ISSEvent_PersistConfigRequest:
+	default:
+		return event, nil
+	}
+
+	// Rewrite a copy of the event, such that the loaded one stays untouched.
+	event = proto.Clone(event).(*eventpb.Event)
+	switch e := event.Type.(*eventpb.Event_Iss).Iss.Type.(type) {
+	case *isspb.ISSEvent_PersistCheckpoint:
+		if err := r.checkpoint(e.PersistCheckpoint); err != nil {
+			return nil, fmt.Errorf("could not renumber checkpoint %d: %w", e.PersistCheckpoint.Sn, err)
+		}
+	case *isspb.ISSEvent_PersistConfigRequest:
+		if err := r.configRequest(e.PersistConfigRequest); err != nil {
+			return nil, fmt.Errorf("could not renumber configuration request %d: %w",
+				e.PersistConfigRequest.RequestRef.ReqNo, err)
+		}
+	}
+	return event, nil
+}
+
+// checkpoint renumbers the nodes of a persisted checkpoint in place.
+func (r *renumbering) checkpoint(checkpoint *isspb.PersistCheckpoint) error {
+	var err error
+	if checkpoint.Membership, err = r.nodeIDs(checkpoint.Membership); err != nil {
+		return err
+	}
+	if checkpoint.Learners, err = r.nodeIDs(checkpoint.Learners); err != nil {
+		return err
+	}
+	return r.nodeKeys(checkpoint.NodeKeys)
+}
+
+// configRequest renumbers the nodes of a persisted configuration request in place.
+// Malformed requests are left unchanged, as they are ignored by the protocol anyway.
+func (r *renumbering) configRequest(configRequest *isspb.PersistConfigRequest) error {
+	change := &isspb.ConfigChange{}
+	if err := proto.Unmarshal(configRequest.Data, change); err != nil {
+		return nil
+	}
+
+	var err error
+	if change.AddNodes, err = r.nodeIDs(change.AddNodes); err != nil {
+		return err
+	}
+	if change.RemoveNodes, err = r.nodeIDs(change.RemoveNodes); err != nil {
+		return err
+	}
+	if change.AddLearners, err = r.nodeIDs(change.AddLearners); err != nil {
+		return err
+	}
+	if change.RemoveLearners, err = r.nodeIDs(change.RemoveLearners); err != nil {
+		return err
+	}
+	if err := r.nodeKeys(change.RotateNodes); err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("could not marshal configuration change: %w", err)
+	}
+	configRequest.Data = data
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The renumber test renumbers the persisted state of all nodes of a deployment offline
+// and restarts the nodes with the renumbered configuration.
+var _ = Describe("Renumber test", func() {
+
+	It("recovers nodes renumbered offline", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     5,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The first four nodes are members, the fifth one is a learner.
+		for _, replica := range deployment.TestReplicas {
+			replica.ISSConfig = iss.DefaultConfig([]t.NodeID{0, 1, 2, 3})
+			replica.ISSConfig.NumBuckets = 4
+			replica.ISSConfig.Learners = []t.NodeID{4}
+		}
+		deploytest.ExpectStopped(deployment.RunUntilThen(deployment.AllProcessed(testConfig.NumFakeRequests), func() {
+			deployment.AwaitStableCheckpoint()
+		}))
+		epoch := deployment.TestReplicas[0].Node().EpochInfo().Epoch
+
+		// Swap the IDs of the last member and the learner. The state of each node is renumbered into a new WAL,
+		// which then replaces the WAL of the replica running the node with its new ID.
+		mapping := mirbft.NodeIDMapping{3: 4, 4: 3}
+		for _, replica := range deployment.TestReplicas {
+			src, err := simplewal.Open(filepath.Join(replica.Dir, "wal"))
+			Expect(err).NotTo(HaveOccurred())
+			dest, err := simplewal.Open(filepath.Join(replica.Dir, "wal-renumbered"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mirbft.RenumberWAL(src, dest, mapping)).To(Succeed())
+			Expect(src.Close()).To(Succeed())
+			Expect(dest.Close()).To(Succeed())
+			Expect(os.RemoveAll(filepath.Join(replica.Dir, "wal"))).To(Succeed())
+		}
+		reqStores := make([]modules.RequestStore, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			newID, ok := mapping[replica.Id]
+			if !ok {
+				newID = replica.Id
+			}
+			Expect(os.Rename(
+				filepath.Join(replica.Dir, "wal-renumbered"),
+				filepath.Join(deployment.TestReplicas[newID].Dir, "wal"),
+			)).To(Succeed())
+			reqStores[newID] = deployment.TestReplicas[i].ReqStore
+		}
+
+		// Restart the nodes with the renumbered configuration.
+		for i, replica := range deployment.TestReplicas {
+			replica.ReqStore = reqStores[i]
+			replica.ISSConfig = iss.DefaultConfig([]t.NodeID{0, 1, 2, 4})
+			replica.ISSConfig.NumBuckets = 4
+			replica.ISSConfig.Learners = []t.NodeID{3}
+			replica.App = &deploytest.FakeApp{}
+			replica.NumFakeRequests = 0
+			replica.Restart = true
+		}
+		deploytest.ExpectStopped(deployment.RunUntil(deployment.AllInEpoch(epoch + 1)))
+
+		// The nodes kept ordering with the renumbered membership.
+		for _, replica := range deployment.TestReplicas {
+			Expect(replica.Node().EpochInfo().Leaders).To(ConsistOf(t.NodeID(0), t.NodeID(1), t.NodeID(2), t.NodeID(4)))
+		}
+
+		// The network configuration is renumbered the same way, and conflicting mappings are rejected.
+		addrs, err := mirbft.RenumberAddresses(map[t.NodeID]string{3: "host3", 4: "host4"}, mapping)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(addrs).To(Equal(map[t.NodeID]string{4: "host3", 3: "host4"}))
+		_, err = mirbft.RenumberAddresses(map[t.NodeID]string{3: "host3", 4: "host4"}, mirbft.NodeIDMapping{3: 4})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recordingpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Replayer re-executes the events recorded by an eventlog.Recorder used as the Node's Interceptor
+// against a given set of modules (typically mocks or fresh instances of the modules used by the recorded Node).
+// Each recorded entry contains a list of events the Node dispatched to one of its modules.
+// The Replayer passes the events of each entry to the same module (using the same Processor the Node would use),
+// in the recorded order and in a single thread, and discards the resulting events.
+// (The recorded Node dispatched the resulting events itself, so they are recorded in subsequent entries.)
+// This way, each module observes exactly the same sequence of inputs as in the recorded run,
+// which makes it possible to deterministically reproduce bugs where a module diverged from its expected behavior.
+type Replayer struct {
+
+	// The node whose modules and Processors the recorded events are replayed against. It is never run.
+	node *Node
+
+	// Sequence number expected for the next replayed entry. Zero if no entry has been replayed yet.
+	nextSeqNo uint64
+}
+
+// NewReplayer returns a new Replayer for the events recorded by the node with ID id.
+// The parameters have the same meaning as with NewNode.
+// The Net module is only used for sending (the replayed messages are discarded, as any other replay output)
+// and the Interceptor module is ignored.
+func NewReplayer(id t.NodeID, config *NodeConfig, m *modules.Modules) (*Replayer, error) {
+
+	// Do not record the replayed run itself.
+	mCopy := *m
+	mCopy.Interceptor = nil
+
+	node, err := NewNode(id, config, &mCopy)
+	if err != nil {
+		return nil, fmt.Errorf("could not create node for replaying: %w", err)
+	}
+	return &Replayer{node: node}, nil
+}
+
+// Replay reads all entries from the given reader and replays them in the order of reading.
+// If the entries are tagged with sequence numbers, Replay checks that no entry is missing.
+// Replay returns nil when it reaches the end of the recording
+// and the first error encountered otherwise (e.g., an error returned by one of the modules).
+func (r *Replayer) Replay(reader *eventlog.Reader) error {
+
+	// Start the Processors for the duration of the replay.
+	if err := r.node.processors.startAll(); err != nil {
+		return err
+	}
+	defer r.node.processors.stopAll()
+
+	for {
+		entry, err := reader.ReadEntry()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not read recorded entry: %w", err)
+		}
+
+		if err := r.replayEntry(entry); err != nil {
+			return fmt.Errorf("could not replay entry %d: %w", entry.SeqNo, err)
+		}
+	}
+}
+
+// replayEntry passes the events of a single recorded entry to the module they have been dispatched to.
+func (r *Replayer) replayEntry(entry *recordingpb.Entry) error {
+
+	// Check that the entry has been recorded by the replayed node.
+	if t.NodeID(entry.NodeId) != r.node.ID {
+		return fmt.Errorf("entry recorded by node %d (replaying node %d)", entry.NodeId, r.node.ID)
+	}
+
+	// Check that no entry has been skipped.
+	if entry.SeqNo != 0 {
+		if r.nextSeqNo != 0 && entry.SeqNo != r.nextSeqNo {
+			return fmt.Errorf("expected entry %d (missing entries in the recording)", r.nextSeqNo)
+		}
+		r.nextSeqNo = entry.SeqNo + 1
+	}
+
+	// Skip entries that have only been recorded for information and have not been dispatched to any module.
+	for _, event := range entry.Events {
+		switch event.Type.(type) {
+		case *eventpb.Event_RequestTooLarge, *eventpb.Event_PoisonedBatch, *eventpb.Event_SendQueueOverflow,
+			*eventpb.Event_MessageTooLarge, *eventpb.Event_Notification:
+			return nil
+		}
+	}
+
+	// Determine the module the events have been dispatched to, the same way the Node does.
+	wi := newWorkItems()
+	if err := wi.AddEvents(events.ListOf(entry.Events...)); err != nil {
+		return err
+	}
+
+	// Pass the events to their module.
+	// The events of an entry are usually all dispatched to the same module,
+	// but the order of processing is fixed in any case.
+
+	if wi.WAL().Len() > 0 {
+		if _, err := appendWALEvents(r.node.modules.WAL, wi.WAL()); err != nil {
+			return fmt.Errorf("could not replay WAL events: %w", err)
+		}
+		if err := r.node.modules.WAL.Sync(); err != nil {
+			return fmt.Errorf("could not sync WAL: %w", err)
+		}
+	}
+
+	for _, list := range []struct {
+		eventsIn  *events.EventList
+		processor Processor
+	}{
+		{wi.Client(), r.node.processors.Client},
+		{wi.Hash(), r.node.processors.Hash},
+		{wi.Crypto(), r.node.processors.Crypto},
+		{wi.Net(), r.node.processors.Net},
+		{wi.App(), r.node.processors.App},
+		{wi.ReqStore(), r.node.processors.ReqStore},
+		{wi.Protocol(), r.node.processors.Protocol},
+	} {
+		if list.eventsIn.Len() == 0 {
+			continue
+		}
+		if _, err := list.processor.Process(list.eventsIn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/clients"
+	mirCrypto "github.com/hyperledger-labs/mirbft/pkg/crypto"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Replay test", func() {
+
+	It("reproduces the application state from a recording", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		// Replay the recording of the first replica against fresh modules.
+		replica := deployment.TestReplicas[0]
+		Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+		issProtocol, err := iss.New(replica.Id, iss.DefaultConfig(replica.Membership), replica.Config.Logger)
+		Expect(err).NotTo(HaveOccurred())
+		cryptoModule, err := mirCrypto.NodePseudo(replica.Membership, replica.ClientIDs, replica.Id, mirCrypto.DefaultPseudoSeed)
+		Expect(err).NotTo(HaveOccurred())
+		replayedApp := &deploytest.FakeApp{}
+
+		replayer, err := mirbft.NewReplayer(replica.Id, mirbft.DefaultNodeConfig(), &modules.Modules{
+			Net:           discardingNet{},
+			App:           replayedApp,
+			WAL:           simplewal.NewVolatileWAL(),
+			ClientTracker: clients.SigningTracker(replica.Config.Logger),
+			Protocol:      issProtocol,
+			Crypto:        cryptoModule,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		file, err := os.Open(replica.EventLogFile())
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+		reader, err := eventlog.NewReader(file)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(replayer.Replay(reader)).To(Succeed())
+		Expect(replayedApp.RequestsProcessed).To(Equal(replica.App.RequestsProcessed))
+	})
+
+	It("reproduces the protocol outputs from a protocol recording", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		replica := deployment.TestReplicas[0]
+		replica.RecordProtocol = true
+
+		deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllCheckpointed()))
+		Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+
+		// Replay the recording against a fresh protocol instance observing the recorded wall clock.
+		file, err := os.Open(replica.ProtocolLogFile())
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+		reader, err := eventlog.NewReader(file)
+		Expect(err).NotTo(HaveOccurred())
+		replayer := eventlog.NewProtocolReplayer(reader)
+
+		issConfig := iss.DefaultConfig(replica.Membership)
+		issConfig.Now = replayer.Now
+		issProtocol, err := iss.New(replica.Id, issConfig, replica.Config.Logger)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replayer.Replay(issProtocol)).To(Succeed())
+
+		// A protocol observing a different wall clock diverges from the recording,
+		// as it includes different timestamps in its Checkpoint messages.
+		file, err = os.Open(replica.ProtocolLogFile())
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+		reader, err = eventlog.NewReader(file)
+		Expect(err).NotTo(HaveOccurred())
+		replayer = eventlog.NewProtocolReplayer(reader)
+
+		issConfig = iss.DefaultConfig(replica.Membership)
+		issConfig.Now = func() time.Time {
+			return replayer.Now().Add(time.Hour)
+		}
+		issProtocol, err = iss.New(replica.Id, issConfig, replica.Config.Logger)
+		Expect(err).NotTo(HaveOccurred())
+		err = replayer.Replay(issProtocol)
+		var divergence *eventlog.DivergenceError
+		Expect(errors.As(err, &divergence)).To(BeTrue())
+	})
+})
+
+// discardingNet is a Net module that drops all sent messages and never receives any.
+type discardingNet struct{}
+
+func (dn discardingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	return nil
+}
+
+func (dn discardingNet) ReceiveChan() <-chan modules.ReceivedMessage {
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// RequestTooLargeError is returned by Node.SubmitRequest if the request payload exceeds NodeConfig.MaxRequestSize.
+type RequestTooLargeError struct {
+	ClientID t.ClientID
+	ReqNo    t.ReqNo
+
+	// Size of the rejected request payload in bytes.
+	Size int
+
+	// Maximal request payload size in bytes (as configured in NodeConfig.MaxRequestSize).
+	MaxSize int
+}
+
+// Error returns a description of the rejected request.
+func (e *RequestTooLargeError) Error() string {
+	return fmt.Sprintf("request %d of client %d too large: %d bytes (max %d bytes)",
+		e.ReqNo, e.ClientID, e.Size, e.MaxSize)
+}
+
+// checkRequestSize returns a RequestTooLargeError if data exceeds the maximal request size of the Node
+// and nil otherwise.
+func (n *Node) checkRequestSize(clientID t.ClientID, reqNo t.ReqNo, data []byte) *RequestTooLargeError {
+	maxSize := n.localParams().MaxRequestSize
+	if maxSize == 0 || len(data) <= maxSize {
+		return nil
+	}
+	return &RequestTooLargeError{
+		ClientID: clientID,
+		ReqNo:    reqNo,
+		Size:     len(data),
+		MaxSize:  maxSize,
+	}
+}
+
+// checkForwardedRequestSize checks the size of the request contained in a message received from another node.
+// If the event is a MessageReceived event carrying a forwarded request that is too large,
+// checkForwardedRequestSize records the oddity and returns false, in which case the event must be dropped.
+// Otherwise, it returns true.
+// It must only be called from the process() goroutine.
+func (n *Node) checkForwardedRequestSize(event *eventpb.Event) bool {
+	msgReceived, ok := event.Type.(*eventpb.Event_MessageReceived)
+	if !ok {
+		return true
+	}
+	fwd, ok := msgReceived.MessageReceived.Msg.Type.(*messagepb.Message_ForwardedRequest)
+	if !ok {
+		return true
+	}
+
+	req := fwd.ForwardedRequest
+	if err := n.checkRequestSize(t.ClientID(req.ClientId), t.ReqNo(req.ReqNo), req.Data); err != nil {
+		from := t.NodeID(msgReceived.MessageReceived.From)
+		n.recordRequestTooLarge(err, &from)
+		return false
+	}
+	return true
+}
+
+// dropOversizeForwardedRequests returns the events from eventList
+// except for those dropped by checkForwardedRequestSize.
+// It must only be called from the process() goroutine.
+func (n *Node) dropOversizeForwardedRequests(eventList *events.EventList) *events.EventList {
+	if n.localParams().MaxRequestSize == 0 {
+		return eventList
+	}
+
+	filtered := &events.EventList{}
+	iter := eventList.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if n.checkForwardedRequestSize(event) {
+			filtered.PushBack(event)
+		}
+	}
+	return filtered
+}
+
+// recordRequestTooLarge logs a rejected request and records the rejection as a RequestTooLarge event
+// with the event Interceptor. forwardedBy is nil if the request has been submitted locally.
+// Forwarded requests are also registered as oddities of the forwarding node.
+// It must only be called from the process() goroutine.
+func (n *Node) recordRequestTooLarge(err *RequestTooLargeError, forwardedBy *t.NodeID) {
+	if forwardedBy != nil {
+		n.logger.Log(logging.LevelWarn, "Dropping forwarded request.", "from", *forwardedBy, "err", err)
+		n.recordOddity(*forwardedBy, OddityRequestTooLarge, "%v", err)
+	} else {
+		n.logger.Log(logging.LevelWarn, "Rejected submitted request.", "err", err)
+	}
+	n.interceptEvents((&events.EventList{}).PushBack(
+		events.RequestTooLarge(err.ClientID, err.ReqNo, err.Size, err.MaxSize, forwardedBy),
+	))
+}
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The maximum request size test submits requests of which some exceed the maximal request size
+// and checks that only the others are delivered.
+var _ = Describe("Maximum request size test", func() {
+
+	It("rejects oversize requests", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     1,
+			Transport:       "fake",
+			NumFakeRequests: 12,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// The payloads of the fake requests 0-9 ("Request 0" to "Request 9") have 9 bytes,
+		// the payloads of requests 10 and 11 exceed the limit.
+		deployment.TestReplicas[0].Config.MaxRequestSize = 9
+
+		deployment.RunUntil(deployment.AllProcessed(10))
+
+		Expect(int(deployment.TestReplicas[0].App.RequestsProcessed)).To(Equal(10))
+	})
+})
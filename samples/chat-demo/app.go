@@ -48,14 +48,17 @@ func NewChatApp(reqStore modules.RequestStore) *ChatApp {
 // Each appended message is also printed to stdout.
 func (chat *ChatApp) Apply(batch *requestpb.Batch) error {
 
+	// Extract the data of all requests in the batch from the request store.
+	batchData, err := chat.reqStore.GetBatch(batch.Requests)
+	if err != nil {
+		return err
+	}
+
 	// For each request in the batch
-	for _, reqRef := range batch.Requests {
+	for i, reqRef := range batch.Requests {
 
-		// Extract request data from the request store and construct a printable chat message.
-		reqData, err := chat.reqStore.GetRequest(reqRef)
-		if err != nil {
-			return err
-		}
+		// Construct a printable chat message.
+		reqData := batchData[i]
 		chatMessage := fmt.Sprintf("Client %d: %s", reqRef.ClientId, string(reqData))
 
 		// Append the received chat message to the chat history.
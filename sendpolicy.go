@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/clock"
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// sendPriority classifies messages according to how the Node treats a failure to send them.
+type sendPriority int
+
+const (
+	// Messages the protocol relies on being delivered, e.g. ordering and checkpoint messages.
+	// Sending them is retried (see NodeConfig.SendRetries).
+	sendCritical sendPriority = iota
+
+	// Messages whose loss the protocol tolerates, e.g. forwarded requests, requests for their retransmission,
+	// or heartbeats, as the protocol repeats them itself when needed. Sending them is never retried.
+	sendBestEffort
+)
+
+// messagePriority returns the sendPriority of a message (see messagepb.Message.BestEffort).
+func messagePriority(msg *messagepb.Message) sendPriority {
+	if msg.BestEffort() {
+		return sendBestEffort
+	}
+	return sendCritical
+}
+
+// sender sends messages using the Net module, applying the send policy configured in the NodeConfig,
+// and keeps track of the reachability of the other nodes.
+// A node is considered unreachable when sending a critical message to it failed even after all retries.
+// Once a node is unreachable, sending to it is not retried any more (which would only delay sending to other nodes)
+// until a message is sent to it successfully.
+// Unless per-destination send queues are used (see NodeConfig.SendQueueLength),
+// the sender is not safe for concurrent use, as the Net module does not support concurrent sending.
+type sender struct {
+	net modules.Net
+
+	// Returns the current number of retries and the delay before the first retry (see LocalParams.SendRetries).
+	params func() *LocalParams
+
+	// Accounts for the sent messages.
+	traffic *peerTraffic
+
+	// Clock measuring the backoff between retries.
+	clock clock.Clock
+
+	// Per-destination outbound queues (see NodeConfig.SendQueueLength). If nil, messages are sent synchronously.
+	queues *sendQueues
+
+	// The set of nodes that are currently considered unreachable, protected by unreachableLock,
+	// as with send queues, messages to different destinations are sent concurrently.
+	unreachable     map[t.NodeID]struct{}
+	unreachableLock sync.Mutex
+}
+
+// newSender returns a new sender using the given Net module and the send policy from the local parameters
+// returned by params. The messages sent successfully are accounted for in traffic.
+// The backoff between retries is measured using clk.
+func newSender(net modules.Net, params func() *LocalParams, traffic *peerTraffic, clk clock.Clock) *sender {
+	return &sender{
+		net:         net,
+		params:      params,
+		traffic:     traffic,
+		clock:       clk,
+		unreachable: make(map[t.NodeID]struct{}),
+	}
+}
+
+// send sends msg to node dest.
+// If the sender uses send queues, the message is only added to the queue of dest and send always returns nil.
+// Otherwise, if the reachability of dest changes as a result of sending the message,
+// send returns the corresponding PeerHealth event. Otherwise, send returns nil.
+func (s *sender) send(dest t.NodeID, msg *messagepb.Message) *eventpb.Event {
+	if s.queues != nil {
+		s.queues.enqueue(dest, msg)
+		return nil
+	}
+	return s.transmit(dest, msg)
+}
+
+// transmit sends msg to node dest using the Net module, retrying according to the send policy.
+// If the reachability of dest changes as a result, transmit returns the corresponding PeerHealth event.
+// Otherwise, transmit returns nil.
+func (s *sender) transmit(dest t.NodeID, msg *messagepb.Message) *eventpb.Event {
+	s.unreachableLock.Lock()
+	_, wasUnreachable := s.unreachable[dest]
+	s.unreachableLock.Unlock()
+	priority := messagePriority(msg)
+
+	// Send the message, retrying with exponential backoff if sending a critical message to a reachable node fails.
+	err := s.net.Send(dest, msg)
+	if err != nil && priority == sendCritical && !wasUnreachable {
+		params := s.params()
+		backoff := params.SendRetryBackoff
+		for i := 0; i < params.SendRetries && err != nil; i++ {
+			s.clock.Sleep(backoff)
+			backoff *= 2
+			err = s.net.Send(dest, msg)
+		}
+	}
+
+	if err == nil {
+		s.traffic.messageSent(dest, msg)
+	}
+
+	// Update the reachability of the destination node.
+	s.unreachableLock.Lock()
+	defer s.unreachableLock.Unlock()
+	if err == nil && wasUnreachable {
+		delete(s.unreachable, dest)
+		return events.PeerHealth(dest, true, nil)
+	} else if err != nil && priority == sendCritical && !wasUnreachable {
+		s.unreachable[dest] = struct{}{}
+		return events.PeerHealth(dest, false, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Send retry test", func() {
+
+	It("delivers all requests despite failing sends", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Make every other attempt to send a message fail. As each failed send is retried, all messages get through.
+		for _, replica := range deployment.TestReplicas {
+			replica.Net = &flakyNet{Net: replica.Net}
+			replica.Config.SendRetries = 2
+			replica.Config.SendRetryBackoff = time.Millisecond
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// flakyNet is a Net module wrapper that fails every other attempt to send a message.
+type flakyNet struct {
+	modules.Net
+	attempts uint64
+}
+
+func (fn *flakyNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if atomic.AddUint64(&fn.attempts, 1)%2 == 1 {
+		return fmt.Errorf("simulated send failure")
+	}
+	return fn.Net.Send(dest, msg)
+}
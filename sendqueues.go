@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// sendQueues implements per-destination flow control of outbound messages (see NodeConfig.SendQueueLength).
+// Each destination has a bounded queue of messages, drained by a dedicated goroutine sending them using a sender.
+// Thus, a slow or unreachable destination only delays the messages destined to itself
+// and the messages waiting for it never occupy more than the capacity of its queue.
+// When a message is added to a full queue, a message is dropped to make space:
+// the oldest queued best-effort message, the new message if it is itself a best-effort one,
+// and only if both the queue and the new message are critical, the oldest queued (critical) message.
+// Each dropped message is reported through the overflow callback.
+// If prioritizing, queued critical messages are sent before the queued best-effort ones.
+type sendQueues struct {
+
+	// Maximal number of messages in each queue.
+	length int
+
+	// If set, critical messages are dequeued before best-effort ones (see NodeConfig.PrioritizeCriticalMessages).
+	prioritize bool
+
+	// Sends the dequeued messages.
+	sender *sender
+
+	// Called with the PeerHealth events resulting from sending the dequeued messages.
+	output func(event *eventpb.Event)
+
+	// Called with a SendQueueOverflow event for each message dropped because of a full queue.
+	overflow func(event *eventpb.Event)
+
+	// When closed, the goroutines draining the queues stop.
+	stopC <-chan struct{}
+
+	// The queues, indexed by destination node ID, created on demand. Protected by lock.
+	queues map[t.NodeID]*sendQueue
+	lock   sync.Mutex
+}
+
+// sendQueue is the outbound queue of a single destination.
+type sendQueue struct {
+
+	// The queued messages, oldest first. Protected by the lock of the sendQueues.
+	msgs []*messagepb.Message
+
+	// Signaled (without blocking) whenever a message is added to the queue.
+	readyC chan struct{}
+}
+
+// newSendQueues returns new (empty) send queues with the given length,
+// sending the queued messages using sender until stopC is closed.
+func newSendQueues(
+	length int,
+	prioritize bool,
+	sender *sender,
+	output func(event *eventpb.Event),
+	overflow func(event *eventpb.Event),
+	stopC <-chan struct{},
+) *sendQueues {
+	return &sendQueues{
+		length:     length,
+		prioritize: prioritize,
+		sender:     sender,
+		output:     output,
+		overflow:   overflow,
+		stopC:      stopC,
+		queues:     make(map[t.NodeID]*sendQueue),
+	}
+}
+
+// enqueue adds msg to the queue of node dest, dropping a message if the queue is full.
+// When the first message is added to the queue of a destination, the goroutine draining the queue is started.
+func (sq *sendQueues) enqueue(dest t.NodeID, msg *messagepb.Message) {
+	sq.lock.Lock()
+	defer sq.lock.Unlock()
+
+	q, ok := sq.queues[dest]
+	if !ok {
+		q = &sendQueue{readyC: make(chan struct{}, 1)}
+		sq.queues[dest] = q
+		go sq.drain(dest, q)
+	}
+
+	// If the queue is full, drop the oldest best-effort message, the new one, or the oldest critical one,
+	// in this order of preference.
+	if len(q.msgs) >= sq.length {
+		dropIdx := 0
+		for i, queued := range q.msgs {
+			if messagePriority(queued) == sendBestEffort {
+				dropIdx = i
+				break
+			}
+		}
+		if messagePriority(q.msgs[dropIdx]) == sendCritical && messagePriority(msg) == sendBestEffort {
+			sq.overflow(events.SendQueueOverflow(dest, false))
+			return
+		}
+		sq.overflow(events.SendQueueOverflow(dest, messagePriority(q.msgs[dropIdx]) == sendCritical))
+		q.msgs = append(q.msgs[:dropIdx], q.msgs[dropIdx+1:]...)
+	}
+
+	// Add the message to the queue and wake up the draining goroutine.
+	q.msgs = append(q.msgs, msg)
+	select {
+	case q.readyC <- struct{}{}:
+	default:
+	}
+}
+
+// drain sends the messages in the queue of node dest (in the order they were added, critical ones first
+// if prioritizing) until sq.stopC is closed.
+func (sq *sendQueues) drain(dest t.NodeID, q *sendQueue) {
+	for {
+		select {
+		case <-q.readyC:
+		case <-sq.stopC:
+			return
+		}
+
+		// Send the queued messages one by one, such that a message only leaves the queue when it is being sent.
+		for msg := sq.dequeue(q); msg != nil; msg = sq.dequeue(q) {
+			if peerHealth := sq.sender.transmit(dest, msg); peerHealth != nil {
+				sq.output(peerHealth)
+			}
+		}
+	}
+}
+
+// dequeue removes and returns the oldest message from queue q, or returns nil if q is empty.
+// If prioritizing, dequeue returns the oldest critical message, if q contains any.
+func (sq *sendQueues) dequeue(q *sendQueue) *messagepb.Message {
+	sq.lock.Lock()
+	defer sq.lock.Unlock()
+
+	if len(q.msgs) == 0 {
+		return nil
+	}
+
+	idx := 0
+	if sq.prioritize {
+		for i, queued := range q.msgs {
+			if messagePriority(queued) == sendCritical {
+				idx = i
+				break
+			}
+		}
+	}
+
+	msg := q.msgs[idx]
+	q.msgs = append(q.msgs[:idx], q.msgs[idx+1:]...)
+	return msg
+}
+
+// outputSendEvent passes an event resulting from sending a queued message (e.g. PeerHealth) to the process() goroutine.
+// It blocks until the event is accepted or the Node stops.
+func (n *Node) outputSendEvent(event *eventpb.Event) {
+	select {
+	case n.workChans.workItemInput <- (&events.EventList{}).PushBack(event):
+	case <-n.workErrNotifier.ExitC():
+	}
+}
+
+// recordSendOverflow logs a message dropped from a full send queue and passes the SendQueueOverflow event
+// to the process() goroutine to be recorded with the event Interceptor. It never blocks.
+func (n *Node) recordSendOverflow(event *eventpb.Event) {
+	overflow := event.Type.(*eventpb.Event_SendQueueOverflow).SendQueueOverflow
+	n.logger.Log(logging.LevelWarn, "Send queue full, dropping message.",
+		"dest", overflow.Destination, "critical", overflow.Critical)
+	select {
+	case n.sendOverflows <- event:
+	default:
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+var _ = Describe("Send queue test", func() {
+
+	It("keeps delivering requests while sending to one node blocks", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// At all other replicas, sending to the last replica blocks until the end of the test.
+		blocked := deployment.TestReplicas[len(deployment.TestReplicas)-1]
+		releaseC := make(chan struct{})
+		defer close(releaseC)
+		for _, replica := range deployment.TestReplicas {
+			if replica != blocked {
+				replica.Net = &blockingNet{Net: replica.Net, blocked: blocked.Id, releaseC: releaseC}
+				replica.Config.SendQueueLength = 16
+			}
+		}
+
+		deployment.RunUntil(func() bool {
+			for _, replica := range deployment.TestReplicas {
+				if replica != blocked && replica.App.Processed() < uint64(testConfig.NumFakeRequests) {
+					return false
+				}
+			}
+			return true
+		})
+
+		// The blocked replica does not affect the others, which still form a quorum.
+		for _, replica := range deployment.TestReplicas {
+			if replica != blocked {
+				Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			}
+		}
+	})
+})
+
+// blockingNet is a Net module wrapper on which sending to one node blocks until releaseC is closed.
+// The blocked messages are dropped.
+type blockingNet struct {
+	modules.Net
+	blocked  t.NodeID
+	releaseC chan struct{}
+}
+
+func (bn *blockingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if dest == bn.blocked {
+		<-bn.releaseC
+		return nil
+	}
+	return bn.Net.Send(dest, msg)
+}
+
+// The priority lanes test coalesces messages while prioritizing critical ones
+// and checks that critical and best-effort messages are never bundled together.
+var _ = Describe("Priority lanes test", func() {
+
+	It("delivers all requests when prioritizing critical messages", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 100,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Gossip the requests, such that forwarded requests compete with the protocol messages,
+		// and count the sent bundles mixing critical and best-effort messages.
+		laneNets := make([]*laneCheckingNet, len(deployment.TestReplicas))
+		for i, replica := range deployment.TestReplicas {
+			laneNets[i] = &laneCheckingNet{Net: replica.Net}
+			replica.Net = laneNets[i]
+			replica.GossipFanout = 1
+			replica.Config.CoalesceWindow = 5 * time.Millisecond
+			replica.Config.SendQueueLength = 1000
+			replica.Config.PrioritizeCriticalMessages = true
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+
+		for i, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			Expect(atomic.LoadUint64(&laneNets[i].mixed)).To(BeZero())
+		}
+	})
+})
+
+// laneCheckingNet is a Net module wrapper that counts the sent message bundles
+// containing both forwarded requests and ordering or checkpoint messages.
+type laneCheckingNet struct {
+	modules.Net
+	mixed uint64
+}
+
+func (ln *laneCheckingNet) Send(dest t.NodeID, msg *messagepb.Message) error {
+	if bundle, ok := msg.Type.(*messagepb.Message_Bundle); ok {
+		forwarded, critical := false, false
+		for _, bundled := range bundle.Bundle.Msgs {
+			switch m := bundled.Type.(type) {
+			case *messagepb.Message_ForwardedRequest:
+				forwarded = true
+			case *messagepb.Message_Iss:
+				switch m.Iss.Type.(type) {
+				case *isspb.ISSMessage_Sb, *isspb.ISSMessage_Checkpoint:
+					critical = true
+				}
+			}
+		}
+		if forwarded && critical {
+			atomic.AddUint64(&ln.mixed, 1)
+		}
+	}
+	return ln.Net.Send(dest, msg)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// checkpointWaiters keeps track of the callers of Node.Snapshot waiting for a stable checkpoint.
+// The zero value is ready to use.
+type checkpointWaiters struct {
+
+	// The waiting callers. Protected by lock.
+	waiters []*checkpointWaiter
+
+	// Number of sequence numbers covered by the last stable checkpoint. Protected by lock.
+	lastStableSn t.SeqNr
+
+	lock sync.Mutex
+}
+
+// checkpointWaiter represents a single caller of Node.Snapshot.
+type checkpointWaiter struct {
+
+	// The caller waits for a stable checkpoint covering at least minSn sequence numbers.
+	minSn t.SeqNr
+
+	// The stable checkpoint is written to resultC (with a buffer of size 1) once it is reached.
+	resultC chan *eventpb.CheckpointStable
+}
+
+// Snapshot waits for a stable checkpoint that includes all the batches committed by the Node
+// (i.e., delivered to the application) before Snapshot was called, and returns it.
+// Once Snapshot returns, a quorum of nodes agreed on the checkpoint and the snapshot of the application state
+// associated with it, which makes it a good moment for taking backups (e.g. using ExportState) or migrating the Node.
+// The returned checkpoint covers the first CheckpointStable.Sn sequence numbers.
+// Note that the Protocol determines when checkpoints are taken. With ISS, this is at the end of each epoch.
+// Snapshot returns an error if ctx is canceled or the Node halts before the checkpoint becomes stable.
+func (n *Node) Snapshot(ctx context.Context) (*eventpb.CheckpointStable, error) {
+
+	// Register the waiter, such that the checkpoint must cover all batches committed so far
+	// and be more recent than the last stable checkpoint.
+	minSn := t.SeqNr(0)
+	if lastCommitted, ok := n.LastCommitted(); ok {
+		minSn = lastCommitted + 1
+	}
+
+	waiter := &checkpointWaiter{resultC: make(chan *eventpb.CheckpointStable, 1)}
+	n.checkpointWaiters.lock.Lock()
+	if minSn <= n.checkpointWaiters.lastStableSn {
+		minSn = n.checkpointWaiters.lastStableSn + 1
+	}
+	waiter.minSn = minSn
+	n.checkpointWaiters.waiters = append(n.checkpointWaiters.waiters, waiter)
+	n.checkpointWaiters.lock.Unlock()
+
+	select {
+	case checkpoint := <-waiter.resultC:
+		return checkpoint, nil
+	case <-ctx.Done():
+		n.removeCheckpointWaiter(waiter)
+		return nil, ctx.Err()
+	case <-n.workErrNotifier.ExitC():
+		n.removeCheckpointWaiter(waiter)
+		return nil, n.workErrNotifier.Err()
+	}
+}
+
+// removeCheckpointWaiter removes the given waiter (that stopped waiting) from the list of waiters.
+func (n *Node) removeCheckpointWaiter(waiter *checkpointWaiter) {
+	n.checkpointWaiters.lock.Lock()
+	defer n.checkpointWaiters.lock.Unlock()
+
+	for i, w := range n.checkpointWaiters.waiters {
+		if w == waiter {
+			n.checkpointWaiters.waiters = append(n.checkpointWaiters.waiters[:i], n.checkpointWaiters.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifyCheckpointWaiters passes the stable checkpoints announced by the given Notification events
+// to the callers of Snapshot waiting for them.
+// It must only be called from the process() goroutine.
+func (n *Node) notifyCheckpointWaiters(notifications *events.EventList) {
+	n.checkpointWaiters.lock.Lock()
+	defer n.checkpointWaiters.lock.Unlock()
+
+	iter := notifications.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		notification := event.Type.(*eventpb.Event_Notification).Notification
+		checkpointStable, ok := notification.Type.(*eventpb.Notification_CheckpointStable)
+		if !ok {
+			continue
+		}
+		checkpoint := checkpointStable.CheckpointStable
+		if t.SeqNr(checkpoint.Sn) > n.checkpointWaiters.lastStableSn {
+			n.checkpointWaiters.lastStableSn = t.SeqNr(checkpoint.Sn)
+		}
+
+		// Pass the checkpoint to all the waiters it satisfies and keep the others.
+		remaining := n.checkpointWaiters.waiters[:0]
+		for _, waiter := range n.checkpointWaiters.waiters {
+			if t.SeqNr(checkpoint.Sn) >= waiter.minSn {
+				waiter.resultC <- checkpoint
+			} else {
+				remaining = append(remaining, waiter)
+			}
+		}
+		n.checkpointWaiters.waiters = remaining
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The snapshot test requests a snapshot from each node in the middle of the protocol execution
+// and checks that each node obtains a stable checkpoint covering all the batches it committed before the request.
+var _ = Describe("Snapshot test", func() {
+
+	It("waits for a stable checkpoint covering all committed batches", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Once all nodes committed some batches, request a snapshot from each node,
+		// remembering the last batch it committed before.
+		checkpoints := make([]*eventpb.CheckpointStable, len(deployment.TestReplicas))
+		snapshotErrs := make([]error, len(deployment.TestReplicas))
+		lastCommitted := make([]t.SeqNr, len(deployment.TestReplicas))
+		deployment.RunUntilThen(deploytest.AllCommitted(deployment.TestReplicas, 1), func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			var wg sync.WaitGroup
+			for i, replica := range deployment.TestReplicas {
+				i, node := i, replica.Node()
+				lastCommitted[i] = node.Healthy().LastCommitSn
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					checkpoints[i], snapshotErrs[i] = node.Snapshot(ctx)
+				}()
+			}
+			wg.Wait()
+		})
+
+		for i := range deployment.TestReplicas {
+			Expect(snapshotErrs[i]).NotTo(HaveOccurred())
+			Expect(t.SeqNr(checkpoints[i].Sn)).To(BeNumerically(">", lastCommitted[i]))
+		}
+	})
+})
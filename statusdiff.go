@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// StatusDiff describes what changed between two status snapshots of a Node (see DiffStatus).
+// It is useful, e.g., in tests asserting that a Node makes progress,
+// or in monitoring to detect a Node that made no progress since the last time its status was obtained.
+// Only the parts of the status describing the progress of the protocol are compared.
+type StatusDiff struct {
+
+	// The epoch of the old and the new status.
+	OldEpoch t.EpochNr
+	NewEpoch t.EpochNr
+
+	// The epoch changes that happened in between, oldest first, with their causes.
+	// Only includes the epoch changes still present in the (bounded) epoch history of the new status.
+	EpochChanges []*isspb.EpochChange
+
+	// Number of sequence numbers delivered in between.
+	Delivered uint64
+
+	// The last stable checkpoint of the old and the new status.
+	OldStableSN t.SeqNr
+	NewStableSN t.SeqNr
+
+	// The clients whose low watermark moved, ordered by client ID.
+	// Clients added in between are included (with a zero OldLowWatermark), removed ones are not.
+	Watermarks []ClientWatermarkDiff
+
+	// The orderers of the new epoch that proposed or committed sequence numbers in between, ordered by instance.
+	// If the epoch changed in between, all the progress of the orderers of the new epoch is included.
+	Orderers []OrdererDiff
+
+	// Nodes that started (resp. stopped) being slow (see iss.Config.MaxCheckpointLag) in between, in increasing order.
+	NewlySlow []t.NodeID
+	CaughtUp  []t.NodeID
+
+	// Change of the total number of events pending in the Node's internal buffers (see statuspb.WorkItemsStatus).
+	PendingEventsDelta int64
+}
+
+// ClientWatermarkDiff describes how the low watermark of a client moved, i.e., how its requests got committed.
+type ClientWatermarkDiff struct {
+	ClientID        t.ClientID
+	OldLowWatermark t.ReqNo
+	NewLowWatermark t.ReqNo
+}
+
+// OrdererDiff describes the progress of an orderer (SB instance) of the current epoch.
+type OrdererDiff struct {
+	Instance t.SBInstanceID
+	Leader   t.NodeID
+
+	// Number of sequence numbers of the orderer's segment newly proposed and committed.
+	Proposed  int
+	Committed int
+}
+
+// DiffStatus compares two status snapshots of the same Node, the older one first, and returns what changed.
+// Any of the two snapshots can lack the protocol status, in which case it is treated as the initial state of the protocol.
+// Sequence numbers, watermarks and checkpoints moving backwards (e.g. if the snapshots were passed in the wrong order)
+// are not considered progress.
+func DiffStatus(oldStatus, newStatus *statuspb.NodeStatus) *StatusDiff {
+	oldISS := oldStatus.GetProtocol().GetIss()
+	newISS := newStatus.GetProtocol().GetIss()
+
+	d := &StatusDiff{
+		OldEpoch:    t.EpochNr(oldISS.GetEpoch()),
+		NewEpoch:    t.EpochNr(newISS.GetEpoch()),
+		OldStableSN: t.SeqNr(oldISS.GetLastStableSn()),
+		NewStableSN: t.SeqNr(newISS.GetLastStableSn()),
+		Watermarks:  make([]ClientWatermarkDiff, 0),
+		Orderers:    make([]OrdererDiff, 0),
+		NewlySlow:   make([]t.NodeID, 0),
+		CaughtUp:    make([]t.NodeID, 0),
+		PendingEventsDelta: pendingEvents(newStatus.GetWorkItems()) -
+			pendingEvents(oldStatus.GetWorkItems()),
+	}
+
+	if newISS.GetNextDeliveredSn() > oldISS.GetNextDeliveredSn() {
+		d.Delivered = newISS.GetNextDeliveredSn() - oldISS.GetNextDeliveredSn()
+	}
+
+	d.EpochChanges = make([]*isspb.EpochChange, 0)
+	for _, change := range newISS.GetEpochHistory() {
+		if change.Epoch > oldISS.GetEpoch() {
+			d.EpochChanges = append(d.EpochChanges, change)
+		}
+	}
+
+	oldLowWatermarks := make(map[uint64]uint64)
+	for _, client := range oldISS.GetClients() {
+		oldLowWatermarks[client.ClientId] = client.LowWatermark
+	}
+	for _, client := range newISS.GetClients() {
+		if oldLowWatermark := oldLowWatermarks[client.ClientId]; client.LowWatermark > oldLowWatermark {
+			d.Watermarks = append(d.Watermarks, ClientWatermarkDiff{
+				ClientID:        t.ClientID(client.ClientId),
+				OldLowWatermark: t.ReqNo(oldLowWatermark),
+				NewLowWatermark: t.ReqNo(client.LowWatermark),
+			})
+		}
+	}
+	sort.Slice(d.Watermarks, func(i, j int) bool { return d.Watermarks[i].ClientID < d.Watermarks[j].ClientID })
+
+	// Orderers are only comparable within the same epoch. Instance IDs are never reused across epochs.
+	oldOrderers := make(map[uint64]*isspb.SBStatus)
+	for _, orderer := range oldISS.GetOrderers() {
+		oldOrderers[orderer.Instance] = orderer
+	}
+	for _, orderer := range newISS.GetOrderers() {
+		oldOrderer := oldOrderers[orderer.Instance]
+		ordererDiff := OrdererDiff{
+			Instance:  t.SBInstanceID(orderer.Instance),
+			Leader:    t.NodeID(orderer.Leader),
+			Proposed:  len(orderer.Proposed) - len(oldOrderer.GetProposed()),
+			Committed: len(orderer.Committed) - len(oldOrderer.GetCommitted()),
+		}
+		if ordererDiff.Proposed > 0 || ordererDiff.Committed > 0 {
+			d.Orderers = append(d.Orderers, ordererDiff)
+		}
+	}
+	sort.Slice(d.Orderers, func(i, j int) bool { return d.Orderers[i].Instance < d.Orderers[j].Instance })
+
+	oldSlow := make(map[uint64]bool)
+	for _, nodeLag := range oldISS.GetNodeLags() {
+		oldSlow[nodeLag.NodeId] = nodeLag.Slow
+	}
+	for _, nodeLag := range newISS.GetNodeLags() {
+		if nodeLag.Slow && !oldSlow[nodeLag.NodeId] {
+			d.NewlySlow = append(d.NewlySlow, t.NodeID(nodeLag.NodeId))
+		} else if !nodeLag.Slow && oldSlow[nodeLag.NodeId] {
+			d.CaughtUp = append(d.CaughtUp, t.NodeID(nodeLag.NodeId))
+		}
+	}
+	sort.Slice(d.NewlySlow, func(i, j int) bool { return d.NewlySlow[i] < d.NewlySlow[j] })
+	sort.Slice(d.CaughtUp, func(i, j int) bool { return d.CaughtUp[i] < d.CaughtUp[j] })
+
+	return d
+}
+
+// Progressed returns true if the Node made progress in between the two snapshots, i.e.,
+// if it delivered a sequence number, a checkpoint became stable, a client watermark moved, or the epoch changed.
+// Newly proposed (but not yet delivered) sequence numbers do not count as progress.
+func (d *StatusDiff) Progressed() bool {
+	return d.Delivered > 0 || d.NewStableSN > d.OldStableSN || len(d.Watermarks) > 0 || d.NewEpoch > d.OldEpoch
+}
+
+// String returns a one-line, human-readable summary of the changes.
+func (d *StatusDiff) String() string {
+	if !d.Progressed() && len(d.Orderers) == 0 && len(d.NewlySlow) == 0 && len(d.CaughtUp) == 0 {
+		return fmt.Sprintf("no progress (epoch %d, stable checkpoint at SN %d)", d.NewEpoch, d.NewStableSN)
+	}
+
+	changes := make([]string, 0)
+	if d.NewEpoch != d.OldEpoch {
+		changes = append(changes, fmt.Sprintf("epoch %d -> %d", d.OldEpoch, d.NewEpoch))
+	}
+	if d.Delivered > 0 {
+		changes = append(changes, fmt.Sprintf("delivered %d SNs", d.Delivered))
+	}
+	if d.NewStableSN != d.OldStableSN {
+		changes = append(changes, fmt.Sprintf("stable checkpoint SN %d -> %d", d.OldStableSN, d.NewStableSN))
+	}
+	if len(d.Watermarks) > 0 {
+		changes = append(changes, fmt.Sprintf("%d client watermarks moved", len(d.Watermarks)))
+	}
+	if len(d.Orderers) > 0 {
+		proposed, committed := 0, 0
+		for _, orderer := range d.Orderers {
+			proposed += orderer.Proposed
+			committed += orderer.Committed
+		}
+		changes = append(changes, fmt.Sprintf("%d orderers proposed %d and committed %d SNs",
+			len(d.Orderers), proposed, committed))
+	}
+	if len(d.NewlySlow) > 0 {
+		changes = append(changes, fmt.Sprintf("nodes %v slow", d.NewlySlow))
+	}
+	if len(d.CaughtUp) > 0 {
+		changes = append(changes, fmt.Sprintf("nodes %v caught up", d.CaughtUp))
+	}
+	return strings.Join(changes, ", ")
+}
+
+// pendingEvents returns the total number of events pending in the Node's internal buffers.
+func pendingEvents(s *statuspb.WorkItemsStatus) int64 {
+	return int64(s.GetWal() + s.GetNet() + s.GetHash() + s.GetClient() + s.GetApp() +
+		s.GetReqStore() + s.GetProtocol() + s.GetCrypto())
+}
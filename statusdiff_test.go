@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+var _ = Describe("Status diff test", func() {
+
+	It("reports the progress between status snapshots", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Sample the status of each node until the node stops.
+		statuses := make([][]*statuspb.NodeStatus, len(deployment.TestReplicas))
+		var wg sync.WaitGroup
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				statusC := node.StatusStream(context.Background(), 100*time.Millisecond)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for s := range statusC {
+						statuses[i] = append(statuses[i], s)
+					}
+				}()
+			}
+		}
+
+		// Run until the client watermarks moved, i.e., until a checkpoint covering the requests became stable
+		// and the requests were garbage-collected. The final status of each node closes its sequence of snapshots.
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(
+			deployment.AllProcessed(testConfig.NumFakeRequests),
+			deployment.AllInEpoch(1),
+			deployment.AllWatermarksMoved(),
+		))
+		wg.Wait()
+		for i, finalStatus := range finalStatuses {
+			Expect(finalStatus.StatusErr).NotTo(HaveOccurred())
+			statuses[i] = append(statuses[i], finalStatus.Status)
+		}
+
+		for i := range deployment.TestReplicas {
+			Expect(len(statuses[i])).To(BeNumerically(">", 1))
+			first, last := statuses[i][0], statuses[i][len(statuses[i])-1]
+
+			// Over the whole run, the node delivered batches, committed all requests and changed epochs.
+			total := mirbft.DiffStatus(first, last)
+			Expect(total.Progressed()).To(BeTrue())
+			Expect(total.Delivered).To(BeNumerically(">", 0))
+			Expect(total.NewEpoch).To(BeNumerically(">", total.OldEpoch))
+			Expect(total.EpochChanges).NotTo(BeEmpty())
+			Expect(total.Watermarks).NotTo(BeEmpty())
+			Expect(total.String()).To(ContainSubstring("delivered"))
+
+			// The progress between consecutive snapshots adds up to the total progress.
+			delivered := uint64(0)
+			for j := 1; j < len(statuses[i]); j++ {
+				delivered += mirbft.DiffStatus(statuses[i][j-1], statuses[i][j]).Delivered
+			}
+			Expect(delivered).To(Equal(total.Delivered))
+
+			// Nothing changes between a snapshot and itself.
+			none := mirbft.DiffStatus(last, last)
+			Expect(none.Progressed()).To(BeFalse())
+			Expect(none.String()).To(HavePrefix("no progress"))
+		}
+	})
+})
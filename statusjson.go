@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+// The status of a Node (as returned by Status and StatusStream) is a statuspb.NodeStatus protobuf message,
+// including the status of the protocol (e.g. isspb.Status) and of the other modules.
+// In binary form, it can be (de)serialized directly using proto.Marshal and proto.Unmarshal.
+// Since the status contains oneof fields (e.g. statuspb.ProtocolStatus), however, serializing it using encoding/json
+// does not produce a representation that can be parsed again.
+// MarshalStatusJSON and UnmarshalStatusJSON provide a stable JSON representation of the status
+// (the canonical JSON mapping of protobuf) that can be consumed by monitoring pipelines and tools written in other languages.
+
+// statusJSONMarshaler is used for converting Node status to JSON.
+// All fields are emitted, even if they have zero values,
+// so that consumers of the JSON representation do not need to know the protobuf defaults.
+var statusJSONMarshaler = jsonpb.Marshaler{
+	OrigName:     true,
+	EmitDefaults: true,
+}
+
+// statusJSONUnmarshaler is used for parsing Node status from JSON.
+// Unknown fields are ignored, such that a status produced by a newer version of the library can still be parsed.
+var statusJSONUnmarshaler = jsonpb.Unmarshaler{
+	AllowUnknownFields: true,
+}
+
+// MarshalStatusJSON returns the JSON representation of a Node status,
+// using the canonical JSON mapping of protobuf with the field names as defined in the .proto files.
+func MarshalStatusJSON(s *statuspb.NodeStatus) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := statusJSONMarshaler.Marshal(&buf, s); err != nil {
+		return nil, fmt.Errorf("could not marshal node status to JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalStatusJSON parses the JSON representation of a Node status as produced by MarshalStatusJSON.
+func UnmarshalStatusJSON(data []byte) (*statuspb.NodeStatus, error) {
+	s := &statuspb.NodeStatus{}
+	if err := statusJSONUnmarshaler.Unmarshal(bytes.NewReader(data), s); err != nil {
+		return nil, fmt.Errorf("could not unmarshal node status from JSON: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+// The status serialization test serializes the final status of all nodes to JSON and to protobuf
+// and checks that the status can be parsed again from both representations.
+var _ = Describe("Status serialization test", func() {
+
+	It("serializes the status to JSON and protobuf", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllInEpoch(1)))
+
+		for _, finalStatus := range finalStatuses {
+			Expect(finalStatus.StatusErr).NotTo(HaveOccurred())
+			status := finalStatus.Status
+			Expect(status.Protocol.GetIss()).NotTo(BeNil())
+
+			// JSON round trip.
+			data, err := mirbft.MarshalStatusJSON(status)
+			Expect(err).NotTo(HaveOccurred())
+			parsed, err := mirbft.UnmarshalStatusJSON(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proto.Equal(parsed, status)).To(BeTrue())
+
+			// The JSON representation can be consumed without knowledge of the protobuf types.
+			var generic map[string]interface{}
+			Expect(json.Unmarshal(data, &generic)).To(Succeed())
+			Expect(generic).To(HaveKey("protocol"))
+			Expect(generic["protocol"]).To(HaveKey("iss"))
+			Expect(generic["protocol"].(map[string]interface{})["iss"]).To(HaveKey("epoch"))
+
+			// Protobuf round trip.
+			data, err = proto.Marshal(status)
+			Expect(err).NotTo(HaveOccurred())
+			parsed = &statuspb.NodeStatus{}
+			Expect(proto.Unmarshal(data, parsed)).To(Succeed())
+			Expect(proto.Equal(parsed, status)).To(BeTrue())
+		}
+
+		// Malformed JSON is rejected.
+		_, err := mirbft.UnmarshalStatusJSON([]byte("{"))
+		Expect(err).To(HaveOccurred())
+	})
+})
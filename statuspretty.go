@@ -0,0 +1,282 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+// DefaultStatusWidth is the width (in characters) of the output of PrettyStatus if no positive width is given.
+const DefaultStatusWidth = 80
+
+// PrettyStatus returns a human-readable, multi-line representation of a Node status,
+// formatted not to exceed width characters per line (DefaultStatusWidth if width is not positive).
+// Besides the progress of the protocol, it shows the occupancy of the sequence numbers of each segment,
+// the requests waiting in each bucket, the ready and committed requests of each client,
+// the messages buffered from each peer, what the transition to the next epoch is waiting for,
+// the causes of the recent epoch changes, and the latencies of persisting data.
+// It is meant for debugging, e.g., for inspecting a network that stopped making progress,
+// and its format may change at any time. For a machine-readable representation, use MarshalStatusJSON.
+func PrettyStatus(s *statuspb.NodeStatus, width int) string {
+	if width <= 0 {
+		width = DefaultStatusWidth
+	}
+	p := &statusPrinter{width: width}
+
+	if iss := s.GetProtocol().GetIss(); iss != nil {
+		p.printISS(iss)
+	} else {
+		p.line("Protocol: no status available")
+	}
+
+	if workItems := s.GetWorkItems(); workItems != nil {
+		p.section("Pending events")
+		p.cells([]string{
+			fmt.Sprintf("wal:%d", workItems.Wal),
+			fmt.Sprintf("net:%d", workItems.Net),
+			fmt.Sprintf("hash:%d", workItems.Hash),
+			fmt.Sprintf("client:%d", workItems.Client),
+			fmt.Sprintf("app:%d", workItems.App),
+			fmt.Sprintf("reqstore:%d", workItems.ReqStore),
+			fmt.Sprintf("protocol:%d", workItems.Protocol),
+			fmt.Sprintf("crypto:%d", workItems.Crypto),
+		})
+	}
+
+	if len(s.GetPersistLatencies()) > 0 {
+		p.section("Persistence latencies (operation:count p50/p90/p99/max us)")
+		for _, latency := range s.PersistLatencies {
+			p.labeledCells(latency.Operation, []string{
+				fmt.Sprintf("%d", latency.Count),
+				fmt.Sprintf("%d/%d/%d/%d", latency.P50Us, latency.P90Us, latency.P99Us, latency.MaxUs),
+			})
+		}
+	}
+
+	if len(s.GetOddities()) > 0 {
+		p.section("Oddities")
+		for _, oddities := range s.Oddities {
+			cells := make([]string, 0, len(oddities.Counts))
+			for _, kind := range sortedKeys(oddities.Counts) {
+				cells = append(cells, fmt.Sprintf("%s:%d", kind, oddities.Counts[kind]))
+			}
+			p.labeledCells(fmt.Sprintf("node %d", oddities.NodeId), cells)
+		}
+	}
+
+	return p.String()
+}
+
+// printISS prints the status of the ISS protocol.
+func (p *statusPrinter) printISS(s *isspb.Status) {
+	p.line(fmt.Sprintf("Epoch %d: delivered up to SN %d, last stable checkpoint at SN %d",
+		s.Epoch, s.NextDeliveredSn, s.LastStableSn))
+
+	if next := s.NextEpoch; next != nil {
+		p.section(fmt.Sprintf("Next epoch %d (starts at SN %d, %d SNs uncommitted)",
+			next.Epoch, next.FirstSn, next.Uncommitted))
+		p.labeledCells("leaders", uint64Cells(next.Leaders))
+		p.labeledCells("unresponsive", uint64Cells(next.UnresponsiveLeaders))
+		p.labeledCells("handed off", uint64Cells(next.HandoffLeaders))
+		if len(next.PendingMembership) > 0 {
+			p.labeledCells("new members", uint64Cells(next.PendingMembership))
+		}
+	}
+
+	if len(s.EpochHistory) > 0 {
+		p.section("Epoch history (cause:implicated leader)")
+		for _, change := range s.EpochHistory {
+			causes := make([]string, 0, len(change.Causes))
+			for _, cause := range change.Causes {
+				switch cause.Reason {
+				case isspb.EpochChangeReason_CONFIG_CHANGE, isspb.EpochChangeReason_RESTORED:
+					causes = append(causes, strings.ToLower(cause.Reason.String()))
+				default:
+					causes = append(causes, fmt.Sprintf("%s:%d", strings.ToLower(cause.Reason.String()), cause.NodeId))
+				}
+			}
+			p.labeledCells(fmt.Sprintf("epoch %d at SN %d", change.Epoch, change.FirstSn), causes)
+		}
+	}
+
+	// Each sequence number of a segment is shown as one character: committed, proposed, or empty.
+	p.section("Segments (# committed, + proposed, . empty)")
+	for _, orderer := range s.Orderers {
+		committed := uint64Set(orderer.Committed)
+		proposed := uint64Set(orderer.Proposed)
+		var occupancy strings.Builder
+		for _, sn := range orderer.SeqNrs {
+			if _, ok := committed[sn]; ok {
+				occupancy.WriteByte('#')
+			} else if _, ok := proposed[sn]; ok {
+				occupancy.WriteByte('+')
+			} else {
+				occupancy.WriteByte('.')
+			}
+		}
+		p.labeledStrip(fmt.Sprintf("instance %d (leader %d, %d/%d committed)",
+			orderer.Instance, orderer.Leader, len(orderer.Committed), len(orderer.SeqNrs)), occupancy.String())
+	}
+
+	p.section("Buckets (bucket:leader/ready requests)")
+	bucketCells := make([]string, 0, len(s.Buckets))
+	for _, bucket := range s.Buckets {
+		bucketCells = append(bucketCells, fmt.Sprintf("%d:%d/%d", bucket.Id, bucket.Leader, bucket.Requests))
+	}
+	p.cells(bucketCells)
+
+	p.section("Clients (client:ready/low watermark/committed above)")
+	clientCells := make([]string, 0, len(s.Clients))
+	for _, client := range s.Clients {
+		clientCells = append(clientCells,
+			fmt.Sprintf("%d:%d/%d/%d", client.ClientId, client.Ready, client.LowWatermark, client.Committed))
+	}
+	p.cells(clientCells)
+
+	p.section("Buffered messages (node:messages/bytes)")
+	bufferCells := make([]string, 0, len(s.MessageBuffers))
+	for _, buffer := range s.MessageBuffers {
+		bufferCells = append(bufferCells, fmt.Sprintf("%d:%d/%d", buffer.NodeId, buffer.Messages, buffer.Size))
+	}
+	p.cells(bufferCells)
+
+	slow := make([]string, 0)
+	for _, nodeLag := range s.NodeLags {
+		if nodeLag.Slow {
+			slow = append(slow, fmt.Sprintf("%d(-%d)", nodeLag.NodeId, nodeLag.Lag))
+		}
+	}
+	if len(slow) > 0 {
+		p.section("Slow nodes (node(SNs behind))")
+		p.cells(slow)
+	}
+}
+
+// statusPrinter accumulates lines of output, none of which is longer than width
+// (unless the width is too small to fit any indentation).
+type statusPrinter struct {
+	width int
+	out   strings.Builder
+}
+
+// String returns the output printed so far.
+func (p *statusPrinter) String() string {
+	return p.out.String()
+}
+
+// line prints a line of text. If the text is wider than the printer,
+// it is wrapped (preferably at a space) and the continuation lines are indented.
+func (p *statusPrinter) line(text string) {
+	const indent = "    "
+	for len(text) > p.width {
+		cut := strings.LastIndexByte(text[:p.width], ' ')
+		if cut <= len(indent) {
+			cut = p.width
+		}
+		p.out.WriteString(strings.TrimRight(text[:cut], " "))
+		p.out.WriteByte('\n')
+		text = indent + strings.TrimLeft(text[cut:], " ")
+		if len(indent) >= p.width {
+			// The printer is too narrow for the indentation, give up on formatting.
+			break
+		}
+	}
+	p.out.WriteString(text)
+	p.out.WriteByte('\n')
+}
+
+// section prints the heading of a new section.
+func (p *statusPrinter) section(title string) {
+	p.line(title + ":")
+}
+
+// cells prints the given cells separated by spaces, indented and wrapped to the width of the printer.
+// An empty list of cells is printed as a single dash.
+func (p *statusPrinter) cells(cells []string) {
+	p.labeledCells("", cells)
+}
+
+// labeledCells prints the given cells like cells, prefixed by a label on the first line.
+func (p *statusPrinter) labeledCells(label string, cells []string) {
+	prefix := "  "
+	if label != "" {
+		prefix += label + ": "
+	}
+	if len(cells) == 0 {
+		cells = []string{"-"}
+	}
+
+	// Continuation lines are indented like the first cell, unless the label leaves too little space.
+	indent := strings.Repeat(" ", len(prefix))
+	if len(prefix) > p.width/2 {
+		indent = "    "
+	}
+
+	current := prefix
+	empty := true
+	for _, cell := range cells {
+		if !empty && len(current)+1+len(cell) > p.width {
+			p.line(current)
+			current, empty = indent, true
+		}
+		if !empty {
+			current += " "
+		}
+		current += cell
+		empty = false
+	}
+	p.line(current)
+}
+
+// labeledStrip prints a label followed by a strip of characters on an indented line,
+// wrapping the strip over as many lines as necessary to fit the width of the printer.
+func (p *statusPrinter) labeledStrip(label string, strip string) {
+	p.line("  " + label)
+	indent := "    "
+	lineLen := p.width - len(indent)
+	if lineLen < 1 {
+		lineLen = 1
+	}
+	for len(strip) > lineLen {
+		p.line(indent + strip[:lineLen])
+		strip = strip[lineLen:]
+	}
+	p.line(indent + strip)
+}
+
+// uint64Cells returns the decimal representations of the given numbers.
+func uint64Cells(numbers []uint64) []string {
+	cells := make([]string, len(numbers))
+	for i, n := range numbers {
+		cells[i] = fmt.Sprintf("%d", n)
+	}
+	return cells
+}
+
+// uint64Set returns the set of the given numbers.
+func uint64Set(numbers []uint64) map[uint64]struct{} {
+	set := make(map[uint64]struct{}, len(numbers))
+	for _, n := range numbers {
+		set[n] = struct{}{}
+	}
+	return set
+}
+
+// sortedKeys returns the keys of a map in increasing order.
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
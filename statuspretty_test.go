@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The pretty status test prints the final status of all nodes in a human-readable form
+// and checks that the output fits the requested width and covers the whole protocol state.
+var _ = Describe("Pretty status test", func() {
+
+	It("prints the status within the terminal width", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		finalStatuses := deployment.RunUntil(deploytest.AllOf(
+			deployment.AllProcessed(testConfig.NumFakeRequests),
+			deployment.AllInEpoch(1),
+			deployment.AllWatermarksMoved(),
+		))
+
+		for _, finalStatus := range finalStatuses {
+			Expect(finalStatus.StatusErr).NotTo(HaveOccurred())
+			status := finalStatus.Status.Protocol.GetIss()
+			Expect(status).NotTo(BeNil())
+
+			// The status describes all segments, buckets and clients, and the transition to the next epoch.
+			// Besides the segments of the current epoch, the segments of a preceding epoch might not have been
+			// garbage-collected yet (depending on when the node stopped).
+			Expect(status.Orderers).NotTo(BeEmpty())
+			Expect(len(status.Orderers) % testConfig.NumReplicas).To(BeZero())
+			for _, orderer := range status.Orderers {
+				Expect(len(orderer.Committed)).To(BeNumerically("<=", len(orderer.SeqNrs)))
+			}
+			Expect(status.Buckets).NotTo(BeEmpty())
+			Expect(status.Clients).NotTo(BeEmpty())
+			Expect(status.NextEpoch).NotTo(BeNil())
+			Expect(status.NextEpoch.Epoch).To(Equal(status.Epoch + 1))
+			Expect(status.NextEpoch.FirstSn).To(BeNumerically(">=", status.NextDeliveredSn))
+
+			for _, width := range []int{40, 120} {
+				pretty := mirbft.PrettyStatus(finalStatus.Status, width)
+				for _, section := range []string{"Segments", "Buckets", "Clients", "Buffered messages", "Next epoch", "Epoch history"} {
+					Expect(pretty).To(ContainSubstring(section))
+				}
+				for _, line := range strings.Split(strings.TrimSuffix(pretty, "\n"), "\n") {
+					Expect(len(line)).To(BeNumerically("<=", width))
+				}
+			}
+		}
+	})
+})
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+// StatusStream returns a channel to which the Node pushes snapshots of its status (see Status),
+// obtained every interval, enabling monitoring without repeatedly calling Status.
+// A snapshot is only pushed if it differs from the previously pushed one.
+// The channel always holds only the most recent snapshot: if the reader does not keep up,
+// an unread snapshot is replaced by a newer one, such that the Node's status is never delayed by the reader.
+// When the Node stops, its final status is pushed and the channel is closed.
+// The channel is also closed (without pushing the final status) when ctx is canceled.
+// The status is obtained by a separate goroutine, started by StatusStream.
+func (n *Node) StatusStream(ctx context.Context, interval time.Duration) <-chan *statuspb.NodeStatus {
+	statusC := make(chan *statuspb.NodeStatus, 1)
+
+	go func() {
+		defer close(statusC)
+
+		ticker := n.clock().NewTicker(interval)
+		defer ticker.Stop()
+
+		var last *statuspb.NodeStatus
+		for {
+			// Obtain the current status. After the Node stopped, this is the final status.
+			s, err := n.Status(ctx)
+			if err != nil && ctx.Err() != nil {
+				return
+			}
+
+			// Push the status, unless it is unchanged.
+			if s != nil && (last == nil || !proto.Equal(s, last)) {
+				pushLatestStatus(statusC, s)
+				last = s
+			}
+
+			// Stop after pushing the final status.
+			select {
+			case <-n.workErrNotifier.ExitStatusC():
+				return
+			default:
+			}
+
+			// Wait for the next interval.
+			select {
+			case <-ticker.C():
+			case <-n.workErrNotifier.ExitStatusC():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return statusC
+}
+
+// pushLatestStatus writes s to statusC (with a buffer of size 1), replacing the unread status in the buffer, if any.
+// It must only be called by the single goroutine writing to statusC.
+func pushLatestStatus(statusC chan *statuspb.NodeStatus, s *statuspb.NodeStatus) {
+	for {
+		select {
+		case statusC <- s:
+			return
+		default:
+		}
+
+		// Discard the stale status (unless the reader has just read it).
+		select {
+		case <-statusC:
+		default:
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+)
+
+// The status stream test streams the status of all nodes
+// and checks that each stream delivers the changing status and, finally, the final status of the protocol.
+var _ = Describe("Status stream test", func() {
+
+	It("streams the status until the final one", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Read the status stream of each node until the node stops (and the stream is closed).
+		statuses := make([][]*statuspb.NodeStatus, len(deployment.TestReplicas))
+		var wg sync.WaitGroup
+		for i, replica := range deployment.TestReplicas {
+			i := i
+			replica.OnNode = func(node *mirbft.Node) {
+				statusC := node.StatusStream(context.Background(), 10*time.Millisecond)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for s := range statusC {
+						statuses[i] = append(statuses[i], s)
+					}
+				}()
+			}
+		}
+
+		deployment.RunUntil(deployment.AllProcessed(testConfig.NumFakeRequests))
+		wg.Wait()
+
+		for i := range deployment.TestReplicas {
+			Expect(len(statuses[i])).To(BeNumerically(">", 1))
+			Expect(statuses[i][len(statuses[i])-1].Protocol).NotTo(BeNil())
+		}
+	})
+})
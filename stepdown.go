@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+)
+
+// StepDown prepares the Node for a planned shutdown (e.g. for maintenance) by making it stop leading.
+// Instead of leaving the other nodes waiting for the proposals of the segments the Node leads,
+// as would happen if the Node was simply stopped, the Node hands off the rest of its segments
+// by immediately proposing empty batches for their remaining sequence numbers.
+// Its pending requests are thus left to the leaders of the following epochs.
+// The other nodes report the Node to the leader selection policy as suspected at the end of the epoch
+// in which it handed off its segment, which allows the policy to stop selecting it as a leader
+// (see iss.LeaderSelectionPolicy). The Node keeps handing off every segment it leads until it is restarted.
+//
+// StepDown returns as soon as the request to step down has been passed to the protocol,
+// without waiting for the handoff to complete. To shut down without disrupting the epoch,
+// the Node should be kept running until the batches of its segments have been committed (see Node.Committed).
+// StepDown returns an error if ctx is canceled or if the Node halts before the request has been passed to the protocol.
+// StepDown is safe to be called concurrently.
+func (n *Node) StepDown(ctx context.Context) error {
+	select {
+	case n.externalInput <- (&events.EventList{}).PushBack(events.StepDown()):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-n.workErrNotifier.ExitC():
+		return n.workErrNotifier.Err()
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"fmt"
+
+	"github.com/hyperledger-labs/mirbft/pkg/events"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// Stepper drives the modules of a node synchronously, in the caller's goroutine, instead of running a Node.
+// Each call to Step dispatches a list of events to the modules the same way the Node does
+// and returns the resulting events, which the caller is expected to pass to a later call to Step.
+// Since the Stepper never processes anything on its own, the caller fully controls the order of processing,
+// e.g., to interleave the processing of multiple nodes in a simulation (see package testengine).
+// Given the same inputs in the same order, deterministic modules always produce the same outputs.
+// The Net module is only used for sending. Received messages must be injected by the caller,
+// passing the events obtained from ReceiveMessage to Step.
+// Linearizable reads (see Node.Read) are not supported and the corresponding events are dropped.
+type Stepper struct {
+
+	// The node whose modules and Processors process the events. It is never run.
+	node *Node
+}
+
+// NewStepper returns a new Stepper driving the modules of the node with ID id.
+// The parameters have the same meaning as with NewNode.
+// The Stepper must be started using Start before the first call to Step.
+func NewStepper(id t.NodeID, config *NodeConfig, m *modules.Modules) (*Stepper, error) {
+	node, err := NewNode(id, config, m)
+	if err != nil {
+		return nil, fmt.Errorf("could not create node for stepping: %w", err)
+	}
+	return &Stepper{node: node}, nil
+}
+
+// RestartStepper returns a new Stepper driving the modules of the node with ID id,
+// recovering the state the node persisted before stopping (e.g. before a crash).
+// The parameters have the same meaning as with RestartNode, which performs the same checks.
+func RestartStepper(id t.NodeID, config *NodeConfig, m *modules.Modules) (*Stepper, error) {
+	node, err := RestartNode(id, config, m)
+	if err != nil {
+		return nil, fmt.Errorf("could not restart node for stepping: %w", err)
+	}
+	return &Stepper{node: node}, nil
+}
+
+// Start starts the Processors and returns the initial events of the node:
+// the entries loaded from the WAL (if it is not empty, e.g. when restarting a node), followed by the Init event.
+func (s *Stepper) Start() (*events.EventList, error) {
+
+	// Load the WAL, unless already loaded on restart.
+	walEntries := s.node.recoveredWAL
+	s.node.recoveredWAL = nil
+	if walEntries == nil {
+		var err error
+		if walEntries, err = loadWAL(s.node.modules.WAL); err != nil {
+			return nil, err
+		}
+		if err := checkRecoveredCheckpoints(walEntries); err != nil {
+			return nil, fmt.Errorf("inconsistent persisted state: %w", err)
+		}
+	}
+
+	if err := s.node.processors.startAll(); err != nil {
+		return nil, err
+	}
+
+	initial := &events.EventList{}
+	for _, entry := range walEntries {
+		initial.PushBack(events.WALEntry(entry.Event, t.WALRetIndex(entry.RetentionIndex)))
+	}
+	return initial.PushBack(events.Init()), nil
+}
+
+// Stop stops the Processors. The Stepper must not be used afterwards.
+func (s *Stepper) Stop() {
+	s.node.processors.stopAll()
+}
+
+// Step dispatches eventsIn to the modules and processes them, one module after the other in a fixed order.
+// The WAL is synced right after appending, such that the follow-up events of persisted entries can be released.
+// Step returns the events produced by the modules and, separately, the notifications contained in eventsIn
+// (which the Node would publish to the subscribers of its events).
+// If eventsIn contains a poisoned batch, Step returns a *PoisonedBatchError, just like Node.Run would.
+func (s *Stepper) Step(eventsIn *events.EventList) (eventsOut *events.EventList, notifications *events.EventList, err error) {
+	wi := newWorkItems()
+	if err := wi.AddEvents(eventsIn); err != nil {
+		return nil, nil, err
+	}
+
+	eventsOut = &events.EventList{}
+
+	if wi.WAL().Len() > 0 {
+		s.node.interceptEvents(wi.WAL())
+		walOut, err := appendWALEvents(s.node.modules.WAL, wi.WAL())
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not process WAL events: %w", err)
+		}
+		if err := s.node.modules.WAL.Sync(); err != nil {
+			return nil, nil, fmt.Errorf("could not sync WAL: %w", err)
+		}
+		eventsOut.PushBackList(walOut)
+	}
+
+	for _, list := range []struct {
+		eventsIn  *events.EventList
+		processor Processor
+	}{
+		{wi.Client(), s.node.processors.Client},
+		{wi.Hash(), s.node.processors.Hash},
+		{wi.Crypto(), s.node.processors.Crypto},
+		{wi.Net(), s.node.processors.Net},
+		{wi.App(), s.node.processors.App},
+		{wi.ReqStore(), s.node.processors.ReqStore},
+		{wi.Protocol(), s.node.processors.Protocol},
+	} {
+		if list.eventsIn.Len() == 0 {
+			continue
+		}
+		s.node.interceptEvents(list.eventsIn)
+		moduleOut, err := list.processor.Process(list.eventsIn)
+		if err != nil {
+			return nil, nil, err
+		}
+		eventsOut.PushBackList(moduleOut)
+	}
+
+	// An interceptor failure is reported by the Node as the reason it stopped.
+	if err := s.node.workErrNotifier.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return eventsOut, wi.Notifications(), nil
+}
+
+// ReceiveMessage turns a message received by the node from node from into the events to be passed to Step
+// (one MessageReceived event per message, if msg is a MessageBundle).
+// Like Node.Step, ReceiveMessage returns an *InvalidMessageError if the message must be dropped.
+func (s *Stepper) ReceiveMessage(from t.NodeID, msg *messagepb.Message) (*events.EventList, error) {
+	if err := s.node.validateMessage(from, msg); err != nil {
+		return nil, err
+	}
+	return s.node.messageReceivedEvents(from, msg), nil
+}
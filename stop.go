@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrStopping is returned by SubmitRequest after a graceful stop of the Node has been initiated (see Stop).
+var ErrStopping = fmt.Errorf("node is stopping")
+
+// Stop gracefully stops the running Node and only returns when it stopped (i.e., when Run returned).
+// The Node stops accepting new input (SubmitRequest returns ErrStopping), drains all the work already in flight
+// (as with NodeConfig.DrainOnStop, including NodeConfig.DrainTimeout, if set),
+// and syncs the WAL and the RequestStore before Run returns ErrStopped.
+// Thus, no request whose submission has been acknowledged is lost when the Node stops in the middle of a batch.
+// If ctx is canceled before the Node stopped, the in-flight work is abandoned and the Node is stopped forcibly
+// (though never in a way that would violate safety). In such a case Stop returns the context's error.
+// Otherwise, Stop returns nil if the Node stopped gracefully, or the error Run returned.
+// Stop can be called multiple times and concurrently. If Run has not been called, Stop only returns when ctx ends.
+func (n *Node) Stop(ctx context.Context) error {
+	n.stopOnce.Do(func() {
+		close(n.stopC)
+	})
+
+	select {
+	case <-n.runDoneC:
+		if n.runErr == ErrStopped {
+			return nil
+		}
+		return n.runErr
+	case <-ctx.Done():
+		// Force the Node to stop and wait until it stopped (if it is running at all).
+		n.workErrNotifier.Fail(ErrStopped)
+		if atomic.LoadInt32(&n.running) != 0 {
+			<-n.runDoneC
+		}
+		return fmt.Errorf("forced stop: %w", ctx.Err())
+	}
+}
+
+// syncDurableState syncs the WAL and the RequestStore, such that all their contents are persisted.
+// It must only be called after the node stopped, when no worker is accessing the WAL or the RequestStore any more.
+func (n *Node) syncDurableState() error {
+	if err := n.modules.WAL.Sync(); err != nil {
+		return fmt.Errorf("could not sync WAL: %w", err)
+	}
+	if err := n.modules.RequestStore.Sync(); err != nil {
+		return fmt.Errorf("could not sync request store: %w", err)
+	}
+	return nil
+}
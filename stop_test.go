@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+)
+
+// The drain test stops nodes configured to drain their in-flight work on stopping
+// and checks that they stop normally.
+var _ = Describe("Drain on stop test", func() {
+
+	It("drains in-flight work and stops", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.Config.DrainOnStop = true
+			replica.Config.DrainTimeout = 5 * time.Second
+		}
+
+		stopC := make(chan struct{})
+		statusC := make(chan []deploytest.NodeStatus, 1)
+		go func() {
+			defer GinkgoRecover()
+			statusC <- deployment.Run(deploytest.TestTickInterval, stopC)
+		}()
+		Eventually(deployment.AllProcessed(testConfig.NumFakeRequests), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		start := time.Now()
+		close(stopC)
+		finalStatuses := <-statusC
+
+		// All nodes must have stopped normally, before the drain timeout.
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		for _, status := range finalStatuses {
+			Expect(status.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+		for _, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
+
+// The graceful stop test stops all nodes using Node.Stop while they are processing requests
+// and checks that the nodes stop normally after processing all the submitted requests.
+var _ = Describe("Graceful stop test", func() {
+
+	It("drains in-flight work, persists the state and stops", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// Stop all nodes concurrently and only then terminate the rest of the deployment.
+		nodes := make([]*mirbft.Node, len(deployment.TestReplicas))
+		stopErrs := make([]error, len(deployment.TestReplicas))
+		finalStatuses := deployment.RunUntilThen(deployment.AllProcessed(testConfig.NumFakeRequests), func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var wg sync.WaitGroup
+			for i, replica := range deployment.TestReplicas {
+				i, node := i, replica.Node()
+				nodes[i] = node
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					stopErrs[i] = node.Stop(ctx)
+				}()
+			}
+			wg.Wait()
+		})
+
+		for i, status := range finalStatuses {
+			Expect(stopErrs[i]).NotTo(HaveOccurred())
+			Expect(status.ExitErr).To(Equal(mirbft.ErrStopped))
+		}
+		for i, replica := range deployment.TestReplicas {
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+			Expect(nodes[i].SubmitRequest(context.Background(), 0, 0, []byte{}, []byte{})).To(Equal(mirbft.ErrStopping))
+		}
+	})
+})
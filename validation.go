@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// InvalidMessageError is returned by Node.Step if the message is invalid and has been rejected.
+// Err describes the problem and wraps one of modules.ErrUnknownNode, modules.ErrEpochOutOfRange,
+// or modules.ErrMalformedMessage, which can be checked using errors.Is.
+// Invalid messages are never the Node's fault, so the caller (e.g. a transport) can simply log and drop them.
+type InvalidMessageError struct {
+	From t.NodeID
+	Err  error
+}
+
+// Error returns a description of the rejected message.
+func (e *InvalidMessageError) Error() string {
+	return fmt.Sprintf("invalid message from node %d: %v", e.From, e.Err)
+}
+
+// Unwrap returns the reason for rejecting the message.
+func (e *InvalidMessageError) Unwrap() error {
+	return e.Err
+}
+
+// validateMessage returns an InvalidMessageError if msg, received from node from, must not be processed by the Node,
+// and nil otherwise.
+// The Node itself checks the structure of the messages it handles itself (e.g. forwarded requests)
+// and their senders against the current members and learners of the protocol (see validateSender).
+// Messages destined to the protocol are validated by the Protocol module, if it implements modules.MessageValidator.
+// validateMessage is safe to be called concurrently.
+func (n *Node) validateMessage(from t.NodeID, msg *messagepb.Message) *InvalidMessageError {
+	if err := n.validateMessageContent(from, msg); err != nil {
+		return &InvalidMessageError{From: from, Err: err}
+	}
+	return nil
+}
+
+// validateMessageContent validates a single message (recursively, in case of a MessageBundle)
+// and returns the reason for rejecting it, if any.
+func (n *Node) validateMessageContent(from t.NodeID, msg *messagepb.Message) error {
+	if msg == nil {
+		return fmt.Errorf("%w: nil message", modules.ErrMalformedMessage)
+	}
+
+	switch m := msg.Type.(type) {
+	case nil:
+		return fmt.Errorf("%w: message without content", modules.ErrMalformedMessage)
+	case *messagepb.Message_Bundle:
+		if m.Bundle == nil {
+			return fmt.Errorf("%w: empty message bundle", modules.ErrMalformedMessage)
+		}
+		for _, bundled := range m.Bundle.Msgs {
+			if err := n.validateMessageContent(from, bundled); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *messagepb.Message_ForwardedRequest:
+		if m.ForwardedRequest == nil {
+			return fmt.Errorf("%w: empty forwarded request", modules.ErrMalformedMessage)
+		}
+		return n.validateSender(from, false)
+	case *messagepb.Message_ReadIndexRequest:
+		if m.ReadIndexRequest == nil {
+			return fmt.Errorf("%w: empty read index request", modules.ErrMalformedMessage)
+		}
+		// Learners read like members (but only the members answer).
+		return n.validateSender(from, true)
+	case *messagepb.Message_ReadIndexResponse:
+		if m.ReadIndexResponse == nil {
+			return fmt.Errorf("%w: empty read index response", modules.ErrMalformedMessage)
+		}
+		return n.validateSender(from, false)
+	case *messagepb.Message_ReliableData, *messagepb.Message_ReliableAck, *messagepb.Message_Compressed,
+		*messagepb.Message_CompressionHello, *messagepb.Message_Authenticated:
+		// Messages of the transport layers must be unwrapped by the Net module and never reach the Node.
+		return fmt.Errorf("%w: unexpected transport-level message: %T", modules.ErrMalformedMessage, m)
+	default:
+		// All other messages are destined to the protocol.
+		if validator, ok := n.modules.Protocol.(modules.MessageValidator); ok {
+			return validator.ValidateMessage(from, msg)
+		}
+		return nil
+	}
+}
+
+// validateSender returns an error wrapping modules.ErrUnknownNode if node from is neither a member of the protocol
+// nor, if learners is set, a learner (see modules.MembershipProvider).
+// As the membership is tracked by the protocol, it changes with the protocol's configuration.
+// If the protocol does not implement modules.MembershipProvider, all senders are accepted.
+func (n *Node) validateSender(from t.NodeID, learners bool) error {
+	provider, ok := n.modules.Protocol.(modules.MembershipProvider)
+	if !ok || provider.IsMember(from) || (learners && provider.IsLearner(from)) {
+		return nil
+	}
+	return fmt.Errorf("%w: %d", modules.ErrUnknownNode, from)
+}
+
+// recordInvalidMessage logs a dropped invalid message and registers it as an oddity of the sender.
+// Messages of past epochs are only logged, as they are commonly sent by correct nodes that are slightly behind.
+// It must only be called from the process() goroutine.
+func (n *Node) recordInvalidMessage(err *InvalidMessageError) {
+	n.logger.Log(logging.LevelWarn, "Dropping invalid message.", "err", err)
+	if !errors.Is(err, modules.ErrEpochOutOfRange) {
+		n.recordOddity(err.From, OddityInvalidMessage, "%v", err.Err)
+	}
+}
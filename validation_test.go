@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/iss"
+	"github.com/hyperledger-labs/mirbft/pkg/modules"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspbftpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
+)
+
+// The message validation test runs a deployment and, while it is running, injects invalid messages into each node.
+// The messages must be rejected with the appropriate errors and the nodes must keep working.
+var _ = Describe("Message validation test", func() {
+
+	It("rejects invalid messages without crashing", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     4,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+
+		// A well-formed preprepare message for the given epoch.
+		preprepare := func(epoch uint64) *messagepb.Message {
+			return &messagepb.Message{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{
+				Type: &isspb.ISSMessage_Sb{Sb: &isspb.SBMessage{
+					Epoch: epoch,
+					Msg: &isspb.SBInstanceMessage{Type: &isspb.SBInstanceMessage_PbftPreprepare{
+						PbftPreprepare: &isspbftpb.Preprepare{Sn: 0, Batch: &requestpb.Batch{}},
+					}},
+				}},
+			}}}
+		}
+
+		// Invalid messages, each from a node in the membership, unless specified otherwise,
+		// together with the reasons for rejecting them.
+		invalidMessages := []struct {
+			from   t.NodeID
+			msg    *messagepb.Message
+			reason error
+		}{
+			{100, preprepare(0), modules.ErrUnknownNode},
+			{100, &messagepb.Message{Type: &messagepb.Message_ForwardedRequest{ForwardedRequest: &requestpb.Request{}}},
+				modules.ErrUnknownNode},
+			{100, &messagepb.Message{Type: &messagepb.Message_ReadIndexRequest{ReadIndexRequest: &messagepb.ReadIndexRequest{}}},
+				modules.ErrUnknownNode},
+			{100, &messagepb.Message{Type: &messagepb.Message_ReadIndexResponse{ReadIndexResponse: &messagepb.ReadIndexResponse{}}},
+				modules.ErrUnknownNode},
+			{1, &messagepb.Message{}, modules.ErrMalformedMessage},
+			{1, &messagepb.Message{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{}}}, modules.ErrMalformedMessage},
+			{1, &messagepb.Message{Type: &messagepb.Message_Iss{Iss: &isspb.ISSMessage{
+				Type: &isspb.ISSMessage_Sb{Sb: &isspb.SBMessage{}},
+			}}}, modules.ErrMalformedMessage},
+			{1, &messagepb.Message{Type: &messagepb.Message_Bundle{Bundle: &messagepb.MessageBundle{
+				Msgs: []*messagepb.Message{iss.HeartbeatMessage(), {}},
+			}}}, modules.ErrMalformedMessage},
+			{1, &messagepb.Message{Type: &messagepb.Message_ReliableAck{ReliableAck: &messagepb.ReliableAck{}}},
+				modules.ErrMalformedMessage},
+			{1, preprepare(0), modules.ErrEpochOutOfRange},
+		}
+
+		// Inject the invalid messages after the nodes progressed beyond the first epoch
+		// and wait until all nodes commit another batch.
+		stepErrs := make([][]error, len(deployment.TestReplicas))
+		epochs := make([]t.EpochNr, len(deployment.TestReplicas))
+		conditions := deploytest.AllOf(deployment.AllProcessed(testConfig.NumFakeRequests), deployment.AllInEpoch(1))
+		finalStatuses := deployment.RunUntilThen(conditions, func() {
+			lastSn := t.SeqNr(0)
+			for i, replica := range deployment.TestReplicas {
+				node := replica.Node()
+				epochs[i] = node.EpochInfo().Epoch
+				if sn, _ := node.LastCommitted(); sn > lastSn {
+					lastSn = sn
+				}
+				for _, invalid := range invalidMessages {
+					stepErrs[i] = append(stepErrs[i], node.Step(context.Background(), invalid.from, invalid.msg))
+				}
+			}
+			Eventually(deploytest.AllCommitted(deployment.TestReplicas, lastSn+1), deploytest.TestTimeout, deploytest.PollInterval).Should(BeTrue())
+		})
+
+		for i, replica := range deployment.TestReplicas {
+			Expect(epochs[i]).To(BeNumerically(">", 0))
+			for j, invalid := range invalidMessages {
+				var invalidErr *mirbft.InvalidMessageError
+				Expect(errors.As(stepErrs[i][j], &invalidErr)).To(BeTrue(), "message %d: %v", j, stepErrs[i][j])
+				Expect(invalidErr.From).To(Equal(invalid.from))
+				Expect(errors.Is(stepErrs[i][j], invalid.reason)).To(BeTrue(), "message %d: %v", j, stepErrs[i][j])
+			}
+			Expect(finalStatuses[i].ExitErr).To(Equal(mirbft.ErrStopped))
+			Expect(int(replica.App.RequestsProcessed)).To(Equal(testConfig.NumFakeRequests))
+		}
+	})
+})
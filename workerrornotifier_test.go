@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/deploytest"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+)
+
+// The module failure test makes the WAL of all replicas panic and checks that the nodes halt with an error
+// instead of crashing the whole process.
+var _ = Describe("Module failure test", func() {
+
+	It("halts nodes whose WAL panics", func() {
+		testConfig := &deploytest.TestConfig{
+			NumReplicas:     1,
+			Transport:       "fake",
+			NumFakeRequests: 10,
+			Directory:       "",
+			VolatileWAL:     true,
+		}
+
+		deployment := deploytest.NewTestDeployment(testConfig)
+		for _, replica := range deployment.TestReplicas {
+			replica.WAL.(*simplewal.VolatileWAL).FailureHook = func(method string) error {
+				if method == "Sync" {
+					panic("simulated WAL failure")
+				}
+				return nil
+			}
+		}
+
+		// The nodes are expected to halt on their own.
+		finalStatuses := deployment.RunUntil(deployment.AllHalted())
+		Expect(finalStatuses).NotTo(BeNil())
+		for _, status := range finalStatuses {
+			Expect(status.ExitErr).To(HaveOccurred())
+			Expect(status.ExitErr.Error()).To(ContainSubstring("simulated WAL failure"))
+		}
+	})
+})
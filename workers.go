@@ -13,10 +13,17 @@ import (
 	"github.com/hyperledger-labs/mirbft/pkg/events"
 	"github.com/hyperledger-labs/mirbft/pkg/modules"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/isspb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstoremetrics"
 	t "github.com/hyperledger-labs/mirbft/pkg/types"
 	"github.com/pkg/errors"
 	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Input and output channels for the modules within the Node.
@@ -34,6 +41,10 @@ type workChans struct {
 	app      chan *events.EventList
 	reqStore chan *events.EventList
 
+	// Output of the WAL append stage and input of the WAL sync stage (see doWALWork and doWALSyncWork).
+	// Each list contains the follow-up events of WAL entries that have been appended, but not yet synced.
+	walSync chan *events.EventList
+
 	// All modules write their output events in a common channel, from where the node processor reads and redistributes
 	// the events to their respective workItems buffers.
 	// External events are also funneled through this channel towards the workItems buffers.
@@ -51,6 +62,7 @@ func newWorkChans() workChans {
 		net:      make(chan *events.EventList),
 		app:      make(chan *events.EventList),
 		reqStore: make(chan *events.EventList),
+		walSync:  make(chan *events.EventList),
 
 		workItemInput: make(chan *events.EventList),
 	}
@@ -66,19 +78,58 @@ type workFunc func(exitC <-chan struct{}) error
 
 // Calls the passed work function repeatedly in an infinite loop until the work function returns an non-nil error.
 // doUntilErr then sets the error in the Node's workErrNotifier and returns.
+// A panic in the work function (e.g. in a module implementation) is treated as an error,
+// such that it halts the Node instead of crashing the whole process.
 func (n *Node) doUntilErr(work workFunc) {
 	for {
-		err := work(n.workErrNotifier.ExitC())
+		err := safeDoWork(work, n.workErrNotifier.ExitC())
 		if err != nil {
 			n.workErrNotifier.Fail(err)
 			return
 		}
+
+		// A work function returning without an error has fully processed one list of events.
+		n.workFinished()
 	}
 }
 
-// Reads a single list of WAL input events from the corresponding work channel and processes its contents.
-// If any results are generated for further processing,
-// writes a list of those results to the corresponding work channel.
+// workFinished accounts for a list of events dispatched to a worker having been fully processed
+// (i.e., including writing the output of the processing).
+// If no more work is in flight, it wakes up the process() goroutine, which might be waiting for the work to drain.
+func (n *Node) workFinished() {
+	if atomic.AddInt64(&n.inFlight, -1) == 0 {
+		select {
+		case n.idleC <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// safeDoWork calls the passed work function, converting a potential panic to an error.
+func safeDoWork(work workFunc, exitC <-chan struct{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rErr, ok := r.(error); ok {
+				err = fmt.Errorf("panic in worker: %w\nStack trace:\n%s", rErr, string(debug.Stack()))
+			} else {
+				err = fmt.Errorf("panic in worker: %v\nStack trace:\n%s", r, string(debug.Stack()))
+			}
+		}
+	}()
+
+	return work(exitC)
+}
+
+// The WAL is processed in two pipelined stages, doWALWork (append stage) and doWALSyncWork (sync stage),
+// each executed by a separate thread.
+// This way, the entries of the next list of WAL events can already be appended while the previous ones are being synced
+// (and, in turn, the other modules, e.g. the Net module, do not idle for a whole append-sync cycle).
+// The follow-up events of WAL entries are only released by the sync stage, after the entries have been synced,
+// and the sync stage releases them in the order in which the entries have been appended.
+
+// Reads a single list of WAL input events from the corresponding work channel and appends their contents to the WAL.
+// The follow-up events are passed to the WAL sync stage (doWALSyncWork),
+// which releases them for further processing after syncing the WAL.
 // If exitC is closed, returns ErrStopped.
 func (n *Node) doWALWork(exitC <-chan struct{}) error {
 	var eventsIn *events.EventList
@@ -91,10 +142,62 @@ func (n *Node) doWALWork(exitC <-chan struct{}) error {
 	}
 
 	// Process events.
-	eventsOut, err := processWALEvents(n.modules.WAL, eventsIn)
+	start := time.Now()
+	eventsOut, err := appendWALEvents(n.modules.WAL, eventsIn)
 	if err != nil {
 		return errors.WithMessage(err, "could not process WAL events")
 	}
+	n.metrics.OnPersist(time.Since(start), eventsIn.Len())
+	n.persisted(PersistWALAppend, start, eventsOut)
+
+	// Pass output to the sync stage.
+	// This happens even if no output was generated, since the appended entries still need to be synced.
+	// The list passed to the sync stage counts as a separate piece of in-flight work.
+	atomic.AddInt64(&n.inFlight, 1)
+	select {
+	case n.workChans.walSync <- eventsOut:
+	case <-exitC:
+		return ErrStopped
+	}
+
+	return nil
+}
+
+// Reads a list of follow-up events of appended WAL entries from the WAL append stage (doWALWork), syncs the WAL
+// and writes the follow-up events to the corresponding work channel.
+// All the lists the append stage produced in the meantime are synced together (group commit).
+// If exitC is closed, returns ErrStopped.
+func (n *Node) doWALSyncWork(exitC <-chan struct{}) error {
+	var eventsIn *events.EventList
+
+	// Read input.
+	select {
+	case eventsIn = <-n.workChans.walSync:
+	case <-exitC:
+		return ErrStopped
+	}
+
+	// Collect all other input that is already available, without blocking.
+	// Only one list is accounted for as processed when returning, so the additional ones are accounted for here.
+	// (Since the first list is still in flight, this never wakes up the process() goroutine prematurely.)
+	eventsOut := (&events.EventList{}).PushBackList(eventsIn)
+	for collecting := true; collecting; {
+		select {
+		case eventsIn = <-n.workChans.walSync:
+			eventsOut.PushBackList(eventsIn)
+			n.workFinished()
+		default:
+			collecting = false
+		}
+	}
+
+	// Sync the WAL. Only after this, the follow-up events of the appended entries can be processed.
+	start := time.Now()
+	if err := n.modules.WAL.Sync(); err != nil {
+		return errors.WithMessage(err, "failed to sync WAL")
+	}
+	n.metrics.OnSync(time.Since(start))
+	n.persisted(PersistWALSync, start, eventsOut)
 
 	// Return if no output was generated.
 	if eventsOut.Len() == 0 {
@@ -126,7 +229,7 @@ func (n *Node) doClientWork(exitC <-chan struct{}) error {
 	}
 
 	// Process events.
-	outputEvents, err := processClientEvents(n.modules.ClientTracker, inputEvents)
+	outputEvents, err := n.processors.Client.Process(inputEvents)
 	if err != nil {
 		return errors.WithMessage(err, "could not process client events")
 	}
@@ -159,7 +262,7 @@ func (n *Node) doHashWork(exitC <-chan struct{}) error {
 	}
 
 	// Process events.
-	eventsOut, err := processHashEvents(n.modules.Hasher, eventsIn)
+	eventsOut, err := n.processors.Hash.Process(eventsIn)
 	if err != nil {
 		return errors.WithMessage(err, "could not process hash events")
 	}
@@ -189,9 +292,9 @@ func (n *Node) doCryptoWork(exitC <-chan struct{}) error {
 	}
 
 	// Process events.
-	eventsOut, err := processCryptoEvents(n.modules.Crypto, eventsIn)
+	eventsOut, err := n.processors.Crypto.Process(eventsIn)
 	if err != nil {
-		return errors.WithMessage(err, "could not process hash events")
+		return errors.WithMessage(err, "could not process crypto events")
 	}
 
 	// Write output.
@@ -219,8 +322,20 @@ func (n *Node) doSendingWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
+	// If coalescing messages, wait for more events, such that the messages are sent together.
+	// The additional lists of events are fully processed together with the first one.
+	numLists, err := n.waitForMoreNetEvents(eventsIn, exitC)
+	defer func() {
+		for i := 0; i < numLists; i++ {
+			n.workFinished()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
 	// Process events.
-	eventsOut, err := processSendEvents(n.ID, n.modules.Net, eventsIn)
+	eventsOut, err := n.processors.Net.Process(eventsIn)
 	if err != nil {
 		return errors.WithMessage(err, "could not process net events")
 	}
@@ -255,7 +370,7 @@ func (n *Node) doAppWork(exitC <-chan struct{}) error {
 	}
 
 	// Process events.
-	eventsOut, err := processAppEvents(n.modules.App, eventsIn)
+	eventsOut, err := n.processors.App.Process(eventsIn)
 	if err != nil {
 		return errors.WithMessage(err, "could not process app events")
 	}
@@ -290,7 +405,7 @@ func (n *Node) doReqStoreWork(exitC <-chan struct{}) error {
 	}
 
 	// Process events.
-	eventsOut, err := processReqStoreEvents(n.modules.RequestStore, eventsIn)
+	eventsOut, err := n.processors.ReqStore.Process(eventsIn)
 	if err != nil {
 		return errors.WithMessage(err, "could not process reqstore events")
 	}
@@ -319,7 +434,12 @@ func (n *Node) doProtocolWork(exitC <-chan struct{}) (err error) {
 	defer func() {
 		if err != nil {
 			s, err := n.modules.Protocol.Status()
-			n.workErrNotifier.SetExitStatus(&statuspb.NodeStatus{Protocol: s}, err)
+			n.workErrNotifier.SetExitStatus(&statuspb.NodeStatus{
+				Protocol:         s,
+				Oddities:         n.oddities.status(),
+				PeerTraffic:      n.peerTraffic.status(),
+				PersistLatencies: n.persistLatencies.status(),
+			}, err)
 			// TODO: Clean up status-related code.
 		}
 	}()
@@ -327,14 +447,20 @@ func (n *Node) doProtocolWork(exitC <-chan struct{}) (err error) {
 	var eventsIn *events.EventList
 
 	// Read input.
-	select {
-	case eventsIn = <-n.workChans.protocol:
-	case <-exitC:
-		return ErrStopped
+	// While waiting for input, also serve requests for the state of the protocol (see Dump).
+	// (Returning after serving a request would account for a list of events as processed.)
+	for eventsIn == nil {
+		select {
+		case eventsIn = <-n.workChans.protocol:
+		case resultC := <-n.protocolDumps:
+			n.serveProtocolDump(resultC)
+		case <-exitC:
+			return ErrStopped
+		}
 	}
 
 	// Process events.
-	eventsOut, err := processProtocolEvents(n.modules.Protocol, eventsIn)
+	eventsOut, err := n.processors.Protocol.Process(eventsIn)
 	if err != nil {
 		return err
 	}
@@ -356,7 +482,9 @@ func (n *Node) doProtocolWork(exitC <-chan struct{}) (err error) {
 
 // TODO: Document the functions below.
 
-func processWALEvents(wal modules.WAL, eventsIn *events.EventList) (*events.EventList, error) {
+// appendWALEvents appends the WAL events to the WAL and returns their follow-up events.
+// It does not sync the WAL. The follow-up events must not be processed before the WAL is synced.
+func appendWALEvents(wal modules.WAL, eventsIn *events.EventList) (*events.EventList, error) {
 	eventsOut := &events.EventList{}
 	iter := eventsIn.Iterator()
 
@@ -372,6 +500,11 @@ func processWALEvents(wal modules.WAL, eventsIn *events.EventList) (*events.Even
 				return nil, fmt.Errorf("could not persist event (retention index %d) to WAL: %w",
 					e.WalAppend.RetentionIndex, err)
 			}
+		case *eventpb.Event_WalTruncate:
+			if err := wal.Truncate(t.WALRetIndex(e.WalTruncate.RetentionIndex)); err != nil {
+				return nil, fmt.Errorf("could not truncate WAL (retention index %d): %w",
+					e.WalTruncate.RetentionIndex, err)
+			}
 		case *eventpb.Event_PersistDummyBatch:
 			if err := wal.Append(event, 0); err != nil {
 				return nil, fmt.Errorf("could not persist dummy batch: %w", err)
@@ -394,11 +527,6 @@ func processWALEvents(wal modules.WAL, eventsIn *events.EventList) (*events.Even
 		}
 	}
 
-	// Then we sync the WAL
-	if err := wal.Sync(); err != nil {
-		return nil, errors.WithMessage(err, "failed to sync WAL")
-	}
-
 	return eventsOut, nil
 }
 
@@ -421,8 +549,18 @@ func processClientEvents(c modules.ClientTracker, eventsIn *events.EventList) (*
 	return eventsOut, nil
 }
 
-func processHashEvents(hasher modules.Hasher, eventsIn *events.EventList) (*events.EventList, error) {
+// processHashEvents computes the digests requested by the hash events using hash.Hash objects from the given pool.
+// The digests of all events in the list are stored in a single buffer allocated for the whole list.
+func processHashEvents(hashers *hasherPool, eventsIn *events.EventList) (*events.EventList, error) {
 	eventsOut := &events.EventList{}
+
+	// Take a single hash.Hash from the pool and reuse it for all events in the list.
+	h := hashers.get()
+	defer hashers.put(h)
+
+	// Allocate a buffer for all the digests at once.
+	digests := make([]byte, 0, eventsIn.Len()*h.Size())
+
 	iter := eventsIn.Iterator()
 	for event := iter.Next(); event != nil; event = iter.Next() {
 
@@ -433,11 +571,16 @@ func processHashEvents(hasher modules.Hasher, eventsIn *events.EventList) (*even
 		case *eventpb.Event_HashRequest:
 			// HashRequest is the only event understood by the hasher module.
 			// Hash all the data and create a hashResult event.
-			h := hasher.New()
+			h.Reset()
 			for _, data := range e.HashRequest.Data {
 				h.Write(data)
 			}
-			eventsOut.PushBack(events.HashResult(h.Sum(nil), e.HashRequest.Origin))
+
+			// Append the digest to the buffer and limit the capacity of the resulting slice,
+			// such that appending to it by the receiver of the digest cannot overwrite the subsequent digests.
+			start := len(digests)
+			digests = h.Sum(digests)
+			eventsOut.PushBack(events.HashResult(digests[start:len(digests):len(digests)], e.HashRequest.Origin))
 		default:
 			// Complain about all other incoming event types.
 			return nil, errors.Errorf("unexpected type of Hash event: %T", event.Type)
@@ -447,46 +590,172 @@ func processHashEvents(hasher modules.Hasher, eventsIn *events.EventList) (*even
 	return eventsOut, nil
 }
 
-func processCryptoEvents(crypto modules.Crypto, eventsIn *events.EventList) (*events.EventList, error) {
+// processCryptoEvents processes a list of crypto events.
+// The signatures are verified using up to numVerifyWorkers concurrent goroutines.
+// Client key updates are applied between the verifications, such that each signature is verified
+// with the keys registered by the updates that precede it in the list.
+// The output events are always produced in the order of the corresponding input events.
+func processCryptoEvents(
+	crypto modules.Crypto,
+	numVerifyWorkers int,
+	eventsIn *events.EventList,
+) (*events.EventList, error) {
 	eventsOut := &events.EventList{}
+
+	// Collect runs of consecutive signature verification events, verifying each run when an update interrupts it.
+	verifications := make([]*eventpb.Event, 0, eventsIn.Len())
 	iter := eventsIn.Iterator()
 	for event := iter.Next(); event != nil; event = iter.Next() {
-
-		// Remove the follow-up events from event and add them directly to the output.
-		eventsOut.PushBackList(events.Strip(event))
-
 		switch e := event.Type.(type) {
 		case *eventpb.Event_VerifyRequestSig:
-			// Verify client request signature.
-			// The signature is only computed (and verified) over the digest of a request.
-			// The other fields can safely be ignored.
-
-			// Convenience variable
-			reqRef := e.VerifyRequestSig.RequestRef
-
-			// Verify signature.
-			err := crypto.VerifyClientSig(
-				[][]byte{reqRef.Digest},
-				e.VerifyRequestSig.Signature,
-				t.ClientID(reqRef.ClientId))
-
-			// Create result event, depending on verification outcome.
-			if err == nil {
-				eventsOut.PushBack(events.RequestSigVerified(reqRef, true, ""))
-			} else {
-				eventsOut.PushBack(events.RequestSigVerified(reqRef, false, err.Error()))
+			verifications = append(verifications, event)
+		case *eventpb.Event_UpdateClientKeys:
+			eventsOut.PushBackList(verifyRequestSigs(crypto, numVerifyWorkers, verifications))
+			verifications = verifications[:0]
+			updateClientKeys(crypto, e.UpdateClientKeys.ClientKeys)
+			eventsOut.PushBackList(events.Strip(event))
+		case *eventpb.Event_UpdateNodeKeys:
+			updateNodeKeys(crypto, e.UpdateNodeKeys.NodeKeys)
+			eventsOut.PushBackList(events.Strip(event))
+		case *eventpb.Event_RetireNodeKeys:
+			if rotator, ok := crypto.(modules.NodeKeyRotator); ok {
+				for _, nodeID := range t.NodeIDSlice(e.RetireNodeKeys.NodeIds) {
+					rotator.RetireNodeKey(nodeID)
+				}
 			}
+			eventsOut.PushBackList(events.Strip(event))
 		default:
 			// Complain about all other incoming event types.
 			return nil, errors.Errorf("unexpected type of Crypto event: %T", event.Type)
 		}
 	}
 
-	return eventsOut, nil
+	return eventsOut.PushBackList(verifyRequestSigs(crypto, numVerifyWorkers, verifications)), nil
 }
 
-func processSendEvents(selfID t.NodeID, net modules.Net, eventsIn *events.EventList) (*events.EventList, error) {
+// verifyRequestSigs verifies the client signatures requested by the given VerifyRequestSig events,
+// using up to numVerifyWorkers concurrent goroutines.
+// The output events are produced in the order of the corresponding input events.
+func verifyRequestSigs(crypto modules.Crypto, numVerifyWorkers int, verifyEvents []*eventpb.Event) *events.EventList {
+
+	// Verify the signatures, potentially in parallel.
+	results := make([]error, len(verifyEvents))
+	verifyAll(numVerifyWorkers, len(verifyEvents), func(i int) {
+		// Verify client request signature.
+		// The signature is only computed (and verified) over the digest of a request.
+		// The other fields can safely be ignored.
+		verification := verifyEvents[i].Type.(*eventpb.Event_VerifyRequestSig).VerifyRequestSig
+		results[i] = crypto.VerifyClientSig(
+			[][]byte{verification.RequestRef.Digest},
+			verification.Signature,
+			t.ClientID(verification.RequestRef.ClientId))
+	})
+
+	// Create the output in the order of the input events.
 	eventsOut := &events.EventList{}
+	for i, event := range verifyEvents {
+
+		// Remove the follow-up events from event and add them directly to the output.
+		eventsOut.PushBackList(events.Strip(event))
+
+		// Create result event, depending on verification outcome.
+		reqRef := event.Type.(*eventpb.Event_VerifyRequestSig).VerifyRequestSig.RequestRef
+		if results[i] == nil {
+			eventsOut.PushBack(events.RequestSigVerified(reqRef, true, ""))
+		} else {
+			eventsOut.PushBack(events.RequestSigVerified(reqRef, false, results[i].Error()))
+		}
+	}
+
+	return eventsOut
+}
+
+// updateClientKeys registers the given client public keys with the Crypto module,
+// deleting the keys of removed clients (represented by empty keys).
+// A key that cannot be registered (e.g., due to an unsupported format) leaves the client without a key,
+// such that none of its requests can be verified.
+func updateClientKeys(crypto modules.Crypto, clientKeys []*isspb.ClientKey) {
+	for _, clientKey := range clientKeys {
+		clientID := t.ClientID(clientKey.ClientId)
+		if len(clientKey.PubKey) == 0 {
+			crypto.DeleteClientKey(clientID)
+		} else if err := crypto.RegisterClientKey(clientKey.PubKey, clientID); err != nil {
+			crypto.DeleteClientKey(clientID)
+		}
+	}
+}
+
+// updateNodeKeys associates the given new public keys with the nodes in the Crypto module.
+// If the Crypto module supports key rotation (see modules.NodeKeyRotator), the nodes' previous keys are still accepted
+// until they are retired. Otherwise, the new keys immediately replace the previous ones.
+// A key that cannot be registered (e.g., due to an unsupported format) is ignored, leaving the previous key in place.
+func updateNodeKeys(crypto modules.Crypto, nodeKeys []*isspb.NodeKey) {
+	rotator, canRotate := crypto.(modules.NodeKeyRotator)
+	for _, nodeKey := range nodeKeys {
+		if canRotate {
+			_ = rotator.RotateNodeKey(nodeKey.PubKey, t.NodeID(nodeKey.NodeId))
+		} else {
+			_ = crypto.RegisterNodeKey(nodeKey.PubKey, t.NodeID(nodeKey.NodeId))
+		}
+	}
+}
+
+// verifyAll invokes verify(i) for each i from 0 to n-1, using up to numWorkers concurrent goroutines.
+// If numWorkers is less than 2, verify is invoked sequentially in the calling goroutine.
+// verifyAll only returns when all invocations of verify have returned.
+func verifyAll(numWorkers int, n int, verify func(i int)) {
+	if numWorkers < 2 || n < 2 {
+		for i := 0; i < n; i++ {
+			verify(i)
+		}
+		return
+	}
+	if numWorkers > n {
+		numWorkers = n
+	}
+
+	// Each worker processes indices taken from a common channel, until the channel is drained.
+	indices := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				verify(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// processSendEvents sends the messages contained in the send events using the sender,
+// which applies the Node's send policy.
+// Changes of the reachability of other nodes observed while sending are reported as PeerHealth events.
+// If coalesce is set, all messages to the same destination are sent together as a single MessageBundle
+// after all events have been processed. Otherwise, each message is sent immediately.
+// If prioritize is set, best-effort messages (e.g. forwarded requests) are only sent
+// after all critical messages (see NodeConfig.PrioritizeCriticalMessages).
+func processSendEvents(
+	selfID t.NodeID,
+	sender *sender,
+	tracer *causalTracer,
+	coalesce bool,
+	prioritize bool,
+	eventsIn *events.EventList,
+) (*events.EventList, error) {
+	eventsOut := &events.EventList{}
+
+	// If coalescing, the (critical, if prioritizing) messages to send to each destination.
+	pending := newOutbox()
+
+	// If prioritizing, the best-effort messages to send to each destination.
+	bulk := newOutbox()
 
 	iter := eventsIn.Iterator()
 	for event := iter.Next(); event != nil; event = iter.Next() {
@@ -496,11 +765,17 @@ func processSendEvents(selfID t.NodeID, net modules.Net, eventsIn *events.EventL
 
 		switch e := event.Type.(type) {
 		case *eventpb.Event_SendMessage:
+			msg := tracer.messageSent(event, e.SendMessage)
 			for _, destId := range e.SendMessage.Destinations {
-				if t.NodeID(destId) == selfID {
-					eventsOut.PushBack(events.MessageReceived(selfID, e.SendMessage.Msg))
-				} else {
-					net.Send(t.NodeID(destId), e.SendMessage.Msg)
+				dest := t.NodeID(destId)
+				if dest == selfID {
+					eventsOut.PushBack(tracer.messageReceived(selfID, msg))
+				} else if prioritize && messagePriority(msg) == sendBestEffort {
+					bulk.add(dest, msg)
+				} else if coalesce {
+					pending.add(dest, msg)
+				} else if peerHealth := sender.send(dest, msg); peerHealth != nil {
+					eventsOut.PushBack(peerHealth)
 				}
 			}
 		default:
@@ -508,10 +783,53 @@ func processSendEvents(selfID t.NodeID, net modules.Net, eventsIn *events.EventL
 		}
 	}
 
+	// Send the coalesced messages and, after them, the best-effort ones.
+	pending.flush(sender, true, eventsOut)
+	bulk.flush(sender, coalesce, eventsOut)
+
 	return eventsOut, nil
 }
 
-func processAppEvents(app modules.App, eventsIn *events.EventList) (*events.EventList, error) {
+// outbox holds messages to be sent later, grouped by destination.
+type outbox struct {
+	msgs  map[t.NodeID][]*messagepb.Message
+	dests []t.NodeID // The destinations in the order in which they were first added.
+}
+
+// newOutbox returns a new empty outbox.
+func newOutbox() *outbox {
+	return &outbox{msgs: make(map[t.NodeID][]*messagepb.Message)}
+}
+
+// add adds msg to the messages to send to node dest.
+func (o *outbox) add(dest t.NodeID, msg *messagepb.Message) {
+	if _, ok := o.msgs[dest]; !ok {
+		o.dests = append(o.dests, dest)
+	}
+	o.msgs[dest] = append(o.msgs[dest], msg)
+}
+
+// flush sends the messages in the outbox using the sender, adding the resulting PeerHealth events to eventsOut.
+// If bundle is set, the messages to the same destination are sent as a single MessageBundle.
+func (o *outbox) flush(sender *sender, bundle bool, eventsOut *events.EventList) {
+	for _, dest := range o.dests {
+		msgs := o.msgs[dest]
+		if bundle {
+			msgs = []*messagepb.Message{bundleMessages(msgs)}
+		}
+		for _, msg := range msgs {
+			if peerHealth := sender.send(dest, msg); peerHealth != nil {
+				eventsOut.PushBack(peerHealth)
+			}
+		}
+	}
+}
+
+func processAppEvents(
+	app modules.App,
+	reqStoreMetrics *reqstoremetrics.RequestStore,
+	eventsIn *events.EventList,
+) (*events.EventList, error) {
 	eventsOut := &events.EventList{}
 	iter := eventsIn.Iterator()
 	for event := iter.Next(); event != nil; event = iter.Next() {
@@ -521,19 +839,42 @@ func processAppEvents(app modules.App, eventsIn *events.EventList) (*events.Even
 
 		switch e := event.Type.(type) {
 		case *eventpb.Event_AnnounceDummyBatch:
-			if err := app.Apply(e.AnnounceDummyBatch.Batch); err != nil {
+			if poisoned, err := safeApplyBatch(app, t.SeqNr(e.AnnounceDummyBatch.Sn), e.AnnounceDummyBatch.Batch); err != nil {
 				return nil, fmt.Errorf("app error: %w", err)
+			} else if poisoned != nil {
+				// If the application panicked, do not process any further events.
+				// The poisoned batch event will make the Node halt.
+				return eventsOut.PushBack(poisoned), nil
 			}
+			reqStoreMetrics.MarkCommitted(e.AnnounceDummyBatch.Batch.Requests)
 		case *eventpb.Event_Deliver:
-			if err := app.Apply(e.Deliver.Batch); err != nil {
+			if poisoned, err := safeApplyBatch(app, t.SeqNr(e.Deliver.Sn), e.Deliver.Batch); err != nil {
 				return nil, fmt.Errorf("app batch delivery error: %w", err)
+			} else if poisoned != nil {
+				// If the application panicked, do not process any further events.
+				// The poisoned batch event will make the Node halt.
+				return eventsOut.PushBack(poisoned), nil
 			}
+			reqStoreMetrics.MarkCommitted(e.Deliver.Batch.Requests)
 		case *eventpb.Event_AppSnapshotRequest:
 			if data, err := app.Snapshot(); err != nil {
 				return nil, fmt.Errorf("app snapshot error: %w", err)
 			} else {
 				return (&events.EventList{}).PushBack(events.AppSnapshot(t.SeqNr(e.AppSnapshotRequest.Sn), data)), nil
 			}
+		case *eventpb.Event_AppRestoreState:
+			if err := app.RestoreState(e.AppRestoreState.Data); err != nil {
+				return nil, fmt.Errorf("app restore state error: %w", err)
+			}
+		case *eventpb.Event_AppQuery:
+			// A failing query only fails the corresponding read, not the whole Node.
+			queryable, ok := app.(modules.QueryableApp)
+			if !ok {
+				eventsOut.PushBack(events.AppQueryResult(e.AppQuery.ReadId, nil, ErrQueriesNotSupported))
+				break
+			}
+			result, err := queryable.Query(e.AppQuery.Query)
+			eventsOut.PushBack(events.AppQueryResult(e.AppQuery.ReadId, result, err))
 		default:
 			return nil, errors.Errorf("unexpected type of App event: %T", event.Type)
 		}
@@ -542,74 +883,130 @@ func processAppEvents(app modules.App, eventsIn *events.EventList) (*events.Even
 	return eventsOut, nil
 }
 
-func processReqStoreEvents(reqStore modules.RequestStore, eventsIn *events.EventList) (*events.EventList, error) {
+// processReqStoreEvents applies the RequestStore events to reqStore and syncs it.
+// If persisted is not nil, it is invoked after syncing with the time the sync started and the output event list.
+func processReqStoreEvents(
+	reqStore modules.RequestStore,
+	persisted func(operation string, start time.Time, eventsOut *events.EventList),
+	eventsIn *events.EventList,
+) (*events.EventList, error) {
 	eventsOut := &events.EventList{}
+
+	// Consecutive verified requests are accumulated and stored in the request store using a single StoreBatch call.
+	// The accumulated requests must be stored before processing any other event, to preserve the order of events.
+	batch := make([]*requestpb.StoredRequest, 0)
+	storeBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := reqStore.StoreBatch(batch); err != nil {
+			return fmt.Errorf("cannot store batch of %d requests (first c%dr%d): %w",
+				len(batch),
+				batch[0].RequestRef.ClientId,
+				batch[0].RequestRef.ReqNo,
+				err)
+		}
+		batch = make([]*requestpb.StoredRequest, 0)
+		return nil
+	}
+
 	iter := eventsIn.Iterator()
 	for event := iter.Next(); event != nil; event = iter.Next() {
 
 		// Remove the follow-up events from event and add them directly to the output.
 		eventsOut.PushBackList(events.Strip(event))
 
+		// Store accumulated requests, unless this event is another one to be added to the batch.
+		switch event.Type.(type) {
+		case *eventpb.Event_StoreVerifiedRequest, *eventpb.Event_StoreDummyRequest:
+		default:
+			if err := storeBatch(); err != nil {
+				return nil, err
+			}
+		}
+
 		// Process event based on its type.
 		switch e := event.Type.(type) {
 		case *eventpb.Event_StoreVerifiedRequest:
-			storeEvent := e.StoreVerifiedRequest
-
-			// Store request data.
-			if err := reqStore.PutRequest(storeEvent.RequestRef, storeEvent.Data); err != nil {
-				return nil, fmt.Errorf("cannot store request (c%dr%d) data: %w",
-					storeEvent.RequestRef.ClientId,
-					storeEvent.RequestRef.ReqNo,
-					err)
-			}
-
-			// Mark request as authenticated.
-			if err := reqStore.SetAuthenticated(storeEvent.RequestRef); err != nil {
-				return nil, fmt.Errorf("cannot mark request (c%dr%d) as authenticated: %w",
-					storeEvent.RequestRef.ClientId,
-					storeEvent.RequestRef.ReqNo,
+			// Add request data, authentication status, and authenticator to the batch to be stored.
+			batch = append(batch, &requestpb.StoredRequest{
+				RequestRef:    e.StoreVerifiedRequest.RequestRef,
+				Data:          e.StoreVerifiedRequest.Data,
+				Authenticated: true,
+				Authenticator: e.StoreVerifiedRequest.Authenticator,
+			})
+
+		case *eventpb.Event_PruneRequests:
+			// Delete data of requests that are not needed any more.
+			if err := reqStore.Prune(t.ClientID(e.PruneRequests.ClientId), t.ReqNo(e.PruneRequests.BelowReqNo)); err != nil {
+				return nil, fmt.Errorf("cannot prune requests of client %d below request number %d: %w",
+					e.PruneRequests.ClientId,
+					e.PruneRequests.BelowReqNo,
 					err)
 			}
 
-			// Store request authenticator.
-			if err := reqStore.PutAuthenticator(storeEvent.RequestRef, storeEvent.Authenticator); err != nil {
-				return nil, fmt.Errorf("cannot store authenticator (c%dr%d) of request: %w",
-					storeEvent.RequestRef.ClientId,
-					storeEvent.RequestRef.ReqNo,
-					err)
+		case *eventpb.Event_ForwardRequests:
+			// Send the stored requests (including their authenticators) to the requesting nodes.
+			// Requests the request store does not contain (e.g. because they already have been pruned) are skipped.
+			destinations := t.NodeIDSlice(e.ForwardRequests.Destinations)
+			for _, reqRef := range e.ForwardRequests.RequestRefs {
+				data, err := reqStore.GetRequest(reqRef)
+				if err != nil {
+					continue
+				}
+				authenticator, err := reqStore.GetAuthenticator(reqRef)
+				if err != nil {
+					continue
+				}
+				eventsOut.PushBack(events.SendMessage(&messagepb.Message{
+					Type: &messagepb.Message_ForwardedRequest{ForwardedRequest: &requestpb.Request{
+						ClientId:      reqRef.ClientId,
+						ReqNo:         reqRef.ReqNo,
+						Data:          data,
+						Authenticator: authenticator,
+					}},
+				}, destinations))
 			}
 
 		case *eventpb.Event_StoreDummyRequest:
 			storeEvent := e.StoreDummyRequest // Helper variable for convenience
 
-			// Store request data.
-			if err := reqStore.PutRequest(storeEvent.RequestRef, storeEvent.Data); err != nil {
-				return nil, fmt.Errorf("cannot store dummy request data: %w", err)
-			}
-
-			// Mark request as authenticated.
-			if err := reqStore.SetAuthenticated(storeEvent.RequestRef); err != nil {
-				return nil, fmt.Errorf("cannot mark dummy request as authenticated: %w", err)
-			}
-
-			// Associate a dummy authenticator with the request
-			if err := reqStore.PutAuthenticator(storeEvent.RequestRef, []byte{0}); err != nil {
-				return nil, fmt.Errorf("cannot store authenticator of dummy request: %w", err)
-			}
-
-			eventsOut.PushBack(events.RequestReady(storeEvent.RequestRef))
+			// Add the request to the batch to be stored, marking it as authenticated
+			// and associating a dummy authenticator with it.
+			// The RequestReady event is only output after the batch has been stored (see the end of this function).
+			batch = append(batch, &requestpb.StoredRequest{
+				RequestRef:    storeEvent.RequestRef,
+				Data:          storeEvent.Data,
+				Authenticated: true,
+				Authenticator: []byte{0},
+			})
+
+			eventsOut.PushBack(events.RequestReady(storeEvent.RequestRef, storeEvent.Data))
 		}
 	}
 
+	// Store the requests accumulated since the last non-storing event.
+	if err := storeBatch(); err != nil {
+		return nil, err
+	}
+
 	// Then sync the request store, ensuring that all updates to its state are persisted.
+	start := time.Now()
 	if err := reqStore.Sync(); err != nil {
 		return nil, errors.WithMessage(err, "could not sync request store, unsafe to continue")
 	}
+	if persisted != nil {
+		persisted(PersistReqStoreSync, start, eventsOut)
+	}
 
 	return eventsOut, nil
 }
 
-func processProtocolEvents(sm modules.Protocol, eventsIn *events.EventList) (*events.EventList, error) {
+func processProtocolEvents(
+	sm modules.Protocol,
+	tracer *causalTracer,
+	eventsIn *events.EventList,
+) (*events.EventList, error) {
 	eventsOut := &events.EventList{}
 	iter := eventsIn.Iterator()
 	for event := iter.Next(); event != nil; event = iter.Next() {
@@ -621,6 +1018,7 @@ func processProtocolEvents(sm modules.Protocol, eventsIn *events.EventList) (*ev
 		if err != nil {
 			return nil, errors.WithMessage(err, "error applying protocol event")
 		}
+		tracer.eventsProduced(event, newEvents)
 		eventsOut.PushBackList(newEvents)
 	}
 
@@ -12,6 +12,10 @@ import (
 	"fmt"
 	"github.com/hyperledger-labs/mirbft/pkg/events"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/eventpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/messagepb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/requestpb"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/statuspb"
+	t "github.com/hyperledger-labs/mirbft/pkg/types"
 )
 
 // WorkItems is a buffer for storing outstanding events that need to be processed by the node.
@@ -25,6 +29,12 @@ type workItems struct {
 	reqStore *events.EventList
 	protocol *events.EventList
 	crypto   *events.EventList
+
+	// Notifications are not processed by any module, but published by the Node to the subscribers of its events.
+	notifications *events.EventList
+
+	// Events serving linearizable reads (see Node.Read) are not processed by any module, but by the Node itself.
+	reads *events.EventList
 }
 
 // NewWorkItems allocates and returns a pointer to a new WorkItems object.
@@ -38,6 +48,9 @@ func newWorkItems() *workItems {
 		reqStore: &events.EventList{},
 		protocol: &events.EventList{},
 		crypto:   &events.EventList{},
+
+		notifications: &events.EventList{},
+		reads:         &events.EventList{},
 	}
 }
 
@@ -56,40 +69,57 @@ func (wi *workItems) AddEvents(events *events.EventList) error {
 			// TODO: Should the Tick event also go elsewhere? Clients?
 		case *eventpb.Event_SendMessage:
 			wi.net.PushBack(event)
-		case *eventpb.Event_MessageReceived, *eventpb.Event_Iss, *eventpb.Event_RequestReady,
-			*eventpb.Event_AppSnapshot:
+		case *eventpb.Event_MessageReceived:
+			// Requests forwarded by other nodes are treated the same way as requests received from clients
+			// (they are authenticated by the client tracker). Messages serving linearizable reads
+			// are handled by the Node itself. All other messages are destined to the protocol.
+			switch msg := t.MessageReceived.Msg.Type.(type) {
+			case *messagepb.Message_ForwardedRequest:
+				wi.client.PushBack(forwardedRequestEvent(t.MessageReceived, msg.ForwardedRequest))
+			case *messagepb.Message_ReadIndexRequest, *messagepb.Message_ReadIndexResponse:
+				wi.reads.PushBack(event)
+			default:
+				wi.protocol.PushBack(event)
+			}
+		case *eventpb.Event_Iss, *eventpb.Event_RequestReady, *eventpb.Event_AppSnapshot, *eventpb.Event_PeerHealth,
+			*eventpb.Event_StepDown, *eventpb.Event_LocalParams:
 			wi.protocol.PushBack(event)
-		case *eventpb.Event_Request, *eventpb.Event_RequestSigVerified:
+		case *eventpb.Event_Request, *eventpb.Event_ForwardedRequest, *eventpb.Event_RequestSigVerified:
 			wi.client.PushBack(event)
-		case *eventpb.Event_StoreVerifiedRequest:
+		case *eventpb.Event_StoreVerifiedRequest, *eventpb.Event_PruneRequests, *eventpb.Event_ForwardRequests:
 			wi.reqStore.PushBack(event)
-		case *eventpb.Event_VerifyRequestSig:
+		case *eventpb.Event_VerifyRequestSig, *eventpb.Event_UpdateClientKeys,
+			*eventpb.Event_UpdateNodeKeys, *eventpb.Event_RetireNodeKeys:
 			wi.crypto.PushBack(event)
 		case *eventpb.Event_HashRequest:
 			wi.hash.PushBack(event)
 		case *eventpb.Event_HashResult:
 			// For hash results, their origin determines the destination.
 			switch t.HashResult.Origin.Type.(type) {
-			case *eventpb.HashOrigin_Request:
-				// If the origin is a request received directly from a client,
+			case *eventpb.HashOrigin_Request, *eventpb.HashOrigin_ForwardedRequest:
+				// If the origin is a request received directly from a client or forwarded by another node,
 				// it is the client tracker that created the request and the result goes back to it.
 				wi.client.PushBack(event)
 			}
-		case *eventpb.Event_WalAppend:
+		case *eventpb.Event_WalAppend, *eventpb.Event_WalTruncate:
 			wi.wal.PushBack(event)
-		case *eventpb.Event_Deliver, *eventpb.Event_AppSnapshotRequest:
+		case *eventpb.Event_Deliver, *eventpb.Event_AppSnapshotRequest, *eventpb.Event_AppRestoreState,
+			*eventpb.Event_AppQuery:
 			wi.app.PushBack(event)
+		case *eventpb.Event_AppQueryResult:
+			wi.reads.PushBack(event)
 		case *eventpb.Event_WalEntry:
 			switch walEntry := t.WalEntry.Event.Type.(type) {
-			case *eventpb.Event_Iss:
-				// TODO: WAL loading is now disabled for ISS by commenting out the next line.
-				//       Implement recovery and re-enable (un-comment).
-				// wi.protocol.PushBack(t.WalEntry.Event)
-			case *eventpb.Event_PersistDummyBatch:
+			case *eventpb.Event_Iss, *eventpb.Event_PersistDummyBatch:
 				wi.protocol.PushBack(t.WalEntry.Event)
 			default:
 				return fmt.Errorf("unsupported WAL entry event type %T", walEntry)
 			}
+		case *eventpb.Event_Notification:
+			wi.notifications.PushBack(event)
+		case *eventpb.Event_PoisonedBatch:
+			// A poisoned batch is not routed to any module. It causes the whole Node to halt.
+			return newPoisonedBatchError(t.PoisonedBatch)
 
 		// TODO: Remove these eventually.
 		case *eventpb.Event_PersistDummyBatch:
@@ -105,6 +135,11 @@ func (wi *workItems) AddEvents(events *events.EventList) error {
 	return nil
 }
 
+// forwardedRequestEvent returns a forwarded request event for a request received from another node.
+func forwardedRequestEvent(msgReceived *eventpb.MessageReceived, req *requestpb.Request) *eventpb.Event {
+	return events.ForwardedRequest(t.NodeID(msgReceived.From), req)
+}
+
 // Getters.
 
 func (wi *workItems) WAL() *events.EventList {
@@ -139,6 +174,36 @@ func (wi *workItems) Crypto() *events.EventList {
 	return wi.crypto
 }
 
+func (wi *workItems) Notifications() *events.EventList {
+	return wi.notifications
+}
+
+func (wi *workItems) Reads() *events.EventList {
+	return wi.reads
+}
+
+// Len returns the total number of events pending in all the buffers.
+// Notifications and read events are not counted, as they are never pending for long (they are handled by the
+// process() goroutine right after being added).
+func (wi *workItems) Len() int {
+	return wi.wal.Len() + wi.net.Len() + wi.hash.Len() + wi.client.Len() +
+		wi.app.Len() + wi.reqStore.Len() + wi.protocol.Len() + wi.crypto.Len()
+}
+
+// Status returns the numbers of events pending in the individual buffers.
+func (wi *workItems) Status() *statuspb.WorkItemsStatus {
+	return &statuspb.WorkItemsStatus{
+		Wal:      uint64(wi.wal.Len()),
+		Net:      uint64(wi.net.Len()),
+		Hash:     uint64(wi.hash.Len()),
+		Client:   uint64(wi.client.Len()),
+		App:      uint64(wi.app.Len()),
+		ReqStore: uint64(wi.reqStore.Len()),
+		Protocol: uint64(wi.protocol.Len()),
+		Crypto:   uint64(wi.crypto.Len()),
+	}
+}
+
 // Methods for clearing the buffers.
 // Each of them returns the list of events that have been removed from workItems.
 
@@ -174,6 +239,14 @@ func (wi *workItems) ClearCrypto() *events.EventList {
 	return clearEventList(&wi.crypto)
 }
 
+func (wi *workItems) ClearNotifications() *events.EventList {
+	return clearEventList(&wi.notifications)
+}
+
+func (wi *workItems) ClearReads() *events.EventList {
+	return clearEventList(&wi.reads)
+}
+
 func clearEventList(listPtr **events.EventList) *events.EventList {
 	oldList := *listPtr
 	*listPtr = &events.EventList{}